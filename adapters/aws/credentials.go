@@ -0,0 +1,290 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Endpoints of the credential sources of AWS workloads.
+const (
+	// containerCredentialsHost serves the credentials of ECS tasks under a relative URI.
+	containerCredentialsHost = "http://169.254.170.2"
+	// instanceMetadataURL is the instance metadata service of EC2 instances.
+	instanceMetadataURL = "http://169.254.169.254/latest"
+)
+
+// Credentials are the access key the requests to AWS are signed with.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is set for temporary credentials, e.g. of an assumed role.
+	SessionToken string
+	// Expires is when temporary credentials expire, zero for long-lived access keys.
+	Expires time.Time
+}
+
+// CredentialsProvider provides the credentials requests are signed with.
+type CredentialsProvider interface {
+	Retrieve(ctx context.Context) (Credentials, error)
+}
+
+// StaticCredentials provides a fixed access key.
+type StaticCredentials Credentials
+
+// Retrieve returns the access key.
+func (sc StaticCredentials) Retrieve(context.Context) (Credentials, error) {
+	return Credentials(sc), nil
+}
+
+// RoleCredentials provides the temporary credentials of the IAM role of the workload. They are
+// cached until five minutes before they expire, so none expires while a request is on its way.
+type RoleCredentials struct {
+	source  string
+	fetch   func(ctx context.Context) (Credentials, error)
+	mu      sync.Mutex
+	current Credentials
+}
+
+// NewCredentialsProvider returns the static access key if one is given, and otherwise the
+// credentials of the IAM role of the workload, looked up like the AWS SDKs do:
+//  1. the web identity token of EKS service accounts (AWS_ROLE_ARN and AWS_WEB_IDENTITY_TOKEN_FILE),
+//     exchanged for role credentials at STS,
+//  2. the container credentials endpoint of ECS tasks and EKS Pod Identity
+//     (AWS_CONTAINER_CREDENTIALS_RELATIVE_URI or AWS_CONTAINER_CREDENTIALS_FULL_URI),
+//  3. the instance metadata service of EC2 instances, with IMDSv2 session tokens.
+//
+// Parameters:
+//   - client: The HTTP client for the requests to the credential sources
+//   - region: The AWS region whose STS endpoint web identity tokens are exchanged at
+//   - static: An access key, empty to use the role of the workload
+//
+// Returns:
+//   - CredentialsProvider: The provider of the credentials
+func NewCredentialsProvider(client *http.Client, region string, static Credentials) CredentialsProvider {
+	if static.AccessKeyID != "" && static.SecretAccessKey != "" {
+		return StaticCredentials(static)
+	}
+	if roleARN, tokenFile := os.Getenv("AWS_ROLE_ARN"), os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE"); roleARN != "" && tokenFile != "" {
+		sessionName := os.Getenv("AWS_ROLE_SESSION_NAME")
+		if sessionName == "" {
+			sessionName = "hexagonal-auth-service"
+		}
+		return &RoleCredentials{source: "web identity", fetch: func(ctx context.Context) (Credentials, error) {
+			return assumeRoleWithWebIdentity(ctx, client, region, roleARN, tokenFile, sessionName)
+		}}
+	}
+	if uri := containerCredentialsURI(); uri != "" {
+		return &RoleCredentials{source: "container", fetch: func(ctx context.Context) (Credentials, error) {
+			return containerCredentials(ctx, client, uri)
+		}}
+	}
+	return &RoleCredentials{source: "instance metadata", fetch: func(ctx context.Context) (Credentials, error) {
+		return instanceCredentials(ctx, client)
+	}}
+}
+
+// Retrieve returns the cached credentials, fetching new ones if they expire within five minutes.
+func (rc *RoleCredentials) Retrieve(ctx context.Context) (Credentials, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.current.AccessKeyID != "" && time.Until(rc.current.Expires) > 5*time.Minute {
+		return rc.current, nil
+	}
+	credentials, err := rc.fetch(ctx)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to get role credentials from %s: %w", rc.source, err)
+	}
+	rc.current = credentials
+	return credentials, nil
+}
+
+// roleCredentialsResponse is the JSON document of the container and instance credential endpoints.
+type roleCredentialsResponse struct {
+	Code            string    `json:"Code"`
+	Message         string    `json:"Message"`
+	AccessKeyID     string    `json:"AccessKeyId"`
+	SecretAccessKey string    `json:"SecretAccessKey"`
+	Token           string    `json:"Token"`
+	Expiration      time.Time `json:"Expiration"`
+}
+
+// credentials converts the response, rejecting failures reported in the body.
+func (r roleCredentialsResponse) credentials() (Credentials, error) {
+	if r.Code != "" && r.Code != "Success" {
+		return Credentials{}, fmt.Errorf("%s: %s", r.Code, r.Message)
+	}
+	if r.AccessKeyID == "" || r.SecretAccessKey == "" {
+		return Credentials{}, errors.New("response holds no access key")
+	}
+	return Credentials{r.AccessKeyID, r.SecretAccessKey, r.Token, r.Expiration}, nil
+}
+
+// containerCredentialsURI returns the credentials endpoint of the container, empty outside of ECS
+// and EKS Pod Identity.
+func containerCredentialsURI() string {
+	if relative := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); relative != "" {
+		return containerCredentialsHost + relative
+	}
+	return os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI")
+}
+
+// containerCredentials fetches the credentials of the task or pod, authorized with the token the
+// agent provides, if any.
+func containerCredentials(ctx context.Context, client *http.Client, uri string) (Credentials, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return Credentials{}, err
+	}
+	token := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN")
+	if file := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE"); file != "" {
+		// the token file is rotated by the agent, read it for every request
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return Credentials{}, fmt.Errorf("failed to read the authorization token: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+
+	var response roleCredentialsResponse
+	if err := getJSON(client, req, &response); err != nil {
+		return Credentials{}, err
+	}
+	return response.credentials()
+}
+
+// instanceCredentials fetches the credentials of the role of the EC2 instance profile.
+func instanceCredentials(ctx context.Context, client *http.Client) (Credentials, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, instanceMetadataURL+"/api/token", nil)
+	if err != nil {
+		return Credentials{}, err
+	}
+	req.Header.Set("X-Aws-Ec2-Metadata-Token-Ttl-Seconds", "300")
+	res, err := client.Do(req)
+	if err != nil {
+		return Credentials{}, err
+	}
+	defer res.Body.Close()
+	token, err := io.ReadAll(io.LimitReader(res.Body, 4096))
+	if err != nil || res.StatusCode != http.StatusOK {
+		return Credentials{}, fmt.Errorf("failed to get a metadata session token: status %d", res.StatusCode)
+	}
+
+	get := func(path string) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, instanceMetadataURL+"/meta-data/iam/security-credentials/"+path, nil)
+		if err == nil {
+			req.Header.Set("X-Aws-Ec2-Metadata-Token", string(token))
+		}
+		return req, err
+	}
+	req, err = get("")
+	if err != nil {
+		return Credentials{}, err
+	}
+	res, err = client.Do(req)
+	if err != nil {
+		return Credentials{}, err
+	}
+	defer res.Body.Close()
+	roles, err := io.ReadAll(io.LimitReader(res.Body, 4096))
+	if err != nil || res.StatusCode != http.StatusOK {
+		return Credentials{}, fmt.Errorf("the instance has no instance profile: status %d", res.StatusCode)
+	}
+	role, _, _ := strings.Cut(strings.TrimSpace(string(roles)), "\n")
+
+	req, err = get(url.PathEscape(role))
+	if err != nil {
+		return Credentials{}, err
+	}
+	var response roleCredentialsResponse
+	if err := getJSON(client, req, &response); err != nil {
+		return Credentials{}, err
+	}
+	return response.credentials()
+}
+
+// assumeRoleWithWebIdentity exchanges the web identity token of the service account for the
+// credentials of the role at STS. The token file is rotated by the kubelet, so it is read for
+// every exchange.
+func assumeRoleWithWebIdentity(ctx context.Context, client *http.Client, region string, roleARN string, tokenFile string, sessionName string) (Credentials, error) {
+	token, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to read the web identity token: %w", err)
+	}
+	endpoint := "https://sts.amazonaws.com/"
+	if region != "" {
+		endpoint = "https://sts." + region + ".amazonaws.com/"
+	}
+	form := url.Values{
+		"Action":           {"AssumeRoleWithWebIdentity"},
+		"Version":          {"2011-06-15"},
+		"RoleArn":          {roleARN},
+		"RoleSessionName":  {sessionName},
+		"WebIdentityToken": {strings.TrimSpace(string(token))},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Credentials{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return Credentials{}, err
+	}
+	defer res.Body.Close()
+	var response struct {
+		Credentials struct {
+			AccessKeyID     string    `xml:"AccessKeyId"`
+			SecretAccessKey string    `xml:"SecretAccessKey"`
+			SessionToken    string    `xml:"SessionToken"`
+			Expiration      time.Time `xml:"Expiration"`
+		} `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+		Error QueryError `xml:"Error"`
+	}
+	if err := xml.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&response); err != nil {
+		return Credentials{}, fmt.Errorf("unexpected response with status %d from STS", res.StatusCode)
+	}
+	if res.StatusCode != http.StatusOK {
+		return Credentials{}, response.Error
+	}
+	c := response.Credentials
+	return Credentials{c.AccessKeyID, c.SecretAccessKey, c.SessionToken, c.Expiration}, nil
+}
+
+// getJSON sends a request and decodes the JSON document of a successful response.
+func getJSON(client *http.Client, req *http.Request, target any) error {
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d from %s", res.StatusCode, req.URL.Host)
+	}
+	return json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(target)
+}
+
+// QueryError is the error of an AWS service speaking the query protocol, e.g. STS or SNS.
+type QueryError struct {
+	Type    string `xml:"Type"`
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// Error returns the code and the message.
+func (qe QueryError) Error() string {
+	return qe.Code + ": " + qe.Message
+}
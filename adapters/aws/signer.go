@@ -0,0 +1,106 @@
+// Package aws signs requests to AWS services with Signature Version 4 and obtains the credentials
+// to sign them with, from static access keys or from the IAM role of the workload. It also verifies
+// the messages SNS posts to HTTP subscriptions.
+package aws
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Signer adds the Signature Version 4 headers to the requests for an AWS service.
+type Signer struct {
+	service     string
+	region      string
+	credentials CredentialsProvider
+	now         func() time.Time
+}
+
+// NewSigner creates a new Signer.
+//
+// Parameters:
+//   - service: The signing name of the service, e.g. "sns"
+//   - region: The AWS region of the service, e.g. "eu-central-1"
+//   - credentials: The provider of the credentials the requests are signed with
+//
+// Returns:
+//   - *Signer: A pointer to the newly created Signer
+func NewSigner(service string, region string, credentials CredentialsProvider) *Signer {
+	return &Signer{service, region, credentials, time.Now}
+}
+
+// Sign adds the date, the session token of temporary credentials and the authorization header.
+// All headers set so far are signed, so the request must not be changed afterwards.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the retrieval of the credentials
+//   - req: The request to sign
+//   - body: The body of the request
+//
+// Returns:
+//   - error: An error if no credentials are available
+func (s *Signer) Sign(ctx context.Context, req *http.Request, body []byte) error {
+	credentials, err := s.credentials.Retrieve(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", credentials.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{req.Method, path, req.URL.RawQuery, canonicalHeaders.String(), signedHeaders, sha256Hex(body)}, "\n")
+	scope := date + "/" + s.region + "/" + s.service + "/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+credentials.SecretAccessKey), date)
+	for _, part := range []string{s.region, s.service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", credentials.AccessKeyID, scope, signedHeaders, signature))
+	return nil
+}
+
+// sha256Hex returns the hex encoded SHA-256 digest of data.
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data under key.
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,210 @@
+package aws
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxSigningCerts bounds the number of SNS signing certificates kept in memory.
+const maxSigningCerts = 16
+
+// snsHostPattern matches the hosts of the SNS endpoints, the only hosts signing certificates and
+// subscription confirmations are fetched from.
+var snsHostPattern = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// Types of SNS messages.
+const (
+	SNSTypeNotification             = "Notification"
+	SNSTypeSubscriptionConfirmation = "SubscriptionConfirmation"
+	SNSTypeUnsubscribeConfirmation  = "UnsubscribeConfirmation"
+)
+
+// SNSMessage is a message SNS posts to an HTTP(S) subscription.
+type SNSMessage struct {
+	Type             string `json:"Type"`
+	MessageID        string `json:"MessageId"`
+	Token            string `json:"Token"`
+	TopicARN         string `json:"TopicArn"`
+	Subject          string `json:"Subject"`
+	Message          string `json:"Message"`
+	Timestamp        string `json:"Timestamp"`
+	SignatureVersion string `json:"SignatureVersion"`
+	Signature        string `json:"Signature"`
+	SigningCertURL   string `json:"SigningCertURL"`
+	SubscribeURL     string `json:"SubscribeURL"`
+}
+
+// SNSMessageVerifier checks the signatures of the messages SNS posts to HTTP(S) subscriptions, so
+// nobody but SNS can post to them. The signing certificates are fetched from SNS and cached.
+type SNSMessageVerifier struct {
+	client *http.Client
+
+	mu    sync.Mutex
+	certs map[string]*x509.Certificate
+}
+
+// NewSNSMessageVerifier creates a new SNSMessageVerifier.
+//
+// Parameters:
+//   - client: The HTTP client fetching the signing certificates and confirming subscriptions
+//
+// Returns:
+//   - *SNSMessageVerifier: A pointer to the newly created SNSMessageVerifier
+func NewSNSMessageVerifier(client *http.Client) *SNSMessageVerifier {
+	return &SNSMessageVerifier{client: client, certs: map[string]*x509.Certificate{}}
+}
+
+// Verify checks the signature of a message with the certificate it names, which has to be served by SNS.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the retrieval of the certificate
+//   - message: The message to verify
+//
+// Returns:
+//   - error: An error if the message is not signed by SNS
+func (sv *SNSMessageVerifier) Verify(ctx context.Context, message SNSMessage) error {
+	var hash crypto.Hash
+	var digest []byte
+	toSign := []byte(message.stringToSign())
+	switch message.SignatureVersion {
+	case "1":
+		sum := sha1.Sum(toSign)
+		hash, digest = crypto.SHA1, sum[:]
+	case "2":
+		sum := sha256.Sum256(toSign)
+		hash, digest = crypto.SHA256, sum[:]
+	default:
+		return fmt.Errorf("unsupported signature version %q", message.SignatureVersion)
+	}
+	signature, err := base64.StdEncoding.DecodeString(message.Signature)
+	if err != nil {
+		return errors.New("signature is not base64 encoded")
+	}
+
+	cert, err := sv.certificate(ctx, message.SigningCertURL)
+	if err != nil {
+		return err
+	}
+	publicKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return errors.New("signing certificate has no RSA key")
+	}
+	if err := rsa.VerifyPKCS1v15(publicKey, hash, digest, signature); err != nil {
+		return errors.New("signature does not match")
+	}
+	return nil
+}
+
+// ConfirmSubscription confirms the subscription a verified SubscriptionConfirmation message asks for.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the request
+//   - message: The SubscriptionConfirmation message
+//
+// Returns:
+//   - error: An error if the confirmation URL is not served by SNS or the confirmation fails
+func (sv *SNSMessageVerifier) ConfirmSubscription(ctx context.Context, message SNSMessage) error {
+	if err := checkSNSURL(message.SubscribeURL); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, message.SubscribeURL, nil)
+	if err != nil {
+		return err
+	}
+	res, err := sv.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to confirm the SNS subscription: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("SNS rejected the subscription confirmation with status %d", res.StatusCode)
+	}
+	return nil
+}
+
+// certificate returns the signing certificate at a URL of SNS.
+func (sv *SNSMessageVerifier) certificate(ctx context.Context, certURL string) (*x509.Certificate, error) {
+	if err := checkSNSURL(certURL); err != nil {
+		return nil, err
+	}
+	sv.mu.Lock()
+	cert, ok := sv.certs[certURL]
+	sv.mu.Unlock()
+	if ok && time.Now().Before(cert.NotAfter) {
+		return cert, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, certURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := sv.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the SNS signing certificate: %w", err)
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(res.Body, 64<<10))
+	if err != nil || res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch the SNS signing certificate, status %d", res.StatusCode)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("SNS signing certificate is not PEM encoded")
+	}
+	cert, err = x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("malformed SNS signing certificate: %w", err)
+	}
+
+	sv.mu.Lock()
+	if len(sv.certs) >= maxSigningCerts {
+		clear(sv.certs)
+	}
+	sv.certs[certURL] = cert
+	sv.mu.Unlock()
+	return cert, nil
+}
+
+// stringToSign returns the fields of the message covered by the signature in the format SNS signs.
+func (m SNSMessage) stringToSign() string {
+	fields := [][2]string{{"Message", m.Message}, {"MessageId", m.MessageID}}
+	if m.Type == SNSTypeNotification {
+		if m.Subject != "" {
+			fields = append(fields, [2]string{"Subject", m.Subject})
+		}
+		fields = append(fields, [2]string{"Timestamp", m.Timestamp})
+	} else {
+		fields = append(fields, [2]string{"SubscribeURL", m.SubscribeURL}, [2]string{"Timestamp", m.Timestamp}, [2]string{"Token", m.Token})
+	}
+	fields = append(fields, [2]string{"TopicArn", m.TopicARN}, [2]string{"Type", m.Type})
+
+	var b strings.Builder
+	for _, field := range fields {
+		b.WriteString(field[0] + "\n" + field[1] + "\n")
+	}
+	return b.String()
+}
+
+// checkSNSURL checks that a URL is an HTTPS URL of SNS.
+func checkSNSURL(value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Scheme != "https" || !snsHostPattern.MatchString(parsed.Hostname()) || parsed.Port() != "" {
+		return fmt.Errorf("%q is not a URL of SNS", value)
+	}
+	return nil
+}
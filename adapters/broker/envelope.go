@@ -0,0 +1,84 @@
+// Package broker publishes the domain events recorded in the outbox to message brokers, so
+// downstream systems can react to registrations and security events.
+package broker
+
+import (
+	"encoding/json"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// Envelope is the serialized form of a published event, the same on every broker. It extends the
+// payload of webhook deliveries by the category, the schema version, the subject and the request.
+type Envelope struct {
+	// ID is the id of the outbox message, the same for every attempt, for dropping duplicates.
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	// Category is the category of the event, e.g. "account".
+	Category string `json:"category"`
+	// SchemaVersion is the version of the fields in Data.
+	SchemaVersion int `json:"schemaVersion"`
+	// Subject is the id of the user the event is about, or the username if the user is unknown.
+	Subject    string          `json:"subject"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	RequestID  string          `json:"requestId,omitempty"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// NewEnvelope wraps an outbox message.
+//
+// Parameters:
+//   - message: The message to publish
+//
+// Returns:
+//   - Envelope: The envelope of the message
+func NewEnvelope(message domain.OutboxMessage) Envelope {
+	return Envelope{
+		ID:            message.ID,
+		Type:          message.EventName,
+		Category:      message.Category,
+		SchemaVersion: message.SchemaVersion,
+		Subject:       message.Key,
+		OccurredAt:    message.OccurredAt.UTC(),
+		RequestID:     message.RequestID,
+		Data:          message.Payload,
+	}
+}
+
+// EnvelopeAvroSchema is the Avro schema of the envelope. Data holds the JSON encoded fields of the
+// event, as the fields differ from event to event.
+const EnvelopeAvroSchema = `{"type":"record","name":"AuthEvent","namespace":"auth.events","fields":[` +
+	`{"name":"id","type":"string"},` +
+	`{"name":"type","type":"string"},` +
+	`{"name":"category","type":"string"},` +
+	`{"name":"schemaVersion","type":"int"},` +
+	`{"name":"subject","type":"string"},` +
+	`{"name":"occurredAt","type":{"type":"long","logicalType":"timestamp-millis"}},` +
+	`{"name":"requestId","type":"string","default":""},` +
+	`{"name":"data","type":"string"}]}`
+
+// avroEnvelope is the Avro JSON encoding of an envelope following EnvelopeAvroSchema.
+type avroEnvelope struct {
+	ID            string `json:"id"`
+	Type          string `json:"type"`
+	Category      string `json:"category"`
+	SchemaVersion int    `json:"schemaVersion"`
+	Subject       string `json:"subject"`
+	OccurredAt    int64  `json:"occurredAt"`
+	RequestID     string `json:"requestId"`
+	Data          string `json:"data"`
+}
+
+// avro converts the envelope into its Avro JSON encoding.
+func (e Envelope) avro() avroEnvelope {
+	return avroEnvelope{
+		ID:            e.ID,
+		Type:          e.Type,
+		Category:      e.Category,
+		SchemaVersion: e.SchemaVersion,
+		Subject:       e.Subject,
+		OccurredAt:    e.OccurredAt.UnixMilli(),
+		RequestID:     e.RequestID,
+		Data:          string(e.Data),
+	}
+}
@@ -0,0 +1,226 @@
+package broker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// Payload encodings of the Kafka publisher.
+const (
+	// EncodingJSON publishes the envelope as JSON.
+	EncodingJSON = "json"
+	// EncodingAvro publishes the envelope as Avro. The REST Proxy registers EnvelopeAvroSchema in
+	// the schema registry and prefixes the records with the schema id.
+	EncodingAvro = "avro"
+)
+
+// KafkaConfig configures the Kafka publisher.
+type KafkaConfig struct {
+	// RESTProxyURL is the base URL of a Kafka REST Proxy speaking the v2 API, e.g. the Confluent
+	// REST Proxy or the Redpanda HTTP Proxy, e.g. "https://kafka-rest.example.com:8082".
+	RESTProxyURL string
+	// TopicPrefix is prepended to the event category to form the topic, e.g. "auth." publishes
+	// logins to "auth.authentication".
+	TopicPrefix string
+	// Encoding is EncodingJSON or EncodingAvro.
+	Encoding string
+	// Username and Password authenticate at the REST Proxy with HTTP basic authentication, if set.
+	Username string
+	Password string
+	// Timeout bounds publishing the records of a topic.
+	Timeout time.Duration
+}
+
+// produceRecord is a record of a v2 produce request.
+type produceRecord struct {
+	Key   string `json:"key"`
+	Value any    `json:"value"`
+}
+
+// produceRequest is the body of a v2 produce request.
+type produceRequest struct {
+	KeySchema   string          `json:"key_schema,omitempty"`
+	ValueSchema string          `json:"value_schema,omitempty"`
+	Records     []produceRecord `json:"records"`
+}
+
+// produceResponse is the body of a v2 produce response. Every record has an offset or an error.
+type produceResponse struct {
+	Offsets []struct {
+		Partition int     `json:"partition"`
+		Offset    int64   `json:"offset"`
+		ErrorCode *int    `json:"error_code"`
+		Error     *string `json:"error"`
+	} `json:"offsets"`
+	ErrorCode int    `json:"error_code"`
+	Message   string `json:"message"`
+}
+
+// KafkaPublisher publishes outbox messages to Kafka through a REST Proxy, a topic per event
+// category, keyed by the subject of the event, so the events of a user land in the same partition
+// and stay in order. It implements the EventPublisherPort interface from the messaging ports package.
+type KafkaPublisher struct {
+	client      *http.Client
+	config      KafkaConfig
+	endpoint    *url.URL
+	contentType string
+}
+
+// NewKafkaPublisher creates a new KafkaPublisher.
+//
+// Parameters:
+//   - client: The HTTP client used to call the REST Proxy
+//   - config: The REST Proxy, the topics and the encoding
+//
+// Returns:
+//   - *KafkaPublisher: A pointer to the newly created KafkaPublisher
+//   - error: An error if the URL is malformed or the encoding unknown
+func NewKafkaPublisher(client *http.Client, config KafkaConfig) (*KafkaPublisher, error) {
+	endpoint, err := KafkaRESTProxyURL(config.RESTProxyURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateEncoding(config.Encoding); err != nil {
+		return nil, err
+	}
+	return &KafkaPublisher{client, config, endpoint, "application/vnd.kafka." + config.Encoding + ".v2+json"}, nil
+}
+
+// KafkaRESTProxyURL parses the base URL of a REST Proxy.
+//
+// Parameters:
+//   - baseURL: An http or https URL, e.g. "https://kafka-rest.example.com:8082"
+//
+// Returns:
+//   - *url.URL: The parsed URL
+//   - error: An error if the URL is not an http or https URL
+func KafkaRESTProxyURL(baseURL string) (*url.URL, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+		return nil, fmt.Errorf("Kafka REST Proxy URL %q must be an http or https URL", baseURL)
+	}
+	return parsed, nil
+}
+
+// ValidateEncoding checks the name of a payload encoding.
+//
+// Parameters:
+//   - encoding: EncodingJSON or EncodingAvro
+//
+// Returns:
+//   - error: An error if the encoding is unknown
+func ValidateEncoding(encoding string) error {
+	if encoding != EncodingJSON && encoding != EncodingAvro {
+		return fmt.Errorf("encoding %q must be json or avro", encoding)
+	}
+	return nil
+}
+
+// Name returns "kafka".
+func (kp *KafkaPublisher) Name() string {
+	return "kafka"
+}
+
+// Topic returns the topic the events of a category are published to.
+func (kp *KafkaPublisher) Topic(category string) string {
+	return kp.config.TopicPrefix + category
+}
+
+// Publish produces the messages to the topics of their categories, a request per topic.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the requests
+//   - messages: The messages to publish, in the order they are produced
+//
+// Returns:
+//   - error: An error if the REST Proxy cannot be reached, rejects a request or reports an error
+//     for any record
+func (kp *KafkaPublisher) Publish(ctx context.Context, messages []domain.OutboxMessage) error {
+	var topics []string
+	byTopic := map[string][]produceRecord{}
+	for _, message := range messages {
+		topic := kp.Topic(message.Category)
+		if _, ok := byTopic[topic]; !ok {
+			topics = append(topics, topic)
+		}
+		envelope := NewEnvelope(message)
+		var value any = envelope
+		if kp.config.Encoding == EncodingAvro {
+			value = envelope.avro()
+		}
+		byTopic[topic] = append(byTopic[topic], produceRecord{Key: message.Key, Value: value})
+	}
+
+	var errs []error
+	for _, topic := range topics {
+		if err := kp.produce(ctx, topic, byTopic[topic]); err != nil {
+			errs = append(errs, fmt.Errorf("topic %s: %w", topic, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// produce posts the records of a topic.
+func (kp *KafkaPublisher) produce(ctx context.Context, topic string, records []produceRecord) error {
+	request := produceRequest{Records: records}
+	if kp.config.Encoding == EncodingAvro {
+		request.KeySchema, request.ValueSchema = `"string"`, EnvelopeAvroSchema
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to encode records: %w", err)
+	}
+
+	if kp.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, kp.config.Timeout)
+		defer cancel()
+	}
+	endpoint := kp.endpoint.JoinPath("topics", topic)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create produce request: %w", err)
+	}
+	req.Header.Set("Content-Type", kp.contentType)
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+	if kp.config.Username != "" {
+		req.SetBasicAuth(kp.config.Username, kp.config.Password)
+	}
+
+	res, err := kp.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call the REST Proxy: %w", err)
+	}
+	defer res.Body.Close()
+	var response produceResponse
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&response); err != nil && res.StatusCode < 300 {
+		return fmt.Errorf("failed to decode produce response: %w", err)
+	}
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("REST Proxy answered %d: %s", res.StatusCode, response.Message)
+	}
+
+	var failures []string
+	for i, offset := range response.Offsets {
+		if offset.Error != nil || (offset.ErrorCode != nil && *offset.ErrorCode != 0) {
+			message := "unknown error"
+			if offset.Error != nil {
+				message = *offset.Error
+			}
+			failures = append(failures, fmt.Sprintf("record %d: %s", i, message))
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("%d of %d records failed: %s", len(failures), len(records), strings.Join(failures, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"strconv"
+	"strings"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/logging"
+)
+
+var logger = logging.Component("broker")
+
+// NatsConfig configures the NATS publisher.
+type NatsConfig struct {
+	// URL lists the NATS servers, comma-separated, e.g. "tls://nats-1.example.com:4222,tls://nats-2.example.com:4222".
+	URL string
+	// CredentialsFile is the path of a .creds file with the JWT and seed of the NATS user, if set.
+	CredentialsFile string
+	// SubjectPrefix is prepended to the subject of the event, e.g. "auth." publishes registrations
+	// to "auth.user.registered".
+	SubjectPrefix string
+	// Stream is the JetStream stream persisting the events.
+	Stream string
+	// CreateStream creates or updates the stream at startup, capturing every subject under the
+	// prefix. Otherwise the stream has to exist.
+	CreateStream bool
+	// DuplicateWindow is how long the stream remembers message ids to drop republished events. It
+	// has to exceed the longest backoff of the outbox relay.
+	DuplicateWindow time.Duration
+	// MaxAge is how long the stream keeps the events, forever if zero.
+	MaxAge time.Duration
+	// Timeout bounds connecting, setting up the stream and waiting for the acknowledgements of a batch.
+	Timeout time.Duration
+}
+
+// NatsPublisher publishes outbox messages to a NATS JetStream stream, a subject per event, e.g.
+// "auth.user.login.failed", so consumers subscribe to single events or whole hierarchies such as
+// "auth.user.login.>". The id of the outbox message is sent as Nats-Msg-Id, so the stream drops
+// the messages of a batch that is published again after a partial failure.
+// It implements the EventPublisherPort interface from the messaging ports package.
+type NatsPublisher struct {
+	jetStream jetstream.JetStream
+	config    NatsConfig
+}
+
+// NewNatsPublisher connects to NATS and creates a new NatsPublisher.
+//
+// The connection reconnects forever; batches published while it is down fail and are retried by
+// the relay.
+//
+// Parameters:
+//   - config: The servers, the subjects and the stream
+//
+// Returns:
+//   - *NatsPublisher: A pointer to the newly created NatsPublisher
+//   - error: An error if the servers cannot be reached, or the stream cannot be created or does not exist
+func NewNatsPublisher(config NatsConfig) (*NatsPublisher, error) {
+	options := []nats.Option{
+		nats.Name("hexagonal-auth-service"),
+		nats.Timeout(config.Timeout),
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				logger.Warn("Disconnected from NATS", "error", err)
+			}
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			logger.Info("Reconnected to NATS", "server", conn.ConnectedUrlRedacted())
+		}),
+	}
+	if config.CredentialsFile != "" {
+		options = append(options, nats.UserCredentials(config.CredentialsFile))
+	}
+	conn, err := nats.Connect(config.URL, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	jetStream, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+	defer cancel()
+	if config.CreateStream {
+		_, err = jetStream.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+			Name:        config.Stream,
+			Description: "Domain events of the auth service",
+			Subjects:    []string{config.SubjectPrefix + ">"},
+			Storage:     jetstream.FileStorage,
+			Duplicates:  config.DuplicateWindow,
+			MaxAge:      config.MaxAge,
+		})
+	} else {
+		_, err = jetStream.Stream(ctx, config.Stream)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to set up stream %s: %w", config.Stream, err)
+	}
+
+	return &NatsPublisher{jetStream, config}, nil
+}
+
+// Name returns "nats".
+func (np *NatsPublisher) Name() string {
+	return "nats"
+}
+
+// Subject returns the subject an event is published to: the prefix followed by the name of the
+// event, its underscores turned into subject tokens.
+func (np *NatsPublisher) Subject(eventName string) string {
+	return np.config.SubjectPrefix + strings.ReplaceAll(eventName, "_", ".")
+}
+
+// Publish publishes the messages asynchronously and waits for the stream to acknowledge all of them.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the wait
+//   - messages: The messages to publish, in the order they are stored
+//
+// Returns:
+//   - error: An error if a message could not be sent or was not acknowledged in time
+func (np *NatsPublisher) Publish(ctx context.Context, messages []domain.OutboxMessage) error {
+	if np.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, np.config.Timeout)
+		defer cancel()
+	}
+
+	futures := make([]jetstream.PubAckFuture, 0, len(messages))
+	for _, message := range messages {
+		data, err := json.Marshal(NewEnvelope(message))
+		if err != nil {
+			return fmt.Errorf("failed to encode message %s: %w", message.ID, err)
+		}
+		msg := nats.NewMsg(np.Subject(message.EventName))
+		msg.Data = data
+		msg.Header.Set("Content-Type", "application/json")
+		msg.Header.Set("Auth-Event-Category", message.Category)
+		msg.Header.Set("Auth-Event-Subject", message.Key)
+		msg.Header.Set("Auth-Schema-Version", strconv.Itoa(message.SchemaVersion))
+		future, err := np.jetStream.PublishMsgAsync(msg, jetstream.WithMsgID(message.ID), jetstream.WithExpectStream(np.config.Stream))
+		if err != nil {
+			return fmt.Errorf("failed to publish message %s: %w", message.ID, err)
+		}
+		futures = append(futures, future)
+	}
+
+	var errs []error
+	for i, future := range futures {
+		select {
+		case <-future.Ok():
+		case err := <-future.Err():
+			errs = append(errs, fmt.Errorf("message %s: %w", messages[i].ID, err))
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for the acknowledgements of %d messages: %w", len(futures)-i, ctx.Err())
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,189 @@
+package broker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/aws"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// snsBatchSize is the largest number of messages SNS accepts in a PublishBatch request.
+const snsBatchSize = 10
+
+// SNSConfig configures the SNS publisher.
+type SNSConfig struct {
+	// TopicARN is the topic the events are published to. FIFO topics, ending in ".fifo", receive
+	// the events of a user in order and drop duplicates by the outbox id.
+	TopicARN string
+	// Timeout bounds a PublishBatch request.
+	Timeout time.Duration
+}
+
+// publishBatchResponse is the result of a PublishBatch request in the query protocol.
+type publishBatchResponse struct {
+	Failed []struct {
+		ID      string `xml:"Id"`
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	} `xml:"PublishBatchResult>Failed>member"`
+	Error aws.QueryError `xml:"Error"`
+}
+
+// SNSPublisher publishes outbox messages to an Amazon SNS topic. The message is the envelope as
+// JSON; the event name, category, subject and schema version are sent as message attributes, so
+// subscriptions select events with filter policies such as {"eventType": ["user.login_failed"]}.
+// It implements the EventPublisherPort interface from the messaging ports package.
+type SNSPublisher struct {
+	client   *http.Client
+	config   SNSConfig
+	endpoint string
+	signer   *aws.Signer
+	fifo     bool
+}
+
+// NewSNSPublisher creates a new SNSPublisher.
+//
+// Parameters:
+//   - client: The HTTP client for the requests to SNS
+//   - config: The topic
+//   - credentials: The provider of the credentials the requests are signed with
+//
+// Returns:
+//   - *SNSPublisher: A pointer to the newly created SNSPublisher
+//   - error: An error if the topic ARN is malformed
+func NewSNSPublisher(client *http.Client, config SNSConfig, credentials aws.CredentialsProvider) (*SNSPublisher, error) {
+	region, err := SNSTopicRegion(config.TopicARN)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := "https://sns." + region + ".amazonaws.com/"
+	signer := aws.NewSigner("sns", region, credentials)
+	return &SNSPublisher{client, config, endpoint, signer, strings.HasSuffix(config.TopicARN, ".fifo")}, nil
+}
+
+// SNSTopicRegion returns the region of an SNS topic.
+//
+// Parameters:
+//   - topicARN: The ARN of the topic, e.g. "arn:aws:sns:eu-central-1:123456789012:auth-events"
+//
+// Returns:
+//   - string: The region, e.g. "eu-central-1"
+//   - error: An error if the ARN does not name an SNS topic
+func SNSTopicRegion(topicARN string) (string, error) {
+	parts := strings.Split(topicARN, ":")
+	if len(parts) != 6 || parts[0] != "arn" || parts[2] != "sns" || parts[3] == "" || parts[5] == "" {
+		return "", fmt.Errorf("%q is not the ARN of an SNS topic", topicARN)
+	}
+	return parts[3], nil
+}
+
+// Name returns "sns".
+func (sp *SNSPublisher) Name() string {
+	return "sns"
+}
+
+// Publish publishes the messages in batches of ten.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the requests
+//   - messages: The messages to publish, in the order they are stored
+//
+// Returns:
+//   - error: An error if SNS cannot be reached or fails any message
+func (sp *SNSPublisher) Publish(ctx context.Context, messages []domain.OutboxMessage) error {
+	for start := 0; start < len(messages); start += snsBatchSize {
+		if err := sp.publishBatch(ctx, messages[start:min(start+snsBatchSize, len(messages))]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// publishBatch sends a PublishBatch request.
+func (sp *SNSPublisher) publishBatch(ctx context.Context, messages []domain.OutboxMessage) error {
+	form := url.Values{
+		"Action":   {"PublishBatch"},
+		"Version":  {"2010-03-31"},
+		"TopicArn": {sp.config.TopicARN},
+	}
+	for i, message := range messages {
+		body, err := json.Marshal(NewEnvelope(message))
+		if err != nil {
+			return fmt.Errorf("failed to encode message %s: %w", message.ID, err)
+		}
+		entry := "PublishBatchRequestEntries.member." + strconv.Itoa(i+1) + "."
+		form.Set(entry+"Id", message.ID)
+		form.Set(entry+"Message", string(body))
+		if sp.fifo {
+			// events about no particular user are ordered within their category
+			group := message.Key
+			if group == "" {
+				group = message.Category
+			}
+			form.Set(entry+"MessageGroupId", group)
+			form.Set(entry+"MessageDeduplicationId", message.ID)
+		}
+		attributes := []struct{ name, dataType, value string }{
+			{"eventType", "String", message.EventName},
+			{"category", "String", message.Category},
+			{"subject", "String", message.Key},
+			{"schemaVersion", "Number", strconv.Itoa(message.SchemaVersion)},
+		}
+		n := 0
+		for _, attribute := range attributes {
+			// SNS rejects empty attribute values, e.g. the subject of events about no user
+			if attribute.value == "" {
+				continue
+			}
+			n++
+			prefix := entry + "MessageAttributes.entry." + strconv.Itoa(n) + "."
+			form.Set(prefix+"Name", attribute.name)
+			form.Set(prefix+"Value.DataType", attribute.dataType)
+			form.Set(prefix+"Value.StringValue", attribute.value)
+		}
+	}
+	body := []byte(form.Encode())
+
+	if sp.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, sp.config.Timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sp.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create PublishBatch request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	if err := sp.signer.Sign(ctx, req, body); err != nil {
+		return err
+	}
+
+	res, err := sp.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call SNS: %w", err)
+	}
+	defer res.Body.Close()
+	var response publishBatchResponse
+	if err := xml.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&response); err != nil {
+		return fmt.Errorf("unexpected response with status %d from SNS", res.StatusCode)
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("SNS rejected the batch: %w", response.Error)
+	}
+
+	var errs []error
+	for _, failed := range response.Failed {
+		errs = append(errs, fmt.Errorf("message %s: %s: %s", failed.ID, failed.Code, failed.Message))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,111 @@
+// Package commands receives administrative commands from other systems over message queues, e.g.
+// a fraud detection system disabling an account, and runs them as the admin API would.
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/logging"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+var logger = logging.Component("commands")
+
+// Types of commands.
+const (
+	TypeDisableUser = "disable_user"
+	TypeEnableUser  = "enable_user"
+	TypeLockUser    = "lock_user"
+	TypeUnlockUser  = "unlock_user"
+)
+
+// ErrInvalidCommand is returned for commands that can never succeed, e.g. of an unknown type.
+var ErrInvalidCommand = errors.New("invalid command")
+
+// Command is an administrative command about a user, e.g.
+// {"command": "lock_user", "userId": "6650f1...", "reason": "FRAUD", "issuedBy": "fraud-detection"}.
+type Command struct {
+	// Type is one of the Type constants.
+	Type string `json:"command"`
+	// UserID is the id of the user the command is about.
+	UserID string `json:"userId"`
+	// TenantID is the tenant of the user, the default tenant if empty.
+	TenantID string `json:"tenantId,omitempty"`
+	// Reason is the lock reason of lock_user commands, e.g. "FRAUD".
+	Reason string `json:"reason,omitempty"`
+	// Until is when the lock of lock_user commands expires, never if empty.
+	Until time.Time `json:"until,omitzero"`
+	// IssuedBy names the system sending the command, recorded as the locking party.
+	IssuedBy string `json:"issuedBy,omitempty"`
+	// RequestID correlates the events of the command with the sender, a new id is used if empty.
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// Handler runs commands with the use cases of the admin API.
+type Handler struct {
+	status usecases.ChangeUserStatusPort
+	locks  usecases.LockUserPort
+}
+
+// NewHandler creates a new Handler.
+//
+// Parameters:
+//   - status: An implementation of ChangeUserStatusPort for disabling and enabling users
+//   - locks: An implementation of LockUserPort for locking and unlocking users
+//
+// Returns:
+//   - *Handler: A pointer to the newly created Handler
+func NewHandler(status usecases.ChangeUserStatusPort, locks usecases.LockUserPort) *Handler {
+	return &Handler{status, locks}
+}
+
+// Handle runs a command. The context has to name the tenant of the command.
+//
+// Parameters:
+//   - ctx: A context.Context naming the tenant and the request id
+//   - command: The command to run
+//
+// Returns:
+//   - error: ErrInvalidCommand for malformed commands, or the error of the use case
+func (h *Handler) Handle(ctx context.Context, command Command) error {
+	if command.UserID == "" {
+		return fmt.Errorf("%w: userId is missing", ErrInvalidCommand)
+	}
+	switch command.Type {
+	case TypeDisableUser:
+		return h.status.DisableUser(ctx, command.UserID)
+	case TypeEnableUser:
+		return h.status.EnableUser(ctx, command.UserID)
+	case TypeLockUser:
+		lockedBy := command.IssuedBy
+		if lockedBy == "" {
+			lockedBy = "queue"
+		}
+		return h.locks.LockUser(ctx, command.UserID, domain.LockReason(command.Reason), command.Until, lockedBy)
+	case TypeUnlockUser:
+		return h.locks.UnlockUser(ctx, command.UserID)
+	default:
+		return fmt.Errorf("%w: unknown command %q", ErrInvalidCommand, command.Type)
+	}
+}
+
+// Permanent reports whether a command failed for good, so retrying it is pointless: it is
+// malformed or rejected by the domain, e.g. for an unknown user. Other failures, e.g. of the
+// database, are temporary.
+//
+// Parameters:
+//   - err: The error of Handle
+//
+// Returns:
+//   - bool: true if the command will never succeed
+func Permanent(err error) bool {
+	if errors.Is(err, ErrInvalidCommand) {
+		return true
+	}
+	e, ok := errorx.As(err)
+	return ok && !errors.Is(e, errorx.ErrDependencyUnavailable) && !errors.Is(e, errorx.ErrOverloaded)
+}
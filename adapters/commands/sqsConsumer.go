@@ -0,0 +1,218 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/aws"
+	"user-auth-hexagonal-architecture/internal/requestid"
+	"user-auth-hexagonal-architecture/internal/tenant"
+)
+
+// SQSConfig configures the SQS consumer.
+type SQSConfig struct {
+	// QueueURL is the queue the commands are received from, e.g.
+	// "https://sqs.eu-central-1.amazonaws.com/123456789012/auth-commands".
+	QueueURL string
+	// WaitTime is how long a receive request waits for messages, at most 20 seconds.
+	WaitTime time.Duration
+	// MaxMessages is the largest number of messages received at once, at most 10.
+	MaxMessages int
+}
+
+// sqsMessage is a message of a ReceiveMessage response.
+type sqsMessage struct {
+	MessageID     string `json:"MessageId"`
+	ReceiptHandle string `json:"ReceiptHandle"`
+	Body          string `json:"Body"`
+}
+
+// snsNotification is the body of messages a queue receives from an SNS subscription without raw
+// message delivery.
+type snsNotification struct {
+	Type    string `json:"Type"`
+	Message string `json:"Message"`
+}
+
+// SQSConsumer receives commands from an Amazon SQS queue with long polling and runs them.
+//
+// A message is deleted once its command succeeded or failed for good, e.g. for an unknown user.
+// Messages whose command failed temporarily stay in the queue and are received again after the
+// visibility timeout of the queue; a redrive policy moves them to a dead-letter queue eventually.
+// Commands run at least once, which is harmless as they are idempotent.
+type SQSConsumer struct {
+	client   *http.Client
+	config   SQSConfig
+	endpoint string
+	signer   *aws.Signer
+	handler  *Handler
+}
+
+// NewSQSConsumer creates a new SQSConsumer.
+//
+// Parameters:
+//   - client: The HTTP client for the requests to SQS, its timeout has to exceed the wait time
+//   - config: The queue and the polling
+//   - credentials: The provider of the credentials the requests are signed with
+//   - handler: The handler running the commands
+//
+// Returns:
+//   - *SQSConsumer: A pointer to the newly created SQSConsumer
+//   - error: An error if the queue URL is malformed
+func NewSQSConsumer(client *http.Client, config SQSConfig, credentials aws.CredentialsProvider, handler *Handler) (*SQSConsumer, error) {
+	region, err := SQSQueueRegion(config.QueueURL)
+	if err != nil {
+		return nil, err
+	}
+	signer := aws.NewSigner("sqs", region, credentials)
+	return &SQSConsumer{client, config, "https://sqs." + region + ".amazonaws.com/", signer, handler}, nil
+}
+
+// SQSQueueRegion returns the region of an SQS queue.
+//
+// Parameters:
+//   - queueURL: The URL of the queue, e.g. "https://sqs.eu-central-1.amazonaws.com/123456789012/auth-commands"
+//
+// Returns:
+//   - string: The region, e.g. "eu-central-1"
+//   - error: An error if the URL is not the URL of an SQS queue
+func SQSQueueRegion(queueURL string) (string, error) {
+	parsed, err := url.Parse(queueURL)
+	if err == nil && parsed.Scheme == "https" && strings.Count(strings.Trim(parsed.Path, "/"), "/") == 1 {
+		if region, ok := strings.CutPrefix(parsed.Hostname(), "sqs."); ok {
+			if region, ok = strings.CutSuffix(region, ".amazonaws.com"); ok && region != "" {
+				return region, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%q is not the URL of an SQS queue", queueURL)
+}
+
+// Run receives and runs commands until the context is cancelled. Failing receive requests are
+// retried with exponential backoff up to a minute.
+//
+// Parameters:
+//   - ctx: A context.Context controlling the lifetime of the consumer
+func (sc *SQSConsumer) Run(ctx context.Context) {
+	backoff := time.Second
+	for ctx.Err() == nil {
+		messages, err := sc.receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.WarnContext(ctx, "Error receiving commands from SQS, retrying", "retry_in", backoff, "error", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, time.Minute)
+			continue
+		}
+		backoff = time.Second
+		for _, message := range messages {
+			sc.process(ctx, message)
+		}
+	}
+}
+
+// process runs the command of a message and deletes the message unless it is worth another attempt.
+func (sc *SQSConsumer) process(ctx context.Context, message sqsMessage) {
+	command, err := decodeCommand(message.Body)
+	if err == nil {
+		id := command.RequestID
+		if id == "" {
+			id = requestid.New()
+		}
+		commandCtx := requestid.WithID(ctx, id)
+		if command.TenantID != "" {
+			commandCtx = tenant.WithID(commandCtx, command.TenantID)
+		}
+		err = sc.handler.Handle(commandCtx, command)
+		if err == nil {
+			logger.InfoContext(commandCtx, "Ran command from SQS", "command", command.Type, "user_id", command.UserID, "issued_by", command.IssuedBy)
+		}
+	}
+	if err != nil && !Permanent(err) {
+		logger.WarnContext(ctx, "Error running command from SQS, it is received again", "message_id", message.MessageID, "error", err)
+		return
+	}
+	if err != nil {
+		logger.ErrorContext(ctx, "Dropping command from SQS", "message_id", message.MessageID, "error", err)
+	}
+	if err := sc.call(ctx, "DeleteMessage", map[string]any{"QueueUrl": sc.config.QueueURL, "ReceiptHandle": message.ReceiptHandle}, nil); err != nil {
+		logger.WarnContext(ctx, "Error deleting command from SQS, it is received again", "message_id", message.MessageID, "error", err)
+	}
+}
+
+// decodeCommand decodes the body of a message, a command or an SNS notification carrying one.
+func decodeCommand(body string) (Command, error) {
+	var notification snsNotification
+	if err := json.Unmarshal([]byte(body), &notification); err == nil && notification.Type == "Notification" {
+		body = notification.Message
+	}
+	var command Command
+	if err := json.Unmarshal([]byte(body), &command); err != nil {
+		return Command{}, fmt.Errorf("%w: %v", ErrInvalidCommand, err)
+	}
+	return command, nil
+}
+
+// receive long-polls the queue for messages.
+func (sc *SQSConsumer) receive(ctx context.Context) ([]sqsMessage, error) {
+	var response struct {
+		Messages []sqsMessage `json:"Messages"`
+	}
+	err := sc.call(ctx, "ReceiveMessage", map[string]any{
+		"QueueUrl":            sc.config.QueueURL,
+		"MaxNumberOfMessages": sc.config.MaxMessages,
+		"WaitTimeSeconds":     int(sc.config.WaitTime.Seconds()),
+	}, &response)
+	return response.Messages, err
+}
+
+// call sends a request of the AWS JSON protocol to SQS and decodes the response into target.
+func (sc *SQSConsumer) call(ctx context.Context, action string, input map[string]any, target any) error {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sc.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.0")
+	req.Header.Set("X-Amz-Target", "AmazonSQS."+action)
+	if err := sc.signer.Sign(ctx, req, body); err != nil {
+		return err
+	}
+
+	res, err := sc.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call SQS %s: %w", action, err)
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(res.Body, 4<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read SQS %s response: %w", action, err)
+	}
+	if res.StatusCode != http.StatusOK {
+		var failure struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(data, &failure)
+		return fmt.Errorf("SQS %s failed with status %d: %s %s", action, res.StatusCode, failure.Type, failure.Message)
+	}
+	if target == nil {
+		return nil
+	}
+	return json.Unmarshal(data, target)
+}
@@ -0,0 +1,13 @@
+package errorreport
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/ports/telemetry"
+)
+
+// Discard drops all reports, used when no error tracking service is configured.
+// It implements the ErrorReporterPort interface from the telemetry ports package.
+type Discard struct{}
+
+// ReportError does nothing.
+func (Discard) ReportError(context.Context, telemetry.ErrorReport) {}
@@ -0,0 +1,275 @@
+// Package errorreport sends unexpected errors and panics to error tracking services.
+package errorreport
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	mathrand "math/rand/v2"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+	"user-auth-hexagonal-architecture/internal/logging"
+	"user-auth-hexagonal-architecture/internal/ports/telemetry"
+)
+
+// logger writes the log records of this package. Failures of the reporter itself are logged as
+// warnings, which are not reported, so a broken error tracking service cannot cause a feedback loop.
+var logger = logging.Component("errorreport")
+
+// maxTagLength is the longest tag value Sentry accepts.
+const maxTagLength = 200
+
+// SentryConfig configures the Sentry reporter.
+type SentryConfig struct {
+	// DSN is the client key of the Sentry project, e.g. "https://<key>@o1.ingest.sentry.io/<project>".
+	DSN string
+	// SampleRate is the fraction of reports sent, between 0 and 1.
+	SampleRate float64
+	// Environment names the deployment, e.g. "production".
+	Environment string
+	// Release names the version of the service.
+	Release string
+	// QueueSize is the number of reports that may wait to be sent. Reports exceeding it are dropped.
+	QueueSize int
+	// Timeout bounds sending a single report.
+	Timeout time.Duration
+}
+
+// sentryEvent is the JSON payload of a Sentry event.
+type sentryEvent struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   time.Time         `json:"timestamp"`
+	Level       string            `json:"level"`
+	Platform    string            `json:"platform"`
+	Logger      string            `json:"logger,omitempty"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	Fingerprint []string          `json:"fingerprint"`
+	Exception   sentryExceptions  `json:"exception"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Extra       map[string]string `json:"extra,omitempty"`
+	User        *sentryUser       `json:"user,omitempty"`
+}
+
+// sentryExceptions lists the exceptions of an event.
+type sentryExceptions struct {
+	Values []sentryException `json:"values"`
+}
+
+// sentryException describes an error of an event.
+type sentryException struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// sentryUser identifies the user affected by an event.
+type sentryUser struct {
+	ID string `json:"id"`
+}
+
+// SentryReporter sends error reports to Sentry through its envelope endpoint.
+// It implements the ErrorReporterPort interface from the telemetry ports package.
+//
+// Reports are queued and sent by a background worker, so reporting never delays a request.
+type SentryReporter struct {
+	client   *http.Client
+	config   SentryConfig
+	endpoint string
+	auth     string
+	server   string
+	queue    chan sentryEvent
+}
+
+// NewSentryReporter creates a new SentryReporter. Call Start to begin sending.
+//
+// Parameters:
+//   - client: The HTTP client used to send the reports
+//   - config: The DSN, sampling and queue of the reporter
+//
+// Returns:
+//   - *SentryReporter: A pointer to the newly created SentryReporter
+//   - error: An error if the DSN or the sample rate is invalid
+func NewSentryReporter(client *http.Client, config SentryConfig) (*SentryReporter, error) {
+	endpoint, key, err := ParseDSN(config.DSN)
+	if err != nil {
+		return nil, err
+	}
+	if config.SampleRate < 0 || config.SampleRate > 1 {
+		return nil, fmt.Errorf("sample rate %v is not between 0 and 1", config.SampleRate)
+	}
+	server, _ := os.Hostname()
+	auth := "Sentry sentry_version=7, sentry_client=user-auth-hexagonal-architecture/" + config.Release + ", sentry_key=" + key
+	return &SentryReporter{client, config, endpoint, auth, server, make(chan sentryEvent, max(config.QueueSize, 1))}, nil
+}
+
+// ParseDSN splits a Sentry DSN into the envelope endpoint and the public key.
+//
+// Parameters:
+//   - dsn: A DSN of the form "https://<key>@<host>[/<path>]/<project>"
+//
+// Returns:
+//   - string: The URL of the envelope endpoint
+//   - string: The public key
+//   - error: An error if the DSN is malformed
+func ParseDSN(dsn string) (string, string, error) {
+	parsed, err := url.Parse(dsn)
+	if err != nil {
+		return "", "", errors.New("malformed Sentry DSN")
+	}
+	if parsed.Scheme != "https" && parsed.Scheme != "http" {
+		return "", "", errors.New("Sentry DSN must be an http or https URL")
+	}
+	if parsed.User == nil || parsed.User.Username() == "" {
+		return "", "", errors.New("Sentry DSN lacks the public key")
+	}
+	prefix, project := "", strings.TrimPrefix(parsed.Path, "/")
+	if i := strings.LastIndex(project, "/"); i >= 0 {
+		prefix, project = project[:i+1], project[i+1:]
+	}
+	if project == "" {
+		return "", "", errors.New("Sentry DSN lacks the project id")
+	}
+	endpoint := parsed.Scheme + "://" + parsed.Host + "/" + prefix + "api/" + project + "/envelope/"
+	return endpoint, parsed.User.Username(), nil
+}
+
+// Start launches the worker sending the reports. It keeps running until the given context is cancelled.
+//
+// Parameters:
+//   - ctx: A context.Context controlling the lifetime of the worker
+func (sr *SentryReporter) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-sr.queue:
+				if err := sr.send(ctx, event); err != nil {
+					logger.WarnContext(ctx, "Error sending error report to Sentry", "event_id", event.EventID, "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// ReportError queues a report unless it is sampled out or the queue is full.
+//
+// Parameters:
+//   - ctx: A context.Context of the failing operation; sending outlives it
+//   - report: The sanitized description of the error
+func (sr *SentryReporter) ReportError(ctx context.Context, report telemetry.ErrorReport) {
+	if sr.config.SampleRate < 1 && mathrand.Float64() >= sr.config.SampleRate {
+		return
+	}
+
+	select {
+	case sr.queue <- sr.event(report):
+	default:
+		logger.WarnContext(ctx, "Error report queue full, dropping report", "message", report.Message)
+	}
+}
+
+// event converts a report into a Sentry event, grouped by its message.
+func (sr *SentryReporter) event(report telemetry.ErrorReport) sentryEvent {
+	event := sentryEvent{
+		EventID:     newEventID(),
+		Timestamp:   time.Now().UTC(),
+		Level:       "error",
+		Platform:    "go",
+		Logger:      report.Tags["component"],
+		ServerName:  sr.server,
+		Environment: sr.config.Environment,
+		Release:     sr.config.Release,
+		Fingerprint: []string{report.Message},
+		Exception:   sentryExceptions{Values: []sentryException{{Type: report.Message, Value: report.Error}}},
+		Tags:        make(map[string]string, len(report.Tags)+1),
+		Extra:       report.Context,
+	}
+	for name, value := range report.Tags {
+		event.Tags[name] = truncate(value)
+	}
+	if report.RequestID != "" {
+		event.Tags["request_id"] = truncate(report.RequestID)
+	}
+	if report.UserID != "" {
+		event.User = &sentryUser{ID: report.UserID}
+	}
+	if report.Stack != "" {
+		event.Level = "fatal"
+		event.Extra = make(map[string]string, len(report.Context)+1)
+		for name, value := range report.Context {
+			event.Extra[name] = value
+		}
+		event.Extra["stack"] = report.Stack
+	}
+	return event
+}
+
+// send posts an event as envelope.
+func (sr *SentryReporter) send(ctx context.Context, event sentryEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, sr.config.Timeout)
+	defer cancel()
+
+	body, err := envelope(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sr.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create Sentry request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", sr.auth)
+
+	resp, err := sr.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send to Sentry: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Sentry responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// envelope encodes an event as a Sentry envelope: a header line, an item header line and the event.
+func envelope(event sentryEvent) ([]byte, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode Sentry event: %w", err)
+	}
+	header, _ := json.Marshal(map[string]any{"event_id": event.EventID, "sent_at": time.Now().UTC()})
+	itemHeader, _ := json.Marshal(map[string]any{"type": "event", "length": len(payload)})
+
+	var body bytes.Buffer
+	for _, line := range [][]byte{header, itemHeader, payload} {
+		body.Write(line)
+		body.WriteByte('\n')
+	}
+	return body.Bytes(), nil
+}
+
+// truncate shortens a tag value to the length Sentry accepts.
+func truncate(value string) string {
+	if len(value) <= maxTagLength {
+		return value
+	}
+	return value[:maxTagLength]
+}
+
+// newEventID returns a random id of 32 hex digits.
+func newEventID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
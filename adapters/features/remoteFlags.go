@@ -0,0 +1,112 @@
+package features
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/logging"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+)
+
+// logger writes the log records of this package.
+var logger = logging.Component("features")
+
+// maxDocumentBytes bounds the size of the flag document read from the remote service.
+const maxDocumentBytes = 1 << 20
+
+// RemoteFlags decides feature flags from a JSON document fetched periodically from a flag service,
+// e.g. {"defaults": {"open-registration": false}, "tenants": {"acme": {"require-mfa": true}}}.
+// Flags the document does not configure, and all flags before the first successful fetch, are
+// decided by the fallback. It implements the FeatureFlagPort interface from the system ports package.
+type RemoteFlags struct {
+	client   *http.Client
+	url      string
+	fallback system.FeatureFlagPort
+	flags    atomic.Pointer[domain.FeatureFlags]
+}
+
+// NewRemoteFlags creates a new RemoteFlags. Call Refresh or RefreshEvery to fetch the document.
+//
+// Parameters:
+//   - client: The HTTP client used to fetch the document
+//   - url: The URL of the flag document
+//   - fallback: An implementation of FeatureFlagPort deciding the flags the document does not configure
+//
+// Returns:
+//   - *RemoteFlags: A pointer to the newly created RemoteFlags
+func NewRemoteFlags(client *http.Client, url string, fallback system.FeatureFlagPort) *RemoteFlags {
+	rf := &RemoteFlags{client: client, url: url, fallback: fallback}
+	rf.flags.Store(&domain.FeatureFlags{})
+	return rf
+}
+
+// Enabled returns the value of the flag from the document for the tenant of the request, else the value of the fallback.
+func (rf *RemoteFlags) Enabled(ctx context.Context, flag domain.FeatureFlag) bool {
+	if enabled, ok := rf.flags.Load().Lookup(flag, tenantOf(ctx)); ok {
+		return enabled
+	}
+	return rf.fallback.Enabled(ctx, flag)
+}
+
+// Refresh fetches the flag document. A document that cannot be fetched or names unknown flags
+// leaves the previous values in effect.
+//
+// Parameters:
+//   - ctx: A context.Context bounding the fetch
+//
+// Returns:
+//   - error: An error if the document cannot be fetched or is invalid
+func (rf *RemoteFlags) Refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rf.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create feature flag request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := rf.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch feature flags: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("feature flag service responded with status %d", resp.StatusCode)
+	}
+
+	var flags domain.FeatureFlags
+	decoder := json.NewDecoder(io.LimitReader(resp.Body, maxDocumentBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&flags); err != nil {
+		return fmt.Errorf("failed to decode feature flags: %w", err)
+	}
+	if err := flags.Validate(); err != nil {
+		return fmt.Errorf("invalid feature flags: %w", err)
+	}
+	rf.flags.Store(&flags)
+	return nil
+}
+
+// RefreshEvery fetches the flag document periodically until the context is cancelled.
+// Failed fetches are logged and keep the previous values in effect.
+//
+// Parameters:
+//   - ctx: A context.Context that stops the refreshing
+//   - interval: The time between two fetches
+func (rf *RemoteFlags) RefreshEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := rf.Refresh(ctx); err != nil {
+				logger.WarnContext(ctx, "Error refreshing feature flags, keeping the previous values", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+// Package features decides feature flags from the configuration or from a remote flag service.
+package features
+
+import (
+	"context"
+	"sync/atomic"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/tenant"
+)
+
+// StaticFlags decides feature flags from configured values that can be replaced while the service runs.
+// It implements the FeatureFlagPort interface from the system ports package.
+type StaticFlags struct {
+	flags atomic.Pointer[domain.FeatureFlags]
+}
+
+// NewStaticFlags creates a new StaticFlags.
+//
+// Parameters:
+//   - flags: The configured values, flags without a value keep their default
+//
+// Returns:
+//   - *StaticFlags: A pointer to the newly created StaticFlags
+func NewStaticFlags(flags domain.FeatureFlags) *StaticFlags {
+	sf := &StaticFlags{}
+	sf.Set(flags)
+	return sf
+}
+
+// Set replaces the configured values; requests in flight keep deciding with the previous ones.
+func (sf *StaticFlags) Set(flags domain.FeatureFlags) {
+	sf.flags.Store(&flags)
+}
+
+// Enabled returns the configured value of the flag for the tenant of the request, else its default.
+func (sf *StaticFlags) Enabled(ctx context.Context, flag domain.FeatureFlag) bool {
+	if enabled, ok := sf.flags.Load().Lookup(flag, tenantOf(ctx)); ok {
+		return enabled
+	}
+	return flag.Default()
+}
+
+// tenantOf returns the tenant of the request, the default tenant if the request does not name one.
+func tenantOf(ctx context.Context) string {
+	if id := tenant.FromContext(ctx); id != "" {
+		return id
+	}
+	return domain.DefaultTenantID
+}
@@ -0,0 +1,161 @@
+// Package grpcapi provides the gRPC adapter exposing the authentication use cases to internal
+// service-to-service callers. It is the gRPC counterpart of the HTTP api package and drives the
+// same primary ports.
+package grpcapi
+
+import (
+	"context"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"net"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/grpc/authv1"
+	"user-auth-hexagonal-architecture/internal/clientip"
+	"user-auth-hexagonal-architecture/internal/device"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// AuthServer implements the AuthService defined in api/proto/auth/v1/auth.proto.
+type AuthServer struct {
+	authv1.UnimplementedAuthServiceServer
+	registerUserPort usecases.RegisterUserPort
+	loadUserPort     usecases.LoadUserPort
+	getUserPort      usecases.GetUserPort
+	verifyTokenPort  usecases.VerifyTokenPort
+}
+
+// NewAuthServer creates a new AuthServer with the given use case ports.
+//
+// Parameters:
+//   - registerUserPort: Port for user registration use case
+//   - loadUserPort: Port for user authentication use case
+//   - getUserPort: Port for reading an account by id
+//   - verifyTokenPort: Port for verifying access tokens
+//
+// Returns:
+//   - *AuthServer: A pointer to the newly created AuthServer
+func NewAuthServer(registerUserPort usecases.RegisterUserPort, loadUserPort usecases.LoadUserPort, getUserPort usecases.GetUserPort, verifyTokenPort usecases.VerifyTokenPort) *AuthServer {
+	return &AuthServer{registerUserPort: registerUserPort, loadUserPort: loadUserPort, getUserPort: getUserPort, verifyTokenPort: verifyTokenPort}
+}
+
+// RegisterUser creates a new account. The gRPC API cannot accept the terms and privacy policy, so
+// registrations fail with FailedPrecondition once a version of them has been published.
+func (as *AuthServer) RegisterUser(ctx context.Context, req *authv1.RegisterUserRequest) (*authv1.RegisterUserResponse, error) {
+	if req.GetUsername() == "" || req.GetPassword() == "" {
+		return nil, invalidArgument("username and password are required")
+	}
+	if _, err := as.registerUserPort.RegisterUser(deviceContext(ctx), req.GetUsername(), req.GetPassword(), req.GetEmail(), nil); err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &authv1.RegisterUserResponse{}, nil
+}
+
+// Login authenticates a user and issues an access token. Users who have not accepted the current
+// terms and privacy policy yet, or have to change a temporary password, have to log in over HTTP
+// once to do so.
+func (as *AuthServer) Login(ctx context.Context, req *authv1.LoginRequest) (*authv1.LoginResponse, error) {
+	if req.GetUsername() == "" || req.GetPassword() == "" {
+		return nil, invalidArgument("username and password are required")
+	}
+	tokens, err := as.loadUserPort.LoadUser(deviceContext(ctx), req.GetUsername(), req.GetPassword(), req.GetMfaCode(), domain.LoginMethodPassword, nil)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	if tokens.PasswordChangeToken != "" {
+		return nil, toStatus(ctx, errorx.ErrPasswordChangeRequired.Detailf("log in over HTTP to change the temporary password"))
+	}
+	return &authv1.LoginResponse{
+		AccessToken:  tokens.AccessToken,
+		TokenType:    tokens.TokenType,
+		ExpiresIn:    int64(time.Until(tokens.ExpiresAt).Seconds()),
+		RefreshToken: tokens.RefreshToken,
+	}, nil
+}
+
+// VerifyToken checks an access token and its session. An invalid token, or one whose session has
+// ended, is not an error but reported as not valid.
+func (as *AuthServer) VerifyToken(ctx context.Context, req *authv1.VerifyTokenRequest) (*authv1.VerifyTokenResponse, error) {
+	principal, err := as.verifyTokenPort.VerifyToken(ctx, req.GetToken())
+	if err != nil {
+		return &authv1.VerifyTokenResponse{Valid: false}, nil
+	}
+	return &authv1.VerifyTokenResponse{
+		Valid:     true,
+		Subject:   principal.Subject,
+		Roles:     roleNames(principal.Roles),
+		ExpiresAt: timestamppb.New(principal.ExpiresAt),
+	}, nil
+}
+
+// GetUser returns an account by id.
+func (as *AuthServer) GetUser(ctx context.Context, req *authv1.GetUserRequest) (*authv1.GetUserResponse, error) {
+	if req.GetId() == "" {
+		return nil, invalidArgument("id is required")
+	}
+	user, err := as.getUserPort.GetUser(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	response := &authv1.User{
+		Id:         user.ID,
+		Username:   user.Username,
+		Email:      user.Email,
+		Role:       primaryRole(user.Roles),
+		Status:     string(user.Status),
+		CreatedAt:  timestamppb.New(user.CreatedAt),
+		MfaEnabled: user.MfaEnabled,
+	}
+	if !user.LastLoginAt.IsZero() {
+		response.LastLoginAt = timestamppb.New(user.LastLoginAt)
+	}
+	return &authv1.GetUserResponse{User: response}, nil
+}
+
+// roleNames converts roles into their names.
+func roleNames(roles []domain.Role) []string {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, string(role))
+	}
+	return names
+}
+
+// primaryRole returns the most privileged role for the single role field of the User message,
+// which predates users holding several roles.
+func primaryRole(roles []domain.Role) string {
+	for _, role := range roles {
+		if role == domain.RoleAdmin {
+			return string(role)
+		}
+	}
+	if len(roles) == 0 {
+		return ""
+	}
+	return string(roles[0])
+}
+
+// deviceContext returns ctx carrying the device the caller reports, which the login use case
+// records in the new session.
+func deviceContext(ctx context.Context) context.Context {
+	var d domain.Device
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("user-agent"); len(values) > 0 {
+			d.UserAgent = values[0]
+		}
+		if values := md.Get(device.CountryHeader); len(values) > 0 {
+			d.Country = device.Country(values[0])
+		}
+	}
+	d.IPAddress = clientip.FromContext(ctx)
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil && d.IPAddress == "" {
+		d.IPAddress = p.Addr.String()
+		if host, _, err := net.SplitHostPort(d.IPAddress); err == nil {
+			d.IPAddress = host
+		}
+	}
+	return device.WithDevice(ctx, d)
+}
@@ -0,0 +1,82 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// domainCodes maps the codes of the domain errors onto gRPC status codes. Codes not listed here,
+// e.g. of errors only the HTTP adapters report, are treated as internal errors.
+var domainCodes = map[errorx.Code]codes.Code{
+	errorx.CodeInvalidCredentials:         codes.Unauthenticated,
+	errorx.CodeAccountDisabled:            codes.PermissionDenied,
+	errorx.CodeAccountLocked:              codes.PermissionDenied,
+	errorx.CodeAccountPending:             codes.PermissionDenied,
+	errorx.CodeInvalidStatusTransition:    codes.FailedPrecondition,
+	errorx.CodeUsernameTaken:              codes.AlreadyExists,
+	errorx.CodeEmailTaken:                 codes.AlreadyExists,
+	errorx.CodeUserNotFound:               codes.NotFound,
+	errorx.CodeUnknownRole:                codes.InvalidArgument,
+	errorx.CodeGroupNotFound:              codes.NotFound,
+	errorx.CodeInvalidUsername:            codes.InvalidArgument,
+	errorx.CodeInvalidEmail:               codes.InvalidArgument,
+	errorx.CodeInvalidProfile:             codes.InvalidArgument,
+	errorx.CodeWeakPassword:               codes.InvalidArgument,
+	errorx.CodeTenantNotFound:             codes.NotFound,
+	errorx.CodeMfaRequired:                codes.PermissionDenied,
+	errorx.CodeLoginMethodNotAllowed:      codes.PermissionDenied,
+	errorx.CodeSessionLimitReached:        codes.ResourceExhausted,
+	errorx.CodeLoginBlocked:               codes.PermissionDenied,
+	errorx.CodeConsentRequired:            codes.FailedPrecondition,
+	errorx.CodeInvalidConsent:             codes.InvalidArgument,
+	errorx.CodeInvalidToken:               codes.Unauthenticated,
+	errorx.CodeInvalidRefreshToken:        codes.Unauthenticated,
+	errorx.CodeRefreshTokenReused:         codes.Unauthenticated,
+	errorx.CodeLastAdmin:                  codes.FailedPrecondition,
+	errorx.CodeRoleRequired:               codes.FailedPrecondition,
+	errorx.CodeLegalHold:                  codes.FailedPrecondition,
+	errorx.CodeInvalidLock:                codes.InvalidArgument,
+	errorx.CodeInvalidUserSpec:            codes.InvalidArgument,
+	errorx.CodeInvalidStatsPeriod:         codes.InvalidArgument,
+	errorx.CodePasswordChangeRequired:     codes.FailedPrecondition,
+	errorx.CodeInvalidPasswordChangeToken: codes.Unauthenticated,
+	errorx.CodeInvalidMerge:               codes.InvalidArgument,
+	errorx.CodeMfaAlreadyEnabled:          codes.AlreadyExists,
+	errorx.CodeMfaNotEnrolled:             codes.NotFound,
+	errorx.CodeInvalidMfaCode:             codes.Unauthenticated,
+	errorx.CodeUnsupportedMfaMethod:       codes.NotFound,
+	errorx.CodeMfaCodeRequired:            codes.Unauthenticated,
+	errorx.CodeRegistrationRejected:       codes.PermissionDenied,
+	errorx.CodeDependencyUnavailable:      codes.Unavailable,
+	errorx.CodeOverloaded:                 codes.Unavailable,
+	errorx.CodeTooManyFailedLogins:        codes.ResourceExhausted,
+}
+
+// toStatus converts an error returned by a use case into a gRPC status error.
+//
+// Typed domain errors keep their message and detail, an exceeded deadline and a cancellation are
+// reported as such, any other error is logged and reported as Internal without revealing details.
+func toStatus(ctx context.Context, err error) error {
+	if domainErr, ok := errorx.As(err); ok {
+		if code, ok := domainCodes[domainErr.Code]; ok {
+			return status.Error(code, domainErr.Error())
+		}
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, context.DeadlineExceeded.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, context.Canceled.Error())
+	}
+
+	logger.ErrorContext(ctx, "Unexpected error handling gRPC call", "error", err)
+	return status.Error(codes.Internal, "internal error")
+}
+
+// invalidArgument reports a malformed request.
+func invalidArgument(message string) error {
+	return status.Error(codes.InvalidArgument, message)
+}
@@ -0,0 +1,231 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"log/slog"
+	"strings"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/grpc/authv1"
+	"user-auth-hexagonal-architecture/internal/clientip"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/logging"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+	"user-auth-hexagonal-architecture/internal/requestid"
+	"user-auth-hexagonal-architecture/internal/tenant"
+)
+
+// principalKey is the context key under which the authenticated Principal is stored.
+type principalKey struct{}
+
+// Principal is the authenticated subject of a call.
+type Principal = domain.Principal
+
+// MethodAccess maps full gRPC method names onto the permission they require.
+// An empty permission marks a method as public. Methods that are not listed require an authenticated subject.
+type MethodAccess map[string]domain.Permission
+
+// DefaultMethodAccess declares the access rules of the AuthService.
+var DefaultMethodAccess = MethodAccess{
+	authv1.AuthService_RegisterUser_FullMethodName: "",
+	authv1.AuthService_Login_FullMethodName:        "",
+	authv1.AuthService_VerifyToken_FullMethodName:  "",
+	authv1.AuthService_GetUser_FullMethodName:      domain.PermissionUsersRead,
+}
+
+// RequestIDInterceptor assigns every call a request id, taken from the "x-request-id" metadata
+// if present, and returns it in the response header.
+func RequestIDInterceptor() grpc.UnaryServerInterceptor {
+	metadataKey := strings.ToLower(requestid.Header)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		id := ""
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(metadataKey); len(values) > 0 && len(values[0]) <= 128 {
+				id = values[0]
+			}
+		}
+		if id == "" {
+			id = requestid.New()
+		}
+
+		_ = grpc.SetHeader(ctx, metadata.Pairs(metadataKey, id))
+		return handler(requestid.WithID(ctx, id), req)
+	}
+}
+
+// ClientIPInterceptor resolves the address of the caller behind trusted proxies from the peer and
+// the forwarding metadata, and stores it in the context.
+func ClientIPInterceptor(resolver *clientip.Resolver) grpc.UnaryServerInterceptor {
+	metadataKey := strings.ToLower(resolver.Header())
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		remoteAddr := ""
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			remoteAddr = p.Addr.String()
+		}
+		var values []string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			values = md.Get(metadataKey)
+		}
+		return handler(clientip.WithIP(ctx, resolver.Resolve(remoteAddr, values)), req)
+	}
+}
+
+// LoggingInterceptor writes one structured log record per call with the method, status code,
+// latency, request id, client IP and, for authenticated calls, the user id. Request messages are never
+// logged, as they carry passwords and tokens.
+//
+// Parameters:
+//   - logger: The logger to write the records to
+//
+// Returns:
+//   - grpc.UnaryServerInterceptor: The interceptor
+func LoggingInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err)
+		level := slog.LevelInfo
+		switch code {
+		case codes.OK, codes.NotFound, codes.AlreadyExists, codes.InvalidArgument:
+		case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+			level = slog.LevelError
+		default:
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", info.FullMethod),
+			slog.String("code", code.String()),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("request_id", requestid.FromContext(ctx)),
+		}
+		if ip := clientip.FromContext(ctx); ip != "" {
+			attrs = append(attrs, slog.String("client_ip", ip))
+		}
+		if principal, ok := PrincipalFromContext(ctx); ok {
+			attrs = append(attrs, slog.String("user_id", principal.Subject))
+		}
+		logger.LogAttrs(ctx, level, "grpc call", attrs...)
+
+		return resp, err
+	}
+}
+
+// AuthInterceptor verifies the bearer token in the "authorization" metadata and enforces the access rules.
+//
+// Public methods are called without checks. Every other method requires a valid token whose
+// session is active, and methods with a permission additionally require one of the caller's
+// roles to grant it.
+//
+// Parameters:
+//   - verifyTokenPort: Port for verifying access tokens
+//   - access: The access rules of the methods
+//   - roleRegistry: Port for the permissions granted by each role
+//   - trackSessionPort: Port for checking and touching the session of the token
+//
+// Returns:
+//   - grpc.UnaryServerInterceptor: The interceptor
+func AuthInterceptor(verifyTokenPort usecases.VerifyTokenPort, access MethodAccess, roleRegistry usecases.RoleRegistryPort, trackSessionPort usecases.TrackSessionPort) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		permission, listed := access[info.FullMethod]
+		if listed && permission == "" {
+			return handler(ctx, req)
+		}
+
+		principal, err := authenticate(ctx, verifyTokenPort)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "authentication required")
+		}
+		if principal.SessionID != "" {
+			err := trackSessionPort.TouchSession(ctx, principal.SessionID)
+			switch {
+			case errors.Is(err, errorx.ErrSessionNotFound), errors.Is(err, errorx.ErrSessionNotActive):
+				return nil, status.Error(codes.Unauthenticated, "session ended")
+			case err != nil:
+				// an outage of the session store must not log everybody out
+				logger.ErrorContext(ctx, "Error checking session", "session_id", principal.SessionID, "error", err)
+			}
+		}
+		if permission != "" && !roleRegistry.RolePermissions().Grants(principal.Tenant, principal.Roles, permission) {
+			return nil, status.Error(codes.PermissionDenied, "insufficient permissions")
+		}
+
+		return handler(logging.WithUser(context.WithValue(ctx, principalKey{}, principal), principal.Subject), req)
+	}
+}
+
+// TenantInterceptor determines the tenant a call is made for and stores its id in the context.
+// It has to run after AuthInterceptor.
+//
+// Authenticated calls belong to the tenant of their access token; "x-tenant-id" metadata naming
+// another tenant is rejected. Public calls name their tenant in the "x-tenant-id" metadata and
+// belong to the default tenant without it.
+//
+// Returns:
+//   - grpc.UnaryServerInterceptor: The interceptor
+func TenantInterceptor() grpc.UnaryServerInterceptor {
+	metadataKey := strings.ToLower(tenant.Header)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		id := ""
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(metadataKey); len(values) > 0 {
+				id = values[0]
+			}
+		}
+		if id != "" && !domain.ValidTenantID(id) {
+			return nil, status.Error(codes.InvalidArgument, "invalid tenant id")
+		}
+
+		if principal, ok := PrincipalFromContext(ctx); ok {
+			if id != "" && id != principal.Tenant {
+				return nil, status.Error(codes.PermissionDenied, "token not issued for this tenant")
+			}
+			id = principal.Tenant
+		}
+		if id == "" {
+			id = domain.DefaultTenantID
+		}
+		return handler(tenant.WithID(ctx, id), req)
+	}
+}
+
+// PrincipalFromContext returns the authenticated principal of the call, if any.
+//
+// Parameters:
+//   - ctx: The call context
+//
+// Returns:
+//   - Principal: The authenticated principal
+//   - bool: false if the call is not authenticated
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
+
+// authenticate verifies the bearer token of the call.
+func authenticate(ctx context.Context, verifyTokenPort usecases.VerifyTokenPort) (Principal, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return Principal{}, fmt.Errorf("missing metadata")
+	}
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return Principal{}, fmt.Errorf("missing authorization")
+	}
+	scheme, token, ok := strings.Cut(values[0], " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return Principal{}, fmt.Errorf("unsupported authorization scheme")
+	}
+	return verifyTokenPort.VerifyToken(ctx, strings.TrimSpace(token))
+}
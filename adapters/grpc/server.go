@@ -0,0 +1,71 @@
+package grpcapi
+
+import (
+	"fmt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"log/slog"
+	"user-auth-hexagonal-architecture/adapters/grpc/authv1"
+	"user-auth-hexagonal-architecture/internal/clientip"
+	"user-auth-hexagonal-architecture/internal/logging"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// logger writes the log records of this package.
+var logger = logging.Component("grpc")
+
+// ServerConfig configures the gRPC server.
+type ServerConfig struct {
+	// CertFile and KeyFile enable TLS. Without them the server runs in plaintext, which is only
+	// acceptable inside a trusted network or behind a TLS terminating service mesh.
+	CertFile string
+	KeyFile  string
+	// Tokens verifies access tokens.
+	Tokens usecases.VerifyTokenPort
+	// RoleRegistry resolves the permissions granted by each role.
+	RoleRegistry usecases.RoleRegistryPort
+	// Sessions checks that the sessions of access tokens are still active.
+	Sessions usecases.TrackSessionPort
+	// ClientIPs resolves the address of callers behind trusted proxies, the peer address is used if nil.
+	ClientIPs *clientip.Resolver
+}
+
+// NewServer creates a gRPC server serving the AuthService.
+//
+// The interceptors run in the order request id, client IP, logging, authentication, tenant, so rejected calls are logged as well.
+//
+// Parameters:
+//   - authServer: The AuthService implementation
+//   - config: The server configuration
+//   - logger: The logger for call records
+//
+// Returns:
+//   - *grpc.Server: The server, to be started with Serve
+//   - error: An error if the TLS certificate cannot be loaded
+func NewServer(authServer *AuthServer, config ServerConfig, logger *slog.Logger) (*grpc.Server, error) {
+	clientIPs := config.ClientIPs
+	if clientIPs == nil {
+		clientIPs, _ = clientip.NewResolver(nil, clientip.HeaderXForwardedFor)
+	}
+	options := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(
+			RequestIDInterceptor(),
+			ClientIPInterceptor(clientIPs),
+			LoggingInterceptor(logger),
+			AuthInterceptor(config.Tokens, DefaultMethodAccess, config.RoleRegistry, config.Sessions),
+			TenantInterceptor(),
+		),
+	}
+
+	if config.CertFile != "" || config.KeyFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load gRPC TLS certificate: %w", err)
+		}
+		options = append(options, grpc.Creds(creds))
+	}
+
+	server := grpc.NewServer(options...)
+	authv1.RegisterAuthServiceServer(server, authServer)
+	return server, nil
+}
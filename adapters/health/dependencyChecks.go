@@ -0,0 +1,273 @@
+// Package health provides the dependency checks the readiness probe and the startup verification are built from.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/mongo"
+	"strings"
+	"sync/atomic"
+	"user-auth-hexagonal-architecture/adapters/notification"
+	"user-auth-hexagonal-architecture/adapters/resilience"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/security"
+)
+
+// minSigningKeyBytes is the minimum length of an HMAC signing key considered usable.
+const minSigningKeyBytes = 8
+
+// PingCheck checks a persistence backend by pinging it.
+type PingCheck struct {
+	name     string
+	backend  persistence.PersistenceHealthPort
+	optional bool
+}
+
+// NewPingCheck creates a check pinging a persistence backend.
+//
+// Parameters:
+//   - name: The name of the backend in readiness reports
+//   - backend: The backend to ping
+//   - optional: Whether the application can serve requests without the backend
+//
+// Returns:
+//   - *PingCheck: A pointer to the newly created PingCheck
+func NewPingCheck(name string, backend persistence.PersistenceHealthPort, optional bool) *PingCheck {
+	return &PingCheck{name, backend, optional}
+}
+
+// Name returns the name of the backend.
+func (pc *PingCheck) Name() string { return pc.name }
+
+// Optional reports whether the backend is optional.
+func (pc *PingCheck) Optional() bool { return pc.optional }
+
+// Check pings the backend.
+func (pc *PingCheck) Check(ctx context.Context) error {
+	return pc.backend.Ping(ctx)
+}
+
+// MongoCheck checks a MongoDB deployment by pinging it, usable before any persistence adapter exists.
+type MongoCheck struct {
+	client *mongo.Client
+}
+
+// NewMongoCheck creates a check pinging a MongoDB deployment.
+//
+// Parameters:
+//   - client: The MongoDB client
+//
+// Returns:
+//   - *MongoCheck: A pointer to the newly created MongoCheck
+func NewMongoCheck(client *mongo.Client) *MongoCheck {
+	return &MongoCheck{client}
+}
+
+// Name returns "mongodb".
+func (mc *MongoCheck) Name() string { return "mongodb" }
+
+// Optional returns false, no request can be served without MongoDB.
+func (mc *MongoCheck) Optional() bool { return false }
+
+// Check pings the deployment.
+func (mc *MongoCheck) Check(ctx context.Context) error {
+	return mc.client.Ping(ctx, nil)
+}
+
+// IndexCheck checks that the indexes a persistence backend relies on exist.
+type IndexCheck struct {
+	name    string
+	backend persistence.PersistenceHealthPort
+}
+
+// NewIndexCheck creates a check of the indexes of a persistence backend.
+//
+// Parameters:
+//   - name: The name of the check in reports
+//   - backend: The backend whose statistics list its expected indexes
+//
+// Returns:
+//   - *IndexCheck: A pointer to the newly created IndexCheck
+func NewIndexCheck(name string, backend persistence.PersistenceHealthPort) *IndexCheck {
+	return &IndexCheck{name, backend}
+}
+
+// Name returns the name of the check.
+func (ic *IndexCheck) Name() string { return ic.name }
+
+// Optional returns false, queries without their indexes scan whole collections and unique
+// constraints are not enforced.
+func (ic *IndexCheck) Optional() bool { return false }
+
+// Check fails if the statistics cannot be read or name missing indexes.
+func (ic *IndexCheck) Check(ctx context.Context) error {
+	stats, err := ic.backend.Stats(ctx)
+	if err != nil {
+		return err
+	}
+	var missing []string
+	for _, index := range stats.Indexes {
+		if !index.Present {
+			missing = append(missing, index.Collection+"."+index.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing indexes %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// SigningKeyCheck checks that a key for signing access tokens is configured.
+type SigningKeyCheck struct {
+	keys security.SigningKeyPort
+}
+
+// NewSigningKeyCheck creates a check of the token signing key.
+//
+// Parameters:
+//   - keys: The keys access tokens are signed with
+//
+// Returns:
+//   - *SigningKeyCheck: A pointer to the newly created SigningKeyCheck
+func NewSigningKeyCheck(keys security.SigningKeyPort) *SigningKeyCheck {
+	return &SigningKeyCheck{keys}
+}
+
+// Name returns "signing-key".
+func (sc *SigningKeyCheck) Name() string { return "signing-key" }
+
+// Optional returns false, no token can be issued without a key.
+func (sc *SigningKeyCheck) Optional() bool { return false }
+
+// Check fails if the current key is missing or too short to be used.
+func (sc *SigningKeyCheck) Check(context.Context) error {
+	if len(sc.keys.SigningKey()) < minSigningKeyBytes {
+		return errors.New("signing key is missing or too short")
+	}
+	return nil
+}
+
+// RedisCheck checks a Redis server by pinging it.
+type RedisCheck struct {
+	client   redis.UniversalClient
+	optional bool
+}
+
+// NewRedisCheck creates a check pinging a Redis server.
+//
+// Parameters:
+//   - client: The Redis client
+//   - optional: Whether the application can serve requests without Redis
+//
+// Returns:
+//   - *RedisCheck: A pointer to the newly created RedisCheck
+func NewRedisCheck(client redis.UniversalClient, optional bool) *RedisCheck {
+	return &RedisCheck{client, optional}
+}
+
+// Name returns "redis".
+func (rc *RedisCheck) Name() string { return "redis" }
+
+// Optional reports whether Redis is optional.
+func (rc *RedisCheck) Optional() bool { return rc.optional }
+
+// Check pings the Redis server.
+func (rc *RedisCheck) Check(ctx context.Context) error {
+	return rc.client.Ping(ctx).Err()
+}
+
+// BreakerCheck reports the circuit breaker of a dependency. It is optional, so an open circuit shows
+// in the reports without taking every instance out of rotation at once; the breaker answers the
+// requests needing the dependency in the meantime.
+type BreakerCheck struct {
+	breaker *resilience.CircuitBreaker
+}
+
+// NewBreakerCheck creates a check of a circuit breaker.
+//
+// Parameters:
+//   - breaker: The circuit breaker guarding the dependency
+//
+// Returns:
+//   - *BreakerCheck: A pointer to the newly created BreakerCheck
+func NewBreakerCheck(breaker *resilience.CircuitBreaker) *BreakerCheck {
+	return &BreakerCheck{breaker}
+}
+
+// Name returns the name of the dependency with the suffix "-circuit", e.g. "mongodb-circuit".
+func (bc *BreakerCheck) Name() string { return bc.breaker.Name() + "-circuit" }
+
+// Optional returns true.
+func (bc *BreakerCheck) Optional() bool { return true }
+
+// Check fails while the circuit is open.
+func (bc *BreakerCheck) Check(context.Context) error {
+	if state := bc.breaker.State(); state != resilience.StateClosed {
+		return fmt.Errorf("circuit is %s", state)
+	}
+	return nil
+}
+
+// SMTPCheck checks that the SMTP server accepts connections and the credentials. It is optional, as
+// emails failing for a while do not keep anyone from logging in.
+type SMTPCheck struct {
+	sender *notification.SMTPSender
+}
+
+// NewSMTPCheck creates a check of the SMTP server of an SMTPSender.
+//
+// Parameters:
+//   - sender: The sender of the emails
+//
+// Returns:
+//   - *SMTPCheck: A pointer to the newly created SMTPCheck
+func NewSMTPCheck(sender *notification.SMTPSender) *SMTPCheck {
+	return &SMTPCheck{sender}
+}
+
+// Name returns "smtp".
+func (sc *SMTPCheck) Name() string { return "smtp" }
+
+// Optional returns true.
+func (sc *SMTPCheck) Optional() bool { return true }
+
+// Check connects and authenticates at the SMTP server.
+func (sc *SMTPCheck) Check(ctx context.Context) error {
+	return sc.sender.Verify(ctx)
+}
+
+// DrainCheck makes the instance unready once it is shutting down, so the load balancer stops
+// sending it new requests while the requests in flight finish.
+type DrainCheck struct {
+	draining atomic.Bool
+}
+
+// NewDrainCheck creates a check that passes until Drain is called.
+//
+// Returns:
+//   - *DrainCheck: A pointer to the newly created DrainCheck
+func NewDrainCheck() *DrainCheck {
+	return &DrainCheck{}
+}
+
+// Drain marks the instance as shutting down.
+func (dc *DrainCheck) Drain() { dc.draining.Store(true) }
+
+// Draining reports whether the instance is shutting down.
+func (dc *DrainCheck) Draining() bool { return dc.draining.Load() }
+
+// Name returns "shutdown".
+func (dc *DrainCheck) Name() string { return "shutdown" }
+
+// Optional returns false, a draining instance must not receive new requests.
+func (dc *DrainCheck) Optional() bool { return false }
+
+// Check fails once the instance is shutting down.
+func (dc *DrainCheck) Check(context.Context) error {
+	if dc.Draining() {
+		return errors.New("shutting down")
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Package hooks provides ready-made interceptors behind the ports of the hooks package.
+package hooks
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// EmailDomainAllowlist only admits registrations with an email address of one of the allowed
+// domains, e.g. to restrict self-registration to the employees of a company. It implements the
+// RegistrationInterceptorPort interface from the hooks ports package.
+type EmailDomainAllowlist struct {
+	domains []string
+}
+
+// NewEmailDomainAllowlist creates a new EmailDomainAllowlist.
+//
+// Parameters:
+//   - domains: The allowed domains, e.g. "example.com"; subdomains are not allowed implicitly
+//
+// Returns:
+//   - EmailDomainAllowlist: The interceptor
+func NewEmailDomainAllowlist(domains []string) EmailDomainAllowlist {
+	allowed := make([]string, 0, len(domains))
+	for _, d := range domains {
+		if d = strings.ToLower(strings.TrimSpace(d)); d != "" {
+			allowed = append(allowed, d)
+		}
+	}
+	return EmailDomainAllowlist{allowed}
+}
+
+// Name returns "email-domain-allowlist".
+func (EmailDomainAllowlist) Name() string {
+	return "email-domain-allowlist"
+}
+
+// BeforeRegistration rejects registrations without an email address of an allowed domain.
+func (a EmailDomainAllowlist) BeforeRegistration(_ context.Context, user domain.User) error {
+	if user.Email.IsZero() {
+		return errorx.ErrRegistrationRejected.Detailf("an email address is required")
+	}
+	if !slices.Contains(a.domains, strings.ToLower(user.Email.Domain())) {
+		return errorx.ErrRegistrationRejected.Detailf("email addresses of %s are not admitted", user.Email.Domain())
+	}
+	return nil
+}
+
+// AfterRegistration does nothing.
+func (EmailDomainAllowlist) AfterRegistration(context.Context, domain.User) error {
+	return nil
+}
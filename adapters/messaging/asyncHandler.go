@@ -0,0 +1,137 @@
+package messaging
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	ports "user-auth-hexagonal-architecture/internal/ports/messaging"
+)
+
+// AsyncConfig configures an AsyncHandler.
+type AsyncConfig struct {
+	// Workers is the number of events handled concurrently.
+	Workers int
+	// QueueSize is the number of events that may wait for a worker. Events exceeding it are dropped.
+	QueueSize int
+}
+
+// DefaultAsyncConfig returns a configuration with 4 workers and room for 1000 waiting events.
+func DefaultAsyncConfig() AsyncConfig {
+	return AsyncConfig{Workers: 4, QueueSize: 1000}
+}
+
+// queuedEvent is an event waiting for a worker, together with the context it was dispatched in.
+type queuedEvent struct {
+	ctx   context.Context
+	event events.Event
+}
+
+// AsyncHandler hands the events to a wrapped handler on background workers, so slow handlers such as
+// notification emails and webhooks do not delay the use case that emitted the event.
+// It implements the EventHandler interface from the messaging ports package.
+//
+// The queue is bounded: events that do not fit are dropped and logged. The handler receives the
+// context of the use case without its cancellation, so the request id, tenant and client device
+// are kept while the request may already have finished.
+type AsyncHandler struct {
+	name    string
+	handler ports.EventHandler
+	config  AsyncConfig
+	queue   chan queuedEvent
+	done    chan struct{}
+	stop    sync.Once
+	wg      sync.WaitGroup
+	dropped atomic.Uint64
+}
+
+// NewAsyncHandler creates a new AsyncHandler. Call Start to begin handling.
+//
+// Parameters:
+//   - name: Identifies the handler in logs, e.g. "notifications"
+//   - handler: The handler the events are passed to
+//   - config: The number of workers and the size of the queue
+//
+// Returns:
+//   - *AsyncHandler: A pointer to the newly created AsyncHandler
+func NewAsyncHandler(name string, handler ports.EventHandler, config AsyncConfig) *AsyncHandler {
+	return &AsyncHandler{name: name, handler: handler, config: config, queue: make(chan queuedEvent, max(config.QueueSize, 1)), done: make(chan struct{})}
+}
+
+// Handle queues an event without blocking.
+//
+// Parameters:
+//   - ctx: A context.Context of the emitting use case; the handling outlives it
+//   - event: The event to handle
+//
+// Returns:
+//   - error: Always nil; events that do not fit into the queue are counted and logged by the workers
+func (a *AsyncHandler) Handle(ctx context.Context, event events.Event) error {
+	select {
+	case a.queue <- queuedEvent{context.WithoutCancel(ctx), event}:
+	default:
+		a.dropped.Add(1)
+	}
+	return nil
+}
+
+// Start launches the workers. They keep running until Stop is called.
+func (a *AsyncHandler) Start() {
+	for range max(a.config.Workers, 1) {
+		a.wg.Add(1)
+		go func() {
+			defer a.wg.Done()
+			for {
+				select {
+				case queued := <-a.queue:
+					a.handle(queued)
+				case <-a.done:
+					a.drain()
+					return
+				}
+			}
+		}()
+	}
+}
+
+// Stop lets the workers handle the events still queued and waits for them to return, or until
+// the context is done. Events dispatched afterwards are not handled anymore.
+//
+// Parameters:
+//   - ctx: A context.Context bounding the wait
+func (a *AsyncHandler) Stop(ctx context.Context) {
+	a.stop.Do(func() { close(a.done) })
+
+	stopped := make(chan struct{})
+	go func() {
+		a.wg.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		logger.WarnContext(ctx, "Queued events were not handled before the shutdown timeout", "handler", a.name, "count", len(a.queue))
+	}
+}
+
+// drain handles the queued events until the queue is empty.
+func (a *AsyncHandler) drain() {
+	for {
+		select {
+		case queued := <-a.queue:
+			a.handle(queued)
+		default:
+			return
+		}
+	}
+}
+
+// handle passes a queued event to the wrapped handler and logs failures and dropped events.
+func (a *AsyncHandler) handle(queued queuedEvent) {
+	if dropped := a.dropped.Swap(0); dropped > 0 {
+		logger.WarnContext(queued.ctx, "Event queue full, dropped events", "handler", a.name, "count", dropped)
+	}
+	if err := a.handler.Handle(queued.ctx, queued.event); err != nil {
+		logger.ErrorContext(queued.ctx, "Error handling event", "handler", a.name, "event", queued.event.Name(), "error", err)
+	}
+}
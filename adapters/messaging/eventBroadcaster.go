@@ -0,0 +1,85 @@
+package messaging
+
+import (
+	"context"
+	"sync"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/tenant"
+)
+
+// EventBroadcaster fans dispatched events out to any number of live subscribers, e.g. open event streams.
+// It implements the EventHandler and EventStreamPort interfaces from the messaging ports package.
+//
+// Delivery never blocks the dispatching use case: a subscriber whose buffer is full misses the event.
+// Subscribers only receive the events of their tenant, taken from the context the event was
+// dispatched with. Events of background jobs carry no tenant and go to the default tenant, which
+// operates the installation.
+type EventBroadcaster struct {
+	mu          sync.RWMutex
+	subscribers map[chan events.Event]string
+}
+
+// NewEventBroadcaster creates a new EventBroadcaster without subscribers.
+//
+// Returns:
+//   - *EventBroadcaster: A pointer to the newly created EventBroadcaster
+func NewEventBroadcaster() *EventBroadcaster {
+	return &EventBroadcaster{subscribers: map[chan events.Event]string{}}
+}
+
+// Handle passes an event on to the current subscribers of the tenant it was dispatched in.
+//
+// Parameters:
+//   - ctx: A context.Context of the emitting use case, naming the tenant of the event
+//   - event: The event to broadcast
+//
+// Returns:
+//   - error: Always nil
+func (b *EventBroadcaster) Handle(ctx context.Context, event events.Event) error {
+	eventTenant := tenant.FromContext(ctx)
+	if eventTenant == "" {
+		eventTenant = domain.DefaultTenantID
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for subscriber, subscriberTenant := range b.subscribers {
+		if subscriberTenant != eventTenant {
+			continue
+		}
+		select {
+		case subscriber <- event:
+		default:
+		}
+	}
+	return nil
+}
+
+// SubscribeEvents registers a new subscriber to the events of a tenant.
+//
+// Parameters:
+//   - tenantID: The tenant whose events the subscriber receives
+//   - buffer: The number of events buffered for the subscriber
+//
+// Returns:
+//   - <-chan events.Event: The channel receiving the events
+//   - func(): Ends the subscription and closes the channel; safe to call more than once
+func (b *EventBroadcaster) SubscribeEvents(tenantID string, buffer int) (<-chan events.Event, func()) {
+	subscriber := make(chan events.Event, buffer)
+
+	b.mu.Lock()
+	b.subscribers[subscriber] = tenantID
+	b.mu.Unlock()
+
+	var once sync.Once
+	return subscriber, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, subscriber)
+			b.mu.Unlock()
+			close(subscriber)
+		})
+	}
+}
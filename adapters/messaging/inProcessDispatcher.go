@@ -0,0 +1,59 @@
+// Package messaging provides adapters for distributing domain events.
+package messaging
+
+import (
+	"context"
+	"sync"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/logging"
+	ports "user-auth-hexagonal-architecture/internal/ports/messaging"
+)
+
+// logger writes the log records of this package.
+var logger = logging.Component("messaging")
+
+// InProcessDispatcher delivers domain events synchronously to handlers registered in the same process.
+// Slow handlers are wrapped in an AsyncHandler, so they do not delay the use case.
+// It implements the EventDispatcherPort interface from the messaging ports package.
+type InProcessDispatcher struct {
+	mu       sync.RWMutex
+	handlers []ports.EventHandler
+}
+
+// NewInProcessDispatcher creates a new InProcessDispatcher without handlers.
+//
+// Returns:
+//   - *InProcessDispatcher: A pointer to the newly created InProcessDispatcher
+func NewInProcessDispatcher() *InProcessDispatcher {
+	return &InProcessDispatcher{}
+}
+
+// Subscribe registers a handler that receives every dispatched event.
+//
+// Parameters:
+//   - handler: The handler to register
+func (d *InProcessDispatcher) Subscribe(handler ports.EventHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers = append(d.handlers, handler)
+}
+
+// Dispatch delivers an event to all registered handlers in registration order.
+//
+// A failing handler is logged and does not prevent delivery to the remaining handlers,
+// nor does it fail the use case that emitted the event.
+//
+// Parameters:
+//   - ctx: A context.Context passed on to the handlers
+//   - event: The event to deliver
+func (d *InProcessDispatcher) Dispatch(ctx context.Context, event events.Event) {
+	d.mu.RLock()
+	handlers := d.handlers
+	d.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler.Handle(ctx, event); err != nil {
+			logger.ErrorContext(ctx, "Error handling event", "event", event.Name(), "error", err)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// exemplar returns the exemplar labels linking a measurement to the trace of the context, nil if
+// the context belongs to no recorded trace.
+func exemplar(ctx context.Context) prometheus.Labels {
+	span := trace.SpanContextFromContext(ctx)
+	if !span.IsValid() || !span.IsSampled() {
+		return nil
+	}
+	return prometheus.Labels{"trace_id": span.TraceID().String()}
+}
+
+// incWithExemplar increments a counter, linked to the trace of the context if there is one.
+func incWithExemplar(ctx context.Context, counter prometheus.Counter) {
+	adder, ok := counter.(prometheus.ExemplarAdder)
+	labels := exemplar(ctx)
+	if !ok || labels == nil {
+		counter.Inc()
+		return
+	}
+	adder.AddWithExemplar(1, labels)
+}
+
+// observeWithExemplar records an observation, linked to the trace of the context if there is one.
+func observeWithExemplar(ctx context.Context, observer prometheus.Observer, value float64) {
+	exemplarObserver, ok := observer.(prometheus.ExemplarObserver)
+	labels := exemplar(ctx)
+	if !ok || labels == nil {
+		observer.Observe(value)
+		return
+	}
+	exemplarObserver.ObserveWithExemplar(value, labels)
+}
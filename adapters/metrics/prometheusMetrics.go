@@ -0,0 +1,290 @@
+// Package metrics instruments the application with Prometheus metrics. It wraps HTTP handlers,
+// use case ports and the MongoDB client, and implements the MetricsPort for measurements taken
+// inside the core layer.
+package metrics
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.mongodb.org/mongo-driver/event"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// namespace prefixes the names of all metrics.
+const namespace = "auth"
+
+// alertWindows are the sliding windows of the ratios meant for alerting, labelled by their length.
+var alertWindows = []struct {
+	label  string
+	period time.Duration
+}{{"1m", time.Minute}, {"5m", 5 * time.Minute}, {"15m", 15 * time.Minute}}
+
+// PrometheusMetrics holds the registry and collectors of the application.
+// It implements the MetricsPort interface from the telemetry package.
+type PrometheusMetrics struct {
+	registry        *prometheus.Registry
+	httpRequests    *prometheus.CounterVec
+	httpDuration    *prometheus.HistogramVec
+	logins          *prometheus.CounterVec
+	registrations   *prometheus.CounterVec
+	tokensIssued    prometheus.Counter
+	passwordHashing *prometheus.HistogramVec
+	mongoOperations *prometheus.HistogramVec
+	verifications   *prometheus.CounterVec
+	lockouts        prometheus.Counter
+	// loginWindow, verificationWindow and lockoutWindow remember the recent outcomes behind the
+	// gauges meant for alerting.
+	loginWindow        *slidingWindow
+	verificationWindow *slidingWindow
+	lockoutWindow      *slidingWindow
+}
+
+// NewPrometheusMetrics creates and registers all collectors, including the Go runtime and process collectors.
+//
+// Returns:
+//   - *PrometheusMetrics: A pointer to the newly created PrometheusMetrics
+func NewPrometheusMetrics() *PrometheusMetrics {
+	m := &PrometheusMetrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Name: "http_requests_total",
+			Help: "HTTP requests by route, method and status code.",
+		}, []string{"route", "method", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Name: "http_request_duration_seconds",
+			Help:    "Duration of HTTP requests by route and method.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"route", "method"}),
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Name: "logins_total",
+			Help: "Login attempts by outcome.",
+		}, []string{"outcome"}),
+		registrations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Name: "registrations_total",
+			Help: "Registration attempts by outcome.",
+		}, []string{"outcome"}),
+		tokensIssued: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace, Name: "tokens_issued_total",
+			Help: "Access tokens issued.",
+		}),
+		passwordHashing: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Name: "password_hashing_duration_seconds",
+			Help:    "Duration of password hashing and verification.",
+			Buckets: []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5},
+		}, []string{"operation"}),
+		mongoOperations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Name: "mongo_operation_duration_seconds",
+			Help:    "Duration of MongoDB commands by command name and outcome.",
+			Buckets: []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1},
+		}, []string{"command", "outcome"}),
+		verifications: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Name: "token_verifications_total",
+			Help: "Access token verifications by outcome.",
+		}, []string{"outcome"}),
+		lockouts: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace, Name: "lockouts_total",
+			Help: "Usernames locked out after too many failed logins.",
+		}),
+		loginWindow:        newSlidingWindow(alertWindows[len(alertWindows)-1].period),
+		verificationWindow: newSlidingWindow(alertWindows[len(alertWindows)-1].period),
+		lockoutWindow:      newSlidingWindow(time.Minute),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests, m.httpDuration, m.logins, m.registrations, m.tokensIssued, m.passwordHashing, m.mongoOperations,
+		m.verifications, m.lockouts,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace, Name: "lockouts_per_minute",
+			Help: "Usernames locked out within the last minute.",
+		}, func() float64 {
+			total, _ := m.lockoutWindow.counts(time.Minute)
+			return float64(total)
+		}),
+	)
+	m.registerRatios("login_failure_ratio", "Share of rejected logins among all logins", m.loginWindow)
+	m.registerRatios("token_verification_error_ratio", "Share of rejected access tokens among all verified tokens", m.verificationWindow)
+	return m
+}
+
+// registerRatios exposes the failure ratio of a sliding window with a series per alert window.
+func (m *PrometheusMetrics) registerRatios(name string, help string, window *slidingWindow) {
+	for _, alertWindow := range alertWindows {
+		period := alertWindow.period
+		m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace, Name: name,
+			Help:        help + " within the sliding window.",
+			ConstLabels: prometheus.Labels{"window": alertWindow.label},
+		}, func() float64 { return window.failureRatio(period) }))
+	}
+}
+
+// Handler returns the HTTP handler exposing the metrics in the Prometheus text format, or in the
+// OpenMetrics format including the exemplars if the scraper asks for it.
+func (m *PrometheusMetrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry, EnableOpenMetrics: true})
+}
+
+// ObservePasswordHashing records the duration of a password hashing or verification.
+//
+// Parameters:
+//   - operation: telemetry.PasswordHash or telemetry.PasswordVerify
+//   - duration: The time the operation took
+func (m *PrometheusMetrics) ObservePasswordHashing(operation string, duration time.Duration) {
+	m.passwordHashing.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+// CountLockout records that a username was locked out after too many failed logins.
+func (m *PrometheusMetrics) CountLockout() {
+	m.lockouts.Inc()
+	m.lockoutWindow.observe(false)
+}
+
+// SigningKeyClock reports when the current signing key became the signing key.
+type SigningKeyClock interface {
+	ActivatedAt() time.Time
+}
+
+// RegisterSigningKeyAge exposes the age of the signing key, so keys that are not rotated as
+// required raise an alert.
+//
+// Parameters:
+//   - keys: The key ring holding the signing key
+func (m *PrometheusMetrics) RegisterSigningKeyAge(keys SigningKeyClock) {
+	m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace, Name: "signing_key_age_seconds",
+		Help: "Time since the current signing key was loaded or rotated to.",
+	}, func() float64 { return time.Since(keys.ActivatedAt()).Seconds() }))
+}
+
+// HashingQueue reports the load of a concurrency-limited password hasher.
+type HashingQueue interface {
+	Running() int
+	Waiting() int
+	Rejected() uint64
+}
+
+// RegisterHashingQueue exposes the password operations running, queued and shed, so saturation
+// shows before logins are rejected.
+//
+// Parameters:
+//   - queue: The limited password hasher
+func (m *PrometheusMetrics) RegisterHashingQueue(queue HashingQueue) {
+	m.registry.MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace, Name: "password_hashing_running",
+			Help: "Password hashing and verification operations running.",
+		}, func() float64 { return float64(queue.Running()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace, Name: "password_hashing_queue_depth",
+			Help: "Password hashing and verification operations waiting for a slot.",
+		}, func() float64 { return float64(queue.Waiting()) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace, Name: "password_hashing_rejected_total",
+			Help: "Password hashing and verification operations shed because of overload.",
+		}, func() float64 { return float64(queue.Rejected()) }),
+	)
+}
+
+// OutboxBacklog reports the domain events waiting to be published to a message broker.
+type OutboxBacklog interface {
+	CountPendingOutboxMessages(ctx context.Context) (int64, error)
+}
+
+// RegisterOutboxBacklog exposes the number of events waiting in the outbox, which grows while the
+// message broker is unavailable. The count is read from the database at scrape time.
+//
+// Parameters:
+//   - backlog: The outbox store
+func (m *PrometheusMetrics) RegisterOutboxBacklog(backlog OutboxBacklog) {
+	m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace, Name: "outbox_pending_messages",
+		Help: "Domain events recorded in the outbox and not yet published.",
+	}, func() float64 {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		count, err := backlog.CountPendingOutboxMessages(ctx)
+		if err != nil {
+			return math.NaN()
+		}
+		return float64(count)
+	}))
+}
+
+// MongoMonitor returns a command monitor recording the duration of every MongoDB command.
+// It has to be set on the client options before connecting.
+//
+// Returns:
+//   - *event.CommandMonitor: The monitor
+func (m *PrometheusMetrics) MongoMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			m.mongoOperations.WithLabelValues(e.CommandName, "success").Observe(e.Duration.Seconds())
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			m.mongoOperations.WithLabelValues(e.CommandName, "failure").Observe(e.Duration.Seconds())
+		},
+	}
+}
+
+// InstrumentHTTP returns middleware counting requests and measuring their duration.
+//
+// Requests are labelled with the route pattern they are dispatched to by mux, e.g. "POST /user/login",
+// rather than the raw path, which keeps the number of series bounded. Requests no route matches
+// are labelled "unmatched".
+//
+// Parameters:
+//   - mux: The mux the wrapped handler dispatches to
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware
+func (m *PrometheusMetrics) InstrumentHTTP(mux *http.ServeMux) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := "unmatched"
+			if _, pattern := mux.Handler(r); pattern != "" {
+				route = pattern
+			}
+
+			start := time.Now()
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			m.httpRequests.WithLabelValues(route, r.Method, strconv.Itoa(recorder.status)).Inc()
+			observeWithExemplar(r.Context(), m.httpDuration.WithLabelValues(route, r.Method), time.Since(start).Seconds())
+		})
+	}
+}
+
+// statusRecorder records the status code of a response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+// WriteHeader records the status code before passing it on.
+func (sr *statusRecorder) WriteHeader(status int) {
+	if !sr.wroteHeader {
+		sr.status = status
+		sr.wroteHeader = true
+	}
+	sr.ResponseWriter.WriteHeader(status)
+}
+
+// Write marks the header as sent, which implies 200 OK if no status was set.
+func (sr *statusRecorder) Write(b []byte) (int, error) {
+	sr.wroteHeader = true
+	return sr.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the wrapped writer to http.ResponseController.
+func (sr *statusRecorder) Unwrap() http.ResponseWriter {
+	return sr.ResponseWriter
+}
@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+// windowBucket counts the observations of a single second.
+type windowBucket struct {
+	second int64
+	total  uint64
+	failed uint64
+}
+
+// slidingWindow counts observations and failures per second over a bounded period, so ratios and
+// rates over the last minutes can be read at scrape time without a query language.
+type slidingWindow struct {
+	mu      sync.Mutex
+	buckets []windowBucket
+}
+
+// newSlidingWindow creates a window remembering the given period, rounded up to whole seconds.
+func newSlidingWindow(period time.Duration) *slidingWindow {
+	return &slidingWindow{buckets: make([]windowBucket, max(int((period+time.Second-1)/time.Second), 1))}
+}
+
+// observe counts an observation at the current time.
+func (sw *slidingWindow) observe(failed bool) {
+	now := time.Now().Unix()
+
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	bucket := &sw.buckets[now%int64(len(sw.buckets))]
+	if bucket.second != now {
+		*bucket = windowBucket{second: now}
+	}
+	bucket.total++
+	if failed {
+		bucket.failed++
+	}
+}
+
+// counts returns the observations and failures of the last period, bounded by the period of the window.
+func (sw *slidingWindow) counts(period time.Duration) (uint64, uint64) {
+	now := time.Now().Unix()
+	oldest := now - int64(period/time.Second)
+
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	var total, failed uint64
+	for _, bucket := range sw.buckets {
+		if bucket.second > oldest && bucket.second <= now {
+			total += bucket.total
+			failed += bucket.failed
+		}
+	}
+	return total, failed
+}
+
+// failureRatio returns the share of failures among the observations of the last period, zero
+// without observations.
+func (sw *slidingWindow) failureRatio(period time.Duration) float64 {
+	total, failed := sw.counts(period)
+	if total == 0 {
+		return 0
+	}
+	return float64(failed) / float64(total)
+}
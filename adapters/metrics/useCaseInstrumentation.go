@@ -0,0 +1,143 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// instrumentedLoadUser wraps a LoadUserPort and counts logins and issued tokens.
+type instrumentedLoadUser struct {
+	next    usecases.LoadUserPort
+	metrics *PrometheusMetrics
+}
+
+// InstrumentLoadUser wraps a LoadUserPort so every login attempt is counted by outcome, linked to
+// its trace, and every successful login as an issued token.
+//
+// Parameters:
+//   - next: The port to wrap
+//
+// Returns:
+//   - usecases.LoadUserPort: The instrumented port
+func (m *PrometheusMetrics) InstrumentLoadUser(next usecases.LoadUserPort) usecases.LoadUserPort {
+	return &instrumentedLoadUser{next, m}
+}
+
+// LoadUser delegates to the wrapped port and records the outcome.
+func (il *instrumentedLoadUser) LoadUser(ctx context.Context, username string, password string, mfaCode string, method domain.LoginMethod, consents []domain.ConsentRef) (domain.AuthTokens, error) {
+	tokens, err := il.next.LoadUser(ctx, username, password, mfaCode, method, consents)
+	result := outcome(err)
+	incWithExemplar(ctx, il.metrics.logins.WithLabelValues(result))
+	il.metrics.loginWindow.observe(rejected(result))
+	if err == nil {
+		il.metrics.tokensIssued.Inc()
+	}
+	return tokens, err
+}
+
+// instrumentedVerifyToken wraps a VerifyTokenPort and counts verifications.
+type instrumentedVerifyToken struct {
+	next    usecases.VerifyTokenPort
+	metrics *PrometheusMetrics
+}
+
+// InstrumentVerifyToken wraps a VerifyTokenPort so every token verification is counted by outcome.
+//
+// Parameters:
+//   - next: The port to wrap
+//
+// Returns:
+//   - usecases.VerifyTokenPort: The instrumented port
+func (m *PrometheusMetrics) InstrumentVerifyToken(next usecases.VerifyTokenPort) usecases.VerifyTokenPort {
+	return &instrumentedVerifyToken{next, m}
+}
+
+// VerifyToken delegates to the wrapped port and records the outcome.
+func (iv *instrumentedVerifyToken) VerifyToken(ctx context.Context, token string) (domain.Principal, error) {
+	principal, err := iv.next.VerifyToken(ctx, token)
+	incWithExemplar(ctx, iv.metrics.verifications.WithLabelValues(outcome(err)))
+	iv.metrics.verificationWindow.observe(err != nil)
+	return principal, err
+}
+
+// instrumentedRegisterUser wraps a RegisterUserPort and counts registrations.
+type instrumentedRegisterUser struct {
+	next    usecases.RegisterUserPort
+	metrics *PrometheusMetrics
+}
+
+// InstrumentRegisterUser wraps a RegisterUserPort so every registration attempt is counted by outcome.
+//
+// Parameters:
+//   - next: The port to wrap
+//
+// Returns:
+//   - usecases.RegisterUserPort: The instrumented port
+func (m *PrometheusMetrics) InstrumentRegisterUser(next usecases.RegisterUserPort) usecases.RegisterUserPort {
+	return &instrumentedRegisterUser{next, m}
+}
+
+// RegisterUser delegates to the wrapped port and records the outcome.
+func (ir *instrumentedRegisterUser) RegisterUser(ctx context.Context, username string, password string, email string, consents []domain.ConsentRef) (string, error) {
+	userID, err := ir.next.RegisterUser(ctx, username, password, email, consents)
+	incWithExemplar(ctx, ir.metrics.registrations.WithLabelValues(outcome(err)))
+	return userID, err
+}
+
+// outcome maps the result of a use case onto a bounded set of label values.
+func outcome(err error) string {
+	switch {
+	case err == nil:
+		return "success"
+	case errors.Is(err, errorx.ErrInvalidCredentials):
+		return "invalid_credentials"
+	case errors.Is(err, errorx.ErrAccountDisabled):
+		return "account_disabled"
+	case errors.Is(err, errorx.ErrAccountLocked):
+		return "account_locked"
+	case errors.Is(err, errorx.ErrTooManyFailedLogins):
+		return "locked_out"
+	case errors.Is(err, errorx.ErrAccountPending):
+		return "account_pending"
+	case errors.Is(err, errorx.ErrMfaRequired), errors.Is(err, errorx.ErrLoginMethodNotAllowed):
+		return "tenant_policy"
+	case errors.Is(err, errorx.ErrMfaCodeRequired), errors.Is(err, errorx.ErrInvalidMfaCode):
+		return "mfa_failed"
+	case errors.Is(err, errorx.ErrSessionLimitReached):
+		return "session_limit"
+	case errors.Is(err, errorx.ErrLoginBlocked):
+		return "risk_blocked"
+	case errors.Is(err, errorx.ErrConsentRequired), errors.Is(err, errorx.ErrInvalidConsent):
+		return "consent_required"
+	case errors.Is(err, errorx.ErrUsernameTaken):
+		return "username_taken"
+	case errors.Is(err, errorx.ErrEmailTaken):
+		return "email_taken"
+	case errors.Is(err, errorx.ErrInvalidUsername), errors.Is(err, errorx.ErrInvalidEmail), errors.Is(err, errorx.ErrWeakPassword):
+		return "invalid_input"
+	case errors.Is(err, errorx.ErrRegistrationRejected):
+		return "registration_rejected"
+	case errors.Is(err, errorx.ErrDependencyUnavailable):
+		return "dependency_unavailable"
+	case errors.Is(err, errorx.ErrOverloaded):
+		return "overloaded"
+	case errors.Is(err, errorx.ErrInvalidToken):
+		return "invalid_token"
+	default:
+		return "error"
+	}
+}
+
+// rejected reports whether an outcome is a login rejected for the credentials or the account, as
+// opposed to a success or a failure of the service, which have alerts of their own.
+func rejected(outcome string) bool {
+	switch outcome {
+	case "success", "error", "dependency_unavailable", "overloaded":
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,87 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/messaging"
+)
+
+// EmailChannel sends notifications as emails rendered from the email templates. The emails are
+// handed to the first provider, and to the next one whenever a provider fails, so a second provider
+// takes over while the first one is down.
+// It implements the NotificationChannelPort interface from the messaging ports package.
+type EmailChannel struct {
+	templates *EmailTemplates
+	from      string
+	senders   []messaging.EmailSenderPort
+}
+
+// NewEmailChannel creates a new EmailChannel.
+//
+// Parameters:
+//   - templates: The templates the emails are rendered with
+//   - from: The sender of the emails, e.g. "Example <no-reply@example.com>"
+//   - senders: The providers in the order they are tried
+//
+// Returns:
+//   - *EmailChannel: A pointer to the newly created EmailChannel
+//   - error: An error if the sender is malformed or no provider is given
+func NewEmailChannel(templates *EmailTemplates, from string, senders ...messaging.EmailSenderPort) (*EmailChannel, error) {
+	if _, err := mail.ParseAddress(from); err != nil {
+		return nil, fmt.Errorf("invalid sender %q: %w", from, err)
+	}
+	if len(senders) == 0 {
+		return nil, errors.New("no email provider")
+	}
+	return &EmailChannel{templates, from, senders}, nil
+}
+
+// Channel returns domain.NotificationChannelEmail.
+func (ec *EmailChannel) Channel() domain.NotificationChannel {
+	return domain.NotificationChannelEmail
+}
+
+// Send renders the email of a notification and sends it to the address of the user.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - notification: The notification to send
+//
+// Returns:
+//   - error: An error if the email cannot be rendered or every provider failed
+func (ec *EmailChannel) Send(ctx context.Context, notification domain.Notification) error {
+	email, err := ec.templates.Render(notification)
+	if err != nil {
+		return err
+	}
+	message := domain.EmailMessage{
+		From:    ec.from,
+		To:      notification.Email,
+		ToName:  notification.DisplayName,
+		Subject: email.Subject,
+		Text:    email.Text,
+		HTML:    email.HTML,
+	}
+
+	var errs []error
+	for i, sender := range ec.senders {
+		err := sender.SendEmail(ctx, message)
+		if err == nil {
+			if i > 0 {
+				logger.InfoContext(ctx, "Sent email through fallback provider", "provider", sender.Provider(), "topic", notification.Topic)
+			}
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", sender.Provider(), err))
+		if ctx.Err() != nil {
+			break
+		}
+		if i < len(ec.senders)-1 {
+			logger.WarnContext(ctx, "Email provider failed, trying the next one", "provider", sender.Provider(), "error", err)
+		}
+	}
+	return errors.Join(errs...)
+}
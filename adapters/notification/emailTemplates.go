@@ -0,0 +1,179 @@
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	texttemplate "text/template"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// defaultLocale is the locale of notifications for users whose locale has no templates.
+const defaultLocale = "en"
+
+//go:embed templates
+var embeddedTemplates embed.FS
+
+// templateFuncs are the functions available in the email templates.
+var templateFuncs = map[string]any{
+	// date formats a time for display, e.g. "2026-10-17 12:00 UTC".
+	"date": func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04 MST") },
+}
+
+// Email is a rendered notification email.
+type Email struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// emailData is the data the email templates are executed with.
+type emailData struct {
+	// Name is the display name of the user, or the username if there is none.
+	Name     string
+	Username string
+	// Details are the topic specific values of the notification, e.g. .Details.userAgent.
+	Details map[string]string
+	At      time.Time
+	// Product is the name of the service the user has an account with.
+	Product string
+}
+
+// EmailTemplates renders notification emails from per-locale templates, a plain text and an HTML
+// template per topic, e.g. "de/account_locked.txt" and "de/account_locked.html". The text template
+// defines the subject in a "subject" block. Templates for English and German are built in; a
+// directory with the same layout overrides single templates or adds locales.
+type EmailTemplates struct {
+	product string
+	text    map[string]*texttemplate.Template
+	html    map[string]*htmltemplate.Template
+}
+
+// NewEmailTemplates loads the built-in templates and the overrides.
+//
+// Parameters:
+//   - product: The name of the service, available to the templates as .Product
+//   - dir: The directory overriding the built-in templates, none if empty
+//
+// Returns:
+//   - *EmailTemplates: A pointer to the newly created EmailTemplates
+//   - error: An error if a template cannot be read or parsed, or is named after an unknown topic
+func NewEmailTemplates(product string, dir string) (*EmailTemplates, error) {
+	et := &EmailTemplates{product, map[string]*texttemplate.Template{}, map[string]*htmltemplate.Template{}}
+	builtIn, err := fs.Sub(embeddedTemplates, "templates")
+	if err != nil {
+		return nil, err
+	}
+	if err := et.load(builtIn); err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		if err := et.load(os.DirFS(dir)); err != nil {
+			return nil, fmt.Errorf("failed to load email templates from %s: %w", dir, err)
+		}
+	}
+	return et, nil
+}
+
+// load parses the templates of every locale directory of fsys, replacing those loaded before.
+func (et *EmailTemplates) load(fsys fs.FS) error {
+	files, err := fs.Glob(fsys, "*/*")
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		name := path.Base(file)
+		topic, extension, _ := strings.Cut(name, ".")
+		if !domain.NotificationTopic(topic).Valid() || (extension != "txt" && extension != "html") {
+			return fmt.Errorf("%s is not named after a notification topic, e.g. registration.txt or registration.html", file)
+		}
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+		key := strings.ToLower(path.Dir(file)) + "/" + topic
+		if extension == "txt" {
+			tmpl, err := texttemplate.New(name).Funcs(templateFuncs).Option("missingkey=zero").Parse(string(data))
+			if err != nil {
+				return err
+			}
+			if tmpl.Lookup("subject") == nil {
+				return fmt.Errorf("%s defines no subject, add {{define \"subject\"}}...{{end}}", file)
+			}
+			et.text[key] = tmpl
+		} else {
+			tmpl, err := htmltemplate.New(name).Funcs(templateFuncs).Option("missingkey=zero").Parse(string(data))
+			if err != nil {
+				return err
+			}
+			et.html[key] = tmpl
+		}
+	}
+	return nil
+}
+
+// Render renders the email of a notification in the locale of the user. Templates missing for the
+// locale, e.g. "de-CH", are taken from its language, "de", and then from English.
+//
+// Parameters:
+//   - notification: The notification to render
+//
+// Returns:
+//   - Email: The subject and the bodies of the email
+//   - error: An error if a template fails
+func (et *EmailTemplates) Render(notification domain.Notification) (Email, error) {
+	name := notification.DisplayName
+	if name == "" {
+		name = notification.Username
+	}
+	data := emailData{name, notification.Username, notification.Details, notification.At, et.product}
+	keys := templateKeys(notification.Locale, notification.Topic)
+
+	text := lookup(et.text, keys)
+	html := lookup(et.html, keys)
+	if text == nil || html == nil {
+		return Email{}, fmt.Errorf("no email templates for topic %s", notification.Topic)
+	}
+	var subject, textBody, htmlBody bytes.Buffer
+	if err := text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Email{}, fmt.Errorf("failed to render subject: %w", err)
+	}
+	if err := text.Execute(&textBody, data); err != nil {
+		return Email{}, fmt.Errorf("failed to render text body: %w", err)
+	}
+	if err := html.Execute(&htmlBody, data); err != nil {
+		return Email{}, fmt.Errorf("failed to render HTML body: %w", err)
+	}
+	// a line break would end the header the subject is sent in
+	cleanSubject := strings.Join(strings.Fields(subject.String()), " ")
+	return Email{cleanSubject, strings.TrimSpace(textBody.String()) + "\n", htmlBody.String()}, nil
+}
+
+// templateKeys returns the keys of the templates of a topic in the order they are looked up.
+func templateKeys(locale string, topic domain.NotificationTopic) []string {
+	locale = strings.ToLower(locale)
+	language, _, _ := strings.Cut(locale, "-")
+	var keys []string
+	for _, candidate := range []string{locale, language, defaultLocale} {
+		if candidate != "" {
+			keys = append(keys, candidate+"/"+string(topic))
+		}
+	}
+	return keys
+}
+
+// lookup returns the first template found under the keys.
+func lookup[T any](templates map[string]*T, keys []string) *T {
+	for _, key := range keys {
+		if tmpl, ok := templates[key]; ok {
+			return tmpl
+		}
+	}
+	return nil
+}
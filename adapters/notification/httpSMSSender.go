@@ -0,0 +1,113 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// DefaultSMSGatewayBody is the request body sent to SMS gateways unless configured otherwise.
+const DefaultSMSGatewayBody = `{"from":{{json .From}},"to":{{json .To}},"text":{{json .Body}}}`
+
+// SMSGatewayConfig configures the HTTP SMS gateway sender.
+type SMSGatewayConfig struct {
+	// URL is the endpoint the SMS are posted to.
+	URL string
+	// Token is sent as bearer token in the Authorization header, none if empty.
+	Token string
+	// Body is the template of the request body, rendered with the From, To and Body of the SMS. The
+	// functions json and query encode a value as JSON string or for a form.
+	Body string
+	// ContentType is the media type of the request body, e.g. "application/json".
+	ContentType string
+	// Timeout bounds a request.
+	Timeout time.Duration
+}
+
+// HTTPSMSSender sends SMS through a generic HTTP gateway, posting a body rendered from a template,
+// so gateways without a dedicated adapter can be used. Every 2xx status counts as accepted.
+// It implements the SMSSenderPort interface from the messaging ports package.
+type HTTPSMSSender struct {
+	client *http.Client
+	config SMSGatewayConfig
+	body   *template.Template
+}
+
+// NewHTTPSMSSender creates a new HTTPSMSSender.
+//
+// Parameters:
+//   - client: The HTTP client for the requests to the gateway
+//   - config: The endpoint, the token and the request body
+//
+// Returns:
+//   - *HTTPSMSSender: A pointer to the newly created HTTPSMSSender
+//   - error: An error if the URL or the body template is malformed
+func NewHTTPSMSSender(client *http.Client, config SMSGatewayConfig) (*HTTPSMSSender, error) {
+	if parsed, err := url.Parse(config.URL); err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return nil, fmt.Errorf("gateway URL %q must be an absolute http(s) URL", config.URL)
+	}
+	body, err := template.New("sms").Option("missingkey=error").Funcs(template.FuncMap{
+		"json": func(value string) (string, error) {
+			encoded, err := json.Marshal(value)
+			return string(encoded), err
+		},
+		"query": url.QueryEscape,
+	}).Parse(config.Body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid body template: %w", err)
+	}
+	return &HTTPSMSSender{client, config, body}, nil
+}
+
+// Provider returns "http".
+func (hs *HTTPSMSSender) Provider() string {
+	return "http"
+}
+
+// SendSMS sends an SMS.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - message: The SMS to send
+//
+// Returns:
+//   - error: An error if the gateway cannot be reached or does not accept the SMS
+func (hs *HTTPSMSSender) SendSMS(ctx context.Context, message domain.SMSMessage) error {
+	var body bytes.Buffer
+	if err := hs.body.Execute(&body, message); err != nil {
+		return fmt.Errorf("failed to render the gateway request: %w", err)
+	}
+
+	if hs.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, hs.config.Timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hs.config.URL, &body)
+	if err != nil {
+		return fmt.Errorf("failed to create gateway request: %w", err)
+	}
+	req.Header.Set("Content-Type", hs.config.ContentType)
+	if hs.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+hs.config.Token)
+	}
+
+	res, err := hs.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call the SMS gateway: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return nil
+	}
+	detail, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+	return fmt.Errorf("SMS gateway rejected the SMS with status %d: %s", res.StatusCode, strings.TrimSpace(string(detail)))
+}
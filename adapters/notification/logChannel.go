@@ -0,0 +1,47 @@
+// Package notification provides channels for delivering notifications to users.
+package notification
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/logging"
+)
+
+// logger writes the log records of this package.
+var logger = logging.Component("notification")
+
+// LogChannel writes notifications to the log instead of delivering them. It stands in for channels
+// without a configured provider, e.g. in development, and logs neither addresses nor details.
+// It implements the NotificationChannelPort interface from the messaging ports package.
+type LogChannel struct {
+	channel domain.NotificationChannel
+}
+
+// NewLogChannel creates a new LogChannel for the given channel.
+//
+// Parameters:
+//   - channel: The channel the LogChannel stands in for
+//
+// Returns:
+//   - *LogChannel: A pointer to the newly created LogChannel
+func NewLogChannel(channel domain.NotificationChannel) *LogChannel {
+	return &LogChannel{channel}
+}
+
+// Channel returns the channel the LogChannel stands in for.
+func (lc *LogChannel) Channel() domain.NotificationChannel {
+	return lc.channel
+}
+
+// Send logs the topic and the recipient of a notification.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - notification: The notification to send
+//
+// Returns:
+//   - error: Always nil
+func (lc *LogChannel) Send(ctx context.Context, notification domain.Notification) error {
+	logger.InfoContext(ctx, "Notification without provider", "topic", notification.Topic, "username", notification.Username, "channel", lc.channel)
+	return nil
+}
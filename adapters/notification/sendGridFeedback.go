@@ -0,0 +1,120 @@
+package notification
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// Headers of the signed Event Webhook of SendGrid.
+const (
+	sendGridSignatureHeader = "X-Twilio-Email-Event-Webhook-Signature"
+	sendGridTimestampHeader = "X-Twilio-Email-Event-Webhook-Timestamp"
+)
+
+// sendGridEvent is an event of the Event Webhook.
+type sendGridEvent struct {
+	Email string `json:"email"`
+	Event string `json:"event"`
+	// Type distinguishes bounces ("bounce") from blocks ("blocked"), which are temporary.
+	Type      string `json:"type"`
+	Reason    string `json:"reason"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// SendGridFeedback decodes the bounces and spam reports of the signed Event Webhook of SendGrid.
+// Other events, e.g. deliveries and opens, are ignored.
+// It implements the EmailFeedbackSourcePort interface from the messaging ports package.
+type SendGridFeedback struct {
+	publicKey *ecdsa.PublicKey
+}
+
+// NewSendGridFeedback creates a new SendGridFeedback.
+//
+// Parameters:
+//   - verificationKey: The verification key of the signed Event Webhook as shown by SendGrid, a
+//     base64 encoded ECDSA public key
+//
+// Returns:
+//   - *SendGridFeedback: A pointer to the newly created SendGridFeedback
+//   - error: An error if the key is malformed
+func NewSendGridFeedback(verificationKey string) (*SendGridFeedback, error) {
+	der, err := base64.StdEncoding.DecodeString(verificationKey)
+	if err != nil {
+		return nil, errors.New("verification key must be base64 encoded")
+	}
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("verification key must be an ECDSA public key")
+	}
+	return &SendGridFeedback{publicKey}, nil
+}
+
+// Provider returns "sendgrid".
+func (sf *SendGridFeedback) Provider() string {
+	return "sendgrid"
+}
+
+// ParseFeedback verifies the signature of an Event Webhook request and returns its bounces and spam reports.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - header: The headers of the request
+//   - body: The body of the request, a JSON array of events
+//
+// Returns:
+//   - []domain.EmailFeedback: The bounces and spam reports
+//   - error: errorx.ErrInvalidEmailFeedback if the signature is missing or wrong, or the body is malformed
+func (sf *SendGridFeedback) ParseFeedback(ctx context.Context, header http.Header, body []byte) ([]domain.EmailFeedback, error) {
+	signature, err := base64.StdEncoding.DecodeString(header.Get(sendGridSignatureHeader))
+	if err != nil || len(signature) == 0 {
+		return nil, errorx.ErrInvalidEmailFeedback.Detailf("%s is missing", sendGridSignatureHeader)
+	}
+	digest := sha256.Sum256(append([]byte(header.Get(sendGridTimestampHeader)), body...))
+	if !ecdsa.VerifyASN1(sf.publicKey, digest[:], signature) {
+		return nil, errorx.ErrInvalidEmailFeedback.Detailf("signature does not match")
+	}
+
+	var events []sendGridEvent
+	if err := json.Unmarshal(body, &events); err != nil {
+		return nil, errorx.ErrInvalidEmailFeedback.Detailf("body must be a JSON array of events")
+	}
+	var feedback []domain.EmailFeedback
+	for _, event := range events {
+		var feedbackType domain.EmailFeedbackType
+		switch {
+		case event.Event == "bounce" && event.Type == "blocked":
+			feedbackType = domain.EmailFeedbackSoftBounce
+		case event.Event == "bounce":
+			feedbackType = domain.EmailFeedbackHardBounce
+		case event.Event == "spamreport":
+			feedbackType = domain.EmailFeedbackComplaint
+		default:
+			continue
+		}
+		var at time.Time
+		if event.Timestamp > 0 {
+			at = time.Unix(event.Timestamp, 0).UTC()
+		}
+		feedback = append(feedback, domain.EmailFeedback{
+			Address:  event.Email,
+			Type:     feedbackType,
+			Provider: sf.Provider(),
+			Detail:   event.Reason,
+			At:       at,
+		})
+	}
+	return feedback, nil
+}
@@ -0,0 +1,132 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// SendGridConfig configures the SendGrid sender.
+type SendGridConfig struct {
+	// BaseURL is the API of SendGrid, "https://api.sendgrid.com" or "https://api.eu.sendgrid.com" for
+	// the EU data residency region.
+	BaseURL string
+	// APIKey authenticates at the API, it needs the "Mail Send" permission.
+	APIKey string
+	// Timeout bounds a request.
+	Timeout time.Duration
+}
+
+// sendGridAddress is an address of the Mail Send API.
+type sendGridAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+// sendGridContent is a body of the Mail Send API.
+type sendGridContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// sendGridPersonalization names the recipients of a request of the Mail Send API.
+type sendGridPersonalization struct {
+	To []sendGridAddress `json:"to"`
+}
+
+// sendGridMail is a request of the Mail Send API.
+type sendGridMail struct {
+	Personalizations []sendGridPersonalization `json:"personalizations"`
+	From             sendGridAddress           `json:"from"`
+	Subject          string                    `json:"subject"`
+	Content          []sendGridContent         `json:"content"`
+}
+
+// SendGridSender sends emails through the Mail Send API of Twilio SendGrid.
+// It implements the EmailSenderPort interface from the messaging ports package.
+type SendGridSender struct {
+	client *http.Client
+	config SendGridConfig
+}
+
+// NewSendGridSender creates a new SendGridSender.
+//
+// Parameters:
+//   - client: The HTTP client for the requests to SendGrid
+//   - config: The API and its key
+//
+// Returns:
+//   - *SendGridSender: A pointer to the newly created SendGridSender
+func NewSendGridSender(client *http.Client, config SendGridConfig) *SendGridSender {
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+	return &SendGridSender{client, config}
+}
+
+// Provider returns "sendgrid".
+func (ss *SendGridSender) Provider() string {
+	return "sendgrid"
+}
+
+// SendEmail sends an email.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - message: The email to send
+//
+// Returns:
+//   - error: An error if SendGrid cannot be reached or does not accept the email
+func (ss *SendGridSender) SendEmail(ctx context.Context, message domain.EmailMessage) error {
+	from, err := mail.ParseAddress(message.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender %q: %w", message.From, err)
+	}
+	body, err := json.Marshal(sendGridMail{
+		Personalizations: []sendGridPersonalization{{To: []sendGridAddress{{message.To, message.ToName}}}},
+		From:             sendGridAddress{from.Address, from.Name},
+		Subject:          message.Subject,
+		Content:          []sendGridContent{{"text/plain", message.Text}, {"text/html", message.HTML}},
+	})
+	if err != nil {
+		return err
+	}
+
+	if ss.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ss.config.Timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ss.config.BaseURL+"/v3/mail/send", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create SendGrid request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+ss.config.APIKey)
+
+	res, err := ss.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call SendGrid: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusAccepted || res.StatusCode == http.StatusOK {
+		return nil
+	}
+	var failure struct {
+		Errors []struct {
+			Message string `json:"message"`
+			Field   string `json:"field"`
+		} `json:"errors"`
+	}
+	_ = json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&failure)
+	messages := make([]string, 0, len(failure.Errors))
+	for _, e := range failure.Errors {
+		messages = append(messages, strings.TrimPrefix(e.Field+": "+e.Message, ": "))
+	}
+	return fmt.Errorf("SendGrid rejected the email with status %d: %s", res.StatusCode, strings.Join(messages, "; "))
+}
@@ -0,0 +1,141 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/aws"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// sesNotification is a bounce or complaint notification of SES. Notifications of identities name
+// the type in notificationType, events of configuration sets in eventType.
+type sesNotification struct {
+	NotificationType string `json:"notificationType"`
+	EventType        string `json:"eventType"`
+	Bounce           struct {
+		BounceType        string `json:"bounceType"`
+		BounceSubType     string `json:"bounceSubType"`
+		BouncedRecipients []struct {
+			EmailAddress   string `json:"emailAddress"`
+			DiagnosticCode string `json:"diagnosticCode"`
+		} `json:"bouncedRecipients"`
+		Timestamp time.Time `json:"timestamp"`
+	} `json:"bounce"`
+	Complaint struct {
+		ComplainedRecipients []struct {
+			EmailAddress string `json:"emailAddress"`
+		} `json:"complainedRecipients"`
+		ComplaintFeedbackType string    `json:"complaintFeedbackType"`
+		Timestamp             time.Time `json:"timestamp"`
+	} `json:"complaint"`
+}
+
+// SESFeedback decodes the bounce and complaint notifications SES publishes to an SNS topic, which
+// posts them to the HTTPS subscription of the service. Messages have to be signed by SNS and come
+// from the configured topic; the subscription is confirmed when SNS asks for it.
+// It implements the EmailFeedbackSourcePort interface from the messaging ports package.
+type SESFeedback struct {
+	verifier *aws.SNSMessageVerifier
+	topicARN string
+}
+
+// NewSESFeedback creates a new SESFeedback.
+//
+// Parameters:
+//   - verifier: The verifier of the SNS signatures
+//   - topicARN: The ARN of the SNS topic SES publishes the notifications to
+//
+// Returns:
+//   - *SESFeedback: A pointer to the newly created SESFeedback
+func NewSESFeedback(verifier *aws.SNSMessageVerifier, topicARN string) *SESFeedback {
+	return &SESFeedback{verifier, topicARN}
+}
+
+// Provider returns "ses".
+func (sf *SESFeedback) Provider() string {
+	return "ses"
+}
+
+// ParseFeedback verifies an SNS message and returns the bounces and complaints of the SES
+// notification it carries. A subscription confirmation is confirmed and yields no feedback.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - header: The headers of the request, unused as SNS signs the body
+//   - body: The body of the request, an SNS message
+//
+// Returns:
+//   - []domain.EmailFeedback: The bounces and complaints
+//   - error: errorx.ErrInvalidEmailFeedback if the message is not signed by SNS, from another topic
+//     or malformed, or an error if the subscription cannot be confirmed
+func (sf *SESFeedback) ParseFeedback(ctx context.Context, header http.Header, body []byte) ([]domain.EmailFeedback, error) {
+	var message aws.SNSMessage
+	if err := json.Unmarshal(body, &message); err != nil {
+		return nil, errorx.ErrInvalidEmailFeedback.Detailf("body must be an SNS message")
+	}
+	if message.TopicARN != sf.topicARN {
+		return nil, errorx.ErrInvalidEmailFeedback.Detailf("topic %s is not subscribed", message.TopicARN)
+	}
+	if err := sf.verifier.Verify(ctx, message); err != nil {
+		return nil, errorx.ErrInvalidEmailFeedback.Detailf("%v", err)
+	}
+
+	if message.Type == aws.SNSTypeSubscriptionConfirmation {
+		if err := sf.verifier.ConfirmSubscription(ctx, message); err != nil {
+			return nil, err
+		}
+		logger.InfoContext(ctx, "Confirmed the SNS subscription of the SES feedback", "topic", message.TopicARN)
+		return nil, nil
+	}
+	if message.Type != aws.SNSTypeNotification {
+		return nil, nil
+	}
+
+	var notification sesNotification
+	if err := json.Unmarshal([]byte(message.Message), &notification); err != nil {
+		return nil, errorx.ErrInvalidEmailFeedback.Detailf("message must be an SES notification")
+	}
+	kind := notification.NotificationType
+	if kind == "" {
+		kind = notification.EventType
+	}
+	var feedback []domain.EmailFeedback
+	switch kind {
+	case "Bounce":
+		feedbackType := domain.EmailFeedbackSoftBounce
+		if notification.Bounce.BounceType == "Permanent" {
+			feedbackType = domain.EmailFeedbackHardBounce
+		}
+		for _, recipient := range notification.Bounce.BouncedRecipients {
+			detail := recipient.DiagnosticCode
+			if detail == "" {
+				detail = notification.Bounce.BounceType + "/" + notification.Bounce.BounceSubType
+			}
+			feedback = append(feedback, domain.EmailFeedback{
+				Address:  recipient.EmailAddress,
+				Type:     feedbackType,
+				Provider: sf.Provider(),
+				Detail:   detail,
+				At:       notification.Bounce.Timestamp,
+			})
+		}
+	case "Complaint":
+		// recipients reporting an email as not spam retract an earlier complaint
+		if notification.Complaint.ComplaintFeedbackType == "not-spam" {
+			return nil, nil
+		}
+		for _, recipient := range notification.Complaint.ComplainedRecipients {
+			feedback = append(feedback, domain.EmailFeedback{
+				Address:  recipient.EmailAddress,
+				Type:     domain.EmailFeedbackComplaint,
+				Provider: sf.Provider(),
+				Detail:   notification.Complaint.ComplaintFeedbackType,
+				At:       notification.Complaint.Timestamp,
+			})
+		}
+	}
+	return feedback, nil
+}
@@ -0,0 +1,132 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/aws"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// SESConfig configures the SES sender.
+type SESConfig struct {
+	// Region is the AWS region the sender identity is verified in, e.g. "eu-central-1".
+	Region string
+	// ConfigurationSet is the configuration set of the emails, e.g. publishing bounces and complaints
+	// to SNS; the default configuration set of the identity applies if empty.
+	ConfigurationSet string
+	// Timeout bounds a request.
+	Timeout time.Duration
+}
+
+// sesContent is a text of the SendEmail action of the SES API v2.
+type sesContent struct {
+	Data    string `json:"Data"`
+	Charset string `json:"Charset"`
+}
+
+// sesSendEmail is a request of the SendEmail action of the SES API v2.
+type sesSendEmail struct {
+	FromEmailAddress string `json:"FromEmailAddress"`
+	Destination      struct {
+		ToAddresses []string `json:"ToAddresses"`
+	} `json:"Destination"`
+	Content struct {
+		Simple struct {
+			Subject sesContent `json:"Subject"`
+			Body    struct {
+				Text sesContent `json:"Text"`
+				HTML sesContent `json:"Html"`
+			} `json:"Body"`
+		} `json:"Simple"`
+	} `json:"Content"`
+	ConfigurationSetName string `json:"ConfigurationSetName,omitempty"`
+}
+
+// SESSender sends emails through Amazon Simple Email Service.
+// It implements the EmailSenderPort interface from the messaging ports package.
+type SESSender struct {
+	client   *http.Client
+	config   SESConfig
+	endpoint string
+	signer   *aws.Signer
+}
+
+// NewSESSender creates a new SESSender.
+//
+// Parameters:
+//   - client: The HTTP client for the requests to SES
+//   - config: The region and the configuration set
+//   - credentials: The provider of the credentials the requests are signed with
+//
+// Returns:
+//   - *SESSender: A pointer to the newly created SESSender
+func NewSESSender(client *http.Client, config SESConfig, credentials aws.CredentialsProvider) *SESSender {
+	endpoint := "https://email." + config.Region + ".amazonaws.com/v2/email/outbound-emails"
+	return &SESSender{client, config, endpoint, aws.NewSigner("ses", config.Region, credentials)}
+}
+
+// Provider returns "ses".
+func (ss *SESSender) Provider() string {
+	return "ses"
+}
+
+// SendEmail sends an email.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - message: The email to send
+//
+// Returns:
+//   - error: An error if SES cannot be reached or does not accept the email
+func (ss *SESSender) SendEmail(ctx context.Context, message domain.EmailMessage) error {
+	from, err := mail.ParseAddress(message.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender %q: %w", message.From, err)
+	}
+	var request sesSendEmail
+	// the names are encoded as RFC 2047 words, SES rejects raw non-ASCII characters in addresses
+	request.FromEmailAddress = from.String()
+	request.Destination.ToAddresses = []string{(&mail.Address{Name: message.ToName, Address: message.To}).String()}
+	request.Content.Simple.Subject = sesContent{message.Subject, "UTF-8"}
+	request.Content.Simple.Body.Text = sesContent{message.Text, "UTF-8"}
+	request.Content.Simple.Body.HTML = sesContent{message.HTML, "UTF-8"}
+	request.ConfigurationSetName = ss.config.ConfigurationSet
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	if ss.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ss.config.Timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ss.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create SES request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if err := ss.signer.Sign(ctx, req, body); err != nil {
+		return err
+	}
+
+	res, err := ss.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call SES: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+	var failure struct {
+		Message string `json:"message"`
+	}
+	_ = json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&failure)
+	return fmt.Errorf("SES rejected the email with status %d (%s): %s", res.StatusCode, res.Header.Get("X-Amzn-Errortype"), failure.Message)
+}
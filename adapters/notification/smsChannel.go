@@ -0,0 +1,84 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/messaging"
+)
+
+// SMSChannel sends notifications as SMS carrying the subject of the email template of the topic.
+// Every SMS has to pass the guard first; it is then handed to the first provider, and to the next one
+// whenever a provider fails.
+// It implements the NotificationChannelPort interface from the messaging ports package.
+type SMSChannel struct {
+	templates *EmailTemplates
+	from      string
+	guard     *SMSGuard
+	senders   []messaging.SMSSenderPort
+}
+
+// NewSMSChannel creates a new SMSChannel.
+//
+// Parameters:
+//   - templates: The templates the texts are rendered with
+//   - from: The sender of the SMS, a phone number or an alphanumeric sender id
+//   - guard: The guard limiting the SMS
+//   - senders: The providers in the order they are tried
+//
+// Returns:
+//   - *SMSChannel: A pointer to the newly created SMSChannel
+//   - error: An error if no provider is given
+func NewSMSChannel(templates *EmailTemplates, from string, guard *SMSGuard, senders ...messaging.SMSSenderPort) (*SMSChannel, error) {
+	if len(senders) == 0 {
+		return nil, errors.New("no SMS provider")
+	}
+	return &SMSChannel{templates, from, guard, senders}, nil
+}
+
+// Channel returns domain.NotificationChannelSMS.
+func (sc *SMSChannel) Channel() domain.NotificationChannel {
+	return domain.NotificationChannelSMS
+}
+
+// Send renders the text of a notification and sends it to the phone number of the user.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - notification: The notification to send
+//
+// Returns:
+//   - error: errorx.ErrSMSLimitExceeded if the guard rejects the SMS, or an error if the text cannot be
+//     rendered or every provider failed
+func (sc *SMSChannel) Send(ctx context.Context, notification domain.Notification) error {
+	email, err := sc.templates.Render(notification)
+	if err != nil {
+		return err
+	}
+	price, err := sc.guard.Admit(ctx, notification.PhoneNumber)
+	if err != nil {
+		return err
+	}
+	message := domain.SMSMessage{From: sc.from, To: notification.PhoneNumber, Body: email.Subject}
+
+	var errs []error
+	for i, sender := range sc.senders {
+		err := sender.SendSMS(ctx, message)
+		if err == nil {
+			if i > 0 {
+				logger.InfoContext(ctx, "Sent SMS through fallback provider", "provider", sender.Provider(), "topic", notification.Topic)
+			}
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", sender.Provider(), err))
+		if ctx.Err() != nil {
+			break
+		}
+		if i < len(sc.senders)-1 {
+			logger.WarnContext(ctx, "SMS provider failed, trying the next one", "provider", sender.Provider(), "error", err)
+		}
+	}
+	sc.guard.Refund(price)
+	return errors.Join(errs...)
+}
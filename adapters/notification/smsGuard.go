@@ -0,0 +1,117 @@
+package notification
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/ports/security"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+)
+
+// SMSGuardConfig configures the limits SMS are sent within.
+type SMSGuardConfig struct {
+	// Countries are the calling codes SMS may be sent to, e.g. "+41"; every country if empty.
+	Countries []string
+	// Limits are the rate limits by calling code, with "*" applying to every other country on its own;
+	// countries without limit are not rate limited.
+	Limits map[string]security.RateLimit
+	// Prices are the estimated prices of an SMS by calling code, with "*" for every other country.
+	Prices map[string]float64
+	// Budget caps the estimated spend per Period, no cap if zero.
+	Budget float64
+	Period time.Duration
+}
+
+// SMSGuard protects against SMS pumping, where attackers trigger SMS to premium numbers they earn
+// from. SMS are only sent to the allowed countries, within the rate limit of their country and until
+// the estimated spend of the period reaches the budget. The rate limits are kept by the rate limiter
+// and shared by every instance; the spend is counted per instance.
+type SMSGuard struct {
+	limiter security.RateLimiterPort
+	clock   system.ClockPort
+	config  SMSGuardConfig
+
+	mu          sync.Mutex
+	windowStart time.Time
+	spent       float64
+}
+
+// NewSMSGuard creates a new SMSGuard.
+//
+// Parameters:
+//   - limiter: The rate limiter keeping the limits of the countries
+//   - clock: The clock the budget periods are measured with
+//   - config: The countries, rate limits, prices and budget
+//
+// Returns:
+//   - *SMSGuard: A pointer to the newly created SMSGuard
+func NewSMSGuard(limiter security.RateLimiterPort, clock system.ClockPort, config SMSGuardConfig) *SMSGuard {
+	return &SMSGuard{limiter: limiter, clock: clock, config: config}
+}
+
+// Admit checks an SMS to a phone number against the limits and reserves its estimated price from
+// the budget. If the rate limiter fails, the SMS is admitted and only the budget applies.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - phoneNumber: The phone number of the recipient in E.164 format
+//
+// Returns:
+//   - float64: The reserved price, to be refunded if the SMS is not sent
+//   - error: errorx.ErrSMSLimitExceeded if the country is not allowed, its rate limit is exceeded or
+//     the budget is spent
+func (sg *SMSGuard) Admit(ctx context.Context, phoneNumber string) (float64, error) {
+	country := domain.CallingCode(phoneNumber)
+	if country == "" {
+		return 0, errorx.ErrSMSLimitExceeded.Detailf("phone number is not in E.164 format")
+	}
+	if len(sg.config.Countries) > 0 && !slices.Contains(sg.config.Countries, country) {
+		return 0, errorx.ErrSMSLimitExceeded.Detailf("SMS to %s are not allowed", country)
+	}
+
+	limit, ok := sg.config.Limits[country]
+	if !ok {
+		limit, ok = sg.config.Limits["*"]
+	}
+	if ok {
+		decision, err := sg.limiter.Allow(ctx, "sms:"+country, limit)
+		if err != nil {
+			logger.WarnContext(ctx, "SMS rate limiter unavailable, only the budget applies", "country", country, "error", err)
+		} else if !decision.Allowed {
+			logger.WarnContext(ctx, "SMS rate limit exceeded", "country", country)
+			return 0, errorx.ErrSMSLimitExceeded.Detailf("too many SMS to %s, retry in %s", country, decision.RetryAfter.Round(time.Second))
+		}
+	}
+
+	price, ok := sg.config.Prices[country]
+	if !ok {
+		price = sg.config.Prices["*"]
+	}
+	if sg.config.Budget <= 0 {
+		return price, nil
+	}
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+	if windowStart := sg.clock.Now().Truncate(sg.config.Period); !windowStart.Equal(sg.windowStart) {
+		sg.windowStart, sg.spent = windowStart, 0
+	}
+	if sg.spent+price > sg.config.Budget {
+		logger.WarnContext(ctx, "SMS budget spent", "country", country, "spent", sg.spent, "budget", sg.config.Budget)
+		return 0, errorx.ErrSMSLimitExceeded.Detailf("SMS budget of the period is spent")
+	}
+	sg.spent += price
+	return price, nil
+}
+
+// Refund returns the reserved price of an SMS that was not sent to the budget.
+//
+// Parameters:
+//   - price: The price Admit reserved
+func (sg *SMSGuard) Refund(price float64) {
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+	sg.spent = max(sg.spent-price, 0)
+}
@@ -0,0 +1,322 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// TLS modes of the connections to the SMTP server.
+const (
+	// SMTPTLSStartTLS upgrades the connection with STARTTLS and fails if the server does not offer it.
+	SMTPTLSStartTLS = "starttls"
+	// SMTPTLSImplicit speaks TLS from the start, usually on port 465.
+	SMTPTLSImplicit = "tls"
+	// SMTPTLSNone sends in plaintext, only for relays on the local host or network.
+	SMTPTLSNone = "none"
+)
+
+// SMTPConfig configures the SMTP sender.
+type SMTPConfig struct {
+	// Host is the host name of the SMTP server, also verified against its certificate.
+	Host string
+	// Port is the port of the SMTP server, e.g. 587 for STARTTLS or 465 for implicit TLS.
+	Port int
+	// Username authenticates at the server with PLAIN, no authentication if empty.
+	Username string
+	// Password authenticates at the server together with Username.
+	Password string
+	// TLS is one of the SMTPTLS modes.
+	TLS string
+	// PoolSize is the largest number of idle connections kept open for later emails.
+	PoolSize int
+	// Timeout bounds connecting and sending an email.
+	Timeout time.Duration
+	// IdleTimeout is how long an idle connection is reused, servers close idle connections after a few minutes.
+	IdleTimeout time.Duration
+}
+
+// ValidateSMTPTLS checks a TLS mode of the SMTP sender.
+//
+// Parameters:
+//   - mode: The mode, e.g. "starttls"
+//
+// Returns:
+//   - error: An error if the mode is unknown
+func ValidateSMTPTLS(mode string) error {
+	if mode != SMTPTLSStartTLS && mode != SMTPTLSImplicit && mode != SMTPTLSNone {
+		return fmt.Errorf("must be %s, %s or %s", SMTPTLSStartTLS, SMTPTLSImplicit, SMTPTLSNone)
+	}
+	return nil
+}
+
+// smtpConn is an open connection to the SMTP server.
+type smtpConn struct {
+	conn     net.Conn
+	client   *smtp.Client
+	lastUsed time.Time
+}
+
+// close ends the session, or drops the connection if the server does not answer.
+func (sc *smtpConn) close() {
+	_ = sc.conn.SetDeadline(time.Now().Add(time.Second))
+	if err := sc.client.Quit(); err != nil {
+		_ = sc.client.Close()
+	}
+}
+
+// SMTPSender sends emails with a plain text and an HTML part over SMTP. Connections are
+// authenticated once and reused for later emails; a pooled connection the server closed in the
+// meantime is noticed by a RSET before the email and replaced.
+// It implements the EmailSenderPort interface from the messaging ports package.
+type SMTPSender struct {
+	config   SMTPConfig
+	hostname string
+	idle     chan *smtpConn
+}
+
+// NewSMTPSender creates a new SMTPSender. No connection is opened until the first email.
+//
+// Parameters:
+//   - config: The server and the pool
+//
+// Returns:
+//   - *SMTPSender: A pointer to the newly created SMTPSender
+//   - error: An error if the TLS mode is invalid
+func NewSMTPSender(config SMTPConfig) (*SMTPSender, error) {
+	if err := ValidateSMTPTLS(config.TLS); err != nil {
+		return nil, fmt.Errorf("invalid TLS mode %q: %w", config.TLS, err)
+	}
+	// the name the sender greets the server with, some servers reject "localhost"
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "localhost"
+	}
+	return &SMTPSender{config, hostname, make(chan *smtpConn, max(config.PoolSize, 0))}, nil
+}
+
+// Provider returns "smtp".
+func (sc *SMTPSender) Provider() string {
+	return "smtp"
+}
+
+// SendEmail sends an email.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - message: The email to send
+//
+// Returns:
+//   - error: An error if the server cannot be reached or does not accept the email
+func (sc *SMTPSender) SendEmail(ctx context.Context, message domain.EmailMessage) error {
+	from, err := mail.ParseAddress(message.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender %q: %w", message.From, err)
+	}
+	data, err := compose(from, message)
+	if err != nil {
+		return fmt.Errorf("failed to compose email: %w", err)
+	}
+
+	conn, err := sc.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	if err := sc.deliver(ctx, conn, from.Address, message.To, data); err != nil {
+		conn.close()
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	sc.release(conn)
+	return nil
+}
+
+// Verify connects and authenticates at the server, so a wrong configuration shows at startup.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//
+// Returns:
+//   - error: An error if the server cannot be reached or rejects the credentials
+func (sc *SMTPSender) Verify(ctx context.Context) error {
+	conn, err := sc.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	sc.release(conn)
+	return nil
+}
+
+// acquire returns a pooled connection that is still open, or opens a new one.
+func (sc *SMTPSender) acquire(ctx context.Context) (*smtpConn, error) {
+	for {
+		select {
+		case conn := <-sc.idle:
+			if time.Since(conn.lastUsed) > sc.config.IdleTimeout {
+				conn.close()
+				continue
+			}
+			_ = conn.conn.SetDeadline(time.Now().Add(sc.config.Timeout))
+			if err := conn.client.Reset(); err != nil {
+				_ = conn.client.Close()
+				continue
+			}
+			return conn, nil
+		default:
+			return sc.dial(ctx)
+		}
+	}
+}
+
+// release returns a connection to the pool, or closes it if the pool is full.
+func (sc *SMTPSender) release(conn *smtpConn) {
+	conn.lastUsed = time.Now()
+	select {
+	case sc.idle <- conn:
+	default:
+		conn.close()
+	}
+}
+
+// dial opens and authenticates a new connection.
+func (sc *SMTPSender) dial(ctx context.Context) (*smtpConn, error) {
+	ctx, cancel := context.WithTimeout(ctx, sc.config.Timeout)
+	defer cancel()
+	addr := net.JoinHostPort(sc.config.Host, strconv.Itoa(sc.config.Port))
+	tlsConfig := &tls.Config{ServerName: sc.config.Host, MinVersion: tls.VersionTLS12}
+
+	var conn net.Conn
+	var err error
+	if sc.config.TLS == SMTPTLSImplicit {
+		conn, err = (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to SMTP server %s: %w", addr, err)
+	}
+	deadline, _ := ctx.Deadline()
+	_ = conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, sc.config.Host)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to greet SMTP server %s: %w", addr, err)
+	}
+	if err := sc.open(client, tlsConfig); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("SMTP server %s: %w", addr, err)
+	}
+	return &smtpConn{conn, client, time.Now()}, nil
+}
+
+// open introduces the sender, upgrades the connection to TLS and authenticates.
+func (sc *SMTPSender) open(client *smtp.Client, tlsConfig *tls.Config) error {
+	if err := client.Hello(sc.hostname); err != nil {
+		return err
+	}
+	if sc.config.TLS == SMTPTLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("STARTTLS is not offered")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("STARTTLS failed: %w", err)
+		}
+	}
+	if sc.config.Username != "" {
+		// PlainAuth refuses to send the password over plaintext connections to other hosts
+		if err := client.Auth(smtp.PlainAuth("", sc.config.Username, sc.config.Password, sc.config.Host)); err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// deliver sends a message over a connection.
+func (sc *SMTPSender) deliver(ctx context.Context, conn *smtpConn, from string, to string, message []byte) error {
+	deadline := time.Now().Add(sc.config.Timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	_ = conn.conn.SetDeadline(deadline)
+	if err := conn.client.Mail(from); err != nil {
+		return err
+	}
+	if err := conn.client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := conn.client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// compose builds a multipart/alternative message of the text and the HTML body. Line ends are
+// converted to CRLF when the message is sent.
+func compose(from *mail.Address, message domain.EmailMessage) ([]byte, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	_, domainPart, _ := strings.Cut(from.Address, "@")
+	to := &mail.Address{Name: message.ToName, Address: message.To}
+
+	var buf bytes.Buffer
+	body := multipart.NewWriter(&buf)
+	headers := []struct{ name, value string }{
+		{"From", from.String()},
+		{"To", to.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", message.Subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", "<" + hex.EncodeToString(id) + "@" + domainPart + ">"},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "multipart/alternative; boundary=" + body.Boundary()},
+	}
+	for _, header := range headers {
+		buf.WriteString(header.name + ": " + header.value + "\n")
+	}
+	buf.WriteString("\n")
+
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", message.Text},
+		{"text/html; charset=utf-8", message.HTML},
+	} {
+		w, err := body.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
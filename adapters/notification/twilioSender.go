@@ -0,0 +1,97 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// TwilioConfig configures the Twilio sender.
+type TwilioConfig struct {
+	// BaseURL is the API of Twilio, "https://api.twilio.com".
+	BaseURL string
+	// AccountSID and AuthToken authenticate at the API.
+	AccountSID string
+	AuthToken  string
+	// MessagingServiceSID sends through a messaging service, which picks the sender number, instead of
+	// the sender of the message.
+	MessagingServiceSID string
+	// Timeout bounds a request.
+	Timeout time.Duration
+}
+
+// TwilioSender sends SMS through the Programmable Messaging API of Twilio.
+// It implements the SMSSenderPort interface from the messaging ports package.
+type TwilioSender struct {
+	client   *http.Client
+	config   TwilioConfig
+	endpoint string
+}
+
+// NewTwilioSender creates a new TwilioSender.
+//
+// Parameters:
+//   - client: The HTTP client for the requests to Twilio
+//   - config: The API, the account and its token
+//
+// Returns:
+//   - *TwilioSender: A pointer to the newly created TwilioSender
+func NewTwilioSender(client *http.Client, config TwilioConfig) *TwilioSender {
+	endpoint := strings.TrimSuffix(config.BaseURL, "/") + "/2010-04-01/Accounts/" + url.PathEscape(config.AccountSID) + "/Messages.json"
+	return &TwilioSender{client, config, endpoint}
+}
+
+// Provider returns "twilio".
+func (ts *TwilioSender) Provider() string {
+	return "twilio"
+}
+
+// SendSMS sends an SMS.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - message: The SMS to send
+//
+// Returns:
+//   - error: An error if Twilio cannot be reached or does not accept the SMS
+func (ts *TwilioSender) SendSMS(ctx context.Context, message domain.SMSMessage) error {
+	form := url.Values{"To": {message.To}, "Body": {message.Body}}
+	if ts.config.MessagingServiceSID != "" {
+		form.Set("MessagingServiceSid", ts.config.MessagingServiceSID)
+	} else {
+		form.Set("From", message.From)
+	}
+
+	if ts.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ts.config.Timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ts.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create Twilio request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(ts.config.AccountSID, ts.config.AuthToken)
+
+	res, err := ts.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call Twilio: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusCreated || res.StatusCode == http.StatusOK {
+		return nil
+	}
+	var failure struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	_ = json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&failure)
+	return fmt.Errorf("Twilio rejected the SMS with status %d (error %d): %s", res.StatusCode, failure.Code, failure.Message)
+}
@@ -0,0 +1,50 @@
+package notification
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/ports/messaging"
+)
+
+// WebhookChannel hands notifications to the webhook subscribers by emitting a NotificationRequested
+// event, which is delivered like every other domain event. Contact details of the user are not
+// part of the event; subscribers address the user by username.
+// It implements the NotificationChannelPort interface from the messaging ports package.
+type WebhookChannel struct {
+	eventDispatcher messaging.EventDispatcherPort
+}
+
+// NewWebhookChannel creates a new WebhookChannel.
+//
+// Parameters:
+//   - eventDispatcher: An implementation of EventDispatcherPort the webhook delivery is subscribed to
+//
+// Returns:
+//   - *WebhookChannel: A pointer to the newly created WebhookChannel
+func NewWebhookChannel(eventDispatcher messaging.EventDispatcherPort) *WebhookChannel {
+	return &WebhookChannel{eventDispatcher}
+}
+
+// Channel returns domain.NotificationChannelWebhook.
+func (wc *WebhookChannel) Channel() domain.NotificationChannel {
+	return domain.NotificationChannelWebhook
+}
+
+// Send emits a NotificationRequested event for the notification.
+//
+// Parameters:
+//   - ctx: A context.Context passed on to the event handlers
+//   - notification: The notification to send
+//
+// Returns:
+//   - error: Always nil, failures of the delivery are handled by the webhook delivery
+func (wc *WebhookChannel) Send(ctx context.Context, notification domain.Notification) error {
+	wc.eventDispatcher.Dispatch(ctx, events.NotificationRequested{
+		Username: notification.Username,
+		Topic:    string(notification.Topic),
+		Details:  notification.Details,
+		At:       notification.At,
+	})
+	return nil
+}
@@ -0,0 +1,97 @@
+// Package otp provides time-based one-time passwords behind the OneTimePasswordPort.
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// secretBytes is the length of generated secrets, as recommended by RFC 4226 for HMAC-SHA1.
+	secretBytes = 20
+	// digits is the number of digits of a code.
+	digits = 6
+	// period is the time step a code is valid for.
+	period = 30 * time.Second
+	// skew is the number of time steps before and after the current one whose codes are accepted as
+	// well, to tolerate clock drift and slow typing.
+	skew = 1
+)
+
+// encoding is the unpadded base32 encoding authenticator apps expect for secrets.
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Totp generates and verifies time-based one-time passwords (RFC 6238) with HMAC-SHA1, 6 digits and
+// 30 second steps, the parameters all common authenticator apps support. It implements the
+// OneTimePasswordPort interface from the security ports package.
+type Totp struct {
+	issuer string
+}
+
+// NewTotp creates a new Totp.
+//
+// Parameters:
+//   - issuer: The name authenticator apps show next to the account, e.g. the name of the service
+//
+// Returns:
+//   - Totp: The one-time password algorithm
+func NewTotp(issuer string) Totp {
+	return Totp{issuer}
+}
+
+// GenerateSecret creates a random 160 bit secret, encoded in unpadded base32.
+func (t Totp) GenerateSecret() (string, error) {
+	secret := make([]byte, secretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(secret), nil
+}
+
+// ProvisioningURI returns the otpauth://totp URI of the secret, labelled "<issuer>:<account>".
+func (t Totp) ProvisioningURI(secret string, account string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", t.issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(digits))
+	query.Set("period", fmt.Sprint(int(period.Seconds())))
+	label := url.PathEscape(t.issuer + ":" + account)
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// Verify reports whether the code matches the secret in the current time step or one step before or after.
+func (t Totp) Verify(secret string, code string, at time.Time) bool {
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil || len(code) != digits {
+		return false
+	}
+	step := at.Unix() / int64(period.Seconds())
+	valid := false
+	for offset := int64(-skew); offset <= skew; offset++ {
+		if subtle.ConstantTimeCompare([]byte(generate(key, step+offset)), []byte(code)) == 1 {
+			valid = true
+		}
+	}
+	return valid
+}
+
+// generate computes the code of a time step with the dynamic truncation of RFC 4226.
+func generate(key []byte, step int64) string {
+	message := make([]byte, 8)
+	binary.BigEndian.PutUint64(message, uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message)
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", digits, value%1000000)
+}
@@ -0,0 +1,94 @@
+package password
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"strings"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// argon2idKeyBytes is the length of the derived key of argon2id hashes.
+const argon2idKeyBytes = 32
+
+// Argon2id hashes passwords with argon2id, encoded in the PHC string format
+// "$argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<key>".
+type Argon2id struct {
+	time    uint32
+	memory  uint32
+	threads uint8
+}
+
+// NewArgon2id creates an argon2id algorithm.
+//
+// Parameters:
+//   - time: The number of passes over the memory, at least 1
+//   - memory: The memory in KiB, at least 8 per thread
+//   - threads: The degree of parallelism, at least 1
+//
+// Returns:
+//   - Argon2id: The algorithm
+//   - error: An error if a parameter is out of bounds
+func NewArgon2id(time uint32, memory uint32, threads uint8) (Argon2id, error) {
+	if time < 1 || threads < 1 || memory < 8*uint32(threads) {
+		return Argon2id{}, errors.New("argon2id needs at least one pass, one thread and 8 KiB of memory per thread")
+	}
+	return Argon2id{time, memory, threads}, nil
+}
+
+// Name returns "argon2id".
+func (Argon2id) Name() string {
+	return "argon2id"
+}
+
+// Hash hashes a password with a random salt.
+func (a Argon2id) Hash(password string) (string, error) {
+	salt, err := newSalt()
+	if err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, a.time, a.memory, a.threads, argon2idKeyBytes)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, a.memory, a.time, a.threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Recognizes reports whether the hash has the prefix $argon2id$.
+func (Argon2id) Recognizes(hash string) bool {
+	return strings.HasPrefix(hash, "$argon2id$")
+}
+
+// Verify compares a password with an argon2id hash, using the parameters encoded in the hash.
+func (Argon2id) Verify(hash string, password string) error {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return errorx.ErrInvalidPasswordHash.Detailf("malformed argon2id hash")
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return errorx.ErrInvalidPasswordHash.Detailf("unsupported argon2id version %q", parts[2])
+	}
+	var memory, time uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return errorx.ErrInvalidPasswordHash.Detailf("malformed argon2id parameters: %v", err)
+	}
+	if _, err := NewArgon2id(time, memory, threads); err != nil {
+		return errorx.ErrInvalidPasswordHash.Detailf("%v", err)
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return errorx.ErrInvalidPasswordHash.Detailf("malformed argon2id salt: %v", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return errorx.ErrInvalidPasswordHash.Detailf("malformed argon2id key")
+	}
+
+	candidate := argon2.IDKey([]byte(password), salt, time, memory, threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(candidate, key) != 1 {
+		return errorx.ErrInvalidCredentials
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// Bcrypt hashes passwords with bcrypt.
+type Bcrypt struct {
+	cost int
+}
+
+// NewBcrypt creates a bcrypt algorithm.
+//
+// Parameters:
+//   - cost: The logarithmic work factor, between bcrypt.MinCost and bcrypt.MaxCost
+//
+// Returns:
+//   - Bcrypt: The algorithm
+//   - error: An error if the cost is out of bounds
+func NewBcrypt(cost int) (Bcrypt, error) {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return Bcrypt{}, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	return Bcrypt{cost}, nil
+}
+
+// Name returns "bcrypt".
+func (Bcrypt) Name() string {
+	return "bcrypt"
+}
+
+// Hash hashes a password, e.g. "$2a$10$...".
+func (b Bcrypt) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), b.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Recognizes reports whether the hash has one of the bcrypt prefixes $2a$, $2b$ or $2y$.
+func (Bcrypt) Recognizes(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+// Verify compares a password with a bcrypt hash.
+func (Bcrypt) Verify(hash string, password string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return errorx.ErrInvalidCredentials
+	}
+	return fmt.Errorf("error comparing passwords: %w", err)
+}
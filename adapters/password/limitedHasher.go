@@ -0,0 +1,130 @@
+package password
+
+import (
+	"runtime"
+	"sync/atomic"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/ports/security"
+)
+
+// LimitConfig bounds the password operations running and waiting at the same time.
+type LimitConfig struct {
+	// MaxConcurrent is the number of hashes and verifications running at once, usually the number of CPUs.
+	MaxConcurrent int
+	// MaxQueue is the number of operations that may wait for a slot. Further operations are shed at once.
+	MaxQueue int
+	// QueueTimeout is how long an operation waits for a slot before it is shed.
+	QueueTimeout time.Duration
+}
+
+// DefaultLimitConfig returns a configuration running one operation per CPU, with up to 64 waiting for at most 2 seconds.
+func DefaultLimitConfig() LimitConfig {
+	return LimitConfig{MaxConcurrent: runtime.GOMAXPROCS(0), MaxQueue: 64, QueueTimeout: 2 * time.Second}
+}
+
+// LimitedHasher bounds the concurrency of a password hasher. Hashing is deliberately expensive, so
+// without a bound a login flood occupies every CPU and starves all other requests; with it, excess
+// operations wait in a bounded queue and are shed with errorx.ErrOverloaded when the queue is full
+// or they waited too long. It implements the PasswordHasherPort interface from the security ports package.
+type LimitedHasher struct {
+	next     security.PasswordHasherPort
+	config   LimitConfig
+	slots    chan struct{}
+	waiting  atomic.Int64
+	rejected atomic.Uint64
+}
+
+// NewLimitedHasher creates a new LimitedHasher.
+//
+// Parameters:
+//   - next: The limited implementation of PasswordHasherPort
+//   - config: The concurrency, queue length and queue timeout
+//
+// Returns:
+//   - *LimitedHasher: A pointer to the newly created LimitedHasher
+func NewLimitedHasher(next security.PasswordHasherPort, config LimitConfig) *LimitedHasher {
+	return &LimitedHasher{next: next, config: config, slots: make(chan struct{}, max(config.MaxConcurrent, 1))}
+}
+
+// Hash hashes a password once a slot is free.
+//
+// Returns:
+//   - domain.HashedPassword: The hash
+//   - error: errorx.ErrOverloaded if the operation was shed, else the error of the limited hasher
+func (lh *LimitedHasher) Hash(password string) (domain.HashedPassword, error) {
+	if err := lh.acquire(); err != nil {
+		return domain.HashedPassword{}, err
+	}
+	defer lh.release()
+	return lh.next.Hash(password)
+}
+
+// Verify compares a password with a hash once a slot is free.
+//
+// Returns:
+//   - error: errorx.ErrOverloaded if the operation was shed, else the result of the limited hasher
+func (lh *LimitedHasher) Verify(hash domain.HashedPassword, password string) error {
+	if err := lh.acquire(); err != nil {
+		return err
+	}
+	defer lh.release()
+	return lh.next.Verify(hash, password)
+}
+
+// Algorithm returns the algorithm of the limited hasher.
+func (lh *LimitedHasher) Algorithm() string {
+	return lh.next.Algorithm()
+}
+
+// NeedsRehash asks the limited hasher without taking a slot, as no hash is computed.
+func (lh *LimitedHasher) NeedsRehash(hash domain.HashedPassword) bool {
+	return lh.next.NeedsRehash(hash)
+}
+
+// Running returns the number of operations holding a slot.
+func (lh *LimitedHasher) Running() int {
+	return len(lh.slots)
+}
+
+// Waiting returns the number of operations queued for a slot.
+func (lh *LimitedHasher) Waiting() int {
+	return int(lh.waiting.Load())
+}
+
+// Rejected returns the number of operations shed since the start.
+func (lh *LimitedHasher) Rejected() uint64 {
+	return lh.rejected.Load()
+}
+
+// acquire takes a slot, waiting in the queue if all are taken.
+func (lh *LimitedHasher) acquire() error {
+	select {
+	case lh.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if lh.waiting.Add(1) > int64(lh.config.MaxQueue) {
+		lh.waiting.Add(-1)
+		lh.rejected.Add(1)
+		return errorx.ErrOverloaded.Detailf("too many password operations queued")
+	}
+	defer lh.waiting.Add(-1)
+
+	timer := time.NewTimer(lh.config.QueueTimeout)
+	defer timer.Stop()
+	select {
+	case lh.slots <- struct{}{}:
+		return nil
+	case <-timer.C:
+		lh.rejected.Add(1)
+		return errorx.ErrOverloaded.Detailf("timed out waiting for a password operation")
+	}
+}
+
+// release frees a slot.
+func (lh *LimitedHasher) release() {
+	<-lh.slots
+}
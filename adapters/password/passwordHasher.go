@@ -0,0 +1,106 @@
+// Package password provides the password hashing algorithms behind the PasswordHasherPort.
+package password
+
+import (
+	"crypto/rand"
+	"fmt"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// saltBytes is the length of the random salt of argon2id and scrypt hashes.
+const saltBytes = 16
+
+// Algorithm is a password hashing algorithm with its cost parameters.
+type Algorithm interface {
+	// Name returns the name of the algorithm, e.g. "bcrypt".
+	Name() string
+	// Hash hashes a password with a random salt and encodes the result in modular crypt format.
+	Hash(password string) (string, error)
+	// Recognizes reports whether a hash was created by the algorithm.
+	Recognizes(hash string) bool
+	// Verify compares a password with a hash of the algorithm. It returns errorx.ErrInvalidCredentials
+	// if the password does not match.
+	Verify(hash string, password string) error
+}
+
+// PasswordHasher hashes passwords with the configured algorithm and verifies hashes of all
+// supported algorithms. It implements the PasswordHasherPort interface from the security ports package.
+//
+// Switching the algorithm therefore does not lock out existing users: their hashes keep verifying
+// with the algorithm they were created with until they are rehashed at the next login.
+type PasswordHasher struct {
+	primary    Algorithm
+	algorithms []Algorithm
+}
+
+// NewPasswordHasher creates a new PasswordHasher.
+//
+// Parameters:
+//   - primary: The algorithm new hashes are created with
+//   - others: Further algorithms whose hashes are verified
+//
+// Returns:
+//   - *PasswordHasher: A pointer to the newly created PasswordHasher
+func NewPasswordHasher(primary Algorithm, others ...Algorithm) *PasswordHasher {
+	return &PasswordHasher{primary, append([]Algorithm{primary}, others...)}
+}
+
+// Hash hashes a plain text password with the primary algorithm.
+//
+// Parameters:
+//   - password: The plain text password
+//
+// Returns:
+//   - domain.HashedPassword: The hash
+//   - error: A wrapped error if hashing fails
+func (ph *PasswordHasher) Hash(password string) (domain.HashedPassword, error) {
+	hash, err := ph.primary.Hash(password)
+	if err != nil {
+		return domain.HashedPassword{}, fmt.Errorf("failed to hash password with %s: %w", ph.primary.Name(), err)
+	}
+	return domain.NewHashedPassword(hash)
+}
+
+// Verify compares a plain text password with a hash, using the algorithm that created the hash.
+//
+// Parameters:
+//   - hash: The stored hash
+//   - password: The plain text password
+//
+// Returns:
+//   - error: errorx.ErrInvalidCredentials if the password does not match, errorx.ErrInvalidPasswordHash if
+//     no algorithm recognizes the hash, a wrapped error if the hash cannot be compared, nil if the password is correct
+func (ph *PasswordHasher) Verify(hash domain.HashedPassword, password string) error {
+	for _, algorithm := range ph.algorithms {
+		if algorithm.Recognizes(hash.String()) {
+			return algorithm.Verify(hash.String(), password)
+		}
+	}
+	return errorx.ErrInvalidPasswordHash.Detailf("unsupported hash format %q", hash.Algorithm())
+}
+
+// Algorithm returns the name of the primary algorithm.
+func (ph *PasswordHasher) Algorithm() string {
+	return ph.primary.Name()
+}
+
+// NeedsRehash reports whether a hash was not created by the primary algorithm.
+//
+// Parameters:
+//   - hash: The stored hash
+//
+// Returns:
+//   - bool: true if the hash should be replaced with one of the primary algorithm
+func (ph *PasswordHasher) NeedsRehash(hash domain.HashedPassword) bool {
+	return !ph.primary.Recognizes(hash.String())
+}
+
+// newSalt returns a random salt.
+func newSalt() ([]byte, error) {
+	salt := make([]byte, saltBytes)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	return salt, nil
+}
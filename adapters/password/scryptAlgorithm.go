@@ -0,0 +1,95 @@
+package password
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/scrypt"
+	"strings"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// scryptKeyBytes is the length of the derived key of scrypt hashes.
+const scryptKeyBytes = 32
+
+// Scrypt hashes passwords with scrypt, encoded as "$scrypt$ln=<log2 N>,r=<r>,p=<p>$<salt>$<key>".
+type Scrypt struct {
+	logN uint8
+	r    int
+	p    int
+}
+
+// NewScrypt creates a scrypt algorithm.
+//
+// Parameters:
+//   - logN: The base 2 logarithm of the CPU/memory cost N, between 1 and 30
+//   - r: The block size, at least 1
+//   - p: The parallelization, at least 1 and r*p below 2^30
+//
+// Returns:
+//   - Scrypt: The algorithm
+//   - error: An error if a parameter is out of bounds
+func NewScrypt(logN uint8, r int, p int) (Scrypt, error) {
+	if logN < 1 || logN > 30 || r < 1 || p < 1 || r*p >= 1<<30 {
+		return Scrypt{}, errors.New("scrypt needs 1 <= log2 N <= 30, r >= 1, p >= 1 and r*p < 2^30")
+	}
+	return Scrypt{logN, r, p}, nil
+}
+
+// Name returns "scrypt".
+func (Scrypt) Name() string {
+	return "scrypt"
+}
+
+// Hash hashes a password with a random salt.
+func (s Scrypt) Hash(password string) (string, error) {
+	salt, err := newSalt()
+	if err != nil {
+		return "", err
+	}
+	key, err := scrypt.Key([]byte(password), salt, 1<<s.logN, s.r, s.p, scryptKeyBytes)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("$scrypt$ln=%d,r=%d,p=%d$%s$%s", s.logN, s.r, s.p,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Recognizes reports whether the hash has the prefix $scrypt$.
+func (Scrypt) Recognizes(hash string) bool {
+	return strings.HasPrefix(hash, "$scrypt$")
+}
+
+// Verify compares a password with a scrypt hash, using the parameters encoded in the hash.
+func (Scrypt) Verify(hash string, password string) error {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 5 {
+		return errorx.ErrInvalidPasswordHash.Detailf("malformed scrypt hash")
+	}
+	var logN uint8
+	var r, p int
+	if _, err := fmt.Sscanf(parts[2], "ln=%d,r=%d,p=%d", &logN, &r, &p); err != nil {
+		return errorx.ErrInvalidPasswordHash.Detailf("malformed scrypt parameters: %v", err)
+	}
+	if _, err := NewScrypt(logN, r, p); err != nil {
+		return errorx.ErrInvalidPasswordHash.Detailf("%v", err)
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return errorx.ErrInvalidPasswordHash.Detailf("malformed scrypt salt: %v", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil || len(key) == 0 {
+		return errorx.ErrInvalidPasswordHash.Detailf("malformed scrypt key")
+	}
+
+	candidate, err := scrypt.Key([]byte(password), salt, 1<<logN, r, p, len(key))
+	if err != nil {
+		return fmt.Errorf("error comparing passwords: %w", err)
+	}
+	if subtle.ConstantTimeCompare(candidate, key) != 1 {
+		return errorx.ErrInvalidCredentials
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+// Package persistence provides functionality for audit data persistence using MongoDB.
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/requestid"
+)
+
+// maxAppendAttempts bounds the retries when concurrent appends race for the same sequence number.
+const maxAppendAttempts = 5
+
+// credentialEventDocument is the MongoDB representation of a domain.CredentialEvent.
+type credentialEventDocument struct {
+	Username   string            `bson:"username"`
+	Sequence   int64             `bson:"sequence"`
+	Type       string            `bson:"type"`
+	OccurredAt time.Time         `bson:"occurredAt"`
+	Details    map[string]string `bson:"details,omitempty"`
+}
+
+// CredentialEventMongoAdapter stores credential events in an append-only MongoDB collection.
+type CredentialEventMongoAdapter struct {
+	collection *mongo.Collection
+}
+
+// NewCredentialEventMongoAdapter creates and initializes a new CredentialEventMongoAdapter.
+//
+// The adapter uses a "credential_events" collection within the specified database. A unique
+// index on username and sequence guarantees that no two events share a position in a stream.
+//
+// Parameters:
+//   - client: A connected MongoDB client
+//   - database: Name of the database to use
+//
+// Returns:
+//   - *CredentialEventMongoAdapter: A pointer to the newly created adapter
+//   - error: An error if the index cannot be created
+func NewCredentialEventMongoAdapter(client *mongo.Client, database string) (*CredentialEventMongoAdapter, error) {
+	collection := client.Database(database).Collection("credential_events")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "username", Value: 1}, {Key: "sequence", Value: 1}},
+		Options: options.Index().SetName("username_1_sequence_1").SetUnique(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create credential event index: %w", err)
+	}
+
+	return &CredentialEventMongoAdapter{collection}, nil
+}
+
+// AppendCredentialEvent appends an event to the end of the user's credential event stream.
+//
+// The event's sequence number is assigned by the adapter. Events are only ever inserted,
+// never updated or deleted.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - event: The event to append
+//
+// Returns:
+//   - error: An error if the event cannot be stored
+func (c *CredentialEventMongoAdapter) AppendCredentialEvent(ctx context.Context, event domain.CredentialEvent) error {
+	for attempt := 0; attempt < maxAppendAttempts; attempt++ {
+		last, err := c.lastSequence(ctx, event.Username)
+		if err != nil {
+			return err
+		}
+
+		insertOpts := options.InsertOne()
+		if comment, ok := requestComment(ctx); ok {
+			insertOpts.SetComment(comment)
+		}
+
+		_, err = c.collection.InsertOne(ctx, credentialEventDocument{
+			Username:   event.Username,
+			Sequence:   last + 1,
+			Type:       string(event.Type),
+			OccurredAt: event.OccurredAt,
+			Details:    event.Details,
+		}, insertOpts)
+		if err == nil {
+			return nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("failed to append credential event: %w", err)
+		}
+	}
+	return fmt.Errorf("failed to append credential event: too many concurrent writers")
+}
+
+// LoadCredentialEvents returns the complete credential event stream of a user in sequence order.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - username: The user whose events should be loaded
+//
+// Returns:
+//   - []domain.CredentialEvent: The user's events, empty if there are none
+//   - error: An error if the query fails
+func (c *CredentialEventMongoAdapter) LoadCredentialEvents(ctx context.Context, username string) ([]domain.CredentialEvent, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "sequence", Value: 1}})
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	cursor, err := c.collection.Find(ctx, bson.M{"username": username}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load credential events: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []credentialEventDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode credential events: %w", err)
+	}
+
+	events := make([]domain.CredentialEvent, 0, len(docs))
+	for _, doc := range docs {
+		events = append(events, domain.CredentialEvent{
+			Username:   doc.Username,
+			Sequence:   doc.Sequence,
+			Type:       domain.CredentialEventType(doc.Type),
+			OccurredAt: doc.OccurredAt,
+			Details:    doc.Details,
+		})
+	}
+	return events, nil
+}
+
+// PseudonymizeCredentialEvents replaces the username of all credential events of a user with a
+// pseudonym and removes their details. The events and their sequence numbers are kept, so the
+// stream stays complete.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - username: The username of the user
+//   - pseudonym: The pseudonym replacing the username
+//
+// Returns:
+//   - int64: The number of pseudonymized events
+//   - error: A wrapped database error
+func (c *CredentialEventMongoAdapter) PseudonymizeCredentialEvents(ctx context.Context, username string, pseudonym string) (int64, error) {
+	update := bson.M{"$set": bson.M{"username": pseudonym}, "$unset": bson.M{"details": ""}}
+	res, err := c.collection.UpdateMany(ctx, bson.M{"username": username}, update)
+	if err != nil {
+		return 0, fmt.Errorf("failed to pseudonymize credential events: %w", err)
+	}
+	return res.ModifiedCount, nil
+}
+
+// requestComment returns the request id of ctx to be attached as comment to MongoDB operations.
+func requestComment(ctx context.Context) (string, bool) {
+	id := requestid.FromContext(ctx)
+	return id, id != ""
+}
+
+// lastSequence returns the highest sequence number in the user's stream, or 0 for an empty stream.
+func (c *CredentialEventMongoAdapter) lastSequence(ctx context.Context, username string) (int64, error) {
+	var last credentialEventDocument
+	opts := options.FindOne().SetSort(bson.D{{Key: "sequence", Value: -1}}).SetProjection(bson.M{"sequence": 1})
+	err := c.collection.FindOne(ctx, bson.M{"username": username}, opts).Decode(&last)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read credential event sequence: %w", err)
+	}
+	return last.Sequence, nil
+}
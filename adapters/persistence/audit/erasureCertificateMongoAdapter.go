@@ -0,0 +1,121 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// erasedRecordsDocument is the MongoDB representation of a domain.ErasedRecords.
+type erasedRecordsDocument struct {
+	Record string `bson:"record"`
+	Action string `bson:"action"`
+	Count  int64  `bson:"count"`
+}
+
+// erasureCertificateDocument is the MongoDB representation of a domain.ErasureCertificate, keyed by the certificate id.
+type erasureCertificateDocument struct {
+	ID                  string                  `bson:"_id"`
+	UserID              string                  `bson:"userId"`
+	TenantID            string                  `bson:"tenantId"`
+	Pseudonym           string                  `bson:"pseudonym"`
+	RequestedBy         string                  `bson:"requestedBy"`
+	LegalHoldOverridden bool                    `bson:"legalHoldOverridden"`
+	Records             []erasedRecordsDocument `bson:"records"`
+	ErasedAt            time.Time               `bson:"erasedAt"`
+}
+
+// ErasureCertificateMongoAdapter stores erasure certificates in MongoDB. Certificates are never
+// changed or removed, as they prove that an erasure took place.
+// It implements the ErasureCertificatePersistencePort interface.
+type ErasureCertificateMongoAdapter struct {
+	collection *mongo.Collection
+}
+
+// NewErasureCertificateMongoAdapter creates a new ErasureCertificateMongoAdapter using the
+// "erasure_certificates" collection of the specified database.
+//
+// Parameters:
+//   - client: A connected MongoDB client
+//   - database: Name of the database to use
+//
+// Returns:
+//   - *ErasureCertificateMongoAdapter: A pointer to the newly created adapter
+func NewErasureCertificateMongoAdapter(client *mongo.Client, database string) *ErasureCertificateMongoAdapter {
+	return &ErasureCertificateMongoAdapter{client.Database(database).Collection("erasure_certificates")}
+}
+
+// SaveErasureCertificate stores a new erasure certificate.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - certificate: The certificate to store
+//
+// Returns:
+//   - error: A wrapped database error
+func (ea *ErasureCertificateMongoAdapter) SaveErasureCertificate(ctx context.Context, certificate domain.ErasureCertificate) error {
+	doc := erasureCertificateDocument{
+		ID:                  certificate.ID,
+		UserID:              certificate.UserID,
+		TenantID:            certificate.TenantID,
+		Pseudonym:           certificate.Pseudonym,
+		RequestedBy:         certificate.RequestedBy,
+		LegalHoldOverridden: certificate.LegalHoldOverridden,
+		Records:             make([]erasedRecordsDocument, 0, len(certificate.Records)),
+		ErasedAt:            certificate.ErasedAt,
+	}
+	for _, records := range certificate.Records {
+		doc.Records = append(doc.Records, erasedRecordsDocument{Record: records.Record, Action: string(records.Action), Count: records.Count})
+	}
+
+	if _, err := ea.collection.InsertOne(ctx, doc); err != nil {
+		return fmt.Errorf("failed to save erasure certificate: %w", err)
+	}
+	return nil
+}
+
+// FindErasureCertificate returns a single erasure certificate.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The id of the certificate
+//
+// Returns:
+//   - domain.ErasureCertificate: The certificate
+//   - error: errorx.ErrErasureCertificateNotFound if no certificate is stored under the id, or a wrapped database error
+func (ea *ErasureCertificateMongoAdapter) FindErasureCertificate(ctx context.Context, id string) (domain.ErasureCertificate, error) {
+	opts := options.FindOne()
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	var doc erasureCertificateDocument
+	err := ea.collection.FindOne(ctx, bson.M{"_id": id}, opts).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.ErasureCertificate{}, errorx.ErrErasureCertificateNotFound
+	}
+	if err != nil {
+		return domain.ErasureCertificate{}, fmt.Errorf("failed to find erasure certificate: %w", err)
+	}
+
+	certificate := domain.ErasureCertificate{
+		ID:                  doc.ID,
+		UserID:              doc.UserID,
+		TenantID:            doc.TenantID,
+		Pseudonym:           doc.Pseudonym,
+		RequestedBy:         doc.RequestedBy,
+		LegalHoldOverridden: doc.LegalHoldOverridden,
+		Records:             make([]domain.ErasedRecords, 0, len(doc.Records)),
+		ErasedAt:            doc.ErasedAt,
+	}
+	for _, records := range doc.Records {
+		certificate.Records = append(certificate.Records, domain.ErasedRecords{Record: records.Record, Action: domain.ErasureAction(records.Action), Count: records.Count})
+	}
+	return certificate, nil
+}
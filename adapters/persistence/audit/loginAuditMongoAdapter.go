@@ -0,0 +1,259 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/tenant"
+)
+
+// loginRecordDocument is the MongoDB representation of a domain.LoginRecord. Records stored before
+// tenants were recorded carry no tenant and belong to the default tenant.
+type loginRecordDocument struct {
+	TenantID  string    `bson:"tenantId,omitempty"`
+	Username  string    `bson:"username"`
+	Outcome   string    `bson:"outcome"`
+	Reason    string    `bson:"reason,omitempty"`
+	IPAddress string    `bson:"ipAddress,omitempty"`
+	UserAgent string    `bson:"userAgent,omitempty"`
+	Country   string    `bson:"country,omitempty"`
+	At        time.Time `bson:"at"`
+}
+
+// LoginAuditMongoAdapter stores the audit records of authentication attempts in MongoDB.
+// It implements the LoginAuditStorePort interface.
+//
+// Queries only see the records of the tenant of the request; requests without a tenant, such as
+// background jobs, see the records of all tenants.
+type LoginAuditMongoAdapter struct {
+	collection *mongo.Collection
+}
+
+// NewLoginAuditMongoAdapter creates and initializes a new LoginAuditMongoAdapter.
+//
+// The adapter uses a "login_attempts" collection within the specified database, indexed for
+// lookups by username and by IP address. A TTL index removes records after the retention period.
+//
+// Parameters:
+//   - client: A connected MongoDB client
+//   - database: Name of the database to use
+//   - retention: How long records are kept
+//
+// Returns:
+//   - *LoginAuditMongoAdapter: A pointer to the newly created adapter
+//   - error: An error if the indexes cannot be created
+func NewLoginAuditMongoAdapter(client *mongo.Client, database string, retention time.Duration) (*LoginAuditMongoAdapter, error) {
+	collection := client.Database(database).Collection("login_attempts")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "username", Value: 1}, {Key: "at", Value: -1}}, Options: options.Index().SetName("username_1_at_-1")},
+		{Keys: bson.D{{Key: "ipAddress", Value: 1}, {Key: "at", Value: -1}}, Options: options.Index().SetName("ipAddress_1_at_-1")},
+		{Keys: bson.D{{Key: "at", Value: 1}}, Options: options.Index().SetName("at_ttl").SetExpireAfterSeconds(int32(retention.Seconds()))},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create login attempt indexes: %w", err)
+	}
+
+	return &LoginAuditMongoAdapter{collection}, nil
+}
+
+// AppendLoginRecord stores the record of an authentication attempt.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - record: The record to store
+//
+// Returns:
+//   - error: A wrapped database error
+func (la *LoginAuditMongoAdapter) AppendLoginRecord(ctx context.Context, record domain.LoginRecord) error {
+	opts := options.InsertOne()
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	_, err := la.collection.InsertOne(ctx, loginRecordDocument{
+		TenantID:  record.TenantID,
+		Username:  record.Username.String(),
+		Outcome:   string(record.Outcome),
+		Reason:    record.Reason,
+		IPAddress: record.IPAddress,
+		UserAgent: record.UserAgent,
+		Country:   record.Country,
+		At:        record.At,
+	}, opts)
+	if err != nil {
+		return fmt.Errorf("failed to append login record: %w", err)
+	}
+	return nil
+}
+
+// FindLoginRecords returns the login records of the tenant of the request matching a filter,
+// newest first.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - filter: The criteria the records have to match and the maximum number of records
+//
+// Returns:
+//   - []domain.LoginRecord: The matching records, empty if there are none
+//   - error: A wrapped database error
+func (la *LoginAuditMongoAdapter) FindLoginRecords(ctx context.Context, filter domain.LoginRecordFilter) ([]domain.LoginRecord, error) {
+	query := bson.M{}
+	if filter.Username.String() != "" {
+		query["username"] = filter.Username.String()
+	}
+	if filter.IPAddress != "" {
+		query["ipAddress"] = filter.IPAddress
+	}
+	if filter.Outcome != "" {
+		query["outcome"] = string(filter.Outcome)
+	}
+	at := bson.M{}
+	if !filter.Since.IsZero() {
+		at["$gte"] = filter.Since
+	}
+	if !filter.Until.IsZero() {
+		at["$lt"] = filter.Until
+	}
+	if len(at) > 0 {
+		query["at"] = at
+	}
+	query = inTenant(ctx, query)
+
+	opts := options.Find().SetSort(bson.D{{Key: "at", Value: -1}}).SetLimit(filter.Limit)
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	cursor, err := la.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find login records: %w", err)
+	}
+	var docs []loginRecordDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode login records: %w", err)
+	}
+
+	records := make([]domain.LoginRecord, 0, len(docs))
+	for _, doc := range docs {
+		tenantID := doc.TenantID
+		if tenantID == "" {
+			tenantID = domain.DefaultTenantID
+		}
+		records = append(records, domain.LoginRecord{
+			TenantID:  tenantID,
+			Username:  domain.RestoreUsername(doc.Username),
+			Outcome:   domain.LoginOutcome(doc.Outcome),
+			Reason:    doc.Reason,
+			IPAddress: doc.IPAddress,
+			UserAgent: doc.UserAgent,
+			Country:   doc.Country,
+			At:        doc.At,
+		})
+	}
+	return records, nil
+}
+
+// DeleteLoginRecords removes all login records of a username.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - username: The username the records were made for
+//
+// Returns:
+//   - int64: The number of removed records
+//   - error: A wrapped database error
+func (la *LoginAuditMongoAdapter) DeleteLoginRecords(ctx context.Context, username string) (int64, error) {
+	res, err := la.collection.DeleteMany(ctx, bson.M{"username": username})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete login records: %w", err)
+	}
+	return res.DeletedCount, nil
+}
+
+// AggregateLogins counts the successful and failed attempts of the tenant of the request in a
+// period, the failures per reason and the distinct usernames that logged in successfully, in a
+// single aggregation.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - period: The days to aggregate
+//
+// Returns:
+//   - domain.LoginStats: The aggregated attempts
+//   - error: A wrapped database error
+func (la *LoginAuditMongoAdapter) AggregateLogins(ctx context.Context, period domain.StatsPeriod) (domain.LoginStats, error) {
+	pipeline := bson.A{
+		bson.M{"$match": inTenant(ctx, bson.M{"at": bson.M{"$gte": period.From, "$lt": period.Until}})},
+		bson.M{"$facet": bson.M{
+			"outcomes": bson.A{
+				bson.M{"$group": bson.M{"_id": bson.M{"outcome": "$outcome", "reason": "$reason"}, "count": bson.M{"$sum": 1}}},
+			},
+			"activeUsers": bson.A{
+				bson.M{"$match": bson.M{"outcome": string(domain.LoginOutcomeSuccess)}},
+				bson.M{"$group": bson.M{"_id": "$username"}},
+				bson.M{"$count": "count"},
+			},
+		}},
+	}
+	opts := options.Aggregate()
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	cursor, err := la.collection.Aggregate(ctx, pipeline, opts)
+	if err != nil {
+		return domain.LoginStats{}, fmt.Errorf("failed to aggregate login records: %w", err)
+	}
+	var docs []struct {
+		Outcomes []struct {
+			ID struct {
+				Outcome string `bson:"outcome"`
+				Reason  string `bson:"reason"`
+			} `bson:"_id"`
+			Count int64 `bson:"count"`
+		} `bson:"outcomes"`
+		ActiveUsers []struct {
+			Count int64 `bson:"count"`
+		} `bson:"activeUsers"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return domain.LoginStats{}, fmt.Errorf("failed to decode login aggregation: %w", err)
+	}
+
+	stats := domain.LoginStats{FailuresByReason: map[string]int64{}}
+	if len(docs) == 0 {
+		return stats, nil
+	}
+	for _, outcome := range docs[0].Outcomes {
+		if domain.LoginOutcome(outcome.ID.Outcome) == domain.LoginOutcomeSuccess {
+			stats.Successes += outcome.Count
+			continue
+		}
+		stats.Failures += outcome.Count
+		stats.FailuresByReason[outcome.ID.Reason] += outcome.Count
+	}
+	if len(docs[0].ActiveUsers) > 0 {
+		stats.ActiveUsers = docs[0].ActiveUsers[0].Count
+	}
+	return stats, nil
+}
+
+// inTenant restricts a filter to the records of the tenant of the request. Records without a tenant
+// belong to the default tenant; requests without a tenant are not restricted.
+func inTenant(ctx context.Context, filter bson.M) bson.M {
+	switch id := tenant.FromContext(ctx); id {
+	case "":
+	case domain.DefaultTenantID:
+		filter["tenantId"] = bson.M{"$in": bson.A{id, nil}}
+	default:
+		filter["tenantId"] = id
+	}
+	return filter
+}
@@ -0,0 +1,215 @@
+// Package persistence provides functionality for consent persistence using MongoDB.
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/requestid"
+)
+
+// documentVersionDocument is the MongoDB representation of a domain.DocumentVersion,
+// keyed by "<document>:<version>" so a version can only be published once.
+type documentVersionDocument struct {
+	ID          string    `bson:"_id"`
+	Document    string    `bson:"document"`
+	Version     string    `bson:"version"`
+	PublishedAt time.Time `bson:"publishedAt"`
+}
+
+// consentDocument is the MongoDB representation of a domain.Consent.
+type consentDocument struct {
+	UserID     string    `bson:"userId"`
+	Username   string    `bson:"username"`
+	Document   string    `bson:"document"`
+	Version    string    `bson:"version"`
+	AcceptedAt time.Time `bson:"acceptedAt"`
+	IPAddress  string    `bson:"ipAddress,omitempty"`
+}
+
+// ConsentMongoAdapter stores published document versions and the consents of users in MongoDB.
+// It implements the ConsentPersistencePort interface.
+type ConsentMongoAdapter struct {
+	documents *mongo.Collection
+	consents  *mongo.Collection
+}
+
+// NewConsentMongoAdapter creates and initializes a new ConsentMongoAdapter.
+//
+// The adapter uses a "consentDocuments" and a "consents" collection within the specified database.
+// An index on the user keeps reading the consent history of a user fast.
+//
+// Parameters:
+//   - client: A connected MongoDB client
+//   - database: Name of the database to use
+//
+// Returns:
+//   - *ConsentMongoAdapter: A pointer to the newly created adapter
+//   - error: An error if the indexes cannot be created
+func NewConsentMongoAdapter(client *mongo.Client, database string) (*ConsentMongoAdapter, error) {
+	db := client.Database(database)
+	consents := db.Collection("consents")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := consents.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "acceptedAt", Value: -1}},
+		Options: options.Index().SetName("userId_1_acceptedAt_-1"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consent indexes: %w", err)
+	}
+
+	return &ConsentMongoAdapter{db.Collection("consentDocuments"), consents}, nil
+}
+
+// SaveDocumentVersion stores a published document version.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - version: The published version
+//
+// Returns:
+//   - error: errorx.ErrInvalidConsent if the version has been published before, or a wrapped database error
+func (ca *ConsentMongoAdapter) SaveDocumentVersion(ctx context.Context, version domain.DocumentVersion) error {
+	doc := documentVersionDocument{
+		ID:          string(version.Document) + ":" + version.Version,
+		Document:    string(version.Document),
+		Version:     version.Version,
+		PublishedAt: version.PublishedAt,
+	}
+	if _, err := ca.documents.InsertOne(ctx, doc); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return errorx.ErrInvalidConsent.Detailf("%s version %q has been published before", version.Document, version.Version)
+		}
+		return fmt.Errorf("failed to save document version: %w", err)
+	}
+	return nil
+}
+
+// FindCurrentDocumentVersions returns the most recently published version of every document.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//
+// Returns:
+//   - []domain.DocumentVersion: The current versions, one per published document
+//   - error: A wrapped database error
+func (ca *ConsentMongoAdapter) FindCurrentDocumentVersions(ctx context.Context) ([]domain.DocumentVersion, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "publishedAt", Value: -1}})
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	cursor, err := ca.documents.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find document versions: %w", err)
+	}
+	var docs []documentVersionDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode document versions: %w", err)
+	}
+
+	var current []domain.DocumentVersion
+	seen := map[string]bool{}
+	for _, doc := range docs {
+		if seen[doc.Document] {
+			continue
+		}
+		seen[doc.Document] = true
+		current = append(current, domain.DocumentVersion{Document: domain.ConsentDocument(doc.Document), Version: doc.Version, PublishedAt: doc.PublishedAt})
+	}
+	return current, nil
+}
+
+// AppendConsents stores consents. Consents are never changed afterwards.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - consents: The consents to store
+//
+// Returns:
+//   - error: A wrapped database error
+func (ca *ConsentMongoAdapter) AppendConsents(ctx context.Context, consents []domain.Consent) error {
+	docs := make([]any, 0, len(consents))
+	for _, consent := range consents {
+		docs = append(docs, consentDocument{
+			UserID:     consent.UserID,
+			Username:   consent.Username.String(),
+			Document:   string(consent.Document),
+			Version:    consent.Version,
+			AcceptedAt: consent.AcceptedAt,
+			IPAddress:  consent.IPAddress,
+		})
+	}
+	if _, err := ca.consents.InsertMany(ctx, docs); err != nil {
+		return fmt.Errorf("failed to append consents: %w", err)
+	}
+	return nil
+}
+
+// FindConsents returns the consents of a user, the most recent first.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - userID: The id of the user
+//
+// Returns:
+//   - []domain.Consent: The consents
+//   - error: A wrapped database error
+func (ca *ConsentMongoAdapter) FindConsents(ctx context.Context, userID string) ([]domain.Consent, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "acceptedAt", Value: -1}})
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	cursor, err := ca.consents.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find consents: %w", err)
+	}
+	var docs []consentDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode consents: %w", err)
+	}
+
+	consents := make([]domain.Consent, 0, len(docs))
+	for _, doc := range docs {
+		consents = append(consents, domain.Consent{
+			UserID:     doc.UserID,
+			Username:   domain.RestoreUsername(doc.Username),
+			Document:   domain.ConsentDocument(doc.Document),
+			Version:    doc.Version,
+			AcceptedAt: doc.AcceptedAt,
+			IPAddress:  doc.IPAddress,
+		})
+	}
+	return consents, nil
+}
+
+// DeleteConsents removes all recorded consents of a user.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - userID: The id of the user
+//
+// Returns:
+//   - int64: The number of removed consents
+//   - error: A wrapped database error
+func (ca *ConsentMongoAdapter) DeleteConsents(ctx context.Context, userID string) (int64, error) {
+	res, err := ca.consents.DeleteMany(ctx, bson.M{"userId": userID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete consents: %w", err)
+	}
+	return res.DeletedCount, nil
+}
+
+// requestComment returns the request id of ctx to be attached as comment to MongoDB operations.
+func requestComment(ctx context.Context) (string, bool) {
+	id := requestid.FromContext(ctx)
+	return id, id != ""
+}
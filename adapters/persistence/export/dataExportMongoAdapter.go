@@ -0,0 +1,316 @@
+// Package persistence provides functionality for data export persistence using MongoDB.
+package persistence
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/requestid"
+)
+
+// dataExportDocument is the MongoDB representation of a domain.DataExport, keyed by the export id.
+type dataExportDocument struct {
+	ID          string    `bson:"_id"`
+	UserID      string    `bson:"userId"`
+	Username    string    `bson:"username"`
+	Status      string    `bson:"status"`
+	RequestedAt time.Time `bson:"requestedAt"`
+	CompletedAt time.Time `bson:"completedAt,omitempty"`
+	ExpiresAt   time.Time `bson:"expiresAt"`
+	LeaseUntil  time.Time `bson:"leaseUntil"`
+}
+
+// toDomain converts the document into a domain.DataExport.
+func (d dataExportDocument) toDomain() domain.DataExport {
+	return domain.DataExport{
+		ID:          d.ID,
+		UserID:      d.UserID,
+		Username:    domain.RestoreUsername(d.Username),
+		Status:      domain.DataExportStatus(d.Status),
+		RequestedAt: d.RequestedAt,
+		CompletedAt: d.CompletedAt,
+		ExpiresAt:   d.ExpiresAt,
+	}
+}
+
+// DataExportMongoAdapter stores data exports in MongoDB and their archives in GridFS, as archives can
+// exceed the 16 MB limit of a document.
+// It implements the DataExportPersistencePort interface.
+type DataExportMongoAdapter struct {
+	collection *mongo.Collection
+	database   *mongo.Database
+}
+
+// NewDataExportMongoAdapter creates and initializes a new DataExportMongoAdapter.
+//
+// The adapter uses a "data_exports" collection and a "data_export_archives" GridFS bucket within the
+// specified database. A TTL index removes exports once they expired; their archives are removed by
+// DeleteExpiredDataExportArchives.
+//
+// Parameters:
+//   - client: A connected MongoDB client
+//   - database: Name of the database to use
+//
+// Returns:
+//   - *DataExportMongoAdapter: A pointer to the newly created adapter
+//   - error: An error if the indexes cannot be created
+func NewDataExportMongoAdapter(client *mongo.Client, database string) (*DataExportMongoAdapter, error) {
+	db := client.Database(database)
+	collection := db.Collection("data_exports")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetName("expiresAt_ttl").SetExpireAfterSeconds(0)},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "requestedAt", Value: 1}}, Options: options.Index().SetName("status_requestedAt")},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create data export indexes: %w", err)
+	}
+	_, err = db.Collection(archiveBucket+".files").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "metadata.expiresAt", Value: 1}}, Options: options.Index().SetName("metadata.expiresAt_1")},
+		{Keys: bson.D{{Key: "metadata.userId", Value: 1}}, Options: options.Index().SetName("metadata.userId_1")},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create data export archive indexes: %w", err)
+	}
+
+	return &DataExportMongoAdapter{collection: collection, database: db}, nil
+}
+
+// archiveBucket is the name of the GridFS bucket holding the archives.
+const archiveBucket = "data_export_archives"
+
+// archiveMetadata is stored with each archive, so archives can be removed without their export.
+type archiveMetadata struct {
+	UserID    string    `bson:"userId"`
+	ExpiresAt time.Time `bson:"expiresAt"`
+}
+
+// SaveDataExport creates or replaces a data export. Replacing an export releases its lease.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - export: The export to store
+//
+// Returns:
+//   - error: A wrapped database error
+func (ea *DataExportMongoAdapter) SaveDataExport(ctx context.Context, export domain.DataExport) error {
+	doc := dataExportDocument{
+		ID:          export.ID,
+		UserID:      export.UserID,
+		Username:    export.Username.String(),
+		Status:      string(export.Status),
+		RequestedAt: export.RequestedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+
+	_, err := ea.collection.ReplaceOne(ctx, bson.M{"_id": doc.ID}, doc, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save data export: %w", err)
+	}
+	return nil
+}
+
+// FindDataExport returns a single data export without its archive.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The id of the export
+//
+// Returns:
+//   - domain.DataExport: The export
+//   - error: errorx.ErrDataExportNotFound if no export is stored under the id, or a wrapped database error
+func (ea *DataExportMongoAdapter) FindDataExport(ctx context.Context, id string) (domain.DataExport, error) {
+	opts := options.FindOne()
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	var doc dataExportDocument
+	err := ea.collection.FindOne(ctx, bson.M{"_id": id}, opts).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.DataExport{}, errorx.ErrDataExportNotFound
+	}
+	if err != nil {
+		return domain.DataExport{}, fmt.Errorf("failed to find data export: %w", err)
+	}
+	return doc.toDomain(), nil
+}
+
+// ClaimPendingDataExport leases the oldest pending export whose lease, if any, has expired.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - lease: How long the export is reserved for the caller
+//
+// Returns:
+//   - domain.DataExport: The leased export
+//   - error: errorx.ErrDataExportNotFound if no export is waiting, or a wrapped database error
+func (ea *DataExportMongoAdapter) ClaimPendingDataExport(ctx context.Context, lease time.Duration) (domain.DataExport, error) {
+	now := time.Now()
+	claimable := bson.M{"status": string(domain.DataExportPending), "leaseUntil": bson.M{"$not": bson.M{"$gt": now}}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "requestedAt", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var doc dataExportDocument
+	err := ea.collection.FindOneAndUpdate(ctx, claimable, bson.M{"$set": bson.M{"leaseUntil": now.Add(lease)}}, opts).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.DataExport{}, errorx.ErrDataExportNotFound
+	}
+	if err != nil {
+		return domain.DataExport{}, fmt.Errorf("failed to claim pending data export: %w", err)
+	}
+	return doc.toDomain(), nil
+}
+
+// SaveDataExportArchive stores the archive of an export in GridFS under the id of the export,
+// replacing an archive stored by an earlier, interrupted attempt.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - export: The export the archive belongs to
+//   - archive: The archive
+//
+// Returns:
+//   - error: A wrapped database error
+func (ea *DataExportMongoAdapter) SaveDataExportArchive(ctx context.Context, export domain.DataExport, archive []byte) error {
+	bucket, err := ea.bucket(ctx)
+	if err != nil {
+		return err
+	}
+	if err := bucket.DeleteContext(ctx, export.ID); err != nil && !errors.Is(err, gridfs.ErrFileNotFound) {
+		return fmt.Errorf("failed to replace data export archive: %w", err)
+	}
+
+	metadata := archiveMetadata{UserID: export.UserID, ExpiresAt: export.ExpiresAt}
+	opts := options.GridFSUpload().SetMetadata(metadata)
+	if err := bucket.UploadFromStreamWithID(export.ID, export.ID+".json", bytes.NewReader(archive), opts); err != nil {
+		return fmt.Errorf("failed to save data export archive: %w", err)
+	}
+	return nil
+}
+
+// LoadDataExportArchive reads the archive of an export from GridFS.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The id of the export
+//
+// Returns:
+//   - []byte: The archive
+//   - error: errorx.ErrDataExportNotFound if no archive is stored for the export, or a wrapped database error
+func (ea *DataExportMongoAdapter) LoadDataExportArchive(ctx context.Context, id string) ([]byte, error) {
+	bucket, err := ea.bucket(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var archive bytes.Buffer
+	_, err = bucket.DownloadToStream(id, &archive)
+	if errors.Is(err, gridfs.ErrFileNotFound) {
+		return nil, errorx.ErrDataExportNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load data export archive: %w", err)
+	}
+	return archive.Bytes(), nil
+}
+
+// DeleteExpiredDataExportArchives removes the archives of the exports that expired before now.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - now: The current time
+//
+// Returns:
+//   - int64: The number of removed archives
+//   - error: A wrapped database error
+func (ea *DataExportMongoAdapter) DeleteExpiredDataExportArchives(ctx context.Context, now time.Time) (int64, error) {
+	return ea.deleteArchives(ctx, bson.M{"metadata.expiresAt": bson.M{"$lte": now}})
+}
+
+// DeleteDataExportsByUser removes all data exports of a user together with their archives.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - userID: The id of the user
+//
+// Returns:
+//   - int64: The number of removed exports
+//   - error: A wrapped database error
+func (ea *DataExportMongoAdapter) DeleteDataExportsByUser(ctx context.Context, userID string) (int64, error) {
+	if _, err := ea.deleteArchives(ctx, bson.M{"metadata.userId": userID}); err != nil {
+		return 0, err
+	}
+	res, err := ea.collection.DeleteMany(ctx, bson.M{"userId": userID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete data exports: %w", err)
+	}
+	return res.DeletedCount, nil
+}
+
+// deleteArchives removes the archives whose GridFS file matches filter.
+func (ea *DataExportMongoAdapter) deleteArchives(ctx context.Context, filter bson.M) (int64, error) {
+	bucket, err := ea.bucket(ctx)
+	if err != nil {
+		return 0, err
+	}
+	cursor, err := bucket.FindContext(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find data export archives: %w", err)
+	}
+	var files []struct {
+		ID string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &files); err != nil {
+		return 0, fmt.Errorf("failed to decode data export archives: %w", err)
+	}
+
+	var deleted int64
+	for _, file := range files {
+		err := bucket.DeleteContext(ctx, file.ID)
+		if errors.Is(err, gridfs.ErrFileNotFound) {
+			continue
+		}
+		if err != nil {
+			return deleted, fmt.Errorf("failed to delete data export archive: %w", err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// bucket opens the archive bucket. GridFS uploads and downloads are bounded by deadlines instead of
+// a context, so a bucket is opened per operation with the deadline of ctx.
+func (ea *DataExportMongoAdapter) bucket(ctx context.Context) (*gridfs.Bucket, error) {
+	bucket, err := gridfs.NewBucket(ea.database, options.GridFSBucket().SetName(archiveBucket))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open data export archives: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := bucket.SetReadDeadline(deadline); err != nil {
+			return nil, fmt.Errorf("failed to open data export archives: %w", err)
+		}
+		if err := bucket.SetWriteDeadline(deadline); err != nil {
+			return nil, fmt.Errorf("failed to open data export archives: %w", err)
+		}
+	}
+	return bucket, nil
+}
+
+// requestComment returns the request id of ctx to be attached as comment to MongoDB operations.
+func requestComment(ctx context.Context) (string, bool) {
+	id := requestid.FromContext(ctx)
+	return id, id != ""
+}
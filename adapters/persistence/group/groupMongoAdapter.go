@@ -0,0 +1,280 @@
+// Package persistence provides functionality for group persistence using MongoDB.
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/requestid"
+	"user-auth-hexagonal-architecture/internal/tenant"
+)
+
+// groupDocument is the MongoDB representation of a domain.Group, keyed by groupKey. Groups stored
+// before tenants existed have neither tenant nor name and belong to the default tenant.
+type groupDocument struct {
+	Key       string    `bson:"_id"`
+	TenantID  string    `bson:"tenantId,omitempty"`
+	Name      string    `bson:"name,omitempty"`
+	Roles     []string  `bson:"roles"`
+	Members   []string  `bson:"members"`
+	UpdatedAt time.Time `bson:"updatedAt"`
+}
+
+// GroupMongoAdapter stores groups and their members in MongoDB.
+// It implements the GroupPersistencePort interface.
+//
+// Groups are looked up by name within the tenant of the request. Requests without a tenant, such
+// as background jobs, see the groups of the default tenant by name and all groups when listing.
+type GroupMongoAdapter struct {
+	collection *mongo.Collection
+}
+
+// NewGroupMongoAdapter creates and initializes a new GroupMongoAdapter.
+//
+// The adapter uses a "groups" collection within the specified database. An index on the members
+// keeps resolving the groups of a user at login fast.
+//
+// Parameters:
+//   - client: A connected MongoDB client
+//   - database: Name of the database to use
+//
+// Returns:
+//   - *GroupMongoAdapter: A pointer to the newly created adapter
+//   - error: An error if the index cannot be created
+func NewGroupMongoAdapter(client *mongo.Client, database string) (*GroupMongoAdapter, error) {
+	collection := client.Database(database).Collection("groups")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "members", Value: 1}},
+		Options: options.Index().SetName("members_1"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create group index: %w", err)
+	}
+
+	return &GroupMongoAdapter{collection}, nil
+}
+
+// FindGroups returns the groups of the tenant of the request.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//
+// Returns:
+//   - []domain.Group: The groups
+//   - error: A wrapped database error
+func (ga *GroupMongoAdapter) FindGroups(ctx context.Context) ([]domain.Group, error) {
+	return ga.find(ctx, inTenant(ctx, bson.M{}))
+}
+
+// FindGroupsByMember returns the groups the user is a member of. Groups only hold users of their
+// tenant, so the groups are not filtered by the tenant of the request.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - userID: The id of the user
+//
+// Returns:
+//   - []domain.Group: The groups of the user
+//   - error: A wrapped database error
+func (ga *GroupMongoAdapter) FindGroupsByMember(ctx context.Context, userID string) ([]domain.Group, error) {
+	return ga.find(ctx, bson.M{"members": userID})
+}
+
+// FindGroup returns a single group.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - name: The name of the group
+//
+// Returns:
+//   - domain.Group: The group
+//   - error: errorx.ErrGroupNotFound if the group does not exist, or a wrapped database error
+func (ga *GroupMongoAdapter) FindGroup(ctx context.Context, name string) (domain.Group, error) {
+	opts := options.FindOne()
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	var doc groupDocument
+	err := ga.collection.FindOne(ctx, bson.M{"_id": groupKey(requestTenant(ctx), name)}, opts).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.Group{}, errorx.ErrGroupNotFound
+	}
+	if err != nil {
+		return domain.Group{}, fmt.Errorf("failed to find group: %w", err)
+	}
+	return doc.toDomain(), nil
+}
+
+// SaveGroup creates a group or replaces its roles. The members are changed with AddGroupMember
+// and RemoveGroupMember only, so saving a group cannot undo a concurrent membership change.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - group: The group to store
+//
+// Returns:
+//   - error: A wrapped database error
+func (ga *GroupMongoAdapter) SaveGroup(ctx context.Context, group domain.Group) error {
+	update := bson.M{
+		"$set":         bson.M{"tenantId": group.TenantID, "name": group.Name, "roles": roleNames(group.Roles), "updatedAt": group.UpdatedAt},
+		"$setOnInsert": bson.M{"members": []string{}},
+	}
+	_, err := ga.collection.UpdateOne(ctx, bson.M{"_id": groupKey(group.TenantID, group.Name)}, update, options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save group: %w", err)
+	}
+	return nil
+}
+
+// DeleteGroup removes a group.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - name: The name of the group
+//
+// Returns:
+//   - error: errorx.ErrGroupNotFound if the group does not exist, or a wrapped database error
+func (ga *GroupMongoAdapter) DeleteGroup(ctx context.Context, name string) error {
+	res, err := ga.collection.DeleteOne(ctx, bson.M{"_id": groupKey(requestTenant(ctx), name)})
+	if err != nil {
+		return fmt.Errorf("failed to delete group: %w", err)
+	}
+	if res.DeletedCount == 0 {
+		return errorx.ErrGroupNotFound
+	}
+	return nil
+}
+
+// AddGroupMember adds a user to the members of a group, unless the user is a member already.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - name: The name of the group
+//   - userID: The id of the user
+//
+// Returns:
+//   - error: errorx.ErrGroupNotFound if the group does not exist, or a wrapped database error
+func (ga *GroupMongoAdapter) AddGroupMember(ctx context.Context, name string, userID string) error {
+	return ga.updateMembers(ctx, name, bson.M{"$addToSet": bson.M{"members": userID}})
+}
+
+// RemoveGroupMember removes a user from the members of a group.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - name: The name of the group
+//   - userID: The id of the user
+//
+// Returns:
+//   - error: errorx.ErrGroupNotFound if the group does not exist, or a wrapped database error
+func (ga *GroupMongoAdapter) RemoveGroupMember(ctx context.Context, name string, userID string) error {
+	return ga.updateMembers(ctx, name, bson.M{"$pull": bson.M{"members": userID}})
+}
+
+// updateMembers applies a member update to a group.
+func (ga *GroupMongoAdapter) updateMembers(ctx context.Context, name string, update bson.M) error {
+	res, err := ga.collection.UpdateOne(ctx, bson.M{"_id": groupKey(requestTenant(ctx), name)}, update)
+	if err != nil {
+		return fmt.Errorf("failed to update group members: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return errorx.ErrGroupNotFound
+	}
+	return nil
+}
+
+// find returns the groups matching the filter.
+func (ga *GroupMongoAdapter) find(ctx context.Context, filter bson.M) ([]domain.Group, error) {
+	opts := options.Find()
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	cursor, err := ga.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find groups: %w", err)
+	}
+	var docs []groupDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode groups: %w", err)
+	}
+
+	groups := make([]domain.Group, 0, len(docs))
+	for _, doc := range docs {
+		groups = append(groups, doc.toDomain())
+	}
+	return groups, nil
+}
+
+// toDomain converts the stored document into a domain.Group.
+func (d groupDocument) toDomain() domain.Group {
+	roles := make([]domain.Role, 0, len(d.Roles))
+	for _, role := range d.Roles {
+		roles = append(roles, domain.Role(role))
+	}
+	members := d.Members
+	if members == nil {
+		members = []string{}
+	}
+	tenantID, name := d.TenantID, d.Name
+	if tenantID == "" {
+		tenantID, name = domain.DefaultTenantID, d.Key
+	}
+	return domain.Group{TenantID: tenantID, Name: name, Roles: roles, Members: members, UpdatedAt: d.UpdatedAt}
+}
+
+// groupKey returns the document id of a group. Groups of the default tenant are keyed by their
+// name, like the groups stored before tenants existed; the groups of other tenants are prefixed
+// with the tenant id. Neither tenant ids nor group names contain a colon.
+func groupKey(tenantID string, name string) string {
+	if tenantID == domain.DefaultTenantID {
+		return name
+	}
+	return tenantID + ":" + name
+}
+
+// requestTenant returns the tenant of the request, the default tenant for requests without one.
+func requestTenant(ctx context.Context) string {
+	if id := tenant.FromContext(ctx); id != "" {
+		return id
+	}
+	return domain.DefaultTenantID
+}
+
+// inTenant restricts a filter to the groups of the tenant of the request. Groups without a tenant
+// belong to the default tenant; requests without a tenant are not restricted.
+func inTenant(ctx context.Context, filter bson.M) bson.M {
+	switch id := tenant.FromContext(ctx); id {
+	case "":
+	case domain.DefaultTenantID:
+		filter["tenantId"] = bson.M{"$in": bson.A{id, nil}}
+	default:
+		filter["tenantId"] = id
+	}
+	return filter
+}
+
+// roleNames converts roles into the names stored in MongoDB.
+func roleNames(roles []domain.Role) []string {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, string(role))
+	}
+	return names
+}
+
+// requestComment returns the request id of ctx to be attached as comment to MongoDB operations.
+func requestComment(ctx context.Context) (string, bool) {
+	id := requestid.FromContext(ctx)
+	return id, id != ""
+}
@@ -0,0 +1,152 @@
+// Package persistence provides functionality for idempotency key persistence using MongoDB.
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"net/http"
+	"time"
+	ports "user-auth-hexagonal-architecture/internal/ports/persistence"
+)
+
+// idempotencyDocument is the MongoDB representation of a persistence.IdempotencyRecord.
+type idempotencyDocument struct {
+	Key         string              `bson:"_id"`
+	Fingerprint string              `bson:"fingerprint"`
+	Completed   bool                `bson:"completed"`
+	Status      int                 `bson:"status,omitempty"`
+	Header      map[string][]string `bson:"header,omitempty"`
+	Body        []byte              `bson:"body,omitempty"`
+	ExpiresAt   time.Time           `bson:"expiresAt"`
+}
+
+// toRecord converts the document into a persistence.IdempotencyRecord.
+func (d idempotencyDocument) toRecord() ports.IdempotencyRecord {
+	return ports.IdempotencyRecord{
+		Key:         d.Key,
+		Fingerprint: d.Fingerprint,
+		Completed:   d.Completed,
+		Response:    ports.StoredResponse{Status: d.Status, Header: http.Header(d.Header), Body: d.Body},
+		ExpiresAt:   d.ExpiresAt,
+	}
+}
+
+// IdempotencyMongoAdapter stores idempotency keys and their responses in MongoDB.
+type IdempotencyMongoAdapter struct {
+	collection *mongo.Collection
+}
+
+// NewIdempotencyMongoAdapter creates and initializes a new IdempotencyMongoAdapter.
+//
+// The adapter uses an "idempotency_keys" collection within the specified database. A TTL index
+// on the expiry lets MongoDB remove keys once their retention window has passed.
+//
+// Parameters:
+//   - client: A connected MongoDB client
+//   - database: Name of the database to use
+//
+// Returns:
+//   - *IdempotencyMongoAdapter: A pointer to the newly created adapter
+//   - error: An error if the index cannot be created
+func NewIdempotencyMongoAdapter(client *mongo.Client, database string) (*IdempotencyMongoAdapter, error) {
+	collection := client.Database(database).Collection("idempotency_keys")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+		Options: options.Index().SetName("expiresAt_ttl").SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create idempotency key index: %w", err)
+	}
+
+	return &IdempotencyMongoAdapter{collection}, nil
+}
+
+// Reserve claims a key for a request.
+//
+// The key is inserted as in progress. If it already exists, the stored record is returned instead.
+// Expired records the TTL monitor has not removed yet are taken over, as MongoDB deletes them
+// only about once a minute.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - key: The scoped idempotency key
+//   - fingerprint: The fingerprint of the request payload
+//   - expiresAt: The end of the retention window
+//
+// Returns:
+//   - persistence.IdempotencyRecord: The existing record if the key was not reserved
+//   - bool: true if the key was reserved for this request
+//   - error: An error if the store cannot be accessed
+func (i *IdempotencyMongoAdapter) Reserve(ctx context.Context, key string, fingerprint string, expiresAt time.Time) (ports.IdempotencyRecord, bool, error) {
+	doc := idempotencyDocument{Key: key, Fingerprint: fingerprint, ExpiresAt: expiresAt}
+
+	_, err := i.collection.InsertOne(ctx, doc)
+	if err == nil {
+		return ports.IdempotencyRecord{}, true, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return ports.IdempotencyRecord{}, false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	res, err := i.collection.ReplaceOne(ctx, bson.M{"_id": key, "expiresAt": bson.M{"$lte": time.Now()}}, doc)
+	if err != nil {
+		return ports.IdempotencyRecord{}, false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	if res.ModifiedCount == 1 {
+		return ports.IdempotencyRecord{}, true, nil
+	}
+
+	var existing idempotencyDocument
+	if err := i.collection.FindOne(ctx, bson.M{"_id": key}).Decode(&existing); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			// released concurrently, let the client retry
+			return ports.IdempotencyRecord{Key: key, Fingerprint: fingerprint}, false, nil
+		}
+		return ports.IdempotencyRecord{}, false, fmt.Errorf("failed to load idempotency key: %w", err)
+	}
+	return existing.toRecord(), false, nil
+}
+
+// Complete stores the response of the request that reserved the key.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - key: The scoped idempotency key
+//   - response: The response to replay
+//
+// Returns:
+//   - error: An error if the response cannot be stored
+func (i *IdempotencyMongoAdapter) Complete(ctx context.Context, key string, response ports.StoredResponse) error {
+	_, err := i.collection.UpdateOne(ctx, bson.M{"_id": key}, bson.M{"$set": bson.M{
+		"completed": true,
+		"status":    response.Status,
+		"header":    map[string][]string(response.Header),
+		"body":      response.Body,
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Release removes a reservation that has not been completed.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - key: The scoped idempotency key
+//
+// Returns:
+//   - error: An error if the reservation cannot be removed
+func (i *IdempotencyMongoAdapter) Release(ctx context.Context, key string) error {
+	if _, err := i.collection.DeleteOne(ctx, bson.M{"_id": key, "completed": false}); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
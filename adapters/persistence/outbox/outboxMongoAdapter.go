@@ -0,0 +1,271 @@
+// Package persistence provides functionality for the event outbox using MongoDB.
+package persistence
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/requestid"
+)
+
+// States of an outbox message.
+const (
+	statusPending   = "pending"
+	statusPublished = "published"
+	statusFailed    = "failed"
+)
+
+// outboxDocument is the MongoDB representation of a domain.OutboxMessage.
+type outboxDocument struct {
+	ID            string    `bson:"_id"`
+	EventName     string    `bson:"eventName"`
+	Category      string    `bson:"category"`
+	Key           string    `bson:"key"`
+	SchemaVersion int       `bson:"schemaVersion"`
+	Payload       []byte    `bson:"payload"`
+	RequestID     string    `bson:"requestId,omitempty"`
+	OccurredAt    time.Time `bson:"occurredAt"`
+	Status        string    `bson:"status"`
+	Attempts      int       `bson:"attempts"`
+	LastError     string    `bson:"lastError,omitempty"`
+	LeaseUntil    time.Time `bson:"leaseUntil"`
+	LeaseOwner    string    `bson:"leaseOwner,omitempty"`
+	PublishedAt   time.Time `bson:"publishedAt,omitempty"`
+	FailedAt      time.Time `bson:"failedAt,omitempty"`
+	ExpiresAt     time.Time `bson:"expiresAt,omitempty"`
+}
+
+// OutboxMongoAdapter stores the messages of the event outbox in MongoDB.
+// It implements the OutboxPersistencePort interface.
+type OutboxMongoAdapter struct {
+	collection *mongo.Collection
+	retention  time.Duration
+}
+
+// NewOutboxMongoAdapter creates and initializes a new OutboxMongoAdapter.
+//
+// The adapter uses an "outbox" collection within the specified database, indexed for claiming the
+// oldest pending messages. A TTL index removes published and failed messages after the retention
+// period; pending messages are kept until they are published.
+//
+// Parameters:
+//   - client: A connected MongoDB client
+//   - database: Name of the database to use
+//   - retention: How long published and failed messages are kept
+//
+// Returns:
+//   - *OutboxMongoAdapter: A pointer to the newly created adapter
+//   - error: An error if the indexes cannot be created
+func NewOutboxMongoAdapter(client *mongo.Client, database string, retention time.Duration) (*OutboxMongoAdapter, error) {
+	collection := client.Database(database).Collection("outbox")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "occurredAt", Value: 1}}, Options: options.Index().SetName("status_1_occurredAt_1")},
+		{Keys: bson.D{{Key: "leaseOwner", Value: 1}}, Options: options.Index().SetName("leaseOwner_1").SetSparse(true)},
+		{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetName("expiresAt_ttl").SetExpireAfterSeconds(0)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create outbox indexes: %w", err)
+	}
+
+	return &OutboxMongoAdapter{collection, retention}, nil
+}
+
+// AppendOutboxMessage inserts a pending message.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - message: The message to record
+//
+// Returns:
+//   - error: A wrapped database error
+func (oa *OutboxMongoAdapter) AppendOutboxMessage(ctx context.Context, message domain.OutboxMessage) error {
+	opts := options.InsertOne()
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	_, err := oa.collection.InsertOne(ctx, outboxDocument{
+		ID:            message.ID,
+		EventName:     message.EventName,
+		Category:      message.Category,
+		Key:           message.Key,
+		SchemaVersion: message.SchemaVersion,
+		Payload:       message.Payload,
+		RequestID:     message.RequestID,
+		OccurredAt:    message.OccurredAt,
+		Status:        statusPending,
+	}, opts)
+	if err != nil {
+		return fmt.Errorf("failed to insert outbox message: %w", err)
+	}
+	return nil
+}
+
+// ClaimOutboxMessages leases the oldest pending messages whose lease, if any, has expired.
+//
+// The candidates are leased with a conditional update under a random owner token and read back by
+// that token, so of two relays claiming the same candidates concurrently each gets those it won.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - limit: The largest number of messages claimed
+//   - lease: How long the messages stay claimed
+//
+// Returns:
+//   - []domain.OutboxMessage: The claimed messages, the oldest first
+//   - error: A wrapped database error
+func (oa *OutboxMongoAdapter) ClaimOutboxMessages(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxMessage, error) {
+	now := time.Now()
+	claimable := bson.M{"status": statusPending, "leaseUntil": bson.M{"$lte": now}}
+	cursor, err := oa.collection.Find(ctx, claimable, options.Find().
+		SetSort(bson.D{{Key: "occurredAt", Value: 1}}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pending outbox messages: %w", err)
+	}
+	var candidates []struct {
+		ID string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &candidates); err != nil {
+		return nil, fmt.Errorf("failed to decode pending outbox messages: %w", err)
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		ids[i] = candidate.ID
+	}
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("failed to generate lease owner: %w", err)
+	}
+	owner := hex.EncodeToString(token)
+	claimable["_id"] = bson.M{"$in": ids}
+	if _, err := oa.collection.UpdateMany(ctx, claimable, bson.M{"$set": bson.M{"leaseUntil": now.Add(lease), "leaseOwner": owner}}); err != nil {
+		return nil, fmt.Errorf("failed to lease outbox messages: %w", err)
+	}
+
+	cursor, err = oa.collection.Find(ctx, bson.M{"leaseOwner": owner, "_id": bson.M{"$in": ids}}, options.Find().SetSort(bson.D{{Key: "occurredAt", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find leased outbox messages: %w", err)
+	}
+	var docs []outboxDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode leased outbox messages: %w", err)
+	}
+
+	messages := make([]domain.OutboxMessage, len(docs))
+	for i, doc := range docs {
+		messages[i] = domain.OutboxMessage{
+			ID:            doc.ID,
+			EventName:     doc.EventName,
+			Category:      doc.Category,
+			Key:           doc.Key,
+			SchemaVersion: doc.SchemaVersion,
+			Payload:       doc.Payload,
+			RequestID:     doc.RequestID,
+			OccurredAt:    doc.OccurredAt,
+			Attempts:      doc.Attempts,
+			LastError:     doc.LastError,
+		}
+	}
+	return messages, nil
+}
+
+// MarkOutboxMessagesPublished completes messages and schedules their removal.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - ids: The ids of the messages
+//   - at: The time the broker acknowledged them
+//
+// Returns:
+//   - error: A wrapped database error
+func (oa *OutboxMongoAdapter) MarkOutboxMessagesPublished(ctx context.Context, ids []string, at time.Time) error {
+	_, err := oa.collection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, bson.M{
+		"$set":   bson.M{"status": statusPublished, "publishedAt": at, "expiresAt": at.Add(oa.retention)},
+		"$unset": bson.M{"leaseOwner": ""},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox messages as published: %w", err)
+	}
+	return nil
+}
+
+// ReleaseOutboxMessages ends the lease of messages after a failed attempt and counts the attempt,
+// so they can be claimed again right away.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - ids: The ids of the messages
+//   - lastError: The reason the attempt failed
+//
+// Returns:
+//   - error: A wrapped database error
+func (oa *OutboxMongoAdapter) ReleaseOutboxMessages(ctx context.Context, ids []string, lastError string) error {
+	_, err := oa.collection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, bson.M{
+		"$set":   bson.M{"leaseUntil": time.Time{}, "lastError": lastError},
+		"$unset": bson.M{"leaseOwner": ""},
+		"$inc":   bson.M{"attempts": 1},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to release outbox messages: %w", err)
+	}
+	return nil
+}
+
+// FailOutboxMessages gives messages up and schedules their removal.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - ids: The ids of the messages
+//   - at: The time of the last attempt
+//   - lastError: The reason the last attempt failed
+//
+// Returns:
+//   - error: A wrapped database error
+func (oa *OutboxMongoAdapter) FailOutboxMessages(ctx context.Context, ids []string, at time.Time, lastError string) error {
+	_, err := oa.collection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, bson.M{
+		"$set":   bson.M{"status": statusFailed, "failedAt": at, "expiresAt": at.Add(oa.retention), "lastError": lastError},
+		"$unset": bson.M{"leaseOwner": ""},
+		"$inc":   bson.M{"attempts": 1},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox messages as failed: %w", err)
+	}
+	return nil
+}
+
+// CountPendingOutboxMessages counts the messages waiting to be published, including leased ones.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//
+// Returns:
+//   - int64: The number of pending messages
+//   - error: A wrapped database error
+func (oa *OutboxMongoAdapter) CountPendingOutboxMessages(ctx context.Context) (int64, error) {
+	count, err := oa.collection.CountDocuments(ctx, bson.M{"status": statusPending})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count pending outbox messages: %w", err)
+	}
+	return count, nil
+}
+
+// requestComment returns the request id of ctx to be attached as comment to MongoDB operations.
+func requestComment(ctx context.Context) (string, bool) {
+	id := requestid.FromContext(ctx)
+	return id, id != ""
+}
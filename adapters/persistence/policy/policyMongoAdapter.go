@@ -0,0 +1,129 @@
+// Package persistence provides functionality for access policy persistence using MongoDB.
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/requestid"
+)
+
+// policyDocument is the MongoDB representation of a domain.Policy, keyed by the policy id.
+type policyDocument struct {
+	ID          string    `bson:"_id"`
+	Description string    `bson:"description,omitempty"`
+	Effect      string    `bson:"effect"`
+	Actions     []string  `bson:"actions"`
+	Condition   string    `bson:"condition,omitempty"`
+	UpdatedAt   time.Time `bson:"updatedAt"`
+}
+
+// PolicyMongoAdapter stores access policies in MongoDB.
+// It implements the PolicyPersistencePort interface.
+type PolicyMongoAdapter struct {
+	collection *mongo.Collection
+}
+
+// NewPolicyMongoAdapter creates a new PolicyMongoAdapter using the "policies" collection of the specified database.
+//
+// Parameters:
+//   - client: A connected MongoDB client
+//   - database: Name of the database to use
+//
+// Returns:
+//   - *PolicyMongoAdapter: A pointer to the newly created adapter
+func NewPolicyMongoAdapter(client *mongo.Client, database string) *PolicyMongoAdapter {
+	return &PolicyMongoAdapter{client.Database(database).Collection("policies")}
+}
+
+// FindPolicies returns all stored policies.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//
+// Returns:
+//   - []domain.Policy: The stored policies
+//   - error: A wrapped database error
+func (pa *PolicyMongoAdapter) FindPolicies(ctx context.Context) ([]domain.Policy, error) {
+	opts := options.Find()
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	cursor, err := pa.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find policies: %w", err)
+	}
+	var docs []policyDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode policies: %w", err)
+	}
+
+	policies := make([]domain.Policy, 0, len(docs))
+	for _, doc := range docs {
+		policies = append(policies, domain.Policy{
+			ID:          doc.ID,
+			Description: doc.Description,
+			Effect:      domain.PolicyEffect(doc.Effect),
+			Actions:     doc.Actions,
+			Condition:   doc.Condition,
+			UpdatedAt:   doc.UpdatedAt,
+		})
+	}
+	return policies, nil
+}
+
+// SavePolicy creates or replaces a policy.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - policy: The policy to store
+//
+// Returns:
+//   - error: A wrapped database error
+func (pa *PolicyMongoAdapter) SavePolicy(ctx context.Context, policy domain.Policy) error {
+	doc := policyDocument{
+		ID:          policy.ID,
+		Description: policy.Description,
+		Effect:      string(policy.Effect),
+		Actions:     policy.Actions,
+		Condition:   policy.Condition,
+		UpdatedAt:   policy.UpdatedAt,
+	}
+
+	_, err := pa.collection.ReplaceOne(ctx, bson.M{"_id": doc.ID}, doc, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save policy: %w", err)
+	}
+	return nil
+}
+
+// DeletePolicy removes a policy.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The id of the policy
+//
+// Returns:
+//   - error: errorx.ErrPolicyNotFound if no policy is stored under the id, or a wrapped database error
+func (pa *PolicyMongoAdapter) DeletePolicy(ctx context.Context, id string) error {
+	res, err := pa.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete policy: %w", err)
+	}
+	if res.DeletedCount == 0 {
+		return errorx.ErrPolicyNotFound
+	}
+	return nil
+}
+
+// requestComment returns the request id of ctx to be attached as comment to MongoDB operations.
+func requestComment(ctx context.Context) (string, bool) {
+	id := requestid.FromContext(ctx)
+	return id, id != ""
+}
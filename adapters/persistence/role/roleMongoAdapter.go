@@ -0,0 +1,123 @@
+// Package persistence provides functionality for role persistence using MongoDB.
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/requestid"
+)
+
+// roleDocument is the MongoDB representation of a domain.RoleDefinition, keyed by the role name.
+type roleDocument struct {
+	Name        string    `bson:"_id"`
+	Permissions []string  `bson:"permissions"`
+	UpdatedAt   time.Time `bson:"updatedAt"`
+}
+
+// RoleMongoAdapter stores role definitions in MongoDB.
+// It implements the RolePersistencePort interface.
+type RoleMongoAdapter struct {
+	collection *mongo.Collection
+}
+
+// NewRoleMongoAdapter creates a new RoleMongoAdapter using the "roles" collection of the specified database.
+//
+// Parameters:
+//   - client: A connected MongoDB client
+//   - database: Name of the database to use
+//
+// Returns:
+//   - *RoleMongoAdapter: A pointer to the newly created adapter
+func NewRoleMongoAdapter(client *mongo.Client, database string) *RoleMongoAdapter {
+	return &RoleMongoAdapter{client.Database(database).Collection("roles")}
+}
+
+// FindRoles returns all stored role definitions.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//
+// Returns:
+//   - []domain.RoleDefinition: The stored definitions
+//   - error: A wrapped database error
+func (ra *RoleMongoAdapter) FindRoles(ctx context.Context) ([]domain.RoleDefinition, error) {
+	opts := options.Find()
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	cursor, err := ra.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find roles: %w", err)
+	}
+	var docs []roleDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode roles: %w", err)
+	}
+
+	definitions := make([]domain.RoleDefinition, 0, len(docs))
+	for _, doc := range docs {
+		permissions := make([]domain.Permission, 0, len(doc.Permissions))
+		for _, permission := range doc.Permissions {
+			permissions = append(permissions, domain.Permission(permission))
+		}
+		definitions = append(definitions, domain.RoleDefinition{
+			Name:        domain.Role(doc.Name),
+			Permissions: permissions,
+			UpdatedAt:   doc.UpdatedAt,
+		})
+	}
+	return definitions, nil
+}
+
+// SaveRole creates or replaces a role definition.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - role: The definition to store
+//
+// Returns:
+//   - error: A wrapped database error
+func (ra *RoleMongoAdapter) SaveRole(ctx context.Context, role domain.RoleDefinition) error {
+	doc := roleDocument{Name: string(role.Name), Permissions: make([]string, 0, len(role.Permissions)), UpdatedAt: role.UpdatedAt}
+	for _, permission := range role.Permissions {
+		doc.Permissions = append(doc.Permissions, string(permission))
+	}
+
+	_, err := ra.collection.ReplaceOne(ctx, bson.M{"_id": doc.Name}, doc, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save role: %w", err)
+	}
+	return nil
+}
+
+// DeleteRole removes a role definition.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - name: The name of the role
+//
+// Returns:
+//   - error: errorx.ErrRoleNotFound if no definition is stored under the name, or a wrapped database error
+func (ra *RoleMongoAdapter) DeleteRole(ctx context.Context, name domain.Role) error {
+	res, err := ra.collection.DeleteOne(ctx, bson.M{"_id": string(name)})
+	if err != nil {
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+	if res.DeletedCount == 0 {
+		return errorx.ErrRoleNotFound
+	}
+	return nil
+}
+
+// requestComment returns the request id of ctx to be attached as comment to MongoDB operations.
+func requestComment(ctx context.Context) (string, bool) {
+	id := requestid.FromContext(ctx)
+	return id, id != ""
+}
@@ -0,0 +1,243 @@
+// Package persistence provides functionality for session persistence using MongoDB.
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/requestid"
+)
+
+// sessionRetention is how long sessions are kept after they expired, so recently ended sessions
+// can still be reviewed.
+const sessionRetention = 30 * 24 * time.Hour
+
+// sessionDocument is the MongoDB representation of a domain.Session, keyed by the session id.
+type sessionDocument struct {
+	ID                       string    `bson:"_id"`
+	UserID                   string    `bson:"userId"`
+	Username                 string    `bson:"username"`
+	TenantID                 string    `bson:"tenantId"`
+	TokenVersion             int       `bson:"tokenVersion,omitempty"`
+	UserAgent                string    `bson:"userAgent,omitempty"`
+	IPAddress                string    `bson:"ipAddress,omitempty"`
+	Country                  string    `bson:"country,omitempty"`
+	CreatedAt                time.Time `bson:"createdAt"`
+	LastSeenAt               time.Time `bson:"lastSeenAt"`
+	ExpiresAt                time.Time `bson:"expiresAt"`
+	RevokedAt                time.Time `bson:"revokedAt,omitempty"`
+	RevocationReason         string    `bson:"revocationReason,omitempty"`
+	RefreshTokenHash         string    `bson:"refreshTokenHash,omitempty"`
+	PreviousRefreshTokenHash string    `bson:"previousRefreshTokenHash,omitempty"`
+	RefreshedAt              time.Time `bson:"refreshedAt,omitempty"`
+}
+
+// toDomain converts the document into a domain.Session.
+func (d sessionDocument) toDomain() domain.Session {
+	return domain.Session{
+		ID:                       d.ID,
+		UserID:                   d.UserID,
+		Username:                 domain.RestoreUsername(d.Username),
+		TenantID:                 d.TenantID,
+		TokenVersion:             d.TokenVersion,
+		Device:                   domain.Device{UserAgent: d.UserAgent, IPAddress: d.IPAddress, Country: d.Country},
+		CreatedAt:                d.CreatedAt,
+		LastSeenAt:               d.LastSeenAt,
+		ExpiresAt:                d.ExpiresAt,
+		RevokedAt:                d.RevokedAt,
+		RevocationReason:         domain.SessionRevocationReason(d.RevocationReason),
+		RefreshTokenHash:         d.RefreshTokenHash,
+		PreviousRefreshTokenHash: d.PreviousRefreshTokenHash,
+		RefreshedAt:              d.RefreshedAt,
+	}
+}
+
+// newSessionDocument converts a domain.Session into its MongoDB representation.
+func newSessionDocument(session domain.Session) sessionDocument {
+	return sessionDocument{
+		ID:                       session.ID,
+		UserID:                   session.UserID,
+		Username:                 session.Username.String(),
+		TenantID:                 session.TenantID,
+		TokenVersion:             session.TokenVersion,
+		UserAgent:                session.Device.UserAgent,
+		IPAddress:                session.Device.IPAddress,
+		Country:                  session.Device.Country,
+		CreatedAt:                session.CreatedAt,
+		LastSeenAt:               session.LastSeenAt,
+		ExpiresAt:                session.ExpiresAt,
+		RevokedAt:                session.RevokedAt,
+		RevocationReason:         string(session.RevocationReason),
+		RefreshTokenHash:         session.RefreshTokenHash,
+		PreviousRefreshTokenHash: session.PreviousRefreshTokenHash,
+		RefreshedAt:              session.RefreshedAt,
+	}
+}
+
+// SessionMongoAdapter stores sessions in MongoDB.
+// It implements the SessionPersistencePort interface.
+type SessionMongoAdapter struct {
+	collection *mongo.Collection
+}
+
+// NewSessionMongoAdapter creates and initializes a new SessionMongoAdapter.
+//
+// The adapter uses a "sessions" collection within the specified database. An index on the user
+// keeps listing the sessions of a user fast, and a TTL index removes sessions 30 days after they expired.
+//
+// Parameters:
+//   - client: A connected MongoDB client
+//   - database: Name of the database to use
+//
+// Returns:
+//   - *SessionMongoAdapter: A pointer to the newly created adapter
+//   - error: An error if the indexes cannot be created
+func NewSessionMongoAdapter(client *mongo.Client, database string) (*SessionMongoAdapter, error) {
+	collection := client.Database(database).Collection("sessions")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("userId_1_createdAt_-1"),
+		},
+		{
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetName("expiresAt_ttl").SetExpireAfterSeconds(int32(sessionRetention / time.Second)),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session indexes: %w", err)
+	}
+
+	return &SessionMongoAdapter{collection}, nil
+}
+
+// SaveSession creates or replaces a session.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - session: The session to store
+//
+// Returns:
+//   - error: A wrapped database error
+func (sa *SessionMongoAdapter) SaveSession(ctx context.Context, session domain.Session) error {
+	doc := newSessionDocument(session)
+
+	_, err := sa.collection.ReplaceOne(ctx, bson.M{"_id": doc.ID}, doc, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
+
+// SaveRefreshedSession replaces a session whose refresh token was rotated. The replacement is
+// conditional on the stored refresh token hash, so of two concurrent refreshes with the same
+// token only one succeeds.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - session: The session holding the new refresh token
+//   - previousRefreshTokenHash: The hash of the refresh token the stored session has to hold
+//
+// Returns:
+//   - error: errorx.ErrRefreshTokenReused if the stored session holds another refresh token, or a wrapped database error
+func (sa *SessionMongoAdapter) SaveRefreshedSession(ctx context.Context, session domain.Session, previousRefreshTokenHash string) error {
+	doc := newSessionDocument(session)
+
+	res, err := sa.collection.ReplaceOne(ctx, bson.M{"_id": doc.ID, "refreshTokenHash": previousRefreshTokenHash}, doc)
+	if err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return errorx.ErrRefreshTokenReused
+	}
+	return nil
+}
+
+// FindSession returns a single session.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The id of the session
+//
+// Returns:
+//   - domain.Session: The session
+//   - error: errorx.ErrSessionNotFound if the session does not exist, or a wrapped database error
+func (sa *SessionMongoAdapter) FindSession(ctx context.Context, id string) (domain.Session, error) {
+	opts := options.FindOne()
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	var doc sessionDocument
+	err := sa.collection.FindOne(ctx, bson.M{"_id": id}, opts).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.Session{}, errorx.ErrSessionNotFound
+	}
+	if err != nil {
+		return domain.Session{}, fmt.Errorf("failed to find session: %w", err)
+	}
+	return doc.toDomain(), nil
+}
+
+// FindSessionsByUser returns the stored sessions of a user, the most recent first.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - userID: The id of the user
+//
+// Returns:
+//   - []domain.Session: The sessions, including ended sessions within the retention window
+//   - error: A wrapped database error
+func (sa *SessionMongoAdapter) FindSessionsByUser(ctx context.Context, userID string) ([]domain.Session, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	cursor, err := sa.collection.Find(ctx, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sessions: %w", err)
+	}
+	var docs []sessionDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode sessions: %w", err)
+	}
+
+	sessions := make([]domain.Session, 0, len(docs))
+	for _, doc := range docs {
+		sessions = append(sessions, doc.toDomain())
+	}
+	return sessions, nil
+}
+
+// DeleteSessionsByUser removes all stored sessions of a user, active or ended.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - userID: The id of the user
+//
+// Returns:
+//   - int64: The number of removed sessions
+//   - error: A wrapped database error
+func (sa *SessionMongoAdapter) DeleteSessionsByUser(ctx context.Context, userID string) (int64, error) {
+	res, err := sa.collection.DeleteMany(ctx, bson.M{"userId": userID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete sessions: %w", err)
+	}
+	return res.DeletedCount, nil
+}
+
+// requestComment returns the request id of ctx to be attached as comment to MongoDB operations.
+func requestComment(ctx context.Context) (string, bool) {
+	id := requestid.FromContext(ctx)
+	return id, id != ""
+}
@@ -0,0 +1,173 @@
+// Package persistence provides functionality for the email suppression list using MongoDB.
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/requestid"
+)
+
+// suppressionDocument is the MongoDB representation of a domain.EmailSuppression, keyed by the address.
+type suppressionDocument struct {
+	Address   string    `bson:"_id"`
+	Reason    string    `bson:"reason"`
+	Provider  string    `bson:"provider"`
+	Detail    string    `bson:"detail,omitempty"`
+	CreatedAt time.Time `bson:"createdAt"`
+	UpdatedAt time.Time `bson:"updatedAt"`
+}
+
+// EmailSuppressionMongoAdapter stores the suppressed email addresses in MongoDB.
+// It implements the EmailSuppressionPersistencePort interface.
+type EmailSuppressionMongoAdapter struct {
+	collection *mongo.Collection
+}
+
+// NewEmailSuppressionMongoAdapter creates and initializes a new EmailSuppressionMongoAdapter.
+//
+// The adapter uses an "email_suppressions" collection within the specified database, keyed by the
+// address and indexed by suppression time, so the newest can be listed efficiently.
+//
+// Parameters:
+//   - client: A connected MongoDB client
+//   - database: Name of the database to use
+//
+// Returns:
+//   - *EmailSuppressionMongoAdapter: A pointer to the newly created adapter
+//   - error: An error if the index cannot be created
+func NewEmailSuppressionMongoAdapter(client *mongo.Client, database string) (*EmailSuppressionMongoAdapter, error) {
+	collection := client.Database(database).Collection("email_suppressions")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "createdAt", Value: -1}},
+		Options: options.Index().SetName("createdAt_-1"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create email suppression index: %w", err)
+	}
+
+	return &EmailSuppressionMongoAdapter{collection}, nil
+}
+
+// SaveEmailSuppression suppresses an address. Feedback for an address that is already suppressed
+// updates the reason and the detail but keeps the time it was first suppressed.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - suppression: The suppression to save
+//
+// Returns:
+//   - error: A wrapped database error
+func (sa *EmailSuppressionMongoAdapter) SaveEmailSuppression(ctx context.Context, suppression domain.EmailSuppression) error {
+	opts := options.Update().SetUpsert(true)
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	_, err := sa.collection.UpdateByID(ctx, suppression.Address, bson.M{
+		"$set": bson.M{
+			"reason":    string(suppression.Reason),
+			"provider":  suppression.Provider,
+			"detail":    suppression.Detail,
+			"updatedAt": suppression.UpdatedAt,
+		},
+		"$setOnInsert": bson.M{"createdAt": suppression.CreatedAt},
+	}, opts)
+	if err != nil {
+		return fmt.Errorf("failed to upsert email suppression: %w", err)
+	}
+	return nil
+}
+
+// IsEmailSuppressed reports whether an address is suppressed.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - address: The normalized address
+//
+// Returns:
+//   - bool: true if no emails may be sent to the address
+//   - error: A wrapped database error
+func (sa *EmailSuppressionMongoAdapter) IsEmailSuppressed(ctx context.Context, address string) (bool, error) {
+	opts := options.Count().SetLimit(1)
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	count, err := sa.collection.CountDocuments(ctx, bson.M{"_id": address}, opts)
+	if err != nil {
+		return false, fmt.Errorf("failed to count email suppressions: %w", err)
+	}
+	return count > 0, nil
+}
+
+// FindEmailSuppressions returns the most recently suppressed addresses.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - limit: The maximum number of suppressions to return
+//
+// Returns:
+//   - []domain.EmailSuppression: The suppressions, newest first
+//   - error: A wrapped database error
+func (sa *EmailSuppressionMongoAdapter) FindEmailSuppressions(ctx context.Context, limit int64) ([]domain.EmailSuppression, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}).SetLimit(limit)
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	cursor, err := sa.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find email suppressions: %w", err)
+	}
+	var docs []suppressionDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode email suppressions: %w", err)
+	}
+
+	suppressions := make([]domain.EmailSuppression, 0, len(docs))
+	for _, doc := range docs {
+		suppressions = append(suppressions, domain.EmailSuppression{
+			Address:   doc.Address,
+			Reason:    domain.EmailFeedbackType(doc.Reason),
+			Provider:  doc.Provider,
+			Detail:    doc.Detail,
+			CreatedAt: doc.CreatedAt,
+			UpdatedAt: doc.UpdatedAt,
+		})
+	}
+	return suppressions, nil
+}
+
+// DeleteEmailSuppression lifts the suppression of an address.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - address: The normalized address
+//
+// Returns:
+//   - error: errorx.ErrEmailSuppressionNotFound if the address is not suppressed, or a wrapped database error
+func (sa *EmailSuppressionMongoAdapter) DeleteEmailSuppression(ctx context.Context, address string) error {
+	res, err := sa.collection.DeleteOne(ctx, bson.M{"_id": address})
+	if err != nil {
+		return fmt.Errorf("failed to delete email suppression: %w", err)
+	}
+	if res.DeletedCount == 0 {
+		return errorx.ErrEmailSuppressionNotFound
+	}
+	return nil
+}
+
+// requestComment returns the request id of ctx to be attached as comment to MongoDB operations.
+func requestComment(ctx context.Context) (string, bool) {
+	id := requestid.FromContext(ctx)
+	return id, id != ""
+}
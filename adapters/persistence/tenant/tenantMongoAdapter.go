@@ -0,0 +1,156 @@
+// Package persistence provides functionality for tenant persistence using MongoDB.
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/requestid"
+)
+
+// passwordPolicyDocument is the MongoDB representation of a domain.PasswordPolicy.
+type passwordPolicyDocument struct {
+	MinLength     int  `bson:"minLength"`
+	RequireUpper  bool `bson:"requireUpper"`
+	RequireLower  bool `bson:"requireLower"`
+	RequireDigit  bool `bson:"requireDigit"`
+	RequireSymbol bool `bson:"requireSymbol"`
+}
+
+// tenantDocument is the MongoDB representation of a domain.Tenant, keyed by the tenant id.
+// The access token lifetime is stored in seconds.
+type tenantDocument struct {
+	ID                    string                 `bson:"_id"`
+	Name                  string                 `bson:"name"`
+	PasswordPolicy        passwordPolicyDocument `bson:"passwordPolicy"`
+	AccessTokenTTLSeconds int64                  `bson:"accessTokenTtlSeconds"`
+	RequireMFA            bool                   `bson:"requireMfa"`
+	LoginMethods          []string               `bson:"loginMethods"`
+	UpdatedAt             time.Time              `bson:"updatedAt"`
+}
+
+// toDomain converts the document into a domain.Tenant.
+func (d tenantDocument) toDomain() domain.Tenant {
+	methods := make([]domain.LoginMethod, 0, len(d.LoginMethods))
+	for _, method := range d.LoginMethods {
+		methods = append(methods, domain.LoginMethod(method))
+	}
+	return domain.Tenant{
+		ID:   d.ID,
+		Name: d.Name,
+		Settings: domain.TenantSettings{
+			PasswordPolicy: domain.PasswordPolicy(d.PasswordPolicy),
+			AccessTokenTTL: time.Duration(d.AccessTokenTTLSeconds) * time.Second,
+			RequireMFA:     d.RequireMFA,
+			LoginMethods:   methods,
+		},
+		UpdatedAt: d.UpdatedAt,
+	}
+}
+
+// TenantMongoAdapter stores tenants in MongoDB.
+// It implements the TenantPersistencePort interface.
+type TenantMongoAdapter struct {
+	collection *mongo.Collection
+}
+
+// NewTenantMongoAdapter creates a new TenantMongoAdapter using the "tenants" collection of the specified database.
+//
+// Parameters:
+//   - client: A connected MongoDB client
+//   - database: Name of the database to use
+//
+// Returns:
+//   - *TenantMongoAdapter: A pointer to the newly created adapter
+func NewTenantMongoAdapter(client *mongo.Client, database string) *TenantMongoAdapter {
+	return &TenantMongoAdapter{client.Database(database).Collection("tenants")}
+}
+
+// FindTenants returns all stored tenants.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//
+// Returns:
+//   - []domain.Tenant: The stored tenants
+//   - error: A wrapped database error
+func (ta *TenantMongoAdapter) FindTenants(ctx context.Context) ([]domain.Tenant, error) {
+	opts := options.Find()
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	cursor, err := ta.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tenants: %w", err)
+	}
+	var docs []tenantDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode tenants: %w", err)
+	}
+
+	tenants := make([]domain.Tenant, 0, len(docs))
+	for _, doc := range docs {
+		tenants = append(tenants, doc.toDomain())
+	}
+	return tenants, nil
+}
+
+// SaveTenant creates or replaces a tenant.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - tenant: The tenant to store
+//
+// Returns:
+//   - error: A wrapped database error
+func (ta *TenantMongoAdapter) SaveTenant(ctx context.Context, tenant domain.Tenant) error {
+	doc := tenantDocument{
+		ID:                    tenant.ID,
+		Name:                  tenant.Name,
+		PasswordPolicy:        passwordPolicyDocument(tenant.Settings.PasswordPolicy),
+		AccessTokenTTLSeconds: int64(tenant.Settings.AccessTokenTTL / time.Second),
+		RequireMFA:            tenant.Settings.RequireMFA,
+		LoginMethods:          make([]string, 0, len(tenant.Settings.LoginMethods)),
+		UpdatedAt:             tenant.UpdatedAt,
+	}
+	for _, method := range tenant.Settings.LoginMethods {
+		doc.LoginMethods = append(doc.LoginMethods, string(method))
+	}
+
+	_, err := ta.collection.ReplaceOne(ctx, bson.M{"_id": doc.ID}, doc, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save tenant: %w", err)
+	}
+	return nil
+}
+
+// DeleteTenant removes a tenant.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The id of the tenant
+//
+// Returns:
+//   - error: errorx.ErrTenantNotFound if no tenant is stored under the id, or a wrapped database error
+func (ta *TenantMongoAdapter) DeleteTenant(ctx context.Context, id string) error {
+	res, err := ta.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete tenant: %w", err)
+	}
+	if res.DeletedCount == 0 {
+		return errorx.ErrTenantNotFound
+	}
+	return nil
+}
+
+// requestComment returns the request id of ctx to be attached as comment to MongoDB operations.
+func requestComment(ctx context.Context) (string, bool) {
+	id := requestid.FromContext(ctx)
+	return id, id != ""
+}
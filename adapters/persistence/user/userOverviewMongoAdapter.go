@@ -0,0 +1,263 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"regexp"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// overviewSortFields maps the public sort keys onto document fields.
+var overviewSortFields = map[string]string{
+	"username":    "username",
+	"createdAt":   "createdAt",
+	"lastLoginAt": "lastLoginAt",
+	"status":      "status",
+}
+
+// userOverviewDocument is the MongoDB representation of a domain.UserOverview.
+type userOverviewDocument struct {
+	ID          string    `bson:"userId"`
+	TenantID    string    `bson:"tenantId,omitempty"`
+	Username    string    `bson:"username"`
+	Status      string    `bson:"status"`
+	Roles       []string  `bson:"roles"`
+	CreatedAt   time.Time `bson:"createdAt"`
+	LastLoginAt time.Time `bson:"lastLoginAt,omitempty"`
+	DeviceCount int       `bson:"deviceCount"`
+	MfaEnabled  bool      `bson:"mfaEnabled"`
+}
+
+// UserOverviewMongoAdapter stores the user overview read model in MongoDB.
+type UserOverviewMongoAdapter struct {
+	collection *mongo.Collection
+}
+
+// NewUserOverviewMongoAdapter creates and initializes a new UserOverviewMongoAdapter.
+//
+// The adapter uses a "user_overview" collection within the specified database,
+// indexed for the admin list and search queries.
+//
+// Parameters:
+//   - client: A connected MongoDB client
+//   - database: Name of the database to use
+//
+// Returns:
+//   - *UserOverviewMongoAdapter: A pointer to the newly created adapter
+//   - error: An error if the indexes cannot be created
+func NewUserOverviewMongoAdapter(client *mongo.Client, database string) (*UserOverviewMongoAdapter, error) {
+	collection := client.Database(database).Collection("user_overview")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "username", Value: 1}}, Options: options.Index().SetName("username_1").SetUnique(true)},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: -1}}, Options: options.Index().SetName("status_1_createdAt_-1")},
+		{Keys: bson.D{{Key: "roles", Value: 1}}, Options: options.Index().SetName("roles_1")},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user overview indexes: %w", err)
+	}
+
+	return &UserOverviewMongoAdapter{collection}, nil
+}
+
+// InsertUserOverview creates the overview of a user. An existing overview of the same
+// user is replaced, which makes replaying events idempotent.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - overview: The overview to store
+//
+// Returns:
+//   - error: An error if the write fails
+func (o *UserOverviewMongoAdapter) InsertUserOverview(ctx context.Context, overview domain.UserOverview) error {
+	doc := userOverviewDocument(overview)
+	_, err := o.collection.ReplaceOne(ctx, bson.M{"username": overview.Username}, doc, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to insert user overview: %w", err)
+	}
+	return nil
+}
+
+// DeleteUserOverview removes the overview of a user.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - username: The user whose overview is removed
+//
+// Returns:
+//   - error: An error if the delete fails
+func (o *UserOverviewMongoAdapter) DeleteUserOverview(ctx context.Context, username string) error {
+	if _, err := o.collection.DeleteOne(ctx, bson.M{"username": username}); err != nil {
+		return fmt.Errorf("failed to delete user overview: %w", err)
+	}
+	return nil
+}
+
+// UpdateUserOverview applies a partial update to the overview of a user.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - username: The user whose overview changes
+//   - update: The fields to change
+//
+// Returns:
+//   - error: An error if the write fails
+func (o *UserOverviewMongoAdapter) UpdateUserOverview(ctx context.Context, username string, update domain.UserOverviewUpdate) error {
+	set := bson.M{}
+	if update.Username != nil {
+		set["username"] = *update.Username
+	}
+	if update.Status != nil {
+		set["status"] = *update.Status
+	}
+	if update.Roles != nil {
+		set["roles"] = update.Roles
+	}
+	if update.LastLoginAt != nil {
+		set["lastLoginAt"] = *update.LastLoginAt
+	}
+	if update.MfaEnabled != nil {
+		set["mfaEnabled"] = *update.MfaEnabled
+	}
+
+	change := bson.M{}
+	if len(set) > 0 {
+		change["$set"] = set
+	}
+	if update.DeviceCountDelta != 0 {
+		change["$inc"] = bson.M{"deviceCount": update.DeviceCountDelta}
+	}
+	if len(change) == 0 {
+		return nil
+	}
+
+	if _, err := o.collection.UpdateOne(ctx, bson.M{"username": username}, change); err != nil {
+		return fmt.Errorf("failed to update user overview: %w", err)
+	}
+	return nil
+}
+
+// FindUserOverviews returns one page of the user overviews of the tenant of the request matching the filter.
+//
+// The specification of the filter is translated into a MongoDB query, see specificationQuery.
+// Overviews stored without a tenant belong to the default tenant.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - filter: The selection, sorting and paging to apply
+//
+// Returns:
+//   - []domain.UserOverview: The requested page
+//   - int64: The total number of matching overviews
+//   - error: An error if the specification cannot be translated or the query fails
+func (o *UserOverviewMongoAdapter) FindUserOverviews(ctx context.Context, filter domain.UserOverviewFilter) ([]domain.UserOverview, int64, error) {
+	query, err := specificationQuery(filter.Spec)
+	if err != nil {
+		return nil, 0, err
+	}
+	query = inTenant(ctx, query)
+
+	countOpts := options.Count()
+	if comment, ok := requestComment(ctx); ok {
+		countOpts.SetComment(comment)
+	}
+	total, err := o.collection.CountDocuments(ctx, query, countOpts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count user overviews: %w", err)
+	}
+
+	sortField, ok := overviewSortFields[filter.SortBy]
+	if !ok {
+		sortField = "username"
+	}
+	direction := 1
+	if filter.Desc {
+		direction = -1
+	}
+	opts := options.Find().SetSort(bson.D{{Key: sortField, Value: direction}}).SetSkip(filter.Offset)
+	if filter.Limit > 0 {
+		opts.SetLimit(filter.Limit)
+	}
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	cursor, err := o.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find user overviews: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []userOverviewDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode user overviews: %w", err)
+	}
+
+	overviews := make([]domain.UserOverview, 0, len(docs))
+	for _, doc := range docs {
+		overviews = append(overviews, domain.UserOverview(doc))
+	}
+	return overviews, total, nil
+}
+
+// specificationQuery translates a user specification into a MongoDB query on the overview documents.
+//
+// The search term matches usernames by case-insensitive prefix, so the username index can be used.
+// A nil specification matches every document.
+func specificationQuery(spec domain.UserSpecification) (bson.M, error) {
+	switch s := spec.(type) {
+	case nil:
+		return bson.M{}, nil
+	case domain.ByStatus:
+		return bson.M{"status": string(s.Status)}, nil
+	case domain.ByRole:
+		return bson.M{"roles": string(s.Role)}, nil
+	case domain.CreatedBetween:
+		period := bson.M{}
+		if !s.From.IsZero() {
+			period["$gte"] = s.From
+		}
+		if !s.To.IsZero() {
+			period["$lt"] = s.To
+		}
+		if len(period) == 0 {
+			return bson.M{}, nil
+		}
+		return bson.M{"createdAt": period}, nil
+	case domain.SearchTerm:
+		return bson.M{"username": bson.M{"$regex": "^" + regexp.QuoteMeta(s.Term), "$options": "i"}}, nil
+	case domain.AndSpecification:
+		return combinedQuery("$and", s.Specs)
+	case domain.OrSpecification:
+		return combinedQuery("$or", s.Specs)
+	case domain.NotSpecification:
+		query, err := specificationQuery(s.Spec)
+		if err != nil {
+			return nil, err
+		}
+		return bson.M{"$nor": bson.A{query}}, nil
+	}
+	return nil, fmt.Errorf("unsupported user specification %T", spec)
+}
+
+// combinedQuery translates the specifications and combines them with a logical query operator.
+func combinedQuery(operator string, specs []domain.UserSpecification) (bson.M, error) {
+	queries := bson.A{}
+	for _, spec := range specs {
+		query, err := specificationQuery(spec)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, query)
+	}
+	if len(queries) == 0 {
+		return bson.M{}, nil
+	}
+	return bson.M{operator: queries}, nil
+}
@@ -6,23 +6,162 @@ import (
 	"errors"
 	"fmt"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
-	"log"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"strings"
 	"time"
 	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
 )
 
+// userDocument is the MongoDB representation of a domain.User.
+type userDocument struct {
+	ID                 primitive.ObjectID  `bson:"_id,omitempty"`
+	TenantID           string              `bson:"tenantId,omitempty"`
+	Username           string              `bson:"username"`
+	Password           string              `bson:"password"`
+	Email              string              `bson:"email,omitempty"`
+	Role               string              `bson:"role,omitempty"`
+	Roles              []string            `bson:"roles"`
+	Status             string              `bson:"status,omitempty"`
+	CreatedAt          time.Time           `bson:"createdAt"`
+	LastLoginAt        time.Time           `bson:"lastLoginAt,omitempty"`
+	MfaEnabled         bool                `bson:"mfaEnabled,omitempty"`
+	CanonicalUsername  string              `bson:"canonicalUsername,omitempty"`
+	CanonicalEmail     string              `bson:"canonicalEmail,omitempty"`
+	TokenVersion       int                 `bson:"tokenVersion,omitempty"`
+	LegalHold          bool                `bson:"legalHold,omitempty"`
+	LockReason         string              `bson:"lockReason,omitempty"`
+	LockedUntil        time.Time           `bson:"lockedUntil,omitempty"`
+	MustChangePassword bool                `bson:"mustChangePassword,omitempty"`
+	Aliases            []string            `bson:"aliases,omitempty"`
+	CanonicalAliases   []string            `bson:"canonicalAliases,omitempty"`
+	MergedInto         string              `bson:"mergedInto,omitempty"`
+	MfaFactors         []mfaFactorDocument `bson:"mfaFactors,omitempty"`
+}
+
+// mfaFactorDocument is the MongoDB representation of a domain.MfaFactor.
+type mfaFactorDocument struct {
+	Method      string    `bson:"method"`
+	Secret      string    `bson:"secret"`
+	EnrolledAt  time.Time `bson:"enrolledAt"`
+	ConfirmedAt time.Time `bson:"confirmedAt,omitempty"`
+}
+
+// mfaFactorDocuments converts second factors into the documents stored for them.
+func mfaFactorDocuments(factors []domain.MfaFactor) []mfaFactorDocument {
+	docs := make([]mfaFactorDocument, 0, len(factors))
+	for _, factor := range factors {
+		docs = append(docs, mfaFactorDocument{
+			Method:      string(factor.Method),
+			Secret:      factor.Secret,
+			EnrolledAt:  factor.EnrolledAt,
+			ConfirmedAt: factor.ConfirmedAt,
+		})
+	}
+	return docs
+}
+
+// toDomain converts the document into a domain.User.
+// Documents written before account statuses existed are treated as active, documents written
+// before users could hold several roles keep their single role, and documents written before
+// tenants existed belong to the default tenant.
+func (d userDocument) toDomain() domain.User {
+	status := domain.AccountStatus(d.Status)
+	if status == "" {
+		status = domain.StatusActive
+	}
+	tenantID := d.TenantID
+	if tenantID == "" {
+		tenantID = domain.DefaultTenantID
+	}
+	roles := make([]domain.Role, 0, len(d.Roles)+1)
+	for _, role := range d.Roles {
+		roles = append(roles, domain.Role(role))
+	}
+	if len(roles) == 0 && d.Role != "" {
+		roles = append(roles, domain.Role(d.Role))
+	}
+	var aliases []domain.Username
+	for _, alias := range d.Aliases {
+		aliases = append(aliases, domain.RestoreUsername(alias))
+	}
+	var factors []domain.MfaFactor
+	for _, factor := range d.MfaFactors {
+		factors = append(factors, domain.MfaFactor{
+			Method:      domain.MfaMethod(factor.Method),
+			Secret:      factor.Secret,
+			EnrolledAt:  factor.EnrolledAt,
+			ConfirmedAt: factor.ConfirmedAt,
+		})
+	}
+
+	return domain.User{
+		ID:                 d.ID.Hex(),
+		TenantID:           tenantID,
+		Username:           domain.RestoreUsername(d.Username),
+		Password:           domain.RestoreHashedPassword(d.Password),
+		Email:              domain.RestoreEmail(d.Email),
+		Roles:              roles,
+		Status:             status,
+		CreatedAt:          d.CreatedAt,
+		LastLoginAt:        d.LastLoginAt,
+		MfaEnabled:         d.MfaEnabled,
+		CanonicalUsername:  d.CanonicalUsername,
+		CanonicalEmail:     d.CanonicalEmail,
+		TokenVersion:       d.TokenVersion,
+		LegalHold:          d.LegalHold,
+		LockReason:         domain.LockReason(d.LockReason),
+		LockedUntil:        d.LockedUntil,
+		MustChangePassword: d.MustChangePassword,
+		Aliases:            aliases,
+		CanonicalAliases:   d.CanonicalAliases,
+		MergedInto:         d.MergedInto,
+		MfaFactors:         factors,
+	}
+}
+
+// duplicateKeyError maps a duplicate key error to the domain error of the violated unique index.
+func duplicateKeyError(err error) error {
+	if strings.Contains(err.Error(), canonicalEmailIndex) {
+		return errorx.ErrEmailTaken
+	}
+	return errorx.ErrUsernameTaken
+}
+
+// roleNames converts roles into the strings stored in the documents.
+func roleNames(roles []domain.Role) []string {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, string(role))
+	}
+	return names
+}
+
+// usernameStrings converts usernames into their stored form.
+func usernameStrings(usernames []domain.Username) []string {
+	strs := make([]string, 0, len(usernames))
+	for _, username := range usernames {
+		strs = append(strs, username.String())
+	}
+	return strs
+}
+
 // UserPersistenceMongoAdapter implements the persistence layer for user-related operations.
 // It encapsulates the MongoDB client and collection for user data.
 type UserPersistenceMongoAdapter struct {
-	client     *mongo.Client
-	collection *mongo.Collection
+	client            *mongo.Client
+	collection        *mongo.Collection
+	archiveCollection *mongo.Collection
 }
 
 // NewUserPersistenceMongoAdapter creates and initializes a new UserPersistenceMongoAdapter.
 //
 // It establishes a connection to MongoDB using the provided connection string and database name.
-// The adapter uses a "user" collection within the specified database for all operations.
+// The adapter uses a "user" collection within the specified database for all operations
+// and a "user_archive" collection for archived accounts. The indexes the adapter relies on
+// are created if they do not exist yet.
 //
 // Parameters:
 //   - connectionString: MongoDB connection URI
@@ -30,49 +169,80 @@ type UserPersistenceMongoAdapter struct {
 //
 // Returns:
 //   - *UserPersistenceMongoAdapter: A pointer to the newly created adapter
-//   - error: An error if the connection fails or cannot be verified
+//   - error: An error if the connection fails or the indexes cannot be created
 func NewUserPersistenceMongoAdapter(client *mongo.Client, database string) (*UserPersistenceMongoAdapter, error) {
 	collection := client.Database(database).Collection("user")
+	archiveCollection := client.Database(database).Collection("user_archive")
+
+	adapter := &UserPersistenceMongoAdapter{client, collection, archiveCollection}
 
-	return &UserPersistenceMongoAdapter{client, collection}, nil
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := adapter.ensureIndexes(ctx); err != nil {
+		return nil, err
+	}
+
+	return adapter, nil
 }
 
-// SaveUser stores user credentials in the MongoDB database.
+// SaveUser stores a new user in the MongoDB database.
 //
-// It creates a new document in the "user" collection with the provided username,
-// hashed password, and the current timestamp.
+// It creates a new document in the "user" collection from the given user.
 //
 // Parameters:
-//   - username: The username of the user to be saved
-//   - hashedPassword: The pre-hashed password of the user
+//   - ctx: A context.Context for cancelling the operation
+//   - user: The user to be saved, as created by domain.NewUser
 //
 // Returns:
-//   - error: An error if the save operation fails, nil otherwise
-//
-// The function logs the ID of the newly inserted document on success.
-func (u *UserPersistenceMongoAdapter) SaveUser(username string, hashedPassword string) error {
-	user := bson.M{
-		"username":  username,
-		"password":  hashedPassword,
-		"role":      "USER",
-		"createdAt": time.Now(),
-	}
-
-	res, err := u.collection.InsertOne(context.Background(), user)
+//   - string: The ID of the newly inserted document
+//   - error: errorx.ErrUsernameTaken if the username or its canonical form is already in use, errorx.ErrEmailTaken
+//     if the canonical email is, another error if the save operation fails, nil otherwise
+func (u *UserPersistenceMongoAdapter) SaveUser(ctx context.Context, user domain.User) (string, error) {
+	res, err := u.collection.InsertOne(ctx, newUserDocument(user))
 	if err != nil {
-		return fmt.Errorf("failed to save user: %w", err)
+		if mongo.IsDuplicateKeyError(err) {
+			return "", duplicateKeyError(err)
+		}
+		return "", fmt.Errorf("failed to save user: %w", err)
 	}
 
-	log.Printf("User saved successfully with ID: %v", res.InsertedID)
-	return nil
+	id, _ := res.InsertedID.(primitive.ObjectID)
+	return id.Hex(), nil
+}
+
+// newUserDocument converts a new domain.User into the document inserted for it.
+func newUserDocument(user domain.User) userDocument {
+	return userDocument{
+		TenantID:           user.TenantID,
+		Username:           user.Username.String(),
+		Password:           user.Password.String(),
+		Email:              user.Email.String(),
+		Roles:              roleNames(user.Roles),
+		Status:             string(user.Status),
+		CreatedAt:          user.CreatedAt,
+		LastLoginAt:        user.LastLoginAt,
+		MfaEnabled:         user.MfaEnabled,
+		CanonicalUsername:  user.CanonicalUsername,
+		CanonicalEmail:     user.CanonicalEmail,
+		TokenVersion:       user.TokenVersion,
+		LegalHold:          user.LegalHold,
+		MustChangePassword: user.MustChangePassword,
+		Aliases:            usernameStrings(user.Aliases),
+		CanonicalAliases:   user.CanonicalAliases,
+		MergedInto:         user.MergedInto,
+		MfaFactors:         mfaFactorDocuments(user.MfaFactors),
+	}
 }
 
 // IsUsernameAvailable checks if a given username is available for registration.
 //
-// It queries the database for an existing user with the provided username.
+// It queries the database for an existing user with the provided username or canonical username,
+// as username or alias, including soft-deleted users, whose usernames stay taken until they are purged.
 //
 // Parameters:
+//   - ctx: A context.Context for cancelling the operation
 //   - username: The username to check for availability
+//   - canonicalUsername: The canonical key of the username
 //
 // Returns:
 //   - bool: true if the username is available, false if it's already taken
@@ -80,9 +250,14 @@ func (u *UserPersistenceMongoAdapter) SaveUser(username string, hashedPassword s
 //
 // Note: This function returns false for both an existing username and a database error.
 // Check the error value to distinguish between these cases.
-func (u *UserPersistenceMongoAdapter) IsUsernameAvailable(username string) (bool, error) {
-	filter := bson.M{"username": username}
-	existingUser := u.collection.FindOne(context.Background(), filter)
+func (u *UserPersistenceMongoAdapter) IsUsernameAvailable(ctx context.Context, username domain.Username, canonicalUsername string) (bool, error) {
+	filter := bson.M{"$or": bson.A{
+		bson.M{"username": username.String()},
+		bson.M{"canonicalUsername": canonicalUsername},
+		bson.M{"aliases": username.String()},
+		bson.M{"canonicalAliases": canonicalUsername},
+	}}
+	existingUser := u.collection.FindOne(ctx, filter)
 	if existingUser.Err() == nil {
 		return false, nil
 	}
@@ -96,39 +271,147 @@ func (u *UserPersistenceMongoAdapter) IsUsernameAvailable(username string) (bool
 
 // FindUser retrieves a user from the MongoDB database by their username.
 //
-// This method queries the MongoDB collection for a user document matching the given username.
-// If found, it constructs and returns a domain.User struct with the user's information.
+// This method queries the MongoDB collection for a user document matching the given username,
+// which may also be an alias of the user. Soft-deleted users are not found. If found, it constructs and returns a domain.User struct with the user's information.
 //
 // Parameters:
-//   - username: A string representing the username of the user to find.
-//   - password: A string representing the password of the user (Note: This parameter is currently unused in the method body).
+//   - ctx: A context.Context for cancelling the operation.
+//   - username: The normalized username of the user to find.
 //
 // Returns:
 //   - domain.User: A User struct containing the user's information if found.
 //   - error: An error if the user is not found or if there's a database error.
-//     The error will be "user not found" if no matching user document is found,
+//     The error will be errorx.ErrUserNotFound if no matching user document is found,
 //     or "failed to load user: [specific error]" for other database errors.
-//
-// Note:
-//   - The password parameter is currently not used in the method body. Consider removing it if it's not needed.
-//   - This method assumes that the "username" and "password" fields in the MongoDB document are strings.
-//     It will panic if these fields are of a different type.
-func (u *UserPersistenceMongoAdapter) FindUser(username string) (domain.User, error) {
-	var result bson.M
-	err := u.collection.FindOne(context.Background(), bson.M{"username": username}).Decode(&result)
+func (u *UserPersistenceMongoAdapter) FindUser(ctx context.Context, username domain.Username) (domain.User, error) {
+	var doc userDocument
+	filter := bson.M{
+		"$or":       bson.A{bson.M{"username": username.String()}, bson.M{"aliases": username.String()}},
+		"deletedAt": bson.M{"$exists": false},
+	}
+	err := u.collection.FindOne(ctx, filter).Decode(&doc)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
-			return domain.User{}, fmt.Errorf("user not found")
+			return domain.User{}, errorx.ErrUserNotFound
 		}
 		return domain.User{}, fmt.Errorf("failed to load user: %w", err)
 	}
 
-	user := domain.User{
-		Username: result["username"].(string),
-		Password: result["password"].(string),
+	return doc.toDomain(), nil
+}
+
+// UpdateLastLogin records the time of the user's most recent successful login.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - username: The username of the user who logged in
+//   - loginAt: The time of the login
+//
+// Returns:
+//   - error: An error if the update fails, nil otherwise
+func (u *UserPersistenceMongoAdapter) UpdateLastLogin(ctx context.Context, username domain.Username, loginAt time.Time) error {
+	_, err := u.collection.UpdateOne(ctx,
+		bson.M{"username": username.String()},
+		bson.M{"$set": bson.M{"lastLoginAt": loginAt}})
+	if err != nil {
+		return fmt.Errorf("failed to update last login: %w", err)
 	}
+	return nil
+}
 
-	return user, nil
+// UpdatePasswordHash replaces the password hash of a user with a hash of the same password, e.g.
+// one created with the configured algorithm after a login verified it.
+//
+// The hash is only replaced while the user still has the previous hash, so a password changed in
+// the meantime is not overwritten with the old one.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - username: The username of the user
+//   - previous: The hash the password was verified against
+//   - password: The new hash of the same password
+//
+// Returns:
+//   - error: An error if the update fails, nil otherwise
+func (u *UserPersistenceMongoAdapter) UpdatePasswordHash(ctx context.Context, username domain.Username, previous domain.HashedPassword, password domain.HashedPassword) error {
+	_, err := u.collection.UpdateOne(ctx,
+		bson.M{"username": username.String(), "password": previous.String()},
+		bson.M{"$set": bson.M{"password": password.String()}})
+	if err != nil {
+		return fmt.Errorf("failed to update password hash: %w", err)
+	}
+	return nil
+}
+
+// ArchiveUsersInactiveSince moves users without activity since the cutoff into the "user_archive" collection.
+//
+// A user counts as inactive if its last login (or, if it never logged in, its registration)
+// lies before the cutoff. Soft-deleted users are left to the purge. Every document is first
+// upserted into the archive and only then removed from the active collection, so an
+// interrupted run can safely be repeated.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - cutoff: Users inactive since before this time are archived
+//
+// Returns:
+//   - int64: The number of archived users
+//   - error: An error if querying, archiving or deleting fails
+func (u *UserPersistenceMongoAdapter) ArchiveUsersInactiveSince(ctx context.Context, cutoff time.Time) (int64, error) {
+	filter := bson.M{
+		"deletedAt": bson.M{"$exists": false},
+		"$or": bson.A{
+			bson.M{"lastLoginAt": bson.M{"$lt": cutoff}},
+			bson.M{"lastLoginAt": bson.M{"$exists": false}, "createdAt": bson.M{"$lt": cutoff}},
+		},
+	}
+
+	cursor, err := u.collection.Find(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find inactive users: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var archived int64
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return archived, fmt.Errorf("failed to decode inactive user: %w", err)
+		}
+		doc["archivedAt"] = time.Now()
+
+		_, err := u.archiveCollection.ReplaceOne(ctx, bson.M{"_id": doc["_id"]}, doc, options.Replace().SetUpsert(true))
+		if err != nil {
+			return archived, fmt.Errorf("failed to archive user: %w", err)
+		}
+		if _, err := u.collection.DeleteOne(ctx, bson.M{"_id": doc["_id"]}); err != nil {
+			return archived, fmt.Errorf("failed to remove archived user: %w", err)
+		}
+		archived++
+	}
+
+	if err := cursor.Err(); err != nil {
+		return archived, fmt.Errorf("failed to iterate inactive users: %w", err)
+	}
+	return archived, nil
+}
+
+// PurgeUsersDeletedBefore permanently removes users that were soft-deleted before the cutoff.
+// Users under legal hold are kept.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - cutoff: Users soft-deleted before this time are purged
+//
+// Returns:
+//   - int64: The number of purged users
+//   - error: An error if the delete operation fails
+func (u *UserPersistenceMongoAdapter) PurgeUsersDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := u.collection.DeleteMany(ctx, bson.M{"deletedAt": bson.M{"$lt": cutoff}, "legalHold": bson.M{"$ne": true}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted users: %w", err)
+	}
+	return res.DeletedCount, nil
 }
 
 // Close terminates the connection to the MongoDB database.
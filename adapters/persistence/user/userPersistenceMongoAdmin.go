@@ -0,0 +1,340 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"regexp"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/requestid"
+	"user-auth-hexagonal-architecture/internal/tenant"
+)
+
+// requestComment returns the request id of ctx to be attached as comment to MongoDB operations,
+// which makes it visible in the profiler and slow query logs.
+func requestComment(ctx context.Context) (string, bool) {
+	id := requestid.FromContext(ctx)
+	return id, id != ""
+}
+
+// inTenant restricts a filter to the users of the tenant of the request carried by ctx, so an
+// administrator cannot read or change the users of another tenant. Documents without a tenant
+// belong to the default tenant. Contexts without a tenant, e.g. of background jobs, are not restricted.
+func inTenant(ctx context.Context, filter bson.M) bson.M {
+	switch id := tenant.FromContext(ctx); id {
+	case "":
+	case domain.DefaultTenantID:
+		filter["tenantId"] = bson.M{"$in": bson.A{id, nil}}
+	default:
+		filter["tenantId"] = id
+	}
+	return filter
+}
+
+// byIDFilter builds a filter matching the active (not soft-deleted) user with the given hex id in
+// the tenant of the request. Malformed ids cannot match any user and are reported as errorx.ErrUserNotFound.
+func byIDFilter(ctx context.Context, id string) (bson.M, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errorx.ErrUserNotFound
+	}
+	return inTenant(ctx, bson.M{"_id": objectID, "deletedAt": bson.M{"$exists": false}}), nil
+}
+
+// FindUserByID retrieves an active user of the tenant of the request by its id.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The hex encoded id of the user
+//
+// Returns:
+//   - domain.User: The user if found
+//   - error: errorx.ErrUserNotFound if no active user of the tenant has the id, or a wrapped database error
+func (u *UserPersistenceMongoAdapter) FindUserByID(ctx context.Context, id string) (domain.User, error) {
+	filter, err := byIDFilter(ctx, id)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	opts := options.FindOne()
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	var doc userDocument
+	if err := u.collection.FindOne(ctx, filter, opts).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.User{}, errorx.ErrUserNotFound
+		}
+		return domain.User{}, fmt.Errorf("failed to load user: %w", err)
+	}
+	return doc.toDomain(), nil
+}
+
+// SearchUsers finds active users of the tenant of the request whose username or email contains the
+// term, ignoring case, sorted by username.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - term: The text to search for, the empty term matches every active user
+//   - offset: The number of matches to skip
+//   - limit: The maximum number of users to return
+//
+// Returns:
+//   - []domain.User: The requested page of matches
+//   - int64: The total number of matches
+//   - error: A wrapped database error
+func (u *UserPersistenceMongoAdapter) SearchUsers(ctx context.Context, term string, offset int64, limit int64) ([]domain.User, int64, error) {
+	filter := inTenant(ctx, bson.M{"deletedAt": bson.M{"$exists": false}})
+	if term != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(term), Options: "i"}
+		filter["$or"] = bson.A{bson.M{"username": pattern}, bson.M{"email": pattern}}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "username", Value: 1}}).SetSkip(offset).SetLimit(limit)
+	countOpts := options.Count()
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+		countOpts.SetComment(comment)
+	}
+
+	total, err := u.collection.CountDocuments(ctx, filter, countOpts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	cursor, err := u.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search users: %w", err)
+	}
+	var docs []userDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode users: %w", err)
+	}
+
+	users := make([]domain.User, 0, len(docs))
+	for _, doc := range docs {
+		users = append(users, doc.toDomain())
+	}
+	return users, total, nil
+}
+
+// UpdateUsername changes the username of a user.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The hex encoded id of the user
+//   - username: The new username
+//   - canonicalUsername: The canonical key of the new username
+//
+// Returns:
+//   - error: errorx.ErrUsernameTaken if another user has the username or its canonical form,
+//     errorx.ErrUserNotFound if no active user has the id, or a wrapped database error
+func (u *UserPersistenceMongoAdapter) UpdateUsername(ctx context.Context, id string, username domain.Username, canonicalUsername string) error {
+	err := u.updateByID(ctx, id, bson.M{"$set": bson.M{"username": username.String(), "canonicalUsername": canonicalUsername}})
+	if mongo.IsDuplicateKeyError(err) {
+		return errorx.ErrUsernameTaken
+	}
+	return err
+}
+
+// UpdateUserRoles replaces the roles and the token version of a user. The single role field of older documents is removed.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The hex encoded id of the user
+//   - roles: The new roles
+//   - tokenVersion: The token version after the change
+//
+// Returns:
+//   - error: errorx.ErrUserNotFound if no active user has the id, or a wrapped database error
+func (u *UserPersistenceMongoAdapter) UpdateUserRoles(ctx context.Context, id string, roles []domain.Role, tokenVersion int) error {
+	return u.updateByID(ctx, id, bson.M{"$set": bson.M{"roles": roleNames(roles), "tokenVersion": tokenVersion}, "$unset": bson.M{"role": ""}})
+}
+
+// CountActiveUsersWithRole counts the users of the tenant of the request that are neither deleted
+// nor disabled and hold a role, including documents that still store a single role and documents
+// without a status.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - role: The role to count the holders of
+//
+// Returns:
+//   - int64: The number of active users holding the role
+//   - error: A wrapped database error
+func (u *UserPersistenceMongoAdapter) CountActiveUsersWithRole(ctx context.Context, role domain.Role) (int64, error) {
+	filter := inTenant(ctx, bson.M{
+		"deletedAt": bson.M{"$exists": false},
+		"status":    bson.M{"$in": bson.A{string(domain.StatusActive), nil}},
+		"$or":       bson.A{bson.M{"roles": string(role)}, bson.M{"role": string(role)}},
+	})
+	opts := options.Count()
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	count, err := u.collection.CountDocuments(ctx, filter, opts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count users with role: %w", err)
+	}
+	return count, nil
+}
+
+// UpdateUserStatus replaces the account status of a user. The reason and expiry of a lock are
+// removed, unless the new status is LOCKED.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The hex encoded id of the user
+//   - status: The new status
+//
+// Returns:
+//   - error: errorx.ErrUserNotFound if no active user has the id, or a wrapped database error
+func (u *UserPersistenceMongoAdapter) UpdateUserStatus(ctx context.Context, id string, status domain.AccountStatus) error {
+	update := bson.M{"$set": bson.M{"status": string(status)}}
+	if status != domain.StatusLocked {
+		update["$unset"] = bson.M{"lockReason": "", "lockedUntil": ""}
+	}
+	return u.updateByID(ctx, id, update)
+}
+
+// UpdateUserLock locks a user, or replaces the reason and expiry of its lock.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The hex encoded id of the user
+//   - reason: Why the user is locked
+//   - until: When the lock expires, the zero time for a lock without expiry
+//
+// Returns:
+//   - error: errorx.ErrUserNotFound if no active user has the id, or a wrapped database error
+func (u *UserPersistenceMongoAdapter) UpdateUserLock(ctx context.Context, id string, reason domain.LockReason, until time.Time) error {
+	set := bson.M{"status": string(domain.StatusLocked), "lockReason": string(reason)}
+	update := bson.M{"$set": set}
+	if until.IsZero() {
+		update["$unset"] = bson.M{"lockedUntil": ""}
+	} else {
+		set["lockedUntil"] = until
+	}
+	return u.updateByID(ctx, id, update)
+}
+
+// FindUsersWithExpiredLocks retrieves the locked users whose lock expired at or before now.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - now: The current time
+//
+// Returns:
+//   - []domain.User: The users, empty if there are none
+//   - error: A wrapped database error
+func (u *UserPersistenceMongoAdapter) FindUsersWithExpiredLocks(ctx context.Context, now time.Time) ([]domain.User, error) {
+	filter := bson.M{
+		"deletedAt":   bson.M{"$exists": false},
+		"status":      string(domain.StatusLocked),
+		"lockedUntil": bson.M{"$lte": now},
+	}
+	opts := options.Find()
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	cursor, err := u.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find users with expired locks: %w", err)
+	}
+	var docs []userDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode users: %w", err)
+	}
+
+	users := make([]domain.User, 0, len(docs))
+	for _, doc := range docs {
+		users = append(users, doc.toDomain())
+	}
+	return users, nil
+}
+
+// SoftDeleteUser marks a user as deleted and sets its status to DELETED. The MFA factors are removed
+// in the same update, so their secrets are not kept for the retention window. The document is kept
+// until the retention job purges it after the configured retention window.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The hex encoded id of the user
+//   - deletedAt: The deletion time
+//
+// Returns:
+//   - error: errorx.ErrUserNotFound if no active user has the id, or a wrapped database error
+func (u *UserPersistenceMongoAdapter) SoftDeleteUser(ctx context.Context, id string, deletedAt time.Time) error {
+	return u.updateByID(ctx, id, bson.M{"$set": bson.M{
+		"deletedAt":  deletedAt,
+		"status":     string(domain.StatusDeleted),
+		"mfaFactors": mfaFactorDocuments(nil),
+		"mfaEnabled": false,
+	}})
+}
+
+// UpdatePassword replaces the password hash of a user and sets or clears the flag requiring the
+// user to change it at the next login.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The hex encoded id of the user
+//   - password: The new password hash
+//   - mustChangePassword: Whether the password is a temporary one
+//
+// Returns:
+//   - error: errorx.ErrUserNotFound if no active user has the id, or a wrapped database error
+func (u *UserPersistenceMongoAdapter) UpdatePassword(ctx context.Context, id string, password domain.HashedPassword, mustChangePassword bool) error {
+	set := bson.M{"password": password.String()}
+	update := bson.M{"$set": set}
+	if mustChangePassword {
+		set["mustChangePassword"] = true
+	} else {
+		update["$unset"] = bson.M{"mustChangePassword": ""}
+	}
+	return u.updateByID(ctx, id, update)
+}
+
+// UpdateMfa replaces the second factors of a user and the flag whether multi-factor authentication is enabled.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The hex encoded id of the user
+//   - factors: The second factors of the user, pending and confirmed
+//   - mfaEnabled: Whether the user has a confirmed second factor
+//
+// Returns:
+//   - error: errorx.ErrUserNotFound if no active user has the id, or a wrapped database error
+func (u *UserPersistenceMongoAdapter) UpdateMfa(ctx context.Context, id string, factors []domain.MfaFactor, mfaEnabled bool) error {
+	return u.updateByID(ctx, id, bson.M{"$set": bson.M{"mfaFactors": mfaFactorDocuments(factors), "mfaEnabled": mfaEnabled}})
+}
+
+// updateByID applies an update to the active user of the tenant of the request with the given id.
+func (u *UserPersistenceMongoAdapter) updateByID(ctx context.Context, id string, update bson.M) error {
+	filter, err := byIDFilter(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	opts := options.Update()
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	res, err := u.collection.UpdateOne(ctx, filter, update, opts)
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return errorx.ErrUserNotFound
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// FindUserForErasure retrieves a user of the tenant of the request by its id, including
+// soft-deleted users and users moved into the archive.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The hex encoded id of the user
+//
+// Returns:
+//   - domain.User: The user
+//   - error: errorx.ErrUserNotFound if no user has the id, or a wrapped database error
+func (u *UserPersistenceMongoAdapter) FindUserForErasure(ctx context.Context, id string) (domain.User, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.User{}, errorx.ErrUserNotFound
+	}
+
+	for _, collection := range []*mongo.Collection{u.collection, u.archiveCollection} {
+		var doc userDocument
+		err := collection.FindOne(ctx, inTenant(ctx, bson.M{"_id": objectID})).Decode(&doc)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			continue
+		}
+		if err != nil {
+			return domain.User{}, fmt.Errorf("failed to load user: %w", err)
+		}
+		return doc.toDomain(), nil
+	}
+	return domain.User{}, errorx.ErrUserNotFound
+}
+
+// UpdateLegalHold places or lifts a legal hold on a user of the tenant of the request, including
+// soft-deleted and archived users.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The hex encoded id of the user
+//   - legalHold: Whether the user is under legal hold
+//
+// Returns:
+//   - error: errorx.ErrUserNotFound if no user has the id, or a wrapped database error
+func (u *UserPersistenceMongoAdapter) UpdateLegalHold(ctx context.Context, id string, legalHold bool) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errorx.ErrUserNotFound
+	}
+
+	update := bson.M{"$set": bson.M{"legalHold": true}}
+	if !legalHold {
+		update = bson.M{"$unset": bson.M{"legalHold": ""}}
+	}
+	for _, collection := range []*mongo.Collection{u.collection, u.archiveCollection} {
+		res, err := collection.UpdateOne(ctx, inTenant(ctx, bson.M{"_id": objectID}), update)
+		if err != nil {
+			return fmt.Errorf("failed to update legal hold: %w", err)
+		}
+		if res.MatchedCount > 0 {
+			return nil
+		}
+	}
+	return errorx.ErrUserNotFound
+}
+
+// EraseUser permanently removes a user of the tenant of the request, including its profile, from
+// the active users and the archive.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The hex encoded id of the user
+//
+// Returns:
+//   - int64: The number of removed documents
+//   - error: errorx.ErrUserNotFound if the id is malformed, or a wrapped database error
+func (u *UserPersistenceMongoAdapter) EraseUser(ctx context.Context, id string) (int64, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return 0, errorx.ErrUserNotFound
+	}
+
+	var erased int64
+	for _, collection := range []*mongo.Collection{u.collection, u.archiveCollection} {
+		res, err := collection.DeleteOne(ctx, inTenant(ctx, bson.M{"_id": objectID}))
+		if err != nil {
+			return erased, fmt.Errorf("failed to erase user: %w", err)
+		}
+		erased += res.DeletedCount
+	}
+	return erased, nil
+}
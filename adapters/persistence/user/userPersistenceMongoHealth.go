@@ -0,0 +1,158 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	ports "user-auth-hexagonal-architecture/internal/ports/persistence"
+)
+
+// namespaceNotFound is the MongoDB error code returned for statistics of collections that do not exist yet.
+const namespaceNotFound = 26
+
+// canonicalEmailIndex is the name of the unique index on the canonical email.
+const canonicalEmailIndex = "canonicalEmail_1"
+
+// userIndexes lists the indexes the "user" collection relies on. The canonical keys are only
+// indexed where present, as users stored before canonicalization was introduced lack them. The
+// creation time is indexed for the registration statistics, the aliases of merged accounts for logins.
+var userIndexes = []mongo.IndexModel{
+	{
+		Keys:    bson.D{{Key: "username", Value: 1}},
+		Options: options.Index().SetName("username_1").SetUnique(true),
+	},
+	{
+		Keys: bson.D{{Key: "canonicalUsername", Value: 1}},
+		Options: options.Index().SetName("canonicalUsername_1").SetUnique(true).
+			SetPartialFilterExpression(bson.M{"canonicalUsername": bson.M{"$exists": true}}),
+	},
+	{
+		Keys: bson.D{{Key: "canonicalEmail", Value: 1}},
+		Options: options.Index().SetName(canonicalEmailIndex).SetUnique(true).
+			SetPartialFilterExpression(bson.M{"canonicalEmail": bson.M{"$exists": true}}),
+	},
+	{
+		Keys:    bson.D{{Key: "createdAt", Value: 1}},
+		Options: options.Index().SetName("createdAt_1"),
+	},
+	{
+		Keys:    bson.D{{Key: "aliases", Value: 1}},
+		Options: options.Index().SetName("aliases_1").SetSparse(true),
+	},
+	{
+		Keys:    bson.D{{Key: "canonicalAliases", Value: 1}},
+		Options: options.Index().SetName("canonicalAliases_1").SetSparse(true),
+	},
+}
+
+// ensureIndexes creates the indexes the adapter relies on if they do not exist yet.
+func (u *UserPersistenceMongoAdapter) ensureIndexes(ctx context.Context) error {
+	if _, err := u.collection.Indexes().CreateMany(ctx, userIndexes); err != nil {
+		return fmt.Errorf("failed to create user indexes: %w", err)
+	}
+	return nil
+}
+
+// Ping verifies that the MongoDB deployment is reachable.
+//
+// Parameters:
+//   - ctx: A context.Context bounding the duration of the ping
+//
+// Returns:
+//   - error: An error if the deployment cannot be reached
+func (u *UserPersistenceMongoAdapter) Ping(ctx context.Context) error {
+	return u.client.Ping(ctx, nil)
+}
+
+// Stats gathers the user count, collection sizes and index health of the adapter's collections.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//
+// Returns:
+//   - ports.PersistenceStats: The collected statistics
+//   - error: An error if any of the statistics queries fail
+func (u *UserPersistenceMongoAdapter) Stats(ctx context.Context) (ports.PersistenceStats, error) {
+	stats := ports.PersistenceStats{Backend: "mongodb"}
+
+	for _, collection := range []*mongo.Collection{u.collection, u.archiveCollection} {
+		collectionStats, err := collectionStats(ctx, collection)
+		if err != nil {
+			return stats, err
+		}
+		stats.Collections = append(stats.Collections, collectionStats)
+	}
+
+	userCount, err := u.collection.CountDocuments(ctx, bson.M{"deletedAt": bson.M{"$exists": false}})
+	if err != nil {
+		return stats, fmt.Errorf("failed to count users: %w", err)
+	}
+	stats.UserCount = userCount
+
+	indexes, err := indexHealth(ctx, u.collection, userIndexes)
+	if err != nil {
+		return stats, err
+	}
+	stats.Indexes = indexes
+
+	return stats, nil
+}
+
+// collectionStats reads the storage statistics of a collection via the $collStats aggregation stage.
+// Collections that have not been created yet are reported as empty.
+func collectionStats(ctx context.Context, collection *mongo.Collection) (ports.CollectionStats, error) {
+	result := ports.CollectionStats{Name: collection.Name()}
+
+	pipeline := mongo.Pipeline{{{Key: "$collStats", Value: bson.M{"storageStats": bson.M{}}}}}
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		var cmdErr mongo.CommandError
+		if errors.As(err, &cmdErr) && cmdErr.Code == namespaceNotFound {
+			return result, nil
+		}
+		return result, fmt.Errorf("failed to read stats of %s: %w", collection.Name(), err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		StorageStats struct {
+			Count          int64 `bson:"count"`
+			Size           int64 `bson:"size"`
+			StorageSize    int64 `bson:"storageSize"`
+			TotalIndexSize int64 `bson:"totalIndexSize"`
+		} `bson:"storageStats"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return result, fmt.Errorf("failed to decode stats of %s: %w", collection.Name(), err)
+	}
+	if len(docs) > 0 {
+		result.DocumentCount = docs[0].StorageStats.Count
+		result.SizeBytes = docs[0].StorageStats.Size
+		result.StorageBytes = docs[0].StorageStats.StorageSize
+		result.IndexSizeBytes = docs[0].StorageStats.TotalIndexSize
+	}
+	return result, nil
+}
+
+// indexHealth checks which of the expected indexes exist on a collection.
+func indexHealth(ctx context.Context, collection *mongo.Collection, expected []mongo.IndexModel) ([]ports.IndexHealth, error) {
+	specs, err := collection.Indexes().ListSpecifications(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexes of %s: %w", collection.Name(), err)
+	}
+
+	existing := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		existing[spec.Name] = true
+	}
+
+	health := make([]ports.IndexHealth, 0, len(expected))
+	for _, index := range expected {
+		name := *index.Options.Name
+		health = append(health, ports.IndexHealth{Collection: collection.Name(), Name: name, Present: existing[name]})
+	}
+	return health, nil
+}
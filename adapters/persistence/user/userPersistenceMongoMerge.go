@@ -0,0 +1,65 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// SaveMergedAccounts stores the outcome of merging a duplicate account into a primary account.
+//
+// The duplicate is renamed, loses its email address and aliases and is soft-deleted first, which
+// frees the unique keys the primary account may take over. The primary account is updated with its
+// merged roles, token version, email address and aliases afterwards. Both updates only apply to
+// users that are not deleted, so a merge of an account deleted in the meantime fails before
+// anything is changed.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - merge: The merged accounts
+//
+// Returns:
+//   - error: errorx.ErrUserNotFound if one of the accounts no longer exists, errorx.ErrEmailTaken if the
+//     email address has been taken in the meantime, or a wrapped database error
+func (u *UserPersistenceMongoAdapter) SaveMergedAccounts(ctx context.Context, merge domain.AccountMerge) error {
+	primary, duplicate := merge.Primary, merge.Duplicate
+	if _, err := u.FindUserByID(ctx, primary.ID); err != nil {
+		return err
+	}
+
+	err := u.updateByID(ctx, duplicate.ID, bson.M{
+		"$set": bson.M{
+			"username":          duplicate.Username.String(),
+			"canonicalUsername": duplicate.CanonicalUsername,
+			"status":            string(duplicate.Status),
+			"deletedAt":         merge.MergedAt,
+			"mergedInto":        duplicate.MergedInto,
+		},
+		"$unset": bson.M{"email": "", "canonicalEmail": "", "aliases": "", "canonicalAliases": ""},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete merged account: %w", err)
+	}
+
+	set := bson.M{"roles": roleNames(primary.Roles), "tokenVersion": primary.TokenVersion}
+	update := bson.M{"$set": set}
+	if merge.EmailMoved {
+		set["email"] = primary.Email.String()
+		set["canonicalEmail"] = primary.CanonicalEmail
+	}
+	if len(primary.Aliases) > 0 {
+		set["aliases"] = usernameStrings(primary.Aliases)
+		set["canonicalAliases"] = primary.CanonicalAliases
+	}
+	err = u.updateByID(ctx, primary.ID, update)
+	if mongo.IsDuplicateKeyError(err) {
+		return errorx.ErrEmailTaken
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update primary account: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// profileDocument is the MongoDB representation of a domain.Profile, embedded in the user document.
+type profileDocument struct {
+	DisplayName   string                       `bson:"displayName,omitempty"`
+	Locale        string                       `bson:"locale,omitempty"`
+	Timezone      string                       `bson:"timezone,omitempty"`
+	AvatarURL     string                       `bson:"avatarUrl,omitempty"`
+	Attributes    map[string]attributeDocument `bson:"attributes,omitempty"`
+	PhoneNumber   string                       `bson:"phoneNumber,omitempty"`
+	Notifications map[string][]string          `bson:"notifications,omitempty"`
+	UpdatedAt     time.Time                    `bson:"updatedAt,omitempty"`
+}
+
+// attributeDocument is the MongoDB representation of a domain.ProfileAttribute.
+type attributeDocument struct {
+	Value      string `bson:"value"`
+	Visibility string `bson:"visibility"`
+}
+
+// FindProfile retrieves the profile of a user. Users that never changed their profile have an empty one.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - username: The normalized username of the user
+//
+// Returns:
+//   - domain.Profile: The profile
+//   - error: errorx.ErrUserNotFound if the user does not exist or is soft-deleted, or a wrapped database error
+func (u *UserPersistenceMongoAdapter) FindProfile(ctx context.Context, username domain.Username) (domain.Profile, error) {
+	var doc struct {
+		Profile profileDocument `bson:"profile"`
+	}
+	filter := bson.M{"username": username.String(), "deletedAt": bson.M{"$exists": false}}
+	err := u.collection.FindOne(ctx, filter, options.FindOne().SetProjection(bson.M{"profile": 1})).Decode(&doc)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.Profile{}, errorx.ErrUserNotFound
+		}
+		return domain.Profile{}, fmt.Errorf("failed to load profile: %w", err)
+	}
+
+	profile := domain.Profile{
+		DisplayName: doc.Profile.DisplayName,
+		Locale:      doc.Profile.Locale,
+		Timezone:    doc.Profile.Timezone,
+		AvatarURL:   doc.Profile.AvatarURL,
+		Attributes:  make(map[string]domain.ProfileAttribute, len(doc.Profile.Attributes)),
+		PhoneNumber: doc.Profile.PhoneNumber,
+		UpdatedAt:   doc.Profile.UpdatedAt,
+	}
+	for key, attribute := range doc.Profile.Attributes {
+		profile.Attributes[key] = domain.ProfileAttribute{Value: attribute.Value, Visibility: domain.AttributeVisibility(attribute.Visibility)}
+	}
+	if len(doc.Profile.Notifications) > 0 {
+		profile.Notifications = make(domain.NotificationPreferences, len(doc.Profile.Notifications))
+		for topic, channels := range doc.Profile.Notifications {
+			preferred := make([]domain.NotificationChannel, 0, len(channels))
+			for _, channel := range channels {
+				preferred = append(preferred, domain.NotificationChannel(channel))
+			}
+			profile.Notifications[domain.NotificationTopic(topic)] = preferred
+		}
+	}
+	return profile, nil
+}
+
+// UpdateProfile replaces the profile of a user.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - username: The normalized username of the user
+//   - profile: The new profile
+//
+// Returns:
+//   - error: errorx.ErrUserNotFound if the user does not exist or is soft-deleted, or a wrapped database error
+func (u *UserPersistenceMongoAdapter) UpdateProfile(ctx context.Context, username domain.Username, profile domain.Profile) error {
+	doc := profileDocument{
+		DisplayName: profile.DisplayName,
+		Locale:      profile.Locale,
+		Timezone:    profile.Timezone,
+		AvatarURL:   profile.AvatarURL,
+		Attributes:  make(map[string]attributeDocument, len(profile.Attributes)),
+		PhoneNumber: profile.PhoneNumber,
+		UpdatedAt:   profile.UpdatedAt,
+	}
+	for key, attribute := range profile.Attributes {
+		doc.Attributes[key] = attributeDocument{Value: attribute.Value, Visibility: string(attribute.Visibility)}
+	}
+	if len(profile.Notifications) > 0 {
+		doc.Notifications = make(map[string][]string, len(profile.Notifications))
+		for topic, channels := range profile.Notifications {
+			preferred := make([]string, 0, len(channels))
+			for _, channel := range channels {
+				preferred = append(preferred, string(channel))
+			}
+			doc.Notifications[string(topic)] = preferred
+		}
+	}
+
+	filter := bson.M{"username": username.String(), "deletedAt": bson.M{"$exists": false}}
+	res, err := u.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"profile": doc}})
+	if err != nil {
+		return fmt.Errorf("failed to update profile: %w", err)
+	}
+	if res.MatchedCount == 0 {
+		return errorx.ErrUserNotFound
+	}
+	return nil
+}
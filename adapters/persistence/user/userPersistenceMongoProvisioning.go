@@ -0,0 +1,62 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"user-auth-hexagonal-architecture/internal/domain"
+	ports "user-auth-hexagonal-architecture/internal/ports/persistence"
+)
+
+// duplicateKeyCode is the MongoDB error code of a unique index violation.
+const duplicateKeyCode = 11000
+
+// SaveUsers stores many new users with a single unordered insert, so a rejected user does not
+// keep the others from being stored.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - users: The users to be saved, as created by domain.NewUser
+//
+// Returns:
+//   - []ports.UserSaveResult: The id of every stored user, or errorx.ErrUsernameTaken or errorx.ErrEmailTaken
+//     if its username or email is already in use, in the order of the users
+//   - error: A wrapped database error if the insert failed as a whole
+func (u *UserPersistenceMongoAdapter) SaveUsers(ctx context.Context, users []domain.User) ([]ports.UserSaveResult, error) {
+	if len(users) == 0 {
+		return nil, nil
+	}
+	docs := make([]interface{}, len(users))
+	results := make([]ports.UserSaveResult, len(users))
+	for i, user := range users {
+		doc := newUserDocument(user)
+		// ids are assigned up front, so they are known for every user even if some inserts fail
+		doc.ID = primitive.NewObjectID()
+		docs[i] = doc
+		results[i].ID = doc.ID.Hex()
+	}
+
+	_, err := u.collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	if err == nil {
+		return results, nil
+	}
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil {
+		return nil, fmt.Errorf("failed to save users: %w", err)
+	}
+	for _, writeErr := range bulkErr.WriteErrors {
+		if writeErr.Index < 0 || writeErr.Index >= len(results) {
+			continue
+		}
+		results[writeErr.Index].ID = ""
+		if writeErr.Code == duplicateKeyCode {
+			results[writeErr.Index].Err = duplicateKeyError(writeErr)
+		} else {
+			results[writeErr.Index].Err = fmt.Errorf("failed to save user: %w", writeErr)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,97 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// CountRegistrationsPerDay counts the users of the tenant of the request created on every UTC day of
+// a period, including users deleted since. Days without registrations are omitted.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - period: The days to count
+//
+// Returns:
+//   - []domain.DailyCount: The registrations per day, in order
+//   - error: A wrapped database error
+func (u *UserPersistenceMongoAdapter) CountRegistrationsPerDay(ctx context.Context, period domain.StatsPeriod) ([]domain.DailyCount, error) {
+	pipeline := bson.A{
+		bson.M{"$match": inTenant(ctx, bson.M{"createdAt": bson.M{"$gte": period.From, "$lt": period.Until}})},
+		bson.M{"$group": bson.M{
+			"_id":   bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$createdAt", "timezone": "UTC"}},
+			"count": bson.M{"$sum": 1},
+		}},
+		bson.M{"$sort": bson.M{"_id": 1}},
+	}
+	opts := options.Aggregate()
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	cursor, err := u.collection.Aggregate(ctx, pipeline, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count registrations: %w", err)
+	}
+	var docs []struct {
+		Day   string `bson:"_id"`
+		Count int64  `bson:"count"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode registration counts: %w", err)
+	}
+
+	counts := make([]domain.DailyCount, 0, len(docs))
+	for _, doc := range docs {
+		day, err := time.Parse(time.DateOnly, doc.Day)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse registration day %q: %w", doc.Day, err)
+		}
+		counts = append(counts, domain.DailyCount{Day: day, Count: doc.Count})
+	}
+	return counts, nil
+}
+
+// CountMfaAdoption counts the users of the tenant of the request that are not deleted and how many of
+// them have enabled multi-factor authentication.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//
+// Returns:
+//   - domain.MfaAdoption: The counts
+//   - error: A wrapped database error
+func (u *UserPersistenceMongoAdapter) CountMfaAdoption(ctx context.Context) (domain.MfaAdoption, error) {
+	pipeline := bson.A{
+		bson.M{"$match": inTenant(ctx, bson.M{"deletedAt": bson.M{"$exists": false}})},
+		bson.M{"$group": bson.M{
+			"_id":        nil,
+			"users":      bson.M{"$sum": 1},
+			"mfaEnabled": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$mfaEnabled", true}}, 1, 0}}},
+		}},
+	}
+	opts := options.Aggregate()
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	cursor, err := u.collection.Aggregate(ctx, pipeline, opts)
+	if err != nil {
+		return domain.MfaAdoption{}, fmt.Errorf("failed to count mfa adoption: %w", err)
+	}
+	var docs []struct {
+		Users      int64 `bson:"users"`
+		MfaEnabled int64 `bson:"mfaEnabled"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return domain.MfaAdoption{}, fmt.Errorf("failed to decode mfa adoption: %w", err)
+	}
+	if len(docs) == 0 {
+		return domain.MfaAdoption{}, nil
+	}
+	return domain.MfaAdoption{Users: docs[0].Users, MfaEnabledUsers: docs[0].MfaEnabled}, nil
+}
@@ -0,0 +1,231 @@
+// Package persistence provides functionality for webhook persistence using MongoDB.
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/requestid"
+	"user-auth-hexagonal-architecture/internal/tenant"
+)
+
+// webhookDocument is the MongoDB representation of a domain.WebhookSubscription.
+type webhookDocument struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	TenantID  string             `bson:"tenantId,omitempty"`
+	URL       string             `bson:"url"`
+	Secret    string             `bson:"secret"`
+	Events    []string           `bson:"events"`
+	CreatedAt time.Time          `bson:"createdAt"`
+}
+
+// deadLetterDocument is the MongoDB representation of a domain.WebhookDeadLetter.
+type deadLetterDocument struct {
+	ID             string    `bson:"_id"`
+	TenantID       string    `bson:"tenantId,omitempty"`
+	SubscriptionID string    `bson:"subscriptionId"`
+	URL            string    `bson:"url"`
+	EventName      string    `bson:"eventName"`
+	Payload        []byte    `bson:"payload"`
+	Attempts       int       `bson:"attempts"`
+	LastError      string    `bson:"lastError"`
+	FailedAt       time.Time `bson:"failedAt"`
+}
+
+// WebhookMongoAdapter stores webhook subscriptions and dead letters in MongoDB.
+// It implements the WebhookPersistencePort and WebhookDeadLetterPersistencePort interfaces.
+type WebhookMongoAdapter struct {
+	webhooks    *mongo.Collection
+	deadLetters *mongo.Collection
+}
+
+// NewWebhookMongoAdapter creates and initializes a new WebhookMongoAdapter.
+//
+// The adapter uses a "webhooks" and a "webhook_dead_letters" collection within the specified
+// database. Dead letters are indexed by failure time, so the newest can be listed efficiently.
+//
+// Parameters:
+//   - client: A connected MongoDB client
+//   - database: Name of the database to use
+//
+// Returns:
+//   - *WebhookMongoAdapter: A pointer to the newly created adapter
+//   - error: An error if the index cannot be created
+func NewWebhookMongoAdapter(client *mongo.Client, database string) (*WebhookMongoAdapter, error) {
+	db := client.Database(database)
+	deadLetters := db.Collection("webhook_dead_letters")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := deadLetters.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "failedAt", Value: -1}},
+		Options: options.Index().SetName("failedAt_-1"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook dead letter index: %w", err)
+	}
+
+	return &WebhookMongoAdapter{db.Collection("webhooks"), deadLetters}, nil
+}
+
+// SaveWebhook inserts a new subscription.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - subscription: The subscription to save
+//
+// Returns:
+//   - string: The hex encoded id of the new subscription
+//   - error: A wrapped database error
+func (w *WebhookMongoAdapter) SaveWebhook(ctx context.Context, subscription domain.WebhookSubscription) (string, error) {
+	res, err := w.webhooks.InsertOne(ctx, webhookDocument{
+		TenantID:  subscription.TenantID,
+		URL:       subscription.URL,
+		Secret:    subscription.Secret,
+		Events:    subscription.Events,
+		CreatedAt: subscription.CreatedAt,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to insert webhook: %w", err)
+	}
+	id, _ := res.InsertedID.(primitive.ObjectID)
+	return id.Hex(), nil
+}
+
+// FindWebhooks returns the subscriptions of the tenant of the request in creation order.
+// Subscriptions without a tenant belong to the default tenant.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//
+// Returns:
+//   - []domain.WebhookSubscription: The subscriptions
+//   - error: A wrapped database error
+func (w *WebhookMongoAdapter) FindWebhooks(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	cursor, err := w.webhooks.Find(ctx, inTenant(ctx, bson.M{}), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find webhooks: %w", err)
+	}
+	var docs []webhookDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode webhooks: %w", err)
+	}
+
+	subscriptions := make([]domain.WebhookSubscription, 0, len(docs))
+	for _, doc := range docs {
+		tenantID := doc.TenantID
+		if tenantID == "" {
+			tenantID = domain.DefaultTenantID
+		}
+		subscriptions = append(subscriptions, domain.WebhookSubscription{
+			ID:        doc.ID.Hex(),
+			TenantID:  tenantID,
+			URL:       doc.URL,
+			Secret:    doc.Secret,
+			Events:    doc.Events,
+			CreatedAt: doc.CreatedAt,
+		})
+	}
+	return subscriptions, nil
+}
+
+// DeleteWebhook removes a subscription of the tenant of the request.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The hex encoded id of the subscription
+//
+// Returns:
+//   - error: errorx.ErrWebhookNotFound if no subscription of the tenant has the id, or a wrapped database error
+func (w *WebhookMongoAdapter) DeleteWebhook(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errorx.ErrWebhookNotFound
+	}
+
+	res, err := w.webhooks.DeleteOne(ctx, inTenant(ctx, bson.M{"_id": objectID}))
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if res.DeletedCount == 0 {
+		return errorx.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// SaveDeadLetter stores a delivery that was given up.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - deadLetter: The failed delivery
+//
+// Returns:
+//   - error: A wrapped database error
+func (w *WebhookMongoAdapter) SaveDeadLetter(ctx context.Context, deadLetter domain.WebhookDeadLetter) error {
+	_, err := w.deadLetters.InsertOne(ctx, deadLetterDocument(deadLetter))
+	if err != nil {
+		return fmt.Errorf("failed to insert webhook dead letter: %w", err)
+	}
+	return nil
+}
+
+// FindDeadLetters returns the most recent dead letters of the tenant of the request.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - limit: The maximum number of dead letters to return
+//
+// Returns:
+//   - []domain.WebhookDeadLetter: The dead letters, newest first
+//   - error: A wrapped database error
+func (w *WebhookMongoAdapter) FindDeadLetters(ctx context.Context, limit int64) ([]domain.WebhookDeadLetter, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "failedAt", Value: -1}}).SetLimit(limit)
+	if comment, ok := requestComment(ctx); ok {
+		opts.SetComment(comment)
+	}
+
+	cursor, err := w.deadLetters.Find(ctx, inTenant(ctx, bson.M{}), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find webhook dead letters: %w", err)
+	}
+	var docs []deadLetterDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook dead letters: %w", err)
+	}
+
+	deadLetters := make([]domain.WebhookDeadLetter, 0, len(docs))
+	for _, doc := range docs {
+		deadLetters = append(deadLetters, domain.WebhookDeadLetter(doc))
+	}
+	return deadLetters, nil
+}
+
+// inTenant restricts a filter to the documents of the tenant of the request. Documents without a
+// tenant belong to the default tenant; requests without a tenant are not restricted.
+func inTenant(ctx context.Context, filter bson.M) bson.M {
+	switch id := tenant.FromContext(ctx); id {
+	case "":
+	case domain.DefaultTenantID:
+		filter["tenantId"] = bson.M{"$in": bson.A{id, nil}}
+	default:
+		filter["tenantId"] = id
+	}
+	return filter
+}
+
+// requestComment returns the request id of ctx to be attached as comment to MongoDB operations.
+func requestComment(ctx context.Context) (string, bool) {
+	id := requestid.FromContext(ctx)
+	return id, id != ""
+}
@@ -0,0 +1,124 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+	"user-auth-hexagonal-architecture/internal/ports/security"
+)
+
+// lockout is the state of a single lockout key.
+type lockout struct {
+	failures    int
+	windowEnds  time.Time
+	lockedUntil time.Time
+}
+
+// MemoryLockoutStore keeps failed login counters in process memory.
+// It implements the LockoutStorePort interface from the security ports package and is
+// suitable for single-instance deployments.
+type MemoryLockoutStore struct {
+	mu        sync.Mutex
+	lockouts  map[string]*lockout
+	lastSweep time.Time
+}
+
+// NewMemoryLockoutStore creates a new MemoryLockoutStore.
+//
+// Returns:
+//   - *MemoryLockoutStore: A pointer to the newly created MemoryLockoutStore
+func NewMemoryLockoutStore() *MemoryLockoutStore {
+	return &MemoryLockoutStore{lockouts: map[string]*lockout{}, lastSweep: time.Now()}
+}
+
+// LockedFor returns how long the key stays locked out.
+//
+// Parameters:
+//   - ctx: Unused, present to satisfy the port
+//   - key: The lockout key, e.g. "default:alice"
+//
+// Returns:
+//   - time.Duration: The remaining lockout, zero if the key is not locked out
+//   - error: Always nil
+func (m *MemoryLockoutStore) LockedFor(_ context.Context, key string) (time.Duration, error) {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	l, ok := m.lockouts[key]
+	if !ok || !now.Before(l.lockedUntil) {
+		return 0, nil
+	}
+	return l.lockedUntil.Sub(now), nil
+}
+
+// RecordFailure counts a failed login and locks the key out once it failed policy.MaxFailures
+// times within policy.Window.
+//
+// Parameters:
+//   - ctx: Unused, present to satisfy the port
+//   - key: The lockout key
+//   - policy: The lockout policy
+//
+// Returns:
+//   - security.LockoutState: The failures within the window and the lockout, if one applies
+//   - error: Always nil
+func (m *MemoryLockoutStore) RecordFailure(_ context.Context, key string, policy security.LockoutPolicy) (security.LockoutState, error) {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if now.Sub(m.lastSweep) > sweepInterval {
+		m.sweep(now)
+	}
+
+	l, ok := m.lockouts[key]
+	if !ok {
+		l = &lockout{}
+		m.lockouts[key] = l
+	}
+	if now.Before(l.lockedUntil) {
+		return security.LockoutState{LockedFor: l.lockedUntil.Sub(now)}, nil
+	}
+	if !now.Before(l.windowEnds) {
+		l.failures, l.windowEnds = 0, now.Add(policy.Window)
+	}
+
+	l.failures++
+	if l.failures < policy.MaxFailures {
+		return security.LockoutState{Failures: l.failures}, nil
+	}
+	failures := l.failures
+	l.failures, l.windowEnds, l.lockedUntil = 0, time.Time{}, now.Add(policy.Duration)
+	return security.LockoutState{Failures: failures, LockedFor: policy.Duration}, nil
+}
+
+// Reset forgets the failed logins of the key. A running lockout is kept.
+//
+// Parameters:
+//   - ctx: Unused, present to satisfy the port
+//   - key: The lockout key
+//
+// Returns:
+//   - error: Always nil
+func (m *MemoryLockoutStore) Reset(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if l, ok := m.lockouts[key]; ok {
+		l.failures, l.windowEnds = 0, time.Time{}
+	}
+	return nil
+}
+
+// sweep drops keys whose window and lockout have both passed, as they are equivalent to new ones.
+func (m *MemoryLockoutStore) sweep(now time.Time) {
+	for key, l := range m.lockouts {
+		if !now.Before(l.windowEnds) && !now.Before(l.lockedUntil) {
+			delete(m.lockouts, key)
+		}
+	}
+	m.lastSweep = now
+}
@@ -0,0 +1,91 @@
+// Package ratelimit provides token bucket rate limiter and failed login lockout adapters.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+	"user-auth-hexagonal-architecture/internal/ports/security"
+)
+
+// sweepInterval is how often idle buckets are removed from memory.
+const sweepInterval = time.Minute
+
+// bucket is the state of a single token bucket.
+type bucket struct {
+	tokens   float64
+	last     time.Time
+	refilled time.Time
+}
+
+// MemoryRateLimiter keeps token buckets in process memory.
+// It implements the RateLimiterPort interface from the security ports package and is
+// suitable for single-instance deployments.
+type MemoryRateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewMemoryRateLimiter creates a new MemoryRateLimiter.
+//
+// Returns:
+//   - *MemoryRateLimiter: A pointer to the newly created MemoryRateLimiter
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{buckets: map[string]*bucket{}, lastSweep: time.Now()}
+}
+
+// Allow takes a token from the bucket identified by key.
+//
+// Parameters:
+//   - ctx: Unused, present to satisfy the port
+//   - key: The bucket identifier, e.g. "login-ip:203.0.113.7"
+//   - limit: The bucket configuration
+//
+// Returns:
+//   - security.RateLimitDecision: Whether the request is allowed and, if not, when to retry
+//   - error: Always nil
+func (m *MemoryRateLimiter) Allow(_ context.Context, key string, limit security.RateLimit) (security.RateLimitDecision, error) {
+	now := time.Now()
+	capacity := float64(limit.Capacity())
+	rate := limit.RefillPerSecond()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if now.Sub(m.lastSweep) > sweepInterval {
+		m.sweep(now)
+	}
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, last: now}
+		m.buckets[key] = b
+	}
+
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	decision := security.RateLimitDecision{}
+	if b.tokens >= 1 {
+		b.tokens--
+		decision.Allowed = true
+		decision.Remaining = int(b.tokens)
+	} else {
+		decision.RetryAfter = time.Duration((1 - b.tokens) / rate * float64(time.Second))
+	}
+
+	b.refilled = now.Add(time.Duration((capacity - b.tokens) / rate * float64(time.Second)))
+	return decision, nil
+}
+
+// sweep drops buckets that have refilled completely, as they are equivalent to new ones.
+func (m *MemoryRateLimiter) sweep(now time.Time) {
+	for key, b := range m.buckets {
+		if now.After(b.refilled) {
+			delete(m.buckets, key)
+		}
+	}
+	m.lastSweep = now
+}
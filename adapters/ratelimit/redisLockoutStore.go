@@ -0,0 +1,127 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"time"
+	"user-auth-hexagonal-architecture/internal/ports/security"
+)
+
+// recordFailureScript counts a failed login and locks the key out once it failed too often, atomically.
+// Windows and lockouts are key expiries, so they are measured by the Redis clock alone.
+//
+// KEYS[1]: failure counter key
+// KEYS[2]: lockout key
+// ARGV[1]: maximum failures
+// ARGV[2]: window in milliseconds
+// ARGV[3]: lockout duration in milliseconds
+//
+// Returns {failures within the window, lockout in milliseconds}; the failures are 0 if the key was
+// locked out already.
+var recordFailureScript = redis.NewScript(`
+local locked = redis.call('PTTL', KEYS[2])
+if locked > 0 then
+  return {0, locked}
+end
+
+local failures = redis.call('INCR', KEYS[1])
+if failures == 1 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+if failures < tonumber(ARGV[1]) then
+  return {failures, 0}
+end
+
+redis.call('DEL', KEYS[1])
+redis.call('SET', KEYS[2], '1', 'PX', ARGV[3])
+return {failures, tonumber(ARGV[3])}
+`)
+
+// RedisLockoutStore keeps failed login counters in Redis so that all instances of the service share them.
+// It implements the LockoutStorePort interface from the security ports package.
+type RedisLockoutStore struct {
+	client    redis.UniversalClient
+	keyPrefix string
+}
+
+// NewRedisLockoutStore creates a new RedisLockoutStore.
+//
+// Parameters:
+//   - client: A Redis client
+//   - keyPrefix: Prefix for all lockout keys, e.g. "auth:lockout:"
+//
+// Returns:
+//   - *RedisLockoutStore: A pointer to the newly created RedisLockoutStore
+func NewRedisLockoutStore(client redis.UniversalClient, keyPrefix string) *RedisLockoutStore {
+	return &RedisLockoutStore{client, keyPrefix}
+}
+
+// LockedFor returns how long the key stays locked out, read from the expiry of its lockout key.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the Redis call
+//   - key: The lockout key, e.g. "default:alice"
+//
+// Returns:
+//   - time.Duration: The remaining lockout, zero if the key is not locked out
+//   - error: An error if Redis cannot be reached
+func (ls *RedisLockoutStore) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	_, lockKey := ls.keys(key)
+	ttl, err := ls.client.PTTL(ctx, lockKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read lockout: %w", err)
+	}
+	return max(ttl, 0), nil
+}
+
+// RecordFailure counts a failed login using a Lua script, so concurrent instances neither lose
+// failures nor lock a key out twice.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the Redis call
+//   - key: The lockout key
+//   - policy: The lockout policy
+//
+// Returns:
+//   - security.LockoutState: The failures within the window and the lockout, if one applies
+//   - error: An error if Redis cannot be reached or returns an unexpected result
+func (ls *RedisLockoutStore) RecordFailure(ctx context.Context, key string, policy security.LockoutPolicy) (security.LockoutState, error) {
+	failuresKey, lockKey := ls.keys(key)
+	result, err := recordFailureScript.Run(ctx, ls.client, []string{failuresKey, lockKey},
+		policy.MaxFailures, policy.Window.Milliseconds(), policy.Duration.Milliseconds()).Int64Slice()
+	if err != nil {
+		return security.LockoutState{}, fmt.Errorf("failed to record failed login: %w", err)
+	}
+	if len(result) != 2 {
+		return security.LockoutState{}, fmt.Errorf("failed to record failed login: unexpected result %v", result)
+	}
+
+	return security.LockoutState{
+		Failures:  int(result[0]),
+		LockedFor: time.Duration(result[1]) * time.Millisecond,
+	}, nil
+}
+
+// Reset forgets the failed logins of the key. A running lockout is kept.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the Redis call
+//   - key: The lockout key
+//
+// Returns:
+//   - error: An error if Redis cannot be reached
+func (ls *RedisLockoutStore) Reset(ctx context.Context, key string) error {
+	failuresKey, _ := ls.keys(key)
+	if err := ls.client.Del(ctx, failuresKey).Err(); err != nil {
+		return fmt.Errorf("failed to reset failed logins: %w", err)
+	}
+	return nil
+}
+
+// keys returns the failure counter and lockout keys of a key. The hash tag places both in the
+// same Redis Cluster slot, which the script touching both requires.
+func (ls *RedisLockoutStore) keys(key string) (string, string) {
+	tagged := ls.keyPrefix + "{" + key + "}"
+	return tagged + ":failures", tagged + ":locked"
+}
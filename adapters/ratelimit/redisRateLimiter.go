@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"time"
+	"user-auth-hexagonal-architecture/internal/ports/security"
+)
+
+// tokenBucketScript takes a token from a bucket stored as a hash, atomically. The refill is
+// measured by the Redis clock, so instances whose clocks drift apart still share consistent buckets.
+//
+// KEYS[1]: bucket key
+// ARGV[1]: refill rate in tokens per second
+// ARGV[2]: capacity
+//
+// Returns {allowed (0/1), remaining tokens, retry after in milliseconds}.
+var tokenBucketScript = redis.NewScript(`
+if redis.replicate_commands then
+  redis.replicate_commands()
+end
+local rate = tonumber(ARGV[1])
+local capacity = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or capacity
+local ts = tonumber(state[2]) or now
+
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate / 1000)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+else
+  retry = math.ceil((1 - tokens) * 1000 / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(capacity * 1000 / rate) + 1000)
+return {allowed, math.floor(tokens), retry}
+`)
+
+// RedisRateLimiter keeps token buckets in Redis so that all instances of the service share them.
+// It implements the RateLimiterPort interface from the security ports package.
+type RedisRateLimiter struct {
+	client    redis.UniversalClient
+	keyPrefix string
+}
+
+// NewRedisRateLimiter creates a new RedisRateLimiter.
+//
+// Parameters:
+//   - client: A Redis client
+//   - keyPrefix: Prefix for all bucket keys, e.g. "auth:ratelimit:"
+//
+// Returns:
+//   - *RedisRateLimiter: A pointer to the newly created RedisRateLimiter
+func NewRedisRateLimiter(client redis.UniversalClient, keyPrefix string) *RedisRateLimiter {
+	return &RedisRateLimiter{client, keyPrefix}
+}
+
+// Allow takes a token from the bucket identified by key using a Lua script,
+// so concurrent instances cannot overdraw a bucket.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the Redis call
+//   - key: The bucket identifier
+//   - limit: The bucket configuration
+//
+// Returns:
+//   - security.RateLimitDecision: Whether the request is allowed and, if not, when to retry
+//   - error: An error if Redis cannot be reached or returns an unexpected result
+func (rl *RedisRateLimiter) Allow(ctx context.Context, key string, limit security.RateLimit) (security.RateLimitDecision, error) {
+	result, err := tokenBucketScript.Run(ctx, rl.client, []string{rl.keyPrefix + key},
+		limit.RefillPerSecond(), limit.Capacity()).Int64Slice()
+	if err != nil {
+		return security.RateLimitDecision{}, fmt.Errorf("failed to evaluate rate limit: %w", err)
+	}
+	if len(result) != 3 {
+		return security.RateLimitDecision{}, fmt.Errorf("failed to evaluate rate limit: unexpected result %v", result)
+	}
+
+	return security.RateLimitDecision{
+		Allowed:    result[0] == 1,
+		Remaining:  int(result[1]),
+		RetryAfter: time.Duration(result[2]) * time.Millisecond,
+	}, nil
+}
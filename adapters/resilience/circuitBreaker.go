@@ -0,0 +1,199 @@
+// Package resilience protects the service from struggling dependencies with circuit breakers
+// wrapped around the adapters of the database, notification providers and webhook endpoints.
+package resilience
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"sync"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/logging"
+)
+
+// logger writes the log records of this package.
+var logger = logging.Component("resilience")
+
+// breakerStates publishes the state of every circuit breaker by name via expvar.
+var breakerStates = expvar.NewMap("circuit_breakers")
+
+// State is the state of a circuit breaker.
+type State string
+
+// Circuit breaker states.
+const (
+	// StateClosed lets every call through.
+	StateClosed State = "closed"
+	// StateOpen rejects every call with errorx.ErrDependencyUnavailable.
+	StateOpen State = "open"
+	// StateHalfOpen lets a limited number of probe calls through to find out whether the dependency recovered.
+	StateHalfOpen State = "half_open"
+)
+
+// BreakerConfig configures circuit breakers.
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the circuit. Zero disables the breaker.
+	FailureThreshold int
+	// OpenDuration is how long the circuit stays open before probe calls are let through.
+	OpenDuration time.Duration
+	// HalfOpenProbes is the number of probe calls let through at a time, all of which have to
+	// succeed to close the circuit again.
+	HalfOpenProbes int
+}
+
+// DefaultBreakerConfig returns a configuration opening the circuit after 5 consecutive failures for 30 seconds.
+func DefaultBreakerConfig() BreakerConfig {
+	return BreakerConfig{FailureThreshold: 5, OpenDuration: 30 * time.Second, HalfOpenProbes: 1}
+}
+
+// CircuitBreaker stops calling a dependency after repeated failures, so requests fail fast
+// instead of waiting for timeouts, and probes it again after a while.
+//
+// Only unexpected errors count as failures: typed domain errors such as errorx.ErrUserNotFound
+// are answers of a healthy dependency, and cancelled calls say nothing about it.
+type CircuitBreaker struct {
+	name   string
+	config BreakerConfig
+
+	mu        sync.Mutex
+	state     State
+	failures  int
+	openedAt  time.Time
+	probes    int
+	successes int
+}
+
+// NewCircuitBreaker creates a new closed CircuitBreaker.
+//
+// Parameters:
+//   - name: The name of the protected dependency, e.g. "mongodb", used in errors, logs and expvar
+//   - config: The failure threshold and the open and half-open behaviour
+//
+// Returns:
+//   - *CircuitBreaker: A pointer to the newly created CircuitBreaker
+func NewCircuitBreaker(name string, config BreakerConfig) *CircuitBreaker {
+	cb := &CircuitBreaker{name: name, config: config, state: StateClosed}
+	breakerStates.Set(name, cb)
+	return cb
+}
+
+// Name returns the name of the protected dependency.
+func (cb *CircuitBreaker) Name() string {
+	return cb.name
+}
+
+// String returns the current state, which makes the breaker an expvar.Var.
+func (cb *CircuitBreaker) String() string {
+	return `"` + string(cb.State()) + `"`
+}
+
+// State returns the current state of the breaker.
+func (cb *CircuitBreaker) State() State {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if cb.state == StateOpen && time.Since(cb.openedAt) >= cb.config.OpenDuration {
+		return StateHalfOpen
+	}
+	return cb.state
+}
+
+// Execute calls fn unless the circuit is open and records its outcome.
+//
+// Parameters:
+//   - ctx: A context.Context passed to fn
+//   - fn: The call to the dependency
+//
+// Returns:
+//   - error: errorx.ErrDependencyUnavailable if the circuit is open, else the error of fn
+func (cb *CircuitBreaker) Execute(ctx context.Context, fn func(ctx context.Context) error) error {
+	if cb.config.FailureThreshold <= 0 {
+		return fn(ctx)
+	}
+	probe, err := cb.allow()
+	if err != nil {
+		return err
+	}
+	err = fn(ctx)
+	cb.record(probe, failure(err))
+	return err
+}
+
+// Call is Execute for calls returning a value.
+func Call[T any](ctx context.Context, cb *CircuitBreaker, fn func(ctx context.Context) (T, error)) (T, error) {
+	var result T
+	err := cb.Execute(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = fn(ctx)
+		return err
+	})
+	return result, err
+}
+
+// allow decides whether a call may proceed and whether it is a probe of a half-open circuit.
+func (cb *CircuitBreaker) allow() (bool, error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == StateOpen && time.Since(cb.openedAt) >= cb.config.OpenDuration {
+		cb.state, cb.probes, cb.successes = StateHalfOpen, 0, 0
+	}
+	switch cb.state {
+	case StateOpen:
+		return false, errorx.ErrDependencyUnavailable.Detailf("%s is unavailable", cb.name)
+	case StateHalfOpen:
+		if cb.probes >= max(cb.config.HalfOpenProbes, 1) {
+			return false, errorx.ErrDependencyUnavailable.Detailf("%s is recovering", cb.name)
+		}
+		cb.probes++
+		return true, nil
+	}
+	return false, nil
+}
+
+// record counts the outcome of a call and moves the breaker to its next state.
+func (cb *CircuitBreaker) record(probe bool, failed bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if probe {
+		cb.probes--
+		if cb.state != StateHalfOpen {
+			return
+		}
+		if failed {
+			cb.open()
+			return
+		}
+		cb.successes++
+		if cb.successes >= max(cb.config.HalfOpenProbes, 1) {
+			cb.state, cb.failures = StateClosed, 0
+			logger.Info("Circuit closed, dependency recovered", "dependency", cb.name)
+		}
+		return
+	}
+
+	if !failed {
+		cb.failures = 0
+		return
+	}
+	cb.failures++
+	if cb.state == StateClosed && cb.failures >= cb.config.FailureThreshold {
+		cb.open()
+	}
+}
+
+// open rejects calls for the open duration.
+func (cb *CircuitBreaker) open() {
+	cb.state, cb.openedAt, cb.failures = StateOpen, time.Now(), 0
+	logger.Warn("Circuit opened, failing calls fast", "dependency", cb.name, "open_for", cb.config.OpenDuration)
+}
+
+// failure reports whether an error points to a struggling dependency.
+func failure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	_, typed := errorx.As(err)
+	return !typed
+}
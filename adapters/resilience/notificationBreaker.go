@@ -0,0 +1,67 @@
+package resilience
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/messaging"
+)
+
+// EmailSenderBreaker guards an email provider with a circuit breaker, so a provider that is down is
+// skipped right away and the next provider takes over.
+// It implements the EmailSenderPort interface from the messaging ports package.
+type EmailSenderBreaker struct {
+	next    messaging.EmailSenderPort
+	breaker *CircuitBreaker
+}
+
+// NewEmailSenderBreaker creates a new EmailSenderBreaker.
+//
+// Parameters:
+//   - next: The guarded implementation of EmailSenderPort
+//   - breaker: The circuit breaker of the provider
+//
+// Returns:
+//   - *EmailSenderBreaker: A pointer to the newly created EmailSenderBreaker
+func NewEmailSenderBreaker(next messaging.EmailSenderPort, breaker *CircuitBreaker) *EmailSenderBreaker {
+	return &EmailSenderBreaker{next, breaker}
+}
+
+// Provider returns the name of the guarded provider.
+func (eb *EmailSenderBreaker) Provider() string {
+	return eb.next.Provider()
+}
+
+// SendEmail hands an email to the provider unless the circuit is open.
+func (eb *EmailSenderBreaker) SendEmail(ctx context.Context, message domain.EmailMessage) error {
+	return eb.breaker.Execute(ctx, func(ctx context.Context) error { return eb.next.SendEmail(ctx, message) })
+}
+
+// SMSSenderBreaker guards an SMS provider with a circuit breaker, so a provider that is down is
+// skipped right away and the next provider takes over.
+// It implements the SMSSenderPort interface from the messaging ports package.
+type SMSSenderBreaker struct {
+	next    messaging.SMSSenderPort
+	breaker *CircuitBreaker
+}
+
+// NewSMSSenderBreaker creates a new SMSSenderBreaker.
+//
+// Parameters:
+//   - next: The guarded implementation of SMSSenderPort
+//   - breaker: The circuit breaker of the provider
+//
+// Returns:
+//   - *SMSSenderBreaker: A pointer to the newly created SMSSenderBreaker
+func NewSMSSenderBreaker(next messaging.SMSSenderPort, breaker *CircuitBreaker) *SMSSenderBreaker {
+	return &SMSSenderBreaker{next, breaker}
+}
+
+// Provider returns the name of the guarded provider.
+func (sb *SMSSenderBreaker) Provider() string {
+	return sb.next.Provider()
+}
+
+// SendSMS hands an SMS to the provider unless the circuit is open.
+func (sb *SMSSenderBreaker) SendSMS(ctx context.Context, message domain.SMSMessage) error {
+	return sb.breaker.Execute(ctx, func(ctx context.Context) error { return sb.next.SendSMS(ctx, message) })
+}
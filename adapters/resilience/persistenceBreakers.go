@@ -0,0 +1,137 @@
+package resilience
+
+import (
+	"context"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+)
+
+// UserPersistenceBreaker guards the user store of logins and registrations with a circuit breaker.
+// It implements the UserPersistencePort interface from the persistence ports package.
+type UserPersistenceBreaker struct {
+	next    persistence.UserPersistencePort
+	breaker *CircuitBreaker
+}
+
+// NewUserPersistenceBreaker creates a new UserPersistenceBreaker.
+//
+// Parameters:
+//   - next: The guarded implementation of UserPersistencePort
+//   - breaker: The circuit breaker of the database
+//
+// Returns:
+//   - *UserPersistenceBreaker: A pointer to the newly created UserPersistenceBreaker
+func NewUserPersistenceBreaker(next persistence.UserPersistencePort, breaker *CircuitBreaker) *UserPersistenceBreaker {
+	return &UserPersistenceBreaker{next, breaker}
+}
+
+// SaveUser stores a new user unless the circuit is open.
+func (ub *UserPersistenceBreaker) SaveUser(ctx context.Context, user domain.User) (string, error) {
+	return Call(ctx, ub.breaker, func(ctx context.Context) (string, error) { return ub.next.SaveUser(ctx, user) })
+}
+
+// FindUser loads a user unless the circuit is open.
+func (ub *UserPersistenceBreaker) FindUser(ctx context.Context, username domain.Username) (domain.User, error) {
+	return Call(ctx, ub.breaker, func(ctx context.Context) (domain.User, error) { return ub.next.FindUser(ctx, username) })
+}
+
+// IsUsernameAvailable checks a username unless the circuit is open.
+func (ub *UserPersistenceBreaker) IsUsernameAvailable(ctx context.Context, username domain.Username, canonicalUsername string) (bool, error) {
+	return Call(ctx, ub.breaker, func(ctx context.Context) (bool, error) {
+		return ub.next.IsUsernameAvailable(ctx, username, canonicalUsername)
+	})
+}
+
+// UpdateLastLogin records a login unless the circuit is open.
+func (ub *UserPersistenceBreaker) UpdateLastLogin(ctx context.Context, username domain.Username, loginAt time.Time) error {
+	return ub.breaker.Execute(ctx, func(ctx context.Context) error { return ub.next.UpdateLastLogin(ctx, username, loginAt) })
+}
+
+// UpdatePasswordHash replaces a password hash unless the circuit is open.
+func (ub *UserPersistenceBreaker) UpdatePasswordHash(ctx context.Context, username domain.Username, previous domain.HashedPassword, password domain.HashedPassword) error {
+	return ub.breaker.Execute(ctx, func(ctx context.Context) error {
+		return ub.next.UpdatePasswordHash(ctx, username, previous, password)
+	})
+}
+
+// SessionPersistenceBreaker guards the session store with a circuit breaker.
+// It implements the SessionPersistencePort interface from the persistence ports package.
+type SessionPersistenceBreaker struct {
+	next    persistence.SessionPersistencePort
+	breaker *CircuitBreaker
+}
+
+// NewSessionPersistenceBreaker creates a new SessionPersistenceBreaker.
+//
+// Parameters:
+//   - next: The guarded implementation of SessionPersistencePort
+//   - breaker: The circuit breaker of the database
+//
+// Returns:
+//   - *SessionPersistenceBreaker: A pointer to the newly created SessionPersistenceBreaker
+func NewSessionPersistenceBreaker(next persistence.SessionPersistencePort, breaker *CircuitBreaker) *SessionPersistenceBreaker {
+	return &SessionPersistenceBreaker{next, breaker}
+}
+
+// SaveSession stores a session unless the circuit is open.
+func (sb *SessionPersistenceBreaker) SaveSession(ctx context.Context, session domain.Session) error {
+	return sb.breaker.Execute(ctx, func(ctx context.Context) error { return sb.next.SaveSession(ctx, session) })
+}
+
+// SaveRefreshedSession stores a refreshed session unless the circuit is open.
+func (sb *SessionPersistenceBreaker) SaveRefreshedSession(ctx context.Context, session domain.Session, previousRefreshTokenHash string) error {
+	return sb.breaker.Execute(ctx, func(ctx context.Context) error {
+		return sb.next.SaveRefreshedSession(ctx, session, previousRefreshTokenHash)
+	})
+}
+
+// FindSession loads a session unless the circuit is open.
+func (sb *SessionPersistenceBreaker) FindSession(ctx context.Context, id string) (domain.Session, error) {
+	return Call(ctx, sb.breaker, func(ctx context.Context) (domain.Session, error) { return sb.next.FindSession(ctx, id) })
+}
+
+// FindSessionsByUser loads the sessions of a user unless the circuit is open.
+func (sb *SessionPersistenceBreaker) FindSessionsByUser(ctx context.Context, userID string) ([]domain.Session, error) {
+	return Call(ctx, sb.breaker, func(ctx context.Context) ([]domain.Session, error) { return sb.next.FindSessionsByUser(ctx, userID) })
+}
+
+// DeleteSessionsByUser deletes the sessions of a user unless the circuit is open.
+func (sb *SessionPersistenceBreaker) DeleteSessionsByUser(ctx context.Context, userID string) (int64, error) {
+	return Call(ctx, sb.breaker, func(ctx context.Context) (int64, error) { return sb.next.DeleteSessionsByUser(ctx, userID) })
+}
+
+// LoginAuditStoreBreaker guards the login history with a circuit breaker, so logins do not wait
+// for a struggling audit store.
+// It implements the LoginAuditStorePort interface from the persistence ports package.
+type LoginAuditStoreBreaker struct {
+	next    persistence.LoginAuditStorePort
+	breaker *CircuitBreaker
+}
+
+// NewLoginAuditStoreBreaker creates a new LoginAuditStoreBreaker.
+//
+// Parameters:
+//   - next: The guarded implementation of LoginAuditStorePort
+//   - breaker: The circuit breaker of the database
+//
+// Returns:
+//   - *LoginAuditStoreBreaker: A pointer to the newly created LoginAuditStoreBreaker
+func NewLoginAuditStoreBreaker(next persistence.LoginAuditStorePort, breaker *CircuitBreaker) *LoginAuditStoreBreaker {
+	return &LoginAuditStoreBreaker{next, breaker}
+}
+
+// AppendLoginRecord stores a login record unless the circuit is open.
+func (lb *LoginAuditStoreBreaker) AppendLoginRecord(ctx context.Context, record domain.LoginRecord) error {
+	return lb.breaker.Execute(ctx, func(ctx context.Context) error { return lb.next.AppendLoginRecord(ctx, record) })
+}
+
+// FindLoginRecords loads login records unless the circuit is open.
+func (lb *LoginAuditStoreBreaker) FindLoginRecords(ctx context.Context, filter domain.LoginRecordFilter) ([]domain.LoginRecord, error) {
+	return Call(ctx, lb.breaker, func(ctx context.Context) ([]domain.LoginRecord, error) { return lb.next.FindLoginRecords(ctx, filter) })
+}
+
+// DeleteLoginRecords deletes the login records of a user unless the circuit is open.
+func (lb *LoginAuditStoreBreaker) DeleteLoginRecords(ctx context.Context, username string) (int64, error) {
+	return Call(ctx, lb.breaker, func(ctx context.Context) (int64, error) { return lb.next.DeleteLoginRecords(ctx, username) })
+}
@@ -0,0 +1,37 @@
+package scheduler
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// DataExportJob generates the archives of requested data exports and removes expired archives.
+// It drives the GenerateDataExportsPort use case.
+type DataExportJob struct {
+	generateDataExportsPort usecases.GenerateDataExportsPort
+}
+
+// NewDataExportJob creates a new DataExportJob.
+//
+// Parameters:
+//   - generateDataExportsPort: Port for the data export generation use case
+//
+// Returns:
+//   - *DataExportJob: A pointer to the newly created DataExportJob
+func NewDataExportJob(generateDataExportsPort usecases.GenerateDataExportsPort) *DataExportJob {
+	return &DataExportJob{generateDataExportsPort}
+}
+
+// Name returns the job identifier.
+func (dj *DataExportJob) Name() string {
+	return "data-export"
+}
+
+// Run generates the pending data exports.
+func (dj *DataExportJob) Run(ctx context.Context) error {
+	generated, err := dj.generateDataExportsPort.GenerateDataExports(ctx)
+	if generated > 0 {
+		logger.InfoContext(ctx, "Generated data exports", "count", generated)
+	}
+	return err
+}
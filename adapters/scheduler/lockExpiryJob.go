@@ -0,0 +1,37 @@
+package scheduler
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// LockExpiryJob unlocks accounts whose lock has expired.
+// It drives the LockExpiryPort use case.
+type LockExpiryJob struct {
+	lockExpiryPort usecases.LockExpiryPort
+}
+
+// NewLockExpiryJob creates a new LockExpiryJob.
+//
+// Parameters:
+//   - lockExpiryPort: Port for the lock expiry use case
+//
+// Returns:
+//   - *LockExpiryJob: A pointer to the newly created LockExpiryJob
+func NewLockExpiryJob(lockExpiryPort usecases.LockExpiryPort) *LockExpiryJob {
+	return &LockExpiryJob{lockExpiryPort}
+}
+
+// Name returns the job identifier.
+func (lj *LockExpiryJob) Name() string {
+	return "lock-expiry"
+}
+
+// Run lifts the expired locks.
+func (lj *LockExpiryJob) Run(ctx context.Context) error {
+	unlocked, err := lj.lockExpiryPort.LiftExpiredLocks(ctx)
+	if unlocked > 0 {
+		logger.InfoContext(ctx, "Unlocked users with expired locks", "count", unlocked)
+	}
+	return err
+}
@@ -0,0 +1,45 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// RetentionJob archives inactive accounts and purges expired soft-deleted accounts.
+// It drives the AccountRetentionPort use case.
+type RetentionJob struct {
+	accountRetentionPort usecases.AccountRetentionPort
+}
+
+// NewRetentionJob creates a new RetentionJob.
+//
+// Parameters:
+//   - accountRetentionPort: Port for the account retention use case
+//
+// Returns:
+//   - *RetentionJob: A pointer to the newly created RetentionJob
+func NewRetentionJob(accountRetentionPort usecases.AccountRetentionPort) *RetentionJob {
+	return &RetentionJob{accountRetentionPort}
+}
+
+// Name returns the job identifier.
+func (rj *RetentionJob) Name() string {
+	return "account-retention"
+}
+
+// Run archives inactive users first and then purges soft-deleted users.
+// A failure of one step does not prevent the other from running.
+func (rj *RetentionJob) Run(ctx context.Context) error {
+	archived, archiveErr := rj.accountRetentionPort.ArchiveInactiveUsers(ctx)
+	if archived > 0 {
+		logger.InfoContext(ctx, "Archived inactive users", "count", archived)
+	}
+
+	purged, purgeErr := rj.accountRetentionPort.PurgeDeletedUsers(ctx)
+	if purged > 0 {
+		logger.InfoContext(ctx, "Purged deleted users", "count", purged)
+	}
+
+	return errors.Join(archiveErr, purgeErr)
+}
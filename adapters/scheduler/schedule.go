@@ -0,0 +1,188 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule describes when a job should run.
+type Schedule interface {
+	// Next returns the next activation time strictly after the given time.
+	Next(after time.Time) time.Time
+}
+
+// intervalSchedule runs a job at a fixed interval, e.g. "@every 1h".
+type intervalSchedule struct {
+	interval time.Duration
+}
+
+// Next returns the given time advanced by the configured interval.
+func (s intervalSchedule) Next(after time.Time) time.Time {
+	return after.Add(s.interval)
+}
+
+// cronSchedule is a classic five-field cron expression (minute hour day-of-month month day-of-week).
+// Every field is stored as a bit set of the allowed values.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+}
+
+// field bounds in cron expression order
+var cronBounds = []struct{ min, max uint }{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of month
+	{1, 12}, // month
+	{0, 6},  // day of week
+}
+
+// cronAliases maps the common shorthand descriptors onto their five-field equivalents.
+var cronAliases = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseSchedule parses a cron-style schedule specification.
+//
+// Supported formats:
+//   - "@every <duration>": fixed interval, e.g. "@every 30m"
+//   - "@yearly", "@monthly", "@weekly", "@daily", "@midnight", "@hourly"
+//   - five-field cron expressions with "*", ranges ("1-5"), lists ("1,15") and steps ("*/10")
+//
+// Parameters:
+//   - spec: The schedule specification
+//
+// Returns:
+//   - Schedule: The parsed schedule
+//   - error: An error if the specification is malformed
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if rest, ok := strings.CutPrefix(spec, "@every "); ok {
+		interval, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval in schedule %q: %w", spec, err)
+		}
+		if interval <= 0 {
+			return nil, fmt.Errorf("interval in schedule %q must be positive", spec)
+		}
+		return intervalSchedule{interval}, nil
+	}
+
+	if alias, ok := cronAliases[spec]; ok {
+		spec = alias
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != len(cronBounds) {
+		return nil, fmt.Errorf("schedule %q must have %d fields, got %d", spec, len(cronBounds), len(fields))
+	}
+
+	bits := make([]uint64, len(fields))
+	for i, field := range fields {
+		b, err := parseCronField(field, cronBounds[i].min, cronBounds[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+		}
+		bits[i] = b
+	}
+
+	return &cronSchedule{
+		minute:  bits[0],
+		hour:    bits[1],
+		dom:     bits[2],
+		month:   bits[3],
+		dow:     bits[4],
+		domStar: fields[2] == "*",
+		dowStar: fields[4] == "*",
+	}, nil
+}
+
+// parseCronField converts a single comma separated cron field into a bit set.
+func parseCronField(field string, min, max uint) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := uint(1)
+		if hasStep {
+			s, err := strconv.ParseUint(stepPart, 10, 8)
+			if err != nil || s == 0 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+			step = uint(s)
+		}
+
+		start, end := min, max
+		if rangePart != "*" {
+			lo, hi, isRange := strings.Cut(rangePart, "-")
+			l, err := strconv.ParseUint(lo, 10, 8)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", lo)
+			}
+			start, end = uint(l), uint(l)
+			if isRange {
+				h, err := strconv.ParseUint(hi, 10, 8)
+				if err != nil {
+					return 0, fmt.Errorf("invalid value %q", hi)
+				}
+				end = uint(h)
+			} else if hasStep {
+				end = max
+			}
+		}
+
+		if start < min || end > max || start > end {
+			return 0, fmt.Errorf("value %q out of range %d-%d", part, min, max)
+		}
+		for v := start; v <= end; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// Next returns the next minute after the given time matching the cron expression.
+// A zero time is returned if no matching time is found within five years.
+func (s *cronSchedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches applies the cron rule that a restricted day-of-month and day-of-week
+// are combined with OR, while a wildcard in either field defers to the other.
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
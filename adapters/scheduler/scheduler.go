@@ -0,0 +1,136 @@
+// Package scheduler provides a cron-style background job runner that drives use cases periodically.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+	"user-auth-hexagonal-architecture/internal/logging"
+)
+
+// logger writes the log records of this package.
+var logger = logging.Component("scheduler")
+
+// Job is a unit of background work executed by the Scheduler.
+type Job interface {
+	// Name returns a human-readable identifier used in logs.
+	Name() string
+	// Run executes the job. The context is cancelled when the scheduler stops.
+	Run(ctx context.Context) error
+}
+
+// entry binds a job to its schedule.
+type entry struct {
+	schedule Schedule
+	job      Job
+}
+
+// Scheduler runs registered jobs according to their schedules.
+//
+// Each job runs in its own goroutine. A job is never executed concurrently with itself:
+// if a run takes longer than the interval to the next activation, the missed activations are skipped.
+type Scheduler struct {
+	mu      sync.Mutex
+	entries []entry
+	wg      sync.WaitGroup
+	started bool
+}
+
+// NewScheduler creates a new, empty Scheduler.
+//
+// Returns:
+//   - *Scheduler: A pointer to the newly created Scheduler
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Register adds a job to the scheduler.
+//
+// Parameters:
+//   - spec: A schedule specification understood by ParseSchedule, e.g. "@every 1h" or "0 3 * * *"
+//   - job: The job to run
+//
+// Returns:
+//   - error: An error if the specification is invalid or the scheduler is already running
+func (s *Scheduler) Register(spec string, job Job) error {
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return fmt.Errorf("failed to register job %s: %w", job.Name(), err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return fmt.Errorf("failed to register job %s: scheduler already started", job.Name())
+	}
+	s.entries = append(s.entries, entry{schedule, job})
+	return nil
+}
+
+// Start launches all registered jobs in the background.
+//
+// The jobs keep running until the given context is cancelled. Use Wait to block
+// until all in-flight runs have returned after cancellation.
+//
+// Parameters:
+//   - ctx: A context.Context controlling the lifetime of the scheduler
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return
+	}
+	s.started = true
+
+	for _, e := range s.entries {
+		s.wg.Add(1)
+		go s.loop(ctx, e)
+	}
+}
+
+// Wait blocks until every job loop has stopped.
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+// loop waits for the next activation of a single entry and runs its job until ctx is done.
+func (s *Scheduler) loop(ctx context.Context, e entry) {
+	defer s.wg.Done()
+
+	for {
+		now := time.Now()
+		next := e.schedule.Next(now)
+		if next.IsZero() {
+			logger.WarnContext(ctx, "Job has no future activation, stopping", "job", e.job.Name())
+			return
+		}
+
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.run(ctx, e.job)
+	}
+}
+
+// run executes a job once, logging its outcome and recovering from panics
+// so that a faulty job cannot take down the service.
+func (s *Scheduler) run(ctx context.Context, job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.ErrorContext(ctx, "Job panicked", "job", job.Name(), "panic", r)
+		}
+	}()
+
+	start := time.Now()
+	if err := job.Run(ctx); err != nil {
+		logger.ErrorContext(ctx, "Job failed", "job", job.Name(), "duration", time.Since(start), "error", err)
+		return
+	}
+	logger.InfoContext(ctx, "Job finished", "job", job.Name(), "duration", time.Since(start))
+}
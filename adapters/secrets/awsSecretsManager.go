@@ -0,0 +1,89 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"user-auth-hexagonal-architecture/adapters/aws"
+)
+
+// AwsSecretsManager reads secrets from AWS Secrets Manager, signing its requests with Signature
+// Version 4. A reference names the secret and optionally a field of a secret stored as JSON object,
+// e.g. "prod/auth#jwt-key"; without a field the whole secret string is used.
+type AwsSecretsManager struct {
+	client   *http.Client
+	endpoint string
+	signer   *aws.Signer
+}
+
+// NewAwsSecretsManager creates a new AwsSecretsManager store.
+//
+// Parameters:
+//   - client: The HTTP client for the requests to AWS
+//   - region: The AWS region of the secrets, e.g. "eu-central-1"
+//   - credentials: The provider of the credentials the requests are signed with
+//
+// Returns:
+//   - *AwsSecretsManager: A pointer to the newly created AwsSecretsManager store
+func NewAwsSecretsManager(client *http.Client, region string, credentials aws.CredentialsProvider) *AwsSecretsManager {
+	endpoint := "https://secretsmanager." + region + ".amazonaws.com/"
+	return &AwsSecretsManager{client, endpoint, aws.NewSigner("secretsmanager", region, credentials)}
+}
+
+// Name returns "aws-secrets-manager".
+func (a *AwsSecretsManager) Name() string {
+	return "aws-secrets-manager"
+}
+
+// Fetch reads the current version of a secret. Secrets Manager does not lease secrets, so the
+// value is refreshed by the cache after its refresh interval.
+func (a *AwsSecretsManager) Fetch(ctx context.Context, ref string) (Lease, error) {
+	secretID, field, _ := strings.Cut(ref, "#")
+	body, err := json.Marshal(map[string]string{"SecretId": secretID})
+	if err != nil {
+		return Lease{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return Lease{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	if err := a.signer.Sign(ctx, req, body); err != nil {
+		return Lease{}, err
+	}
+
+	res, err := a.client.Do(req)
+	if err != nil {
+		return Lease{}, fmt.Errorf("failed to read %s from secrets manager: %w", secretID, err)
+	}
+	defer res.Body.Close()
+	var response struct {
+		SecretString string `json:"SecretString"`
+		Message      string `json:"message"`
+		Type         string `json:"__type"`
+	}
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&response); err != nil {
+		return Lease{}, fmt.Errorf("failed to read %s from secrets manager: unexpected response with status %d", secretID, res.StatusCode)
+	}
+	if res.StatusCode >= 300 {
+		return Lease{}, fmt.Errorf("failed to read %s from secrets manager: %s %s", secretID, response.Type, response.Message)
+	}
+
+	if field == "" {
+		return Lease{Value: response.SecretString}, nil
+	}
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(response.SecretString), &fields); err != nil {
+		return Lease{}, fmt.Errorf("secret %s is not a JSON object", secretID)
+	}
+	value, ok := fields[field].(string)
+	if !ok {
+		return Lease{}, fmt.Errorf("secret %s has no string field %s", secretID, field)
+	}
+	return Lease{Value: value}, nil
+}
@@ -0,0 +1,153 @@
+// Package secrets reads credentials from HashiCorp Vault, AWS Secrets Manager and GCP Secret Manager
+// behind the SecretsProviderPort.
+package secrets
+
+import (
+	"context"
+	"sync"
+	"time"
+	"user-auth-hexagonal-architecture/internal/logging"
+)
+
+// logger writes the log records of this package.
+var logger = logging.Component("secrets")
+
+// Lease is the value of a secret together with how long it may be used.
+type Lease struct {
+	Value string
+	// TTL is how long the value is valid, 0 if the store does not limit it.
+	TTL time.Duration
+	// ID identifies a renewable lease in the store, empty if the lease cannot be renewed.
+	ID string
+}
+
+// Store fetches secrets from a secret store.
+type Store interface {
+	// Name identifies the store in logs, e.g. "vault".
+	Name() string
+	// Fetch reads the current value of a secret.
+	Fetch(ctx context.Context, ref string) (Lease, error)
+}
+
+// Renewer is implemented by stores whose leases can be extended without reading the secret again.
+type Renewer interface {
+	// Renew extends a lease and returns it with its new TTL.
+	Renew(ctx context.Context, lease Lease) (Lease, error)
+}
+
+// cachedLease is a lease held by the cache with the time it has to be refreshed.
+type cachedLease struct {
+	lease     Lease
+	refreshAt time.Time
+}
+
+// Cache keeps the secrets of a store in memory, so every secret is fetched once per lease rather
+// than on every use, and renews leases before they expire. It implements the SecretsProviderPort
+// interface from the security ports package.
+//
+// Leases are refreshed after two thirds of their TTL; secrets without a TTL are fetched again
+// after the refresh interval, so rotated values are picked up.
+type Cache struct {
+	store           Store
+	refreshInterval time.Duration
+	now             func() time.Time
+
+	mu     sync.Mutex
+	leases map[string]cachedLease
+}
+
+// NewCache creates a new Cache.
+//
+// Parameters:
+//   - store: The store the secrets are fetched from
+//   - refreshInterval: How long secrets without a TTL are served from memory
+//
+// Returns:
+//   - *Cache: A pointer to the newly created Cache
+func NewCache(store Store, refreshInterval time.Duration) *Cache {
+	return &Cache{store: store, refreshInterval: refreshInterval, now: time.Now, leases: make(map[string]cachedLease)}
+}
+
+// Secret returns the cached value of a secret, fetching or renewing it if its lease is due.
+// If the store fails while a previously fetched value is still valid, that value is returned.
+func (c *Cache) Secret(ctx context.Context, ref string) (string, error) {
+	c.mu.Lock()
+	cached, ok := c.leases[ref]
+	c.mu.Unlock()
+	if ok && c.now().Before(cached.refreshAt) {
+		return cached.lease.Value, nil
+	}
+
+	lease, err := c.refresh(ctx, ref, cached.lease, ok)
+	if err != nil {
+		if ok && (cached.lease.TTL == 0 || c.now().Before(cached.refreshAt.Add(cached.lease.TTL/3))) {
+			logger.WarnContext(ctx, "Error refreshing secret, using the cached value", "ref", ref, "store", c.store.Name(), "error", err)
+			return cached.lease.Value, nil
+		}
+		return "", err
+	}
+	return lease.Value, nil
+}
+
+// Refresh fetches a secret from the store regardless of its lease and caches the new value, e.g. to
+// pick up a secret rotated in the store when the configuration is reloaded.
+func (c *Cache) Refresh(ctx context.Context, ref string) (string, error) {
+	lease, err := c.refresh(ctx, ref, Lease{}, false)
+	if err != nil {
+		return "", err
+	}
+	return lease.Value, nil
+}
+
+// RenewEvery refreshes the leases that are due at the given interval until the context is cancelled,
+// so leases do not lapse between two uses of a secret.
+func (c *Cache) RenewEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.mu.Lock()
+			due := make(map[string]Lease)
+			for ref, cached := range c.leases {
+				if !c.now().Before(cached.refreshAt) {
+					due[ref] = cached.lease
+				}
+			}
+			c.mu.Unlock()
+			for ref, lease := range due {
+				if _, err := c.refresh(ctx, ref, lease, true); err != nil {
+					logger.ErrorContext(ctx, "Error renewing secret", "ref", ref, "store", c.store.Name(), "error", err)
+				}
+			}
+		}
+	}
+}
+
+// refresh renews a renewable lease or fetches the secret again, and caches the result.
+func (c *Cache) refresh(ctx context.Context, ref string, previous Lease, cached bool) (Lease, error) {
+	var lease Lease
+	var err error
+	renewer, renewable := c.store.(Renewer)
+	if cached && renewable && previous.ID != "" {
+		if lease, err = renewer.Renew(ctx, previous); err != nil {
+			logger.WarnContext(ctx, "Error renewing lease of secret, fetching it again", "ref", ref, "store", c.store.Name(), "error", err)
+		}
+	}
+	if lease.Value == "" {
+		if lease, err = c.store.Fetch(ctx, ref); err != nil {
+			return Lease{}, err
+		}
+	}
+
+	validFor := c.refreshInterval
+	if lease.TTL > 0 {
+		validFor = lease.TTL * 2 / 3
+	}
+	c.mu.Lock()
+	c.leases[ref] = cachedLease{lease: lease, refreshAt: c.now().Add(validFor)}
+	c.mu.Unlock()
+	return lease, nil
+}
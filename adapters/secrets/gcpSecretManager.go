@@ -0,0 +1,135 @@
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// gcpMetadataTokenURL is the endpoint of the metadata server issuing access tokens for the
+// service account of the instance, available on GCE, GKE and Cloud Run.
+const gcpMetadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+// GcpSecretManager reads secrets from Google Cloud Secret Manager. A reference names a secret of the
+// project, e.g. "jwt-key" for its latest version or "jwt-key/versions/3", or a full resource name
+// such as "projects/other/secrets/jwt-key/versions/latest".
+//
+// Requests are authorized with a static access token if one is configured, and otherwise with
+// tokens of the metadata server, which are cached until shortly before they expire.
+type GcpSecretManager struct {
+	client      *http.Client
+	project     string
+	staticToken string
+	now         func() time.Time
+
+	mu             sync.Mutex
+	token          string
+	tokenExpiresAt time.Time
+}
+
+// NewGcpSecretManager creates a new GcpSecretManager store.
+//
+// Parameters:
+//   - client: The HTTP client for the requests to Google Cloud
+//   - project: The id of the project holding the secrets
+//   - accessToken: An OAuth 2.0 access token, empty to use the metadata server
+//
+// Returns:
+//   - *GcpSecretManager: A pointer to the newly created GcpSecretManager store
+func NewGcpSecretManager(client *http.Client, project string, accessToken string) *GcpSecretManager {
+	return &GcpSecretManager{client: client, project: project, staticToken: accessToken, now: time.Now}
+}
+
+// Name returns "gcp-secret-manager".
+func (g *GcpSecretManager) Name() string {
+	return "gcp-secret-manager"
+}
+
+// Fetch accesses a secret version. Secret Manager does not lease secrets, so the value is
+// refreshed by the cache after its refresh interval.
+func (g *GcpSecretManager) Fetch(ctx context.Context, ref string) (Lease, error) {
+	name := ref
+	if !strings.HasPrefix(name, "projects/") {
+		name = "projects/" + g.project + "/secrets/" + name
+	}
+	if !strings.Contains(name, "/versions/") {
+		name += "/versions/latest"
+	}
+	token, err := g.accessToken(ctx)
+	if err != nil {
+		return Lease{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://secretmanager.googleapis.com/v1/"+name+":access", nil)
+	if err != nil {
+		return Lease{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	res, err := g.client.Do(req)
+	if err != nil {
+		return Lease{}, fmt.Errorf("failed to access %s: %w", name, err)
+	}
+	defer res.Body.Close()
+	var response struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&response); err != nil {
+		return Lease{}, fmt.Errorf("failed to access %s: unexpected response with status %d", name, res.StatusCode)
+	}
+	if res.StatusCode >= 300 {
+		return Lease{}, fmt.Errorf("failed to access %s: status %d: %s", name, res.StatusCode, response.Error.Message)
+	}
+	value, err := base64.StdEncoding.DecodeString(response.Payload.Data)
+	if err != nil {
+		return Lease{}, fmt.Errorf("failed to access %s: malformed payload", name)
+	}
+	return Lease{Value: string(value)}, nil
+}
+
+// accessToken returns the configured token or a cached token of the metadata server.
+func (g *GcpSecretManager) accessToken(ctx context.Context) (string, error) {
+	if g.staticToken != "" {
+		return g.staticToken, nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.token != "" && g.now().Before(g.tokenExpiresAt) {
+		return g.token, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gcpMetadataTokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	res, err := g.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get access token from the metadata server: %w", err)
+	}
+	defer res.Body.Close()
+	var response struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if res.StatusCode >= 300 {
+		return "", fmt.Errorf("failed to get access token from the metadata server: status %d", res.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<16)).Decode(&response); err != nil {
+		return "", fmt.Errorf("failed to get access token from the metadata server: %w", err)
+	}
+	g.token = response.AccessToken
+	// tokens are replaced a minute early, so none expires while a request is on its way
+	g.tokenExpiresAt = g.now().Add(time.Duration(response.ExpiresIn)*time.Second - time.Minute)
+	return g.token, nil
+}
@@ -0,0 +1,129 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Vault reads secrets from HashiCorp Vault over its HTTP API. A reference names the path of a
+// secret and one of its fields, e.g. "secret/data/auth#jwt-key" for the KV version 2 engine mounted at
+// secret/ or "database/creds/auth#password" for dynamic database credentials, whose leases are renewed.
+type Vault struct {
+	client  *http.Client
+	address string
+	token   string
+}
+
+// vaultResponse is the JSON body of a read or lease renewal.
+type vaultResponse struct {
+	LeaseID       string         `json:"lease_id"`
+	LeaseDuration int64          `json:"lease_duration"`
+	Renewable     bool           `json:"renewable"`
+	Data          map[string]any `json:"data"`
+	Errors        []string       `json:"errors"`
+}
+
+// NewVault creates a new Vault store.
+//
+// Parameters:
+//   - client: The HTTP client for the requests to Vault
+//   - address: The base URL of Vault, e.g. "https://vault.example.com:8200"
+//   - token: The Vault token authorizing the reads
+//
+// Returns:
+//   - *Vault: A pointer to the newly created Vault store
+func NewVault(client *http.Client, address string, token string) *Vault {
+	return &Vault{client, strings.TrimSuffix(address, "/"), token}
+}
+
+// Name returns "vault".
+func (v *Vault) Name() string {
+	return "vault"
+}
+
+// Fetch reads a field of a secret. Fields of KV version 2 secrets are looked up in the nested data object.
+func (v *Vault) Fetch(ctx context.Context, ref string) (Lease, error) {
+	path, field, ok := strings.Cut(ref, "#")
+	if !ok || path == "" || field == "" {
+		return Lease{}, fmt.Errorf("vault secret reference %q must look like path#field", ref)
+	}
+	var response vaultResponse
+	if err := v.do(ctx, http.MethodGet, "/v1/"+strings.TrimPrefix(path, "/"), nil, &response); err != nil {
+		return Lease{}, fmt.Errorf("failed to read %s from vault: %w", path, err)
+	}
+
+	data := response.Data
+	if nested, ok := data["data"].(map[string]any); ok {
+		data = nested
+	}
+	value, ok := data[field].(string)
+	if !ok {
+		return Lease{}, fmt.Errorf("vault secret %s has no string field %s", path, field)
+	}
+	lease := Lease{Value: value, TTL: time.Duration(response.LeaseDuration) * time.Second}
+	if response.Renewable {
+		lease.ID = response.LeaseID
+	}
+	return lease, nil
+}
+
+// Renew extends the lease of a dynamic secret by its previous TTL.
+func (v *Vault) Renew(ctx context.Context, lease Lease) (Lease, error) {
+	body := map[string]any{"lease_id": lease.ID, "increment": int64(lease.TTL.Seconds())}
+	var response vaultResponse
+	if err := v.do(ctx, http.MethodPut, "/v1/sys/leases/renew", body, &response); err != nil {
+		return Lease{}, fmt.Errorf("failed to renew lease: %w", err)
+	}
+	renewed := Lease{Value: lease.Value, TTL: time.Duration(response.LeaseDuration) * time.Second}
+	if response.Renewable {
+		renewed.ID = response.LeaseID
+	}
+	return renewed, nil
+}
+
+// RenewToken extends the lease of the Vault token itself, so long-running processes keep access.
+func (v *Vault) RenewToken(ctx context.Context) error {
+	if err := v.do(ctx, http.MethodPost, "/v1/auth/token/renew-self", map[string]any{}, &vaultResponse{}); err != nil {
+		return fmt.Errorf("failed to renew vault token: %w", err)
+	}
+	return nil
+}
+
+// do sends an authenticated request to Vault and decodes the JSON response.
+func (v *Vault) do(ctx context.Context, method string, path string, body any, response *vaultResponse) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, v.address+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(response); err != nil && err != io.EOF {
+		return fmt.Errorf("unexpected response with status %d: %w", res.StatusCode, err)
+	}
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("status %d: %s", res.StatusCode, strings.Join(response.Errors, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package siem
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Identification of the service in CEF headers and syslog messages.
+const (
+	vendor  = "CodePawfect"
+	product = "hexagonal-auth-service"
+)
+
+// severities rate the events worth attention above the default severity, on the CEF scale from 0 to 10.
+var severities = map[string]int{
+	"user.login_failed":     5,
+	"user.account_locked":   7,
+	"user.mfa_disabled":     6,
+	"user.role_changed":     6,
+	"user.password_changed": 4,
+	"user.session_revoked":  4,
+	"user.merged":           5,
+	"user.deleted":          5,
+	"user.erased":           5,
+}
+
+// defaultSeverity is the CEF severity of all other events.
+const defaultSeverity = 3
+
+// severity returns the CEF severity of a record.
+func severity(record Record) int {
+	if s, ok := severities[record.Event.Name()]; ok {
+		return s
+	}
+	return defaultSeverity
+}
+
+// cefFields maps event fields onto CEF extension keys that SIEMs index without custom parsing.
+var cefFields = map[string]string{
+	"Username": "suser",
+	"UserID":   "suid",
+	"Reason":   "reason",
+}
+
+// formatCEF renders a record as a CEF message, e.g.
+// "CEF:0|CodePawfect|hexagonal-auth-service|1.2.0|user.login_failed|login failed|5|rt=... cat=authentication suser=alice ...".
+// The fields of the event without CEF key are appended as JSON in msg.
+func formatCEF(record Record, version string) string {
+	header := make([]string, 0, 7)
+	header = append(header, "CEF:0")
+	for _, field := range []string{vendor, product, version, record.Event.Name(), title(record.Event.Name()), strconv.Itoa(severity(record))} {
+		header = append(header, escapeHeader(field))
+	}
+
+	var extensions []string
+	extension := func(key, value string) {
+		if value != "" {
+			extensions = append(extensions, key+"="+escapeExtension(value))
+		}
+	}
+	extension("rt", strconv.FormatInt(record.Event.OccurredAt().UnixMilli(), 10))
+	extension("cat", string(record.Category))
+	extension("src", record.ClientIP)
+	extension("requestClientApplication", record.UserAgent)
+	extension("externalId", record.RequestID)
+
+	fields := eventFields(record)
+	for _, name := range slices.Sorted(maps.Keys(fields)) {
+		key, ok := cefFields[name]
+		value, isString := fields[name].(string)
+		if ok && isString {
+			extension(key, value)
+			delete(fields, name)
+		}
+	}
+	if len(fields) > 0 {
+		data, _ := json.Marshal(fields)
+		extension("msg", string(data))
+	}
+	return strings.Join(header, "|") + "|" + strings.Join(extensions, " ")
+}
+
+// eventFields returns the fields of an event except its time, which every format carries separately.
+func eventFields(record Record) map[string]any {
+	var fields map[string]any
+	data, err := json.Marshal(record.Event)
+	if err != nil || json.Unmarshal(data, &fields) != nil {
+		return nil
+	}
+	delete(fields, "At")
+	return fields
+}
+
+// title turns an event name into a readable one, e.g. "user.login_failed" into "login failed".
+func title(name string) string {
+	return strings.ReplaceAll(strings.TrimPrefix(name, "user."), "_", " ")
+}
+
+// escapeHeader escapes the characters CEF reserves in header fields.
+func escapeHeader(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\n", " ", "\r", " ").Replace(value)
+}
+
+// escapeExtension escapes the characters CEF reserves in extension values.
+func escapeExtension(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`).Replace(value)
+}
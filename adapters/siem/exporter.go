@@ -0,0 +1,175 @@
+// Package siem streams audit events to security information and event management systems,
+// as CEF messages over syslog and as JSON to the Splunk HTTP Event Collector.
+package siem
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"time"
+	"user-auth-hexagonal-architecture/internal/device"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/logging"
+	"user-auth-hexagonal-architecture/internal/requestid"
+)
+
+// logger writes the log records of this package. Failures of an exporter are logged as warnings,
+// which are not reported as errors, as a SIEM outage must not page for every dropped batch.
+var logger = logging.Component("siem")
+
+// Record is an audit event together with the request it was emitted in.
+type Record struct {
+	Event     events.Event
+	Category  events.Category
+	RequestID string
+	ClientIP  string
+	UserAgent string
+}
+
+// Sink sends batches of records to a SIEM.
+type Sink interface {
+	// Name identifies the sink in logs, e.g. "syslog".
+	Name() string
+	// Send delivers a batch of records. Errors wrapping ErrRejected are not retried.
+	Send(ctx context.Context, records []Record) error
+}
+
+// ErrRejected marks records the SIEM refused, e.g. for a wrong token, which sending again does not fix.
+var ErrRejected = errors.New("rejected by the SIEM")
+
+// ExportConfig configures an exporter.
+type ExportConfig struct {
+	// Categories are the event categories exported, all if empty.
+	Categories []events.Category
+	// QueueSize is the number of records that may wait to be sent. Records exceeding it are dropped.
+	QueueSize int
+	// BatchSize is the largest number of records sent at once.
+	BatchSize int
+	// FlushInterval is how long records wait for a batch to fill up before they are sent anyway.
+	FlushInterval time.Duration
+	// MaxAttempts is the number of attempts to send a batch before it is dropped.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry. It doubles with every further retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts.
+	MaxBackoff time.Duration
+}
+
+// DefaultExportConfig returns a configuration exporting all categories in batches of up to 100
+// records every 5 seconds, with 5 attempts per batch.
+func DefaultExportConfig() ExportConfig {
+	return ExportConfig{
+		QueueSize:      10000,
+		BatchSize:      100,
+		FlushInterval:  5 * time.Second,
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     30 * time.Second,
+	}
+}
+
+// Exporter batches the audit events of the selected categories and sends them to a sink.
+// It implements the EventHandler interface from the messaging ports package.
+//
+// Events are queued and sent by a background worker, so a slow or unavailable SIEM never delays a
+// use case. Failed batches are retried with exponential backoff and dropped after the last attempt.
+type Exporter struct {
+	sink    Sink
+	config  ExportConfig
+	queue   chan Record
+	dropped atomic.Uint64
+}
+
+// NewExporter creates a new Exporter. Call Start to begin sending.
+//
+// Parameters:
+//   - sink: The SIEM the records are sent to
+//   - config: The categories, batching and retries of the exporter
+//
+// Returns:
+//   - *Exporter: A pointer to the newly created Exporter
+func NewExporter(sink Sink, config ExportConfig) *Exporter {
+	return &Exporter{sink: sink, config: config, queue: make(chan Record, max(config.QueueSize, 1))}
+}
+
+// Handle queues an event of a selected category without blocking.
+//
+// Parameters:
+//   - ctx: A context.Context of the emitting use case, providing the request id and the client device
+//   - event: The event to export
+//
+// Returns:
+//   - error: Always nil; events that do not fit into the queue are counted and logged by the worker
+func (e *Exporter) Handle(ctx context.Context, event events.Event) error {
+	category := events.CategoryOf(event)
+	if len(e.config.Categories) > 0 && !slices.Contains(e.config.Categories, category) {
+		return nil
+	}
+
+	client := device.FromContext(ctx)
+	record := Record{Event: event, Category: category, RequestID: requestid.FromContext(ctx), ClientIP: client.IPAddress, UserAgent: client.UserAgent}
+	select {
+	case e.queue <- record:
+	default:
+		e.dropped.Add(1)
+	}
+	return nil
+}
+
+// Start launches the worker sending the batches. It keeps running until the given context is cancelled.
+//
+// Parameters:
+//   - ctx: A context.Context controlling the lifetime of the worker
+func (e *Exporter) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(e.config.FlushInterval)
+		defer ticker.Stop()
+
+		batch := make([]Record, 0, max(e.config.BatchSize, 1))
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case record := <-e.queue:
+				batch = append(batch, record)
+				if len(batch) < cap(batch) {
+					continue
+				}
+			case <-ticker.C:
+				if len(batch) == 0 {
+					continue
+				}
+			}
+			e.flush(ctx, batch)
+			batch = batch[:0]
+		}
+	}()
+}
+
+// flush sends a batch, retrying with exponential backoff.
+func (e *Exporter) flush(ctx context.Context, batch []Record) {
+	if dropped := e.dropped.Swap(0); dropped > 0 {
+		logger.WarnContext(ctx, "SIEM queue full, dropped audit events", "sink", e.sink.Name(), "count", dropped)
+	}
+
+	backoff := e.config.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := e.sink.Send(ctx, batch)
+		if err == nil {
+			return
+		}
+		if errors.Is(err, ErrRejected) || attempt >= e.config.MaxAttempts {
+			logger.WarnContext(ctx, "Error sending audit events, dropping batch", "sink", e.sink.Name(), "count", len(batch), "attempts", attempt, "error", err)
+			return
+		}
+		logger.WarnContext(ctx, "Error sending audit events, retrying", "sink", e.sink.Name(), "count", len(batch), "attempt", attempt, "retry_in", backoff, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, e.config.MaxBackoff)
+	}
+}
@@ -0,0 +1,175 @@
+package siem
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// hecPath is the path of the HEC endpoint accepting JSON events.
+const hecPath = "/services/collector/event"
+
+// SplunkConfig configures the Splunk HTTP Event Collector sink.
+type SplunkConfig struct {
+	// URL is the base URL of the collector, e.g. "https://splunk.example.com:8088".
+	URL string
+	// Token is the HEC token the events are authorized with.
+	Token string
+	// Index is the Splunk index the events are stored in, the default index of the token if empty.
+	Index string
+	// SourceType is the source type of the events.
+	SourceType string
+	// Timeout bounds sending a batch.
+	Timeout time.Duration
+}
+
+// hecEvent is a single event of a HEC request.
+type hecEvent struct {
+	Time       float64      `json:"time"`
+	Host       string       `json:"host,omitempty"`
+	Source     string       `json:"source"`
+	SourceType string       `json:"sourcetype,omitempty"`
+	Index      string       `json:"index,omitempty"`
+	Event      hecEventBody `json:"event"`
+}
+
+// hecEventBody is the searchable body of an event.
+type hecEventBody struct {
+	Name       string         `json:"name"`
+	Category   string         `json:"category"`
+	OccurredAt time.Time      `json:"occurredAt"`
+	RequestID  string         `json:"requestId,omitempty"`
+	ClientIP   string         `json:"clientIp,omitempty"`
+	UserAgent  string         `json:"userAgent,omitempty"`
+	Data       map[string]any `json:"data,omitempty"`
+}
+
+// SplunkSink posts records to the Splunk HTTP Event Collector, a batch per request.
+// It implements the Sink interface.
+type SplunkSink struct {
+	client   *http.Client
+	config   SplunkConfig
+	endpoint string
+	hostname string
+}
+
+// NewSplunkSink creates a new SplunkSink.
+//
+// Parameters:
+//   - client: The HTTP client used to post the events
+//   - config: The collector, token, index and timeout
+//
+// Returns:
+//   - *SplunkSink: A pointer to the newly created SplunkSink
+//   - error: An error if the URL is malformed or the token is missing
+func NewSplunkSink(client *http.Client, config SplunkConfig) (*SplunkSink, error) {
+	endpoint, err := HECEndpoint(config.URL)
+	if err != nil {
+		return nil, err
+	}
+	if config.Token == "" {
+		return nil, errors.New("Splunk HEC token is missing")
+	}
+	hostname, _ := os.Hostname()
+	return &SplunkSink{client, config, endpoint, hostname}, nil
+}
+
+// HECEndpoint returns the event endpoint of a collector.
+//
+// Parameters:
+//   - baseURL: The base URL of the collector, e.g. "https://splunk.example.com:8088", or the full endpoint URL
+//
+// Returns:
+//   - string: The URL events are posted to
+//   - error: An error if the URL is not an http or https URL
+func HECEndpoint(baseURL string) (string, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+		return "", fmt.Errorf("Splunk HEC URL %q must be an http or https URL", baseURL)
+	}
+	if !strings.HasSuffix(parsed.Path, hecPath) {
+		parsed.Path = strings.TrimSuffix(parsed.Path, "/") + hecPath
+	}
+	return parsed.String(), nil
+}
+
+// Name returns "splunk".
+func (ss *SplunkSink) Name() string {
+	return "splunk"
+}
+
+// Send posts a batch of records as concatenated JSON events.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the request
+//   - records: The records to send
+//
+// Returns:
+//   - error: An error wrapping ErrRejected if the collector refuses the events, e.g. for an invalid
+//     token, or an error if it cannot be reached or is unavailable
+func (ss *SplunkSink) Send(ctx context.Context, records []Record) error {
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	for _, record := range records {
+		if err := encoder.Encode(ss.event(record)); err != nil {
+			return fmt.Errorf("failed to encode %s: %w", record.Event.Name(), err)
+		}
+	}
+
+	if ss.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ss.config.Timeout)
+		defer cancel()
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, ss.endpoint, &body)
+	if err != nil {
+		return fmt.Errorf("failed to create HEC request: %w", err)
+	}
+	request.Header.Set("Authorization", "Splunk "+ss.config.Token)
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := ss.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to post to HEC: %w", err)
+	}
+	defer response.Body.Close()
+	message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+
+	switch {
+	case response.StatusCode < 300:
+		return nil
+	case response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500:
+		return fmt.Errorf("HEC answered %d: %s", response.StatusCode, message)
+	default:
+		return fmt.Errorf("%w: HEC answered %d: %s", ErrRejected, response.StatusCode, message)
+	}
+}
+
+// event converts a record into a HEC event.
+func (ss *SplunkSink) event(record Record) hecEvent {
+	occurredAt := record.Event.OccurredAt()
+	return hecEvent{
+		Time:       float64(occurredAt.UnixMilli()) / 1000,
+		Host:       ss.hostname,
+		Source:     product,
+		SourceType: ss.config.SourceType,
+		Index:      ss.config.Index,
+		Event: hecEventBody{
+			Name:       record.Event.Name(),
+			Category:   string(record.Category),
+			OccurredAt: occurredAt.UTC(),
+			RequestID:  record.RequestID,
+			ClientIP:   record.ClientIP,
+			UserAgent:  record.UserAgent,
+			Data:       eventFields(record),
+		},
+	}
+}
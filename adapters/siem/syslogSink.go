@@ -0,0 +1,180 @@
+package siem
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Syslog facility and severities of the messages, see RFC 5424.
+const (
+	facilityAuthPriv = 10
+	severityWarning  = 4
+	severityNotice   = 5
+	severityInfo     = 6
+)
+
+// SyslogConfig configures the syslog sink.
+type SyslogConfig struct {
+	// Address is the syslog server as "udp://host:514", "tcp://host:514" or "tls://host:6514".
+	Address string
+	// Version is the version of the service in the CEF header.
+	Version string
+	// Timeout bounds connecting and writing a batch, zero for no bound.
+	Timeout time.Duration
+}
+
+// SyslogSink sends records as CEF messages in RFC 5424 syslog frames. Over TCP and TLS the frames
+// are delimited by octet counting (RFC 6587), over UDP every message is a datagram.
+// It implements the Sink interface.
+//
+// The connection is kept open between batches and re-established after an error. A batch that
+// failed halfway is sent again as a whole, so the SIEM may receive some messages twice.
+type SyslogSink struct {
+	network  string
+	address  string
+	tls      bool
+	config   SyslogConfig
+	hostname string
+	pid      string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewSyslogSink creates a new SyslogSink.
+//
+// Parameters:
+//   - config: The syslog server, the version in the CEF header and the timeout
+//
+// Returns:
+//   - *SyslogSink: A pointer to the newly created SyslogSink
+//   - error: An error if the address is malformed
+func NewSyslogSink(config SyslogConfig) (*SyslogSink, error) {
+	network, address, useTLS, err := ParseSyslogAddress(config.Address)
+	if err != nil {
+		return nil, err
+	}
+	hostname, _ := os.Hostname()
+	return &SyslogSink{network: network, address: address, tls: useTLS, config: config, hostname: hostname, pid: strconv.Itoa(os.Getpid())}, nil
+}
+
+// ParseSyslogAddress splits the address of a syslog server.
+//
+// Parameters:
+//   - address: An address like "udp://host:514", "tcp://host:514" or "tls://host:6514"
+//
+// Returns:
+//   - string: The network to dial, "udp" or "tcp"
+//   - string: The host and port
+//   - bool: Whether the connection uses TLS
+//   - error: An error if the address is malformed
+func ParseSyslogAddress(address string) (string, string, bool, error) {
+	parsed, err := url.Parse(address)
+	if err != nil || parsed.Host == "" {
+		return "", "", false, fmt.Errorf("syslog address %q must look like tcp://host:514", address)
+	}
+	if _, _, err := net.SplitHostPort(parsed.Host); err != nil {
+		return "", "", false, fmt.Errorf("syslog address %q lacks the port", address)
+	}
+	switch parsed.Scheme {
+	case "udp", "tcp":
+		return parsed.Scheme, parsed.Host, false, nil
+	case "tls":
+		return "tcp", parsed.Host, true, nil
+	default:
+		return "", "", false, fmt.Errorf("syslog address %q must use udp, tcp or tls", address)
+	}
+}
+
+// Name returns "syslog".
+func (ss *SyslogSink) Name() string {
+	return "syslog"
+}
+
+// Send writes a batch of records to the syslog server.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the connection attempt
+//   - records: The records to send
+//
+// Returns:
+//   - error: An error if the server cannot be reached or the connection breaks
+func (ss *SyslogSink) Send(ctx context.Context, records []Record) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if ss.conn == nil {
+		conn, err := ss.dial(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to connect to syslog server: %w", err)
+		}
+		ss.conn = conn
+	}
+
+	if ss.config.Timeout > 0 {
+		if err := ss.conn.SetWriteDeadline(time.Now().Add(ss.config.Timeout)); err != nil {
+			return ss.reset(err)
+		}
+	}
+	for _, record := range records {
+		if _, err := ss.conn.Write(ss.frame(record)); err != nil {
+			return ss.reset(err)
+		}
+	}
+	return nil
+}
+
+// dial connects to the syslog server.
+func (ss *SyslogSink) dial(ctx context.Context) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: ss.config.Timeout}
+	if ss.tls {
+		return (&tls.Dialer{NetDialer: dialer}).DialContext(ctx, ss.network, ss.address)
+	}
+	return dialer.DialContext(ctx, ss.network, ss.address)
+}
+
+// reset closes a broken connection, so the next batch connects again.
+func (ss *SyslogSink) reset(err error) error {
+	_ = ss.conn.Close()
+	ss.conn = nil
+	return fmt.Errorf("failed to write to syslog server: %w", err)
+}
+
+// frame renders a record as an RFC 5424 message, prefixed with its length over stream connections.
+func (ss *SyslogSink) frame(record Record) []byte {
+	priority := facilityAuthPriv*8 + syslogSeverity(severity(record))
+	message := fmt.Sprintf("<%d>1 %s %s %s %s %s - %s", priority,
+		record.Event.OccurredAt().UTC().Format(time.RFC3339Nano), nilValue(ss.hostname), product, ss.pid,
+		record.Event.Name(), formatCEF(record, ss.config.Version))
+	if ss.network == "udp" {
+		return []byte(message)
+	}
+	return []byte(strconv.Itoa(len(message)) + " " + message)
+}
+
+// syslogSeverity maps a CEF severity onto a syslog severity.
+func syslogSeverity(cefSeverity int) int {
+	switch {
+	case cefSeverity >= 7:
+		return severityWarning
+	case cefSeverity >= 5:
+		return severityNotice
+	default:
+		return severityInfo
+	}
+}
+
+// nilValue replaces an empty header field with the RFC 5424 nil value "-".
+func nilValue(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,102 @@
+// Package signing holds the keys tokens are signed and verified with.
+package signing
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// retiredKey is a previous signing key that is still accepted until a point in time.
+type retiredKey struct {
+	key   []byte
+	until time.Time
+}
+
+// keyRingState is an immutable snapshot of the keys, swapped as a whole on rotation.
+type keyRingState struct {
+	current     []byte
+	activatedAt time.Time
+	retired     []retiredKey
+}
+
+// KeyRing implements the SigningKeyPort with a signing key that can be rotated at runtime.
+//
+// Tokens signed with a previous key remain valid for a grace period after the rotation, so
+// sessions survive a key change as long as their access tokens expire within the grace period.
+// Reads never block, so rotating the key does not stall requests in flight.
+type KeyRing struct {
+	state atomic.Pointer[keyRingState]
+	// mu serialises rotations, readers only load the state.
+	mu sync.Mutex
+}
+
+// NewKeyRing creates a new KeyRing signing with the given key.
+//
+// Parameters:
+//   - key: The initial signing key
+//
+// Returns:
+//   - *KeyRing: A pointer to the newly created KeyRing
+func NewKeyRing(key []byte) *KeyRing {
+	kr := &KeyRing{}
+	kr.state.Store(&keyRingState{current: key, activatedAt: time.Now()})
+	return kr
+}
+
+// SigningKey returns the key new tokens are signed with.
+func (kr *KeyRing) SigningKey() []byte {
+	return kr.state.Load().current
+}
+
+// ActivatedAt returns when the signing key was loaded or rotated to.
+func (kr *KeyRing) ActivatedAt() time.Time {
+	return kr.state.Load().activatedAt
+}
+
+// VerificationKeys returns the signing key followed by the retired keys whose grace period has not ended.
+func (kr *KeyRing) VerificationKeys() [][]byte {
+	state := kr.state.Load()
+	keys := [][]byte{state.current}
+	now := time.Now()
+	for _, retired := range state.retired {
+		if now.Before(retired.until) {
+			keys = append(keys, retired.key)
+		}
+	}
+	return keys
+}
+
+// Rotate makes a new key the signing key. The previous key is still accepted for the grace period,
+// a zero grace period invalidates all tokens signed with it immediately. Rotating to the current
+// key does nothing.
+//
+// Parameters:
+//   - key: The new signing key
+//   - grace: How long tokens signed with the previous key remain valid
+//
+// Returns:
+//   - bool: Whether the signing key changed
+func (kr *KeyRing) Rotate(key []byte, grace time.Duration) bool {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	state := kr.state.Load()
+	if bytes.Equal(state.current, key) {
+		return false
+	}
+
+	now := time.Now()
+	next := &keyRingState{current: key, activatedAt: now}
+	for _, retired := range state.retired {
+		if now.Before(retired.until) && !bytes.Equal(retired.key, key) {
+			next.retired = append(next.retired, retired)
+		}
+	}
+	if grace > 0 {
+		next.retired = append(next.retired, retiredKey{key: state.current, until: now.Add(grace)})
+	}
+	kr.state.Store(next)
+	return true
+}
@@ -0,0 +1,22 @@
+package system
+
+import (
+	"crypto/rand"
+)
+
+// CryptoRandomSource reads from the cryptographically secure random number generator of the host.
+// It implements the RandomSourcePort interface from the system ports package.
+type CryptoRandomSource struct{}
+
+// NewCryptoRandomSource creates a new CryptoRandomSource.
+//
+// Returns:
+//   - CryptoRandomSource: The random source
+func NewCryptoRandomSource() CryptoRandomSource {
+	return CryptoRandomSource{}
+}
+
+// Read fills b with cryptographically secure random bytes.
+func (CryptoRandomSource) Read(b []byte) (int, error) {
+	return rand.Read(b)
+}
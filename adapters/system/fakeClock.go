@@ -0,0 +1,47 @@
+package system
+
+import (
+	"sync"
+	"time"
+)
+
+// FakeClock is a clock that only moves when told to, so expiry and lockout rules can be
+// exercised without sleeping. It is safe for concurrent use.
+// It implements the ClockPort interface from the system ports package.
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock creates a FakeClock standing at the given time.
+//
+// Parameters:
+//   - now: The time the clock starts at
+//
+// Returns:
+//   - *FakeClock: A pointer to the newly created FakeClock
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the time the clock stands at.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set moves the clock to the given time, which may be in the past.
+func (c *FakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// Advance moves the clock forward by d and returns the new time.
+func (c *FakeClock) Advance(d time.Duration) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	return c.now
+}
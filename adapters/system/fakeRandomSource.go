@@ -0,0 +1,35 @@
+package system
+
+import (
+	"encoding/binary"
+	"math/rand/v2"
+	"sync"
+)
+
+// FakeRandomSource produces a reproducible stream of bytes from a seed, so generated secrets
+// are predictable in tests. It must never be used outside of tests. It is safe for concurrent use.
+// It implements the RandomSourcePort interface from the system ports package.
+type FakeRandomSource struct {
+	mu     sync.Mutex
+	stream *rand.ChaCha8
+}
+
+// NewFakeRandomSource creates a FakeRandomSource. Sources created with the same seed produce the same bytes.
+//
+// Parameters:
+//   - seed: The seed of the byte stream
+//
+// Returns:
+//   - *FakeRandomSource: A pointer to the newly created FakeRandomSource
+func NewFakeRandomSource(seed uint64) *FakeRandomSource {
+	var key [32]byte
+	binary.LittleEndian.PutUint64(key[:], seed)
+	return &FakeRandomSource{stream: rand.NewChaCha8(key)}
+}
+
+// Read fills b with the next bytes of the stream.
+func (r *FakeRandomSource) Read(b []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stream.Read(b)
+}
@@ -0,0 +1,24 @@
+// Package system provides the clock and random source adapters: the real ones backed by the
+// operating system and fake ones whose time and randomness tests control.
+package system
+
+import (
+	"time"
+)
+
+// SystemClock reads the wall clock of the host.
+// It implements the ClockPort interface from the system ports package.
+type SystemClock struct{}
+
+// NewSystemClock creates a new SystemClock.
+//
+// Returns:
+//   - SystemClock: The clock
+func NewSystemClock() SystemClock {
+	return SystemClock{}
+}
+
+// Now returns the current time of the host.
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,118 @@
+// Package tracing sets up OpenTelemetry tracing and provides the instrumentation of the web
+// adapter and the MongoDB client, so a request can be followed from the handler through the
+// services down to the database.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"user-auth-hexagonal-architecture/internal/buildinfo"
+)
+
+// ServiceName is the name the application reports its spans under.
+const ServiceName = "hexagonal-auth-service"
+
+// Config configures the export of traces.
+type Config struct {
+	// Endpoint is the host:port of the OTLP/HTTP collector, e.g. "localhost:4318". Empty disables tracing.
+	Endpoint string
+	// Insecure sends traces over plaintext HTTP instead of HTTPS.
+	Insecure bool
+	// SampleRatio is the fraction of new traces that are recorded. Traces started by a caller
+	// follow the caller's sampling decision.
+	SampleRatio float64
+}
+
+// Setup installs the global tracer provider and the W3C trace context propagator.
+//
+// With an empty endpoint no spans are exported, but incoming trace context is still propagated.
+//
+// Parameters:
+//   - ctx: A context.Context for creating the exporter
+//   - config: The tracing configuration
+//
+// Returns:
+//   - func(context.Context) error: Flushes and stops the export, to be called on shutdown
+//   - error: An error if the exporter cannot be created
+func Setup(ctx context.Context, config Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if config.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(config.Endpoint)}
+	if config.Insecure {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceName(ServiceName),
+			semconv.ServiceVersion(buildinfo.Get().Version),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Handler returns middleware starting a server span for every request, continuing the trace of the caller if any.
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware
+func Handler() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return otelhttp.NewHandler(next, "http.server", otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method
+		}))
+	}
+}
+
+// NameByRoute returns middleware naming the current server span after the route pattern mux
+// dispatches the request to, e.g. "POST /user/login", which keeps span names low in cardinality.
+//
+// Parameters:
+//   - mux: The mux the wrapped handler dispatches to
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware
+func NameByRoute(mux *http.ServeMux) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, pattern := mux.Handler(r); pattern != "" {
+				span := trace.SpanFromContext(r.Context())
+				span.SetName(pattern)
+				span.SetAttributes(attribute.String("http.route", pattern))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// MongoMonitor returns a command monitor creating a client span for every MongoDB command.
+// Command bodies are not recorded, as they contain password hashes.
+//
+// Returns:
+//   - *event.CommandMonitor: The monitor
+func MongoMonitor() *event.CommandMonitor {
+	return otelmongo.NewMonitor(otelmongo.WithCommandAttributeDisabled(true))
+}
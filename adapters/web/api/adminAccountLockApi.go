@@ -0,0 +1,92 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"net/http"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/middleware"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/adapters/web/validation"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// AdminAccountLockApi handles HTTP requests for locking and unlocking user accounts.
+// It acts as an adapter between the HTTP layer and the account lock use cases.
+type AdminAccountLockApi struct {
+	lockUserPort usecases.LockUserPort
+}
+
+// lockRequest represents the expected JSON structure for locking a user. Until is optional; without
+// it the account stays locked until it is unlocked explicitly.
+type lockRequest struct {
+	Reason string     `json:"reason"`
+	Until  *time.Time `json:"until"`
+}
+
+// validate checks that a known reason is given.
+func (lr *lockRequest) validate(v *validation.Validator) {
+	v.Required("reason", lr.Reason)
+	if lr.Reason != "" && !domain.LockReason(lr.Reason).Valid() {
+		v.Add("reason", "invalid_value", "reason must be one of SUSPECTED_COMPROMISE, FRAUD, POLICY_VIOLATION, LEGAL_REQUEST or OTHER")
+	}
+}
+
+// NewAdminAccountLockApiAdapter creates a new AdminAccountLockApi with the given use case port.
+//
+// Parameters:
+//   - lockUserPort: Port for locking and unlocking users
+//
+// Returns:
+//   - *AdminAccountLockApi: A pointer to the newly created AdminAccountLockApi
+func NewAdminAccountLockApiAdapter(lockUserPort usecases.LockUserPort) *AdminAccountLockApi {
+	return &AdminAccountLockApi{lockUserPort}
+}
+
+// InitAdminAccountLockRoutes sets up the HTTP routes for account locks.
+//
+// Access control is declared in RouteAccess.
+func (la *AdminAccountLockApi) InitAdminAccountLockRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /admin/users/{id}/lock", la.handleLockUser)
+	mux.HandleFunc("POST /admin/users/{id}/unlock", la.handleUnlockUser)
+}
+
+// handleLockUser handles HTTP POST requests that lock a user.
+//
+// The function expects a JSON body with the "reason" and an optional RFC 3339 "until", e.g.
+// {"reason": "SUSPECTED_COMPROMISE", "until": "2026-11-01T00:00:00Z"}. On success, it responds with
+// HTTP 204 No Content. It responds with 400 Bad Request for unknown reasons or an expiry in the past,
+// 404 Not Found if the user does not exist and 409 Conflict unless the account is active or locked.
+func (la *AdminAccountLockApi) handleLockUser(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.AuthenticationRequired, "")
+		return
+	}
+	var request lockRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+	var until time.Time
+	if request.Until != nil {
+		until = *request.Until
+	}
+
+	if err := la.lockUserPort.LockUser(r.Context(), r.PathValue("id"), domain.LockReason(request.Reason), until, principal.Subject); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleUnlockUser handles HTTP POST requests that lift the lock of a user.
+//
+// On success, it responds with HTTP 204 No Content, with 404 Not Found if the user does not exist
+// and 409 Conflict if the account is neither locked nor active.
+func (la *AdminAccountLockApi) handleUnlockUser(w http.ResponseWriter, r *http.Request) {
+	if err := la.lockUserPort.UnlockUser(r.Context(), r.PathValue("id")); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
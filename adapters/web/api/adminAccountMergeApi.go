@@ -0,0 +1,112 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"net/http"
+	"user-auth-hexagonal-architecture/adapters/web/middleware"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/adapters/web/validation"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// AdminAccountMergeApi handles HTTP requests for merging duplicate user accounts.
+// It acts as an adapter between the HTTP layer and the account merge use case.
+type AdminAccountMergeApi struct {
+	mergeAccountsPort usecases.MergeAccountsPort
+}
+
+// mergeRequest represents the expected JSON structure for merging a duplicate into an account.
+// Username is RELEASE or ALIAS, RELEASE if omitted.
+type mergeRequest struct {
+	DuplicateID string `json:"duplicateId"`
+	Username    string `json:"username"`
+}
+
+// validate checks that the duplicate is given and the username disposition is known.
+func (mr *mergeRequest) validate(v *validation.Validator) {
+	v.Required("duplicateId", mr.DuplicateID)
+	if mr.Username != "" && !domain.MergeUsernameDisposition(mr.Username).Valid() {
+		v.Add("username", "invalid_value", "username must be RELEASE or ALIAS")
+	}
+}
+
+// mergeResponse represents the JSON structure of the primary account after a merge.
+type mergeResponse struct {
+	ID               string   `json:"id"`
+	Username         string   `json:"username"`
+	Email            string   `json:"email,omitempty"`
+	Roles            []string `json:"roles"`
+	Aliases          []string `json:"aliases,omitempty"`
+	MergedID         string   `json:"mergedId"`
+	MergedUsername   string   `json:"mergedUsername"`
+	UsernameReleased bool     `json:"usernameReleased"`
+	EmailMoved       bool     `json:"emailMoved"`
+}
+
+// NewAdminAccountMergeApiAdapter creates a new AdminAccountMergeApi with the given use case port.
+//
+// Parameters:
+//   - mergeAccountsPort: Port for merging accounts
+//
+// Returns:
+//   - *AdminAccountMergeApi: A pointer to the newly created AdminAccountMergeApi
+func NewAdminAccountMergeApiAdapter(mergeAccountsPort usecases.MergeAccountsPort) *AdminAccountMergeApi {
+	return &AdminAccountMergeApi{mergeAccountsPort}
+}
+
+// InitAdminAccountMergeRoutes sets up the HTTP routes for account merges.
+//
+// Access control is declared in RouteAccess.
+func (ma *AdminAccountMergeApi) InitAdminAccountMergeRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /admin/users/{id}/merge", ma.handleMergeAccounts)
+}
+
+// handleMergeAccounts handles HTTP POST requests merging a duplicate account into the account of the path.
+//
+// The function expects a JSON body with the "duplicateId" and optionally what happens to the
+// username of the duplicate, e.g. {"duplicateId": "66f2...", "username": "ALIAS"}. With RELEASE the
+// username can be registered again, with ALIAS it logs in to the primary account. On success, it
+// responds with HTTP 200 OK and the merged primary account:
+//
+//	{"id": "66f1...", "username": "alice", "roles": ["USER"], "aliases": ["alice2"], "mergedId": "66f2...",
+//	 "mergedUsername": "alice2", "usernameReleased": false, "emailMoved": true}
+//
+// It responds with 400 Bad Request if the accounts are the same or belong to different tenants,
+// 404 Not Found if one of them does not exist and 409 Conflict if the email address has been taken.
+func (ma *AdminAccountMergeApi) handleMergeAccounts(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.AuthenticationRequired, "")
+		return
+	}
+	var request mergeRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+	disposition := domain.MergeUsernameDisposition(request.Username)
+	if disposition == "" {
+		disposition = domain.MergeReleaseUsername
+	}
+
+	merge, err := ma.mergeAccountsPort.MergeAccounts(r.Context(), r.PathValue("id"), request.DuplicateID, disposition, principal.Subject)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	aliases := make([]string, 0, len(merge.Primary.Aliases))
+	for _, alias := range merge.Primary.Aliases {
+		aliases = append(aliases, alias.String())
+	}
+	writeResponse(w, r, http.StatusOK, mergeResponse{
+		ID:               merge.Primary.ID,
+		Username:         merge.Primary.Username.String(),
+		Email:            merge.Primary.Email.String(),
+		Roles:            roleNames(merge.Primary.Roles),
+		Aliases:          aliases,
+		MergedID:         merge.Duplicate.ID,
+		MergedUsername:   merge.DuplicateUsername.String(),
+		UsernameReleased: merge.Disposition == domain.MergeReleaseUsername,
+		EmailMoved:       merge.EmailMoved,
+	})
+}
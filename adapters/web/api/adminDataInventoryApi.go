@@ -0,0 +1,60 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"net/http"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// AdminDataInventoryApi handles HTTP requests for the classification of the data stored about users.
+// It lets data protection tooling discover personal data instead of hardcoding field lists.
+type AdminDataInventoryApi struct{}
+
+// classifiedFieldResponse represents the JSON structure of a classified field.
+type classifiedFieldResponse struct {
+	Name           string `json:"name"`
+	Classification string `json:"classification"`
+	Exported       bool   `json:"exported"`
+	Erased         bool   `json:"erased"`
+}
+
+// recordClassificationResponse represents the JSON structure of a record and its classified fields.
+type recordClassificationResponse struct {
+	Record string                    `json:"record"`
+	Fields []classifiedFieldResponse `json:"fields"`
+}
+
+// NewAdminDataInventoryApiAdapter creates a new AdminDataInventoryApi.
+//
+// Returns:
+//   - *AdminDataInventoryApi: A pointer to the newly created AdminDataInventoryApi
+func NewAdminDataInventoryApiAdapter() *AdminDataInventoryApi {
+	return &AdminDataInventoryApi{}
+}
+
+// InitAdminDataInventoryRoutes sets up the HTTP route for the data inventory.
+func (da *AdminDataInventoryApi) InitAdminDataInventoryRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /admin/data-inventory", da.handleGetDataInventory)
+}
+
+// handleGetDataInventory handles HTTP GET requests for the data inventory.
+//
+// It responds with HTTP 200 OK and every record holding data about users, with the classification
+// of its fields ("pii", "credential", "operational") and whether they are part of data exports and erasures.
+func (da *AdminDataInventoryApi) handleGetDataInventory(w http.ResponseWriter, r *http.Request) {
+	inventory := domain.PersonalDataInventory()
+	response := make([]recordClassificationResponse, 0, len(inventory))
+	for _, record := range inventory {
+		fields := make([]classifiedFieldResponse, 0, len(record.Fields))
+		for _, f := range record.Fields {
+			fields = append(fields, classifiedFieldResponse{
+				Name:           f.Name,
+				Classification: string(f.Classification),
+				Exported:       f.Classification.Exportable(),
+				Erased:         f.Classification.Erasable(),
+			})
+		}
+		response = append(response, recordClassificationResponse{Record: record.Record, Fields: fields})
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
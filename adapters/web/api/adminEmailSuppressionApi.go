@@ -0,0 +1,86 @@
+package api
+
+import (
+	"net/http"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// AdminEmailSuppressionApi handles HTTP requests for the list of addresses no emails are sent to.
+// It acts as an adapter between the HTTP layer and the email suppression use cases.
+type AdminEmailSuppressionApi struct {
+	manageEmailSuppressionsPort usecases.ManageEmailSuppressionsPort
+}
+
+// emailSuppressionResponse represents the JSON structure of a suppressed address.
+type emailSuppressionResponse struct {
+	Address   string    `json:"address"`
+	Reason    string    `json:"reason"`
+	Provider  string    `json:"provider"`
+	Detail    string    `json:"detail,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// NewAdminEmailSuppressionApiAdapter creates a new AdminEmailSuppressionApi with the given use case port.
+//
+// Parameters:
+//   - manageEmailSuppressionsPort: Port for listing and lifting suppressions
+//
+// Returns:
+//   - *AdminEmailSuppressionApi: A pointer to the newly created AdminEmailSuppressionApi
+func NewAdminEmailSuppressionApiAdapter(manageEmailSuppressionsPort usecases.ManageEmailSuppressionsPort) *AdminEmailSuppressionApi {
+	return &AdminEmailSuppressionApi{manageEmailSuppressionsPort}
+}
+
+// InitAdminEmailSuppressionRoutes sets up the HTTP routes for email suppressions.
+//
+// Access control is declared in RouteAccess.
+func (sa *AdminEmailSuppressionApi) InitAdminEmailSuppressionRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /admin/email-suppressions", sa.handleListEmailSuppressions)
+	mux.HandleFunc("DELETE /admin/email-suppressions/{address}", sa.handleDeleteEmailSuppression)
+}
+
+// handleListEmailSuppressions handles HTTP GET requests for the suppressed addresses.
+//
+// The optional "limit" query parameter bounds the number of entries (default and maximum 1000).
+// It responds with HTTP 200 OK and the suppressions, newest first.
+func (sa *AdminEmailSuppressionApi) handleListEmailSuppressions(w http.ResponseWriter, r *http.Request) {
+	limit, err := positiveIntParam(r.URL.Query().Get("limit"), 0)
+	if err != nil {
+		problem.Write(w, r, problem.InvalidRequest, "limit must be a positive integer")
+		return
+	}
+
+	suppressions, err := sa.manageEmailSuppressionsPort.ListEmailSuppressions(r.Context(), limit)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	response := make([]emailSuppressionResponse, 0, len(suppressions))
+	for _, suppression := range suppressions {
+		response = append(response, emailSuppressionResponse{
+			Address:   suppression.Address,
+			Reason:    string(suppression.Reason),
+			Provider:  suppression.Provider,
+			Detail:    suppression.Detail,
+			CreatedAt: suppression.CreatedAt,
+			UpdatedAt: suppression.UpdatedAt,
+		})
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+// handleDeleteEmailSuppression handles HTTP DELETE requests lifting the suppression of an address,
+// e.g. after the user fixed their mailbox.
+//
+// On success, it responds with HTTP 204 No Content, with 404 Not Found if the address is not suppressed.
+func (sa *AdminEmailSuppressionApi) handleDeleteEmailSuppression(w http.ResponseWriter, r *http.Request) {
+	if err := sa.manageEmailSuppressionsPort.DeleteEmailSuppression(r.Context(), r.PathValue("address")); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
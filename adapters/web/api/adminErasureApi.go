@@ -0,0 +1,149 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/middleware"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/adapters/web/validation"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// AdminErasureApi handles HTTP requests for erasing the personal data of users and for the legal
+// holds preventing it.
+// It acts as an adapter between the HTTP layer and the erasure use cases.
+type AdminErasureApi struct {
+	eraseUserPort usecases.EraseUserPort
+	legalHoldPort usecases.LegalHoldPort
+}
+
+// erasureRequest represents the expected JSON structure for erasure requests.
+type erasureRequest struct {
+	OverrideLegalHold bool `json:"overrideLegalHold"`
+}
+
+// validate accepts every request; whether a legal hold may be overridden is decided by the use case.
+func (er *erasureRequest) validate(*validation.Validator) {}
+
+// erasedRecordsResponse represents the JSON structure of the records of a kind that were erased.
+type erasedRecordsResponse struct {
+	Record string `json:"record"`
+	Action string `json:"action"`
+	Count  int64  `json:"count"`
+}
+
+// erasureCertificateResponse represents the JSON structure of an erasure certificate.
+type erasureCertificateResponse struct {
+	ID                  string                  `json:"id"`
+	UserID              string                  `json:"userId"`
+	TenantID            string                  `json:"tenantId"`
+	Pseudonym           string                  `json:"pseudonym"`
+	RequestedBy         string                  `json:"requestedBy"`
+	LegalHoldOverridden bool                    `json:"legalHoldOverridden"`
+	Records             []erasedRecordsResponse `json:"records"`
+	ErasedAt            time.Time               `json:"erasedAt"`
+}
+
+// NewAdminErasureApiAdapter creates a new AdminErasureApi with the given use case ports.
+//
+// Parameters:
+//   - eraseUserPort: Port for erasing users and reading erasure certificates
+//   - legalHoldPort: Port for placing and lifting legal holds
+//
+// Returns:
+//   - *AdminErasureApi: A pointer to the newly created AdminErasureApi
+func NewAdminErasureApiAdapter(eraseUserPort usecases.EraseUserPort, legalHoldPort usecases.LegalHoldPort) *AdminErasureApi {
+	return &AdminErasureApi{eraseUserPort, legalHoldPort}
+}
+
+// InitAdminErasureRoutes sets up the HTTP routes for erasures and legal holds.
+//
+// Access control is declared in RouteAccess.
+func (ea *AdminErasureApi) InitAdminErasureRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /admin/users/{id}/erasure", ea.handleEraseUser)
+	mux.HandleFunc("GET /admin/erasure-certificates/{id}", ea.handleGetCertificate)
+	mux.HandleFunc("PUT /admin/users/{id}/legal-hold", ea.handlePlaceLegalHold)
+	mux.HandleFunc("DELETE /admin/users/{id}/legal-hold", ea.handleLiftLegalHold)
+}
+
+// handleEraseUser handles HTTP POST requests erasing the personal data of a user.
+//
+// The function accepts an optional JSON body with "overrideLegalHold". On success, it responds
+// with HTTP 201 Created, the erasure certificate and its URL in the Location header. It responds
+// with 404 Not Found if the user does not exist and 409 Conflict if the user is under legal hold.
+func (ea *AdminErasureApi) handleEraseUser(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.AuthenticationRequired, "")
+		return
+	}
+	var request erasureRequest
+	if r.ContentLength != 0 && !decodeRequest(w, r, &request) {
+		return
+	}
+
+	certificate, err := ea.eraseUserPort.EraseUser(r.Context(), r.PathValue("id"), principal.Subject, request.OverrideLegalHold)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	prefix, _, _ := strings.Cut(r.URL.Path, "/admin/users/")
+	w.Header().Set("Location", prefix+"/admin/erasure-certificates/"+certificate.ID)
+	writeResponse(w, r, http.StatusCreated, toErasureCertificateResponse(certificate))
+}
+
+// handleGetCertificate handles HTTP GET requests for an erasure certificate.
+//
+// It responds with HTTP 200 OK and the certificate, or 404 Not Found if it does not exist.
+func (ea *AdminErasureApi) handleGetCertificate(w http.ResponseWriter, r *http.Request) {
+	certificate, err := ea.eraseUserPort.GetErasureCertificate(r.Context(), r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, toErasureCertificateResponse(certificate))
+}
+
+// handlePlaceLegalHold handles HTTP PUT requests placing a legal hold on a user.
+//
+// On success, it responds with HTTP 204 No Content, with 404 Not Found if the user does not exist.
+func (ea *AdminErasureApi) handlePlaceLegalHold(w http.ResponseWriter, r *http.Request) {
+	if err := ea.legalHoldPort.PlaceLegalHold(r.Context(), r.PathValue("id")); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleLiftLegalHold handles HTTP DELETE requests lifting the legal hold of a user.
+//
+// On success, it responds with HTTP 204 No Content, with 404 Not Found if the user does not exist.
+func (ea *AdminErasureApi) handleLiftLegalHold(w http.ResponseWriter, r *http.Request) {
+	if err := ea.legalHoldPort.LiftLegalHold(r.Context(), r.PathValue("id")); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// toErasureCertificateResponse converts an erasure certificate into its JSON representation.
+func toErasureCertificateResponse(certificate domain.ErasureCertificate) erasureCertificateResponse {
+	response := erasureCertificateResponse{
+		ID:                  certificate.ID,
+		UserID:              certificate.UserID,
+		TenantID:            certificate.TenantID,
+		Pseudonym:           certificate.Pseudonym,
+		RequestedBy:         certificate.RequestedBy,
+		LegalHoldOverridden: certificate.LegalHoldOverridden,
+		Records:             make([]erasedRecordsResponse, 0, len(certificate.Records)),
+		ErasedAt:            certificate.ErasedAt,
+	}
+	for _, records := range certificate.Records {
+		response.Records = append(response.Records, erasedRecordsResponse{Record: records.Record, Action: string(records.Action), Count: records.Count})
+	}
+	return response
+}
@@ -0,0 +1,140 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/ports/messaging"
+	"user-auth-hexagonal-architecture/internal/tenant"
+)
+
+// Event stream settings.
+const (
+	// streamBuffer is the number of events buffered per connection before events are dropped.
+	streamBuffer = 64
+	// streamHeartbeat is the interval of comment lines keeping idle connections and proxies alive.
+	streamHeartbeat = 15 * time.Second
+	// streamRetry is the reconnection delay in milliseconds announced to clients.
+	streamRetry = 5000
+)
+
+// defaultStreamEvents are the security relevant events streamed if the client does not choose.
+var defaultStreamEvents = []string{
+	events.UserRegistered{}.Name(),
+	events.LoginFailed{}.Name(),
+	events.UserStatusChanged{}.Name(),
+	events.PasswordChanged{}.Name(),
+	events.AccountLocked{}.Name(),
+	events.AccountUnlocked{}.Name(),
+	events.SessionEvicted{}.Name(),
+	events.SessionRevoked{}.Name(),
+	events.UserErased{}.Name(),
+	events.AccountsMerged{}.Name(),
+	events.MfaEnabled{}.Name(),
+	events.MfaDisabled{}.Name(),
+}
+
+// AdminEventStreamApi handles HTTP requests for the live stream of security events.
+type AdminEventStreamApi struct {
+	eventStreamPort messaging.EventStreamPort
+}
+
+// streamEvent represents the JSON structure of the data of a streamed event.
+type streamEvent struct {
+	Type       string       `json:"type"`
+	OccurredAt time.Time    `json:"occurredAt"`
+	Data       events.Event `json:"data"`
+}
+
+// NewAdminEventStreamApiAdapter creates a new AdminEventStreamApi.
+//
+// Parameters:
+//   - eventStreamPort: Port for following domain events
+//
+// Returns:
+//   - *AdminEventStreamApi: A pointer to the newly created AdminEventStreamApi
+func NewAdminEventStreamApiAdapter(eventStreamPort messaging.EventStreamPort) *AdminEventStreamApi {
+	return &AdminEventStreamApi{eventStreamPort}
+}
+
+// InitAdminEventStreamRoutes sets up the HTTP route of the event stream.
+func (ea *AdminEventStreamApi) InitAdminEventStreamRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /admin/events/stream", ea.handleEventStream)
+}
+
+// handleEventStream handles HTTP GET requests for the Server-Sent Events stream of security events.
+// Only the events of the tenant of the request are streamed.
+//
+// The optional "events" query parameter is a comma-separated list of event names to receive,
+// e.g. "user.login_failed,user.status_changed", or "*" for all events. It defaults to
+// defaultStreamEvents: registrations, failed logins, account status changes, password changes,
+// account locks and unlocks, evicted and revoked sessions, erasures, account merges and MFA being
+// enabled or disabled. Every event is sent with its name as SSE event type and a JSON data line:
+//
+//	event: user.login_failed
+//	data: {"type":"user.login_failed","occurredAt":"...","data":{"Username":"alice","Reason":"invalid_credentials",...}}
+//
+// Events that happen while the client is disconnected or too slow to keep up are not replayed.
+func (ea *AdminEventStreamApi) handleEventStream(w http.ResponseWriter, r *http.Request) {
+	wanted := map[string]bool{}
+	for _, name := range defaultStreamEvents {
+		wanted[name] = true
+	}
+	if param := r.URL.Query().Get("events"); param != "" {
+		wanted = map[string]bool{}
+		for _, name := range strings.Split(param, ",") {
+			wanted[strings.TrimSpace(name)] = true
+		}
+	}
+
+	controller := http.NewResponseController(w)
+	// the stream outlives the server's write timeout
+	_ = controller.SetWriteDeadline(time.Time{})
+
+	stream, unsubscribe := ea.eventStreamPort.SubscribeEvents(tenant.FromContext(r.Context()), streamBuffer)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry)
+	if err := controller.Flush(); err != nil {
+		logger.ErrorContext(r.Context(), "Error starting event stream", "error", err)
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	var id int64
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case event, ok := <-stream:
+			if !ok {
+				return
+			}
+			if !wanted[event.Name()] && !wanted["*"] {
+				continue
+			}
+			data, err := json.Marshal(streamEvent{event.Name(), event.OccurredAt(), event})
+			if err != nil {
+				logger.ErrorContext(r.Context(), "Error encoding event", "event", event.Name(), "error", err)
+				continue
+			}
+			id++
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", id, event.Name(), data)
+		}
+		if err := controller.Flush(); err != nil {
+			return
+		}
+	}
+}
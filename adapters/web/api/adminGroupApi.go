@@ -0,0 +1,192 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"net/http"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/adapters/web/validation"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// AdminGroupApi handles HTTP requests for managing user groups and their members.
+// It acts as an adapter between the HTTP layer and the group use cases.
+type AdminGroupApi struct {
+	manageGroupsPort usecases.ManageGroupsPort
+}
+
+// groupRolesRequest represents the expected JSON structure for group definition requests.
+type groupRolesRequest struct {
+	Roles []string `json:"roles"`
+}
+
+// validate checks that every role name is well-formed.
+func (gr *groupRolesRequest) validate(v *validation.Validator) {
+	for _, role := range gr.Roles {
+		if !domain.Role(role).Valid() {
+			v.Add("roles", "invalid_format", "roles must be named like \"SUPPORT_AGENT\"")
+		}
+	}
+}
+
+// groupResponse represents the JSON structure of a group.
+type groupResponse struct {
+	Name      string    `json:"name"`
+	Roles     []string  `json:"roles"`
+	Members   []string  `json:"members"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// NewAdminGroupApiAdapter creates a new AdminGroupApi with the given use case port.
+//
+// Parameters:
+//   - manageGroupsPort: Port for managing groups and their members
+//
+// Returns:
+//   - *AdminGroupApi: A pointer to the newly created AdminGroupApi
+func NewAdminGroupApiAdapter(manageGroupsPort usecases.ManageGroupsPort) *AdminGroupApi {
+	return &AdminGroupApi{manageGroupsPort}
+}
+
+// InitAdminGroupRoutes sets up the HTTP routes for group management.
+//
+// All routes live under /admin/groups; access control is declared in RouteAccess.
+func (ga *AdminGroupApi) InitAdminGroupRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /admin/groups", ga.handleListGroups)
+	mux.HandleFunc("GET /admin/groups/{name}", ga.handleGetGroup)
+	mux.HandleFunc("PUT /admin/groups/{name}", ga.handleSaveGroup)
+	mux.HandleFunc("DELETE /admin/groups/{name}", ga.handleDeleteGroup)
+	mux.HandleFunc("PUT /admin/groups/{name}/members/{userId}", ga.handleAddMember)
+	mux.HandleFunc("DELETE /admin/groups/{name}/members/{userId}", ga.handleRemoveMember)
+}
+
+// handleListGroups handles HTTP GET requests for all groups.
+//
+// It responds with HTTP 200 OK and the groups sorted by name.
+func (ga *AdminGroupApi) handleListGroups(w http.ResponseWriter, r *http.Request) {
+	groups, err := ga.manageGroupsPort.ListGroups(r.Context())
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	response := make([]groupResponse, 0, len(groups))
+	for _, group := range groups {
+		response = append(response, toGroupResponse(group))
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+// handleGetGroup handles HTTP GET requests for a single group.
+//
+// It responds with HTTP 200 OK and the group, or 404 Not Found if the group does not exist.
+func (ga *AdminGroupApi) handleGetGroup(w http.ResponseWriter, r *http.Request) {
+	name, ok := groupName(w, r)
+	if !ok {
+		return
+	}
+
+	group, err := ga.manageGroupsPort.GetGroup(r.Context(), name)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, toGroupResponse(group))
+}
+
+// handleSaveGroup handles HTTP PUT requests that create a group or replace its roles.
+//
+// The function expects a JSON body with a "roles" list, e.g. ["SUPPORT"]. On success, it responds
+// with HTTP 200 OK and the stored group. It responds with 400 Bad Request for malformed names and
+// unknown roles.
+func (ga *AdminGroupApi) handleSaveGroup(w http.ResponseWriter, r *http.Request) {
+	name, ok := groupName(w, r)
+	if !ok {
+		return
+	}
+	var request groupRolesRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	roles := make([]domain.Role, 0, len(request.Roles))
+	for _, role := range request.Roles {
+		roles = append(roles, domain.Role(role))
+	}
+	group, err := ga.manageGroupsPort.SaveGroup(r.Context(), name, roles)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, toGroupResponse(group))
+}
+
+// handleDeleteGroup handles HTTP DELETE requests for a group.
+//
+// On success, it responds with HTTP 204 No Content, or 404 Not Found if the group does not exist.
+func (ga *AdminGroupApi) handleDeleteGroup(w http.ResponseWriter, r *http.Request) {
+	name, ok := groupName(w, r)
+	if !ok {
+		return
+	}
+
+	if err := ga.manageGroupsPort.DeleteGroup(r.Context(), name); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleAddMember handles HTTP PUT requests that add a user to a group.
+//
+// On success, it responds with HTTP 204 No Content, or 404 Not Found if the group or the user
+// does not exist.
+func (ga *AdminGroupApi) handleAddMember(w http.ResponseWriter, r *http.Request) {
+	name, ok := groupName(w, r)
+	if !ok {
+		return
+	}
+
+	if err := ga.manageGroupsPort.AddGroupMember(r.Context(), name, r.PathValue("userId")); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleRemoveMember handles HTTP DELETE requests that remove a user from a group.
+//
+// On success, it responds with HTTP 204 No Content, or 404 Not Found if the group does not exist.
+func (ga *AdminGroupApi) handleRemoveMember(w http.ResponseWriter, r *http.Request) {
+	name, ok := groupName(w, r)
+	if !ok {
+		return
+	}
+
+	if err := ga.manageGroupsPort.RemoveGroupMember(r.Context(), name, r.PathValue("userId")); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// groupName extracts the group name from the path and answers malformed names with 400 Bad Request.
+func groupName(w http.ResponseWriter, r *http.Request) (string, bool) {
+	name := r.PathValue("name")
+	if !domain.ValidGroupName(name) {
+		problem.Write(w, r, problem.InvalidRequest, "group names consist of up to 64 lower case letters, digits, dashes and underscores")
+		return "", false
+	}
+	return name, true
+}
+
+// toGroupResponse converts a group into its JSON representation.
+func toGroupResponse(group domain.Group) groupResponse {
+	return groupResponse{
+		Name:      group.Name,
+		Roles:     roleNames(group.Roles),
+		Members:   group.Members,
+		UpdatedAt: group.UpdatedAt,
+	}
+}
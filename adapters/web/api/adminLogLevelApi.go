@@ -0,0 +1,107 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/middleware"
+	"user-auth-hexagonal-architecture/adapters/web/validation"
+	"user-auth-hexagonal-architecture/internal/logging"
+)
+
+// AdminLogLevelApi handles HTTP requests for changing the log level at runtime.
+type AdminLogLevelApi struct {
+	levels *logging.LevelSwitch
+	// revertAfter is how long a changed level applies if the request does not say.
+	revertAfter time.Duration
+}
+
+// logLevelRequest represents the expected JSON structure for log level changes.
+type logLevelRequest struct {
+	Level           string `json:"level"`
+	DurationSeconds *int   `json:"durationSeconds"`
+}
+
+// validate checks that the level is known and the duration is not negative.
+func (lr *logLevelRequest) validate(v *validation.Validator) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(lr.Level)); err != nil {
+		v.Add("level", "invalid_value", "level must be one of debug, info, warn or error")
+	}
+	if lr.DurationSeconds != nil && *lr.DurationSeconds < 0 {
+		v.Add("durationSeconds", "invalid_value", "durationSeconds must not be negative")
+	}
+}
+
+// logLevelResponse represents the JSON structure of the current log level.
+type logLevelResponse struct {
+	Level     string     `json:"level"`
+	BaseLevel string     `json:"baseLevel"`
+	RevertAt  *time.Time `json:"revertAt,omitempty"`
+}
+
+// NewAdminLogLevelApiAdapter creates a new AdminLogLevelApi.
+//
+// Parameters:
+//   - levels: The switch holding the log level
+//   - revertAfter: How long a changed level applies unless the request gives a duration, zero keeps it
+//
+// Returns:
+//   - *AdminLogLevelApi: A pointer to the newly created AdminLogLevelApi
+func NewAdminLogLevelApiAdapter(levels *logging.LevelSwitch, revertAfter time.Duration) *AdminLogLevelApi {
+	return &AdminLogLevelApi{levels, revertAfter}
+}
+
+// InitAdminLogLevelRoutes sets up the HTTP routes for reading and changing the log level.
+func (la *AdminLogLevelApi) InitAdminLogLevelRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /admin/log-level", la.handleGetLogLevel)
+	mux.HandleFunc("PUT /admin/log-level", la.handleSetLogLevel)
+}
+
+// handleGetLogLevel handles HTTP GET requests for the current log level.
+//
+// It responds with HTTP 200 OK, the current and the configured level and, while a changed level
+// applies, the time it reverts.
+func (la *AdminLogLevelApi) handleGetLogLevel(w http.ResponseWriter, r *http.Request) {
+	writeResponse(w, r, http.StatusOK, toLogLevelResponse(la.levels.Get()))
+}
+
+// handleSetLogLevel handles HTTP PUT requests changing the log level.
+//
+// The function expects a JSON body with a "level" of "debug", "info", "warn" or "error" and an
+// optional "durationSeconds" after which the configured level applies again; zero keeps the level
+// until the next change. On success, it responds with HTTP 200 OK and the new level; an invalid
+// body is answered with 400 Bad Request.
+func (la *AdminLogLevelApi) handleSetLogLevel(w http.ResponseWriter, r *http.Request) {
+	var request logLevelRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	var level slog.Level
+	_ = level.UnmarshalText([]byte(request.Level))
+	duration := la.revertAfter
+	if request.DurationSeconds != nil {
+		duration = time.Duration(*request.DurationSeconds) * time.Second
+	}
+	la.levels.Set(level, duration)
+
+	principal, _ := middleware.PrincipalFromContext(r.Context())
+	logger.WarnContext(r.Context(), "Log level changed", "level", level, "duration", duration, "by", principal.Subject)
+
+	writeResponse(w, r, http.StatusOK, toLogLevelResponse(la.levels.Get()))
+}
+
+// toLogLevelResponse converts a level state into its JSON representation.
+func toLogLevelResponse(state logging.LevelState) logLevelResponse {
+	response := logLevelResponse{
+		Level:     strings.ToLower(state.Level.String()),
+		BaseLevel: strings.ToLower(state.Base.String()),
+	}
+	if !state.RevertAt.IsZero() {
+		response.RevertAt = &state.RevertAt
+	}
+	return response
+}
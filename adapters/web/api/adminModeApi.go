@@ -0,0 +1,98 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"net/http"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/middleware"
+	"user-auth-hexagonal-architecture/adapters/web/validation"
+)
+
+// AdminModeApi handles HTTP requests for switching the API into read-only or maintenance mode.
+type AdminModeApi struct {
+	modes *middleware.ModeSwitch
+}
+
+// modeRequest represents the expected JSON structure for mode changes.
+type modeRequest struct {
+	Mode              string `json:"mode"`
+	RetryAfterSeconds int    `json:"retryAfterSeconds"`
+	Message           string `json:"message"`
+}
+
+// validate checks that the mode is known and the retry interval is not negative.
+func (mr *modeRequest) validate(v *validation.Validator) {
+	if _, err := middleware.ParseMode(mr.Mode); err != nil {
+		v.Add("mode", "invalid_value", "mode must be one of normal, read_only or maintenance")
+	}
+	if mr.RetryAfterSeconds < 0 {
+		v.Add("retryAfterSeconds", "invalid_value", "retryAfterSeconds must not be negative")
+	}
+	v.MaxBytes("message", mr.Message, 500)
+}
+
+// modeResponse represents the JSON structure of the current mode.
+type modeResponse struct {
+	Mode              string    `json:"mode"`
+	RetryAfterSeconds int       `json:"retryAfterSeconds,omitempty"`
+	Message           string    `json:"message,omitempty"`
+	Since             time.Time `json:"since"`
+}
+
+// NewAdminModeApiAdapter creates a new AdminModeApi.
+//
+// Parameters:
+//   - modes: The switch holding the mode of the API
+//
+// Returns:
+//   - *AdminModeApi: A pointer to the newly created AdminModeApi
+func NewAdminModeApiAdapter(modes *middleware.ModeSwitch) *AdminModeApi {
+	return &AdminModeApi{modes}
+}
+
+// InitAdminModeRoutes sets up the HTTP routes for reading and switching the mode.
+func (ma *AdminModeApi) InitAdminModeRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /admin/mode", ma.handleGetMode)
+	mux.HandleFunc("PUT /admin/mode", ma.handleSetMode)
+}
+
+// handleGetMode handles HTTP GET requests for the current mode.
+//
+// It responds with HTTP 200 OK and the mode as JSON.
+func (ma *AdminModeApi) handleGetMode(w http.ResponseWriter, r *http.Request) {
+	writeResponse(w, r, http.StatusOK, toModeResponse(ma.modes.Get()))
+}
+
+// handleSetMode handles HTTP PUT requests switching the mode.
+//
+// The function expects a JSON body with a "mode" of "normal", "read_only" or "maintenance", and an
+// optional "retryAfterSeconds" and "message" announced to rejected clients. On success, it responds
+// with HTTP 200 OK and the new mode; an invalid body is answered with 400 Bad Request.
+func (ma *AdminModeApi) handleSetMode(w http.ResponseWriter, r *http.Request) {
+	var request modeRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	state := middleware.ModeState{
+		Mode:       middleware.Mode(request.Mode),
+		RetryAfter: time.Duration(request.RetryAfterSeconds) * time.Second,
+		Message:    request.Message,
+	}
+	ma.modes.Set(state)
+
+	principal, _ := middleware.PrincipalFromContext(r.Context())
+	logger.InfoContext(r.Context(), "API mode switched", "mode", request.Mode, "by", principal.Subject)
+
+	writeResponse(w, r, http.StatusOK, toModeResponse(ma.modes.Get()))
+}
+
+// toModeResponse converts a mode state into its JSON representation.
+func toModeResponse(state middleware.ModeState) modeResponse {
+	return modeResponse{
+		Mode:              string(state.Mode),
+		RetryAfterSeconds: int(state.RetryAfter.Seconds()),
+		Message:           state.Message,
+		Since:             state.Since,
+	}
+}
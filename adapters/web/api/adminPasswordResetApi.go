@@ -0,0 +1,63 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"net/http"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/adapters/web/validation"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// AdminPasswordResetApi handles HTTP requests for resetting the password of a user to a temporary one.
+// It acts as an adapter between the HTTP layer and the password reset use case.
+type AdminPasswordResetApi struct {
+	resetPasswordPort usecases.ResetPasswordPort
+}
+
+// resetPasswordRequest represents the expected JSON structure for resetting a password.
+type resetPasswordRequest struct {
+	TemporaryPassword string `json:"temporaryPassword"`
+}
+
+// validate checks that a temporary password is given.
+func (rr *resetPasswordRequest) validate(v *validation.Validator) {
+	v.Required("temporaryPassword", rr.TemporaryPassword).MaxBytes("temporaryPassword", rr.TemporaryPassword, validation.MaxPasswordBytes)
+}
+
+// NewAdminPasswordResetApiAdapter creates a new AdminPasswordResetApi with the given use case port.
+//
+// Parameters:
+//   - resetPasswordPort: Port for resetting passwords
+//
+// Returns:
+//   - *AdminPasswordResetApi: A pointer to the newly created AdminPasswordResetApi
+func NewAdminPasswordResetApiAdapter(resetPasswordPort usecases.ResetPasswordPort) *AdminPasswordResetApi {
+	return &AdminPasswordResetApi{resetPasswordPort}
+}
+
+// InitAdminPasswordResetRoutes sets up the HTTP routes for password resets.
+//
+// Access control is declared in RouteAccess.
+func (pr *AdminPasswordResetApi) InitAdminPasswordResetRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /admin/users/{id}/password", pr.handleResetPassword)
+}
+
+// handleResetPassword handles HTTP POST requests that reset the password of a user.
+//
+// The function expects a JSON body with the "temporaryPassword", which has to satisfy the password
+// policy of the tenant, e.g. {"temporaryPassword": "..."}. The user has to replace it at the next
+// login, and the sessions of the user are revoked. On success, it responds with HTTP 204 No Content.
+// Weak passwords are answered with 400 Bad Request, unknown users with 404 Not Found and deleted
+// accounts with 409 Conflict.
+func (pr *AdminPasswordResetApi) handleResetPassword(w http.ResponseWriter, r *http.Request) {
+	var request resetPasswordRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	if err := pr.resetPasswordPort.ResetPassword(r.Context(), r.PathValue("id"), request.TemporaryPassword); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
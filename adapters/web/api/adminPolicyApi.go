@@ -0,0 +1,159 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"net/http"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/adapters/web/validation"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// AdminPolicyApi handles HTTP requests for managing attribute-based access policies.
+// It acts as an adapter between the HTTP layer and the policy use cases.
+type AdminPolicyApi struct {
+	managePoliciesPort usecases.ManagePoliciesPort
+}
+
+// policyRequest represents the expected JSON structure for policy definition requests.
+type policyRequest struct {
+	Description string   `json:"description"`
+	Effect      string   `json:"effect"`
+	Actions     []string `json:"actions"`
+	Condition   string   `json:"condition"`
+}
+
+// validate checks the effect, the actions and the syntax of the condition.
+func (pr *policyRequest) validate(v *validation.Validator) {
+	v.MaxBytes("description", pr.Description, 256)
+	if pr.Effect != string(domain.EffectAllow) && pr.Effect != string(domain.EffectDeny) {
+		v.Add("effect", "invalid_value", "effect must be \"allow\" or \"deny\"")
+	}
+	if len(pr.Actions) == 0 {
+		v.Add("actions", "required", "actions is required")
+	}
+	for _, action := range pr.Actions {
+		if action == "" {
+			v.Add("actions", "invalid_value", "actions must not be empty")
+		}
+	}
+	if _, err := domain.ParseCondition(pr.Condition); err != nil {
+		v.Add("condition", "invalid_syntax", err.Error())
+	}
+}
+
+// policyResponse represents the JSON structure of a policy.
+type policyResponse struct {
+	ID          string    `json:"id"`
+	Description string    `json:"description,omitempty"`
+	Effect      string    `json:"effect"`
+	Actions     []string  `json:"actions"`
+	Condition   string    `json:"condition,omitempty"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// NewAdminPolicyApiAdapter creates a new AdminPolicyApi with the given use case port.
+//
+// Parameters:
+//   - managePoliciesPort: Port for listing, saving and deleting policies
+//
+// Returns:
+//   - *AdminPolicyApi: A pointer to the newly created AdminPolicyApi
+func NewAdminPolicyApiAdapter(managePoliciesPort usecases.ManagePoliciesPort) *AdminPolicyApi {
+	return &AdminPolicyApi{managePoliciesPort}
+}
+
+// InitAdminPolicyRoutes sets up the HTTP routes for policy management.
+//
+// All routes live under /admin/policies; access control is declared in RouteAccess.
+func (pa *AdminPolicyApi) InitAdminPolicyRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /admin/policies", pa.handleListPolicies)
+	mux.HandleFunc("PUT /admin/policies/{id}", pa.handleSavePolicy)
+	mux.HandleFunc("DELETE /admin/policies/{id}", pa.handleDeletePolicy)
+}
+
+// handleListPolicies handles HTTP GET requests for all policies.
+//
+// It responds with HTTP 200 OK and the policies sorted by id.
+func (pa *AdminPolicyApi) handleListPolicies(w http.ResponseWriter, r *http.Request) {
+	policies, err := pa.managePoliciesPort.ListPolicies(r.Context())
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	response := make([]policyResponse, 0, len(policies))
+	for _, policy := range policies {
+		response = append(response, toPolicyResponse(policy))
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+// handleSavePolicy handles HTTP PUT requests that create or replace a policy.
+//
+// The function expects a JSON body with an "effect" ("allow" or "deny"), a list of "actions"
+// (route patterns such as "GET /admin/users/{id}", optionally ending in "*") and an optional
+// "condition". On success, it responds with HTTP 200 OK and the stored policy. It responds with
+// 400 Bad Request for malformed ids and policies.
+func (pa *AdminPolicyApi) handleSavePolicy(w http.ResponseWriter, r *http.Request) {
+	id, ok := policyID(w, r)
+	if !ok {
+		return
+	}
+	var request policyRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	policy, err := pa.managePoliciesPort.SavePolicy(r.Context(), domain.Policy{
+		ID:          id,
+		Description: request.Description,
+		Effect:      domain.PolicyEffect(request.Effect),
+		Actions:     request.Actions,
+		Condition:   request.Condition,
+	})
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, toPolicyResponse(policy))
+}
+
+// handleDeletePolicy handles HTTP DELETE requests for a policy.
+//
+// On success, it responds with HTTP 204 No Content, or 404 Not Found if the policy does not exist.
+func (pa *AdminPolicyApi) handleDeletePolicy(w http.ResponseWriter, r *http.Request) {
+	id, ok := policyID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := pa.managePoliciesPort.DeletePolicy(r.Context(), id); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// policyID extracts the policy id from the path and answers malformed ids with 400 Bad Request.
+func policyID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id := r.PathValue("id")
+	if !domain.ValidPolicyID(id) {
+		problem.Write(w, r, problem.InvalidRequest, "policy ids consist of up to 64 lower case letters, digits, dashes and underscores")
+		return "", false
+	}
+	return id, true
+}
+
+// toPolicyResponse converts a policy into its JSON representation.
+func toPolicyResponse(policy domain.Policy) policyResponse {
+	return policyResponse{
+		ID:          policy.ID,
+		Description: policy.Description,
+		Effect:      string(policy.Effect),
+		Actions:     policy.Actions,
+		Condition:   policy.Condition,
+		UpdatedAt:   policy.UpdatedAt,
+	}
+}
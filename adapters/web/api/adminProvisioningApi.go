@@ -0,0 +1,193 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/adapters/web/validation"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// maxImportUsers is the maximum number of accounts created by a single import request.
+const maxImportUsers = 1000
+
+// AdminProvisioningApi handles HTTP requests for creating user accounts on behalf of administrators,
+// one at a time or many at once.
+// It acts as an adapter between the HTTP layer and the provisioning use cases.
+type AdminProvisioningApi struct {
+	provisionUsersPort usecases.ProvisionUsersPort
+	createUserPort     usecases.CreateUserPort
+}
+
+// createUserRequest represents the expected JSON structure for creating an account with a temporary password.
+type createUserRequest struct {
+	Username          string `json:"username"`
+	Email             string `json:"email"`
+	Role              string `json:"role"`
+	TemporaryPassword string `json:"temporaryPassword"`
+}
+
+// validate checks the format of the username and the optional email and that a temporary password is given.
+func (cr *createUserRequest) validate(v *validation.Validator) {
+	v.Username("username", cr.Username)
+	v.Email("email", cr.Email)
+	v.Required("temporaryPassword", cr.TemporaryPassword).MaxBytes("temporaryPassword", cr.TemporaryPassword, validation.MaxPasswordBytes)
+}
+
+// importRequest represents the expected JSON structure for user import requests.
+type importRequest struct {
+	Users []userSpecDTO `json:"users"`
+}
+
+// userSpecDTO represents the JSON structure of an account to import.
+type userSpecDTO struct {
+	Username          string `json:"username"`
+	Email             string `json:"email,omitempty"`
+	Role              string `json:"role,omitempty"`
+	TemporaryPassword string `json:"temporaryPassword,omitempty"`
+	Invite            bool   `json:"invite,omitempty"`
+}
+
+// validate checks that between one and maxImportUsers accounts are given; the accounts themselves
+// are validated one by one by the use case.
+func (ir *importRequest) validate(v *validation.Validator) {
+	switch {
+	case len(ir.Users) == 0:
+		v.Add("users", "required", "users is required")
+	case len(ir.Users) > maxImportUsers:
+		v.Add("users", "too_long", fmt.Sprintf("users must not contain more than %d entries", maxImportUsers))
+	}
+	for i, user := range ir.Users {
+		v.MaxBytes(fmt.Sprintf("users[%d].temporaryPassword", i), user.TemporaryPassword, validation.MaxPasswordBytes)
+	}
+}
+
+// importResponse represents the JSON structure of the report of an import.
+type importResponse struct {
+	Created int               `json:"created"`
+	Failed  int               `json:"failed"`
+	Results []importResultDTO `json:"results"`
+}
+
+// importResultDTO represents the JSON structure of the outcome of importing a single account.
+type importResultDTO struct {
+	Index    int             `json:"index"`
+	Username string          `json:"username"`
+	Status   string          `json:"status"`
+	ID       string          `json:"id,omitempty"`
+	Invited  bool            `json:"invited,omitempty"`
+	Error    *importErrorDTO `json:"error,omitempty"`
+}
+
+// importErrorDTO represents the JSON structure of the reason an account was not imported.
+type importErrorDTO struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// NewAdminProvisioningApiAdapter creates a new AdminProvisioningApi with the given use case ports.
+//
+// Parameters:
+//   - provisionUsersPort: Port for creating many accounts at once
+//   - createUserPort: Port for creating a single account with a temporary password
+//
+// Returns:
+//   - *AdminProvisioningApi: A pointer to the newly created AdminProvisioningApi
+func NewAdminProvisioningApiAdapter(provisionUsersPort usecases.ProvisionUsersPort, createUserPort usecases.CreateUserPort) *AdminProvisioningApi {
+	return &AdminProvisioningApi{provisionUsersPort, createUserPort}
+}
+
+// InitAdminProvisioningRoutes sets up the HTTP routes for creating and importing users.
+//
+// Access control is declared in RouteAccess.
+func (pa *AdminProvisioningApi) InitAdminProvisioningRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /admin/users", pa.handleCreateUser)
+	mux.HandleFunc("POST /admin/users/import", pa.handleImportUsers)
+}
+
+// handleCreateUser handles HTTP POST requests that create an account with a temporary password.
+//
+// The function expects a JSON body with a "username", an optional "email" and "role" (USER if
+// omitted) and the "temporaryPassword", which has to satisfy the password policy of the tenant.
+// The user has to replace it at the first login. On success, it responds with HTTP 201 Created and
+// the id of the created user, e.g. {"id": "66f1..."}. Invalid fields and weak passwords are answered
+// with 400 Bad Request, taken usernames and email addresses with 409 Conflict.
+func (pa *AdminProvisioningApi) handleCreateUser(w http.ResponseWriter, r *http.Request) {
+	var request createUserRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	userID, err := pa.createUserPort.CreateUser(r.Context(), usecases.UserSpec{
+		Username:          request.Username,
+		Email:             request.Email,
+		Role:              domain.Role(request.Role),
+		TemporaryPassword: request.TemporaryPassword,
+	})
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	writeResponse(w, r, http.StatusCreated, registerResponse{ID: userID})
+}
+
+// handleImportUsers handles HTTP POST requests that create many accounts at once.
+//
+// The function expects a JSON body with up to 1000 "users", each with a "username", an optional
+// "email" and "role" (USER if omitted), and either a "temporaryPassword" or "invite": true, e.g.
+//
+//	{"users": [{"username": "alice", "email": "alice@example.com", "invite": true}, {"username": "bob", "temporaryPassword": "..."}]}
+//
+// It responds with HTTP 200 OK and one result per account in the order of the request, even if some
+// or all accounts were rejected:
+//
+//	{"created": 1, "failed": 1, "results": [{"index": 0, "username": "alice", "status": "created", "id": "66f1...", "invited": true},
+//	 {"index": 1, "username": "bob", "status": "failed", "error": {"code": "WEAK_PASSWORD", "message": "password too weak: ..."}}]}
+//
+// An empty or too long list is answered with 400 Bad Request.
+func (pa *AdminProvisioningApi) handleImportUsers(w http.ResponseWriter, r *http.Request) {
+	var request importRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	specs := make([]usecases.UserSpec, 0, len(request.Users))
+	for _, user := range request.Users {
+		specs = append(specs, usecases.UserSpec{
+			Username:          user.Username,
+			Email:             user.Email,
+			Role:              domain.Role(user.Role),
+			TemporaryPassword: user.TemporaryPassword,
+			Invite:            user.Invite,
+		})
+	}
+	report, err := pa.provisionUsersPort.ProvisionUsers(r.Context(), specs)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	response := importResponse{Created: report.Created, Failed: report.Failed, Results: make([]importResultDTO, 0, len(report.Results))}
+	for _, result := range report.Results {
+		dto := importResultDTO{Index: result.Index, Username: result.Username, Status: "created", ID: result.UserID, Invited: result.Invited}
+		if result.Err != nil {
+			dto.Status, dto.Invited = "failed", false
+			dto.Error = importError(result.Err)
+		}
+		response.Results = append(response.Results, dto)
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+// importError converts the reason an account was not imported into its JSON structure. Unexpected
+// errors are reported without details.
+func importError(err error) *importErrorDTO {
+	if domainErr, ok := errorx.As(err); ok {
+		return &importErrorDTO{Code: string(domainErr.Code), Message: domainErr.Error()}
+	}
+	logger.Error("Error importing user", "error", err)
+	return &importErrorDTO{Code: string(problem.InternalError), Message: "internal error"}
+}
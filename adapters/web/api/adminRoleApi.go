@@ -0,0 +1,161 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"net/http"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/adapters/web/validation"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// AdminRoleApi handles HTTP requests for managing roles and the permissions they grant.
+// It acts as an adapter between the HTTP layer and the role use cases.
+type AdminRoleApi struct {
+	manageRolesPort usecases.ManageRolesPort
+}
+
+// rolePermissionsRequest represents the expected JSON structure for role definition requests.
+type rolePermissionsRequest struct {
+	Permissions []string `json:"permissions"`
+}
+
+// validate checks that every permission is named "<resource>:<action>".
+func (rr *rolePermissionsRequest) validate(v *validation.Validator) {
+	for _, permission := range rr.Permissions {
+		if !domain.Permission(permission).Valid() {
+			v.Add("permissions", "invalid_format", "permissions must be named like \"users:read\"")
+		}
+	}
+}
+
+// roleResponse represents the JSON structure of a role definition.
+type roleResponse struct {
+	Name        string     `json:"name"`
+	Permissions []string   `json:"permissions"`
+	BuiltIn     bool       `json:"builtIn"`
+	UpdatedAt   *time.Time `json:"updatedAt,omitempty"`
+}
+
+// NewAdminRoleApiAdapter creates a new AdminRoleApi with the given use case port.
+//
+// Parameters:
+//   - manageRolesPort: Port for listing, saving and deleting roles
+//
+// Returns:
+//   - *AdminRoleApi: A pointer to the newly created AdminRoleApi
+func NewAdminRoleApiAdapter(manageRolesPort usecases.ManageRolesPort) *AdminRoleApi {
+	return &AdminRoleApi{manageRolesPort}
+}
+
+// InitAdminRoleRoutes sets up the HTTP routes for role management.
+//
+// All routes live under /admin/roles; access control is declared in RouteAccess.
+func (ra *AdminRoleApi) InitAdminRoleRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /admin/roles", ra.handleListRoles)
+	mux.HandleFunc("PUT /admin/roles/{name}", ra.handleSaveRole)
+	mux.HandleFunc("DELETE /admin/roles/{name}", ra.handleDeleteRole)
+}
+
+// handleListRoles handles HTTP GET requests for all roles.
+//
+// It responds with HTTP 200 OK and the roles sorted by name.
+func (ra *AdminRoleApi) handleListRoles(w http.ResponseWriter, r *http.Request) {
+	definitions, err := ra.manageRolesPort.ListRoles(r.Context())
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	response := make([]roleResponse, 0, len(definitions))
+	for _, definition := range definitions {
+		response = append(response, toRoleResponse(definition))
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+// handleSaveRole handles HTTP PUT requests that create a role or replace its permissions.
+//
+// The function expects a JSON body with a "permissions" list, e.g. ["users:read"].
+// On success, it responds with HTTP 200 OK and the stored role. It responds with 400 Bad Request
+// for malformed role or permission names and 409 Conflict for the ADMIN role.
+func (ra *AdminRoleApi) handleSaveRole(w http.ResponseWriter, r *http.Request) {
+	name, ok := roleName(w, r)
+	if !ok {
+		return
+	}
+	var request rolePermissionsRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	permissions := make([]domain.Permission, 0, len(request.Permissions))
+	for _, permission := range request.Permissions {
+		permissions = append(permissions, domain.Permission(permission))
+	}
+	definition, err := ra.manageRolesPort.SaveRole(r.Context(), name, permissions)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, toRoleResponse(definition))
+}
+
+// handleDeleteRole handles HTTP DELETE requests for a role.
+//
+// On success, it responds with HTTP 204 No Content, with 404 Not Found if the role does not exist
+// and 409 Conflict for built-in roles.
+func (ra *AdminRoleApi) handleDeleteRole(w http.ResponseWriter, r *http.Request) {
+	name, ok := roleName(w, r)
+	if !ok {
+		return
+	}
+
+	if err := ra.manageRolesPort.DeleteRole(r.Context(), name); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// roleName extracts the role name from the path and answers malformed names with 400 Bad Request.
+func roleName(w http.ResponseWriter, r *http.Request) (domain.Role, bool) {
+	name := domain.Role(r.PathValue("name"))
+	if !name.Valid() {
+		problem.Write(w, r, problem.InvalidRequest, "role names consist of up to 32 upper case letters, digits and underscores")
+		return "", false
+	}
+	return name, true
+}
+
+// toRoleResponse converts a role definition into its JSON representation.
+func toRoleResponse(definition domain.RoleDefinition) roleResponse {
+	response := roleResponse{
+		Name:        string(definition.Name),
+		Permissions: permissionNames(definition.Permissions),
+		BuiltIn:     definition.BuiltIn,
+	}
+	if !definition.UpdatedAt.IsZero() {
+		response.UpdatedAt = &definition.UpdatedAt
+	}
+	return response
+}
+
+// roleNames converts roles into their names.
+func roleNames(roles []domain.Role) []string {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, string(role))
+	}
+	return names
+}
+
+// permissionNames converts permissions into their names.
+func permissionNames(permissions []domain.Permission) []string {
+	names := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		names = append(names, string(permission))
+	}
+	return names
+}
@@ -0,0 +1,135 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"net/http"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// AdminStatsApi handles HTTP requests for the statistics of the service.
+// It acts as an adapter between the HTTP layer and the statistics use case.
+type AdminStatsApi struct {
+	getAuthStatsPort usecases.GetAuthStatsPort
+}
+
+// authStatsResponse represents the JSON structure of the statistics of a period.
+type authStatsResponse struct {
+	From          string               `json:"from"`
+	To            string               `json:"to"`
+	Registrations []dailyCountResponse `json:"registrations"`
+	ActiveUsers   int64                `json:"activeUsers"`
+	Logins        loginStatsResponse   `json:"logins"`
+	Mfa           mfaAdoptionResponse  `json:"mfa"`
+}
+
+// dailyCountResponse represents the JSON structure of a count on a day.
+type dailyCountResponse struct {
+	Day   string `json:"day"`
+	Count int64  `json:"count"`
+}
+
+// loginStatsResponse represents the JSON structure of the aggregated login attempts.
+type loginStatsResponse struct {
+	Successes        int64            `json:"successes"`
+	Failures         int64            `json:"failures"`
+	SuccessRatio     float64          `json:"successRatio"`
+	FailuresByReason map[string]int64 `json:"failuresByReason"`
+}
+
+// mfaAdoptionResponse represents the JSON structure of the MFA adoption.
+type mfaAdoptionResponse struct {
+	Users    int64   `json:"users"`
+	Enabled  int64   `json:"enabled"`
+	Adoption float64 `json:"adoption"`
+}
+
+// NewAdminStatsApiAdapter creates a new AdminStatsApi with the given use case port.
+//
+// Parameters:
+//   - getAuthStatsPort: Port for aggregating the statistics
+//
+// Returns:
+//   - *AdminStatsApi: A pointer to the newly created AdminStatsApi
+func NewAdminStatsApiAdapter(getAuthStatsPort usecases.GetAuthStatsPort) *AdminStatsApi {
+	return &AdminStatsApi{getAuthStatsPort}
+}
+
+// InitAdminStatsRoutes sets up the HTTP routes for the statistics.
+//
+// Access control is declared in RouteAccess.
+func (sa *AdminStatsApi) InitAdminStatsRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /admin/stats", sa.handleGetStats)
+}
+
+// handleGetStats handles HTTP GET requests for the statistics of a period.
+//
+// Supported query parameters:
+//   - from: first day of the period as YYYY-MM-DD (default 30 days before to)
+//   - to: last day of the period as YYYY-MM-DD, inclusive (default today)
+//
+// It responds with HTTP 200 OK and the statistics, or 400 Bad Request for malformed parameters
+// and periods that are empty or longer than 366 days.
+func (sa *AdminStatsApi) handleGetStats(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	from, err := dateParam(query.Get("from"))
+	if err != nil {
+		problem.Write(w, r, problem.InvalidRequest, "from must be a date in the format YYYY-MM-DD")
+		return
+	}
+	var until time.Time
+	to, err := dateParam(query.Get("to"))
+	if err != nil {
+		problem.Write(w, r, problem.InvalidRequest, "to must be a date in the format YYYY-MM-DD")
+		return
+	}
+	if !to.IsZero() {
+		until = to.AddDate(0, 0, 1)
+	}
+
+	stats, err := sa.getAuthStatsPort.GetAuthStats(r.Context(), from, until)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, toAuthStatsResponse(stats))
+}
+
+// dateParam parses an optional YYYY-MM-DD query parameter, returning the zero time if it is empty.
+func dateParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
+// toAuthStatsResponse converts statistics into their JSON representation.
+func toAuthStatsResponse(stats domain.AuthStats) authStatsResponse {
+	registrations := make([]dailyCountResponse, 0, len(stats.Registrations))
+	for _, count := range stats.Registrations {
+		registrations = append(registrations, dailyCountResponse{Day: count.Day.Format(time.DateOnly), Count: count.Count})
+	}
+	failuresByReason := stats.Logins.FailuresByReason
+	if failuresByReason == nil {
+		failuresByReason = map[string]int64{}
+	}
+	return authStatsResponse{
+		From:          stats.Period.From.Format(time.DateOnly),
+		To:            stats.Period.Until.AddDate(0, 0, -1).Format(time.DateOnly),
+		Registrations: registrations,
+		ActiveUsers:   stats.Logins.ActiveUsers,
+		Logins: loginStatsResponse{
+			Successes:        stats.Logins.Successes,
+			Failures:         stats.Logins.Failures,
+			SuccessRatio:     stats.Logins.SuccessRatio(),
+			FailuresByReason: failuresByReason,
+		},
+		Mfa: mfaAdoptionResponse{
+			Users:    stats.Mfa.Users,
+			Enabled:  stats.Mfa.MfaEnabledUsers,
+			Adoption: stats.Mfa.Ratio(),
+		},
+	}
+}
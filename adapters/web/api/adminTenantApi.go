@@ -0,0 +1,195 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"net/http"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/adapters/web/validation"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// AdminTenantApi handles HTTP requests for managing tenants and their security settings.
+// It acts as an adapter between the HTTP layer and the tenant use cases.
+type AdminTenantApi struct {
+	manageTenantsPort usecases.ManageTenantsPort
+}
+
+// passwordPolicyDTO represents the JSON structure of a password policy.
+type passwordPolicyDTO struct {
+	MinLength     int  `json:"minLength"`
+	RequireUpper  bool `json:"requireUpper"`
+	RequireLower  bool `json:"requireLower"`
+	RequireDigit  bool `json:"requireDigit"`
+	RequireSymbol bool `json:"requireSymbol"`
+}
+
+// tenantRequest represents the expected JSON structure for tenant definition requests.
+// Settings that are omitted keep their default.
+type tenantRequest struct {
+	Name                  string             `json:"name"`
+	PasswordPolicy        *passwordPolicyDTO `json:"passwordPolicy"`
+	AccessTokenTTLSeconds int64              `json:"accessTokenTtlSeconds"`
+	RequireMFA            bool               `json:"requireMfa"`
+	LoginMethods          []string           `json:"loginMethods"`
+}
+
+// validate checks the name of the tenant. The bounds of the settings are checked by the core.
+func (tr *tenantRequest) validate(v *validation.Validator) {
+	v.Required("name", tr.Name).MaxBytes("name", tr.Name, 128)
+	if tr.AccessTokenTTLSeconds < 0 {
+		v.Add("accessTokenTtlSeconds", "invalid_value", "accessTokenTtlSeconds must not be negative")
+	}
+}
+
+// tenantResponse represents the JSON structure of a tenant.
+type tenantResponse struct {
+	ID                    string            `json:"id"`
+	Name                  string            `json:"name"`
+	PasswordPolicy        passwordPolicyDTO `json:"passwordPolicy"`
+	AccessTokenTTLSeconds int64             `json:"accessTokenTtlSeconds"`
+	RequireMFA            bool              `json:"requireMfa"`
+	LoginMethods          []string          `json:"loginMethods"`
+	UpdatedAt             *time.Time        `json:"updatedAt,omitempty"`
+}
+
+// NewAdminTenantApiAdapter creates a new AdminTenantApi with the given use case port.
+//
+// Parameters:
+//   - manageTenantsPort: Port for listing, saving and deleting tenants
+//
+// Returns:
+//   - *AdminTenantApi: A pointer to the newly created AdminTenantApi
+func NewAdminTenantApiAdapter(manageTenantsPort usecases.ManageTenantsPort) *AdminTenantApi {
+	return &AdminTenantApi{manageTenantsPort}
+}
+
+// InitAdminTenantRoutes sets up the HTTP routes for tenant management.
+//
+// All routes live under /admin/tenants; access control is declared in RouteAccess.
+func (ta *AdminTenantApi) InitAdminTenantRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /admin/tenants", ta.handleListTenants)
+	mux.HandleFunc("GET /admin/tenants/{id}", ta.handleGetTenant)
+	mux.HandleFunc("PUT /admin/tenants/{id}", ta.handleSaveTenant)
+	mux.HandleFunc("DELETE /admin/tenants/{id}", ta.handleDeleteTenant)
+}
+
+// handleListTenants handles HTTP GET requests for all tenants.
+//
+// It responds with HTTP 200 OK and the tenants sorted by id, including the default tenant.
+func (ta *AdminTenantApi) handleListTenants(w http.ResponseWriter, r *http.Request) {
+	tenants, err := ta.manageTenantsPort.ListTenants(r.Context())
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	response := make([]tenantResponse, 0, len(tenants))
+	for _, t := range tenants {
+		response = append(response, toTenantResponse(t))
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+// handleGetTenant handles HTTP GET requests for a single tenant.
+//
+// It responds with HTTP 200 OK and the tenant, or 404 Not Found if the tenant does not exist.
+func (ta *AdminTenantApi) handleGetTenant(w http.ResponseWriter, r *http.Request) {
+	id, ok := tenantID(w, r)
+	if !ok {
+		return
+	}
+
+	t, err := ta.manageTenantsPort.GetTenant(r.Context(), id)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, toTenantResponse(t))
+}
+
+// handleSaveTenant handles HTTP PUT requests that create or replace a tenant.
+//
+// The function expects a JSON body with a "name" and optionally a "passwordPolicy", an
+// "accessTokenTtlSeconds", "requireMfa" and the allowed "loginMethods" ("password", "session").
+// On success, it responds with HTTP 200 OK and the stored tenant. It responds with 400 Bad Request
+// for malformed ids and settings out of bounds.
+func (ta *AdminTenantApi) handleSaveTenant(w http.ResponseWriter, r *http.Request) {
+	id, ok := tenantID(w, r)
+	if !ok {
+		return
+	}
+	var request tenantRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	settings := domain.DefaultTenantSettings()
+	if request.PasswordPolicy != nil {
+		settings.PasswordPolicy = domain.PasswordPolicy(*request.PasswordPolicy)
+	}
+	if request.AccessTokenTTLSeconds != 0 {
+		settings.AccessTokenTTL = time.Duration(request.AccessTokenTTLSeconds) * time.Second
+	}
+	settings.RequireMFA = request.RequireMFA
+	if request.LoginMethods != nil {
+		settings.LoginMethods = make([]domain.LoginMethod, 0, len(request.LoginMethods))
+		for _, method := range request.LoginMethods {
+			settings.LoginMethods = append(settings.LoginMethods, domain.LoginMethod(method))
+		}
+	}
+
+	t, err := ta.manageTenantsPort.SaveTenant(r.Context(), domain.Tenant{ID: id, Name: request.Name, Settings: settings})
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, toTenantResponse(t))
+}
+
+// handleDeleteTenant handles HTTP DELETE requests for a tenant.
+//
+// On success, it responds with HTTP 204 No Content, or 404 Not Found if the tenant does not exist.
+// Deleting the default tenant resets its settings.
+func (ta *AdminTenantApi) handleDeleteTenant(w http.ResponseWriter, r *http.Request) {
+	id, ok := tenantID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := ta.manageTenantsPort.DeleteTenant(r.Context(), id); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// tenantID extracts the tenant id from the path and answers malformed ids with 400 Bad Request.
+func tenantID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id := r.PathValue("id")
+	if !domain.ValidTenantID(id) {
+		problem.Write(w, r, problem.InvalidRequest, "tenant ids consist of up to 64 lower case letters, digits, dashes and underscores")
+		return "", false
+	}
+	return id, true
+}
+
+// toTenantResponse converts a tenant into its JSON representation.
+func toTenantResponse(t domain.Tenant) tenantResponse {
+	response := tenantResponse{
+		ID:                    t.ID,
+		Name:                  t.Name,
+		PasswordPolicy:        passwordPolicyDTO(t.Settings.PasswordPolicy),
+		AccessTokenTTLSeconds: int64(t.Settings.AccessTokenTTL / time.Second),
+		RequireMFA:            t.Settings.RequireMFA,
+		LoginMethods:          make([]string, 0, len(t.Settings.LoginMethods)),
+	}
+	for _, method := range t.Settings.LoginMethods {
+		response.LoginMethods = append(response.LoginMethods, string(method))
+	}
+	if !t.UpdatedAt.IsZero() {
+		response.UpdatedAt = &t.UpdatedAt
+	}
+	return response
+}
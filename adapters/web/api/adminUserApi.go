@@ -0,0 +1,444 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/adapters/web/validation"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// defaultPageSize is used when a listing request does not specify a page size.
+const defaultPageSize = 20
+
+// AdminUserApi handles HTTP requests for administrative user management.
+// It acts as an adapter between the HTTP layer and the administrative use cases.
+type AdminUserApi struct {
+	listUsersPort        usecases.ListUsersPort
+	getUserPort          usecases.GetUserPort
+	searchUsersPort      usecases.SearchUsersPort
+	assignRolePort       usecases.AssignRolePort
+	renameUserPort       usecases.RenameUserPort
+	changeUserStatusPort usecases.ChangeUserStatusPort
+	deleteUserPort       usecases.DeleteUserPort
+	securityTimelinePort usecases.SecurityTimelinePort
+}
+
+// roleRequest represents the expected JSON structure for role assignment requests.
+// Either the list of roles or, as before users could hold several roles, a single role is given.
+type roleRequest struct {
+	Role  string   `json:"role,omitempty"`
+	Roles []string `json:"roles,omitempty"`
+}
+
+// validate checks that roles are given; whether they exist is decided by the use case.
+func (rr *roleRequest) validate(v *validation.Validator) {
+	if rr.Role == "" && len(rr.Roles) == 0 {
+		v.Add("roles", "required", "roles is required")
+	}
+}
+
+// roles returns the requested roles.
+func (rr *roleRequest) roles() []domain.Role {
+	roles := make([]domain.Role, 0, len(rr.Roles)+1)
+	if rr.Role != "" {
+		roles = append(roles, domain.Role(rr.Role))
+	}
+	for _, role := range rr.Roles {
+		roles = append(roles, domain.Role(role))
+	}
+	return roles
+}
+
+// usernameRequest represents the expected JSON structure for rename requests.
+type usernameRequest struct {
+	Username string `json:"username"`
+}
+
+// validate checks that a username is given; the username policy is enforced by the use case.
+func (ur *usernameRequest) validate(v *validation.Validator) {
+	v.Username("username", ur.Username)
+}
+
+// userListResponse represents the JSON structure of a page of users.
+type userListResponse struct {
+	Items    []userOverviewResponse `json:"items"`
+	Page     int64                  `json:"page"`
+	PageSize int64                  `json:"pageSize"`
+	Total    int64                  `json:"total"`
+}
+
+// userOverviewResponse represents the JSON structure of a user in a listing.
+type userOverviewResponse struct {
+	ID          string     `json:"id"`
+	Username    string     `json:"username"`
+	Status      string     `json:"status"`
+	Roles       []string   `json:"roles"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	LastLoginAt *time.Time `json:"lastLoginAt,omitempty"`
+	DeviceCount int        `json:"deviceCount"`
+	MfaEnabled  bool       `json:"mfaEnabled"`
+}
+
+// userSearchResponse represents the JSON structure of a page of search results.
+type userSearchResponse struct {
+	Items    []adminUserResponse `json:"items"`
+	Page     int64               `json:"page"`
+	PageSize int64               `json:"pageSize"`
+	Total    int64               `json:"total"`
+}
+
+// adminUserResponse represents the JSON structure of a single user.
+type adminUserResponse struct {
+	ID          string     `json:"id"`
+	Username    string     `json:"username"`
+	Email       string     `json:"email,omitempty"`
+	Roles       []string   `json:"roles"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	LastLoginAt *time.Time `json:"lastLoginAt,omitempty"`
+	MfaEnabled  bool       `json:"mfaEnabled"`
+	LockReason  string     `json:"lockReason,omitempty"`
+	LockedUntil *time.Time `json:"lockedUntil,omitempty"`
+}
+
+// NewAdminUserApiAdapter creates a new AdminUserApi with the given use case ports.
+//
+// Parameters:
+//   - listUsersPort: Port for listing users
+//   - getUserPort: Port for retrieving a single user
+//   - searchUsersPort: Port for searching users by username and email
+//   - assignRolePort: Port for role assignment
+//   - renameUserPort: Port for changing usernames
+//   - changeUserStatusPort: Port for disabling and enabling users
+//   - deleteUserPort: Port for deleting users
+//   - securityTimelinePort: Port for reconstructing a user's credential history
+//
+// Returns:
+//   - *AdminUserApi: A pointer to the newly created AdminUserApi
+func NewAdminUserApiAdapter(listUsersPort usecases.ListUsersPort, getUserPort usecases.GetUserPort, searchUsersPort usecases.SearchUsersPort, assignRolePort usecases.AssignRolePort, renameUserPort usecases.RenameUserPort, changeUserStatusPort usecases.ChangeUserStatusPort, deleteUserPort usecases.DeleteUserPort, securityTimelinePort usecases.SecurityTimelinePort) *AdminUserApi {
+	return &AdminUserApi{listUsersPort, getUserPort, searchUsersPort, assignRolePort, renameUserPort, changeUserStatusPort, deleteUserPort, securityTimelinePort}
+}
+
+// InitAdminUserRoutes sets up the HTTP routes for administrative user management.
+//
+// All routes live under /admin/users; access control is declared in RouteAccess.
+func (aa *AdminUserApi) InitAdminUserRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /admin/users", aa.handleListUsers)
+	mux.HandleFunc("GET /admin/users/search", aa.handleSearchUsers)
+	mux.HandleFunc("GET /admin/users/{id}", aa.handleGetUser)
+	mux.HandleFunc("PUT /admin/users/{id}/roles", aa.handleAssignRole)
+	mux.HandleFunc("PUT /admin/users/{id}/role", aa.handleAssignRole)
+	mux.HandleFunc("PUT /admin/users/{id}/roles/{role}", aa.handleGrantRole)
+	mux.HandleFunc("DELETE /admin/users/{id}/roles/{role}", aa.handleRevokeRole)
+	mux.HandleFunc("PUT /admin/users/{id}/username", aa.handleRenameUser)
+	mux.HandleFunc("POST /admin/users/{id}/disable", aa.handleDisableUser)
+	mux.HandleFunc("POST /admin/users/{id}/enable", aa.handleEnableUser)
+	mux.HandleFunc("DELETE /admin/users/{id}", aa.handleDeleteUser)
+	mux.HandleFunc("GET /admin/users/{id}/security-timeline", aa.handleSecurityTimeline)
+}
+
+// handleListUsers handles HTTP GET requests for a page of users.
+//
+// Supported query parameters:
+//   - page: 1-based page number (default 1)
+//   - pageSize: number of users per page (default 20, at most 100)
+//   - q: username prefix to search for
+//   - status: account status to filter by
+//   - role: role to filter by
+//   - createdAfter: RFC 3339 time from which on users have registered (inclusive)
+//   - createdBefore: RFC 3339 time until which users have registered (exclusive)
+//   - sort: field to sort by (username, createdAt, lastLoginAt, status), prefixed with "-" for descending order
+//
+// On success, it responds with HTTP 200 OK and the page as JSON.
+// On failure, it responds with an application/problem+json body and 400 Bad Request
+// for malformed paging parameters or 500 Internal Server Error if the users cannot be listed.
+func (aa *AdminUserApi) handleListUsers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	page, err := positiveIntParam(query.Get("page"), 1)
+	if err != nil {
+		problem.Write(w, r, problem.InvalidRequest, "page must be a positive integer")
+		return
+	}
+	pageSize, err := positiveIntParam(query.Get("pageSize"), defaultPageSize)
+	if err != nil {
+		problem.Write(w, r, problem.InvalidRequest, "pageSize must be a positive integer")
+		return
+	}
+
+	var specs []domain.UserSpecification
+	if q := query.Get("q"); q != "" {
+		specs = append(specs, domain.SearchTerm{Term: q})
+	}
+	if status := query.Get("status"); status != "" {
+		if !domain.AccountStatus(status).Valid() {
+			problem.Write(w, r, problem.InvalidRequest, "status must be one of PENDING, ACTIVE, LOCKED, DISABLED or DELETED")
+			return
+		}
+		specs = append(specs, domain.ByStatus{Status: domain.AccountStatus(status)})
+	}
+	if role := query.Get("role"); role != "" {
+		specs = append(specs, domain.ByRole{Role: domain.Role(role)})
+	}
+	createdAfter, err := timeParam(query.Get("createdAfter"))
+	if err != nil {
+		problem.Write(w, r, problem.InvalidRequest, "createdAfter must be an RFC 3339 time")
+		return
+	}
+	createdBefore, err := timeParam(query.Get("createdBefore"))
+	if err != nil {
+		problem.Write(w, r, problem.InvalidRequest, "createdBefore must be an RFC 3339 time")
+		return
+	}
+	if !createdAfter.IsZero() || !createdBefore.IsZero() {
+		specs = append(specs, domain.CreatedBetween{From: createdAfter, To: createdBefore})
+	}
+
+	sortBy, desc := strings.CutPrefix(query.Get("sort"), "-")
+	filter := domain.UserOverviewFilter{
+		Spec:   domain.And(specs...),
+		SortBy: sortBy,
+		Desc:   desc,
+		Offset: (page - 1) * pageSize,
+		Limit:  pageSize,
+	}
+
+	result, err := aa.listUsersPort.ListUsers(r.Context(), filter)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	response := userListResponse{Items: make([]userOverviewResponse, 0, len(result.Users)), Page: page, PageSize: pageSize, Total: result.Total}
+	for _, user := range result.Users {
+		item := userOverviewResponse{
+			ID:          user.ID,
+			Username:    user.Username,
+			Status:      user.Status,
+			Roles:       user.Roles,
+			CreatedAt:   user.CreatedAt,
+			DeviceCount: user.DeviceCount,
+			MfaEnabled:  user.MfaEnabled,
+		}
+		if !user.LastLoginAt.IsZero() {
+			lastLoginAt := user.LastLoginAt
+			item.LastLoginAt = &lastLoginAt
+		}
+		response.Items = append(response.Items, item)
+	}
+
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+// handleSearchUsers handles HTTP GET requests searching users by username and email.
+//
+// Supported query parameters:
+//   - q: text the username or email has to contain, ignoring case
+//   - page: 1-based page number (default 1)
+//   - pageSize: number of users per page (default 20, at most 100)
+//
+// On success, it responds with HTTP 200 OK and the page of users, sorted by username, as JSON.
+// On failure, it responds with an application/problem+json body and 400 Bad Request
+// for malformed paging parameters or 500 Internal Server Error if the users cannot be searched.
+func (aa *AdminUserApi) handleSearchUsers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	page, err := positiveIntParam(query.Get("page"), 1)
+	if err != nil {
+		problem.Write(w, r, problem.InvalidRequest, "page must be a positive integer")
+		return
+	}
+	pageSize, err := positiveIntParam(query.Get("pageSize"), defaultPageSize)
+	if err != nil {
+		problem.Write(w, r, problem.InvalidRequest, "pageSize must be a positive integer")
+		return
+	}
+
+	result, err := aa.searchUsersPort.SearchUsers(r.Context(), query.Get("q"), (page-1)*pageSize, pageSize)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	response := userSearchResponse{Items: make([]adminUserResponse, 0, len(result.Users)), Page: page, PageSize: pageSize, Total: result.Total}
+	for _, user := range result.Users {
+		response.Items = append(response.Items, toAdminUserResponse(user))
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+// handleGetUser handles HTTP GET requests for a single user.
+//
+// On success, it responds with HTTP 200 OK and the user as JSON,
+// with 404 Not Found if the user does not exist.
+func (aa *AdminUserApi) handleGetUser(w http.ResponseWriter, r *http.Request) {
+	user, err := aa.getUserPort.GetUser(r.Context(), r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, toAdminUserResponse(user))
+}
+
+// handleAssignRole handles HTTP PUT requests that replace the roles of a user.
+//
+// The function expects a JSON body with a "roles" list or, on the older /role route, a single "role".
+// On success, it responds with HTTP 204 No Content.
+// On failure, it responds with 400 Bad Request for invalid JSON or an unknown role,
+// 404 Not Found if the user does not exist, 409 Conflict if the roles would leave no
+// administrator, or 500 Internal Server Error.
+func (aa *AdminUserApi) handleAssignRole(w http.ResponseWriter, r *http.Request) {
+	var request roleRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	if err := aa.assignRolePort.AssignRoles(r.Context(), r.PathValue("id"), request.roles()); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleGrantRole handles HTTP PUT requests that add a single role to the roles of a user.
+//
+// On success, it responds with HTTP 204 No Content, also if the user held the role already.
+// On failure, it responds with 400 Bad Request for an unknown role or 404 Not Found if the user does not exist.
+func (aa *AdminUserApi) handleGrantRole(w http.ResponseWriter, r *http.Request) {
+	if err := aa.assignRolePort.GrantRole(r.Context(), r.PathValue("id"), domain.Role(r.PathValue("role"))); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleRevokeRole handles HTTP DELETE requests that remove a single role from the roles of a user.
+//
+// On success, it responds with HTTP 204 No Content, also if the user did not hold the role.
+// On failure, it responds with 404 Not Found if the user does not exist, or 409 Conflict if the
+// role is the only role of the user or the user is the last administrator.
+func (aa *AdminUserApi) handleRevokeRole(w http.ResponseWriter, r *http.Request) {
+	if err := aa.assignRolePort.RevokeRole(r.Context(), r.PathValue("id"), domain.Role(r.PathValue("role"))); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleRenameUser handles HTTP PUT requests that change the username of a user.
+//
+// The function expects a JSON body with the new "username". On success, it responds with HTTP 204
+// No Content. On failure, it responds with 400 Bad Request if the username violates the username
+// policy, 404 Not Found if the user does not exist, or 409 Conflict if the username is taken.
+func (aa *AdminUserApi) handleRenameUser(w http.ResponseWriter, r *http.Request) {
+	var request usernameRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	if err := aa.renameUserPort.RenameUser(r.Context(), r.PathValue("id"), request.Username); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleDisableUser handles HTTP POST requests that disable a user.
+//
+// On success, it responds with HTTP 204 No Content, with 404 Not Found if the user does not exist.
+func (aa *AdminUserApi) handleDisableUser(w http.ResponseWriter, r *http.Request) {
+	if err := aa.changeUserStatusPort.DisableUser(r.Context(), r.PathValue("id")); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleEnableUser handles HTTP POST requests that enable a user.
+//
+// On success, it responds with HTTP 204 No Content, with 404 Not Found if the user does not exist.
+func (aa *AdminUserApi) handleEnableUser(w http.ResponseWriter, r *http.Request) {
+	if err := aa.changeUserStatusPort.EnableUser(r.Context(), r.PathValue("id")); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleDeleteUser handles HTTP DELETE requests for a user.
+//
+// On success, it responds with HTTP 204 No Content, with 404 Not Found if the user does not exist.
+func (aa *AdminUserApi) handleDeleteUser(w http.ResponseWriter, r *http.Request) {
+	if err := aa.deleteUserPort.DeleteUser(r.Context(), r.PathValue("id")); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleSecurityTimeline handles HTTP GET requests for the credential history of a user.
+//
+// On success, it responds with HTTP 200 OK and the replayed timeline as JSON,
+// with 404 Not Found if the user does not exist.
+func (aa *AdminUserApi) handleSecurityTimeline(w http.ResponseWriter, r *http.Request) {
+	user, err := aa.getUserPort.GetUser(r.Context(), r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	timeline, err := aa.securityTimelinePort.GetSecurityTimeline(r.Context(), user.Username)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, timeline)
+}
+
+// toAdminUserResponse converts a user view into its JSON representation.
+func toAdminUserResponse(user usecases.UserView) adminUserResponse {
+	response := adminUserResponse{
+		ID:         user.ID,
+		Username:   user.Username,
+		Email:      user.Email,
+		Roles:      roleNames(user.Roles),
+		Status:     string(user.Status),
+		CreatedAt:  user.CreatedAt,
+		MfaEnabled: user.MfaEnabled,
+		LockReason: string(user.LockReason),
+	}
+	if !user.LastLoginAt.IsZero() {
+		response.LastLoginAt = &user.LastLoginAt
+	}
+	if !user.LockedUntil.IsZero() {
+		response.LockedUntil = &user.LockedUntil
+	}
+	return response
+}
+
+// positiveIntParam parses an optional positive integer query parameter.
+func positiveIntParam(value string, fallback int64) (int64, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 1 {
+		return 0, errors.New("not a positive integer")
+	}
+	return n, nil
+}
+
+// timeParam parses an optional RFC 3339 time query parameter; an empty value yields the zero time.
+func timeParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
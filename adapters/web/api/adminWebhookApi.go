@@ -0,0 +1,174 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"net/http"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/adapters/web/validation"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// AdminWebhookApi handles HTTP requests for managing outbound webhooks.
+// It acts as an adapter between the HTTP layer and the webhook use cases.
+type AdminWebhookApi struct {
+	manageWebhooksPort  usecases.ManageWebhooksPort
+	listDeadLettersPort usecases.ListWebhookDeadLettersPort
+}
+
+// webhookRequest represents the expected JSON structure for webhook subscription requests.
+type webhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+}
+
+// validate checks the endpoint URL and that no event name is empty.
+func (wr *webhookRequest) validate(v *validation.Validator) {
+	v.URL("url", wr.URL)
+	for _, event := range wr.Events {
+		v.Required("events", event)
+	}
+}
+
+// webhookResponse represents the JSON structure of a webhook subscription.
+// The secret is only present in the response to the creation.
+type webhookResponse struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// deadLetterResponse represents the JSON structure of a failed webhook delivery.
+type deadLetterResponse struct {
+	ID             string    `json:"id"`
+	SubscriptionID string    `json:"subscriptionId"`
+	URL            string    `json:"url"`
+	Event          string    `json:"event"`
+	Payload        string    `json:"payload"`
+	Attempts       int       `json:"attempts"`
+	LastError      string    `json:"lastError"`
+	FailedAt       time.Time `json:"failedAt"`
+}
+
+// NewAdminWebhookApiAdapter creates a new AdminWebhookApi with the given use case ports.
+//
+// Parameters:
+//   - manageWebhooksPort: Port for creating, listing and deleting subscriptions
+//   - listDeadLettersPort: Port for listing failed deliveries
+//
+// Returns:
+//   - *AdminWebhookApi: A pointer to the newly created AdminWebhookApi
+func NewAdminWebhookApiAdapter(manageWebhooksPort usecases.ManageWebhooksPort, listDeadLettersPort usecases.ListWebhookDeadLettersPort) *AdminWebhookApi {
+	return &AdminWebhookApi{manageWebhooksPort, listDeadLettersPort}
+}
+
+// InitAdminWebhookRoutes sets up the HTTP routes for webhook management.
+//
+// All routes live under /admin/webhooks; access control is declared in RouteAccess.
+func (wa *AdminWebhookApi) InitAdminWebhookRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /admin/webhooks", wa.handleCreateWebhook)
+	mux.HandleFunc("GET /admin/webhooks", wa.handleListWebhooks)
+	mux.HandleFunc("DELETE /admin/webhooks/{id}", wa.handleDeleteWebhook)
+	mux.HandleFunc("GET /admin/webhooks/dead-letters", wa.handleListDeadLetters)
+}
+
+// handleCreateWebhook handles HTTP POST requests that subscribe an endpoint to events.
+//
+// The function expects a JSON body with a "url" and an optional "events" list, e.g.
+// ["user.registered", "user.status_changed"]; an empty list or "*" subscribes to all events.
+// On success, it responds with HTTP 201 Created and the subscription including the signing
+// secret, which is not returned again. Invalid fields are answered with 400 Bad Request.
+func (wa *AdminWebhookApi) handleCreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var request webhookRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	subscription, err := wa.manageWebhooksPort.CreateWebhook(r.Context(), request.URL, request.Events)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeResponse(w, r, http.StatusCreated, toWebhookResponse(subscription))
+}
+
+// handleListWebhooks handles HTTP GET requests for all subscriptions.
+//
+// It responds with HTTP 200 OK and the subscriptions without their secrets.
+func (wa *AdminWebhookApi) handleListWebhooks(w http.ResponseWriter, r *http.Request) {
+	subscriptions, err := wa.manageWebhooksPort.ListWebhooks(r.Context())
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	response := make([]webhookResponse, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		response = append(response, toWebhookResponse(subscription))
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+// handleDeleteWebhook handles HTTP DELETE requests for a subscription.
+//
+// On success, it responds with HTTP 204 No Content, with 404 Not Found if the subscription does not exist.
+func (wa *AdminWebhookApi) handleDeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if err := wa.manageWebhooksPort.DeleteWebhook(r.Context(), r.PathValue("id")); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleListDeadLetters handles HTTP GET requests for the deliveries that were given up.
+//
+// The optional "limit" query parameter bounds the number of entries (default and maximum 100).
+// It responds with HTTP 200 OK and the dead letters, newest first, each with the payload that was sent.
+func (wa *AdminWebhookApi) handleListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	limit, err := positiveIntParam(r.URL.Query().Get("limit"), 0)
+	if err != nil {
+		problem.Write(w, r, problem.InvalidRequest, "limit must be a positive integer")
+		return
+	}
+
+	deadLetters, err := wa.listDeadLettersPort.ListWebhookDeadLetters(r.Context(), limit)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	response := make([]deadLetterResponse, 0, len(deadLetters))
+	for _, deadLetter := range deadLetters {
+		response = append(response, deadLetterResponse{
+			ID:             deadLetter.ID,
+			SubscriptionID: deadLetter.SubscriptionID,
+			URL:            deadLetter.URL,
+			Event:          deadLetter.EventName,
+			Payload:        string(deadLetter.Payload),
+			Attempts:       deadLetter.Attempts,
+			LastError:      deadLetter.LastError,
+			FailedAt:       deadLetter.FailedAt,
+		})
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+// toWebhookResponse converts a subscription into its JSON representation.
+func toWebhookResponse(subscription domain.WebhookSubscription) webhookResponse {
+	eventNames := subscription.Events
+	if len(eventNames) == 0 {
+		eventNames = []string{domain.WebhookAllEvents}
+	}
+	return webhookResponse{
+		ID:        subscription.ID,
+		URL:       subscription.URL,
+		Secret:    subscription.Secret,
+		Events:    eventNames,
+		CreatedAt: subscription.CreatedAt,
+	}
+}
@@ -0,0 +1,24 @@
+package api
+
+import (
+	"user-auth-hexagonal-architecture/adapters/web/middleware"
+)
+
+// CacheableRoutes declares the read routes of this package whose responses carry an ETag, so
+// clients and gateways polling them can revalidate with If-None-Match instead of refetching.
+var CacheableRoutes = middleware.CacheableRoutes{
+	"GET /user/me":                            true,
+	"GET /user/me/profile":                    true,
+	"GET /users/{username}/profile":           true,
+	"GET /admin/users":                        true,
+	"GET /admin/users/search":                 true,
+	"GET /admin/users/{id}":                   true,
+	"GET /admin/users/{id}/security-timeline": true,
+	"GET /admin/users/{id}/sessions":          true,
+	"GET /user/sessions":                      true,
+	"GET /admin/stats":                        true,
+	"GET /capabilities":                       true,
+
+	"GET /.well-known/oauth-authorization-server": true,
+	"GET /.well-known/jwks.json":                  true,
+}
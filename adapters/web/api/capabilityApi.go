@@ -0,0 +1,94 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"net/http"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/internal/buildinfo"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// CapabilityApi serves the capabilities of the deployment, so clients and operators can find out
+// programmatically which login and MFA methods and features are available.
+type CapabilityApi struct {
+	describeCapabilitiesPort usecases.DescribeCapabilitiesPort
+}
+
+// capabilitiesResponse represents the JSON structure of the capabilities.
+type capabilitiesResponse struct {
+	Build                  buildinfo.Info              `json:"build"`
+	Tenant                 string                      `json:"tenant"`
+	Registration           bool                        `json:"registration"`
+	LoginMethods           []domain.LoginMethod        `json:"loginMethods"`
+	MfaMethods             []domain.MfaMethod          `json:"mfaMethods"`
+	MfaRequired            bool                        `json:"mfaRequired"`
+	SocialProviders        []string                    `json:"socialProviders"`
+	TokenSigningAlgorithms []string                    `json:"tokenSigningAlgorithms"`
+	AccessTokenTTLSeconds  int                         `json:"accessTokenTtlSeconds"`
+	PasswordPolicy         passwordPolicyResponse      `json:"passwordPolicy"`
+	Features               map[domain.FeatureFlag]bool `json:"features"`
+}
+
+// passwordPolicyResponse represents the JSON structure of the password policy new passwords have to satisfy.
+type passwordPolicyResponse struct {
+	MinLength     int  `json:"minLength"`
+	RequireUpper  bool `json:"requireUpper"`
+	RequireLower  bool `json:"requireLower"`
+	RequireDigit  bool `json:"requireDigit"`
+	RequireSymbol bool `json:"requireSymbol"`
+}
+
+// NewCapabilityApiAdapter creates a new CapabilityApi with the given use case port.
+//
+// Parameters:
+//   - describeCapabilitiesPort: Port for describing the capabilities of the deployment
+//
+// Returns:
+//   - *CapabilityApi: A pointer to the newly created CapabilityApi
+func NewCapabilityApiAdapter(describeCapabilitiesPort usecases.DescribeCapabilitiesPort) *CapabilityApi {
+	return &CapabilityApi{describeCapabilitiesPort}
+}
+
+// InitCapabilityRoutes sets up the HTTP route of the capabilities.
+//
+// Access control is declared in RouteAccess.
+func (ca *CapabilityApi) InitCapabilityRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /capabilities", ca.handleCapabilities)
+}
+
+// handleCapabilities handles HTTP GET requests for the capabilities.
+//
+// The capabilities apply to the tenant of the request, as login methods, the MFA requirement, the
+// password policy and feature flags can differ per tenant.
+// On success, it responds with HTTP 200 OK, the build information and the capabilities.
+// On failure, it responds with an application/problem+json body and 404 Not Found if the tenant
+// of the request does not exist.
+func (ca *CapabilityApi) handleCapabilities(w http.ResponseWriter, r *http.Request) {
+	capabilities, err := ca.describeCapabilitiesPort.DescribeCapabilities(r.Context())
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	policy := capabilities.PasswordPolicy
+	writeResponse(w, r, http.StatusOK, capabilitiesResponse{
+		Build:                  buildinfo.Get(),
+		Tenant:                 capabilities.TenantID,
+		Registration:           capabilities.Registration,
+		LoginMethods:           capabilities.LoginMethods,
+		MfaMethods:             capabilities.MfaMethods,
+		MfaRequired:            capabilities.MfaRequired,
+		SocialProviders:        capabilities.SocialProviders,
+		TokenSigningAlgorithms: capabilities.TokenSigningAlgorithms,
+		AccessTokenTTLSeconds:  int(capabilities.AccessTokenTTL.Seconds()),
+		PasswordPolicy: passwordPolicyResponse{
+			MinLength:     policy.MinLength,
+			RequireUpper:  policy.RequireUpper,
+			RequireLower:  policy.RequireLower,
+			RequireDigit:  policy.RequireDigit,
+			RequireSymbol: policy.RequireSymbol,
+		},
+		Features: capabilities.Features,
+	})
+}
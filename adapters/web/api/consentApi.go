@@ -0,0 +1,212 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/middleware"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/adapters/web/validation"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// ConsentApi handles HTTP requests for the terms and privacy policy and their acceptance by users.
+// It acts as an adapter between the HTTP layer and the consent use cases.
+type ConsentApi struct {
+	consentDocumentsPort usecases.ConsentDocumentsPort
+	userConsentPort      usecases.UserConsentPort
+}
+
+// consentDTO represents the JSON structure of an accepted document version.
+type consentDTO struct {
+	Document string `json:"document"`
+	Version  string `json:"version"`
+}
+
+// acceptConsentsRequest represents the expected JSON structure for accepting document versions.
+type acceptConsentsRequest struct {
+	Consents []consentDTO `json:"consents"`
+}
+
+// validate checks that at least one complete document version is accepted.
+func (ar *acceptConsentsRequest) validate(v *validation.Validator) {
+	if len(ar.Consents) == 0 {
+		v.Add("consents", "required", "consents is required")
+	}
+	validateConsents(v, ar.Consents)
+}
+
+// publishDocumentRequest represents the expected JSON structure for publishing a document version.
+type publishDocumentRequest struct {
+	Document string `json:"document"`
+	Version  string `json:"version"`
+}
+
+// validate checks presence; the format of the version is checked by the core.
+func (pr *publishDocumentRequest) validate(v *validation.Validator) {
+	v.Required("document", pr.Document)
+	v.Required("version", pr.Version)
+}
+
+// documentVersionResponse represents the JSON structure of a published document version.
+type documentVersionResponse struct {
+	Document    string    `json:"document"`
+	Version     string    `json:"version"`
+	PublishedAt time.Time `json:"publishedAt"`
+}
+
+// consentResponse represents the JSON structure of a recorded consent.
+type consentResponse struct {
+	Document   string    `json:"document"`
+	Version    string    `json:"version"`
+	AcceptedAt time.Time `json:"acceptedAt"`
+	IPAddress  string    `json:"ipAddress,omitempty"`
+}
+
+// consentStatusResponse represents the JSON structure of the consent history of a user.
+type consentStatusResponse struct {
+	Consents    []consentResponse         `json:"consents"`
+	Outstanding []documentVersionResponse `json:"outstanding"`
+}
+
+// NewConsentApiAdapter creates a new ConsentApi with the given use case ports.
+//
+// Parameters:
+//   - consentDocumentsPort: Port for reading and publishing document versions
+//   - userConsentPort: Port for reading and giving the consents of the authenticated user
+//
+// Returns:
+//   - *ConsentApi: A pointer to the newly created ConsentApi
+func NewConsentApiAdapter(consentDocumentsPort usecases.ConsentDocumentsPort, userConsentPort usecases.UserConsentPort) *ConsentApi {
+	return &ConsentApi{consentDocumentsPort, userConsentPort}
+}
+
+// InitConsentRoutes sets up the HTTP routes for document versions and consents.
+//
+// Access control is declared in RouteAccess.
+func (ca *ConsentApi) InitConsentRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /consent-documents", ca.handleListDocuments)
+	mux.HandleFunc("GET /user/consents", ca.handleGetConsents)
+	mux.HandleFunc("POST /user/consents", ca.handleAcceptConsents)
+	mux.HandleFunc("POST /admin/consent-documents", ca.handlePublishDocument)
+}
+
+// handleListDocuments handles HTTP GET requests for the current document versions, which clients
+// show before registration and send back as accepted "consents".
+//
+// It responds with HTTP 200 OK and the current versions, an empty list if none have been published.
+func (ca *ConsentApi) handleListDocuments(w http.ResponseWriter, r *http.Request) {
+	current, err := ca.consentDocumentsPort.CurrentDocumentVersions(r.Context())
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	response := make([]documentVersionResponse, 0, len(current))
+	for _, version := range current {
+		response = append(response, toDocumentVersionResponse(version))
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+// handleGetConsents handles HTTP GET requests for the consent history of the authenticated user.
+//
+// It responds with HTTP 200 OK, the consents, the most recent first, and the "outstanding" document
+// versions the user still has to accept.
+func (ca *ConsentApi) handleGetConsents(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.AuthenticationRequired, "")
+		return
+	}
+
+	status, err := ca.userConsentPort.GetConsents(r.Context(), principal.Subject)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	response := consentStatusResponse{
+		Consents:    make([]consentResponse, 0, len(status.Consents)),
+		Outstanding: make([]documentVersionResponse, 0, len(status.Outstanding)),
+	}
+	for _, consent := range status.Consents {
+		response.Consents = append(response.Consents, consentResponse{
+			Document:   string(consent.Document),
+			Version:    consent.Version,
+			AcceptedAt: consent.AcceptedAt,
+			IPAddress:  consent.IPAddress,
+		})
+	}
+	for _, version := range status.Outstanding {
+		response.Outstanding = append(response.Outstanding, toDocumentVersionResponse(version))
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+// handleAcceptConsents handles HTTP POST requests in which the authenticated user accepts document versions.
+//
+// The function expects a JSON body like {"consents": [{"document": "terms", "version": "2026-10"}]}.
+// On success, it responds with HTTP 204 No Content. It responds with 400 Bad Request if a version
+// is not the current one.
+func (ca *ConsentApi) handleAcceptConsents(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.AuthenticationRequired, "")
+		return
+	}
+	var request acceptConsentsRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	if err := ca.userConsentPort.AcceptConsents(deviceContext(r), principal.Subject, toConsentRefs(request.Consents)); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlePublishDocument handles HTTP POST requests publishing a new version of a document.
+//
+// The function expects a JSON body like {"document": "terms", "version": "2026-10"}. On success, it
+// responds with HTTP 201 Created and the published version; from then on every user has to accept it
+// at the next login. It responds with 400 Bad Request for unknown documents and versions that are
+// malformed or have been published before.
+func (ca *ConsentApi) handlePublishDocument(w http.ResponseWriter, r *http.Request) {
+	var request publishDocumentRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	published, err := ca.consentDocumentsPort.PublishDocumentVersion(r.Context(), domain.ConsentDocument(request.Document), request.Version)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	writeResponse(w, r, http.StatusCreated, toDocumentVersionResponse(published))
+}
+
+// validateConsents checks that every accepted document version names a document and a version.
+func validateConsents(v *validation.Validator, consents []consentDTO) {
+	for i, consent := range consents {
+		v.Required(fmt.Sprintf("consents[%d].document", i), consent.Document)
+		v.Required(fmt.Sprintf("consents[%d].version", i), consent.Version)
+	}
+}
+
+// toConsentRefs converts accepted document versions into their domain representation.
+func toConsentRefs(consents []consentDTO) []domain.ConsentRef {
+	refs := make([]domain.ConsentRef, 0, len(consents))
+	for _, consent := range consents {
+		refs = append(refs, domain.ConsentRef{Document: domain.ConsentDocument(consent.Document), Version: consent.Version})
+	}
+	return refs
+}
+
+// toDocumentVersionResponse converts a document version into its JSON representation.
+func toDocumentVersionResponse(version domain.DocumentVersion) documentVersionResponse {
+	return documentVersionResponse{Document: string(version.Document), Version: version.Version, PublishedAt: version.PublishedAt}
+}
@@ -0,0 +1,136 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/middleware"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// DataExportApi handles HTTP requests for exports of the data stored about the authenticated user.
+// It acts as an adapter between the HTTP layer and the data export use cases.
+type DataExportApi struct {
+	exportUserDataPort     usecases.ExportUserDataPort
+	downloadDataExportPort usecases.DownloadDataExportPort
+}
+
+// dataExportResponse represents the JSON structure of a data export. The download URL is only
+// present once the archive is ready and stays valid until the export expires.
+type dataExportResponse struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	RequestedAt time.Time  `json:"requestedAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	ExpiresAt   time.Time  `json:"expiresAt"`
+	DownloadURL string     `json:"downloadUrl,omitempty"`
+}
+
+// NewDataExportApiAdapter creates a new DataExportApi with the given use case ports.
+//
+// Parameters:
+//   - exportUserDataPort: Port for requesting and polling data exports
+//   - downloadDataExportPort: Port for downloading the archive of a data export
+//
+// Returns:
+//   - *DataExportApi: A pointer to the newly created DataExportApi
+func NewDataExportApiAdapter(exportUserDataPort usecases.ExportUserDataPort, downloadDataExportPort usecases.DownloadDataExportPort) *DataExportApi {
+	return &DataExportApi{exportUserDataPort, downloadDataExportPort}
+}
+
+// InitDataExportRoutes sets up the HTTP routes for data exports.
+//
+// Access control is declared in RouteAccess; the archive route is public, as it is authorized by
+// the signed token of its download URL.
+func (ea *DataExportApi) InitDataExportRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /user/data-exports", ea.handleRequestExport)
+	mux.HandleFunc("GET /user/data-exports/{id}", ea.handleGetExport)
+	mux.HandleFunc("GET /user/data-exports/{id}/archive", ea.handleDownloadArchive)
+}
+
+// handleRequestExport handles HTTP POST requests for an export of the data of the authenticated user.
+//
+// It responds with HTTP 202 Accepted, the pending export and its URL in the Location header, which
+// is polled until the status is READY and the response carries the "downloadUrl".
+func (ea *DataExportApi) handleRequestExport(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.AuthenticationRequired, "")
+		return
+	}
+
+	export, err := ea.exportUserDataPort.ExportUserData(r.Context(), principal.Subject)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	location := r.URL.Path + "/" + export.ID
+	w.Header().Set("Location", location)
+	writeResponse(w, r, http.StatusAccepted, toDataExportResponse(export, location))
+}
+
+// handleGetExport handles HTTP GET requests for a data export of the authenticated user.
+//
+// It responds with HTTP 200 OK and the export, or 404 Not Found if the user has no such export
+// or it has expired.
+func (ea *DataExportApi) handleGetExport(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.AuthenticationRequired, "")
+		return
+	}
+
+	export, err := ea.exportUserDataPort.GetDataExport(r.Context(), principal.Subject, r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeResponse(w, r, http.StatusOK, toDataExportResponse(export, r.URL.Path))
+}
+
+// handleDownloadArchive handles HTTP GET requests for the archive of a data export.
+//
+// The request is authorized by the "token" query parameter of the download URL. On success, it
+// responds with HTTP 200 OK and the ZIP archive as attachment. It responds with 403 Forbidden for
+// invalid or expired links and 409 Conflict if the archive is not ready.
+func (ea *DataExportApi) handleDownloadArchive(w http.ResponseWriter, r *http.Request) {
+	archive, err := ea.downloadDataExportPort.DownloadDataExport(r.Context(), r.URL.Query().Get("token"))
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="data-export.zip"`)
+	w.Header().Set("Content-Length", strconv.Itoa(len(archive)))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(archive); err != nil {
+		logger.ErrorContext(r.Context(), "Error writing data export", "error", err)
+	}
+}
+
+// toDataExportResponse converts a data export into its JSON representation. location is the path
+// of the export, from which the download URL is derived.
+func toDataExportResponse(export usecases.DataExportView, location string) dataExportResponse {
+	response := dataExportResponse{
+		ID:          export.ID,
+		Status:      string(export.Status),
+		RequestedAt: export.RequestedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+	if export.Status != domain.DataExportPending {
+		response.CompletedAt = &export.CompletedAt
+	}
+	if export.DownloadToken != "" {
+		response.DownloadURL = location + "/archive?token=" + url.QueryEscape(export.DownloadToken)
+	}
+	return response
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// maxEmailFeedbackBytes bounds the size of email provider callbacks, which carry batches of events.
+const maxEmailFeedbackBytes = 1 << 20
+
+// EmailFeedbackApi handles the callbacks email providers report bounces and complaints through.
+// It acts as an adapter between the HTTP layer and the email suppression use cases.
+type EmailFeedbackApi struct {
+	recordEmailFeedbackPort usecases.RecordEmailFeedbackPort
+}
+
+// NewEmailFeedbackApiAdapter creates a new EmailFeedbackApi with the given use case port.
+//
+// Parameters:
+//   - recordEmailFeedbackPort: Port for recording the feedback of the providers
+//
+// Returns:
+//   - *EmailFeedbackApi: A pointer to the newly created EmailFeedbackApi
+func NewEmailFeedbackApiAdapter(recordEmailFeedbackPort usecases.RecordEmailFeedbackPort) *EmailFeedbackApi {
+	return &EmailFeedbackApi{recordEmailFeedbackPort}
+}
+
+// InitEmailFeedbackRoutes sets up the HTTP route for email provider callbacks.
+//
+// The route is public, the callbacks are authenticated by the signatures of the providers.
+func (fa *EmailFeedbackApi) InitEmailFeedbackRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /email-feedback/{provider}", fa.handleEmailFeedback)
+}
+
+// handleEmailFeedback handles HTTP POST requests of the callbacks of an email provider, e.g.
+// /email-feedback/sendgrid for the Event Webhook of SendGrid.
+//
+// The body is passed on unparsed, as the signatures cover the exact bytes. On success, it responds
+// with HTTP 204 No Content. Callbacks with a missing or wrong signature, or of a provider whose
+// feedback is not configured, are answered with 400 Bad Request.
+func (fa *EmailFeedbackApi) handleEmailFeedback(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxEmailFeedbackBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			problem.Write(w, r, problem.PayloadTooLarge, fmt.Sprintf("The request body must not exceed %d bytes", maxBytesErr.Limit))
+			return
+		}
+		problem.Write(w, r, problem.InvalidRequest, "Failed to read the request body")
+		return
+	}
+
+	if err := fa.recordEmailFeedbackPort.RecordEmailFeedback(r.Context(), r.PathValue("provider"), r.Header, body); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
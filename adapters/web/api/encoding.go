@@ -0,0 +1,171 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/vmihailenco/msgpack/v5"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+)
+
+// encoder writes response bodies in one media type.
+type encoder interface {
+	// ContentType returns the media type of the encoded bodies.
+	ContentType() string
+	// Encode writes v to w.
+	Encode(w io.Writer, v any) error
+}
+
+// jsonEncoder encodes responses as JSON, the default media type.
+type jsonEncoder struct{}
+
+// ContentType returns "application/json".
+func (jsonEncoder) ContentType() string { return "application/json" }
+
+// Encode writes v as JSON.
+func (jsonEncoder) Encode(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+// xmlEncoder encodes responses as XML for consumers that cannot process JSON.
+//
+// The document mirrors the JSON representation: the root element is <response>, object members
+// become elements named like the JSON fields and array entries become <item> elements.
+type xmlEncoder struct{}
+
+// ContentType returns "application/xml".
+func (xmlEncoder) ContentType() string { return "application/xml" }
+
+// Encode writes v as XML.
+func (xmlEncoder) Encode(w io.Writer, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := jsonToXML(decoder, enc, "response"); err != nil {
+		return err
+	}
+	return enc.Flush()
+}
+
+// msgpackEncoder encodes responses as MessagePack, using the JSON field names as keys.
+type msgpackEncoder struct{}
+
+// ContentType returns "application/msgpack".
+func (msgpackEncoder) ContentType() string { return "application/msgpack" }
+
+// Encode writes v as MessagePack.
+func (msgpackEncoder) Encode(w io.Writer, v any) error {
+	enc := msgpack.NewEncoder(w)
+	enc.SetCustomStructTag("json")
+	return enc.Encode(v)
+}
+
+// encoders maps every supported media type onto its encoder.
+var encoders = map[string]encoder{
+	"application/json":        jsonEncoder{},
+	"application/xml":         xmlEncoder{},
+	"text/xml":                xmlEncoder{},
+	"application/msgpack":     msgpackEncoder{},
+	"application/x-msgpack":   msgpackEncoder{},
+	"application/vnd.msgpack": msgpackEncoder{},
+}
+
+// negotiateEncoder picks the encoder for the media type the client prefers according to its Accept header.
+// JSON is used if the header is missing, accepts anything or lists no supported media type.
+func negotiateEncoder(accept string) encoder {
+	var best encoder = jsonEncoder{}
+	bestQuality := 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+
+		enc, ok := encoders[mediaType]
+		if mediaType == "*/*" || mediaType == "application/*" {
+			enc, ok = jsonEncoder{}, true
+		}
+		if ok && quality > bestQuality {
+			best, bestQuality = enc, quality
+		}
+	}
+	return best
+}
+
+// jsonToXML converts the next JSON value of decoder into an XML element with the given name.
+func jsonToXML(decoder *json.Decoder, enc *xml.Encoder, name string) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	switch value := token.(type) {
+	case json.Delim:
+		if err := enc.EncodeToken(start); err != nil {
+			return err
+		}
+		for decoder.More() {
+			child := "item"
+			if value == '{' {
+				key, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+				child = xmlName(key.(string))
+			}
+			if err := jsonToXML(decoder, enc, child); err != nil {
+				return err
+			}
+		}
+		if _, err := decoder.Token(); err != nil {
+			return err
+		}
+		return enc.EncodeToken(start.End())
+	case nil:
+		start.Attr = []xml.Attr{{Name: xml.Name{Local: "nil"}, Value: "true"}}
+		return enc.EncodeElement("", start)
+	case string, json.Number, bool:
+		return enc.EncodeElement(fmt.Sprint(value), start)
+	default:
+		return errors.New("unexpected JSON token")
+	}
+}
+
+// xmlName turns a JSON field name into a valid XML element name.
+func xmlName(key string) string {
+	var b strings.Builder
+	for i, c := range key {
+		switch {
+		case c == '_' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z':
+			b.WriteRune(c)
+		case i > 0 && (c == '-' || c == '.' || c >= '0' && c <= '9'):
+			b.WriteRune(c)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
@@ -0,0 +1,88 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+	"user-auth-hexagonal-architecture/internal/buildinfo"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// HealthApi handles HTTP requests for operational health information.
+type HealthApi struct {
+	checkHealthPort    usecases.CheckHealthPort
+	checkReadinessPort usecases.CheckReadinessPort
+}
+
+// NewHealthApiAdapter creates a new HealthApi with the given use case ports.
+//
+// Parameters:
+//   - checkHealthPort: Port for the health check use case
+//   - checkReadinessPort: Port for the readiness check use case
+//
+// Returns:
+//   - *HealthApi: A pointer to the newly created HealthApi
+func NewHealthApiAdapter(checkHealthPort usecases.CheckHealthPort, checkReadinessPort usecases.CheckReadinessPort) *HealthApi {
+	return &HealthApi{checkHealthPort, checkReadinessPort}
+}
+
+// InitHealthRoutes sets up the HTTP routes for health checks, the Kubernetes probes and build information.
+func (ha *HealthApi) InitHealthRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /health", ha.handleHealth)
+	mux.HandleFunc("GET /healthz", ha.handleLiveness)
+	mux.HandleFunc("GET /readyz", ha.handleReadiness)
+	mux.HandleFunc("GET /version", ha.handleVersion)
+}
+
+// handleHealth handles HTTP GET requests for the health report.
+//
+// It responds with HTTP 200 OK and the JSON health report if all backends are healthy,
+// or with HTTP 503 Service Unavailable and the same report otherwise.
+func (ha *HealthApi) handleHealth(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	report := ha.checkHealthPort.CheckHealth(ctx)
+
+	status := http.StatusOK
+	if !report.Healthy {
+		status = http.StatusServiceUnavailable
+	}
+
+	writeResponse(w, r, status, report)
+}
+
+// handleLiveness handles HTTP GET requests of the liveness probe.
+//
+// It always responds with HTTP 200 OK as long as the process serves requests. Dependencies are
+// deliberately not checked, so an outage of the database makes the instance unready instead
+// of getting it restarted.
+func (ha *HealthApi) handleLiveness(w http.ResponseWriter, r *http.Request) {
+	writeResponse(w, r, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleReadiness handles HTTP GET requests of the readiness probe.
+//
+// It responds with HTTP 200 OK and the JSON readiness report if all required dependencies are usable,
+// or with HTTP 503 Service Unavailable and the same report otherwise.
+func (ha *HealthApi) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+	defer cancel()
+
+	report := ha.checkReadinessPort.CheckReadiness(ctx)
+
+	status := http.StatusOK
+	if !report.Ready {
+		status = http.StatusServiceUnavailable
+	}
+
+	writeResponse(w, r, status, report)
+}
+
+// handleVersion handles HTTP GET requests for the build information.
+//
+// It responds with HTTP 200 OK and the version, commit, build date and Go version of the running binary.
+func (ha *HealthApi) handleVersion(w http.ResponseWriter, r *http.Request) {
+	writeResponse(w, r, http.StatusOK, buildinfo.Get())
+}
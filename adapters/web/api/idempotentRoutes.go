@@ -0,0 +1,38 @@
+package api
+
+import (
+	"user-auth-hexagonal-architecture/adapters/web/middleware"
+)
+
+// IdempotentRoutes declares the routes of this package that accept an Idempotency-Key header.
+// These are the state-changing operations a client may need to retry after a timeout without
+// knowing whether the first attempt went through.
+var IdempotentRoutes = middleware.IdempotentRoutes{
+	"POST /user/register":                           true,
+	"PATCH /user/me/profile":                        true,
+	"POST /admin/users":                             true,
+	"POST /admin/users/import":                      true,
+	"PUT /admin/users/{id}/roles":                   true,
+	"PUT /admin/users/{id}/role":                    true,
+	"PUT /admin/users/{id}/roles/{role}":            true,
+	"DELETE /admin/users/{id}/roles/{role}":         true,
+	"PUT /admin/users/{id}/username":                true,
+	"POST /admin/users/{id}/disable":                true,
+	"POST /admin/users/{id}/lock":                   true,
+	"POST /admin/users/{id}/merge":                  true,
+	"POST /admin/users/{id}/unlock":                 true,
+	"POST /admin/users/{id}/password":               true,
+	"POST /admin/users/{id}/enable":                 true,
+	"DELETE /admin/users/{id}":                      true,
+	"DELETE /admin/users/{id}/sessions/{sessionId}": true,
+	"POST /admin/users/{id}/erasure":                true,
+	"PUT /admin/users/{id}/legal-hold":              true,
+	"DELETE /admin/users/{id}/legal-hold":           true,
+	"POST /admin/webhooks":                          true,
+	"PUT /admin/roles/{name}":                       true,
+	"PUT /admin/groups/{name}":                      true,
+	"PUT /admin/policies/{id}":                      true,
+	"PUT /admin/tenants/{id}":                       true,
+	"POST /user/consents":                           true,
+	"POST /admin/consent-documents":                 true,
+}
@@ -0,0 +1,139 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"net/http"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/middleware"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// LoginHistoryApi handles HTTP requests for the audit records of authentication attempts, by users
+// for their own account and by administrators for forensic lookups.
+// It acts as an adapter between the HTTP layer and the login audit use cases.
+type LoginHistoryApi struct {
+	loginHistoryPort        usecases.LoginHistoryPort
+	searchLoginAttemptsPort usecases.SearchLoginAttemptsPort
+}
+
+// loginAttemptResponse represents the JSON structure of an authentication attempt.
+type loginAttemptResponse struct {
+	Username  string    `json:"username"`
+	Outcome   string    `json:"outcome"`
+	Reason    string    `json:"reason,omitempty"`
+	IPAddress string    `json:"ipAddress,omitempty"`
+	UserAgent string    `json:"userAgent,omitempty"`
+	Country   string    `json:"country,omitempty"`
+	At        time.Time `json:"at"`
+}
+
+// NewLoginHistoryApiAdapter creates a new LoginHistoryApi with the given use case ports.
+//
+// Parameters:
+//   - loginHistoryPort: Port for the login history of the authenticated user
+//   - searchLoginAttemptsPort: Port for searching the authentication attempts of all users
+//
+// Returns:
+//   - *LoginHistoryApi: A pointer to the newly created LoginHistoryApi
+func NewLoginHistoryApiAdapter(loginHistoryPort usecases.LoginHistoryPort, searchLoginAttemptsPort usecases.SearchLoginAttemptsPort) *LoginHistoryApi {
+	return &LoginHistoryApi{loginHistoryPort, searchLoginAttemptsPort}
+}
+
+// InitLoginHistoryRoutes sets up the HTTP routes for the login history.
+//
+// Access control is declared in RouteAccess.
+func (la *LoginHistoryApi) InitLoginHistoryRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /user/me/login-history", la.handleGetLoginHistory)
+	mux.HandleFunc("GET /admin/login-attempts", la.handleSearchLoginAttempts)
+}
+
+// handleGetLoginHistory handles HTTP GET requests for the recent authentication attempts on the
+// account of the authenticated user.
+//
+// The optional "limit" query parameter bounds the number of entries (default and maximum 100).
+// It responds with HTTP 200 OK and the attempts, newest first.
+func (la *LoginHistoryApi) handleGetLoginHistory(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.AuthenticationRequired, "")
+		return
+	}
+	limit, err := positiveIntParam(r.URL.Query().Get("limit"), 0)
+	if err != nil {
+		problem.Write(w, r, problem.InvalidRequest, "limit must be a positive integer")
+		return
+	}
+
+	records, err := la.loginHistoryPort.GetLoginHistory(r.Context(), principal.Subject, limit)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, toLoginAttemptResponses(records))
+}
+
+// handleSearchLoginAttempts handles HTTP GET requests searching the authentication attempts of all users.
+//
+// Supported query parameters:
+//   - username: username the attempts were made for, also unknown ones
+//   - ip: IP address the attempts were made from
+//   - outcome: SUCCESS or FAILURE
+//   - since: RFC 3339 time from which on attempts were made (inclusive)
+//   - until: RFC 3339 time until which attempts were made (exclusive)
+//   - limit: number of entries (default and maximum 100)
+//
+// It responds with HTTP 200 OK and the attempts, newest first, or 400 Bad Request for malformed parameters.
+func (la *LoginHistoryApi) handleSearchLoginAttempts(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := domain.LoginRecordFilter{IPAddress: query.Get("ip")}
+	if username := query.Get("username"); username != "" {
+		filter.Username = domain.NormalizeUsername(username)
+	}
+	if outcome := domain.LoginOutcome(query.Get("outcome")); outcome != "" {
+		if !outcome.Valid() {
+			problem.Write(w, r, problem.InvalidRequest, "outcome must be SUCCESS or FAILURE")
+			return
+		}
+		filter.Outcome = outcome
+	}
+	var err error
+	if filter.Since, err = timeParam(query.Get("since")); err != nil {
+		problem.Write(w, r, problem.InvalidRequest, "since must be an RFC 3339 time")
+		return
+	}
+	if filter.Until, err = timeParam(query.Get("until")); err != nil {
+		problem.Write(w, r, problem.InvalidRequest, "until must be an RFC 3339 time")
+		return
+	}
+	if filter.Limit, err = positiveIntParam(query.Get("limit"), 0); err != nil {
+		problem.Write(w, r, problem.InvalidRequest, "limit must be a positive integer")
+		return
+	}
+
+	records, err := la.searchLoginAttemptsPort.SearchLoginAttempts(r.Context(), filter)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, toLoginAttemptResponses(records))
+}
+
+// toLoginAttemptResponses converts login records into their JSON representation.
+func toLoginAttemptResponses(records []domain.LoginRecord) []loginAttemptResponse {
+	response := make([]loginAttemptResponse, 0, len(records))
+	for _, record := range records {
+		response = append(response, loginAttemptResponse{
+			Username:  record.Username.String(),
+			Outcome:   string(record.Outcome),
+			Reason:    record.Reason,
+			IPAddress: record.IPAddress,
+			UserAgent: record.UserAgent,
+			Country:   record.Country,
+			At:        record.At,
+		})
+	}
+	return response
+}
@@ -0,0 +1,183 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"net/http"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/middleware"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/adapters/web/validation"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// MfaApi handles HTTP requests for users managing their own second factors.
+// It acts as an adapter between the HTTP layer and the MFA use cases.
+type MfaApi struct {
+	manageMfaPort usecases.ManageMfaPort
+}
+
+// mfaConfirmRequest represents the expected JSON structure for confirming a pending factor.
+type mfaConfirmRequest struct {
+	Code string `json:"code"`
+}
+
+// validate checks that the code is given.
+func (mr *mfaConfirmRequest) validate(v *validation.Validator) {
+	v.Required("code", mr.Code)
+}
+
+// mfaDisableRequest represents the expected JSON structure for removing a factor.
+type mfaDisableRequest struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+// validate checks that the password and the code are given.
+func (mr *mfaDisableRequest) validate(v *validation.Validator) {
+	v.Required("password", mr.Password).MaxBytes("password", mr.Password, validation.MaxPasswordBytes)
+	v.Required("code", mr.Code)
+}
+
+// mfaMethodResponse represents the JSON structure of a second factor, without its secret.
+type mfaMethodResponse struct {
+	Method      string     `json:"method"`
+	Enabled     bool       `json:"enabled"`
+	EnrolledAt  time.Time  `json:"enrolledAt"`
+	ConfirmedAt *time.Time `json:"confirmedAt,omitempty"`
+}
+
+// mfaEnrollmentResponse represents the JSON structure of a started enrollment.
+type mfaEnrollmentResponse struct {
+	Method          string `json:"method"`
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+// NewMfaApiAdapter creates a new MfaApi with the given use case port.
+//
+// Parameters:
+//   - manageMfaPort: Port for listing, enabling and disabling the authenticated user's second factors
+//
+// Returns:
+//   - *MfaApi: A pointer to the newly created MfaApi
+func NewMfaApiAdapter(manageMfaPort usecases.ManageMfaPort) *MfaApi {
+	return &MfaApi{manageMfaPort}
+}
+
+// InitMfaRoutes sets up the HTTP routes for second factors.
+//
+// Access control is declared in RouteAccess.
+func (ma *MfaApi) InitMfaRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /user/me/mfa", ma.handleListMfaMethods)
+	mux.HandleFunc("POST /user/me/mfa/{method}", ma.handleBeginMfaEnrollment)
+	mux.HandleFunc("POST /user/me/mfa/{method}/confirm", ma.handleEnableMfa)
+	mux.HandleFunc("DELETE /user/me/mfa/{method}", ma.handleDisableMfa)
+}
+
+// handleListMfaMethods handles HTTP GET requests for the authenticated user's second factors.
+//
+// On success, it responds with HTTP 200 OK and the pending and enabled factors, e.g.
+// [{"method": "totp", "enabled": true, "enrolledAt": "...", "confirmedAt": "..."}].
+func (ma *MfaApi) handleListMfaMethods(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.AuthenticationRequired, "")
+		return
+	}
+
+	factors, err := ma.manageMfaPort.ListMfaMethods(r.Context(), principal.Subject)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	response := make([]mfaMethodResponse, 0, len(factors))
+	for _, factor := range factors {
+		method := mfaMethodResponse{Method: string(factor.Method), Enabled: factor.Confirmed(), EnrolledAt: factor.EnrolledAt}
+		if factor.Confirmed() {
+			method.ConfirmedAt = &factor.ConfirmedAt
+		}
+		response = append(response, method)
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+// handleBeginMfaEnrollment handles HTTP POST requests starting the enrollment of a second factor.
+//
+// On success, it responds with HTTP 201 Created, the secret and the otpauth:// provisioning URI to
+// scan with the authenticator app. The factor stays pending until it is confirmed with a code.
+// On failure, it responds with an application/problem+json body and one of the following:
+//   - 404 Not Found if the method is not supported
+//   - 409 Conflict if the method is enabled already
+func (ma *MfaApi) handleBeginMfaEnrollment(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.AuthenticationRequired, "")
+		return
+	}
+
+	enrollment, err := ma.manageMfaPort.BeginMfaEnrollment(r.Context(), principal.Subject, domain.MfaMethod(r.PathValue("method")))
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeResponse(w, r, http.StatusCreated, mfaEnrollmentResponse{
+		Method:          string(enrollment.Method),
+		Secret:          enrollment.Secret,
+		ProvisioningURI: enrollment.ProvisioningURI,
+	})
+}
+
+// handleEnableMfa handles HTTP POST requests confirming a pending factor.
+//
+// The function expects a JSON body with a current "code" of the authenticator app.
+// On success, it responds with HTTP 204 No Content and multi-factor authentication is enabled.
+// On failure, it responds with an application/problem+json body and one of the following:
+//   - 400 Bad Request if the code is missing
+//   - 401 Unauthorized if the code is wrong
+//   - 404 Not Found if no factor of the method is pending
+func (ma *MfaApi) handleEnableMfa(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.AuthenticationRequired, "")
+		return
+	}
+	var request mfaConfirmRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	if err := ma.manageMfaPort.EnableMfa(r.Context(), principal.Subject, domain.MfaMethod(r.PathValue("method")), request.Code); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleDisableMfa handles HTTP DELETE requests removing a second factor.
+//
+// The function expects a JSON body with the current "password" and a current "code" of the factor.
+// On success, it responds with HTTP 204 No Content.
+// On failure, it responds with an application/problem+json body and one of the following:
+//   - 400 Bad Request if the password or the code is missing
+//   - 401 Unauthorized if the password or the code is wrong
+//   - 403 Forbidden if the tenant requires multi-factor authentication and this is the last factor
+//   - 404 Not Found if the method is not enabled
+func (ma *MfaApi) handleDisableMfa(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.AuthenticationRequired, "")
+		return
+	}
+	var request mfaDisableRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	if err := ma.manageMfaPort.DisableMfa(r.Context(), principal.Subject, domain.MfaMethod(r.PathValue("method")), request.Password, request.Code); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
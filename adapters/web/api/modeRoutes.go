@@ -0,0 +1,20 @@
+package api
+
+import (
+	"user-auth-hexagonal-architecture/adapters/web/middleware"
+)
+
+// ModeRoutes declares the routes of this package that stay available in a more restrictive mode
+// than their method implies. Token verification keeps working during maintenance, so services
+// relying on it are not taken down, and logins keep working in read-only mode, as a failure to
+// record the login time does not fail the login.
+var ModeRoutes = middleware.ModeRoutes{
+	"POST /token/verify-batch": middleware.ModeMaintenance,
+	"GET /admin/log-level":     middleware.ModeMaintenance,
+	"PUT /admin/log-level":     middleware.ModeMaintenance,
+	"GET /admin/mode":          middleware.ModeMaintenance,
+	"PUT /admin/mode":          middleware.ModeMaintenance,
+	"POST /user/login":         middleware.ModeReadOnly,
+	"POST /user/session":       middleware.ModeReadOnly,
+	"DELETE /user/session":     middleware.ModeReadOnly,
+}
@@ -0,0 +1,128 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"net/http"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/adapters/web/validation"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// PasswordApi handles HTTP requests for replacing a temporary password and rating new passwords.
+// It acts as an adapter between the HTTP layer and the password use cases.
+type PasswordApi struct {
+	changeTemporaryPasswordPort  usecases.ChangeTemporaryPasswordPort
+	estimatePasswordStrengthPort usecases.EstimatePasswordStrengthPort
+}
+
+// passwordChangeRequest represents the expected JSON structure for replacing a temporary password.
+type passwordChangeRequest struct {
+	PasswordChangeToken string `json:"password_change_token"`
+	NewPassword         string `json:"new_password"`
+}
+
+// validate checks that the token and the new password are given.
+func (pr *passwordChangeRequest) validate(v *validation.Validator) {
+	v.Required("password_change_token", pr.PasswordChangeToken)
+	v.Required("new_password", pr.NewPassword).MaxBytes("new_password", pr.NewPassword, validation.MaxPasswordBytes)
+}
+
+// passwordStrengthRequest represents the expected JSON structure for rating a password.
+type passwordStrengthRequest struct {
+	Password string `json:"password"`
+	Username string `json:"username"`
+}
+
+// validate checks that the password is given; its length is rated rather than rejected.
+func (pr *passwordStrengthRequest) validate(v *validation.Validator) {
+	v.Required("password", pr.Password).MaxBytes("password", pr.Password, 4*validation.MaxPasswordBytes)
+}
+
+// passwordStrengthResponse represents the JSON structure of a password rating.
+type passwordStrengthResponse struct {
+	Score       int      `json:"score"`
+	EntropyBits float64  `json:"entropyBits"`
+	Acceptable  bool     `json:"acceptable"`
+	Violations  []string `json:"violations"`
+	Suggestions []string `json:"suggestions"`
+}
+
+// NewPasswordApiAdapter creates a new PasswordApi with the given use case ports.
+//
+// Parameters:
+//   - changeTemporaryPasswordPort: Port for replacing a temporary password
+//   - estimatePasswordStrengthPort: Port for rating passwords against the password policy
+//
+// Returns:
+//   - *PasswordApi: A pointer to the newly created PasswordApi
+func NewPasswordApiAdapter(changeTemporaryPasswordPort usecases.ChangeTemporaryPasswordPort, estimatePasswordStrengthPort usecases.EstimatePasswordStrengthPort) *PasswordApi {
+	return &PasswordApi{changeTemporaryPasswordPort, estimatePasswordStrengthPort}
+}
+
+// InitPasswordRoutes sets up the HTTP routes for password changes.
+//
+// Access control is declared in RouteAccess.
+func (pa *PasswordApi) InitPasswordRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /user/password/change", pa.handleChangeTemporaryPassword)
+	mux.HandleFunc("POST /user/password/strength", pa.handleEstimatePasswordStrength)
+}
+
+// handleChangeTemporaryPassword handles HTTP POST requests replacing a temporary password.
+//
+// The function expects a JSON body with the "password_change_token" returned by POST /user/login
+// and the "new_password", which has to satisfy the password policy of the tenant and differ from
+// the temporary password. The route is public, the token authenticates the request.
+// On success, it responds with HTTP 204 No Content and the user logs in with the new password.
+// On failure, it responds with an application/problem+json body and one of the following:
+//   - 400 Bad Request for invalid JSON, missing fields or a weak password
+//   - 401 Unauthorized if the token is invalid, expired or has been used
+//   - 403 Forbidden or 423 Locked if the account has been disabled or locked in the meantime
+func (pa *PasswordApi) handleChangeTemporaryPassword(w http.ResponseWriter, r *http.Request) {
+	var request passwordChangeRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	if err := pa.changeTemporaryPasswordPort.ChangeTemporaryPassword(r.Context(), request.PasswordChangeToken, request.NewPassword); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleEstimatePasswordStrength handles HTTP POST requests rating a password, e.g. while a user
+// chooses one during registration.
+//
+// The function expects a JSON body with the "password" and optionally the "username" it is for.
+// On success, it responds with HTTP 200 OK, a "score" from 0 (very weak) to 4 (very strong), whether
+// the password is "acceptable" under the password policy of the tenant, the violated rules and
+// suggestions, e.g. {"score": 1, "acceptable": false, "violations": ["must contain a digit"], ...}.
+// The route is public and rate limited per client; the password is neither stored nor logged.
+func (pa *PasswordApi) handleEstimatePasswordStrength(w http.ResponseWriter, r *http.Request) {
+	var request passwordStrengthRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	strength, err := pa.estimatePasswordStrengthPort.EstimatePasswordStrength(r.Context(), request.Password, request.Username)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	response := passwordStrengthResponse{
+		Score:       strength.Score,
+		EntropyBits: strength.EntropyBits,
+		Acceptable:  strength.Acceptable,
+		Violations:  strength.Violations,
+		Suggestions: strength.Suggestions,
+	}
+	if response.Violations == nil {
+		response.Violations = []string{}
+	}
+	if response.Suggestions == nil {
+		response.Suggestions = []string{}
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeResponse(w, r, http.StatusOK, response)
+}
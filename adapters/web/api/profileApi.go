@@ -0,0 +1,198 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/middleware"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/adapters/web/validation"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// ProfileApi handles HTTP requests for reading and changing user profiles.
+// It acts as an adapter between the HTTP layer and the profile use cases.
+type ProfileApi struct {
+	getProfilePort    usecases.GetProfilePort
+	updateProfilePort usecases.UpdateProfilePort
+}
+
+// attributeRequest represents a custom profile attribute in update requests.
+type attributeRequest struct {
+	Value      string `json:"value"`
+	Visibility string `json:"visibility"`
+}
+
+// profileUpdateRequest represents the expected JSON structure for profile updates.
+// Omitted fields are left unchanged; attributes set to null are removed. Notifications replace the
+// notification preferences as a whole, an empty object restores the defaults.
+type profileUpdateRequest struct {
+	DisplayName   *string                      `json:"displayName"`
+	Locale        *string                      `json:"locale"`
+	Timezone      *string                      `json:"timezone"`
+	AvatarURL     *string                      `json:"avatarUrl"`
+	Attributes    map[string]*attributeRequest `json:"attributes"`
+	PhoneNumber   *string                      `json:"phoneNumber"`
+	Notifications map[string][]string          `json:"notifications"`
+}
+
+// validate only bounds the number of attributes; the profile rules are enforced by the domain.
+func (pr *profileUpdateRequest) validate(v *validation.Validator) {
+	if len(pr.Attributes) > domain.MaxProfileAttributes {
+		v.Add("attributes", "too_many", fmt.Sprintf("at most %d attributes are allowed", domain.MaxProfileAttributes))
+	}
+}
+
+// attributeResponse represents the JSON structure of a custom profile attribute.
+type attributeResponse struct {
+	Value      string `json:"value"`
+	Visibility string `json:"visibility"`
+}
+
+// userProfileResponse represents the JSON structure of a user profile.
+type userProfileResponse struct {
+	DisplayName   string                       `json:"displayName,omitempty"`
+	Locale        string                       `json:"locale,omitempty"`
+	Timezone      string                       `json:"timezone,omitempty"`
+	AvatarURL     string                       `json:"avatarUrl,omitempty"`
+	Attributes    map[string]attributeResponse `json:"attributes"`
+	PhoneNumber   string                       `json:"phoneNumber,omitempty"`
+	Notifications map[string][]string          `json:"notifications,omitempty"`
+	UpdatedAt     *time.Time                   `json:"updatedAt,omitempty"`
+}
+
+// NewProfileApiAdapter creates a new ProfileApi with the given use case ports.
+//
+// Parameters:
+//   - getProfilePort: Port for reading profiles
+//   - updateProfilePort: Port for changing the authenticated user's profile
+//
+// Returns:
+//   - *ProfileApi: A pointer to the newly created ProfileApi
+func NewProfileApiAdapter(getProfilePort usecases.GetProfilePort, updateProfilePort usecases.UpdateProfilePort) *ProfileApi {
+	return &ProfileApi{getProfilePort, updateProfilePort}
+}
+
+// InitProfileRoutes sets up the HTTP routes for profile operations.
+//
+// Access control is declared in RouteAccess.
+func (pa *ProfileApi) InitProfileRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /user/me/profile", pa.handleGetProfile)
+	mux.HandleFunc("PATCH /user/me/profile", pa.handleUpdateProfile)
+	mux.HandleFunc("GET /users/{username}/profile", pa.handleGetPublicProfile)
+}
+
+// handleGetProfile handles HTTP GET requests for the authenticated user's complete profile.
+//
+// On success, it responds with HTTP 200 OK and the profile including private attributes,
+// with 404 Not Found if the account of the token subject no longer exists.
+func (pa *ProfileApi) handleGetProfile(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.AuthenticationRequired, "")
+		return
+	}
+
+	profile, err := pa.getProfilePort.GetProfile(r.Context(), principal.Subject)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, toUserProfileResponse(profile))
+}
+
+// handleUpdateProfile handles HTTP PATCH requests that change the authenticated user's profile.
+//
+// The function expects a JSON body with any of "displayName", "locale", "timezone", "avatarUrl",
+// "attributes", "phoneNumber" and "notifications", e.g. {"attributes": {"team": {"value": "core",
+// "visibility": "public"}}} or {"notifications": {"new_device_login": ["email", "sms"]}}.
+// On success, it responds with HTTP 200 OK and the updated profile. It responds with
+// 400 Bad Request and a detail naming the rejected field for invalid values.
+func (pa *ProfileApi) handleUpdateProfile(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.AuthenticationRequired, "")
+		return
+	}
+	var request profileUpdateRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	changes := domain.ProfileChanges{
+		DisplayName: request.DisplayName,
+		Locale:      request.Locale,
+		Timezone:    request.Timezone,
+		AvatarURL:   request.AvatarURL,
+		Attributes:  make(map[string]*domain.ProfileAttribute, len(request.Attributes)),
+		PhoneNumber: request.PhoneNumber,
+	}
+	for key, attribute := range request.Attributes {
+		if attribute == nil {
+			changes.Attributes[key] = nil
+			continue
+		}
+		changes.Attributes[key] = &domain.ProfileAttribute{Value: attribute.Value, Visibility: domain.AttributeVisibility(attribute.Visibility)}
+	}
+	if request.Notifications != nil {
+		changes.Notifications = make(domain.NotificationPreferences, len(request.Notifications))
+		for topic, channels := range request.Notifications {
+			preferred := make([]domain.NotificationChannel, 0, len(channels))
+			for _, channel := range channels {
+				preferred = append(preferred, domain.NotificationChannel(channel))
+			}
+			changes.Notifications[domain.NotificationTopic(topic)] = preferred
+		}
+	}
+
+	profile, err := pa.updateProfilePort.UpdateProfile(r.Context(), principal.Subject, changes)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, toUserProfileResponse(profile))
+}
+
+// handleGetPublicProfile handles HTTP GET requests for the profile of any user.
+//
+// On success, it responds with HTTP 200 OK and the profile without private attributes,
+// with 404 Not Found if the user does not exist.
+func (pa *ProfileApi) handleGetPublicProfile(w http.ResponseWriter, r *http.Request) {
+	profile, err := pa.getProfilePort.GetPublicProfile(r.Context(), r.PathValue("username"))
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, toUserProfileResponse(profile))
+}
+
+// toUserProfileResponse converts a profile into its JSON representation.
+func toUserProfileResponse(profile domain.Profile) userProfileResponse {
+	response := userProfileResponse{
+		DisplayName: profile.DisplayName,
+		Locale:      profile.Locale,
+		Timezone:    profile.Timezone,
+		AvatarURL:   profile.AvatarURL,
+		Attributes:  make(map[string]attributeResponse, len(profile.Attributes)),
+		PhoneNumber: profile.PhoneNumber,
+	}
+	for key, attribute := range profile.Attributes {
+		response.Attributes[key] = attributeResponse{Value: attribute.Value, Visibility: string(attribute.Visibility)}
+	}
+	if len(profile.Notifications) > 0 {
+		response.Notifications = make(map[string][]string, len(profile.Notifications))
+		for topic, channels := range profile.Notifications {
+			preferred := make([]string, 0, len(channels))
+			for _, channel := range channels {
+				preferred = append(preferred, string(channel))
+			}
+			response.Notifications[string(topic)] = preferred
+		}
+	}
+	if !profile.UpdatedAt.IsZero() {
+		response.UpdatedAt = &profile.UpdatedAt
+	}
+	return response
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/middleware"
+	"user-auth-hexagonal-architecture/internal/ports/security"
+)
+
+// RateLimits declares the rate limits of the routes registered by this package.
+// Credential endpoints are limited per client IP and per targeted username, so neither a single
+// client nor a distributed attack on a single account can brute-force passwords.
+var RateLimits = middleware.RateLimitPolicy{
+	"POST /user/login": {
+		{Name: "login-ip", Limit: security.RateLimit{Requests: 20, Per: time.Minute}, Key: middleware.ByClientIP},
+		{Name: "login-user", Limit: security.RateLimit{Requests: 5, Per: time.Minute}, Key: middleware.ByUsername("username")},
+	},
+	"POST /user/session": {
+		{Name: "login-ip", Limit: security.RateLimit{Requests: 20, Per: time.Minute}, Key: middleware.ByClientIP},
+		{Name: "login-user", Limit: security.RateLimit{Requests: 5, Per: time.Minute}, Key: middleware.ByUsername("username")},
+	},
+	"POST /token/verify-batch": {
+		{Name: "verify-batch-ip", Limit: security.RateLimit{Requests: 120, Per: time.Minute, Burst: 30}, Key: middleware.ByClientIP},
+	},
+	"POST /token/refresh": {
+		{Name: "refresh-ip", Limit: security.RateLimit{Requests: 60, Per: time.Minute, Burst: 20}, Key: middleware.ByClientIP},
+	},
+	"POST /user/password/change": {
+		{Name: "password-change-ip", Limit: security.RateLimit{Requests: 10, Per: time.Minute}, Key: middleware.ByClientIP},
+	},
+	"POST /user/password/strength": {
+		{Name: "password-strength-ip", Limit: security.RateLimit{Requests: 60, Per: time.Minute, Burst: 20}, Key: middleware.ByClientIP},
+	},
+	"POST /user/register": {
+		{Name: "register-ip", Limit: security.RateLimit{Requests: 5, Per: time.Minute, Burst: 10}, Key: middleware.ByClientIP},
+	},
+	"GET /user/username-available": {
+		{Name: "username-check-ip", Limit: security.RateLimit{Requests: 30, Per: time.Minute, Burst: 10}, Key: middleware.ByClientIP},
+	},
+	"POST /user/data-exports": {
+		{Name: "data-export-ip", Limit: security.RateLimit{Requests: 5, Per: time.Hour}, Key: middleware.ByClientIP},
+	},
+	"POST /user/me/mfa/{method}/confirm": {
+		{Name: "mfa-code-ip", Limit: security.RateLimit{Requests: 10, Per: time.Minute}, Key: middleware.ByClientIP},
+	},
+	"DELETE /user/me/mfa/{method}": {
+		{Name: "mfa-code-ip", Limit: security.RateLimit{Requests: 10, Per: time.Minute}, Key: middleware.ByClientIP},
+	},
+	"DELETE /user/me": {
+		{Name: "delete-account-ip", Limit: security.RateLimit{Requests: 5, Per: time.Minute}, Key: middleware.ByClientIP},
+	},
+}
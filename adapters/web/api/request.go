@@ -0,0 +1,63 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"user-auth-hexagonal-architecture/adapters/web/middleware"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/adapters/web/validation"
+	"user-auth-hexagonal-architecture/internal/device"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// maxUserAgentBytes bounds the user agent recorded for a session.
+const maxUserAgentBytes = 512
+
+// maxRequestBodyBytes bounds the size of JSON request bodies.
+const maxRequestBodyBytes = 64 << 10
+
+// validatable is implemented by request DTOs that check their own fields.
+type validatable interface {
+	validate(v *validation.Validator)
+}
+
+// decodeRequest decodes a JSON request body into dst and validates it.
+//
+// Bodies larger than maxRequestBodyBytes are rejected with 413. On any other failure it writes
+// a 400 problem response, with per-field errors for validation failures, and returns false;
+// the caller must then stop handling the request.
+func decodeRequest(w http.ResponseWriter, r *http.Request, dst validatable) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			problem.Write(w, r, problem.PayloadTooLarge, fmt.Sprintf("The request body must not exceed %d bytes", maxBytesErr.Limit))
+			return false
+		}
+		problem.Write(w, r, problem.InvalidRequest, "Invalid JSON format")
+		return false
+	}
+
+	v := validation.New()
+	dst.validate(v)
+	if !v.Valid() {
+		problem.WriteValidation(w, r, v.Errors())
+		return false
+	}
+	return true
+}
+
+// deviceContext returns the context of the request carrying the device the client reports,
+// which the login use case records in the new session.
+func deviceContext(r *http.Request) context.Context {
+	userAgent := r.UserAgent()
+	if len(userAgent) > maxUserAgentBytes {
+		userAgent = userAgent[:maxUserAgentBytes]
+	}
+	ip, _ := middleware.ByClientIP(r)
+	country := device.Country(r.Header.Get(device.CountryHeader))
+	return device.WithDevice(r.Context(), domain.Device{UserAgent: userAgent, IPAddress: ip, Country: country})
+}
@@ -0,0 +1,22 @@
+package api
+
+import (
+	"net/http"
+	"user-auth-hexagonal-architecture/internal/logging"
+)
+
+// logger writes the log records of this package.
+var logger = logging.Component("http")
+
+// writeResponse writes v with the given status code, encoded in the media type negotiated from
+// the Accept header: JSON by default, XML or MessagePack on request.
+func writeResponse(w http.ResponseWriter, r *http.Request, status int, v any) {
+	enc := negotiateEncoder(r.Header.Get("Accept"))
+
+	w.Header().Add("Vary", "Accept")
+	w.Header().Set("Content-Type", enc.ContentType())
+	w.WriteHeader(status)
+	if err := enc.Encode(w, v); err != nil {
+		logger.ErrorContext(r.Context(), "Error writing response", "error", err)
+	}
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"user-auth-hexagonal-architecture/adapters/web/middleware"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// RouteAccess declares the access rule of every route registered by this package.
+// Patterns are relative to the version prefix, e.g. "GET /user/me" is served as "GET /api/v1/user/me".
+// Routes that are not listed require an authenticated subject.
+var RouteAccess = middleware.RouteAccess{
+	"POST /user/register":                 middleware.Public(),
+	"POST /user/login":                    middleware.Public(),
+	"GET /user/username-available":        middleware.Public(),
+	"GET /user/me":                        middleware.Permission(domain.PermissionProfileRead),
+	"DELETE /user/me":                     middleware.Permission(domain.PermissionProfileWrite),
+	"POST /user/session":                  middleware.Public(),
+	"DELETE /user/session":                middleware.Public(),
+	"GET /user/me/login-history":          middleware.Permission(domain.PermissionProfileRead),
+	"GET /user/sessions":                  middleware.Permission(domain.PermissionProfileRead),
+	"DELETE /user/sessions/{id}":          middleware.Permission(domain.PermissionProfileWrite),
+	"POST /user/data-exports":             middleware.Permission(domain.PermissionProfileRead),
+	"GET /user/data-exports/{id}":         middleware.Permission(domain.PermissionProfileRead),
+	"GET /user/data-exports/{id}/archive": middleware.Public(),
+	"GET /csrf":                           middleware.Public(),
+	"GET /health":                         middleware.Public(),
+	"GET /healthz":                        middleware.Public(),
+	"GET /readyz":                         middleware.Public(),
+	"GET /version":                        middleware.Public(),
+	"GET /capabilities":                   middleware.Public(),
+	"GET /metrics":                        middleware.Public(),
+
+	"POST /token/verify-batch": middleware.Public(),
+	"POST /token/refresh":      middleware.Public(),
+
+	"POST /user/password/change":   middleware.Public(),
+	"POST /user/password/strength": middleware.Public(),
+
+	"GET /user/me/profile":          middleware.Permission(domain.PermissionProfileRead),
+	"PATCH /user/me/profile":        middleware.Permission(domain.PermissionProfileWrite),
+	"GET /users/{username}/profile": middleware.Permission(domain.PermissionProfileRead),
+
+	"GET /user/me/mfa":                   middleware.Permission(domain.PermissionProfileRead),
+	"POST /user/me/mfa/{method}":         middleware.Permission(domain.PermissionProfileWrite),
+	"POST /user/me/mfa/{method}/confirm": middleware.Permission(domain.PermissionProfileWrite),
+	"DELETE /user/me/mfa/{method}":       middleware.Permission(domain.PermissionProfileWrite),
+
+	"GET /.well-known/oauth-authorization-server": middleware.Public(),
+	"GET /.well-known/jwks.json":                  middleware.Public(),
+
+	"GET /admin/users":                              middleware.Permission(domain.PermissionUsersRead),
+	"GET /admin/login-attempts":                     middleware.Permission(domain.PermissionUsersRead),
+	"GET /admin/stats":                              middleware.Permission(domain.PermissionUsersRead),
+	"GET /admin/users/search":                       middleware.Permission(domain.PermissionUsersRead),
+	"POST /admin/users":                             middleware.Permission(domain.PermissionUsersWrite),
+	"POST /admin/users/import":                      middleware.Permission(domain.PermissionUsersWrite),
+	"GET /admin/users/{id}":                         middleware.Permission(domain.PermissionUsersRead),
+	"GET /admin/users/{id}/security-timeline":       middleware.Permission(domain.PermissionUsersRead),
+	"PUT /admin/users/{id}/roles":                   middleware.Permission(domain.PermissionUsersWrite),
+	"PUT /admin/users/{id}/role":                    middleware.Permission(domain.PermissionUsersWrite),
+	"PUT /admin/users/{id}/roles/{role}":            middleware.Permission(domain.PermissionUsersWrite),
+	"DELETE /admin/users/{id}/roles/{role}":         middleware.Permission(domain.PermissionUsersWrite),
+	"PUT /admin/users/{id}/username":                middleware.Permission(domain.PermissionUsersWrite),
+	"POST /admin/users/{id}/disable":                middleware.Permission(domain.PermissionUsersWrite),
+	"POST /admin/users/{id}/lock":                   middleware.Permission(domain.PermissionUsersWrite),
+	"POST /admin/users/{id}/unlock":                 middleware.Permission(domain.PermissionUsersWrite),
+	"POST /admin/users/{id}/password":               middleware.Permission(domain.PermissionUsersWrite),
+	"POST /admin/users/{id}/merge":                  middleware.Permission(domain.PermissionUsersWrite),
+	"POST /admin/users/{id}/enable":                 middleware.Permission(domain.PermissionUsersWrite),
+	"DELETE /admin/users/{id}":                      middleware.Permission(domain.PermissionUsersWrite),
+	"POST /admin/users/{id}/erasure":                middleware.Permission(domain.PermissionUsersErase),
+	"GET /admin/erasure-certificates/{id}":          middleware.Permission(domain.PermissionUsersErase),
+	"PUT /admin/users/{id}/legal-hold":              middleware.Permission(domain.PermissionUsersErase),
+	"DELETE /admin/users/{id}/legal-hold":           middleware.Permission(domain.PermissionUsersErase),
+	"GET /admin/users/{id}/sessions":                middleware.Permission(domain.PermissionUsersRead),
+	"DELETE /admin/users/{id}/sessions/{sessionId}": middleware.Permission(domain.PermissionUsersWrite),
+	"GET /admin/events/stream":                      middleware.Permission(domain.PermissionUsersRead),
+	"GET /admin/log-level":                          middleware.Permission(domain.PermissionSystem),
+	"PUT /admin/log-level":                          middleware.Permission(domain.PermissionSystem),
+	"GET /admin/mode":                               middleware.Permission(domain.PermissionSystem),
+	"GET /admin/data-inventory":                     middleware.Permission(domain.PermissionUsersRead),
+	"PUT /admin/mode":                               middleware.Permission(domain.PermissionSystem),
+
+	"POST /admin/webhooks":             middleware.Permission(domain.PermissionWebhooks),
+	"GET /admin/webhooks":              middleware.Permission(domain.PermissionWebhooks),
+	"DELETE /admin/webhooks/{id}":      middleware.Permission(domain.PermissionWebhooks),
+	"GET /admin/webhooks/dead-letters": middleware.Permission(domain.PermissionWebhooks),
+
+	"GET /admin/roles":           middleware.Permission(domain.PermissionRoles),
+	"PUT /admin/roles/{name}":    middleware.Permission(domain.PermissionRoles),
+	"DELETE /admin/roles/{name}": middleware.Permission(domain.PermissionRoles),
+
+	"GET /admin/groups":                            middleware.Permission(domain.PermissionGroups),
+	"GET /admin/groups/{name}":                     middleware.Permission(domain.PermissionGroups),
+	"PUT /admin/groups/{name}":                     middleware.Permission(domain.PermissionGroups),
+	"DELETE /admin/groups/{name}":                  middleware.Permission(domain.PermissionGroups),
+	"PUT /admin/groups/{name}/members/{userId}":    middleware.Permission(domain.PermissionGroups),
+	"DELETE /admin/groups/{name}/members/{userId}": middleware.Permission(domain.PermissionGroups),
+
+	"GET /admin/policies":         middleware.Permission(domain.PermissionPolicies),
+	"PUT /admin/policies/{id}":    middleware.Permission(domain.PermissionPolicies),
+	"DELETE /admin/policies/{id}": middleware.Permission(domain.PermissionPolicies),
+
+	"GET /consent-documents":        middleware.Public(),
+	"GET /user/consents":            middleware.Permission(domain.PermissionProfileRead),
+	"POST /user/consents":           middleware.Permission(domain.PermissionProfileWrite),
+	"POST /admin/consent-documents": middleware.Permission(domain.PermissionConsents),
+
+	"GET /admin/tenants":         middleware.Permission(domain.PermissionTenants),
+	"GET /admin/tenants/{id}":    middleware.Permission(domain.PermissionTenants),
+	"PUT /admin/tenants/{id}":    middleware.Permission(domain.PermissionTenants),
+	"DELETE /admin/tenants/{id}": middleware.Permission(domain.PermissionTenants),
+
+	"POST /email-feedback/{provider}":            middleware.Public(),
+	"GET /admin/email-suppressions":              middleware.Permission(domain.PermissionUsersRead),
+	"DELETE /admin/email-suppressions/{address}": middleware.Permission(domain.PermissionUsersWrite),
+}
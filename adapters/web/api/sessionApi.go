@@ -0,0 +1,122 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/middleware"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// SessionApi handles HTTP requests for cookie-based browser sessions.
+// The access token is kept in an HttpOnly cookie instead of being handed to scripts.
+type SessionApi struct {
+	loadUserPort   usecases.LoadUserPort
+	endSessionPort usecases.EndSessionPort
+	cookie         middleware.CookieConfig
+	csrf           *middleware.CSRFProtection
+}
+
+// csrfResponse represents the JSON structure carrying a CSRF token.
+type csrfResponse struct {
+	CSRFToken string `json:"csrfToken"`
+}
+
+// NewSessionApiAdapter creates a new SessionApi.
+//
+// Parameters:
+//   - loadUserPort: Port for user loading use case
+//   - endSessionPort: Port for revoking the session on logout
+//   - cookie: The attributes of the session cookie
+//   - csrf: The CSRF protection issuing the tokens
+//
+// Returns:
+//   - *SessionApi: A pointer to the newly created SessionApi
+func NewSessionApiAdapter(loadUserPort usecases.LoadUserPort, endSessionPort usecases.EndSessionPort, cookie middleware.CookieConfig, csrf *middleware.CSRFProtection) *SessionApi {
+	return &SessionApi{loadUserPort, endSessionPort, cookie, csrf}
+}
+
+// InitSessionRoutes sets up the HTTP routes for cookie sessions and CSRF tokens.
+func (sa *SessionApi) InitSessionRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /user/session", sa.handleCreateSession)
+	mux.HandleFunc("DELETE /user/session", sa.handleDeleteSession)
+	mux.HandleFunc("GET /csrf", sa.handleCSRFToken)
+}
+
+// handleCreateSession handles HTTP POST requests for a cookie session login.
+//
+// It expects the same JSON body as POST /user/login and answers with the same errors. On success,
+// the access token is set as HttpOnly session cookie expiring together with the token, a fresh
+// CSRF token is issued and the response is HTTP 200 OK with the CSRF token in the body:
+//
+//	{"csrfToken": "q3Jm...Yw.M0Zl...Ag"}
+//
+// Users with a temporary password are answered with 403 Forbidden, as they have to change it
+// with the token issued by POST /user/login first.
+func (sa *SessionApi) handleCreateSession(w http.ResponseWriter, r *http.Request) {
+	var userRequest userRequest
+	if !decodeRequest(w, r, &userRequest) {
+		return
+	}
+
+	tokens, err := sa.loadUserPort.LoadUser(deviceContext(r), userRequest.Username, userRequest.Password, userRequest.MfaCode, domain.LoginMethodSession, toConsentRefs(userRequest.Consents))
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	if tokens.PasswordChangeToken != "" {
+		problem.WriteError(w, r, errorx.ErrPasswordChangeRequired.Detailf("log in with POST /user/login to change the temporary password"))
+		return
+	}
+
+	http.SetCookie(w, sa.sessionCookie(tokens.AccessToken, tokens.ExpiresAt))
+	token := sa.csrf.IssueToken(w)
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeResponse(w, r, http.StatusOK, csrfResponse{token})
+}
+
+// handleDeleteSession handles HTTP DELETE requests logging out of a cookie session.
+//
+// It revokes the session, so its access token is no longer accepted, expires the session cookie
+// and responds with HTTP 204 No Content, also if there was no session.
+func (sa *SessionApi) handleDeleteSession(w http.ResponseWriter, r *http.Request) {
+	if principal, ok := middleware.PrincipalFromContext(r.Context()); ok && principal.SessionID != "" {
+		err := sa.endSessionPort.EndSession(r.Context(), principal.SessionID)
+		if err != nil && !errors.Is(err, errorx.ErrSessionNotFound) && !errors.Is(err, errorx.ErrSessionNotActive) {
+			problem.WriteError(w, r, err)
+			return
+		}
+	}
+	http.SetCookie(w, sa.sessionCookie("", time.Unix(0, 0)))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleCSRFToken handles HTTP GET requests for a CSRF token.
+//
+// It sets a new CSRF cookie and responds with HTTP 200 OK and the same token in the body,
+// which has to be sent in the CSRF header of every state-changing request of the session.
+func (sa *SessionApi) handleCSRFToken(w http.ResponseWriter, r *http.Request) {
+	token := sa.csrf.IssueToken(w)
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeResponse(w, r, http.StatusOK, csrfResponse{token})
+}
+
+// sessionCookie creates the session cookie with the configured attributes.
+func (sa *SessionApi) sessionCookie(value string, expires time.Time) *http.Cookie {
+	return &http.Cookie{
+		Name:     sa.cookie.Name,
+		Value:    value,
+		Path:     "/",
+		Domain:   sa.cookie.Domain,
+		Expires:  expires,
+		Secure:   sa.cookie.Secure,
+		HttpOnly: true,
+		SameSite: sa.cookie.SameSite,
+	}
+}
@@ -0,0 +1,11 @@
+package api
+
+import (
+	"user-auth-hexagonal-architecture/adapters/web/middleware"
+)
+
+// StreamingRoutes declares the routes of this package that stream responses for as long as the
+// client stays connected, so the request timeout does not apply to them.
+var StreamingRoutes = middleware.StreamingRoutes{
+	"GET /admin/events/stream": true,
+}
@@ -0,0 +1,148 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/adapters/web/validation"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// maxBatchTokens is the maximum number of tokens verified in a single batch request.
+const maxBatchTokens = 100
+
+// TokenApi handles HTTP requests for verifying access tokens on behalf of other services.
+type TokenApi struct {
+	verifyTokenPort    usecases.VerifyTokenPort
+	refreshSessionPort usecases.RefreshSessionPort
+}
+
+// refreshRequest represents the expected JSON structure for token refresh requests.
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// validate checks that a refresh token is given.
+func (rr *refreshRequest) validate(v *validation.Validator) {
+	v.Required("refresh_token", rr.RefreshToken)
+}
+
+// verifyBatchRequest represents the expected JSON structure for batch verification requests.
+type verifyBatchRequest struct {
+	Tokens []string `json:"tokens"`
+}
+
+// validate checks that between one and maxBatchTokens tokens are given.
+func (vr *verifyBatchRequest) validate(v *validation.Validator) {
+	switch {
+	case len(vr.Tokens) == 0:
+		v.Add("tokens", "required", "tokens is required")
+	case len(vr.Tokens) > maxBatchTokens:
+		v.Add("tokens", "too_long", fmt.Sprintf("tokens must not contain more than %d entries", maxBatchTokens))
+	}
+}
+
+// verifyBatchResponse represents the JSON structure of the batch verification results.
+type verifyBatchResponse struct {
+	Results []tokenVerificationResponse `json:"results"`
+}
+
+// tokenVerificationResponse represents the JSON structure of the verification result of a single token.
+type tokenVerificationResponse struct {
+	Active      bool       `json:"active"`
+	Subject     string     `json:"subject,omitempty"`
+	Roles       []string   `json:"roles,omitempty"`
+	Permissions []string   `json:"permissions,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+}
+
+// NewTokenApiAdapter creates a new TokenApi.
+//
+// Parameters:
+//   - verifyTokenPort: Port for verifying access tokens
+//   - refreshSessionPort: Port for renewing the tokens of a session with its refresh token
+//
+// Returns:
+//   - *TokenApi: A pointer to the newly created TokenApi
+func NewTokenApiAdapter(verifyTokenPort usecases.VerifyTokenPort, refreshSessionPort usecases.RefreshSessionPort) *TokenApi {
+	return &TokenApi{verifyTokenPort, refreshSessionPort}
+}
+
+// InitTokenRoutes sets up the HTTP routes for token verification.
+func (ta *TokenApi) InitTokenRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /token/verify-batch", ta.handleVerifyBatch)
+	mux.HandleFunc("POST /token/refresh", ta.handleRefresh)
+}
+
+// handleRefresh handles HTTP POST requests renewing the tokens of a session.
+//
+// The function expects a JSON body with the "refresh_token" issued at login or by the previous refresh.
+// On success, it responds with HTTP 200 OK and a new token pair in the format of the login response;
+// the presented refresh token cannot be used again:
+//
+//	{"access_token": "eyJhbGciOiJIUzI1NiIs...", "token_type": "Bearer", "expires_in": 86400, "refresh_token": "..."}
+//
+// On failure, it responds with an application/problem+json body and one of the following:
+//   - 400 Bad Request for invalid JSON format or a missing refresh token
+//   - 401 Unauthorized if the refresh token is invalid, its session has ended or it has already been used
+//   - 403 Forbidden if the account has been disabled, 423 Locked if it has been locked
+//   - 500 Internal Server Error for unexpected errors
+func (ta *TokenApi) handleRefresh(w http.ResponseWriter, r *http.Request) {
+	var request refreshRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	tokens, err := ta.refreshSessionPort.RefreshSession(r.Context(), request.RefreshToken)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	response := tokenResponse{
+		AccessToken:  tokens.AccessToken,
+		TokenType:    tokens.TokenType,
+		ExpiresIn:    int64(time.Until(tokens.ExpiresAt).Seconds()),
+		RefreshToken: tokens.RefreshToken,
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+// handleVerifyBatch handles HTTP POST requests verifying many access tokens in one round trip.
+//
+// The function expects a JSON body with a "tokens" list of at most 100 tokens, and responds with
+// HTTP 200 OK and one result per token in the same order. Tokens that are malformed, forged or
+// expired, or whose session was logged out of or revoked, are reported as inactive without further details:
+//
+//	{"results": [{"active": true, "subject": "alice", "roles": ["USER"], "expiresAt": "..."}, {"active": false}]}
+//
+// An empty or too long list is answered with 400 Bad Request.
+func (ta *TokenApi) handleVerifyBatch(w http.ResponseWriter, r *http.Request) {
+	var request verifyBatchRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	response := verifyBatchResponse{Results: make([]tokenVerificationResponse, 0, len(request.Tokens))}
+	for _, token := range request.Tokens {
+		principal, err := ta.verifyTokenPort.VerifyToken(r.Context(), token)
+		if err != nil {
+			response.Results = append(response.Results, tokenVerificationResponse{})
+			continue
+		}
+		response.Results = append(response.Results, tokenVerificationResponse{
+			Active:      true,
+			Subject:     principal.Subject,
+			Roles:       roleNames(principal.Roles),
+			Permissions: permissionNames(principal.Permissions),
+			ExpiresAt:   &principal.ExpiresAt,
+		})
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeResponse(w, r, http.StatusOK, response)
+}
@@ -2,35 +2,117 @@
 package api
 
 import (
-	"encoding/json"
-	"log"
 	"net/http"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/middleware"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/adapters/web/validation"
+	"user-auth-hexagonal-architecture/internal/domain"
 	"user-auth-hexagonal-architecture/internal/ports/usecases"
 )
 
 // UserApi handles HTTP requests for user operations.
 // It acts as an adapter between the HTTP layer and the application's use cases.
 type UserApi struct {
-	registerUserPort usecases.RegisterUserPort
-	loadUserPort     usecases.LoadUserPort
+	registerUserPort   usecases.RegisterUserPort
+	loadUserPort       usecases.LoadUserPort
+	getCurrentUserPort usecases.GetCurrentUserPort
+	deleteAccountPort  usecases.DeleteOwnAccountPort
+	checkUsernamePort  usecases.CheckUsernamePort
 }
 
-// userRequest represents the expected JSON structure for user registration requests.
+// userRequest represents the expected JSON structure for login requests.
 type userRequest struct {
-	Username string `json:"username"`
+	Username string       `json:"username"`
+	Password string       `json:"password"`
+	MfaCode  string       `json:"mfaCode"`
+	Consents []consentDTO `json:"consents"`
+}
+
+// validate only checks presence, so accounts created under older username rules can still log in.
+func (ur *userRequest) validate(v *validation.Validator) {
+	v.Required("username", ur.Username)
+	v.Required("password", ur.Password).MaxBytes("password", ur.Password, validation.MaxPasswordBytes)
+	validateConsents(v, ur.Consents)
+}
+
+// registerRequest represents the expected JSON structure for user registration requests.
+type registerRequest struct {
+	Username string       `json:"username"`
+	Email    string       `json:"email"`
+	Password string       `json:"password"`
+	Consents []consentDTO `json:"consents"`
+}
+
+// validate checks the format of the new username, the optional email and the password.
+func (rr *registerRequest) validate(v *validation.Validator) {
+	v.Username("username", rr.Username)
+	v.Email("email", rr.Email)
+	v.Required("password", rr.Password).MaxBytes("password", rr.Password, validation.MaxPasswordBytes)
+	validateConsents(v, rr.Consents)
+}
+
+// deleteAccountRequest represents the expected JSON structure for deleting the own account.
+type deleteAccountRequest struct {
 	Password string `json:"password"`
 }
 
+// validate checks that the deletion is confirmed with a password.
+func (dr *deleteAccountRequest) validate(v *validation.Validator) {
+	v.Required("password", dr.Password).MaxBytes("password", dr.Password, validation.MaxPasswordBytes)
+}
+
+// registerResponse represents the JSON structure returned after a successful registration.
+type registerResponse struct {
+	ID string `json:"id"`
+}
+
+// usernameAvailabilityResponse represents the JSON structure of a username availability check.
+type usernameAvailabilityResponse struct {
+	Username  string `json:"username"`
+	Available bool   `json:"available"`
+}
+
+// tokenResponse represents the JSON structure returned after a successful login.
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// passwordChangeRequiredResponse represents the JSON structure returned instead of a tokenResponse
+// when the user logged in with a temporary password.
+type passwordChangeRequiredResponse struct {
+	PasswordChangeRequired bool   `json:"password_change_required"`
+	PasswordChangeToken    string `json:"password_change_token"`
+	ExpiresIn              int64  `json:"expires_in"`
+}
+
+// profileResponse represents the JSON structure of the authenticated user's profile.
+type profileResponse struct {
+	ID          string     `json:"id"`
+	Username    string     `json:"username"`
+	Email       string     `json:"email,omitempty"`
+	Roles       []string   `json:"roles"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	LastLoginAt *time.Time `json:"lastLoginAt,omitempty"`
+	MfaEnabled  bool       `json:"mfaEnabled"`
+}
+
 // NewUserApiAdapter creates a new UserApi with the given use case ports.
 //
 // Parameters:
 //   - registerUserPort: Port for user registration use case
 //   - loadUserPort: Port for user loading use case
+//   - getCurrentUserPort: Port for reading the authenticated user's profile
+//   - deleteAccountPort: Port for deleting the authenticated user's account
+//   - checkUsernamePort: Port for checking whether a username can be registered
 //
 // Returns:
 //   - *UserApi: A pointer to the newly created UserApi
-func NewUserApiAdapter(registerUserPort usecases.RegisterUserPort, loadUserPort usecases.LoadUserPort) *UserApi {
-	return &UserApi{registerUserPort, loadUserPort}
+func NewUserApiAdapter(registerUserPort usecases.RegisterUserPort, loadUserPort usecases.LoadUserPort, getCurrentUserPort usecases.GetCurrentUserPort, deleteAccountPort usecases.DeleteOwnAccountPort, checkUsernamePort usecases.CheckUsernamePort) *UserApi {
+	return &UserApi{registerUserPort, loadUserPort, getCurrentUserPort, deleteAccountPort, checkUsernamePort}
 }
 
 // InitUserRoutes sets up the HTTP routes for user-related operations.
@@ -38,7 +120,10 @@ func NewUserApiAdapter(registerUserPort usecases.RegisterUserPort, loadUserPort
 // This method registers the necessary HTTP handlers with the given ServeMux.
 func (ua *UserApi) InitUserRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("POST /user/register", ua.handleUserRegister)
-	//TODO: add route for loading a user
+	mux.HandleFunc("POST /user/login", ua.handleLoadUser)
+	mux.HandleFunc("GET /user/username-available", ua.handleCheckUsername)
+	mux.HandleFunc("GET /user/me", ua.handleGetCurrentUser)
+	mux.HandleFunc("DELETE /user/me", ua.handleDeleteAccount)
 }
 
 // handleUserRegister handles HTTP POST requests for user registration.
@@ -46,32 +131,55 @@ func (ua *UserApi) InitUserRoutes(mux *http.ServeMux) {
 // It decodes the JSON request body, calls the RegisterUser use case,
 // and responds with appropriate HTTP status codes.
 //
-// The function expects a JSON body with "username" and "password" fields.
-// On success, it responds with HTTP 201 Created.
-// On failure, it responds with either 400 Bad Request for invalid JSON
-// or 500 Internal Server Error for registration failures.
+// The function expects a JSON body with "username" and "password" fields, an optional "email" and the
+// accepted "consents", e.g. [{"document": "terms", "version": "2026-10"}].
+// On success, it responds with HTTP 201 Created and the id of the created user, e.g. {"id": "66f1..."}.
+// On failure, it responds with an application/problem+json body and either
+// 400 Bad Request for invalid JSON or fields (listed in "errors"), 409 Conflict if the username is taken,
+// 403 Forbidden if the current terms or privacy policy are not accepted,
+// or 500 Internal Server Error for other registration failures.
 //
 // Parameters:
 //   - w: HTTP ResponseWriter to write the response
 //   - r: HTTP Request containing the registration data
 //
-// Note: This method logs errors but does not return them to the caller.
+// Note: Unexpected errors are logged but not returned to the caller.
 func (ua *UserApi) handleUserRegister(w http.ResponseWriter, r *http.Request) {
-	var userRequest userRequest
-	err := json.NewDecoder(r.Body).Decode(&userRequest)
+	var registerRequest registerRequest
+	if !decodeRequest(w, r, &registerRequest) {
+		return
+	}
+
+	userID, err := ua.registerUserPort.RegisterUser(deviceContext(r), registerRequest.Username, registerRequest.Password, registerRequest.Email, toConsentRefs(registerRequest.Consents))
 	if err != nil {
-		log.Printf("Error registering user: %v", err)
-		http.Error(w, "Invalid JSON format", http.StatusBadRequest)
+		problem.WriteError(w, r, err)
 		return
 	}
+	writeResponse(w, r, http.StatusCreated, registerResponse{ID: userID})
+}
 
-	err = ua.registerUserPort.RegisterUser(userRequest.Username, userRequest.Password)
+// handleCheckUsername handles HTTP GET requests checking whether a username can be registered,
+// e.g. GET /user/username-available?username=alice while the user types.
+//
+// On success, it responds with HTTP 200 OK and {"username": "alice", "available": true}, where the
+// username is given in its normalized form. Usernames violating the username policy are answered
+// with 400 Bad Request and the violated rule as detail. Responses are delayed by a random amount
+// of time and rate limited per client, so the endpoint cannot be used to enumerate accounts quickly.
+func (ua *UserApi) handleCheckUsername(w http.ResponseWriter, r *http.Request) {
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		problem.Write(w, r, problem.InvalidRequest, "the username query parameter is required")
+		return
+	}
+
+	available, err := ua.checkUsernamePort.CheckUsername(r.Context(), username)
 	if err != nil {
-		log.Printf("Error registering user: %v", err)
-		http.Error(w, "Registering new user failed", http.StatusInternalServerError)
+		problem.WriteError(w, r, err)
 		return
 	}
-	w.WriteHeader(http.StatusCreated)
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeResponse(w, r, http.StatusOK, usernameAvailabilityResponse{Username: domain.NormalizeUsername(username).String(), Available: available})
 }
 
 // handleLoadUser handles HTTP POST requests for user authentication.
@@ -79,25 +187,127 @@ func (ua *UserApi) handleUserRegister(w http.ResponseWriter, r *http.Request) {
 // This function processes user login attempts by decoding the JSON request body,
 // calling the LoadUser use case, and responding with appropriate HTTP status codes.
 //
-// The function expects a JSON body with "username" and "password" fields.
+// The function expects a JSON body with "username" and "password" fields and optionally the accepted
+// "consents", which are required while the user has not accepted the current terms and privacy policy,
+// and "mfaCode", a current one-time code required for accounts with multi-factor authentication.
 // On successful authentication, it responds with HTTP 200 OK and a JWT token in the response body.
-// On failure, it responds with one of the following:
-//   - 400 Bad Request for invalid JSON format
-//   - 401 Unauthorized for invalid credentials
+// On failure, it responds with an application/problem+json body and one of the following:
+//   - 400 Bad Request for invalid JSON format or a missing username or password (listed in "errors")
+//   - 401 Unauthorized for invalid credentials, or a missing or wrong one-time code
+//   - 403 Forbidden if the account has been disabled or the current terms and privacy policy have not been accepted
+//   - 423 Locked if the account has been locked
 //   - 500 Internal Server Error for unexpected errors during the authentication process
 //
 // Parameters:
 //   - w: HTTP ResponseWriter to write the response
 //   - r: HTTP Request containing the login credentials
 //
-// The response body for a successful login will contain a JSON object with a "token" field:
+// The response body for a successful login follows the OAuth 2.0 token response format,
+// with "refresh_token" only present when refresh tokens are enabled:
 //
-//	{"token": "eyJhbGciOiJIUzI1NiIs..."}
+//	{"access_token": "eyJhbGciOiJIUzI1NiIs...", "token_type": "Bearer", "expires_in": 86400}
+//
+// If the password was set by an administrator, no access token is issued. The response is HTTP 200 OK
+// with a token that is only accepted by POST /user/password/change, after which the user logs in again:
+//
+//	{"password_change_required": true, "password_change_token": "eyJhbGciOiJIUzI1NiIs...", "expires_in": 600}
 //
 // Note:
 //   - This method logs errors but does not return them to the caller to avoid
 //     leaking sensitive information.
 //   - The actual JWT token generation is handled by the LoadUser use case.
 func (ua *UserApi) handleLoadUser(w http.ResponseWriter, r *http.Request) {
-	//TODO: implement handler for api route for loading a user
+	var userRequest userRequest
+	if !decodeRequest(w, r, &userRequest) {
+		return
+	}
+
+	tokens, err := ua.loadUserPort.LoadUser(deviceContext(r), userRequest.Username, userRequest.Password, userRequest.MfaCode, domain.LoginMethodPassword, toConsentRefs(userRequest.Consents))
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	if tokens.PasswordChangeToken != "" {
+		writeResponse(w, r, http.StatusOK, passwordChangeRequiredResponse{
+			PasswordChangeRequired: true,
+			PasswordChangeToken:    tokens.PasswordChangeToken,
+			ExpiresIn:              int64(time.Until(tokens.ExpiresAt).Seconds()),
+		})
+		return
+	}
+
+	response := tokenResponse{
+		AccessToken:  tokens.AccessToken,
+		TokenType:    tokens.TokenType,
+		ExpiresIn:    int64(time.Until(tokens.ExpiresAt).Seconds()),
+		RefreshToken: tokens.RefreshToken,
+	}
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+// handleGetCurrentUser handles HTTP GET requests for the authenticated user's profile.
+//
+// The user is identified by the subject of the bearer token, which the authentication
+// middleware has stored in the request context.
+// On success, it responds with HTTP 200 OK and the profile as JSON.
+// On failure, it responds with an application/problem+json body and one of the following:
+//   - 401 Unauthorized if the request is not authenticated
+//   - 404 Not Found if the account of the token subject no longer exists
+//   - 500 Internal Server Error for unexpected errors
+func (ua *UserApi) handleGetCurrentUser(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.AuthenticationRequired, "")
+		return
+	}
+
+	user, err := ua.getCurrentUserPort.GetCurrentUser(r.Context(), principal.Subject)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+
+	response := profileResponse{
+		ID:         user.ID,
+		Username:   user.Username.String(),
+		Email:      user.Email.String(),
+		Roles:      roleNames(user.Roles),
+		CreatedAt:  user.CreatedAt,
+		MfaEnabled: user.MfaEnabled,
+	}
+	if !user.LastLoginAt.IsZero() {
+		response.LastLoginAt = &user.LastLoginAt
+	}
+
+	writeResponse(w, r, http.StatusOK, response)
+}
+
+// handleDeleteAccount handles HTTP DELETE requests for the authenticated user's account.
+//
+// The function expects a JSON body with the current "password" confirming the deletion. The account
+// is soft-deleted, all its sessions are revoked and it is purged after the deletion grace period.
+// On success, it responds with HTTP 204 No Content.
+// On failure, it responds with an application/problem+json body and one of the following:
+//   - 400 Bad Request if the password is missing
+//   - 401 Unauthorized if the request is not authenticated or the password is wrong
+//   - 404 Not Found if the account of the token subject no longer exists
+//   - 500 Internal Server Error for unexpected errors
+func (ua *UserApi) handleDeleteAccount(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.AuthenticationRequired, "")
+		return
+	}
+	var request deleteAccountRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	if err := ua.deleteAccountPort.DeleteOwnAccount(r.Context(), principal.Subject, request.Password); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
 }
@@ -0,0 +1,135 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"net/http"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/middleware"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// UserSessionsApi handles HTTP requests for reviewing and ending active sessions, by users for
+// their own sessions and by support staff for the sessions of any user.
+// It acts as an adapter between the HTTP layer and the session use cases.
+type UserSessionsApi struct {
+	userSessionsPort  usecases.UserSessionsPort
+	adminSessionsPort usecases.AdminSessionsPort
+}
+
+// sessionResponse represents the JSON structure of an active session. Current marks the session
+// the request itself is made with.
+type sessionResponse struct {
+	ID         string    `json:"id"`
+	Current    bool      `json:"current"`
+	UserAgent  string    `json:"userAgent,omitempty"`
+	IPAddress  string    `json:"ipAddress,omitempty"`
+	Country    string    `json:"country,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+// NewUserSessionsApiAdapter creates a new UserSessionsApi with the given use case ports.
+//
+// Parameters:
+//   - userSessionsPort: Port for listing and revoking the sessions of the authenticated user
+//   - adminSessionsPort: Port for listing and revoking the sessions of any user
+//
+// Returns:
+//   - *UserSessionsApi: A pointer to the newly created UserSessionsApi
+func NewUserSessionsApiAdapter(userSessionsPort usecases.UserSessionsPort, adminSessionsPort usecases.AdminSessionsPort) *UserSessionsApi {
+	return &UserSessionsApi{userSessionsPort, adminSessionsPort}
+}
+
+// InitUserSessionsRoutes sets up the HTTP routes for session management.
+//
+// Access control is declared in RouteAccess.
+func (sa *UserSessionsApi) InitUserSessionsRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /user/sessions", sa.handleListMySessions)
+	mux.HandleFunc("DELETE /user/sessions/{id}", sa.handleRevokeMySession)
+	mux.HandleFunc("GET /admin/users/{id}/sessions", sa.handleListUserSessions)
+	mux.HandleFunc("DELETE /admin/users/{id}/sessions/{sessionId}", sa.handleRevokeUserSession)
+}
+
+// handleListMySessions handles HTTP GET requests for the active sessions of the authenticated user.
+//
+// It responds with HTTP 200 OK and the sessions, the most recent first, with the session of the
+// request marked as "current".
+func (sa *UserSessionsApi) handleListMySessions(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.AuthenticationRequired, "")
+		return
+	}
+
+	sessions, err := sa.userSessionsPort.ListMySessions(r.Context(), principal.Subject)
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, toSessionResponses(sessions, principal.SessionID))
+}
+
+// handleRevokeMySession handles HTTP DELETE requests ending a session of the authenticated user.
+//
+// On success, it responds with HTTP 204 No Content and the access token of the session is no longer
+// accepted. It responds with 404 Not Found if the user has no such session and 409 Conflict if the
+// session has already ended.
+func (sa *UserSessionsApi) handleRevokeMySession(w http.ResponseWriter, r *http.Request) {
+	principal, ok := middleware.PrincipalFromContext(r.Context())
+	if !ok {
+		problem.Write(w, r, problem.AuthenticationRequired, "")
+		return
+	}
+
+	if err := sa.userSessionsPort.RevokeSession(r.Context(), principal.Subject, r.PathValue("id")); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleListUserSessions handles HTTP GET requests for the active sessions of a user.
+//
+// It responds with HTTP 200 OK and the sessions, the most recent first, or 404 Not Found if the
+// user does not exist.
+func (sa *UserSessionsApi) handleListUserSessions(w http.ResponseWriter, r *http.Request) {
+	sessions, err := sa.adminSessionsPort.ListUserSessions(r.Context(), r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, toSessionResponses(sessions, ""))
+}
+
+// handleRevokeUserSession handles HTTP DELETE requests ending a session of a user.
+//
+// On success, it responds with HTTP 204 No Content. It responds with 404 Not Found if the user or
+// the session does not exist and 409 Conflict if the session has already ended.
+func (sa *UserSessionsApi) handleRevokeUserSession(w http.ResponseWriter, r *http.Request) {
+	if err := sa.adminSessionsPort.RevokeUserSession(r.Context(), r.PathValue("id"), r.PathValue("sessionId")); err != nil {
+		problem.WriteError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// toSessionResponses converts sessions into their JSON representation.
+func toSessionResponses(sessions []domain.Session, currentID string) []sessionResponse {
+	response := make([]sessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		response = append(response, sessionResponse{
+			ID:         s.ID,
+			Current:    currentID != "" && s.ID == currentID,
+			UserAgent:  s.Device.UserAgent,
+			IPAddress:  s.Device.IPAddress,
+			Country:    s.Device.Country,
+			CreatedAt:  s.CreatedAt,
+			LastSeenAt: s.LastSeenAt,
+			ExpiresAt:  s.ExpiresAt,
+		})
+	}
+	return response
+}
@@ -0,0 +1,102 @@
+// Package api provides HTTP handlers for domain-related operations in a hexagonal architecture.
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WellKnownApi serves the discovery documents below /.well-known.
+//
+// The documents change only when the deployment or the signing keys change, so they are sent with
+// a public max-age and an ETag, which lets verifiers cache them and revalidate cheaply. The max-age
+// must stay below the grace period during which retired signing keys remain published, so no
+// verifier keeps trusting a retired key for longer than that.
+type WellKnownApi struct {
+	issuer string
+	maxAge time.Duration
+}
+
+// authorizationServerMetadata represents the RFC 8414 authorization server metadata.
+type authorizationServerMetadata struct {
+	Issuer                            string   `json:"issuer"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	JwksURI                           string   `json:"jwks_uri"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	TokenSigningAlgValuesSupported    []string `json:"token_signing_alg_values_supported"`
+}
+
+// jwks represents an RFC 7517 JSON Web Key Set.
+type jwks struct {
+	Keys []map[string]string `json:"keys"`
+}
+
+// NewWellKnownApiAdapter creates a new WellKnownApi.
+//
+// Parameters:
+//   - issuer: The public base URL of the service, e.g. "https://auth.example.com"; empty derives it from the request
+//   - maxAge: How long verifiers may cache the documents
+//
+// Returns:
+//   - *WellKnownApi: A pointer to the newly created WellKnownApi
+func NewWellKnownApiAdapter(issuer string, maxAge time.Duration) *WellKnownApi {
+	return &WellKnownApi{strings.TrimSuffix(issuer, "/"), maxAge}
+}
+
+// InitWellKnownRoutes sets up the HTTP routes of the discovery documents.
+func (wk *WellKnownApi) InitWellKnownRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /.well-known/oauth-authorization-server", wk.handleMetadata)
+	mux.HandleFunc("GET /.well-known/jwks.json", wk.handleJWKS)
+}
+
+// handleMetadata handles HTTP GET requests for the authorization server metadata.
+//
+// It responds with HTTP 200 OK and the endpoints and algorithms of the service.
+func (wk *WellKnownApi) handleMetadata(w http.ResponseWriter, r *http.Request) {
+	issuer := wk.issuerOf(r)
+	metadata := authorizationServerMetadata{
+		Issuer:                            issuer,
+		TokenEndpoint:                     issuer + "/api/v1/user/login",
+		JwksURI:                           issuer + "/.well-known/jwks.json",
+		GrantTypesSupported:               []string{"password"},
+		ResponseTypesSupported:            []string{"token"},
+		TokenEndpointAuthMethodsSupported: []string{"none"},
+		TokenSigningAlgValuesSupported:    []string{"HS256"},
+	}
+
+	wk.setCacheControl(w)
+	writeResponse(w, r, http.StatusOK, metadata)
+}
+
+// handleJWKS handles HTTP GET requests for the public signing keys.
+//
+// Access tokens are currently signed with a shared HMAC secret, which must never be published, so
+// the key set is empty. Services verify tokens through POST /api/v1/token/verify-batch or the gRPC
+// VerifyToken method instead.
+func (wk *WellKnownApi) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	wk.setCacheControl(w)
+	writeResponse(w, r, http.StatusOK, jwks{Keys: []map[string]string{}})
+}
+
+// setCacheControl allows shared caches to keep the documents for maxAge and to serve a stale copy
+// for another tenth of it while revalidating in the background.
+func (wk *WellKnownApi) setCacheControl(w http.ResponseWriter) {
+	maxAge := int(wk.maxAge.Seconds())
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d, stale-while-revalidate=%d", maxAge, maxAge/10))
+}
+
+// issuerOf returns the configured issuer, or the base URL the request was sent to.
+func (wk *WellKnownApi) issuerOf(r *http.Request) string {
+	if wk.issuer != "" {
+		return wk.issuer
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
@@ -0,0 +1,41 @@
+// Package console serves the embedded admin web console.
+//
+// The console is a static HTML/JS application that runs in the operator's browser and talks
+// to the admin API with the operator's own access token. It holds no privileges of its own.
+package console
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// contentSecurityPolicy allows the console to load its own assets and call the API of the same origin only.
+const contentSecurityPolicy = "default-src 'none'; script-src 'self'; style-src 'self'; connect-src 'self'; img-src 'self'; frame-ancestors 'none'; base-uri 'none'; form-action 'none'"
+
+//go:embed static
+var static embed.FS
+
+// Handler returns the handler serving the console. It has to be registered for "GET /admin/{$}"
+// and "GET /admin/assets/", so the admin API routes below /admin stay untouched.
+//
+// The console needs scripts, which the restrictive policy of the API forbids, so the
+// Content-Security-Policy header is replaced by one permitting same-origin assets.
+//
+// Returns:
+//   - http.Handler: The handler
+func Handler() http.Handler {
+	assets, _ := fs.Sub(static, "static")
+	files := http.FileServerFS(assets)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", contentSecurityPolicy)
+		w.Header().Set("Cache-Control", "no-cache")
+
+		if r.URL.Path == "/admin/" {
+			http.ServeFileFS(w, r, assets, "index.html")
+			return
+		}
+		http.StripPrefix("/admin/assets", files).ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,217 @@
+"use strict";
+
+// The console keeps the access token for the lifetime of the browser tab only.
+const api = "/api/v1";
+const pageSize = 20;
+let roles = ["USER", "ADMIN"];
+
+let page = 1;
+let total = 0;
+
+const $ = (id) => document.getElementById(id);
+
+function token() {
+  return sessionStorage.getItem("token");
+}
+
+function showMessage(text) {
+  $("message").textContent = text;
+  if (text) {
+    setTimeout(() => { if ($("message").textContent === text) $("message").textContent = ""; }, 5000);
+  }
+}
+
+// request calls the API with the stored token and turns problem responses into errors.
+async function request(method, path, body) {
+  const headers = { "Accept": "application/json" };
+  if (token()) headers["Authorization"] = "Bearer " + token();
+  if (body !== undefined) headers["Content-Type"] = "application/json";
+
+  const response = await fetch(api + path, { method, headers, body: body === undefined ? undefined : JSON.stringify(body) });
+  if (response.status === 401 && token()) {
+    logout();
+    throw new Error("Session expired, please log in again");
+  }
+  if (!response.ok) {
+    let problem = {};
+    try { problem = await response.json(); } catch (e) { /* not a problem response */ }
+    throw new Error(problem.detail || problem.title || response.statusText);
+  }
+  return response.status === 204 ? null : response.json();
+}
+
+function showView(name) {
+  for (const view of ["login-view", "users-view", "timeline-view"]) {
+    $(view).hidden = view !== name;
+  }
+  $("logout").hidden = name === "login-view";
+}
+
+function logout() {
+  sessionStorage.removeItem("token");
+  showView("login-view");
+}
+
+function formatTime(value) {
+  return value ? new Date(value).toLocaleString() : "";
+}
+
+function cell(row, text) {
+  const td = document.createElement("td");
+  td.textContent = text;
+  row.appendChild(td);
+  return td;
+}
+
+function button(parent, label, onClick) {
+  const b = document.createElement("button");
+  b.textContent = label;
+  b.addEventListener("click", onClick);
+  parent.appendChild(b);
+  return b;
+}
+
+async function loadUsers() {
+  const form = new FormData($("search-form"));
+  const query = new URLSearchParams({ page, pageSize });
+  for (const [key, value] of form) {
+    if (value) query.set(key, value);
+  }
+
+  try {
+    roles = (await request("GET", "/admin/roles")).map((role) => role.name);
+    const result = await request("GET", "/admin/users?" + query);
+    total = result.total;
+    renderUsers(result.items);
+  } catch (e) {
+    showMessage(e.message);
+  }
+}
+
+function renderUsers(users) {
+  const tbody = $("users");
+  tbody.replaceChildren();
+
+  for (const user of users) {
+    const row = document.createElement("tr");
+    cell(row, user.username);
+    cell(row, user.status);
+
+    const roleCell = cell(row, "");
+    const select = document.createElement("select");
+    for (const role of roles) {
+      select.add(new Option(role, role, false, user.roles.includes(role)));
+    }
+    select.addEventListener("change", () => run(
+      () => request("PUT", `/admin/users/${encodeURIComponent(user.id)}/roles`, { roles: [select.value] }),
+      `Role of ${user.username} changed to ${select.value}`));
+    roleCell.appendChild(select);
+
+    cell(row, formatTime(user.createdAt));
+    cell(row, formatTime(user.lastLoginAt));
+
+    const actions = cell(row, "");
+    if (user.status === "DISABLED") {
+      button(actions, "Unlock", () => run(
+        () => request("POST", `/admin/users/${encodeURIComponent(user.id)}/enable`), `${user.username} unlocked`));
+    } else {
+      button(actions, "Lock", () => run(
+        () => request("POST", `/admin/users/${encodeURIComponent(user.id)}/disable`), `${user.username} locked`));
+    }
+    button(actions, "Audit", () => loadTimeline(user));
+
+    tbody.appendChild(row);
+  }
+
+  const pages = Math.max(1, Math.ceil(total / pageSize));
+  $("page-info").textContent = `Page ${page} of ${pages} (${total} users)`;
+  $("previous-page").disabled = page <= 1;
+  $("next-page").disabled = page >= pages;
+}
+
+// run performs an action, reports its outcome and refreshes the user list.
+async function run(action, success) {
+  try {
+    await action();
+    showMessage(success);
+  } catch (e) {
+    showMessage(e.message);
+  }
+  loadUsers();
+}
+
+async function loadTimeline(user) {
+  let timeline;
+  try {
+    timeline = await request("GET", `/admin/users/${encodeURIComponent(user.id)}/security-timeline`);
+  } catch (e) {
+    showMessage(e.message);
+    return;
+  }
+
+  $("timeline-user").textContent = user.username;
+
+  const summary = $("timeline-summary");
+  summary.replaceChildren();
+  const facts = [
+    ["Password changed", formatTime(timeline.PasswordChangedAt)],
+    ["Hash algorithm", timeline.HashAlgorithm],
+    ["MFA methods", (timeline.MfaMethods || []).join(", ") || "none"],
+    ["Locked", timeline.Locked ? `yes ${timeline.LockedUntil ? "until " + formatTime(timeline.LockedUntil) : ""}` : "no"],
+    ["Lockouts", String(timeline.LockoutCount)],
+    ["Stream consistent", timeline.ConsistentSequence ? "yes" : "NO - sequence gaps detected"],
+  ];
+  for (const [term, description] of facts) {
+    const dt = document.createElement("dt");
+    dt.textContent = term;
+    const dd = document.createElement("dd");
+    dd.textContent = description;
+    summary.append(dt, dd);
+  }
+
+  const tbody = $("timeline-events");
+  tbody.replaceChildren();
+  for (const event of timeline.Events || []) {
+    const row = document.createElement("tr");
+    cell(row, String(event.Sequence));
+    cell(row, event.Type);
+    cell(row, formatTime(event.OccurredAt));
+    cell(row, Object.entries(event.Details || {}).map(([k, v]) => `${k}=${v}`).join(", "));
+    tbody.appendChild(row);
+  }
+
+  showView("timeline-view");
+}
+
+document.addEventListener("DOMContentLoaded", () => {
+  $("login-form").addEventListener("submit", async (e) => {
+    e.preventDefault();
+    const form = new FormData(e.target);
+    try {
+      const tokens = await request("POST", "/user/login", { username: form.get("username"), password: form.get("password"), mfaCode: form.get("mfaCode") });
+      sessionStorage.setItem("token", tokens.access_token);
+      e.target.reset();
+      showView("users-view");
+      loadUsers();
+    } catch (err) {
+      showMessage(err.message);
+    }
+  });
+
+  $("search-form").addEventListener("submit", (e) => {
+    e.preventDefault();
+    page = 1;
+    loadUsers();
+  });
+  $("previous-page").addEventListener("click", () => { page--; loadUsers(); });
+  $("next-page").addEventListener("click", () => { page++; loadUsers(); });
+  $("close-timeline").addEventListener("click", () => showView("users-view"));
+  $("logout").addEventListener("click", logout);
+
+  if (token()) {
+    showView("users-view");
+    loadUsers();
+  } else {
+    showView("login-view");
+  }
+});
@@ -0,0 +1,101 @@
+// Package diagnostics serves the Go runtime profiles and expvar variables for profiling a running
+// instance, e.g. the CPU time spent hashing passwords or goroutines leaked by the token subsystem.
+//
+// The handler is meant for a separate listener that is not exposed with the API. It has its own
+// access control, independent of user accounts: requests must come from the local host, present the
+// diagnostics token, or both.
+package diagnostics
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"expvar"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"strings"
+)
+
+// Config configures the access to the diagnostics endpoints.
+type Config struct {
+	// Token must be presented as bearer token, empty allows requests without a token.
+	Token string
+	// LocalhostOnly rejects requests from other hosts than the local one.
+	LocalhostOnly bool
+}
+
+// hiddenVars are the expvar variables that are not served, as the command line carries secrets
+// passed as flags.
+var hiddenVars = map[string]bool{"cmdline": true}
+
+func init() {
+	expvar.Publish("goroutines", expvar.Func(func() any { return runtime.NumGoroutine() }))
+}
+
+// Handler returns the handler serving the profiles under /debug/pprof/ and the expvar variables
+// under /debug/vars.
+//
+// The command line is served by neither, unlike by the handlers of the standard library, as it
+// may contain secrets.
+//
+// Parameters:
+//   - config: The access configuration
+//
+// Returns:
+//   - http.Handler: The handler
+func Handler(config Config) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /debug/pprof/", pprof.Index)
+	mux.HandleFunc("GET /debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("GET /debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("POST /debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("GET /debug/pprof/trace", pprof.Trace)
+	mux.Handle("GET /debug/pprof/cmdline", http.NotFoundHandler())
+	mux.HandleFunc("GET /debug/vars", serveVars)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if config.LocalhostOnly && !fromLoopback(r) {
+			http.Error(w, "diagnostics are only available from the local host", http.StatusForbidden)
+			return
+		}
+		if config.Token != "" && !validToken(r, config.Token) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="diagnostics"`)
+			http.Error(w, "diagnostics token required", http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// serveVars writes the published expvar variables as a JSON object, leaving out the hidden ones.
+func serveVars(w http.ResponseWriter, _ *http.Request) {
+	vars := make(map[string]json.RawMessage)
+	expvar.Do(func(kv expvar.KeyValue) {
+		if !hiddenVars[kv.Key] {
+			vars[kv.Key] = json.RawMessage(kv.Value.String())
+		}
+	})
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(vars)
+}
+
+// fromLoopback reports whether a request was made from a loopback address.
+func fromLoopback(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// validToken compares the bearer token of a request with the configured one in constant time.
+func validToken(r *http.Request, token string) bool {
+	scheme, presented, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimSpace(presented)), []byte(token)) == 1
+}
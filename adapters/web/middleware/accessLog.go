@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"go.opentelemetry.io/otel/trace"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"user-auth-hexagonal-architecture/internal/logging"
+	"user-auth-hexagonal-architecture/internal/requestid"
+)
+
+// logger writes the log records of this package.
+var logger = logging.Component("http")
+
+// redacted replaces the values of sensitive query parameters in access logs.
+const redacted = "REDACTED"
+
+// AccessLogConfig configures HTTP access logging.
+type AccessLogConfig struct {
+	// SampleRate is the fraction of successful requests that are logged, between 0 and 1.
+	// Requests answered with a 4xx or 5xx status are always logged.
+	SampleRate float64
+	// RedactedParams lists query parameters whose values must never appear in logs.
+	// Matching is case-insensitive.
+	RedactedParams []string
+}
+
+// DefaultAccessLogConfig returns a configuration that logs every request and redacts the
+// query parameters commonly used to carry credentials.
+func DefaultAccessLogConfig() AccessLogConfig {
+	return AccessLogConfig{
+		SampleRate:     1,
+		RedactedParams: []string{"password", "token", "access_token", "refresh_token", "id_token", "code", "secret", "api_key"},
+	}
+}
+
+// accessLogWriter records the status code and the number of bytes written to a response.
+type accessLogWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+// WriteHeader records the status code before passing it on.
+func (w *accessLogWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the number of bytes written. A write without a prior WriteHeader implies 200 OK.
+func (w *accessLogWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap exposes the wrapped writer to http.ResponseController.
+func (w *accessLogWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// AccessLog returns middleware that writes one structured log record per request.
+//
+// Each record carries the method, path, status, latency, response size, request id, client IP and, for
+// authenticated and traced requests, the user id and trace id. It therefore has to run inside
+// the RequestID, tracing and Authenticate middleware. Values of the configured query parameters are redacted.
+// Server errors are logged at error level, client errors at warn level, everything else at info level.
+//
+// Parameters:
+//   - logger: The logger to write the records to
+//   - config: The access log configuration
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware
+func AccessLog(logger *slog.Logger, config AccessLogConfig) func(http.Handler) http.Handler {
+	redactedParams := make(map[string]bool, len(config.RedactedParams))
+	for _, param := range config.RedactedParams {
+		redactedParams[strings.ToLower(param)] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := &accessLogWriter{ResponseWriter: w}
+
+			next.ServeHTTP(recorder, r)
+
+			status := recorder.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if status < http.StatusBadRequest && !sampled(config.SampleRate) {
+				return
+			}
+
+			level := slog.LevelInfo
+			switch {
+			case status >= http.StatusInternalServerError:
+				level = slog.LevelError
+			case status >= http.StatusBadRequest:
+				level = slog.LevelWarn
+			}
+
+			attrs := []slog.Attr{
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", status),
+				slog.Duration("latency", time.Since(start)),
+				slog.Int64("bytes", recorder.bytes),
+				slog.String("request_id", requestid.FromContext(r.Context())),
+			}
+			if ip, ok := ByClientIP(r); ok {
+				attrs = append(attrs, slog.String("client_ip", ip))
+			}
+			if r.URL.RawQuery != "" {
+				attrs = append(attrs, slog.String("query", redactQuery(r.URL.Query(), redactedParams)))
+			}
+			if principal, ok := PrincipalFromContext(r.Context()); ok {
+				attrs = append(attrs, slog.String("user_id", principal.Subject))
+			}
+			if spanContext := trace.SpanContextFromContext(r.Context()); spanContext.HasTraceID() {
+				attrs = append(attrs, slog.String("trace_id", spanContext.TraceID().String()))
+			}
+
+			logger.LogAttrs(r.Context(), level, "http request", attrs...)
+		})
+	}
+}
+
+// sampled decides whether a request is logged for the given sample rate.
+func sampled(rate float64) bool {
+	if rate >= 1 {
+		return true
+	}
+	return rate > 0 && rand.Float64() < rate
+}
+
+// redactQuery encodes the query with the values of all redacted parameters replaced.
+func redactQuery(query url.Values, redactedParams map[string]bool) string {
+	for key, values := range query {
+		if !redactedParams[strings.ToLower(key)] {
+			continue
+		}
+		for i := range values {
+			values[i] = redacted
+		}
+	}
+	return query.Encode()
+}
@@ -0,0 +1,88 @@
+// Package middleware provides HTTP middleware for the web adapter.
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/logging"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// principalKey is the context key under which the authenticated Principal is stored.
+type principalKey struct{}
+
+// Principal is the authenticated subject of a request.
+type Principal = domain.Principal
+
+// PrincipalFromContext returns the authenticated principal of the request, if any.
+//
+// Parameters:
+//   - ctx: The request context
+//
+// Returns:
+//   - Principal: The authenticated principal
+//   - bool: false if the request is not authenticated
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
+
+// Authenticate returns middleware that verifies bearer tokens.
+//
+// A request carrying a valid "Authorization: Bearer <jwt>" header gets its Principal stored in
+// the request context. Requests without or with an invalid token pass through unauthenticated;
+// rejecting them is left to the authorization middleware, so public routes keep working.
+//
+// If a session cookie name is given, requests without an Authorization header are authenticated
+// with the token stored in that cookie instead. Such requests must additionally be guarded by
+// CSRFProtection.
+//
+// Parameters:
+//   - verifyTokenPort: Port for verifying access tokens
+//   - sessionCookie: The name of the session cookie, empty if cookie sessions are disabled
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware
+func Authenticate(verifyTokenPort usecases.VerifyTokenPort, sessionCookie string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerToken(r)
+			if !ok && sessionCookie != "" && r.Header.Get("Authorization") == "" {
+				token, ok = cookieToken(r, sessionCookie)
+			}
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			principal, err := verifyTokenPort.VerifyToken(r.Context(), token)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := logging.WithUser(context.WithValue(r.Context(), principalKey{}, principal), principal.Subject)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// cookieToken extracts the token from the session cookie.
+func cookieToken(r *http.Request, name string) (string, bool) {
+	cookie, err := r.Cookie(name)
+	if err != nil || cookie.Value == "" {
+		return "", false
+	}
+	return cookie.Value, true
+}
+
+// bearerToken extracts the token from the Authorization header.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
@@ -0,0 +1,236 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// AccessRule declares who may call a route.
+//
+// The zero value requires an authenticated subject. Roles are alternatives: holding any of
+// them is sufficient. A Permission, if set, must additionally be granted by one of the
+// principal's roles.
+type AccessRule struct {
+	Public     bool
+	Roles      []domain.Role
+	Permission domain.Permission
+}
+
+// RouteAccess maps http.ServeMux patterns (e.g. "GET /user/me") to their access rules.
+// Routes missing from the mapping require an authenticated subject.
+type RouteAccess map[string]AccessRule
+
+// Public returns a rule that admits anyone.
+func Public() AccessRule { return AccessRule{Public: true} }
+
+// Authenticated returns a rule that admits any authenticated subject.
+func Authenticated() AccessRule { return AccessRule{} }
+
+// Role returns a rule that admits subjects holding any of the given roles.
+func Role(roles ...domain.Role) AccessRule { return AccessRule{Roles: roles} }
+
+// Permission returns a rule that admits subjects whose roles grant the given permission.
+func Permission(permission domain.Permission) AccessRule { return AccessRule{Permission: permission} }
+
+// Authorizer enforces access rules based on the principal stored by Authenticate.
+//
+// Permissions are resolved from the principal's roles with the current role registry rather
+// than taken from the token, so changed role definitions apply to tokens issued before.
+//
+// Before the role-based rule is checked, the attribute-based access policies are consulted:
+// a deny decision rejects the request and an allow decision admits it, whatever the rule says.
+// Only if no policy applies does the rule decide. Routes requiring the permission to manage
+// policies are exempt from policies, so a faulty policy cannot lock administrators out of
+// repairing it.
+type Authorizer struct {
+	roleRegistry usecases.RoleRegistryPort
+	policies     usecases.AuthorizePort
+}
+
+// NewAuthorizer creates a new Authorizer.
+//
+// Parameters:
+//   - roleRegistry: Port for the permissions granted by each role
+//   - policies: Port for the decisions of the attribute-based access policies
+//
+// Returns:
+//   - *Authorizer: A pointer to the newly created Authorizer
+func NewAuthorizer(roleRegistry usecases.RoleRegistryPort, policies usecases.AuthorizePort) *Authorizer {
+	return &Authorizer{roleRegistry, policies}
+}
+
+// RequireAuthenticated wraps a handler so that it only serves authenticated requests.
+func (a *Authorizer) RequireAuthenticated(next http.Handler) http.Handler {
+	return a.require("", Authenticated(), next)
+}
+
+// RequireRole wraps a handler so that it only serves subjects holding any of the given roles.
+func (a *Authorizer) RequireRole(roles ...domain.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return a.require("", Role(roles...), next)
+	}
+}
+
+// RequirePermission wraps a handler so that it only serves subjects granted the given permission.
+func (a *Authorizer) RequirePermission(permission domain.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return a.require("", Permission(permission), next)
+	}
+}
+
+// Enforce wraps a ServeMux so that every routed request is checked against the declarative mapping.
+//
+// The route is resolved exactly as the mux will resolve it. Requests that match no route are
+// passed through so the mux can answer with 404 or 405.
+//
+// Parameters:
+//   - mux: The mux whose routes are protected
+//   - access: The route-to-rule mapping
+//
+// Returns:
+//   - http.Handler: The protected handler
+func (a *Authorizer) Enforce(mux *http.ServeMux, access RouteAccess) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, pattern := mux.Handler(r)
+		if pattern == "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+		a.require(pattern, access[pattern], mux).ServeHTTP(w, r)
+	})
+}
+
+// require returns a handler that checks the policies and a single rule before delegating to next.
+// The pattern is the route the request was matched to, empty for handlers wrapped individually.
+func (a *Authorizer) require(pattern string, rule AccessRule, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rule.Public {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		principal, ok := PrincipalFromContext(r.Context())
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+			problem.Write(w, r, problem.AuthenticationRequired, "")
+			return
+		}
+
+		decision := domain.DecisionNotApplicable
+		if rule.Permission != domain.PermissionPolicies {
+			decision = a.policies.Authorize(r.Context(), a.accessRequest(r, pattern, principal))
+		}
+		if decision == domain.DecisionDeny || (decision == domain.DecisionNotApplicable && !a.allows(principal, rule)) {
+			problem.Write(w, r, problem.Forbidden, "")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allows reports whether the principal satisfies the role and permission requirements of a rule.
+func (a *Authorizer) allows(principal Principal, rule AccessRule) bool {
+	if len(rule.Roles) > 0 {
+		hasRole := false
+		for _, role := range rule.Roles {
+			if principal.HasRole(role) {
+				hasRole = true
+				break
+			}
+		}
+		if !hasRole {
+			return false
+		}
+	}
+
+	if rule.Permission != "" {
+		return a.HasPermission(principal, rule.Permission)
+	}
+	return true
+}
+
+// HasPermission reports whether any of the principal's roles grants the permission in the tenant of the principal.
+func (a *Authorizer) HasPermission(principal Principal, permission domain.Permission) bool {
+	return a.roleRegistry.RolePermissions().Grants(principal.Tenant, principal.Roles, permission)
+}
+
+// accessRequest collects the attributes the access policies are evaluated against.
+//
+// The action is the route pattern, e.g. "GET /admin/users/{id}", and the resource carries the
+// route, the path and the path values, e.g. resource.id. The environment carries the client
+// address and the current time in UTC.
+func (a *Authorizer) accessRequest(r *http.Request, pattern string, principal Principal) domain.AccessRequest {
+	action := pattern
+	if action == "" {
+		action = r.Method + " " + r.URL.Path
+	}
+
+	roles := make([]string, 0, len(principal.Roles))
+	for _, role := range principal.Roles {
+		roles = append(roles, string(role))
+	}
+	permissions := []string{}
+	for _, permission := range a.roleRegistry.RolePermissions().Flatten(principal.Tenant, principal.Roles) {
+		permissions = append(permissions, string(permission))
+	}
+	subject := domain.Attributes{
+		"id":          {principal.Subject},
+		"tenant":      {principal.Tenant},
+		"roles":       roles,
+		"permissions": permissions,
+		"groups":      principal.Groups,
+	}
+
+	resource := domain.Attributes{"path": {r.URL.Path}}
+	if pattern != "" {
+		resource["route"] = []string{pattern}
+		for name, value := range pathValues(pattern, r.URL.Path) {
+			resource[name] = []string{value}
+		}
+	}
+
+	now := time.Now().UTC()
+	environment := domain.Attributes{
+		"time":    {now.Format(time.RFC3339)},
+		"hour":    {now.Format("15")},
+		"weekday": {now.Format("Mon")},
+	}
+	if ip, ok := ByClientIP(r); ok {
+		environment["ip"] = []string{ip}
+	}
+
+	return domain.AccessRequest{Subject: subject, Resource: resource, Action: action, Environment: environment}
+}
+
+// pathValues extracts the wildcard values of a ServeMux pattern from a request path, e.g. "id"
+// from "GET /admin/users/{id}". The mux fills in the path values only once it serves the request,
+// which is after authorization.
+func pathValues(pattern string, path string) map[string]string {
+	if _, route, ok := strings.Cut(pattern, " "); ok {
+		pattern = route
+	}
+	if i := strings.Index(pattern, "/"); i > 0 {
+		pattern = pattern[i:]
+	}
+
+	values := map[string]string{}
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range patternSegments {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") || segment == "{$}" || i >= len(pathSegments) {
+			continue
+		}
+		name := strings.TrimSuffix(segment[1:len(segment)-1], "...")
+		if strings.HasSuffix(segment, "...}") {
+			values[name] = strings.Join(pathSegments[i:], "/")
+			break
+		}
+		values[name] = pathSegments[i]
+	}
+	return values
+}
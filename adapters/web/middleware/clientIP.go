@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+	"user-auth-hexagonal-architecture/internal/clientip"
+)
+
+// ClientIP returns middleware that resolves the address of the client behind trusted proxies and
+// stores it in the request context, where ByClientIP, the access log and the handlers read it. It
+// therefore has to run outside of them.
+//
+// Parameters:
+//   - resolver: The trusted proxies and the forwarding header they set
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware
+func ClientIP(resolver *clientip.Resolver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := resolver.Resolve(r.RemoteAddr, r.Header.Values(resolver.Header()))
+			next.ServeHTTP(w, r.WithContext(clientip.WithIP(r.Context(), ip)))
+		})
+	}
+}
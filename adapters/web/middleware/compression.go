@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// minCompressBytes is the response size below which compression is not worth the overhead.
+const minCompressBytes = 1024
+
+// compressWriter compresses a response once it is known to be large enough.
+//
+// The first minCompressBytes of the body are buffered. If the body stays smaller, it is sent
+// as is; otherwise the header is sent with a Content-Encoding and the body is compressed.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding   string
+	status     int
+	buffer     []byte
+	decided    bool
+	compressor io.WriteCloser
+}
+
+// WriteHeader defers sending the status code until it is decided whether to compress.
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.status == 0 {
+		cw.status = status
+	}
+}
+
+// Write buffers the start of the body and compresses the rest, if it gets compressed at all.
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+	if !cw.decided {
+		if !cw.compressible() {
+			cw.decide(false)
+		} else {
+			cw.buffer = append(cw.buffer, b...)
+			if len(cw.buffer) < minCompressBytes {
+				return len(b), nil
+			}
+			cw.decide(true)
+			return len(b), nil
+		}
+	}
+	if cw.compressor != nil {
+		return cw.compressor.Write(b)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+// Flush sends what has been written so far, compressed if the response is large enough already.
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		cw.decide(cw.compressible() && len(cw.buffer) >= minCompressBytes)
+	}
+	if flusher, ok := cw.compressor.(interface{ Flush() error }); ok {
+		_ = flusher.Flush()
+	}
+	_ = http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+// Unwrap exposes the wrapped writer to http.ResponseController.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// compressible reports whether the response may be compressed at all.
+func (cw *compressWriter) compressible() bool {
+	header := cw.Header()
+	if header.Get("Content-Encoding") != "" || cw.status == http.StatusNoContent || cw.status == http.StatusNotModified {
+		return false
+	}
+	contentType := header.Get("Content-Type")
+	// event streams must reach the client immediately
+	return !strings.HasPrefix(contentType, "text/event-stream") && !strings.HasPrefix(contentType, "image/")
+}
+
+// decide sends the header, with a Content-Encoding if the body is compressed, followed by the buffered body.
+func (cw *compressWriter) decide(compress bool) {
+	cw.decided = true
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+
+	if compress {
+		cw.Header().Set("Content-Encoding", cw.encoding)
+		cw.Header().Del("Content-Length")
+		if cw.encoding == "gzip" {
+			cw.compressor = gzip.NewWriter(cw.ResponseWriter)
+		} else {
+			cw.compressor, _ = flate.NewWriter(cw.ResponseWriter, flate.DefaultCompression)
+		}
+	}
+
+	cw.ResponseWriter.WriteHeader(cw.status)
+	if len(cw.buffer) > 0 {
+		if cw.compressor != nil {
+			_, _ = cw.compressor.Write(cw.buffer)
+		} else {
+			_, _ = cw.ResponseWriter.Write(cw.buffer)
+		}
+		cw.buffer = nil
+	}
+}
+
+// close completes the response.
+func (cw *compressWriter) close() {
+	if !cw.decided {
+		if cw.status == 0 && len(cw.buffer) == 0 {
+			return
+		}
+		cw.decide(false)
+	}
+	if cw.compressor != nil {
+		_ = cw.compressor.Close()
+	}
+}
+
+// Compress returns middleware compressing responses with gzip or deflate, as accepted by the client.
+//
+// Responses smaller than minCompressBytes, already encoded responses and event streams are sent
+// uncompressed. Every response carries "Vary: Accept-Encoding", so caches keep the variants apart.
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware
+func Compress() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			writer := &compressWriter{ResponseWriter: w, encoding: encoding}
+			defer writer.close()
+			next.ServeHTTP(writer, r)
+		})
+	}
+}
+
+// negotiateEncoding picks gzip or deflate from an Accept-Encoding header, preferring gzip.
+// Encodings refused with q=0 are not picked.
+func negotiateEncoding(acceptEncoding string) string {
+	accepted := map[string]bool{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if q := strings.ReplaceAll(params, " ", ""); q == "q=0" || q == "q=0.0" || q == "q=0.00" || q == "q=0.000" {
+			continue
+		}
+		accepted[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+
+	switch {
+	case accepted["gzip"] || accepted["*"]:
+		return "gzip"
+	case accepted["deflate"]:
+		return "deflate"
+	default:
+		return ""
+	}
+}
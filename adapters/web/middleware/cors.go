@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// CORSConfig configures cross-origin resource sharing.
+type CORSConfig struct {
+	// AllowedOrigins lists the origins allowed to call the API. "*" allows any origin without
+	// credentials, and a leading wildcard label such as "https://*.example.com" allows all subdomains.
+	AllowedOrigins []string
+	// AllowedMethods lists the methods allowed in cross-origin requests.
+	AllowedMethods []string
+	// AllowedHeaders lists the request headers allowed in cross-origin requests.
+	AllowedHeaders []string
+	// ExposedHeaders lists the response headers readable by cross-origin scripts.
+	ExposedHeaders []string
+	// AllowCredentials allows cookies and authorization headers in cross-origin requests.
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache preflight results. Zero omits the header.
+	MaxAge time.Duration
+}
+
+// DefaultCORSConfig returns a configuration that allows no origins but otherwise sensible defaults,
+// so enabling CORS only requires listing the allowed origins.
+func DefaultCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete},
+		AllowedHeaders: []string{"Authorization", "Content-Type", "X-Request-ID", "Idempotency-Key", "If-None-Match", "X-CSRF-Token", "X-Tenant-ID"},
+		ExposedHeaders: []string{"Deprecation", "Sunset", "Link", "Retry-After", "X-Request-ID", "Idempotent-Replayed", "ETag"},
+		MaxAge:         10 * time.Minute,
+	}
+}
+
+// corsState is a CORS configuration with its headers prepared once per configuration change.
+type corsState struct {
+	config         CORSConfig
+	allowedMethods string
+	allowedHeaders string
+	exposedHeaders string
+	maxAge         string
+}
+
+// CORSSwitch holds the CORS configuration and can be switched at runtime, e.g. to allow a new origin
+// without a restart.
+type CORSSwitch struct {
+	state atomic.Pointer[corsState]
+}
+
+// NewCORSSwitch creates a new CORSSwitch starting with the given configuration.
+//
+// Parameters:
+//   - initial: The initial configuration
+//
+// Returns:
+//   - *CORSSwitch: A pointer to the newly created CORSSwitch
+func NewCORSSwitch(initial CORSConfig) *CORSSwitch {
+	cs := &CORSSwitch{}
+	cs.Set(initial)
+	return cs
+}
+
+// Get returns the current configuration.
+func (cs *CORSSwitch) Get() CORSConfig {
+	return cs.state.Load().config
+}
+
+// Set switches to a new configuration, requests in flight finish with the previous one.
+//
+// Parameters:
+//   - config: The new configuration
+func (cs *CORSSwitch) Set(config CORSConfig) {
+	cs.state.Store(&corsState{
+		config:         config,
+		allowedMethods: strings.Join(config.AllowedMethods, ", "),
+		allowedHeaders: strings.Join(config.AllowedHeaders, ", "),
+		exposedHeaders: strings.Join(config.ExposedHeaders, ", "),
+		maxAge:         strconv.Itoa(int(config.MaxAge.Seconds())),
+	})
+}
+
+// CORS returns middleware implementing the CORS protocol.
+//
+// Preflight requests from allowed origins are answered directly with 204 No Content and never
+// reach the wrapped handler. Requests from origins that are not allowed are passed through
+// without CORS headers, which makes the browser withhold the response from the calling script.
+//
+// Origins only allowed by "*" are answered with the wildcard and never with credentials, so a
+// configuration combining "*" with AllowCredentials cannot expose responses made with the cookies
+// of the user to any website.
+//
+// Parameters:
+//   - cors: The switch holding the current CORS configuration
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware
+func CORS(cors *CORSSwitch) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			state := cors.state.Load()
+			config := state.config
+			origin := r.Header.Get("Origin")
+			isPreflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+			w.Header().Add("Vary", "Origin")
+			listed := origin != "" && config.originListed(origin)
+			if !listed && (origin == "" || !config.allowsAnyOrigin()) {
+				if isPreflight {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if listed {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				if config.AllowCredentials {
+					w.Header().Set("Access-Control-Allow-Credentials", "true")
+				}
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			}
+
+			if !isPreflight {
+				if state.exposedHeaders != "" {
+					w.Header().Set("Access-Control-Expose-Headers", state.exposedHeaders)
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+			w.Header().Set("Access-Control-Allow-Methods", state.allowedMethods)
+			w.Header().Set("Access-Control-Allow-Headers", state.allowedHeaders)
+			if config.MaxAge > 0 {
+				w.Header().Set("Access-Control-Max-Age", state.maxAge)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
+
+// allowsAnyOrigin reports whether the wildcard origin is configured.
+func (c CORSConfig) allowsAnyOrigin() bool {
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// originListed matches an origin against the configured origins other than "*", case-insensitively.
+func (c CORSConfig) originListed(origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, allowed := range c.AllowedOrigins {
+		allowed = strings.ToLower(allowed)
+		if allowed == origin {
+			return true
+		}
+
+		scheme, host, ok := strings.Cut(allowed, "://*.")
+		if ok && strings.HasPrefix(origin, scheme+"://") && strings.HasSuffix(origin, "."+host) {
+			return true
+		}
+	}
+	return false
+}
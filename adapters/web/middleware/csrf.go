@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+)
+
+// CookieConfig configures the attributes of a cookie set by the service.
+type CookieConfig struct {
+	// Name is the cookie name.
+	Name string
+	// Domain scopes the cookie to a domain and its subdomains. Empty restricts it to the exact host.
+	Domain string
+	// Secure restricts the cookie to HTTPS. It must only be disabled for local development.
+	Secure bool
+	// SameSite controls whether browsers send the cookie with cross-site requests.
+	SameSite http.SameSite
+}
+
+// CSRFConfig configures the CSRF protection of cookie sessions.
+type CSRFConfig struct {
+	// Cookie is the cookie carrying the CSRF token. It is readable by scripts, so they can
+	// copy the token into the header.
+	Cookie CookieConfig
+	// HeaderName is the request header the token has to be echoed in.
+	HeaderName string
+	// Key signs the tokens, so that a cookie planted by a sibling subdomain is not accepted.
+	Key []byte
+}
+
+// DefaultCSRFConfig returns a configuration using the "csrf_token" cookie and the "X-CSRF-Token" header.
+//
+// Parameters:
+//   - key: The key signing the tokens
+//
+// Returns:
+//   - CSRFConfig: The configuration
+func DefaultCSRFConfig(key []byte) CSRFConfig {
+	return CSRFConfig{
+		Cookie:     CookieConfig{Name: "csrf_token", Secure: true, SameSite: http.SameSiteStrictMode},
+		HeaderName: "X-CSRF-Token",
+		Key:        key,
+	}
+}
+
+// CSRFProtection implements signed double-submit cookie CSRF protection for cookie sessions.
+type CSRFProtection struct {
+	config        CSRFConfig
+	sessionCookie string
+}
+
+// NewCSRFProtection creates a new CSRFProtection.
+//
+// Parameters:
+//   - config: The CSRF configuration
+//   - sessionCookie: The name of the session cookie whose requests are protected
+//
+// Returns:
+//   - *CSRFProtection: A pointer to the newly created CSRFProtection
+func NewCSRFProtection(config CSRFConfig, sessionCookie string) *CSRFProtection {
+	return &CSRFProtection{config, sessionCookie}
+}
+
+// IssueToken creates a new token, sets it as cookie and returns it, so the client can echo it in the header.
+//
+// Parameters:
+//   - w: The response to set the cookie on
+//
+// Returns:
+//   - string: The token
+func (c *CSRFProtection) IssueToken(w http.ResponseWriter) string {
+	nonce := make([]byte, 32)
+	_, _ = rand.Read(nonce)
+	encodedNonce := base64.RawURLEncoding.EncodeToString(nonce)
+	token := encodedNonce + "." + c.sign(encodedNonce)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     c.config.Cookie.Name,
+		Value:    token,
+		Path:     "/",
+		Domain:   c.config.Cookie.Domain,
+		Secure:   c.config.Cookie.Secure,
+		SameSite: c.config.Cookie.SameSite,
+	})
+	return token
+}
+
+// Protect returns middleware rejecting state-changing requests authenticated by the session
+// cookie unless they echo the CSRF cookie in the CSRF header.
+//
+// Requests with an Authorization header are not affected, as browsers never attach those on
+// their own. Rejected requests are answered with 403 and the CSRF_TOKEN_INVALID code. Without a
+// session cookie name, cookie sessions are disabled and the middleware passes every request through.
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware
+func (c *CSRFProtection) Protect() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if c.sessionCookie == "" || isSafeMethod(r.Method) || r.Header.Get("Authorization") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if _, err := r.Cookie(c.sessionCookie); err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !c.valid(r) {
+				problem.Write(w, r, problem.CSRFTokenInvalid, "Send the token of the "+c.config.Cookie.Name+" cookie in the "+c.config.HeaderName+" header")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// valid reports whether the request carries a correctly signed token in both the cookie and the header.
+func (c *CSRFProtection) valid(r *http.Request) bool {
+	cookie, err := r.Cookie(c.config.Cookie.Name)
+	if err != nil {
+		return false
+	}
+	header := r.Header.Get(c.config.HeaderName)
+	if header == "" || subtle.ConstantTimeCompare([]byte(header), []byte(cookie.Value)) != 1 {
+		return false
+	}
+
+	nonce, signature, ok := strings.Cut(cookie.Value, ".")
+	return ok && hmac.Equal([]byte(signature), []byte(c.sign(nonce)))
+}
+
+// sign returns the signature of a nonce.
+func (c *CSRFProtection) sign(nonce string) string {
+	mac := hmac.New(sha256.New, c.config.Key)
+	mac.Write([]byte("csrf:" + nonce))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// isSafeMethod reports whether a method must not change state and therefore needs no CSRF protection.
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
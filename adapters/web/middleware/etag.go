@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strings"
+)
+
+// CacheableRoutes lists the http.ServeMux patterns (e.g. "GET /user/me") whose responses carry an ETag.
+type CacheableRoutes map[string]bool
+
+// etagRecorder buffers a response so its ETag can be computed before anything is sent.
+type etagRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+// Header returns the buffered header.
+func (er *etagRecorder) Header() http.Header {
+	return er.header
+}
+
+// WriteHeader records the status code.
+func (er *etagRecorder) WriteHeader(status int) {
+	if er.status == 0 {
+		er.status = status
+	}
+}
+
+// Write buffers the body. A write without a prior WriteHeader implies 200 OK.
+func (er *etagRecorder) Write(b []byte) (int, error) {
+	if er.status == 0 {
+		er.status = http.StatusOK
+	}
+	return er.body.Write(b)
+}
+
+// ETag returns middleware adding entity tags to the GET responses of the listed routes and
+// answering matching If-None-Match requests with 304 Not Modified.
+//
+// The tag is a weak validator derived from the response body, so it stays valid across content
+// encodings. Responses without a Cache-Control header get "private, no-cache", which lets clients
+// and gateways keep a copy but makes them revalidate it on every use.
+//
+// Parameters:
+//   - mux: The mux used to resolve the route of a request
+//   - routes: The routes whose responses carry an ETag
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware
+func ETag(mux *http.ServeMux, routes CacheableRoutes) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if _, pattern := mux.Handler(r); !routes[pattern] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			recorder := &etagRecorder{header: w.Header()}
+			next.ServeHTTP(recorder, r)
+			if recorder.status == 0 {
+				recorder.status = http.StatusOK
+			}
+
+			if recorder.status == http.StatusOK {
+				sum := sha256.Sum256(recorder.body.Bytes())
+				etag := `W/"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+				w.Header().Set("ETag", etag)
+				if w.Header().Get("Cache-Control") == "" {
+					w.Header().Set("Cache-Control", "private, no-cache")
+				}
+
+				if etagMatches(r.Header.Get("If-None-Match"), etag) {
+					w.Header().Del("Content-Type")
+					w.Header().Del("Content-Length")
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+			}
+
+			w.WriteHeader(recorder.status)
+			_, _ = w.Write(recorder.body.Bytes())
+		})
+	}
+}
+
+// etagMatches reports whether an If-None-Match header matches the tag, using the weak comparison
+// RFC 9110 prescribes for If-None-Match.
+func etagMatches(ifNoneMatch string, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	if strings.TrimSpace(ifNoneMatch) == "*" {
+		return true
+	}
+	opaque := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == opaque {
+			return true
+		}
+	}
+	return false
+}
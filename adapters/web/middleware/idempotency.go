@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client-chosen idempotency key.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks responses replayed from the idempotency store.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	// maxIdempotencyKeyLength bounds accepted idempotency keys.
+	maxIdempotencyKeyLength = 255
+)
+
+// replayedHeaders lists the response headers stored for replay.
+var replayedHeaders = []string{"Content-Type", "Location", "Cache-Control"}
+
+// IdempotentRoutes lists the http.ServeMux patterns (e.g. "POST /user/register") accepting an Idempotency-Key header.
+type IdempotentRoutes map[string]bool
+
+// idempotencyRecorder passes a response through while keeping a copy for the idempotency store.
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+// WriteHeader records the status code before passing it on.
+func (ir *idempotencyRecorder) WriteHeader(status int) {
+	if ir.status == 0 {
+		ir.status = status
+	}
+	ir.ResponseWriter.WriteHeader(status)
+}
+
+// Write keeps a copy of the body. A write without a prior WriteHeader implies 200 OK.
+func (ir *idempotencyRecorder) Write(b []byte) (int, error) {
+	if ir.status == 0 {
+		ir.status = http.StatusOK
+	}
+	ir.body.Write(b)
+	return ir.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the wrapped writer to http.ResponseController.
+func (ir *idempotencyRecorder) Unwrap() http.ResponseWriter {
+	return ir.ResponseWriter
+}
+
+// Idempotency returns middleware that makes the listed routes safe to retry.
+//
+// A request carrying an Idempotency-Key header reserves the key, scoped by the authenticated
+// subject and the route, for the retention window. Its response is stored, and later requests
+// with the same key and payload receive the stored response with an Idempotent-Replayed header
+// instead of being executed again. Reusing a key for a different payload is rejected with 422,
+// and a retry while the original request is still running with 409. Responses that are worth
+// retrying, i.e. server errors and authentication, authorization and rate limit rejections, are
+// not stored. Requests without the header, and all requests if the store fails, are handled normally.
+//
+// Parameters:
+//   - store: The store holding the keys and responses
+//   - mux: The mux used to resolve the route of a request
+//   - routes: The routes accepting idempotency keys
+//   - retention: How long keys and responses are kept
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware
+func Idempotency(store persistence.IdempotencyStorePort, mux *http.ServeMux, routes IdempotentRoutes, retention time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			_, pattern := mux.Handler(r)
+			if !routes[pattern] {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				problem.Write(w, r, problem.InvalidRequest, "The Idempotency-Key header must not exceed 255 characters")
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxPeekBytes+1))
+			if err != nil {
+				problem.Write(w, r, problem.InvalidRequest, "The request body could not be read")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			scopedKey := scopeIdempotencyKey(r, pattern, key)
+			fingerprint := sha256.Sum256(body)
+			record, reserved, err := store.Reserve(r.Context(), scopedKey, hex.EncodeToString(fingerprint[:]), time.Now().Add(retention))
+			if err != nil {
+				logger.ErrorContext(r.Context(), "Error reserving idempotency key", "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !reserved {
+				switch {
+				case record.Fingerprint != hex.EncodeToString(fingerprint[:]):
+					problem.Write(w, r, problem.IdempotencyKeyReused, "The Idempotency-Key was already used for a different request")
+				case !record.Completed:
+					problem.Write(w, r, problem.IdempotencyConflict, "A request with this Idempotency-Key is still being processed")
+				default:
+					replay(w, record.Response)
+				}
+				return
+			}
+
+			recorder := &idempotencyRecorder{ResponseWriter: w}
+			next.ServeHTTP(recorder, r)
+
+			// the outcome is stored even if the client has gone away in the meantime
+			ctx := context.WithoutCancel(r.Context())
+			if !storable(recorder.status) {
+				if err := store.Release(ctx, scopedKey); err != nil {
+					logger.ErrorContext(r.Context(), "Error releasing idempotency key", "error", err)
+				}
+				return
+			}
+
+			response := persistence.StoredResponse{Status: recorder.status, Header: http.Header{}, Body: recorder.body.Bytes()}
+			for _, name := range replayedHeaders {
+				if values := w.Header().Values(name); len(values) > 0 {
+					response.Header[name] = values
+				}
+			}
+			if err := store.Complete(ctx, scopedKey, response); err != nil {
+				logger.ErrorContext(r.Context(), "Error storing idempotent response", "error", err)
+			}
+		})
+	}
+}
+
+// scopeIdempotencyKey prefixes the client key with the subject and the route, so clients cannot
+// replay each other's responses and one key can be used for different operations.
+func scopeIdempotencyKey(r *http.Request, pattern string, key string) string {
+	subject := "anonymous"
+	if principal, ok := PrincipalFromContext(r.Context()); ok {
+		subject = principal.Subject
+	}
+	return subject + "|" + pattern + "|" + r.URL.Path + "|" + key
+}
+
+// storable reports whether a response with the given status is kept for replay.
+func storable(status int) bool {
+	switch status {
+	case 0, http.StatusUnauthorized, http.StatusForbidden, http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return false
+	}
+	return status < http.StatusInternalServerError
+}
+
+// replay writes a stored response.
+func replay(w http.ResponseWriter, response persistence.StoredResponse) {
+	for name, values := range response.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(response.Status)
+	_, _ = w.Write(response.Body)
+}
@@ -0,0 +1,223 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/internal/clientip"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/security"
+)
+
+// maxPeekBytes bounds how much of a request body is read to extract a rate limit key.
+const maxPeekBytes = 64 << 10
+
+// KeyFunc derives the rate limit key from a request. It returns false if the request has no key,
+// in which case the rule does not apply.
+type KeyFunc func(r *http.Request) (string, bool)
+
+// RateLimitRule limits requests sharing the same key.
+type RateLimitRule struct {
+	// Name distinguishes the buckets of different rules, e.g. "login-ip".
+	Name  string
+	Limit security.RateLimit
+	Key   KeyFunc
+}
+
+// RateLimitPolicy maps http.ServeMux patterns (e.g. "POST /user/login") to their rules.
+// A request must pass every rule of its route.
+type RateLimitPolicy map[string][]RateLimitRule
+
+// WithLimits returns a copy of the policy in which the rules with the given names use new limits.
+// A name applies to every route sharing the rule, e.g. "login-ip" to both login routes.
+//
+// Parameters:
+//   - limits: The new limits by rule name
+//
+// Returns:
+//   - RateLimitPolicy: The changed copy, the policy itself is not modified
+//   - error: An error if no rule has one of the names
+func (p RateLimitPolicy) WithLimits(limits map[string]security.RateLimit) (RateLimitPolicy, error) {
+	used := make(map[string]bool)
+	changed := make(RateLimitPolicy, len(p))
+	for pattern, rules := range p {
+		changed[pattern] = make([]RateLimitRule, len(rules))
+		for i, rule := range rules {
+			if limit, ok := limits[rule.Name]; ok {
+				rule.Limit = limit
+				used[rule.Name] = true
+			}
+			changed[pattern][i] = rule
+		}
+	}
+	for name := range limits {
+		if !used[name] {
+			return nil, fmt.Errorf("unknown rate limit rule %q", name)
+		}
+	}
+	return changed, nil
+}
+
+// ParseRateLimits parses a comma-separated list of limits such as "login-ip=20/1m,login-user=5/1m/10",
+// each giving the requests per period and optionally the burst of a rule.
+//
+// Parameters:
+//   - value: The list, empty for no limits
+//
+// Returns:
+//   - map[string]security.RateLimit: The limits by rule name
+//   - error: An error if an entry is malformed
+func ParseRateLimits(value string) (map[string]security.RateLimit, error) {
+	limits := make(map[string]security.RateLimit)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, spec, ok := strings.Cut(entry, "=")
+		parts := strings.Split(spec, "/")
+		if !ok || name == "" || len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf("rate limit %q must look like name=requests/period[/burst]", entry)
+		}
+
+		var limit security.RateLimit
+		var err error
+		if limit.Requests, err = strconv.Atoi(parts[0]); err != nil || limit.Requests <= 0 {
+			return nil, fmt.Errorf("rate limit %q: requests must be a positive number", entry)
+		}
+		if limit.Per, err = time.ParseDuration(parts[1]); err != nil || limit.Per <= 0 {
+			return nil, fmt.Errorf("rate limit %q: period must be a positive duration", entry)
+		}
+		if len(parts) == 3 {
+			if limit.Burst, err = strconv.Atoi(parts[2]); err != nil || limit.Burst <= 0 {
+				return nil, fmt.Errorf("rate limit %q: burst must be a positive number", entry)
+			}
+		}
+		limits[name] = limit
+	}
+	return limits, nil
+}
+
+// RateLimitSwitch holds the rate limit policy and can be switched at runtime, e.g. to tighten a
+// limit during an attack without a restart.
+type RateLimitSwitch struct {
+	policy atomic.Pointer[RateLimitPolicy]
+}
+
+// NewRateLimitSwitch creates a new RateLimitSwitch starting with the given policy.
+//
+// Parameters:
+//   - initial: The initial policy
+//
+// Returns:
+//   - *RateLimitSwitch: A pointer to the newly created RateLimitSwitch
+func NewRateLimitSwitch(initial RateLimitPolicy) *RateLimitSwitch {
+	rs := &RateLimitSwitch{}
+	rs.Set(initial)
+	return rs
+}
+
+// Get returns the current policy.
+func (rs *RateLimitSwitch) Get() RateLimitPolicy {
+	return *rs.policy.Load()
+}
+
+// Set switches to a new policy. Buckets are kept, so a changed limit applies to the tokens
+// already taken.
+//
+// Parameters:
+//   - policy: The new policy
+func (rs *RateLimitSwitch) Set(policy RateLimitPolicy) {
+	rs.policy.Store(&policy)
+}
+
+// ByClientIP keys requests by the IP address of the client, as resolved by the ClientIP
+// middleware, or of the peer if the middleware did not run.
+func ByClientIP(r *http.Request) (string, bool) {
+	if ip := clientip.FromContext(r.Context()); ip != "" {
+		return ip, true
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr, r.RemoteAddr != ""
+	}
+	return host, true
+}
+
+// ByUsername keys requests by the username in a top-level string field of their JSON body. The
+// username is normalized like at login, so variants in case, Unicode form or surrounding whitespace
+// share the bucket of the account. The body is restored, so handlers can still decode it.
+func ByUsername(field string) KeyFunc {
+	return func(r *http.Request) (string, bool) {
+		if r.Body == nil {
+			return "", false
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxPeekBytes))
+		r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+		if err != nil {
+			return "", false
+		}
+
+		var fields map[string]any
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return "", false
+		}
+		value, ok := fields[field].(string)
+		if !ok {
+			return "", false
+		}
+		username := domain.NormalizeUsername(value).String()
+		return username, username != ""
+	}
+}
+
+// RateLimit returns middleware enforcing a rate limit policy on the routes of a mux.
+//
+// Rejected requests are answered with 429 Too Many Requests and a Retry-After header.
+// If the limiter fails, the request is allowed, so that an unavailable backend such as
+// Redis does not take down authentication.
+//
+// Parameters:
+//   - limiter: The rate limiter holding the buckets
+//   - mux: The mux used to resolve the route of a request
+//   - policies: The switch holding the rules per route
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware
+func RateLimit(limiter security.RateLimiterPort, mux *http.ServeMux, policies *RateLimitSwitch) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, pattern := mux.Handler(r)
+			for _, rule := range policies.Get()[pattern] {
+				key, ok := rule.Key(r)
+				if !ok {
+					continue
+				}
+
+				decision, err := limiter.Allow(r.Context(), rule.Name+":"+key, rule.Limit)
+				if err != nil {
+					logger.ErrorContext(r.Context(), "Error evaluating rate limit", "rule", rule.Name, "error", err)
+					continue
+				}
+				if !decision.Allowed {
+					retryAfter := int(math.Ceil(decision.RetryAfter.Seconds()))
+					w.Header().Set("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+					problem.Write(w, r, problem.RateLimited, "")
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
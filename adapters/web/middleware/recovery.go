@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/internal/logging"
+	"user-auth-hexagonal-architecture/internal/ports/telemetry"
+	"user-auth-hexagonal-architecture/internal/requestid"
+)
+
+// recoveredPanics counts the handler panics caught by Recover. It is published via expvar.
+var recoveredPanics = expvar.NewInt("http_recovered_panics_total")
+
+// recoveryWriter remembers whether the response header has been sent.
+type recoveryWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+// WriteHeader marks the header as sent.
+func (w *recoveryWriter) WriteHeader(status int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write marks the header as sent.
+func (w *recoveryWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the wrapped writer to http.ResponseController.
+func (w *recoveryWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Recover returns middleware that turns handler panics into 500 problem responses.
+//
+// The panic value and the stack trace are logged together with the request id and sent to the
+// error reporter with the panic value scrubbed of credentials, while the client only receives a
+// generic INTERNAL_ERROR problem. If the handler already started the response,
+// it cannot be replaced anymore and the connection is aborted instead. Deliberate aborts with
+// http.ErrAbortHandler are passed through untouched. To have recovered panics show up in the
+// access log, Recover has to run inside the AccessLog middleware.
+//
+// Parameters:
+//   - reporter: An implementation of ErrorReporterPort receiving the panics
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware
+func Recover(reporter telemetry.ErrorReporterPort) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			recorder := &recoveryWriter{ResponseWriter: w}
+
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				if err, ok := recovered.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+					panic(recovered)
+				}
+
+				recoveredPanics.Add(1)
+				stack := string(debug.Stack())
+				logger.ErrorContext(r.Context(), "Panic handling request", "method", r.Method, "path", r.URL.Path, "panic", recovered, "stack", stack)
+				reporter.ReportError(r.Context(), telemetry.ErrorReport{
+					Message:   "Panic handling request",
+					Error:     logging.Scrub(fmt.Sprintf("%+v", recovered)),
+					Stack:     stack,
+					RequestID: requestid.FromContext(r.Context()),
+					Tags:      map[string]string{"component": "http", "method": r.Method},
+					Context:   map[string]string{"path": r.URL.Path},
+				})
+
+				if recorder.wroteHeader {
+					panic(http.ErrAbortHandler)
+				}
+				problem.Write(recorder, r, problem.InternalError, "")
+			}()
+
+			next.ServeHTTP(recorder, r)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"user-auth-hexagonal-architecture/internal/requestid"
+)
+
+// maxRequestIDLength bounds accepted incoming request ids.
+const maxRequestIDLength = 128
+
+// RequestID returns middleware that assigns every request a correlation id.
+//
+// A well-formed X-Request-ID sent by the client or an upstream proxy is kept, otherwise a new
+// id is generated. The id is stored in the request context and echoed in the response header.
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware
+func RequestID() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(requestid.Header)
+			if !validRequestID(id) {
+				id = requestid.New()
+			}
+
+			w.Header().Set(requestid.Header, id)
+			next.ServeHTTP(w, r.WithContext(requestid.WithID(r.Context(), id)))
+		})
+	}
+}
+
+// validRequestID accepts non-empty ids of bounded length made of characters that are safe
+// to log and to forward in headers.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		isAlphanumeric := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+		if !isAlphanumeric && c != '-' && c != '_' && c != '.' && c != ':' {
+			return false
+		}
+	}
+	return true
+}
+
+// Chain applies middleware to a handler. The first middleware is the outermost one.
+//
+// Parameters:
+//   - handler: The innermost handler
+//   - middlewares: The middleware to apply
+//
+// Returns:
+//   - http.Handler: The wrapped handler
+func Chain(handler http.Handler, middlewares ...func(http.Handler) http.Handler) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+)
+
+// Mode is the operating mode of the API.
+type Mode string
+
+const (
+	// ModeNormal serves every request.
+	ModeNormal Mode = "normal"
+	// ModeReadOnly rejects state-changing requests, e.g. during database migrations.
+	ModeReadOnly Mode = "read_only"
+	// ModeMaintenance rejects all requests except those of routes declared available in maintenance.
+	ModeMaintenance Mode = "maintenance"
+)
+
+// level orders the modes by how restrictive they are.
+var level = map[Mode]int{ModeNormal: 0, ModeReadOnly: 1, ModeMaintenance: 2}
+
+// ParseMode validates the name of a mode.
+//
+// Parameters:
+//   - value: "normal", "read_only" or "maintenance"
+//
+// Returns:
+//   - Mode: The mode
+//   - error: An error if the mode is unknown
+func ParseMode(value string) (Mode, error) {
+	if _, ok := level[Mode(value)]; !ok {
+		return "", fmt.Errorf("unknown mode %q", value)
+	}
+	return Mode(value), nil
+}
+
+// ModeState is the current mode together with the information announced to rejected clients.
+type ModeState struct {
+	Mode Mode
+	// RetryAfter is sent in the Retry-After header of rejected requests, zero omits the header.
+	RetryAfter time.Duration
+	// Message is sent as detail of the problem response of rejected requests.
+	Message string
+	// Since is the time the mode was entered.
+	Since time.Time
+}
+
+// ModeSwitch holds the mode of the API and can be switched at runtime.
+type ModeSwitch struct {
+	state atomic.Pointer[ModeState]
+}
+
+// NewModeSwitch creates a new ModeSwitch starting in the given state.
+//
+// Parameters:
+//   - initial: The initial state
+//
+// Returns:
+//   - *ModeSwitch: A pointer to the newly created ModeSwitch
+func NewModeSwitch(initial ModeState) *ModeSwitch {
+	ms := &ModeSwitch{}
+	ms.Set(initial)
+	return ms
+}
+
+// Get returns the current state.
+func (ms *ModeSwitch) Get() ModeState {
+	return *ms.state.Load()
+}
+
+// Set switches to a new state. A zero Since is replaced by the current time.
+//
+// Parameters:
+//   - state: The new state
+func (ms *ModeSwitch) Set(state ModeState) {
+	if state.Since.IsZero() {
+		state.Since = time.Now()
+	}
+	ms.state.Store(&state)
+}
+
+// ModeRoutes overrides the most restrictive mode in which a route (e.g. "POST /token/verify-batch")
+// is still served. Routes that are not listed are served up to ModeReadOnly if their method is
+// safe and in ModeNormal only otherwise.
+type ModeRoutes map[string]Mode
+
+// ServiceMode returns middleware rejecting the requests the current mode does not allow.
+//
+// Rejected requests are answered with 503 Service Unavailable, the SERVICE_UNAVAILABLE code and,
+// if configured, a Retry-After header.
+//
+// Parameters:
+//   - modes: The switch holding the current mode
+//   - mux: The mux used to resolve the route of a request
+//   - routes: The routes whose availability differs from the default
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware
+func ServiceMode(modes *ModeSwitch, mux *http.ServeMux, routes ModeRoutes) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			state := modes.Get()
+			if state.Mode == ModeNormal {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			availableUpTo := ModeNormal
+			if isSafeMethod(r.Method) {
+				availableUpTo = ModeReadOnly
+			}
+			if _, pattern := mux.Handler(r); pattern != "" {
+				if mode, ok := routes[pattern]; ok {
+					availableUpTo = mode
+				}
+			}
+			if level[state.Mode] <= level[availableUpTo] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if state.RetryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(state.RetryAfter.Seconds())))
+			}
+			problem.Write(w, r, problem.ServiceUnavailable, state.Message)
+		})
+	}
+}
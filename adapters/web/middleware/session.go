@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// TrackSessions returns middleware that only keeps requests authenticated while the session
+// their access token is bound to is active, and records the activity of the session. It has to
+// run after Authenticate.
+//
+// Requests with a token of a revoked or expired session pass through unauthenticated, like
+// requests with an invalid token, so public routes such as the login keep working. If the
+// sessions cannot be looked up, the token is accepted, so an outage of the session store does
+// not log everybody out.
+//
+// Parameters:
+//   - trackSessionPort: Port for checking and touching sessions
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware
+func TrackSessions(trackSessionPort usecases.TrackSessionPort) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := PrincipalFromContext(r.Context())
+			if !ok || principal.SessionID == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			err := trackSessionPort.TouchSession(r.Context(), principal.SessionID)
+			switch {
+			case errors.Is(err, errorx.ErrSessionNotFound), errors.Is(err, errorx.ErrSessionNotActive):
+				next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalKey{}, nil)))
+				return
+			case err != nil:
+				logger.ErrorContext(r.Context(), "Error checking session", "session_id", principal.SessionID, "error", err)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/tenant"
+)
+
+// ResolveTenant returns middleware that determines the tenant a request is made for and stores
+// its id in the request context. It has to run after Authenticate.
+//
+// Authenticated requests belong to the tenant of their access token; an X-Tenant-ID header naming
+// another tenant is rejected, so a token cannot be used across tenants. Unauthenticated requests,
+// e.g. logins and registrations, name their tenant in the X-Tenant-ID header and belong to the
+// default tenant without it.
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware
+func ResolveTenant() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(tenant.Header)
+			if id != "" && !domain.ValidTenantID(id) {
+				problem.Write(w, r, problem.InvalidRequest, "The "+tenant.Header+" header is not a valid tenant id")
+				return
+			}
+
+			if principal, ok := PrincipalFromContext(r.Context()); ok {
+				if id != "" && id != principal.Tenant {
+					problem.Write(w, r, problem.Forbidden, "The access token was not issued for tenant "+id)
+					return
+				}
+				id = principal.Tenant
+			}
+			if id == "" {
+				id = domain.DefaultTenantID
+			}
+
+			next.ServeHTTP(w, r.WithContext(tenant.WithID(r.Context(), id)))
+		})
+	}
+}
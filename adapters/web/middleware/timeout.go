@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// StreamingRoutes lists the http.ServeMux patterns (e.g. "GET /admin/events/stream") that hold the
+// connection open by design and are therefore not bounded by the request timeout.
+type StreamingRoutes map[string]bool
+
+// Timeout returns middleware that bounds the time a request may spend in the application.
+//
+// The deadline is set on the request context, which the handlers pass on to the use cases and
+// from there to the persistence adapters, so slow database calls are cancelled instead of piling
+// up. Use cases report an exceeded deadline as context.DeadlineExceeded, which is answered with
+// a TIMEOUT problem. A zero timeout disables the middleware.
+//
+// The streaming routes are exempted by their route, never by request headers, so clients cannot
+// lift the deadline of other routes.
+//
+// Parameters:
+//   - timeout: The maximum duration of a request
+//   - mux: The mux used to resolve the route of a request
+//   - streams: The routes that are not bounded
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware
+func Timeout(timeout time.Duration, mux *http.ServeMux, streams StreamingRoutes) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if timeout <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, pattern := mux.Handler(r); streams[pattern] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,287 @@
+// Package problem writes RFC 7807 "application/problem+json" error responses and maps
+// domain errors onto them, so every HTTP adapter reports errors the same way.
+package problem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"user-auth-hexagonal-architecture/adapters/web/validation"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/logging"
+	"user-auth-hexagonal-architecture/internal/requestid"
+)
+
+// logger writes the log records of this package.
+var logger = logging.Component("http")
+
+// ContentType is the media type of problem responses.
+const ContentType = "application/problem+json"
+
+// Code is a stable, machine-readable error code clients can branch on.
+type Code string
+
+const (
+	InvalidRequest         Code = "INVALID_REQUEST"
+	ValidationFailed       Code = "VALIDATION_FAILED"
+	AuthenticationRequired Code = "AUTHENTICATION_REQUIRED"
+	Forbidden              Code = "FORBIDDEN"
+	NotFound               Code = "NOT_FOUND"
+	// the codes of domain errors are taken from errorx, so WriteError can report them unchanged
+	InvalidCredentials         Code = Code(errorx.CodeInvalidCredentials)
+	AccountDisabled            Code = Code(errorx.CodeAccountDisabled)
+	AccountLocked              Code = Code(errorx.CodeAccountLocked)
+	AccountPending             Code = Code(errorx.CodeAccountPending)
+	InvalidTransition          Code = Code(errorx.CodeInvalidStatusTransition)
+	UsernameTaken              Code = Code(errorx.CodeUsernameTaken)
+	EmailTaken                 Code = Code(errorx.CodeEmailTaken)
+	UserNotFound               Code = Code(errorx.CodeUserNotFound)
+	UnknownRole                Code = Code(errorx.CodeUnknownRole)
+	RoleNotFound               Code = Code(errorx.CodeRoleNotFound)
+	BuiltInRole                Code = Code(errorx.CodeBuiltInRole)
+	GroupNotFound              Code = Code(errorx.CodeGroupNotFound)
+	PolicyNotFound             Code = Code(errorx.CodePolicyNotFound)
+	InvalidPolicy              Code = Code(errorx.CodeInvalidPolicy)
+	InvalidProfile             Code = Code(errorx.CodeInvalidProfile)
+	InvalidUsername            Code = Code(errorx.CodeInvalidUsername)
+	InvalidEmail               Code = Code(errorx.CodeInvalidEmail)
+	WeakPassword               Code = Code(errorx.CodeWeakPassword)
+	WebhookNotFound            Code = Code(errorx.CodeWebhookNotFound)
+	TenantNotFound             Code = Code(errorx.CodeTenantNotFound)
+	InvalidTenant              Code = Code(errorx.CodeInvalidTenant)
+	MfaRequired                Code = Code(errorx.CodeMfaRequired)
+	LoginMethodNotAllowed      Code = Code(errorx.CodeLoginMethodNotAllowed)
+	SessionNotFound            Code = Code(errorx.CodeSessionNotFound)
+	SessionNotActive           Code = Code(errorx.CodeSessionNotActive)
+	SessionLimitReached        Code = Code(errorx.CodeSessionLimitReached)
+	LoginBlocked               Code = Code(errorx.CodeLoginBlocked)
+	ConsentRequired            Code = Code(errorx.CodeConsentRequired)
+	InvalidConsent             Code = Code(errorx.CodeInvalidConsent)
+	InvalidToken               Code = Code(errorx.CodeInvalidToken)
+	InvalidRefreshToken        Code = Code(errorx.CodeInvalidRefreshToken)
+	RefreshTokenReused         Code = Code(errorx.CodeRefreshTokenReused)
+	LastAdmin                  Code = Code(errorx.CodeLastAdmin)
+	RoleRequired               Code = Code(errorx.CodeRoleRequired)
+	DataExportNotFound         Code = Code(errorx.CodeDataExportNotFound)
+	DataExportNotReady         Code = Code(errorx.CodeDataExportNotReady)
+	InvalidDownloadLink        Code = Code(errorx.CodeInvalidDownloadLink)
+	LegalHold                  Code = Code(errorx.CodeLegalHold)
+	ErasureCertificateNotFound Code = Code(errorx.CodeErasureCertificateNotFound)
+	InvalidLock                Code = Code(errorx.CodeInvalidLock)
+	InvalidUserSpec            Code = Code(errorx.CodeInvalidUserSpec)
+	InvalidStatsPeriod         Code = Code(errorx.CodeInvalidStatsPeriod)
+	PasswordChangeRequired     Code = Code(errorx.CodePasswordChangeRequired)
+	InvalidPasswordChangeToken Code = Code(errorx.CodeInvalidPasswordChangeToken)
+	InvalidMerge               Code = Code(errorx.CodeInvalidMerge)
+	MfaAlreadyEnabled          Code = Code(errorx.CodeMfaAlreadyEnabled)
+	MfaNotEnrolled             Code = Code(errorx.CodeMfaNotEnrolled)
+	InvalidMfaCode             Code = Code(errorx.CodeInvalidMfaCode)
+	UnsupportedMfaMethod       Code = Code(errorx.CodeUnsupportedMfaMethod)
+	MfaCodeRequired            Code = Code(errorx.CodeMfaCodeRequired)
+	RegistrationRejected       Code = Code(errorx.CodeRegistrationRejected)
+	DependencyUnavailable      Code = Code(errorx.CodeDependencyUnavailable)
+	Overloaded                 Code = Code(errorx.CodeOverloaded)
+	TooManyFailedLogins        Code = Code(errorx.CodeTooManyFailedLogins)
+	EmailSuppressionNotFound   Code = Code(errorx.CodeEmailSuppressionNotFound)
+	InvalidEmailFeedback       Code = Code(errorx.CodeInvalidEmailFeedback)
+	SMSLimitExceeded           Code = Code(errorx.CodeSMSLimitExceeded)
+	RateLimited                Code = "RATE_LIMITED"
+	PayloadTooLarge            Code = "PAYLOAD_TOO_LARGE"
+	Timeout                    Code = "TIMEOUT"
+	IdempotencyKeyReused       Code = "IDEMPOTENCY_KEY_REUSED"
+	IdempotencyConflict        Code = "IDEMPOTENCY_CONFLICT"
+	CSRFTokenInvalid           Code = "CSRF_TOKEN_INVALID"
+	ServiceUnavailable         Code = "SERVICE_UNAVAILABLE"
+	InternalError              Code = "INTERNAL_ERROR"
+)
+
+// Details is the RFC 7807 problem details object, extended by a machine-readable code,
+// the request id for support inquiries and, for validation problems, the list of rejected fields.
+type Details struct {
+	Type      string                  `json:"type"`
+	Title     string                  `json:"title"`
+	Status    int                     `json:"status"`
+	Detail    string                  `json:"detail,omitempty"`
+	Instance  string                  `json:"instance,omitempty"`
+	Code      Code                    `json:"code"`
+	Errors    []validation.FieldError `json:"errors,omitempty"`
+	RequestID string                  `json:"requestId,omitempty"`
+}
+
+// definition holds the fixed presentation of a code.
+type definition struct {
+	status int
+	title  string
+}
+
+// definitions maps every code onto its HTTP status and human-readable title.
+var definitions = map[Code]definition{
+	InvalidRequest:             {http.StatusBadRequest, "Invalid request"},
+	ValidationFailed:           {http.StatusBadRequest, "Validation failed"},
+	AuthenticationRequired:     {http.StatusUnauthorized, "Authentication required"},
+	Forbidden:                  {http.StatusForbidden, "Insufficient permissions"},
+	NotFound:                   {http.StatusNotFound, "Resource not found"},
+	InvalidCredentials:         {http.StatusUnauthorized, "Invalid username or password"},
+	AccountDisabled:            {http.StatusForbidden, "Account disabled"},
+	AccountLocked:              {http.StatusLocked, "Account locked"},
+	AccountPending:             {http.StatusForbidden, "Account not activated"},
+	InvalidTransition:          {http.StatusConflict, "Account status cannot be changed"},
+	UsernameTaken:              {http.StatusConflict, "Username already taken"},
+	EmailTaken:                 {http.StatusConflict, "Email already registered"},
+	UserNotFound:               {http.StatusNotFound, "User not found"},
+	UnknownRole:                {http.StatusBadRequest, "Unknown role"},
+	RoleNotFound:               {http.StatusNotFound, "Role not found"},
+	BuiltInRole:                {http.StatusConflict, "Built-in role cannot be changed"},
+	GroupNotFound:              {http.StatusNotFound, "Group not found"},
+	PolicyNotFound:             {http.StatusNotFound, "Policy not found"},
+	InvalidPolicy:              {http.StatusBadRequest, "Invalid policy"},
+	InvalidProfile:             {http.StatusBadRequest, "Invalid profile"},
+	InvalidUsername:            {http.StatusBadRequest, "Invalid username"},
+	InvalidEmail:               {http.StatusBadRequest, "Invalid email address"},
+	WeakPassword:               {http.StatusBadRequest, "Password too weak"},
+	WebhookNotFound:            {http.StatusNotFound, "Webhook not found"},
+	TenantNotFound:             {http.StatusNotFound, "Tenant not found"},
+	InvalidTenant:              {http.StatusBadRequest, "Invalid tenant settings"},
+	MfaRequired:                {http.StatusForbidden, "Multi-factor authentication required"},
+	LoginMethodNotAllowed:      {http.StatusForbidden, "Login method not allowed"},
+	SessionNotFound:            {http.StatusNotFound, "Session not found"},
+	SessionNotActive:           {http.StatusConflict, "Session already ended"},
+	SessionLimitReached:        {http.StatusConflict, "Too many active sessions"},
+	LoginBlocked:               {http.StatusForbidden, "Login blocked"},
+	ConsentRequired:            {http.StatusForbidden, "Consent required"},
+	InvalidConsent:             {http.StatusBadRequest, "Invalid consent"},
+	InvalidToken:               {http.StatusUnauthorized, "Invalid token"},
+	InvalidRefreshToken:        {http.StatusUnauthorized, "Invalid refresh token"},
+	RefreshTokenReused:         {http.StatusUnauthorized, "Refresh token reused"},
+	LastAdmin:                  {http.StatusConflict, "Last administrator"},
+	RoleRequired:               {http.StatusConflict, "Role required"},
+	DataExportNotFound:         {http.StatusNotFound, "Data export not found"},
+	DataExportNotReady:         {http.StatusConflict, "Data export not ready"},
+	InvalidDownloadLink:        {http.StatusForbidden, "Invalid download link"},
+	LegalHold:                  {http.StatusConflict, "Account under legal hold"},
+	ErasureCertificateNotFound: {http.StatusNotFound, "Erasure certificate not found"},
+	InvalidLock:                {http.StatusBadRequest, "Invalid Lock"},
+	InvalidUserSpec:            {http.StatusBadRequest, "Invalid user spec"},
+	InvalidStatsPeriod:         {http.StatusBadRequest, "Invalid statistics period"},
+	PasswordChangeRequired:     {http.StatusForbidden, "Password change required"},
+	InvalidPasswordChangeToken: {http.StatusUnauthorized, "Invalid password change token"},
+	InvalidMerge:               {http.StatusBadRequest, "Invalid account merge"},
+	MfaAlreadyEnabled:          {http.StatusConflict, "Multi-factor authentication already enabled"},
+	MfaNotEnrolled:             {http.StatusNotFound, "Multi-factor authentication not enrolled"},
+	InvalidMfaCode:             {http.StatusUnauthorized, "Invalid one-time code"},
+	UnsupportedMfaMethod:       {http.StatusNotFound, "Unsupported multi-factor authentication method"},
+	MfaCodeRequired:            {http.StatusUnauthorized, "One-time code required"},
+	RegistrationRejected:       {http.StatusForbidden, "Registration rejected"},
+	DependencyUnavailable:      {http.StatusServiceUnavailable, "Dependency temporarily unavailable"},
+	Overloaded:                 {http.StatusServiceUnavailable, "Service overloaded"},
+	TooManyFailedLogins:        {http.StatusTooManyRequests, "Too many failed logins"},
+	EmailSuppressionNotFound:   {http.StatusNotFound, "Email suppression not found"},
+	InvalidEmailFeedback:       {http.StatusBadRequest, "Invalid email feedback"},
+	SMSLimitExceeded:           {http.StatusTooManyRequests, "SMS limit exceeded"},
+	RateLimited:                {http.StatusTooManyRequests, "Too many requests"},
+	PayloadTooLarge:            {http.StatusRequestEntityTooLarge, "Request body too large"},
+	Timeout:                    {http.StatusServiceUnavailable, "Request timed out"},
+	IdempotencyKeyReused:       {http.StatusUnprocessableEntity, "Idempotency key reused"},
+	IdempotencyConflict:        {http.StatusConflict, "Request in progress"},
+	CSRFTokenInvalid:           {http.StatusForbidden, "Missing or invalid CSRF token"},
+	ServiceUnavailable:         {http.StatusServiceUnavailable, "Service temporarily unavailable"},
+	InternalError:              {http.StatusInternalServerError, "Internal server error"},
+}
+
+// Status returns the HTTP status a code is answered with, 500 Internal Server Error for unknown codes.
+//
+// Parameters:
+//   - code: The problem code
+//
+// Returns:
+//   - int: The HTTP status
+func Status(code Code) int {
+	if def, ok := definitions[code]; ok {
+		return def.status
+	}
+	return definitions[InternalError].status
+}
+
+// Write sends a problem response for the given code.
+//
+// Parameters:
+//   - w: HTTP ResponseWriter to write the response
+//   - r: The request the problem occurred in; its path becomes the problem instance
+//   - code: The problem code
+//   - detail: An optional human-readable explanation specific to this occurrence
+func Write(w http.ResponseWriter, r *http.Request, code Code, detail string) {
+	write(w, r, code, detail, nil)
+}
+
+// WriteValidation sends a 400 problem response listing the rejected fields.
+//
+// Parameters:
+//   - w: HTTP ResponseWriter to write the response
+//   - r: The request that failed validation
+//   - fieldErrors: The field errors collected by a validation.Validator
+func WriteValidation(w http.ResponseWriter, r *http.Request, fieldErrors []validation.FieldError) {
+	write(w, r, ValidationFailed, "One or more fields are invalid", fieldErrors)
+}
+
+// write renders a problem details object.
+func write(w http.ResponseWriter, r *http.Request, code Code, detail string, fieldErrors []validation.FieldError) {
+	def, ok := definitions[code]
+	if !ok {
+		def = definitions[InternalError]
+	}
+
+	// the request URI is used rather than URL.Path, which has version prefixes stripped
+	instance, _, _ := strings.Cut(r.RequestURI, "?")
+	if instance == "" {
+		instance = r.URL.Path
+	}
+
+	details := Details{
+		Type:      "/problems/" + strings.ReplaceAll(strings.ToLower(string(code)), "_", "-"),
+		Title:     def.title,
+		Status:    def.status,
+		Detail:    detail,
+		Instance:  instance,
+		Code:      code,
+		Errors:    fieldErrors,
+		RequestID: requestid.FromContext(r.Context()),
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(def.status)
+	if err := json.NewEncoder(w).Encode(details); err != nil {
+		logger.ErrorContext(r.Context(), "Error writing problem response", "error", err)
+	}
+}
+
+// WriteError sends a problem response for an error returned by a use case.
+//
+// Typed domain errors are reported with their code and, for structured errors, their detail;
+// an exceeded request deadline is mapped onto TIMEOUT.
+// Any other error is logged, which also reports it to the error reporter, and answered with a generic 500
+// that does not reveal internal details.
+//
+// Parameters:
+//   - w: HTTP ResponseWriter to write the response
+//   - r: The request the error occurred in
+//   - err: The error to report
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	if domainErr, ok := errorx.As(err); ok {
+		if _, ok := definitions[Code(domainErr.Code)]; ok {
+			Write(w, r, Code(domainErr.Code), domainErr.Detail)
+			return
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		logger.WarnContext(r.Context(), "Timeout handling request", "method", r.Method, "path", r.URL.Path, "error", err)
+		Write(w, r, Timeout, "")
+		return
+	}
+
+	logger.ErrorContext(r.Context(), "Unexpected error handling request", "method", r.Method, "route", r.Pattern, "path", r.URL.Path, "error", err)
+	Write(w, r, InternalError, "")
+}
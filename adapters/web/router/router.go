@@ -0,0 +1,112 @@
+// Package router mounts versioned API handlers side by side and marks deprecated versions.
+package router
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+)
+
+// Version describes a mounted version of the HTTP API.
+type Version struct {
+	// Prefix is the path prefix of the version, e.g. "/api/v1". An empty prefix mounts the
+	// version at the root, which is used for the legacy unversioned routes.
+	Prefix string
+	// Deprecated marks the version as deprecated; responses carry a Deprecation header.
+	Deprecated bool
+	// DeprecatedSince is the optional time the deprecation took effect.
+	DeprecatedSince time.Time
+	// Sunset is the optional time after which the version will stop being served.
+	Sunset time.Time
+	// Successor is the optional prefix of the version clients should migrate to.
+	Successor string
+	// Enabled optionally decides per request whether the version is served, e.g. by a feature flag
+	// for the tenant of the request. Requests to a disabled version are answered with 404.
+	Enabled func(ctx context.Context) bool
+}
+
+// Router dispatches requests to the API version matching their path prefix.
+// Unversioned operational routes can be registered alongside the versions.
+type Router struct {
+	mux *http.ServeMux
+}
+
+// NewRouter creates a new Router without mounted versions.
+//
+// Returns:
+//   - *Router: A pointer to the newly created Router
+func NewRouter() *Router {
+	return &Router{http.NewServeMux()}
+}
+
+// Mount serves a version of the API.
+//
+// The handler sees paths without the version prefix, so the same handler can be mounted
+// under several prefixes. Responses of deprecated versions carry Deprecation, Sunset and
+// Link headers as defined in RFC 9745 and RFC 8594.
+//
+// Parameters:
+//   - version: The version to mount
+//   - handler: The handler serving the version's routes
+func (rt *Router) Mount(version Version, handler http.Handler) {
+	if version.Deprecated {
+		handler = deprecationHeaders(version, handler)
+	}
+	if version.Enabled != nil {
+		handler = enabledOnly(version.Enabled, handler)
+	}
+
+	prefix := strings.TrimSuffix(version.Prefix, "/")
+	if prefix == "" {
+		rt.mux.Handle("/", handler)
+		return
+	}
+	rt.mux.Handle(prefix+"/", http.StripPrefix(prefix, handler))
+}
+
+// Handle registers an unversioned route, e.g. for health checks.
+//
+// Parameters:
+//   - pattern: An http.ServeMux pattern
+//   - handler: The handler serving the route
+func (rt *Router) Handle(pattern string, handler http.Handler) {
+	rt.mux.Handle(pattern, handler)
+}
+
+// ServeHTTP dispatches the request to the matching version or unversioned route.
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rt.mux.ServeHTTP(w, r)
+}
+
+// deprecationHeaders wraps a handler so that its responses announce the deprecation of the version.
+func deprecationHeaders(version Version, next http.Handler) http.Handler {
+	deprecation := "true"
+	if !version.DeprecatedSince.IsZero() {
+		deprecation = fmt.Sprintf("@%d", version.DeprecatedSince.Unix())
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", deprecation)
+		if !version.Sunset.IsZero() {
+			w.Header().Set("Sunset", version.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if version.Successor != "" {
+			w.Header().Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, version.Successor))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// enabledOnly wraps a handler so that it only serves requests for which the version is enabled.
+func enabledOnly(enabled func(ctx context.Context) bool, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !enabled(r.Context()) {
+			problem.Write(w, r, problem.NotFound, "")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
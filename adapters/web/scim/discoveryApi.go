@@ -0,0 +1,89 @@
+package scim
+
+import (
+	"net/http"
+)
+
+// supported is a feature flag of the service provider configuration.
+type supported struct {
+	Supported bool `json:"supported"`
+}
+
+// DiscoveryApi serves the ServiceProviderConfig and ResourceTypes endpoints, which identity
+// providers read to learn which features the service supports.
+type DiscoveryApi struct {
+	baseURL string
+}
+
+// NewDiscoveryApiAdapter creates a new DiscoveryApi.
+//
+// Parameters:
+//   - baseURL: The public URL of the service the resource locations are built from, derived from
+//     the request if empty
+//
+// Returns:
+//   - *DiscoveryApi: A pointer to the newly created DiscoveryApi
+func NewDiscoveryApiAdapter(baseURL string) *DiscoveryApi {
+	return &DiscoveryApi{baseURL}
+}
+
+// InitDiscoveryRoutes sets up the HTTP routes of the discovery endpoints, relative to the SCIM base path.
+func (da *DiscoveryApi) InitDiscoveryRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /ServiceProviderConfig", da.handleServiceProviderConfig)
+	mux.HandleFunc("GET /ResourceTypes", da.handleResourceTypes)
+}
+
+// handleServiceProviderConfig handles HTTP GET requests for the supported features.
+//
+// It responds with HTTP 200 OK: PATCH and filtering are supported, bulk operations, password
+// changes, sorting and ETags are not.
+func (da *DiscoveryApi) handleServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, r, http.StatusOK, map[string]any{
+		"schemas":        []string{schemaServiceProviderConfig},
+		"patch":          supported{true},
+		"bulk":           map[string]any{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]any{"supported": true, "maxResults": maxPageSize},
+		"changePassword": supported{false},
+		"sort":           supported{false},
+		"etag":           supported{false},
+		"authenticationSchemes": []map[string]any{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer Token",
+			"description": "A static bearer token configured at the identity provider",
+			"primary":     true,
+		}},
+		"meta": meta{ResourceType: "ServiceProviderConfig", Location: baseURL(da.baseURL, r) + "/ServiceProviderConfig"},
+	})
+}
+
+// handleResourceTypes handles HTTP GET requests for the resource types.
+//
+// It responds with HTTP 200 OK and a ListResponse of the User and Group resource types.
+func (da *DiscoveryApi) handleResourceTypes(w http.ResponseWriter, r *http.Request) {
+	base := baseURL(da.baseURL, r)
+	resourceTypes := []any{
+		map[string]any{
+			"schemas":  []string{schemaResourceType},
+			"id":       "User",
+			"name":     "User",
+			"endpoint": "/Users",
+			"schema":   schemaUser,
+			"meta":     meta{ResourceType: "ResourceType", Location: base + "/ResourceTypes/User"},
+		},
+		map[string]any{
+			"schemas":  []string{schemaResourceType},
+			"id":       "Group",
+			"name":     "Group",
+			"endpoint": "/Groups",
+			"schema":   schemaGroup,
+			"meta":     meta{ResourceType: "ResourceType", Location: base + "/ResourceTypes/Group"},
+		},
+	}
+	writeJSON(w, r, http.StatusOK, listResponse{
+		Schemas:      []string{schemaListResponse},
+		TotalResults: len(resourceTypes),
+		StartIndex:   1,
+		ItemsPerPage: len(resourceTypes),
+		Resources:    resourceTypes,
+	})
+}
@@ -0,0 +1,46 @@
+package scim
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// filterPattern matches the only filters identity providers send for provisioning: an attribute
+// compared to a string with "eq", e.g. userName eq "jdoe".
+var filterPattern = regexp.MustCompile(`(?i)^\s*([a-z][a-z0-9._:]*?)\s+eq\s+("(?:[^"\\]|\\.)*")\s*$`)
+
+// filter is an equality filter on an attribute.
+type filter struct {
+	// Attribute is the lower case path of the attribute, e.g. "username" or "emails.value".
+	Attribute string
+	Value     string
+}
+
+// parseFilter parses the filter of a list request. An empty expression yields a nil filter.
+//
+// Parameters:
+//   - expression: The filter, e.g. `userName eq "jdoe"`
+//
+// Returns:
+//   - *filter: The filter, or nil if the expression is empty
+//   - bool: false if the expression is not an equality filter on a string
+func parseFilter(expression string) (*filter, bool) {
+	if strings.TrimSpace(expression) == "" {
+		return nil, true
+	}
+	match := filterPattern.FindStringSubmatch(expression)
+	if match == nil {
+		return nil, false
+	}
+	var value string
+	if err := json.Unmarshal([]byte(match[2]), &value); err != nil {
+		return nil, false
+	}
+	attribute := strings.ToLower(match[1])
+	// the schema URN prefix of core attributes is optional
+	if i := strings.LastIndex(attribute, ":"); i >= 0 {
+		attribute = attribute[i+1:]
+	}
+	return &filter{attribute, value}, true
+}
@@ -0,0 +1,386 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// invalidGroupNameChars matches the runs of characters group names cannot contain.
+var invalidGroupNameChars = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// memberFilterPattern matches the member selector of PATCH paths, e.g. members[value eq "42"].
+var memberFilterPattern = regexp.MustCompile(`(?i)^members\[\s*value\s+eq\s+("(?:[^"\\]|\\.)*")\s*\]$`)
+
+// GroupsApi serves the SCIM Groups resource on top of the groups of the service. The id of a group
+// is its name, derived from the displayName of the client; groups cannot be renamed. The roles of
+// a group are managed by administrators, the client only manages its members.
+type GroupsApi struct {
+	manageGroupsPort usecases.ManageGroupsPort
+	baseURL          string
+}
+
+// NewGroupsApiAdapter creates a new GroupsApi with the given use case port.
+//
+// Parameters:
+//   - manageGroupsPort: Port for managing groups and their members
+//   - baseURL: The public URL of the service the resource locations are built from, derived from
+//     the request if empty
+//
+// Returns:
+//   - *GroupsApi: A pointer to the newly created GroupsApi
+func NewGroupsApiAdapter(manageGroupsPort usecases.ManageGroupsPort, baseURL string) *GroupsApi {
+	return &GroupsApi{manageGroupsPort, baseURL}
+}
+
+// InitGroupsRoutes sets up the HTTP routes of the Groups resource, relative to the SCIM base path.
+func (ga *GroupsApi) InitGroupsRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /Groups", ga.handleListGroups)
+	mux.HandleFunc("POST /Groups", ga.handleCreateGroup)
+	mux.HandleFunc("GET /Groups/{id}", ga.handleGetGroup)
+	mux.HandleFunc("PUT /Groups/{id}", ga.handleReplaceGroup)
+	mux.HandleFunc("PATCH /Groups/{id}", ga.handlePatchGroup)
+	mux.HandleFunc("DELETE /Groups/{id}", ga.handleDeleteGroup)
+}
+
+// handleListGroups handles HTTP GET requests for a page of groups.
+//
+// It supports equality filters on id and displayName, pagination with startIndex and count, and
+// excludedAttributes=members to leave out the members of large groups. It responds with HTTP
+// 200 OK and a ListResponse, or 400 Bad Request for other filters.
+func (ga *GroupsApi) handleListGroups(w http.ResponseWriter, r *http.Request) {
+	f, ok := parseFilter(r.URL.Query().Get("filter"))
+	if !ok {
+		writeScimError(w, r, http.StatusBadRequest, scimTypeInvalidFilter, `filters must compare an attribute with "eq", e.g. displayName eq "Support"`)
+		return
+	}
+	if f != nil && f.Attribute != "id" && f.Attribute != "displayname" {
+		writeScimError(w, r, http.StatusBadRequest, scimTypeInvalidFilter, "groups can be filtered by id and displayName")
+		return
+	}
+	startIndex, count := pagination(r)
+
+	groups, err := ga.manageGroupsPort.ListGroups(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if f != nil {
+		name := f.Value
+		if f.Attribute == "displayname" {
+			name = toGroupName(f.Value)
+		}
+		var matches []domain.Group
+		for _, group := range groups {
+			if group.Name == name {
+				matches = append(matches, group)
+			}
+		}
+		groups = matches
+	}
+
+	excludeMembers := strings.Contains(strings.ToLower(r.URL.Query().Get("excludedAttributes")), "members")
+	page := paginate(groups, startIndex, count)
+	response := listResponse{Schemas: []string{schemaListResponse}, TotalResults: len(groups), StartIndex: startIndex, ItemsPerPage: len(page), Resources: make([]any, 0, len(page))}
+	for _, group := range page {
+		if excludeMembers {
+			group.Members = nil
+		}
+		response.Resources = append(response.Resources, ga.toGroupResponse(r, group))
+	}
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// handleCreateGroup handles HTTP POST requests that create a group without roles.
+//
+// The name of the group is the displayName in lower case with other characters than letters,
+// digits, dashes and underscores replaced by dashes. It responds with HTTP 201 Created and the
+// group, 400 Bad Request for an invalid displayName, 404 Not Found if a member does not exist,
+// or 409 Conflict if the group exists.
+func (ga *GroupsApi) handleCreateGroup(w http.ResponseWriter, r *http.Request) {
+	var request groupRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+	name := toGroupName(request.DisplayName)
+	if !domain.ValidGroupName(name) {
+		writeScimError(w, r, http.StatusBadRequest, scimTypeInvalidValue, "displayName must contain up to 64 letters, digits, dashes and underscores")
+		return
+	}
+	if _, err := ga.manageGroupsPort.GetGroup(r.Context(), name); err == nil {
+		writeScimError(w, r, http.StatusConflict, scimTypeUniqueness, "group "+name+" exists")
+		return
+	} else if !errors.Is(err, errorx.ErrGroupNotFound) {
+		writeError(w, r, err)
+		return
+	}
+
+	if _, err := ga.manageGroupsPort.SaveGroup(r.Context(), name, nil); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	for _, m := range request.Members {
+		if err := ga.manageGroupsPort.AddGroupMember(r.Context(), name, m.Value); err != nil {
+			// the group is created with all of its members or not at all, so the client can retry
+			if deleteErr := ga.manageGroupsPort.DeleteGroup(r.Context(), name); deleteErr != nil {
+				logger.ErrorContext(r.Context(), "Error deleting partially created group", "group", name, "error", deleteErr)
+			}
+			writeError(w, r, err)
+			return
+		}
+	}
+
+	group, err := ga.manageGroupsPort.GetGroup(r.Context(), name)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	response := ga.toGroupResponse(r, group)
+	w.Header().Set("Location", response.Meta.Location)
+	writeJSON(w, r, http.StatusCreated, response)
+}
+
+// handleGetGroup handles HTTP GET requests for a single group.
+//
+// It responds with HTTP 200 OK and the group, or 404 Not Found if the group does not exist.
+func (ga *GroupsApi) handleGetGroup(w http.ResponseWriter, r *http.Request) {
+	group, err := ga.manageGroupsPort.GetGroup(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, r, http.StatusOK, ga.toGroupResponse(r, group))
+}
+
+// handleReplaceGroup handles HTTP PUT requests that replace the members of a group.
+//
+// It responds with HTTP 200 OK and the group, 400 Bad Request if the displayName changes the
+// name of the group, or 404 Not Found if the group or a member does not exist.
+func (ga *GroupsApi) handleReplaceGroup(w http.ResponseWriter, r *http.Request) {
+	var request groupRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+	group, err := ga.manageGroupsPort.GetGroup(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if request.DisplayName != "" && toGroupName(request.DisplayName) != group.Name {
+		writeScimError(w, r, http.StatusBadRequest, scimTypeMutability, "groups cannot be renamed")
+		return
+	}
+
+	wanted := make(map[string]bool, len(request.Members))
+	for _, m := range request.Members {
+		wanted[m.Value] = true
+	}
+	current := make(map[string]bool, len(group.Members))
+	for _, userID := range group.Members {
+		current[userID] = true
+		if !wanted[userID] {
+			if err := ga.manageGroupsPort.RemoveGroupMember(r.Context(), group.Name, userID); err != nil {
+				writeError(w, r, err)
+				return
+			}
+		}
+	}
+	for userID := range wanted {
+		if !current[userID] {
+			if err := ga.manageGroupsPort.AddGroupMember(r.Context(), group.Name, userID); err != nil {
+				writeError(w, r, err)
+				return
+			}
+		}
+	}
+	ga.writeGroup(w, r, group.Name)
+}
+
+// handlePatchGroup handles HTTP PATCH requests that add, remove or replace members of a group.
+//
+// Members are removed by value or with a selector like members[value eq "42"]; a remove without
+// a value or selector removes all members. It responds with HTTP 200 OK and the group, 400 Bad
+// Request for invalid operations or a changed displayName, or 404 Not Found if the group or a
+// member does not exist.
+func (ga *GroupsApi) handlePatchGroup(w http.ResponseWriter, r *http.Request) {
+	var request patchRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+	group, err := ga.manageGroupsPort.GetGroup(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	for _, operation := range request.Operations {
+		if err := ga.applyOperation(r.Context(), &group, operation); err != nil {
+			var scimErr *operationError
+			if errors.As(err, &scimErr) {
+				writeScimError(w, r, http.StatusBadRequest, scimErr.scimType, scimErr.detail)
+				return
+			}
+			writeError(w, r, err)
+			return
+		}
+	}
+	ga.writeGroup(w, r, group.Name)
+}
+
+// handleDeleteGroup handles HTTP DELETE requests for a group.
+//
+// On success, it responds with HTTP 204 No Content, or 404 Not Found if the group does not exist.
+func (ga *GroupsApi) handleDeleteGroup(w http.ResponseWriter, r *http.Request) {
+	if err := ga.manageGroupsPort.DeleteGroup(r.Context(), r.PathValue("id")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// operationError is a PATCH operation the client has to correct.
+type operationError struct {
+	scimType string
+	detail   string
+}
+
+// Error returns the detail of the error.
+func (oe *operationError) Error() string {
+	return oe.detail
+}
+
+// applyOperation applies a PATCH operation to the members of a group and keeps the members of
+// group in step, so later operations see the changes of earlier ones.
+func (ga *GroupsApi) applyOperation(ctx context.Context, group *domain.Group, operation patchOperation) error {
+	op := strings.ToLower(operation.Op)
+	path := strings.TrimSpace(operation.Path)
+
+	if path == "" {
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(operation.Value, &values); err != nil {
+			return &operationError{scimTypeInvalidValue, "operations without a path need an object value"}
+		}
+		for attribute, value := range values {
+			if err := ga.applyOperation(ctx, group, patchOperation{Op: operation.Op, Path: attribute, Value: value}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if strings.EqualFold(path, "displayName") {
+		var displayName string
+		if err := json.Unmarshal(operation.Value, &displayName); err != nil || toGroupName(displayName) != group.Name {
+			return &operationError{scimTypeMutability, "groups cannot be renamed"}
+		}
+		return nil
+	}
+	if match := memberFilterPattern.FindStringSubmatch(path); match != nil {
+		var userID string
+		if err := json.Unmarshal([]byte(match[1]), &userID); err != nil {
+			return &operationError{scimTypeInvalidPath, "malformed member selector"}
+		}
+		if op != "remove" {
+			return &operationError{scimTypeInvalidPath, "member selectors can only be removed"}
+		}
+		return ga.removeMembers(ctx, group, []string{userID})
+	}
+	if !strings.EqualFold(path, "members") {
+		return &operationError{scimTypeInvalidPath, "only members and displayName can be changed"}
+	}
+
+	var members []member
+	if len(operation.Value) > 0 {
+		if err := json.Unmarshal(operation.Value, &members); err != nil {
+			return &operationError{scimTypeInvalidValue, "members must be a list of objects with a value"}
+		}
+	}
+	userIDs := make([]string, 0, len(members))
+	for _, m := range members {
+		userIDs = append(userIDs, m.Value)
+	}
+	switch op {
+	case "add":
+		return ga.addMembers(ctx, group, userIDs)
+	case "remove":
+		if len(members) == 0 {
+			return ga.removeMembers(ctx, group, group.Members)
+		}
+		return ga.removeMembers(ctx, group, userIDs)
+	case "replace":
+		if err := ga.removeMembers(ctx, group, group.Members); err != nil {
+			return err
+		}
+		return ga.addMembers(ctx, group, userIDs)
+	}
+	return &operationError{scimTypeInvalidSyntax, "op must be add, replace or remove"}
+}
+
+// addMembers adds users to a group.
+func (ga *GroupsApi) addMembers(ctx context.Context, group *domain.Group, userIDs []string) error {
+	for _, userID := range userIDs {
+		if err := ga.manageGroupsPort.AddGroupMember(ctx, group.Name, userID); err != nil {
+			return err
+		}
+		group.Members = append(group.Members, userID)
+	}
+	return nil
+}
+
+// removeMembers removes users from a group.
+func (ga *GroupsApi) removeMembers(ctx context.Context, group *domain.Group, userIDs []string) error {
+	removed := make(map[string]bool, len(userIDs))
+	for _, userID := range userIDs {
+		if err := ga.manageGroupsPort.RemoveGroupMember(ctx, group.Name, userID); err != nil {
+			return err
+		}
+		removed[userID] = true
+	}
+	members := make([]string, 0, len(group.Members))
+	for _, userID := range group.Members {
+		if !removed[userID] {
+			members = append(members, userID)
+		}
+	}
+	group.Members = members
+	return nil
+}
+
+// writeGroup responds with HTTP 200 OK and the current state of a group.
+func (ga *GroupsApi) writeGroup(w http.ResponseWriter, r *http.Request, name string) {
+	group, err := ga.manageGroupsPort.GetGroup(r.Context(), name)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, r, http.StatusOK, ga.toGroupResponse(r, group))
+}
+
+// toGroupResponse converts a group into its SCIM representation.
+func (ga *GroupsApi) toGroupResponse(r *http.Request, group domain.Group) groupResponse {
+	response := groupResponse{
+		Schemas:     []string{schemaGroup},
+		ID:          group.Name,
+		DisplayName: group.Name,
+		Meta:        meta{ResourceType: "Group", Location: baseURL(ga.baseURL, r) + "/Groups/" + group.Name},
+	}
+	for _, userID := range group.Members {
+		response.Members = append(response.Members, member{Value: userID})
+	}
+	if !group.UpdatedAt.IsZero() {
+		response.Meta.LastModified = &group.UpdatedAt
+	}
+	return response
+}
+
+// toGroupName derives the name of a group from its displayName, e.g. "support-team" from "Support Team".
+func toGroupName(displayName string) string {
+	name := invalidGroupNameChars.ReplaceAllString(strings.ToLower(strings.TrimSpace(displayName)), "-")
+	return strings.Trim(name, "-")
+}
@@ -0,0 +1,121 @@
+// Package scim serves the SCIM 2.0 protocol (RFC 7643, RFC 7644), so identity providers such as
+// Okta or Microsoft Entra ID provision and deprovision accounts and groups automatically.
+package scim
+
+import (
+	"encoding/json"
+	"time"
+	"user-auth-hexagonal-architecture/internal/logging"
+)
+
+// logger writes the log records of this package.
+var logger = logging.Component("scim")
+
+// ContentType is the media type of SCIM messages.
+const ContentType = "application/scim+json"
+
+// Schemas of the resources and messages.
+const (
+	schemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	schemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	schemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	schemaResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+	schemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	schemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+// maxPageSize is the largest number of resources returned at once.
+const maxPageSize = 100
+
+// maxRequestBodyBytes bounds the size of request bodies, which may list many group members.
+const maxRequestBodyBytes = 1 << 20
+
+// meta holds the metadata of a resource.
+type meta struct {
+	ResourceType string     `json:"resourceType"`
+	Created      *time.Time `json:"created,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	Location     string     `json:"location"`
+}
+
+// email is an email address of a user.
+type email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// userRequest represents the JSON structure of a user created or replaced by the client.
+// Attributes the service does not store, e.g. name or externalId, are ignored.
+type userRequest struct {
+	UserName string  `json:"userName"`
+	Active   *bool   `json:"active"`
+	Emails   []email `json:"emails"`
+	Password string  `json:"password"`
+}
+
+// primaryEmail returns the primary email address of the request, or the first one if none is primary.
+func (ur userRequest) primaryEmail() string {
+	for _, e := range ur.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	if len(ur.Emails) > 0 {
+		return ur.Emails[0].Value
+	}
+	return ""
+}
+
+// userResponse represents the JSON structure of a user.
+type userResponse struct {
+	Schemas  []string `json:"schemas"`
+	ID       string   `json:"id"`
+	UserName string   `json:"userName"`
+	Active   bool     `json:"active"`
+	Emails   []email  `json:"emails,omitempty"`
+	Meta     meta     `json:"meta"`
+}
+
+// member is a member of a group, identified by the id of the user.
+type member struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+}
+
+// groupRequest represents the JSON structure of a group created or replaced by the client.
+type groupRequest struct {
+	DisplayName string   `json:"displayName"`
+	Members     []member `json:"members"`
+}
+
+// groupResponse represents the JSON structure of a group.
+type groupResponse struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id"`
+	DisplayName string   `json:"displayName"`
+	Members     []member `json:"members,omitempty"`
+	Meta        meta     `json:"meta"`
+}
+
+// listResponse represents the JSON structure of a page of resources.
+type listResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+// patchRequest represents the JSON structure of a PATCH request.
+type patchRequest struct {
+	Operations []patchOperation `json:"Operations"`
+}
+
+// patchOperation is a single change of a PATCH request. The value is decoded by the resource, as its
+// type depends on the path.
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
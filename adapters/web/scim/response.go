@@ -0,0 +1,148 @@
+package scim
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// Types of SCIM errors (RFC 7644, section 3.12).
+const (
+	scimTypeInvalidFilter = "invalidFilter"
+	scimTypeInvalidSyntax = "invalidSyntax"
+	scimTypeInvalidPath   = "invalidPath"
+	scimTypeInvalidValue  = "invalidValue"
+	scimTypeMutability    = "mutability"
+	scimTypeUniqueness    = "uniqueness"
+)
+
+// errorResponse represents the JSON structure of a SCIM error.
+type errorResponse struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+// writeJSON sends a SCIM response with the given status.
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, body any) {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logger.ErrorContext(r.Context(), "Error encoding SCIM response", "error", err)
+	}
+}
+
+// writeScimError sends a SCIM error.
+func writeScimError(w http.ResponseWriter, r *http.Request, status int, scimType string, detail string) {
+	writeJSON(w, r, status, errorResponse{
+		Schemas:  []string{schemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+}
+
+// writeError reports an error of a use case as a SCIM error. Typed domain errors keep the status
+// of their problem code, taken usernames and emails are reported as uniqueness violations and
+// invalid input as invalid values. Any other error is logged and answered with a generic 500.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	if domainErr, ok := errorx.As(err); ok {
+		status := problem.Status(problem.Code(domainErr.Code))
+		if status != http.StatusInternalServerError {
+			detail := domainErr.Message
+			if domainErr.Detail != "" {
+				detail += ": " + domainErr.Detail
+			}
+			var scimType string
+			switch {
+			case domainErr.Code == errorx.CodeUsernameTaken || domainErr.Code == errorx.CodeEmailTaken:
+				scimType = scimTypeUniqueness
+			case status == http.StatusBadRequest || status == http.StatusUnprocessableEntity:
+				status, scimType = http.StatusBadRequest, scimTypeInvalidValue
+			}
+			writeScimError(w, r, status, scimType, detail)
+			return
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		logger.WarnContext(r.Context(), "Timeout handling SCIM request", "method", r.Method, "path", r.URL.Path, "error", err)
+		writeScimError(w, r, http.StatusServiceUnavailable, "", "request timed out")
+		return
+	}
+
+	logger.ErrorContext(r.Context(), "Unexpected error handling SCIM request", "method", r.Method, "route", r.Pattern, "path", r.URL.Path, "error", err)
+	writeScimError(w, r, http.StatusInternalServerError, "", "internal server error")
+}
+
+// decodeRequest decodes a JSON request body into target and answers malformed bodies with
+// 400 Bad Request. Unknown attributes are ignored, as identity providers send extension schemas.
+func decodeRequest(w http.ResponseWriter, r *http.Request, target any) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodyBytes)
+	if err := json.NewDecoder(r.Body).Decode(target); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeScimError(w, r, http.StatusRequestEntityTooLarge, "", "request body is too large")
+			return false
+		}
+		writeScimError(w, r, http.StatusBadRequest, scimTypeInvalidSyntax, "request body must be a JSON object")
+		return false
+	}
+	return true
+}
+
+// pagination reads the 1-based startIndex and the count of a list request. Values out of range
+// are clamped as RFC 7644, section 3.4.2.4 asks for.
+func pagination(r *http.Request) (startIndex int, count int) {
+	startIndex, count = 1, maxPageSize
+	if value, err := strconv.Atoi(r.URL.Query().Get("startIndex")); err == nil && value > 1 {
+		startIndex = value
+	}
+	if value, err := strconv.Atoi(r.URL.Query().Get("count")); err == nil {
+		count = min(max(value, 0), maxPageSize)
+	}
+	return startIndex, count
+}
+
+// baseURL returns the URL the resources are located under, the configured URL if set, otherwise
+// derived from the request.
+func baseURL(configured string, r *http.Request) string {
+	if configured != "" {
+		return strings.TrimSuffix(configured, "/") + "/scim/v2"
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + "/scim/v2"
+}
+
+// RequireToken returns a middleware that admits only requests carrying the static bearer token
+// configured at the identity provider. SCIM clients do not obtain tokens from the service, so
+// they are not subject to the authorization of the API.
+//
+// Parameters:
+//   - token: The bearer token the identity provider sends
+//
+// Returns:
+//   - func(http.Handler) http.Handler: The middleware
+func RequireToken(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scheme, presented, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+			if !ok || !strings.EqualFold(scheme, "Bearer") || subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="scim"`)
+				writeScimError(w, r, http.StatusUnauthorized, "", "a valid bearer token is required")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
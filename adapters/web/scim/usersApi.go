@@ -0,0 +1,406 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// UsersApi serves the SCIM Users resource. Users are created through the provisioning use case,
+// with a temporary password if the client sends one and as an invitation otherwise. A user is
+// active unless disabled; deactivating disables the account, deleting it soft-deletes the account,
+// revokes its sessions and removes its MFA factors until the retention job purges it.
+// The email address is managed by its owner and cannot be changed by the client.
+type UsersApi struct {
+	searchUsersPort      usecases.SearchUsersPort
+	getUserPort          usecases.GetUserPort
+	provisionUsersPort   usecases.ProvisionUsersPort
+	renameUserPort       usecases.RenameUserPort
+	changeUserStatusPort usecases.ChangeUserStatusPort
+	deleteUserPort       usecases.DeleteUserPort
+	baseURL              string
+}
+
+// userChanges are the attributes a PUT or PATCH request sets; nil attributes stay unchanged.
+type userChanges struct {
+	userName *string
+	active   *bool
+	email    *string
+}
+
+// NewUsersApiAdapter creates a new UsersApi with the given use case ports.
+//
+// Parameters:
+//   - searchUsersPort: Port for looking up users by username or email
+//   - getUserPort: Port for loading a single user
+//   - provisionUsersPort: Port for creating users
+//   - renameUserPort: Port for changing usernames
+//   - changeUserStatusPort: Port for disabling and enabling users
+//   - deleteUserPort: Port for deleting users
+//   - baseURL: The public URL of the service the resource locations are built from, derived from
+//     the request if empty
+//
+// Returns:
+//   - *UsersApi: A pointer to the newly created UsersApi
+func NewUsersApiAdapter(searchUsersPort usecases.SearchUsersPort, getUserPort usecases.GetUserPort, provisionUsersPort usecases.ProvisionUsersPort, renameUserPort usecases.RenameUserPort, changeUserStatusPort usecases.ChangeUserStatusPort, deleteUserPort usecases.DeleteUserPort, baseURL string) *UsersApi {
+	return &UsersApi{searchUsersPort, getUserPort, provisionUsersPort, renameUserPort, changeUserStatusPort, deleteUserPort, baseURL}
+}
+
+// InitUsersRoutes sets up the HTTP routes of the Users resource, relative to the SCIM base path.
+func (ua *UsersApi) InitUsersRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /Users", ua.handleListUsers)
+	mux.HandleFunc("POST /Users", ua.handleCreateUser)
+	mux.HandleFunc("GET /Users/{id}", ua.handleGetUser)
+	mux.HandleFunc("PUT /Users/{id}", ua.handleReplaceUser)
+	mux.HandleFunc("PATCH /Users/{id}", ua.handlePatchUser)
+	mux.HandleFunc("DELETE /Users/{id}", ua.handleDeleteUser)
+}
+
+// handleListUsers handles HTTP GET requests for a page of users.
+//
+// It supports equality filters on id, userName and emails.value, which identity providers use to
+// match existing accounts, and pagination with startIndex and count. It responds with HTTP 200 OK
+// and a ListResponse, or 400 Bad Request for other filters.
+func (ua *UsersApi) handleListUsers(w http.ResponseWriter, r *http.Request) {
+	f, ok := parseFilter(r.URL.Query().Get("filter"))
+	if !ok {
+		writeScimError(w, r, http.StatusBadRequest, scimTypeInvalidFilter, `filters must compare an attribute with "eq", e.g. userName eq "jdoe"`)
+		return
+	}
+	startIndex, count := pagination(r)
+
+	var users []usecases.UserView
+	var total int
+	switch {
+	case f == nil:
+		page, err := ua.searchUsersPort.SearchUsers(r.Context(), "", int64(startIndex-1), int64(max(count, 1)))
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		users, total = page.Users[:min(count, len(page.Users))], int(page.Total)
+	case f.Attribute == "id":
+		user, err := ua.getUserPort.GetUser(r.Context(), f.Value)
+		if err != nil && !errors.Is(err, errorx.ErrUserNotFound) {
+			writeError(w, r, err)
+			return
+		}
+		if err == nil {
+			users, total = paginate([]usecases.UserView{user}, startIndex, count), 1
+		}
+	case f.Attribute == "username" || f.Attribute == "emails" || f.Attribute == "emails.value":
+		matches, err := ua.findUsers(r.Context(), f)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		users, total = paginate(matches, startIndex, count), len(matches)
+	default:
+		writeScimError(w, r, http.StatusBadRequest, scimTypeInvalidFilter, "users can be filtered by id, userName and emails.value")
+		return
+	}
+
+	response := listResponse{Schemas: []string{schemaListResponse}, TotalResults: total, StartIndex: startIndex, ItemsPerPage: len(users), Resources: make([]any, 0, len(users))}
+	for _, user := range users {
+		response.Resources = append(response.Resources, ua.toUserResponse(r, user))
+	}
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// handleCreateUser handles HTTP POST requests that create a user.
+//
+// A password in the request becomes a temporary password the user has to change at the first
+// login; without a password the user is invited by email. Inactive users need a password, as
+// invitations cannot be disabled before they are accepted. It responds with HTTP 201 Created and
+// the user, 400 Bad Request for invalid attributes, or 409 Conflict if the username or email is taken.
+func (ua *UsersApi) handleCreateUser(w http.ResponseWriter, r *http.Request) {
+	var request userRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+	if request.UserName == "" {
+		writeScimError(w, r, http.StatusBadRequest, scimTypeInvalidValue, "userName is required")
+		return
+	}
+	inactive := request.Active != nil && !*request.Active
+	if inactive && request.Password == "" {
+		writeScimError(w, r, http.StatusBadRequest, scimTypeInvalidValue, "inactive users need a password, invitations cannot be disabled")
+		return
+	}
+
+	report, err := ua.provisionUsersPort.ProvisionUsers(r.Context(), []usecases.UserSpec{{
+		Username:          request.UserName,
+		Email:             request.primaryEmail(),
+		TemporaryPassword: request.Password,
+		Invite:            request.Password == "",
+	}})
+	if err == nil && len(report.Results) == 1 {
+		err = report.Results[0].Err
+	}
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	id := report.Results[0].UserID
+	if inactive {
+		if err := ua.changeUserStatusPort.DisableUser(r.Context(), id); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+
+	user, err := ua.getUserPort.GetUser(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	response := ua.toUserResponse(r, user)
+	w.Header().Set("Location", response.Meta.Location)
+	writeJSON(w, r, http.StatusCreated, response)
+}
+
+// handleGetUser handles HTTP GET requests for a single user.
+//
+// It responds with HTTP 200 OK and the user, or 404 Not Found if the user does not exist.
+func (ua *UsersApi) handleGetUser(w http.ResponseWriter, r *http.Request) {
+	user, err := ua.getUserPort.GetUser(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, r, http.StatusOK, ua.toUserResponse(r, user))
+}
+
+// handleReplaceUser handles HTTP PUT requests that replace a user.
+//
+// The userName and active attributes are applied; an absent active attribute keeps the status.
+// It responds with HTTP 200 OK and the user, 400 Bad Request for invalid attributes or a changed
+// email, 404 Not Found if the user does not exist, or 409 Conflict if the username is taken.
+func (ua *UsersApi) handleReplaceUser(w http.ResponseWriter, r *http.Request) {
+	var request userRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+	if request.UserName == "" {
+		writeScimError(w, r, http.StatusBadRequest, scimTypeInvalidValue, "userName is required")
+		return
+	}
+
+	changes := userChanges{userName: &request.UserName, active: request.Active}
+	if len(request.Emails) > 0 {
+		email := request.primaryEmail()
+		changes.email = &email
+	}
+	ua.applyChanges(w, r, changes)
+}
+
+// handlePatchUser handles HTTP PATCH requests that change attributes of a user.
+//
+// The operations may set userName and active, either by path or, as Okta does, with an object
+// value and no path. Booleans sent as strings, as Microsoft Entra ID does, are accepted. Other
+// attributes are ignored. It responds with HTTP 200 OK and the user, 400 Bad Request for invalid
+// operations or a changed email, or 404 Not Found if the user does not exist.
+func (ua *UsersApi) handlePatchUser(w http.ResponseWriter, r *http.Request) {
+	var request patchRequest
+	if !decodeRequest(w, r, &request) {
+		return
+	}
+
+	var changes userChanges
+	for _, operation := range request.Operations {
+		op := strings.ToLower(operation.Op)
+		if op != "add" && op != "replace" && op != "remove" {
+			writeScimError(w, r, http.StatusBadRequest, scimTypeInvalidSyntax, "op must be add, replace or remove")
+			return
+		}
+		values := map[string]json.RawMessage{}
+		if operation.Path == "" {
+			if err := json.Unmarshal(operation.Value, &values); err != nil {
+				writeScimError(w, r, http.StatusBadRequest, scimTypeInvalidValue, "operations without a path need an object value")
+				return
+			}
+		} else {
+			values[operation.Path] = operation.Value
+		}
+		for path, value := range values {
+			if err := changes.set(op, path, value); err != nil {
+				writeScimError(w, r, http.StatusBadRequest, scimTypeInvalidValue, err.Error())
+				return
+			}
+		}
+	}
+	ua.applyChanges(w, r, changes)
+}
+
+// handleDeleteUser handles HTTP DELETE requests for a user.
+//
+// On success, it responds with HTTP 204 No Content, or 404 Not Found if the user does not exist.
+func (ua *UsersApi) handleDeleteUser(w http.ResponseWriter, r *http.Request) {
+	if err := ua.deleteUserPort.DeleteUser(r.Context(), r.PathValue("id")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// applyChanges renames, disables or enables the user of the path as the changes ask for and
+// responds with the updated user. Only disabled users are enabled, locks are not lifted.
+func (ua *UsersApi) applyChanges(w http.ResponseWriter, r *http.Request, changes userChanges) {
+	id := r.PathValue("id")
+	user, err := ua.getUserPort.GetUser(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if changes.email != nil && !strings.EqualFold(*changes.email, user.Email) {
+		writeScimError(w, r, http.StatusBadRequest, scimTypeMutability, "email addresses are changed and verified by their owners")
+		return
+	}
+
+	if changes.userName != nil && *changes.userName != user.Username {
+		if err := ua.renameUserPort.RenameUser(r.Context(), id, *changes.userName); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+	if changes.active != nil {
+		switch {
+		case !*changes.active && user.Status != domain.StatusDisabled:
+			err = ua.changeUserStatusPort.DisableUser(r.Context(), id)
+		case *changes.active && user.Status == domain.StatusDisabled:
+			err = ua.changeUserStatusPort.EnableUser(r.Context(), id)
+		}
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+
+	if user, err = ua.getUserPort.GetUser(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, r, http.StatusOK, ua.toUserResponse(r, user))
+}
+
+// findUsers returns the users whose username or email equals the value of the filter, ignoring
+// case. The search use case matches substrings, so its pages are narrowed to exact matches.
+func (ua *UsersApi) findUsers(ctx context.Context, f *filter) ([]usecases.UserView, error) {
+	var matches []usecases.UserView
+	for offset := int64(0); ; offset += maxPageSize {
+		page, err := ua.searchUsersPort.SearchUsers(ctx, f.Value, offset, maxPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range page.Users {
+			value := user.Email
+			if f.Attribute == "username" {
+				value = user.Username
+			}
+			if strings.EqualFold(value, f.Value) {
+				matches = append(matches, user)
+			}
+		}
+		if len(page.Users) == 0 || offset+int64(len(page.Users)) >= page.Total {
+			return matches, nil
+		}
+	}
+}
+
+// set records the change of a PATCH operation on an attribute. Attributes the service does not
+// store are ignored.
+func (uc *userChanges) set(op string, path string, value json.RawMessage) error {
+	attribute := strings.ToLower(path)
+	switch {
+	case attribute == "username":
+		if op == "remove" {
+			return errors.New("userName cannot be removed")
+		}
+		var userName string
+		if err := json.Unmarshal(value, &userName); err != nil || userName == "" {
+			return errors.New("userName must be a string")
+		}
+		uc.userName = &userName
+	case attribute == "active":
+		if op == "remove" {
+			return errors.New("active cannot be removed")
+		}
+		active, ok := decodeBool(value)
+		if !ok {
+			return errors.New("active must be a boolean")
+		}
+		uc.active = &active
+	case attribute == "emails" || strings.HasPrefix(attribute, "emails[") || attribute == "emails.value":
+		if op == "remove" {
+			return errors.New("emails cannot be removed")
+		}
+		email, ok := decodeEmail(value)
+		if !ok {
+			return errors.New("emails must be a list of email addresses")
+		}
+		uc.email = &email
+	}
+	return nil
+}
+
+// decodeBool decodes a boolean, which some clients send as the string "True" or "False".
+func decodeBool(value json.RawMessage) (bool, bool) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, true
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		switch strings.ToLower(s) {
+		case "true":
+			return true, true
+		case "false":
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// decodeEmail decodes the primary email address of a list of emails, or a single address.
+func decodeEmail(value json.RawMessage) (string, bool) {
+	var address string
+	if err := json.Unmarshal(value, &address); err == nil {
+		return address, true
+	}
+	var emails []email
+	if err := json.Unmarshal(value, &emails); err != nil || len(emails) == 0 {
+		return "", false
+	}
+	return userRequest{Emails: emails}.primaryEmail(), true
+}
+
+// toUserResponse converts a user into its SCIM representation.
+func (ua *UsersApi) toUserResponse(r *http.Request, user usecases.UserView) userResponse {
+	response := userResponse{
+		Schemas:  []string{schemaUser},
+		ID:       user.ID,
+		UserName: user.Username,
+		Active:   user.Status != domain.StatusDisabled,
+		Meta:     meta{ResourceType: "User", Location: baseURL(ua.baseURL, r) + "/Users/" + user.ID},
+	}
+	if user.Email != "" {
+		response.Emails = []email{{Value: user.Email, Type: "work", Primary: true}}
+	}
+	if !user.CreatedAt.IsZero() {
+		response.Meta.Created = &user.CreatedAt
+	}
+	return response
+}
+
+// paginate returns the page of items starting at the 1-based startIndex.
+func paginate[T any](items []T, startIndex int, count int) []T {
+	if startIndex > len(items) {
+		return nil
+	}
+	return items[startIndex-1 : min(startIndex-1+count, len(items))]
+}
@@ -0,0 +1,118 @@
+// Package validation checks incoming request DTOs and collects field-level errors,
+// so malformed input is rejected at the edge instead of reaching the use cases.
+package validation
+
+import (
+	"fmt"
+	"net/url"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// MaxPasswordBytes is the longest password bcrypt can hash; longer input would be rejected by the hasher.
+const MaxPasswordBytes = domain.MaxPasswordBytes
+
+// maxUsernameBytes bounds usernames before they reach the username policy.
+const maxUsernameBytes = 256
+
+// FieldError describes why a single field was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Validator accumulates field errors. Rules after the first failing rule of a field are skipped,
+// so every field reports at most one error.
+type Validator struct {
+	errors []FieldError
+	failed map[string]bool
+}
+
+// New creates an empty Validator.
+//
+// Returns:
+//   - *Validator: A pointer to the newly created Validator
+func New() *Validator {
+	return &Validator{failed: map[string]bool{}}
+}
+
+// Valid reports whether no rule has failed.
+func (v *Validator) Valid() bool {
+	return len(v.errors) == 0
+}
+
+// Errors returns the collected field errors in the order they were detected.
+func (v *Validator) Errors() []FieldError {
+	return v.errors
+}
+
+// Add records a field error unless the field already failed.
+//
+// Parameters:
+//   - field: The JSON name of the field
+//   - code: A machine-readable error code such as "required"
+//   - message: A human-readable explanation
+func (v *Validator) Add(field string, code string, message string) {
+	if v.failed[field] {
+		return
+	}
+	v.failed[field] = true
+	v.errors = append(v.errors, FieldError{field, code, message})
+}
+
+// Required checks that a value is not empty.
+func (v *Validator) Required(field string, value string) *Validator {
+	if value == "" {
+		v.Add(field, "required", fmt.Sprintf("%s is required", field))
+	}
+	return v
+}
+
+// MaxBytes checks that a value does not exceed a number of bytes.
+func (v *Validator) MaxBytes(field string, value string, max int) *Validator {
+	if len(value) > max {
+		v.Add(field, "too_long", fmt.Sprintf("%s must not exceed %d bytes", field, max))
+	}
+	return v
+}
+
+// Username checks that a new username is given and of bounded size. The configurable username
+// policy is enforced by the use cases, which report violations as domain errors.
+func (v *Validator) Username(field string, value string) *Validator {
+	switch {
+	case value == "":
+		v.Add(field, "required", fmt.Sprintf("%s is required", field))
+	case len(value) > maxUsernameBytes:
+		v.Add(field, "too_long", fmt.Sprintf("%s must not exceed %d bytes", field, maxUsernameBytes))
+	}
+	return v
+}
+
+// Email checks that a non-empty value is a single bare email address, following the rules of
+// domain.NewEmail. Empty values are accepted; combine with Required for mandatory addresses.
+func (v *Validator) Email(field string, value string) *Validator {
+	if value == "" {
+		return v
+	}
+	if len(value) > domain.MaxEmailLength {
+		v.Add(field, "too_long", fmt.Sprintf("%s must not exceed %d characters", field, domain.MaxEmailLength))
+		return v
+	}
+	if _, err := domain.NewEmail(value); err != nil {
+		v.Add(field, "invalid_format", fmt.Sprintf("%s must be a valid email address", field))
+	}
+	return v
+}
+
+// URL checks that a value is an absolute http or https URL.
+func (v *Validator) URL(field string, value string) *Validator {
+	if value == "" {
+		v.Add(field, "required", fmt.Sprintf("%s is required", field))
+		return v
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		v.Add(field, "invalid_format", fmt.Sprintf("%s must be an absolute http or https URL", field))
+	}
+	return v
+}
@@ -0,0 +1,318 @@
+// Package webhook delivers domain events to external HTTP endpoints.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	mathrand "math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/resilience"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/logging"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/requestid"
+)
+
+// logger writes the log records of this package.
+var logger = logging.Component("webhook")
+
+// Headers sent with every delivery.
+const (
+	// SignatureHeader carries the delivery timestamp and the HMAC-SHA256 signature, e.g. "t=1700000000,v1=5257a8...".
+	// The signature is computed with the subscription secret over "<timestamp>.<body>".
+	SignatureHeader = "X-Webhook-Signature"
+	// EventHeader carries the name of the delivered event.
+	EventHeader = "X-Webhook-Event"
+	// DeliveryHeader carries the id of the delivery, which stays the same across retries.
+	DeliveryHeader = "X-Webhook-Delivery"
+)
+
+// DeliveryConfig configures the webhook delivery.
+type DeliveryConfig struct {
+	// Workers is the number of deliveries sent concurrently.
+	Workers int
+	// QueueSize is the number of deliveries that may wait for a worker. Deliveries exceeding it are dead-lettered.
+	QueueSize int
+	// MaxAttempts is the number of attempts before a delivery is dead-lettered.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry. It doubles with every further retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts.
+	MaxBackoff time.Duration
+	// Timeout bounds a single attempt.
+	Timeout time.Duration
+	// CircuitBreaker configures the breaker of every subscription endpoint. While it is open,
+	// attempts fail without a request and are retried after the backoff.
+	CircuitBreaker resilience.BreakerConfig
+}
+
+// DefaultDeliveryConfig returns a configuration with 4 workers and 6 attempts spread over roughly 15 minutes.
+func DefaultDeliveryConfig() DeliveryConfig {
+	return DeliveryConfig{
+		Workers:        4,
+		QueueSize:      1000,
+		MaxAttempts:    6,
+		InitialBackoff: 30 * time.Second,
+		MaxBackoff:     10 * time.Minute,
+		Timeout:        10 * time.Second,
+		CircuitBreaker: resilience.DefaultBreakerConfig(),
+	}
+}
+
+// payload is the JSON body of a delivery.
+type payload struct {
+	ID         string       `json:"id"`
+	Type       string       `json:"type"`
+	OccurredAt time.Time    `json:"occurredAt"`
+	Data       events.Event `json:"data"`
+}
+
+// delivery is a queued event for a single subscription. requestID is the id of the request that
+// emitted the event, empty for events of background jobs.
+type delivery struct {
+	id           string
+	subscription domain.WebhookSubscription
+	eventName    string
+	body         []byte
+	requestID    string
+}
+
+// HTTPDelivery posts signed JSON payloads to webhook subscriptions.
+// It implements the WebhookDeliveryPort interface from the messaging ports package.
+//
+// Deliveries are queued and sent by background workers. Failed attempts are retried with exponential
+// backoff; deliveries that still fail after the last attempt are stored as dead letters.
+type HTTPDelivery struct {
+	client      *http.Client
+	deadLetters persistence.WebhookDeadLetterPersistencePort
+	config      DeliveryConfig
+	queue       chan delivery
+	wg          sync.WaitGroup
+	mu          sync.Mutex
+	breakers    map[string]*resilience.CircuitBreaker
+}
+
+// NewHTTPDelivery creates a new HTTPDelivery. Call Start to begin sending.
+//
+// Parameters:
+//   - client: The HTTP client used for the deliveries
+//   - deadLetters: An implementation of WebhookDeadLetterPersistencePort for failed deliveries
+//   - config: The delivery configuration
+//
+// Returns:
+//   - *HTTPDelivery: A pointer to the newly created HTTPDelivery
+func NewHTTPDelivery(client *http.Client, deadLetters persistence.WebhookDeadLetterPersistencePort, config DeliveryConfig) *HTTPDelivery {
+	return &HTTPDelivery{client: client, deadLetters: deadLetters, config: config, queue: make(chan delivery, config.QueueSize), breakers: map[string]*resilience.CircuitBreaker{}}
+}
+
+// Start launches the workers. They keep running until the given context is cancelled.
+//
+// Parameters:
+//   - ctx: A context.Context controlling the lifetime of the workers
+func (hd *HTTPDelivery) Start(ctx context.Context) {
+	for range max(hd.config.Workers, 1) {
+		hd.wg.Add(1)
+		go func() {
+			defer hd.wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case d := <-hd.queue:
+					hd.send(ctx, d)
+				}
+			}
+		}()
+	}
+}
+
+// Wait blocks until all workers have returned after the context passed to Start was cancelled.
+func (hd *HTTPDelivery) Wait() {
+	hd.wg.Wait()
+}
+
+// Deliver queues an event for a subscription without blocking.
+//
+// Parameters:
+//   - ctx: A context.Context of the emitting use case; the delivery outlives it
+//   - subscription: The subscription to deliver to
+//   - event: The event to deliver
+func (hd *HTTPDelivery) Deliver(ctx context.Context, subscription domain.WebhookSubscription, event events.Event) {
+	id := newDeliveryID()
+	body, err := json.Marshal(payload{ID: id, Type: event.Name(), OccurredAt: event.OccurredAt(), Data: event})
+	if err != nil {
+		logger.ErrorContext(ctx, "Error encoding webhook payload", "event", event.Name(), "error", err)
+		return
+	}
+
+	d := delivery{id, subscription, event.Name(), body, requestid.FromContext(ctx)}
+	select {
+	case hd.queue <- d:
+	default:
+		hd.deadLetter(d, 0, "delivery queue full")
+	}
+}
+
+// send attempts a delivery until it succeeds, fails permanently or runs out of attempts.
+func (hd *HTTPDelivery) send(ctx context.Context, d delivery) {
+	var err error
+	attempt := 1
+	for ; attempt <= hd.config.MaxAttempts; attempt++ {
+		var retryable bool
+		retryable, err = hd.attempt(ctx, d)
+		if err == nil {
+			return
+		}
+		if !retryable || attempt == hd.config.MaxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			hd.deadLetter(d, attempt, fmt.Sprintf("%v (delivery stopped)", err))
+			return
+		case <-time.After(hd.backoff(attempt)):
+		}
+	}
+	hd.deadLetter(d, min(attempt, hd.config.MaxAttempts), err.Error())
+}
+
+// attempt posts the payload once through the circuit breaker of the subscription and reports
+// whether a failure is worth retrying. Only failures worth retrying count against the breaker, an
+// endpoint rejecting a payload is up.
+func (hd *HTTPDelivery) attempt(ctx context.Context, d delivery) (bool, error) {
+	var retryable bool
+	var rejected error
+	err := hd.breaker(d.subscription.ID).Execute(ctx, func(ctx context.Context) error {
+		var err error
+		if retryable, err = hd.post(ctx, d); err != nil && !retryable {
+			rejected = err
+			return nil
+		}
+		return err
+	})
+	if errors.Is(err, errorx.ErrDependencyUnavailable) {
+		return true, err
+	}
+	if rejected != nil {
+		return false, rejected
+	}
+	return retryable, err
+}
+
+// breaker returns the circuit breaker of a subscription.
+func (hd *HTTPDelivery) breaker(subscriptionID string) *resilience.CircuitBreaker {
+	hd.mu.Lock()
+	defer hd.mu.Unlock()
+	breaker, ok := hd.breakers[subscriptionID]
+	if !ok {
+		breaker = resilience.NewCircuitBreaker("webhook:"+subscriptionID, hd.config.CircuitBreaker)
+		hd.breakers[subscriptionID] = breaker
+	}
+	return breaker
+}
+
+// post sends the payload once and reports whether a failure is worth retrying.
+// Network errors, timeouts, 429 and 5xx responses are retried, any other non-2xx status is not.
+func (hd *HTTPDelivery) post(ctx context.Context, d delivery) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, hd.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.subscription.URL, bytes.NewReader(d.body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, d.eventName)
+	req.Header.Set(DeliveryHeader, d.id)
+	req.Header.Set(SignatureHeader, "t="+timestamp+",v1="+Sign(d.subscription.Secret, timestamp, d.body))
+	if d.requestID != "" {
+		req.Header.Set(requestid.Header, d.requestID)
+	}
+
+	resp, err := hd.client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode >= 500:
+		return true, fmt.Errorf("webhook endpoint answered %d", resp.StatusCode)
+	default:
+		return false, fmt.Errorf("webhook endpoint answered %d", resp.StatusCode)
+	}
+}
+
+// backoff returns the delay after the given attempt, doubling from InitialBackoff up to MaxBackoff
+// with up to 20% jitter, so retries of many deliveries do not hit the receiver at the same time.
+func (hd *HTTPDelivery) backoff(attempt int) time.Duration {
+	delay := hd.config.InitialBackoff
+	for i := 1; i < attempt && delay < hd.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, hd.config.MaxBackoff)
+	return delay + time.Duration(mathrand.Int64N(int64(delay)/5+1))
+}
+
+// deadLetter stores a delivery that was given up.
+func (hd *HTTPDelivery) deadLetter(d delivery, attempts int, reason string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := hd.deadLetters.SaveDeadLetter(ctx, domain.WebhookDeadLetter{
+		ID:             d.id,
+		TenantID:       d.subscription.TenantID,
+		SubscriptionID: d.subscription.ID,
+		URL:            d.subscription.URL,
+		EventName:      d.eventName,
+		Payload:        d.body,
+		Attempts:       attempts,
+		LastError:      reason,
+		FailedAt:       time.Now(),
+	})
+	if err != nil {
+		logger.Error("Error saving webhook dead letter", "delivery_id", d.id, "error", err)
+	}
+}
+
+// Sign computes the hex-encoded HMAC-SHA256 signature of a delivery.
+// Receivers verify a delivery by recomputing it from the timestamp and the raw body.
+//
+// Parameters:
+//   - secret: The subscription secret
+//   - timestamp: The unix timestamp from the signature header
+//   - body: The raw request body
+//
+// Returns:
+//   - string: The signature
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newDeliveryID returns a random delivery id.
+func newDeliveryID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
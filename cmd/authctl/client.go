@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// adminPath is the prefix of the admin API routes.
+const adminPath = "/api/v1/admin"
+
+// apiClient calls the admin API of a running service with the access token of an administrator.
+// The service applies its own rules, emits the events and notifies webhook subscribers as if the
+// request came from the admin console.
+type apiClient struct {
+	httpClient *http.Client
+	server     string
+	token      string
+}
+
+// apiError is a problem response of the admin API.
+type apiError struct {
+	problem.Details
+}
+
+// Error returns the title and detail of the problem and the rejected fields.
+func (e *apiError) Error() string {
+	message := fmt.Sprintf("%d %s", e.Status, e.Title)
+	if e.Detail != "" {
+		message += ": " + e.Detail
+	}
+	for _, field := range e.Errors {
+		message += fmt.Sprintf("\n  %s: %s", field.Field, field.Message)
+	}
+	return message
+}
+
+// newAPIClient creates a client of the admin API served at server, e.g. "https://admin.example.com".
+func newAPIClient(server string, token string) *apiClient {
+	return &apiClient{&http.Client{}, strings.TrimSuffix(server, "/"), token}
+}
+
+// CreateUser creates an account with POST /admin/users and returns its id.
+func (c *apiClient) CreateUser(ctx context.Context, spec usecases.UserSpec) (string, error) {
+	request := map[string]string{"username": spec.Username, "email": spec.Email, "role": string(spec.Role), "temporaryPassword": spec.TemporaryPassword}
+	var response struct {
+		ID string `json:"id"`
+	}
+	if err := c.call(ctx, http.MethodPost, "/users", request, &response); err != nil {
+		return "", err
+	}
+	return response.ID, nil
+}
+
+// ProvisionUsers creates many accounts with POST /admin/users/import.
+func (c *apiClient) ProvisionUsers(ctx context.Context, specs []usecases.UserSpec) (usecases.ProvisioningReport, error) {
+	type userSpec struct {
+		Username          string `json:"username"`
+		Email             string `json:"email,omitempty"`
+		Role              string `json:"role,omitempty"`
+		TemporaryPassword string `json:"temporaryPassword,omitempty"`
+		Invite            bool   `json:"invite,omitempty"`
+	}
+	request := struct {
+		Users []userSpec `json:"users"`
+	}{}
+	for _, spec := range specs {
+		request.Users = append(request.Users, userSpec{spec.Username, spec.Email, string(spec.Role), spec.TemporaryPassword, spec.Invite})
+	}
+	var response struct {
+		Created int `json:"created"`
+		Failed  int `json:"failed"`
+		Results []struct {
+			Index    int    `json:"index"`
+			Username string `json:"username"`
+			ID       string `json:"id"`
+			Invited  bool   `json:"invited"`
+			Error    *struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		} `json:"results"`
+	}
+	if err := c.call(ctx, http.MethodPost, "/users/import", request, &response); err != nil {
+		return usecases.ProvisioningReport{}, err
+	}
+
+	report := usecases.ProvisioningReport{Created: response.Created, Failed: response.Failed}
+	for _, result := range response.Results {
+		entry := usecases.ProvisioningResult{Index: result.Index, Username: result.Username, UserID: result.ID, Invited: result.Invited}
+		if result.Error != nil {
+			entry.Err = errors.New(result.Error.Message)
+		}
+		report.Results = append(report.Results, entry)
+	}
+	return report, nil
+}
+
+// ResetPassword sets a temporary password with POST /admin/users/{id}/password.
+func (c *apiClient) ResetPassword(ctx context.Context, id string, temporaryPassword string) error {
+	return c.call(ctx, http.MethodPost, "/users/"+url.PathEscape(id)+"/password", map[string]string{"temporaryPassword": temporaryPassword}, nil)
+}
+
+// UnlockUser lifts the lock of an account with POST /admin/users/{id}/unlock.
+func (c *apiClient) UnlockUser(ctx context.Context, id string) error {
+	return c.call(ctx, http.MethodPost, "/users/"+url.PathEscape(id)+"/unlock", nil, nil)
+}
+
+// Close releases the idle connections to the service.
+func (c *apiClient) Close(context.Context) error {
+	c.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// call sends a JSON request to an admin route and decodes the JSON response into response, if given.
+// Problem responses are returned as *apiError.
+func (c *apiClient) call(ctx context.Context, method string, path string, request any, response any) error {
+	var body io.Reader
+	if request != nil {
+		encoded, err := json.Marshal(request)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = bytes.NewReader(encoded)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.server+adminPath+path, body)
+	if err != nil {
+		return fmt.Errorf("invalid request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call the admin API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := &apiError{problem.Details{Status: resp.StatusCode, Title: http.StatusText(resp.StatusCode)}}
+		// responses of proxies in front of the service are no problem documents, keep the status then
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&apiErr.Details)
+		return apiErr
+	}
+	if response == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return fmt.Errorf("failed to decode response of %s %s: %w", method, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/messaging"
+	"user-auth-hexagonal-architecture/adapters/metrics"
+	"user-auth-hexagonal-architecture/adapters/password"
+	auditPersistence "user-auth-hexagonal-architecture/adapters/persistence/audit"
+	consentPersistence "user-auth-hexagonal-architecture/adapters/persistence/consent"
+	exportPersistence "user-auth-hexagonal-architecture/adapters/persistence/export"
+	groupPersistence "user-auth-hexagonal-architecture/adapters/persistence/group"
+	idempotencyPersistence "user-auth-hexagonal-architecture/adapters/persistence/idempotency"
+	rolePersistence "user-auth-hexagonal-architecture/adapters/persistence/role"
+	sessionPersistence "user-auth-hexagonal-architecture/adapters/persistence/session"
+	tenantPersistence "user-auth-hexagonal-architecture/adapters/persistence/tenant"
+	persistence "user-auth-hexagonal-architecture/adapters/persistence/user"
+	webhookPersistence "user-auth-hexagonal-architecture/adapters/persistence/webhook"
+	"user-auth-hexagonal-architecture/adapters/system"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+	"user-auth-hexagonal-architecture/internal/service"
+	"user-auth-hexagonal-architecture/internal/tenant"
+)
+
+// database runs the use cases in process against the MongoDB database, like the service does.
+//
+// The events of the use cases update the read models and the security timelines, but are not
+// delivered to webhooks or the SIEM, which only the service sends to.
+type database struct {
+	client       *mongo.Client
+	tenantID     string
+	users        *persistence.UserPersistenceMongoAdapter
+	provisioning *service.UserProvisioningService
+	passwords    *service.PasswordChangeService
+	locks        *service.AccountLockService
+}
+
+// openDatabase connects to MongoDB and wires the use cases. Creating the stores creates their
+// indexes, as at the startup of the service.
+func openDatabase(ctx context.Context, opts *settings) (*database, error) {
+	hasher, err := newPasswordHasher(opts.hashAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(opts.mongoURI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		_ = client.Disconnect(context.Background())
+		return nil, fmt.Errorf("failed to reach MongoDB: %w", err)
+	}
+
+	db, err := wireDatabase(ctx, client, opts, hasher)
+	if err != nil {
+		_ = client.Disconnect(context.Background())
+		return nil, err
+	}
+	return db, nil
+}
+
+// wireDatabase creates the stores and the use cases on a connected client.
+func wireDatabase(ctx context.Context, client *mongo.Client, opts *settings, hasher *password.PasswordHasher) (*database, error) {
+	name := opts.mongoDatabase
+	userPersistence, err := persistence.NewUserPersistenceMongoAdapter(client, name)
+	if err != nil {
+		return nil, err
+	}
+	credentialEventStore, err := auditPersistence.NewCredentialEventMongoAdapter(client, name)
+	if err != nil {
+		return nil, err
+	}
+	userOverviewPersistence, err := persistence.NewUserOverviewMongoAdapter(client, name)
+	if err != nil {
+		return nil, err
+	}
+	sessionStore, err := sessionPersistence.NewSessionMongoAdapter(client, name)
+	if err != nil {
+		return nil, err
+	}
+	// the remaining stores are only created for their indexes
+	if _, err := auditPersistence.NewLoginAuditMongoAdapter(client, name, opts.loginHistoryRetention); err != nil {
+		return nil, err
+	}
+	if _, err := idempotencyPersistence.NewIdempotencyMongoAdapter(client, name); err != nil {
+		return nil, err
+	}
+	if _, err := webhookPersistence.NewWebhookMongoAdapter(client, name); err != nil {
+		return nil, err
+	}
+	if _, err := groupPersistence.NewGroupMongoAdapter(client, name); err != nil {
+		return nil, err
+	}
+	if _, err := consentPersistence.NewConsentMongoAdapter(client, name); err != nil {
+		return nil, err
+	}
+	if _, err := exportPersistence.NewDataExportMongoAdapter(client, name); err != nil {
+		return nil, err
+	}
+
+	clock := system.NewSystemClock()
+	roleService := service.NewRoleService(rolePersistence.NewRoleMongoAdapter(client, name), clock)
+	if err := roleService.Refresh(ctx); err != nil {
+		return nil, fmt.Errorf("failed to load roles: %w", err)
+	}
+	tenantService := service.NewTenantService(tenantPersistence.NewTenantMongoAdapter(client, name), clock)
+	if err := tenantService.Refresh(ctx); err != nil {
+		return nil, fmt.Errorf("failed to load tenants: %w", err)
+	}
+
+	eventDispatcher := messaging.NewInProcessDispatcher()
+	eventDispatcher.Subscribe(service.NewUserOverviewProjection(userOverviewPersistence))
+	eventDispatcher.Subscribe(service.NewCredentialAuditProjection(credentialEventStore))
+	prometheusMetrics := metrics.NewPrometheusMetrics()
+
+	return &database{
+		client:       client,
+		tenantID:     opts.tenant,
+		users:        userPersistence,
+		provisioning: service.NewUserProvisioningService(userPersistence, userPersistence, credentialEventStore, eventDispatcher, prometheusMetrics, roleService, tenantService, hasher, clock, domain.DefaultUsernamePolicy, domain.DefaultCanonicalizer),
+		// password change tokens are never issued here, so no signing key is needed
+		passwords: service.NewPasswordChangeService(userPersistence, sessionStore, eventDispatcher, prometheusMetrics, tenantService, hasher, clock, nil),
+		locks:     service.NewAccountLockService(userPersistence, eventDispatcher, clock),
+	}, nil
+}
+
+// CreateUser creates an account in the tenant of the flags.
+func (db *database) CreateUser(ctx context.Context, spec usecases.UserSpec) (string, error) {
+	return db.provisioning.CreateUser(db.withTenant(ctx), spec)
+}
+
+// ProvisionUsers creates many accounts in the tenant of the flags.
+func (db *database) ProvisionUsers(ctx context.Context, specs []usecases.UserSpec) (usecases.ProvisioningReport, error) {
+	return db.provisioning.ProvisionUsers(db.withTenant(ctx), specs)
+}
+
+// ResetPassword sets a temporary password, checked against the policy of the tenant of the flags.
+func (db *database) ResetPassword(ctx context.Context, id string, temporaryPassword string) error {
+	return db.passwords.ResetPassword(db.withTenant(ctx), id, temporaryPassword)
+}
+
+// UnlockUser lifts the lock of an account.
+func (db *database) UnlockUser(ctx context.Context, id string) error {
+	return db.locks.UnlockUser(db.withTenant(ctx), id)
+}
+
+// Close disconnects from MongoDB.
+func (db *database) Close(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	return db.client.Disconnect(ctx)
+}
+
+// withTenant returns a copy of ctx naming the tenant of the flags, if one is given.
+func (db *database) withTenant(ctx context.Context) context.Context {
+	if db.tenantID == "" {
+		return ctx
+	}
+	return tenant.WithID(ctx, db.tenantID)
+}
+
+// newPasswordHasher creates new password hashes with the named algorithm and the default parameters
+// of the service, and verifies the hashes of all algorithms.
+func newPasswordHasher(name string) (*password.PasswordHasher, error) {
+	bcryptAlgorithm, err := password.NewBcrypt(10)
+	if err != nil {
+		return nil, err
+	}
+	argon2idAlgorithm, err := password.NewArgon2id(3, 64*1024, 2)
+	if err != nil {
+		return nil, err
+	}
+	scryptAlgorithm, err := password.NewScrypt(15, 8, 1)
+	if err != nil {
+		return nil, err
+	}
+	algorithms := []password.Algorithm{bcryptAlgorithm, argon2idAlgorithm, scryptAlgorithm}
+	for i, algorithm := range algorithms {
+		if algorithm.Name() == name {
+			others := append(append([]password.Algorithm{}, algorithms[:i]...), algorithms[i+1:]...)
+			return password.NewPasswordHasher(algorithm, others...), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown password hash algorithm %q", name)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+// minKeyBytes is the minimum size of a generated signing key, the size of the HS256 hash.
+const minKeyBytes = 32
+
+// newKeysCommand creates the commands managing token signing keys.
+func newKeysCommand() *cobra.Command {
+	keys := &cobra.Command{
+		Use:   "keys",
+		Short: "Generate token signing keys",
+	}
+	keys.AddCommand(newGenerateKeyCommand())
+	return keys
+}
+
+// newGenerateKeyCommand creates the command generating a random signing key.
+func newGenerateKeyCommand() *cobra.Command {
+	var size int
+	var output string
+	generate := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate a random token signing key",
+		Long: `Generate a random token signing key and print it, or write it to a file readable by the owner only.
+
+Keys never travel over the admin API. To rotate the key of a running service, store the new key
+where -jwt-key or -jwt-key-secret reads it from, i.e. the configuration file or the secret store,
+and send SIGHUP to the service (or wait for -config-watch-interval). Tokens signed with the previous
+key stay valid for -signing-key-grace.`,
+		Example: `  authctl keys generate
+  vault kv patch secret/auth jwt-key="$(authctl keys generate)"`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if size < minKeyBytes {
+				return fmt.Errorf("keys must have at least %d bytes", minKeyBytes)
+			}
+			random := make([]byte, size)
+			if _, err := rand.Read(random); err != nil {
+				return fmt.Errorf("failed to generate key: %w", err)
+			}
+			key := base64.RawURLEncoding.EncodeToString(random)
+			if output == "" {
+				fmt.Fprintln(cmd.OutOrStdout(), key)
+				return nil
+			}
+			file, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+			if err != nil {
+				if errors.Is(err, os.ErrExist) {
+					return fmt.Errorf("%s exists already, keys are not overwritten", output)
+				}
+				return err
+			}
+			if _, err := fmt.Fprintln(file, key); err != nil {
+				_ = file.Close()
+				return err
+			}
+			return file.Close()
+		},
+	}
+	generate.Flags().IntVar(&size, "bytes", 48, "number of random bytes of the key")
+	generate.Flags().StringVar(&output, "output", "", "file to write the key to instead of printing it")
+	return generate
+}
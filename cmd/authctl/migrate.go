@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"user-auth-hexagonal-architecture/adapters/health"
+)
+
+// newMigrateCommand creates the command creating the indexes of the database.
+func newMigrateCommand(opts *settings) *cobra.Command {
+	return &cobra.Command{
+		Use:   "migrate",
+		Short: "Create the indexes of the database and verify them",
+		Long: `Create the collections and indexes the service relies on and verify that they are in place.
+
+The service creates them at startup as well; running the migration before a deployment keeps the
+index builds on large collections out of the startup. The command always works on the database,
+--offline is implied.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := context.WithTimeout(cmd.Context(), opts.timeout)
+			defer cancel()
+
+			db, err := openDatabase(ctx, opts)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = db.Close(context.Background()) }()
+
+			stats, err := db.users.Stats(ctx)
+			if err != nil {
+				return err
+			}
+			for _, index := range stats.Indexes {
+				state := "present"
+				if !index.Present {
+					state = "missing"
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%-24s %-32s %s\n", index.Collection, index.Name, state)
+			}
+			return health.NewIndexCheck("user-indexes", db.users).Check(ctx)
+		},
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// demoUsers are the accounts created by the seed command.
+var demoUsers = []usecases.UserSpec{
+	{Username: "admin", Email: "admin@example.com", Role: domain.RoleAdmin},
+	{Username: "alice", Email: "alice@example.com", Role: domain.RoleUser},
+	{Username: "bob", Email: "bob@example.com", Role: domain.RoleUser},
+}
+
+// newSeedCommand creates the command creating demo accounts.
+func newSeedCommand(opts *settings) *cobra.Command {
+	var temporaryPassword string
+	seed := &cobra.Command{
+		Use:   "seed",
+		Short: "Create demo accounts for local development",
+		Long: `Create the demo accounts admin (an administrator), alice and bob, all with the same temporary
+password. Accounts that exist already are reported and left unchanged, so seeding twice is harmless.`,
+		Args: cobra.NoArgs,
+		RunE: withAdmin(opts, func(cmd *cobra.Command, args []string, a admin) error {
+			specs := make([]usecases.UserSpec, len(demoUsers))
+			for i, spec := range demoUsers {
+				spec.TemporaryPassword = temporaryPassword
+				specs[i] = spec
+			}
+			report, err := a.ProvisionUsers(cmd.Context(), specs)
+			if err != nil {
+				return err
+			}
+			for _, result := range report.Results {
+				if result.Err != nil {
+					fmt.Fprintf(cmd.OutOrStdout(), "%-8s skipped: %v\n", result.Username, result.Err)
+					continue
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%-8s created with id %s\n", result.Username, result.UserID)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Created %d accounts, temporary password: %s\n", report.Created, temporaryPassword)
+			return nil
+		}),
+	}
+	seed.Flags().StringVar(&temporaryPassword, "password", "change-me-please", "temporary password of the demo accounts")
+	return seed
+}
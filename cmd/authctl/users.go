@@ -0,0 +1,116 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"github.com/spf13/cobra"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// newUsersCommand creates the commands managing accounts.
+func newUsersCommand(opts *settings) *cobra.Command {
+	users := &cobra.Command{
+		Use:   "users",
+		Short: "Create accounts, reset passwords and unlock accounts",
+	}
+	users.AddCommand(newCreateUserCommand(opts), newResetPasswordCommand(opts), newUnlockUserCommand(opts))
+	return users
+}
+
+// newCreateUserCommand creates the command creating an account, an administrator by default.
+func newCreateUserCommand(opts *settings) *cobra.Command {
+	var spec usecases.UserSpec
+	var role string
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "Create an account with a temporary password, an administrator by default",
+		Long: `Create an account with a temporary password, which has to be changed at the first login.
+Without --password a random password is generated and printed.
+
+Use --offline to create the first administrator, before anybody can call the admin API.`,
+		Example: "  authctl users create --offline --username root --email root@example.com",
+		Args:    cobra.NoArgs,
+		RunE: withAdmin(opts, func(cmd *cobra.Command, args []string, a admin) error {
+			spec.Role = domain.Role(role)
+			generated, err := ensurePassword(&spec.TemporaryPassword)
+			if err != nil {
+				return err
+			}
+			id, err := a.CreateUser(cmd.Context(), spec)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Created %s %s with id %s\n", spec.Role, spec.Username, id)
+			if generated {
+				fmt.Fprintf(cmd.OutOrStdout(), "Temporary password: %s\n", spec.TemporaryPassword)
+			}
+			return nil
+		}),
+	}
+	create.Flags().StringVar(&spec.Username, "username", "", "username of the account")
+	create.Flags().StringVar(&spec.Email, "email", "", "email address of the account")
+	create.Flags().StringVar(&role, "role", string(domain.RoleAdmin), "role of the account")
+	create.Flags().StringVar(&spec.TemporaryPassword, "password", "", "temporary password, generated if empty")
+	_ = create.MarkFlagRequired("username")
+	return create
+}
+
+// newResetPasswordCommand creates the command resetting the password of an account.
+func newResetPasswordCommand(opts *settings) *cobra.Command {
+	var temporaryPassword string
+	reset := &cobra.Command{
+		Use:   "reset-password USER_ID",
+		Short: "Reset the password of an account to a temporary one and revoke its sessions",
+		Long: `Reset the password of an account to a temporary one, which has to be changed at the next
+login, and revoke the sessions of the account. Without --password a random password is generated
+and printed.`,
+		Args: cobra.ExactArgs(1),
+		RunE: withAdmin(opts, func(cmd *cobra.Command, args []string, a admin) error {
+			generated, err := ensurePassword(&temporaryPassword)
+			if err != nil {
+				return err
+			}
+			if err := a.ResetPassword(cmd.Context(), args[0], temporaryPassword); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Reset the password of %s\n", args[0])
+			if generated {
+				fmt.Fprintf(cmd.OutOrStdout(), "Temporary password: %s\n", temporaryPassword)
+			}
+			return nil
+		}),
+	}
+	reset.Flags().StringVar(&temporaryPassword, "password", "", "temporary password, generated if empty")
+	return reset
+}
+
+// newUnlockUserCommand creates the command lifting the lock of an account.
+func newUnlockUserCommand(opts *settings) *cobra.Command {
+	return &cobra.Command{
+		Use:   "unlock USER_ID",
+		Short: "Lift the lock of an account",
+		Args:  cobra.ExactArgs(1),
+		RunE: withAdmin(opts, func(cmd *cobra.Command, args []string, a admin) error {
+			if err := a.UnlockUser(cmd.Context(), args[0]); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Unlocked %s\n", args[0])
+			return nil
+		}),
+	}
+}
+
+// ensurePassword generates a random password if none is given and reports whether it did.
+func ensurePassword(password *string) (bool, error) {
+	if *password != "" {
+		return false, nil
+	}
+	random := make([]byte, 18)
+	if _, err := rand.Read(random); err != nil {
+		return false, fmt.Errorf("failed to generate password: %w", err)
+	}
+	*password = base64.RawURLEncoding.EncodeToString(random)
+	return true, nil
+}
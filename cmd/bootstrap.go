@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	healthPorts "user-auth-hexagonal-architecture/internal/ports/health"
+	"user-auth-hexagonal-architecture/internal/service"
+)
+
+// verifyDependencies runs dependency checks concurrently before the listeners are bound and
+// returns every failure at once, so a misconfigured deployment is fixed in one go instead of
+// failing step by step or on the first request. Optional dependencies have to pass as well: they
+// are configured, so failing at startup points to a wrong address rather than a passing outage.
+func verifyDependencies(timeout time.Duration, checks ...healthPorts.DependencyCheckPort) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	report := service.NewHealthService(checks).CheckReadiness(ctx)
+	var failures []error
+	for _, status := range report.Dependencies {
+		if !status.Ready {
+			failures = append(failures, fmt.Errorf("%s: %s", status.Name, status.Error))
+		} else {
+			logger.Info("Verified dependency", "dependency", status.Name)
+		}
+	}
+	return errors.Join(failures...)
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"net"
+	"net/mail"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/broker"
+	"user-auth-hexagonal-architecture/adapters/commands"
+	"user-auth-hexagonal-architecture/adapters/errorreport"
+	"user-auth-hexagonal-architecture/adapters/notification"
+	"user-auth-hexagonal-architecture/adapters/siem"
+	"user-auth-hexagonal-architecture/internal/clientip"
+	"user-auth-hexagonal-architecture/internal/config"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/logging"
+)
+
+// envPrefix is the prefix of the environment variables overriding settings, e.g. AUTH_LISTEN_ADDR.
+const envPrefix = "AUTH_"
+
+// minSigningKeyBytes is the minimum size of the token signing key, the size of the HS256 hash.
+const minSigningKeyBytes = 32
+
+// smsSenderPattern matches the senders of SMS: phone numbers in E.164 format or alphanumeric sender ids.
+var smsSenderPattern = regexp.MustCompile(`^(\+[1-9][0-9]{6,14}|[A-Za-z0-9 ]{1,11})$`)
+
+// newConfigLoader creates the loader filling the flags of the command line from the environment and
+// the configuration file, with the secrets to redact and the checks run at startup.
+func newConfigLoader() *config.Loader {
+	loader := config.NewLoader(flag.CommandLine, envPrefix)
+	loader.Secret("mongo-uri", "jwt-key", "vault-token", "aws-secret-access-key", "aws-session-token", "gcp-access-token", "diagnostics-token", "sentry-dsn", "siem-splunk-token", "kafka-rest-password", "amqp-url", "nats-url", "smtp-password", "sendgrid-api-key", "twilio-auth-token", "sms-gateway-url", "sms-gateway-token", "feature-flags-url", "scim-token")
+	loader.Validate("mongo-uri", func(value string) error {
+		if !strings.HasPrefix(value, "mongodb://") && !strings.HasPrefix(value, "mongodb+srv://") {
+			return errors.New("must be a mongodb:// or mongodb+srv:// connection string")
+		}
+		return nil
+	})
+	loader.Validate("mongo-database", required)
+	loader.Validate("jwt-key", optional(signingKeySize))
+	loader.Validate("listen-addr", hostPort)
+	loader.Validate("admin-listen-addr", optional(hostPort))
+	loader.Validate("operations-listen-addr", optional(hostPort))
+	loader.Validate("grpc-addr", optional(hostPort))
+	loader.Validate("http-redirect-addr", optional(hostPort))
+	loader.Validate("redis-addr", optional(hostPort))
+	loader.Validate("diagnostics-addr", optional(hostPort))
+	loader.Validate("sentry-dsn", optional(func(value string) error {
+		_, _, err := errorreport.ParseDSN(value)
+		return err
+	}))
+	loader.Validate("siem-syslog-addr", optional(func(value string) error {
+		_, _, _, err := siem.ParseSyslogAddress(value)
+		return err
+	}))
+	loader.Validate("siem-splunk-url", optional(func(value string) error {
+		_, err := siem.HECEndpoint(value)
+		return err
+	}))
+	loader.Validate("event-publisher", optional(func(value string) error {
+		if value != "kafka" && value != "nats" && value != "amqp" && value != "sns" {
+			return errors.New("must be kafka, nats, amqp, sns or empty")
+		}
+		return nil
+	}))
+	loader.Validate("sns-topic-arn", optional(func(value string) error {
+		_, err := broker.SNSTopicRegion(value)
+		return err
+	}))
+	loader.Validate("sqs-command-queue-url", optional(func(value string) error {
+		_, err := commands.SQSQueueRegion(value)
+		return err
+	}))
+	loader.Validate("sqs-wait-time", func(value string) error {
+		if wait, err := time.ParseDuration(value); err != nil || wait < 0 || wait > 20*time.Second {
+			return errors.New("must be a duration between 0s and 20s")
+		}
+		return nil
+	})
+	loader.Validate("amqp-url", func(value string) error {
+		_, err := amqp.ParseURI(value)
+		return err
+	})
+	loader.Validate("kafka-rest-url", optional(func(value string) error {
+		_, err := broker.KafkaRESTProxyURL(value)
+		return err
+	}))
+	loader.Validate("kafka-encoding", broker.ValidateEncoding)
+	loader.Validate("smtp-host", optional(func(value string) error {
+		if strings.ContainsAny(value, ":/ ") {
+			return errors.New("must be a host name without port, set the port with -smtp-port")
+		}
+		return nil
+	}))
+	loader.Validate("smtp-tls", notification.ValidateSMTPTLS)
+	loader.Validate("email-providers", func(value string) error {
+		for _, provider := range splitList(value) {
+			if provider != "smtp" && provider != "sendgrid" && provider != "ses" {
+				return fmt.Errorf("unknown provider %q, must be smtp, sendgrid or ses", provider)
+			}
+		}
+		return nil
+	})
+	loader.Validate("email-from", optional(func(value string) error {
+		_, err := mail.ParseAddress(value)
+		return err
+	}))
+	loader.Validate("ses-feedback-topic-arn", optional(func(value string) error {
+		_, err := broker.SNSTopicRegion(value)
+		return err
+	}))
+	loader.Validate("sms-providers", func(value string) error {
+		for _, provider := range splitList(value) {
+			if provider != "twilio" && provider != "http" {
+				return fmt.Errorf("unknown provider %q, must be twilio or http", provider)
+			}
+		}
+		return nil
+	})
+	loader.Validate("sms-from", optional(func(value string) error {
+		if !smsSenderPattern.MatchString(value) {
+			return errors.New("must be a phone number in E.164 format or an alphanumeric sender id of up to 11 characters")
+		}
+		return nil
+	}))
+	loader.Validate("sms-countries", func(value string) error {
+		_, err := parseSMSGuard(value, "", "")
+		return err
+	})
+	loader.Validate("sms-country-limits", func(value string) error {
+		_, err := parseSMSGuard("", value, "")
+		return err
+	})
+	loader.Validate("sms-prices", func(value string) error {
+		_, err := parseSMSGuard("", "", value)
+		return err
+	})
+	loader.Validate("sms-budget-period", func(value string) error {
+		if period, err := time.ParseDuration(value); err != nil || period <= 0 {
+			return errors.New("must be a positive duration")
+		}
+		return nil
+	})
+	// a retention of 0 would purge soft-deleted users right away, before they can be restored
+	loader.Validate("purge-deleted-after", func(value string) error {
+		if retention, err := time.ParseDuration(value); err != nil || retention <= 0 {
+			return errors.New("must be a positive duration")
+		}
+		return nil
+	})
+	for _, name := range []string{"siem-syslog-categories", "siem-splunk-categories", "event-categories"} {
+		loader.Validate(name, func(value string) error {
+			_, err := events.ParseCategories(value)
+			return err
+		})
+	}
+	loader.Validate("trusted-proxies", func(value string) error {
+		_, err := clientip.ParseTrustedProxies(value)
+		return err
+	})
+	loader.Validate("client-ip-header", func(value string) error {
+		_, err := clientip.ParseHeader(value)
+		return err
+	})
+	loader.Validate("log-format", func(value string) error {
+		_, err := logging.ParseFormat(value)
+		return err
+	})
+	loader.Validate("feature-flags", func(value string) error {
+		_, err := domain.ParseFeatureFlags(value)
+		return err
+	})
+	loader.Validate("feature-flags-url", optional(func(value string) error {
+		if !strings.HasPrefix(value, "https://") && !strings.HasPrefix(value, "http://") {
+			return errors.New("must be an http:// or https:// URL")
+		}
+		return nil
+	}))
+	// with credentials a wildcard would let any website read the responses to requests made with
+	// the cookies of the user, including the CSRF token
+	loader.Validate("cors-allowed-origins", func(value string) error {
+		credentials := flag.Lookup("cors-allow-credentials").Value.String() == "true"
+		if credentials && slices.Contains(splitList(value), "*") {
+			return errors.New(`must not contain "*" while -cors-allow-credentials is set, list the allowed origins instead`)
+		}
+		return nil
+	})
+	loader.Validate("rate-limits", func(value string) error {
+		_, err := rateLimitOverrides(value)
+		return err
+	})
+	return loader
+}
+
+// required rejects empty values.
+func required(value string) error {
+	if value == "" {
+		return errors.New("must not be empty")
+	}
+	return nil
+}
+
+// signingKeySize rejects token signing keys shorter than the HS256 hash, which could be guessed
+// offline from any issued token.
+func signingKeySize(value string) error {
+	if len(value) < minSigningKeyBytes {
+		return fmt.Errorf("must have at least %d bytes, generate one with authctl keys generate", minSigningKeyBytes)
+	}
+	return nil
+}
+
+// hostPort accepts listen and dial addresses such as ":8080" or "localhost:6379".
+func hostPort(value string) error {
+	if _, _, err := net.SplitHostPort(value); err != nil {
+		return errors.New("must be an address like :8080 or host:port")
+	}
+	return nil
+}
+
+// optional applies a check to non-empty values only.
+func optional(check config.Check) config.Check {
+	return func(value string) error {
+		if value == "" {
+			return nil
+		}
+		return check(value)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/web/diagnostics"
+)
+
+// serveDiagnostics starts the listener of the profiling and expvar endpoints in the background.
+// Diagnostics reachable from other hosts must be protected by a token.
+//
+// Parameters:
+//   - addr: The address to listen on, e.g. "127.0.0.1:6060"
+//   - config: The access configuration
+//
+// Returns:
+//   - error: An error if the diagnostics would be reachable from other hosts without a token
+func serveDiagnostics(addr string, config diagnostics.Config) error {
+	if !config.LocalhostOnly && config.Token == "" {
+		return errors.New("diagnostics reachable from other hosts require -diagnostics-token")
+	}
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           diagnostics.Handler(config),
+		ReadHeaderTimeout: 5 * time.Second,
+		IdleTimeout:       time.Minute,
+		// no write timeout, CPU profiles and traces take as long as requested
+	}
+	go func() {
+		logger.Info("Starting diagnostics server", "addr", addr, "localhost_only", config.LocalhostOnly)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("Error serving diagnostics", "error", err)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/health"
+)
+
+// lifecycle shuts the servers down in the order a rolling deployment behind kube-proxy needs:
+// the instance turns unready first and keeps serving while the endpoints are removed from the
+// load balancers, then stops accepting connections and waits for the requests in flight.
+type lifecycle struct {
+	drain   *health.DrainCheck
+	servers []*http.Server
+	stops   []func(ctx context.Context)
+}
+
+// newLifecycle creates a lifecycle marking the instance as draining with the check.
+func newLifecycle(drain *health.DrainCheck) *lifecycle {
+	return &lifecycle{drain: drain}
+}
+
+// track registers an HTTP server to drain and shut down.
+func (l *lifecycle) track(server *http.Server) {
+	l.servers = append(l.servers, server)
+}
+
+// onStop registers a function stopping another server, e.g. gRPC. It must return once the
+// context is done at the latest.
+func (l *lifecycle) onStop(stop func(ctx context.Context)) {
+	l.stops = append(l.stops, stop)
+}
+
+// shutdown drains the instance and stops every server.
+//
+// The readiness probe fails right away. During the delay the servers keep accepting connections,
+// as kube-proxy and ingress controllers keep routing to the instance until they observe the
+// removed endpoint, but keep-alives are disabled, so clients reconnect to other instances after
+// their next response instead of holding on to a connection that is about to close. Then the
+// servers stop accepting connections and wait for the requests in flight, within the timeout.
+//
+// Parameters:
+//   - delay: How long to keep serving after turning unready, e.g. a few seconds more than the
+//     readiness probe period
+//   - timeout: How long to wait for the requests in flight before closing their connections
+//
+// Returns:
+//   - error: An error if the requests in flight did not finish within the timeout
+func (l *lifecycle) shutdown(delay time.Duration, timeout time.Duration) error {
+	l.drain.Drain()
+	for _, server := range l.servers {
+		server.SetKeepAlivesEnabled(false)
+	}
+	if delay > 0 {
+		logger.Info("Draining before shutdown", "delay", delay)
+		time.Sleep(delay)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(l.servers))
+	for i, server := range l.servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// long-lived responses such as event streams would keep the server open, close them
+			if err := server.Shutdown(ctx); err != nil {
+				errs[i] = err
+				_ = server.Close()
+			}
+		}()
+	}
+	for _, stop := range l.stops {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stop(ctx)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"user-auth-hexagonal-architecture/adapters/web/problem"
+)
+
+// routePaths are path prefixes of the routes served on a listener. A prefix matches itself and
+// every path below it, "/admin" matches "/admin" and "/admin/users" but not "/administrator".
+type routePaths []string
+
+// adminPaths are the console and the admin API, in the current version and the legacy routes.
+var adminPaths = routePaths{"/admin", "/api/v1/admin"}
+
+// consoleLoginPaths are the routes the console logs operators in with. They are served on the
+// admin listener as well, so the console works without reaching the public listener.
+var consoleLoginPaths = routePaths{"/api/v1/user/login"}
+
+// operationsPaths are the health checks, the version and the metrics. The discovery documents stay
+// on the public listener, clients verifying tokens fetch them.
+var operationsPaths = routePaths{"/health", "/healthz", "/readyz", "/version", "/metrics"}
+
+// match reports whether the path is one of the prefixes or below one of them.
+func (rp routePaths) match(path string) bool {
+	for _, prefix := range rp {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// listenerOptions configures an HTTP listener besides the public one.
+type listenerOptions struct {
+	// Addr is the address to listen on, the listener is disabled if empty.
+	Addr string
+	// CertFile and KeyFile enable TLS with a static certificate, the listener serves plaintext if both are empty.
+	CertFile string
+	KeyFile  string
+}
+
+// enabled reports whether the listener is configured.
+func (o listenerOptions) enabled() bool {
+	return o.Addr != ""
+}
+
+// onlyPaths answers requests outside of the paths with 404, so a listener serves a part of the routes only.
+//
+// Parameters:
+//   - next: The handler serving all routes
+//   - paths: The paths served by the listener
+//
+// Returns:
+//   - http.Handler: The restricted handler
+func onlyPaths(next http.Handler, paths ...routePaths) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, p := range paths {
+			if p.match(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		problem.Write(w, r, problem.NotFound, "")
+	})
+}
+
+// exceptPaths answers requests within the paths with 404, so routes moved to a listener of their
+// own are no longer reachable on the public one.
+//
+// Parameters:
+//   - next: The handler serving all routes
+//   - paths: The paths served by other listeners
+//
+// Returns:
+//   - http.Handler: The restricted handler
+func exceptPaths(next http.Handler, paths ...routePaths) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, p := range paths {
+			if p.match(r.URL.Path) {
+				problem.Write(w, r, problem.NotFound, "")
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// startListener binds an additional listener right away, so a taken port or a broken certificate
+// stops the startup, and serves it in the background.
+//
+// Parameters:
+//   - name: The name of the listener in the logs, e.g. "admin"
+//   - server: The configured server, its Addr is the address to listen on
+//   - opts: The TLS certificate of the listener
+//
+// Returns:
+//   - error: An error if the address cannot be bound or the certificate cannot be loaded
+func startListener(name string, server *http.Server, opts listenerOptions) error {
+	if (opts.CertFile == "") != (opts.KeyFile == "") {
+		return errors.New("tls certificate and key must be configured together")
+	}
+	useTLS := opts.CertFile != ""
+	if useTLS {
+		certificate, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return err
+		}
+		server.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12, Certificates: []tls.Certificate{certificate}}
+	}
+
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		return err
+	}
+	go func() {
+		logger.Info("Starting listener", "listener", name, "addr", server.Addr, "tls", useTLS)
+		var serveErr error
+		if useTLS {
+			serveErr = server.ServeTLS(listener, "", "")
+		} else {
+			serveErr = server.Serve(listener)
+		}
+		if serveErr != nil && !errors.Is(serveErr, http.ErrServerClosed) {
+			fatal("Listener failed", "listener", name, "error", serveErr)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"user-auth-hexagonal-architecture/internal/config"
+)
+
+// reloadHandler applies a group of settings that can change while the service runs.
+type reloadHandler struct {
+	names []string
+	// always runs the handler on every reload, e.g. to pick up a secret rotated in the store.
+	always bool
+	apply  func() error
+}
+
+// reloader re-applies the configuration when the process receives SIGHUP or the configuration file
+// changes. Settings with a handler take effect immediately, requests in flight finish with the
+// previous values; every other changed setting is logged as requiring a restart.
+type reloader struct {
+	loader   *config.Loader
+	handlers []reloadHandler
+	mu       sync.Mutex
+}
+
+// newReloader creates a reloader for the settings of a loader.
+func newReloader(loader *config.Loader) *reloader {
+	return &reloader{loader: loader}
+}
+
+// onChange registers the function applying a group of settings, run once per reload if any of them changed.
+func (r *reloader) onChange(apply func() error, names ...string) {
+	r.handlers = append(r.handlers, reloadHandler{names: names, apply: apply})
+}
+
+// onReload registers a function run on every reload, with the settings it reads.
+func (r *reloader) onReload(apply func() error, names ...string) {
+	r.handlers = append(r.handlers, reloadHandler{names: names, always: true, apply: apply})
+}
+
+// reload re-reads the configuration and applies the changes. A rejected configuration is logged
+// and leaves every setting unchanged.
+func (r *reloader) reload() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changed, err := r.loader.Reload()
+	if err != nil {
+		logger.Error("Rejected configuration reload, keeping the current settings", "error", err)
+		return
+	}
+	pending := make(map[string]bool, len(changed))
+	for _, name := range changed {
+		pending[name] = true
+	}
+
+	for _, handler := range r.handlers {
+		run := handler.always
+		for _, name := range handler.names {
+			run = run || pending[name]
+			delete(pending, name)
+		}
+		if !run {
+			continue
+		}
+		if err := handler.apply(); err != nil {
+			logger.Error("Error applying settings", "settings", strings.Join(handler.names, ", "), "error", err)
+		}
+	}
+	for _, name := range changed {
+		if pending[name] {
+			logger.Warn("Setting changed, restart to apply it", "setting", name)
+		}
+	}
+	logger.Info("Reloaded configuration", "changed", len(changed))
+}
+
+// watch reloads the configuration on SIGHUP and, if the interval is positive, whenever the
+// modification time of the configuration file changes, until the context is cancelled.
+func (r *reloader) watch(ctx context.Context, interval time.Duration) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	var poll <-chan time.Time
+	file := r.loader.File()
+	if file != "" && interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+	modified := modTime(file)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			logger.Info("Received SIGHUP, reloading configuration")
+			modified = modTime(file)
+			r.reload()
+		case <-poll:
+			if current := modTime(file); !current.Equal(modified) {
+				logger.Info("Configuration file changed, reloading configuration", "file", file)
+				modified = current
+				r.reload()
+			}
+		}
+	}
+}
+
+// modTime returns the modification time of a file, the zero time if it cannot be read.
+func modTime(file string) time.Time {
+	if file == "" {
+		return time.Time{}
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+	"user-auth-hexagonal-architecture/adapters/aws"
+	"user-auth-hexagonal-architecture/adapters/secrets"
+)
+
+// secretsOptions configures the secret store credentials are read from.
+type secretsOptions struct {
+	// Provider selects the store: vault, aws or gcp. Empty disables secret references.
+	Provider string
+	// RefreshInterval is how long secrets the store does not lease are served from memory.
+	RefreshInterval time.Duration
+	VaultAddr       string
+	VaultToken      string
+	AwsRegion       string
+	Aws             aws.Credentials
+	GcpProject      string
+	GcpAccessToken  string
+}
+
+// newSecretsProvider creates the cached secret store selected by the options, or nil if no store
+// is configured. Vault tokens and leases are renewed in the background until ctx is cancelled.
+//
+// Parameters:
+//   - ctx: The context bounding the background renewal
+//   - opts: The secret store options
+//
+// Returns:
+//   - *secrets.Cache: The cached store, nil if opts.Provider is empty
+//   - error: An error if the provider is unknown or misses settings
+func newSecretsProvider(ctx context.Context, opts secretsOptions) (*secrets.Cache, error) {
+	opts.VaultAddr = orEnv(opts.VaultAddr, "VAULT_ADDR")
+	opts.VaultToken = orEnv(opts.VaultToken, "VAULT_TOKEN")
+	opts.AwsRegion, opts.Aws = awsSettings(opts)
+	opts.GcpProject = orEnv(opts.GcpProject, "GOOGLE_CLOUD_PROJECT")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	var store secrets.Store
+	switch opts.Provider {
+	case "":
+		return nil, nil
+	case "vault":
+		if opts.VaultAddr == "" || opts.VaultToken == "" {
+			return nil, fmt.Errorf("vault needs -vault-addr and -vault-token")
+		}
+		vault := secrets.NewVault(client, opts.VaultAddr, opts.VaultToken)
+		go renewVaultToken(ctx, vault, opts.RefreshInterval)
+		store = vault
+	case "aws":
+		if opts.AwsRegion == "" {
+			return nil, fmt.Errorf("aws needs -aws-region")
+		}
+		store = secrets.NewAwsSecretsManager(client, opts.AwsRegion, aws.NewCredentialsProvider(client, opts.AwsRegion, opts.Aws))
+	case "gcp":
+		if opts.GcpProject == "" {
+			return nil, fmt.Errorf("gcp needs -gcp-project")
+		}
+		store = secrets.NewGcpSecretManager(client, opts.GcpProject, opts.GcpAccessToken)
+	default:
+		return nil, fmt.Errorf("unknown secrets provider %q, use vault, aws or gcp", opts.Provider)
+	}
+
+	cache := secrets.NewCache(store, opts.RefreshInterval)
+	go cache.RenewEvery(ctx, time.Minute)
+	return cache, nil
+}
+
+// awsSettings returns the AWS region and access key of the options, completed from the standard
+// environment variables of the AWS tooling. Without an access key the role of the workload is used.
+func awsSettings(opts secretsOptions) (string, aws.Credentials) {
+	credentials := opts.Aws
+	credentials.AccessKeyID = orEnv(credentials.AccessKeyID, "AWS_ACCESS_KEY_ID")
+	credentials.SecretAccessKey = orEnv(credentials.SecretAccessKey, "AWS_SECRET_ACCESS_KEY")
+	credentials.SessionToken = orEnv(credentials.SessionToken, "AWS_SESSION_TOKEN")
+	return orEnv(opts.AwsRegion, "AWS_REGION"), credentials
+}
+
+// orEnv returns the value, or the standard environment variable of the cloud tooling if it is empty.
+func orEnv(value string, env string) string {
+	if value == "" {
+		return os.Getenv(env)
+	}
+	return value
+}
+
+// renewVaultToken renews the Vault token at the given interval until the context is cancelled.
+func renewVaultToken(ctx context.Context, vault *secrets.Vault, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := vault.RenewToken(ctx); err != nil {
+				logger.ErrorContext(ctx, "Error renewing vault token", "error", err)
+			}
+		}
+	}
+}
+
+// resolveSecret returns the value a secret reference points to, or the fallback if the reference is empty.
+//
+// Parameters:
+//   - provider: The secret store, nil if none is configured
+//   - ref: The reference of the secret, e.g. "secret/data/auth#jwt-key"
+//   - fallback: The value configured directly
+//
+// Returns:
+//   - string: The secret
+//   - error: An error if a reference is given without a store, or the store fails
+func resolveSecret(provider *secrets.Cache, ref string, fallback string) (string, error) {
+	if ref == "" {
+		return fallback, nil
+	}
+	if provider == nil {
+		return "", fmt.Errorf("secret reference %q needs -secrets-provider", ref)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	return provider.Secret(ctx, ref)
+}
+
+// refreshSecret is resolveSecret bypassing the cache, so a reload picks up secrets rotated in the store.
+func refreshSecret(provider *secrets.Cache, ref string, fallback string) (string, error) {
+	if ref == "" || provider == nil {
+		return resolveSecret(provider, ref, fallback)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	return provider.Refresh(ctx, ref)
+}
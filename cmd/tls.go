@@ -0,0 +1,105 @@
+package main
+
+import (
+	"crypto/tls"
+	"errors"
+	"golang.org/x/crypto/acme/autocert"
+	"net"
+	"net/http"
+	"time"
+)
+
+// tlsOptions configures how the server terminates TLS.
+type tlsOptions struct {
+	// CertFile and KeyFile point to a static certificate and its private key.
+	CertFile string
+	KeyFile  string
+	// AutocertDomains enables automatic certificates from Let's Encrypt for the listed host names.
+	AutocertDomains []string
+	// AutocertCacheDir is where obtained certificates are stored across restarts.
+	AutocertCacheDir string
+	// AutocertEmail is the contact address registered with the ACME account, may be empty.
+	AutocertEmail string
+	// RedirectAddr is the address of the plaintext listener redirecting to HTTPS. Empty disables it.
+	RedirectAddr string
+}
+
+// enabled reports whether TLS is configured.
+func (o tlsOptions) enabled() bool {
+	return len(o.AutocertDomains) > 0 || o.CertFile != "" || o.KeyFile != ""
+}
+
+// serve starts the server with TLS if configured, and in plaintext otherwise.
+//
+// With TLS enabled, an optional plaintext listener answers ACME HTTP-01 challenges (when
+// certificates are obtained automatically) and redirects every other request to HTTPS, so
+// credentials and tokens are never served over an unencrypted connection.
+//
+// Parameters:
+//   - server: The configured API server
+//   - opts: The TLS options
+//
+// Returns:
+//   - error: The error that made the server stop
+func serve(server *http.Server, opts tlsOptions) error {
+	if !opts.enabled() {
+		logger.Info("Starting server", "addr", server.Addr)
+		return server.ListenAndServe()
+	}
+
+	var redirect http.Handler = http.HandlerFunc(redirectToHTTPS(server.Addr))
+	certFile, keyFile := opts.CertFile, opts.KeyFile
+
+	switch {
+	case len(opts.AutocertDomains) > 0:
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(opts.AutocertDomains...),
+			Cache:      autocert.DirCache(opts.AutocertCacheDir),
+			Email:      opts.AutocertEmail,
+		}
+		server.TLSConfig = manager.TLSConfig()
+		server.TLSConfig.MinVersion = tls.VersionTLS12
+		redirect = manager.HTTPHandler(redirect)
+		certFile, keyFile = "", ""
+	case opts.CertFile == "" || opts.KeyFile == "":
+		return errors.New("tls certificate and key must be configured together")
+	default:
+		server.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	if opts.RedirectAddr != "" {
+		redirectServer := &http.Server{
+			Addr:              opts.RedirectAddr,
+			Handler:           redirect,
+			ReadHeaderTimeout: 5 * time.Second,
+			IdleTimeout:       time.Minute,
+		}
+		go func() {
+			logger.Info("Redirecting plaintext requests to HTTPS", "addr", opts.RedirectAddr)
+			if err := redirectServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("Error serving HTTPS redirect", "error", err)
+			}
+		}()
+	}
+
+	logger.Info("Starting server with TLS", "addr", server.Addr)
+	return server.ListenAndServeTLS(certFile, keyFile)
+}
+
+// redirectToHTTPS returns a handler that permanently redirects to the same URL on the HTTPS listener.
+// 308 is used so clients repeat non-GET requests with the same method.
+func redirectToHTTPS(httpsAddr string) http.HandlerFunc {
+	_, httpsPort, _ := net.SplitHostPort(httpsAddr)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if httpsPort != "" && httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	}
+}
@@ -0,0 +1,45 @@
+// Package buildinfo describes the running build. Version, Commit and BuildDate are meant to be set
+// at link time, e.g. go build -ldflags "-X user-auth-hexagonal-architecture/internal/buildinfo.Version=1.2.0".
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+var (
+	// Version is the release version of the build.
+	Version = "dev"
+	// Commit is the VCS revision the build was made from.
+	Commit = ""
+	// BuildDate is the time the build was made.
+	BuildDate = ""
+)
+
+// Info is the description of the running build.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildDate string `json:"buildDate,omitempty"`
+	GoVersion string `json:"goVersion"`
+}
+
+// Get returns the description of the running build.
+//
+// Values that were not set at link time are taken from the VCS information the Go toolchain
+// embeds into the binary, if available.
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit, BuildDate: BuildDate, GoVersion: runtime.Version()}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range build.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.BuildDate == "":
+				info.BuildDate = setting.Value
+			}
+		}
+	}
+	return info
+}
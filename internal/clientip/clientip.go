@@ -0,0 +1,215 @@
+// Package clientip resolves the address of the client a request originates from behind reverse
+// proxies and carries it through contexts, so rate limits, audit records and risk checks see the
+// client instead of the load balancer in front of the service.
+package clientip
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+)
+
+// Forwarding headers in which proxies report the client.
+const (
+	// HeaderXForwardedFor lists the client and every proxy but the last one, each proxy appending the address it received the request from.
+	HeaderXForwardedFor = "X-Forwarded-For"
+	// HeaderForwarded is the standardized form of X-Forwarded-For (RFC 7239), e.g. "for=192.0.2.60;proto=https".
+	HeaderForwarded = "Forwarded"
+	// HeaderXRealIP holds the client as seen by the last proxy only.
+	HeaderXRealIP = "X-Real-IP"
+)
+
+// contextKey is the context key under which the client address is stored.
+type contextKey struct{}
+
+// Resolver determines the client of a request from the address of the peer and the forwarding
+// header its proxies set.
+//
+// The header is only read if the peer is a trusted proxy, as anybody else can send any header.
+// Its hops are walked from the right, the one closest to the service, and the first hop that is
+// not a trusted proxy is the client: the hops to its left were reported by the client itself and
+// can be spoofed. A malformed or obfuscated hop ends the walk at the last trusted proxy.
+type Resolver struct {
+	trusted []netip.Prefix
+	header  string
+}
+
+// ParseTrustedProxies converts a comma-separated list of proxy networks.
+//
+// Parameters:
+//   - value: CIDR ranges or single addresses, e.g. "10.0.0.0/8, 192.168.1.10, fd00::/8"
+//
+// Returns:
+//   - []netip.Prefix: The networks
+//   - error: An error naming the first malformed entry
+func ParseTrustedProxies(value string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, fmt.Errorf("trusted proxy %q is no CIDR range", entry)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q is no IP address", entry)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// ParseHeader converts the name of a forwarding header, ignoring case.
+//
+// Parameters:
+//   - name: "X-Forwarded-For", "Forwarded" or "X-Real-IP"
+//
+// Returns:
+//   - string: The canonical name of the header
+//   - error: An error if the header is not a forwarding header
+func ParseHeader(name string) (string, error) {
+	for _, header := range []string{HeaderXForwardedFor, HeaderForwarded, HeaderXRealIP} {
+		if strings.EqualFold(name, header) {
+			return header, nil
+		}
+	}
+	return "", fmt.Errorf("client IP header %q must be X-Forwarded-For, Forwarded or X-Real-IP", name)
+}
+
+// NewResolver creates a new Resolver.
+//
+// Parameters:
+//   - trusted: The networks of the proxies whose forwarding header is believed, none to always use the peer
+//   - header: The forwarding header the proxies set, see ParseHeader
+//
+// Returns:
+//   - *Resolver: A pointer to the newly created Resolver
+//   - error: An error if the header is not a forwarding header
+func NewResolver(trusted []netip.Prefix, header string) (*Resolver, error) {
+	header, err := ParseHeader(header)
+	if err != nil {
+		return nil, err
+	}
+	return &Resolver{trusted, header}, nil
+}
+
+// Header returns the forwarding header the resolver reads.
+func (r *Resolver) Header() string {
+	return r.header
+}
+
+// Resolve returns the address of the client.
+//
+// Parameters:
+//   - remoteAddr: The address of the peer, with or without port
+//   - values: The values of the forwarding header, in the order they were received
+//
+// Returns:
+//   - string: The address of the client, or remoteAddr without its port if it is no IP address
+func (r *Resolver) Resolve(remoteAddr string, values []string) string {
+	peer, ok := parseAddr(remoteAddr)
+	if !ok {
+		if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+			return host
+		}
+		return remoteAddr
+	}
+	if !r.trusts(peer) {
+		return peer.String()
+	}
+
+	hops := r.hops(values)
+	client := peer
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, ok := parseAddr(hops[i])
+		if !ok {
+			break
+		}
+		client = hop
+		if !r.trusts(hop) {
+			break
+		}
+	}
+	return client.String()
+}
+
+// trusts reports whether the address belongs to a trusted proxy.
+func (r *Resolver) trusts(addr netip.Addr) bool {
+	for _, prefix := range r.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// hops returns the addresses reported in the header, the one closest to the service last.
+// Repeated headers count as one list, as proxies may append a header instead of extending it.
+func (r *Resolver) hops(values []string) []string {
+	var hops []string
+	switch r.header {
+	case HeaderXRealIP:
+		if len(values) > 0 {
+			hops = append(hops, values[len(values)-1])
+		}
+	case HeaderForwarded:
+		for _, value := range values {
+			for _, element := range strings.Split(value, ",") {
+				hops = append(hops, forwardedFor(element))
+			}
+		}
+	default:
+		for _, value := range values {
+			hops = append(hops, strings.Split(value, ",")...)
+		}
+	}
+	return hops
+}
+
+// forwardedFor returns the "for" parameter of an RFC 7239 element, empty if it has none.
+func forwardedFor(element string) string {
+	for _, pair := range strings.Split(element, ";") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if ok && strings.EqualFold(name, "for") {
+			return strings.Trim(value, `"`)
+		}
+	}
+	return ""
+}
+
+// parseAddr parses an address with or without port, e.g. "192.0.2.60", "192.0.2.60:4711",
+// "2001:db8::1" or "[2001:db8::1]:4711". Obfuscated identifiers such as "unknown" or "_hidden" are
+// rejected. IPv4 addresses mapped into IPv6 are unmapped, zones dropped.
+func parseAddr(value string) (netip.Addr, bool) {
+	value = strings.TrimSpace(value)
+	addr, err := netip.ParseAddr(strings.TrimSuffix(strings.TrimPrefix(value, "["), "]"))
+	if err != nil {
+		addrPort, err := netip.ParseAddrPort(value)
+		if err != nil {
+			return netip.Addr{}, false
+		}
+		addr = addrPort.Addr()
+	}
+	return addr.Unmap().WithZone(""), true
+}
+
+// WithIP returns a copy of ctx carrying the address of the client.
+func WithIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, contextKey{}, ip)
+}
+
+// FromContext returns the address of the client stored in ctx, or an empty string.
+func FromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(contextKey{}).(string)
+	return ip
+}
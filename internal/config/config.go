@@ -0,0 +1,278 @@
+// Package config loads the settings of the service from command line flags, environment variables
+// and an optional configuration file.
+//
+// The settings are the flags of a flag.FlagSet, so every setting has a name, a type, a default and
+// a usage text in one place. A setting is taken from the first source that provides it:
+//
+//  1. the command line, e.g. -listen-addr=:8443
+//  2. an environment variable named after the flag, e.g. AUTH_LISTEN_ADDR=:8443
+//  3. the configuration file named by -config or AUTH_CONFIG, e.g. listen-addr: ":8443"
+//  4. the default of the flag
+//
+// Values from all sources are parsed by the flag itself, so a malformed duration in the file is
+// rejected at startup just like one on the command line.
+//
+// Reload applies the environment and the file again while the service runs, so settings that can
+// change at runtime do not need a restart.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Source names where the value of a setting came from.
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "file"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+)
+
+// redacted replaces the values of secret settings in the effective configuration.
+const redacted = "[REDACTED]"
+
+// Check validates the value of a setting after all sources have been applied.
+type Check func(value string) error
+
+// Loader applies the configuration sources to the flags of a flag set.
+type Loader struct {
+	flags     *flag.FlagSet
+	envPrefix string
+	file      string
+	path      string
+	secrets   map[string]bool
+	checks    map[string][]Check
+	sources   map[string]Source
+}
+
+// NewLoader creates a new Loader and registers the -config flag naming the configuration file.
+//
+// Parameters:
+//   - flags: The flag set defining the settings, usually flag.CommandLine
+//   - envPrefix: The prefix of the environment variables, e.g. "AUTH_"
+//
+// Returns:
+//   - *Loader: A pointer to the newly created Loader
+func NewLoader(flags *flag.FlagSet, envPrefix string) *Loader {
+	l := &Loader{
+		flags:     flags,
+		envPrefix: envPrefix,
+		secrets:   make(map[string]bool),
+		checks:    make(map[string][]Check),
+		sources:   make(map[string]Source),
+	}
+	flags.StringVar(&l.file, "config", "", "path of a YAML (.yaml, .yml) or TOML (.toml) configuration file")
+	return l
+}
+
+// Secret marks settings whose values must not be printed, e.g. keys and connection strings with credentials.
+func (l *Loader) Secret(names ...string) {
+	for _, name := range names {
+		l.secrets[name] = true
+	}
+}
+
+// Validate registers a check of a setting, run by Load once all sources have been applied.
+func (l *Loader) Validate(name string, check Check) {
+	l.checks[name] = append(l.checks[name], check)
+}
+
+// EnvName returns the environment variable of a setting, e.g. "AUTH_LISTEN_ADDR" for "listen-addr".
+func (l *Loader) EnvName(name string) string {
+	return l.envPrefix + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+}
+
+// Load parses the command line and fills the settings it does not set from the environment and
+// the configuration file.
+//
+// Parameters:
+//   - args: The command line arguments without the program name
+//
+// Returns:
+//   - error: An error naming the setting and its source if a value is malformed, the file cannot be
+//     read or names unknown settings, or a check fails
+func (l *Loader) Load(args []string) error {
+	if err := l.flags.Parse(args); err != nil {
+		return err
+	}
+	l.flags.Visit(func(f *flag.Flag) { l.sources[f.Name] = SourceFlag })
+
+	l.path = l.file
+	if l.sources["config"] != SourceFlag {
+		l.path = os.Getenv(l.EnvName("config"))
+	}
+	if err := l.apply(); err != nil {
+		return err
+	}
+	return l.check()
+}
+
+// Reload re-reads the environment and the configuration file and applies them to all settings that
+// were not set on the command line, so changes to the file take effect without a restart. Settings
+// removed from the file fall back to their defaults.
+//
+// Reloading is all or nothing: if a value is malformed or a check fails, every setting keeps its
+// previous value.
+//
+// Returns:
+//   - []string: The names of the settings whose values changed, in alphabetical order
+//   - error: An error naming the setting and its source if the new configuration is rejected
+func (l *Loader) Reload() ([]string, error) {
+	previous := make(map[string]string)
+	l.flags.VisitAll(func(f *flag.Flag) { previous[f.Name] = f.Value.String() })
+	previousSources := make(map[string]Source, len(l.sources))
+	for name, source := range l.sources {
+		previousSources[name] = source
+	}
+
+	err := l.apply()
+	if err == nil {
+		err = l.check()
+	}
+	if err != nil {
+		l.flags.VisitAll(func(f *flag.Flag) {
+			if f.Value.String() != previous[f.Name] {
+				// the previous value was accepted before, so setting it again cannot fail
+				_ = f.Value.Set(previous[f.Name])
+			}
+		})
+		l.sources = previousSources
+		return nil, err
+	}
+
+	var changed []string
+	l.flags.VisitAll(func(f *flag.Flag) {
+		if f.Value.String() != previous[f.Name] {
+			changed = append(changed, f.Name)
+		}
+	})
+	return changed, nil
+}
+
+// File returns the path of the configuration file, empty if none is used.
+func (l *Loader) File() string {
+	return l.path
+}
+
+// apply sets every setting that is not set on the command line from the environment, the
+// configuration file or its default.
+func (l *Loader) apply() error {
+	var fileValues map[string]string
+	if l.path != "" {
+		var err error
+		if fileValues, err = readFile(l.path); err != nil {
+			return err
+		}
+		for name := range fileValues {
+			if l.flags.Lookup(name) == nil {
+				return fmt.Errorf("%s: unknown setting %q", l.path, name)
+			}
+		}
+	}
+
+	var errs []error
+	l.flags.VisitAll(func(f *flag.Flag) {
+		if l.sources[f.Name] == SourceFlag {
+			return
+		}
+		value, source := f.DefValue, SourceDefault
+		if env, ok := os.LookupEnv(l.EnvName(f.Name)); ok {
+			value, source = env, SourceEnv
+		} else if fromFile, ok := fileValues[f.Name]; ok {
+			value, source = fromFile, SourceFile
+		}
+		if value != f.Value.String() {
+			if err := f.Value.Set(value); err != nil {
+				errs = append(errs, fmt.Errorf("invalid value %s for %s from %s: %w", l.quote(f.Name, value), f.Name, l.describe(f.Name, source), err))
+				return
+			}
+		}
+		l.sources[f.Name] = source
+	})
+	return errors.Join(errs...)
+}
+
+// check runs the registered checks against the effective values.
+func (l *Loader) check() error {
+	names := make([]string, 0, len(l.checks))
+	for name := range l.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		f := l.flags.Lookup(name)
+		if f == nil {
+			return fmt.Errorf("check of unknown setting %q", name)
+		}
+		for _, check := range l.checks[name] {
+			if err := check(f.Value.String()); err != nil {
+				errs = append(errs, fmt.Errorf("invalid value %s for %s from %s: %w", l.quote(name, f.Value.String()), name, l.describe(name, l.Source(name)), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// quote quotes a value for error messages, redacting the values of secret settings.
+func (l *Loader) quote(name string, value string) string {
+	if l.secrets[name] && value != "" {
+		return redacted
+	}
+	return strconv.Quote(value)
+}
+
+// describe names the source of a setting in error messages, e.g. "environment variable AUTH_PORT".
+func (l *Loader) describe(name string, source Source) string {
+	switch source {
+	case SourceEnv:
+		return "environment variable " + l.EnvName(name)
+	case SourceFile:
+		return l.path
+	case SourceFlag:
+		return "flag -" + name
+	default:
+		return "default"
+	}
+}
+
+// Source returns where the value of a setting came from, SourceDefault before Load.
+func (l *Loader) Source(name string) Source {
+	if source, ok := l.sources[name]; ok {
+		return source
+	}
+	return SourceDefault
+}
+
+// Dump writes the effective configuration, one "name = value (source)" line per setting in
+// alphabetical order. Values of secret settings are redacted unless they are empty.
+//
+// Parameters:
+//   - w: The writer receiving the configuration
+//
+// Returns:
+//   - error: An error if writing fails
+func (l *Loader) Dump(w io.Writer) error {
+	var err error
+	l.flags.VisitAll(func(f *flag.Flag) {
+		if err != nil {
+			return
+		}
+		value := f.Value.String()
+		if l.secrets[f.Name] && value != "" {
+			value = redacted
+		}
+		_, err = fmt.Fprintf(w, "%s = %q (%s)\n", f.Name, value, l.Source(f.Name))
+	})
+	return err
+}
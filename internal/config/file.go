@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go.yaml.in/yaml/v2"
+)
+
+// readFile reads a configuration file and flattens it into setting names and values. Nested
+// sections are joined with "-", so the key uri in the section mongo sets the setting mongo-uri,
+// and lists are joined with ",", the separator of list settings.
+func readFile(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration file: %w", err)
+	}
+
+	var tree map[string]any
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		var doc yaml.MapSlice
+		if err := yaml.Unmarshal(content, &doc); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		tree = yamlTree(doc)
+	case ".toml":
+		if tree, err = parseTOML(string(content)); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("%s: unsupported configuration format, use .yaml, .yml or .toml", path)
+	}
+
+	values := make(map[string]string)
+	if err := flatten("", tree, values); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return values, nil
+}
+
+// yamlTree converts a decoded YAML mapping into nested maps.
+func yamlTree(doc yaml.MapSlice) map[string]any {
+	tree := make(map[string]any, len(doc))
+	for _, item := range doc {
+		tree[fmt.Sprint(item.Key)] = yamlValue(item.Value)
+	}
+	return tree
+}
+
+// yamlValue converts the mappings within a decoded YAML value into nested maps.
+func yamlValue(value any) any {
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		return yamlTree(v)
+	case []any:
+		items := make([]any, 0, len(v))
+		for _, item := range v {
+			items = append(items, yamlValue(item))
+		}
+		return items
+	default:
+		return value
+	}
+}
+
+// flatten writes the scalar values of a tree under their joined keys.
+func flatten(prefix string, tree map[string]any, values map[string]string) error {
+	keys := make([]string, 0, len(tree))
+	for key := range tree {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		name := key
+		if prefix != "" {
+			name = prefix + "-" + key
+		}
+		var leaf string
+		switch value := tree[key].(type) {
+		case map[string]any:
+			if err := flatten(name, value, values); err != nil {
+				return err
+			}
+			continue
+		case []any:
+			items := make([]string, 0, len(value))
+			for _, item := range value {
+				if _, nested := item.(map[string]any); nested {
+					return fmt.Errorf("%s: lists may only contain plain values", name)
+				}
+				items = append(items, fmt.Sprint(item))
+			}
+			leaf = strings.Join(items, ",")
+		case nil:
+		default:
+			leaf = fmt.Sprint(value)
+		}
+		if _, ok := values[name]; ok {
+			return fmt.Errorf("%s is set twice", name)
+		}
+		values[name] = leaf
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseTOML parses the subset of TOML configuration files need: comments, [tables] and [dotted.tables],
+// bare, quoted and dotted keys, and single-line values that are strings, numbers, booleans or arrays of them.
+func parseTOML(content string) (map[string]any, error) {
+	root := make(map[string]any)
+	table := root
+	for i, raw := range strings.Split(content, "\n") {
+		lineNo := i + 1
+		line := strings.TrimSpace(stripComment(raw))
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") || strings.HasPrefix(line, "[[") {
+				return nil, fmt.Errorf("line %d: malformed table header", lineNo)
+			}
+			keys, err := parseKey(line[1 : len(line)-1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			if table, err = subTable(root, keys); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			continue
+		}
+
+		rawKey, rawValue, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNo)
+		}
+		keys, err := parseKey(rawKey)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		value, err := parseValue(strings.TrimSpace(rawValue))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		parent, err := subTable(table, keys[:len(keys)-1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		last := keys[len(keys)-1]
+		if _, exists := parent[last]; exists {
+			return nil, fmt.Errorf("line %d: %s is set twice", lineNo, last)
+		}
+		parent[last] = value
+	}
+	return root, nil
+}
+
+// stripComment removes a # comment that is not part of a string.
+func stripComment(line string) string {
+	var quote rune
+	escaped := false
+	for i, r := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && r == '\\':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// parseKey splits a possibly dotted key into its parts, e.g. `mongo."uri"` into mongo and uri.
+func parseKey(raw string) ([]string, error) {
+	var keys []string
+	for _, part := range splitOutsideQuotes(raw, '.') {
+		part = strings.TrimSpace(part)
+		if len(part) >= 2 && (part[0] == '"' || part[0] == '\'') {
+			unquoted, err := parseString(part)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, unquoted)
+			continue
+		}
+		if part == "" || strings.IndexFunc(part, func(r rune) bool {
+			return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_')
+		}) >= 0 {
+			return nil, fmt.Errorf("invalid key %q", raw)
+		}
+		keys = append(keys, part)
+	}
+	return keys, nil
+}
+
+// subTable returns the table at the given path below root, creating missing tables.
+func subTable(root map[string]any, keys []string) (map[string]any, error) {
+	table := root
+	for _, key := range keys {
+		switch next := table[key].(type) {
+		case nil:
+			created := make(map[string]any)
+			table[key] = created
+			table = created
+		case map[string]any:
+			table = next
+		default:
+			return nil, fmt.Errorf("%s is a value, not a table", key)
+		}
+	}
+	return table, nil
+}
+
+// parseValue parses a string, number, boolean or array.
+func parseValue(raw string) (any, error) {
+	switch {
+	case raw == "":
+		return nil, fmt.Errorf("missing value")
+	case raw[0] == '"' || raw[0] == '\'':
+		return parseString(raw)
+	case raw[0] == '[':
+		if !strings.HasSuffix(raw, "]") {
+			return nil, fmt.Errorf("arrays must be written on a single line")
+		}
+		inner := strings.TrimSpace(raw[1 : len(raw)-1])
+		items := []any{}
+		for _, item := range splitOutsideQuotes(inner, ',') {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			value, err := parseValue(item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, value)
+		}
+		return items, nil
+	case raw == "true" || raw == "false":
+		return raw == "true", nil
+	}
+	number := strings.ReplaceAll(raw, "_", "")
+	if n, err := strconv.ParseInt(number, 0, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(number, 64); err == nil {
+		return f, nil
+	}
+	return nil, fmt.Errorf("unsupported value %q", raw)
+}
+
+// parseString unquotes a basic "string" with escapes or a literal 'string'.
+func parseString(raw string) (string, error) {
+	if len(raw) < 2 || raw[len(raw)-1] != raw[0] {
+		return "", fmt.Errorf("unterminated string %s", raw)
+	}
+	if raw[0] == '\'' {
+		return raw[1 : len(raw)-1], nil
+	}
+	unquoted, err := strconv.Unquote(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid string %s", raw)
+	}
+	return unquoted, nil
+}
+
+// splitOutsideQuotes splits s at every separator that is not part of a string.
+func splitOutsideQuotes(s string, separator rune) []string {
+	var parts []string
+	var quote rune
+	escaped := false
+	start := 0
+	for i, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && r == '\\':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == separator:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
@@ -0,0 +1,37 @@
+// Package device carries the description of the client a request is made from through contexts,
+// so the core layer can record which device a session was started on.
+package device
+
+import (
+	"context"
+	"strings"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// CountryHeader is the HTTP header (and gRPC metadata key) in which an edge proxy reports the country it
+// located the client in. The proxy has to overwrite the header, as clients could otherwise claim any country.
+const CountryHeader = "X-Client-Country"
+
+// contextKey is the context key under which the device is stored.
+type contextKey struct{}
+
+// WithDevice returns a copy of ctx carrying the device.
+func WithDevice(ctx context.Context, device domain.Device) context.Context {
+	return context.WithValue(ctx, contextKey{}, device)
+}
+
+// FromContext returns the device stored in ctx, or the zero Device if the adapter did not provide one.
+func FromContext(ctx context.Context) domain.Device {
+	device, _ := ctx.Value(contextKey{}).(domain.Device)
+	return device
+}
+
+// Country normalizes the value of the CountryHeader to an upper case ISO 3166-1 alpha-2 code.
+// Anything else, e.g. the "XX" some proxies report for unknown locations, yields an empty string.
+func Country(value string) string {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if len(value) != 2 || value == "XX" || strings.Trim(value, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return ""
+	}
+	return value
+}
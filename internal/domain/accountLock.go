@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// LockReason explains why an administrator locked an account.
+type LockReason string
+
+// Reasons administrators lock accounts for.
+const (
+	// LockReasonSuspectedCompromise marks accounts whose credentials are believed to be known to someone else.
+	LockReasonSuspectedCompromise LockReason = "SUSPECTED_COMPROMISE"
+	// LockReasonFraud marks accounts used for fraudulent activity.
+	LockReasonFraud LockReason = "FRAUD"
+	// LockReasonPolicyViolation marks accounts whose owner violated the terms of use.
+	LockReasonPolicyViolation LockReason = "POLICY_VIOLATION"
+	// LockReasonLegalRequest marks accounts locked on request of an authority.
+	LockReasonLegalRequest LockReason = "LEGAL_REQUEST"
+	// LockReasonOther marks accounts locked for any other reason.
+	LockReasonOther LockReason = "OTHER"
+)
+
+// Valid reports whether the reason is one of the known reasons.
+func (r LockReason) Valid() bool {
+	switch r {
+	case LockReasonSuspectedCompromise, LockReasonFraud, LockReasonPolicyViolation, LockReasonLegalRequest, LockReasonOther:
+		return true
+	default:
+		return false
+	}
+}
+
+// LockFor locks the account on behalf of an administrator, until the given time or, for the zero
+// time, until it is unlocked explicitly. Locking a locked account replaces its reason and expiry.
+//
+// Parameters:
+//   - reason: Why the account is locked
+//   - until: When the lock expires, the zero time for a lock without expiry
+//   - at: The time of the lock
+//
+// Returns:
+//   - StatusTransition: The transition; unchanged if the user was locked already
+//   - error: errorx.ErrInvalidLock for unknown reasons or an expiry not after at, an error wrapping
+//     errorx.ErrInvalidStatusTransition unless the account is active or locked
+func (u *User) LockFor(reason LockReason, until time.Time, at time.Time) (StatusTransition, error) {
+	if !reason.Valid() {
+		return StatusTransition{}, errorx.ErrInvalidLock.Detailf("unknown reason %q", reason)
+	}
+	if !until.IsZero() && !until.After(at) {
+		return StatusTransition{}, errorx.ErrInvalidLock.Detailf("the lock has to expire in the future")
+	}
+	transition, err := u.Lock(at)
+	if err != nil {
+		return StatusTransition{}, err
+	}
+	u.LockReason = reason
+	u.LockedUntil = until
+	return transition, nil
+}
+
+// LockExpired reports whether the account is locked by a lock whose expiry has passed.
+func (u User) LockExpired(now time.Time) bool {
+	return u.Status == StatusLocked && !u.LockedUntil.IsZero() && !now.Before(u.LockedUntil)
+}
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"slices"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// MergeUsernameDisposition decides what happens to the username of a duplicate account that is
+// merged into another one.
+type MergeUsernameDisposition string
+
+const (
+	// MergeReleaseUsername frees the username, so it can be registered again.
+	MergeReleaseUsername MergeUsernameDisposition = "RELEASE"
+	// MergeAliasUsername keeps the username as an alias the primary account can log in with.
+	MergeAliasUsername MergeUsernameDisposition = "ALIAS"
+)
+
+// Valid reports whether the disposition is one of the known dispositions.
+func (d MergeUsernameDisposition) Valid() bool {
+	return d == MergeReleaseUsername || d == MergeAliasUsername
+}
+
+// MergedUsername returns the username a merged duplicate account is renamed to, so the deleted
+// duplicate no longer blocks its former username.
+func MergedUsername(id string) Username {
+	return RestoreUsername("merged-" + id)
+}
+
+// AccountMerge is the outcome of merging a duplicate account into a primary account. Both users
+// are in their merged state and have to be stored.
+type AccountMerge struct {
+	Primary   User
+	Duplicate User
+	// DuplicateUsername is the username the duplicate had before the merge.
+	DuplicateUsername Username
+	Disposition       MergeUsernameDisposition
+	PreviousRoles     []Role
+	RolesChanged      bool
+	EmailMoved        bool
+	MergedAt          time.Time
+}
+
+// MergeAccounts merges a duplicate account, e.g. one created twice by an import, into a primary account.
+//
+// The primary account keeps its username, password and status and gains the roles of the duplicate
+// and, if it has none, its email address. Depending on the disposition, the username and aliases
+// of the duplicate are released or become aliases of the primary account. The duplicate is renamed
+// to MergedUsername, loses its email address and is deleted.
+//
+// Parameters:
+//   - primary: The account that remains
+//   - duplicate: The account that is merged into the primary one
+//   - disposition: What happens to the username of the duplicate
+//   - known: The roles known to the application; roles of the duplicate that no longer exist are dropped
+//   - c: The canonicalizer for the changed usernames, aliases and email addresses
+//   - at: The time of the merge
+//
+// Returns:
+//   - AccountMerge: The merged accounts
+//   - error: errorx.ErrInvalidMerge if the accounts are the same, belong to different tenants or the
+//     disposition is unknown, errorx.ErrInvalidStatusTransition if one of them is deleted
+func MergeAccounts(primary User, duplicate User, disposition MergeUsernameDisposition, known RolePermissions, c Canonicalizer, at time.Time) (AccountMerge, error) {
+	switch {
+	case primary.ID == duplicate.ID:
+		return AccountMerge{}, errorx.ErrInvalidMerge.Detailf("an account cannot be merged into itself")
+	case primary.TenantID != duplicate.TenantID:
+		return AccountMerge{}, errorx.ErrInvalidMerge.Detailf("the accounts belong to different tenants")
+	case !disposition.Valid():
+		return AccountMerge{}, errorx.ErrInvalidMerge.Detailf("unknown username disposition %q", disposition)
+	case primary.Status == StatusDeleted || duplicate.Status == StatusDeleted:
+		return AccountMerge{}, errorx.ErrInvalidStatusTransition.Detailf("deleted accounts cannot be merged")
+	}
+
+	merge := AccountMerge{DuplicateUsername: duplicate.Username, Disposition: disposition, PreviousRoles: slices.Clone(primary.Roles), MergedAt: at}
+	roles := slices.Clone(primary.Roles)
+	for _, role := range duplicate.Roles {
+		if known.Exists(role) {
+			roles = append(roles, role)
+		}
+	}
+	changed, err := primary.AssignRoles(roles, known)
+	if err != nil {
+		return AccountMerge{}, err
+	}
+	merge.RolesChanged = changed
+	if primary.Email.IsZero() && !duplicate.Email.IsZero() {
+		primary.ChangeEmail(duplicate.Email)
+		merge.EmailMoved = true
+	}
+	if disposition == MergeAliasUsername {
+		primary.Aliases = append(primary.Aliases, duplicate.Username)
+		primary.Aliases = append(primary.Aliases, duplicate.Aliases...)
+	}
+	primary.Canonicalize(c)
+
+	duplicate.Rename(MergedUsername(duplicate.ID))
+	duplicate.ChangeEmail(Email{})
+	duplicate.Aliases = nil
+	if _, err := duplicate.Delete(at); err != nil {
+		return AccountMerge{}, err
+	}
+	duplicate.MergedInto = primary.ID
+	duplicate.Canonicalize(c)
+
+	merge.Primary = primary
+	merge.Duplicate = duplicate
+	return merge, nil
+}
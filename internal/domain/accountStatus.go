@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// AccountStatus is a state in the lifecycle of a user account.
+//
+// The lifecycle is a state machine:
+//
+//	PENDING  -> ACTIVE, DELETED
+//	ACTIVE   -> LOCKED, DISABLED, DELETED
+//	LOCKED   -> ACTIVE, DISABLED, DELETED
+//	DISABLED -> ACTIVE, DELETED
+//	DELETED  (final)
+//
+// Only ACTIVE accounts may log in.
+type AccountStatus string
+
+// Account statuses.
+const (
+	StatusPending  AccountStatus = "PENDING"
+	StatusActive   AccountStatus = "ACTIVE"
+	StatusLocked   AccountStatus = "LOCKED"
+	StatusDisabled AccountStatus = "DISABLED"
+	StatusDeleted  AccountStatus = "DELETED"
+)
+
+// statusTransitions lists the statuses every status may change to.
+var statusTransitions = map[AccountStatus][]AccountStatus{
+	StatusPending:  {StatusActive, StatusDeleted},
+	StatusActive:   {StatusLocked, StatusDisabled, StatusDeleted},
+	StatusLocked:   {StatusActive, StatusDisabled, StatusDeleted},
+	StatusDisabled: {StatusActive, StatusDeleted},
+	StatusDeleted:  {},
+}
+
+// Valid reports whether the status is one of the known statuses.
+func (s AccountStatus) Valid() bool {
+	_, ok := statusTransitions[s]
+	return ok
+}
+
+// CanTransitionTo reports whether the lifecycle allows changing from this status to next.
+func (s AccountStatus) CanTransitionTo(next AccountStatus) bool {
+	for _, allowed := range statusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusTransition records a change of the account status.
+type StatusTransition struct {
+	From AccountStatus
+	To   AccountStatus
+	At   time.Time
+}
+
+// transitionStatus moves the account to the next status, if the lifecycle allows it.
+//
+// Returns:
+//   - StatusTransition: The transition, with From equal to To if the account had the status already
+//   - error: An error wrapping errorx.ErrInvalidStatusTransition if the lifecycle does not allow the change
+func (u *User) transitionStatus(next AccountStatus, at time.Time) (StatusTransition, error) {
+	transition := StatusTransition{From: u.Status, To: next, At: at}
+	if u.Status == next {
+		return transition, nil
+	}
+	if !u.Status.CanTransitionTo(next) {
+		return StatusTransition{}, errorx.ErrInvalidStatusTransition.Detailf("%s to %s", u.Status, next)
+	}
+	u.Status = next
+	if next != StatusLocked {
+		u.LockReason = ""
+		u.LockedUntil = time.Time{}
+	}
+	return transition, nil
+}
+
+// Changed reports whether the transition changed the status.
+func (t StatusTransition) Changed() bool {
+	return t.From != t.To
+}
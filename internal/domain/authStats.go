@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// Bounds of the period statistics are reported for.
+const (
+	DefaultStatsPeriod = 30 * 24 * time.Hour
+	MaxStatsPeriod     = 366 * 24 * time.Hour
+)
+
+// StatsPeriod is the range of whole UTC days statistics are aggregated over, from the start of
+// From up to but excluding Until.
+type StatsPeriod struct {
+	From  time.Time
+	Until time.Time
+}
+
+// NewStatsPeriod creates the period of whole days between two times.
+//
+// Parameters:
+//   - from: A time on the first day of the period
+//   - until: A time on the day after the last day of the period
+//
+// Returns:
+//   - StatsPeriod: The period, truncated to UTC days
+//   - error: errorx.ErrInvalidStatsPeriod if the period is empty or longer than MaxStatsPeriod
+func NewStatsPeriod(from time.Time, until time.Time) (StatsPeriod, error) {
+	period := StatsPeriod{From: from.UTC().Truncate(24 * time.Hour), Until: until.UTC().Truncate(24 * time.Hour)}
+	if !period.Until.After(period.From) {
+		return StatsPeriod{}, errorx.ErrInvalidStatsPeriod.Detailf("the period must end after it starts")
+	}
+	if period.Until.Sub(period.From) > MaxStatsPeriod {
+		return StatsPeriod{}, errorx.ErrInvalidStatsPeriod.Detailf("the period must not be longer than %d days", int(MaxStatsPeriod/(24*time.Hour)))
+	}
+	return period, nil
+}
+
+// Days returns the first instant of every day of the period.
+func (p StatsPeriod) Days() []time.Time {
+	var days []time.Time
+	for day := p.From; day.Before(p.Until); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+	return days
+}
+
+// DailyCount is the number of occurrences on a UTC day.
+type DailyCount struct {
+	Day   time.Time
+	Count int64
+}
+
+// FillDays returns a count for every day of the period, in order, with zero for days without occurrences.
+func (p StatsPeriod) FillDays(counts []DailyCount) []DailyCount {
+	byDay := make(map[time.Time]int64, len(counts))
+	for _, count := range counts {
+		byDay[count.Day.UTC().Truncate(24*time.Hour)] += count.Count
+	}
+	days := p.Days()
+	filled := make([]DailyCount, 0, len(days))
+	for _, day := range days {
+		filled = append(filled, DailyCount{Day: day, Count: byDay[day]})
+	}
+	return filled
+}
+
+// LoginStats aggregates the authentication attempts of a period. Active users are the distinct
+// usernames with at least one successful login.
+type LoginStats struct {
+	Successes        int64
+	Failures         int64
+	FailuresByReason map[string]int64
+	ActiveUsers      int64
+}
+
+// SuccessRatio returns the share of successful attempts, 0 if there were none.
+func (s LoginStats) SuccessRatio() float64 {
+	total := s.Successes + s.Failures
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Successes) / float64(total)
+}
+
+// MfaAdoption counts the accounts that have enabled multi-factor authentication.
+type MfaAdoption struct {
+	Users           int64
+	MfaEnabledUsers int64
+}
+
+// Ratio returns the share of accounts with multi-factor authentication, 0 if there are no accounts.
+func (a MfaAdoption) Ratio() float64 {
+	if a.Users == 0 {
+		return 0
+	}
+	return float64(a.MfaEnabledUsers) / float64(a.Users)
+}
+
+// AuthStats are the statistics of a period. The MFA adoption is the current state of the accounts,
+// independent of the period.
+type AuthStats struct {
+	Period        StatsPeriod
+	Registrations []DailyCount
+	Logins        LoginStats
+	Mfa           MfaAdoption
+}
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"regexp"
+	"sort"
+	"time"
+)
+
+// Role names a set of permissions granted to the users holding it.
+type Role string
+
+// Permission is the right to perform an operation, named "<resource>:<action>".
+type Permission string
+
+// Built-in roles.
+const (
+	RoleUser  Role = "USER"
+	RoleAdmin Role = "ADMIN"
+)
+
+// Built-in permissions.
+const (
+	PermissionProfileRead  Permission = "profile:read"
+	PermissionProfileWrite Permission = "profile:write"
+	PermissionUsersRead    Permission = "users:read"
+	PermissionUsersWrite   Permission = "users:write"
+	PermissionWebhooks     Permission = "webhooks:manage"
+	PermissionSystem       Permission = "system:manage"
+	PermissionRoles        Permission = "roles:manage"
+	PermissionGroups       Permission = "groups:manage"
+	PermissionPolicies     Permission = "policies:manage"
+	PermissionTenants      Permission = "tenants:manage"
+	PermissionConsents     Permission = "consents:manage"
+	PermissionUsersErase   Permission = "users:erase"
+)
+
+// platformPermissions are the permissions over the whole installation instead of a single tenant:
+// the tenants, the role definitions shared by all tenants and the state of the service. They are
+// only granted to users of the default tenant, which operates the installation, so the
+// administrators of other tenants cannot change settings that apply beyond their own tenant.
+var platformPermissions = map[Permission]bool{PermissionSystem: true, PermissionRoles: true, PermissionTenants: true}
+
+// rolePattern allows upper case letters, digits and underscores, starting with a letter.
+var rolePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,31}$`)
+
+// permissionPattern allows "<resource>:<action>" made of lower case letters, digits, dashes and underscores.
+var permissionPattern = regexp.MustCompile(`^[a-z0-9_-]{1,32}:[a-z0-9_-]{1,32}$`)
+
+// Valid reports whether the role name is well-formed, e.g. "SUPPORT_AGENT".
+func (r Role) Valid() bool {
+	return rolePattern.MatchString(string(r))
+}
+
+// Valid reports whether the permission name is well-formed, e.g. "users:read".
+func (p Permission) Valid() bool {
+	return permissionPattern.MatchString(string(p))
+}
+
+// RoleDefinition is a role together with the permissions it grants.
+//
+// Built-in roles always exist and cannot be deleted; the permissions of ADMIN cannot be changed
+// either, so the application always keeps an administrator able to repair the role definitions.
+type RoleDefinition struct {
+	Name        Role
+	Permissions []Permission
+	BuiltIn     bool
+	UpdatedAt   time.Time
+}
+
+// Editable reports whether the permissions of the role may be changed.
+func (rd RoleDefinition) Editable() bool {
+	return rd.Name != RoleAdmin
+}
+
+// RolePermissions maps every known role to the permissions it grants.
+type RolePermissions map[Role][]Permission
+
+// DefaultRolePermissions maps every built-in role to the permissions it grants by default.
+// The platform permissions of ADMIN only apply to administrators of the default tenant.
+var DefaultRolePermissions = RolePermissions{
+	RoleUser:  {PermissionProfileRead, PermissionProfileWrite},
+	RoleAdmin: {PermissionProfileRead, PermissionProfileWrite, PermissionUsersRead, PermissionUsersWrite, PermissionWebhooks, PermissionSystem, PermissionRoles, PermissionGroups, PermissionPolicies, PermissionTenants, PermissionConsents, PermissionUsersErase},
+}
+
+// Exists reports whether the role is known.
+func (rp RolePermissions) Exists(role Role) bool {
+	_, ok := rp[role]
+	return ok
+}
+
+// Grants reports whether any of the roles grants the permission to a user of the tenant.
+// Platform permissions are only granted in the default tenant.
+func (rp RolePermissions) Grants(tenantID string, roles []Role, permission Permission) bool {
+	if platformPermissions[permission] && tenantID != DefaultTenantID {
+		return false
+	}
+	for _, role := range roles {
+		for _, granted := range rp[role] {
+			if granted == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Flatten returns the permissions granted by any of the roles to a user of the tenant, sorted and
+// without duplicates. Unknown roles grant nothing, platform permissions are left out outside the
+// default tenant.
+func (rp RolePermissions) Flatten(tenantID string, roles []Role) []Permission {
+	seen := map[Permission]bool{}
+	permissions := []Permission{}
+	for _, role := range roles {
+		for _, permission := range rp[role] {
+			if platformPermissions[permission] && tenantID != DefaultTenantID {
+				continue
+			}
+			if !seen[permission] {
+				seen[permission] = true
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+	sort.Slice(permissions, func(i, j int) bool { return permissions[i] < permissions[j] })
+	return permissions
+}
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"golang.org/x/net/idna"
+	"golang.org/x/text/unicode/norm"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// homoglyphs maps characters of other scripts that are indistinguishable from Latin letters to
+// those letters, e.g. the Cyrillic "а" to "a".
+var homoglyphs = map[rune]rune{
+	// Cyrillic
+	'а': 'a', 'в': 'b', 'е': 'e', 'һ': 'h', 'і': 'i', 'ј': 'j', 'к': 'k', 'ӏ': 'l', 'м': 'm', 'н': 'h', 'о': 'o',
+	'р': 'p', 'ԛ': 'q', 'ѕ': 's', 'т': 't', 'у': 'y', 'ү': 'y', 'ԝ': 'w', 'х': 'x', 'ԁ': 'd', 'с': 'c',
+	// Greek
+	'α': 'a', 'β': 'b', 'ι': 'i', 'κ': 'k', 'ν': 'v', 'ο': 'o', 'ρ': 'p', 'τ': 't', 'υ': 'u', 'χ': 'x', 'γ': 'y',
+	// Latin look-alikes
+	'ı': 'i', 'ɡ': 'g', 'ℓ': 'l',
+}
+
+// usernameDigitHomoglyphs maps digits that pass for letters in usernames.
+var usernameDigitHomoglyphs = map[rune]rune{'0': 'o', '1': 'l'}
+
+// Canonicalizer derives the keys under which usernames and email addresses have to be unique.
+// Two accounts whose keys are equal look the same to people or reach the same mailbox, e.g.
+// "jdoe" and "j.d0e", or "jane.doe+spam@gmail.com" and "janedoe@googlemail.com", so the second
+// one is rejected as duplicate.
+type Canonicalizer struct {
+	// FoldPlusAliases removes "+tag" suffixes from the local part of every address.
+	FoldPlusAliases bool
+	// FoldDots removes the dots from the local part of addresses at the DotInsensitiveDomains.
+	FoldDots bool
+	// DotInsensitiveDomains lists the mail providers that ignore dots in local parts.
+	DotInsensitiveDomains []string
+	// DomainAliases maps domains to the domain they deliver to, e.g. "googlemail.com" to "gmail.com".
+	DomainAliases map[string]string
+}
+
+// DefaultCanonicalizer folds plus aliases everywhere and dots at Gmail, which also serves googlemail.com.
+var DefaultCanonicalizer = Canonicalizer{
+	FoldPlusAliases:       true,
+	FoldDots:              true,
+	DotInsensitiveDomains: []string{"gmail.com"},
+	DomainAliases:         map[string]string{"googlemail.com": "gmail.com"},
+}
+
+// Username returns the canonical key of a username: its skeleton without punctuation, with
+// homoglyphs of other scripts and the digits 0 and 1 replaced by the letters they resemble.
+//
+// Parameters:
+//   - username: The normalized username
+//
+// Returns:
+//   - string: The key, "" for the zero Username
+func (c Canonicalizer) Username(username Username) string {
+	return strings.Map(func(r rune) rune {
+		if latin, ok := usernameDigitHomoglyphs[r]; ok {
+			return latin
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return -1
+		}
+		return r
+	}, skeleton(username.value))
+}
+
+// Email returns the canonical key of an email address. The domain is decoded from punycode, the
+// skeletons of both parts are taken and the configured aliases are folded.
+//
+// Parameters:
+//   - email: The normalized address
+//
+// Returns:
+//   - string: The key, "" for the zero Email
+func (c Canonicalizer) Email(email Email) string {
+	at := strings.LastIndexByte(email.value, '@')
+	if at < 0 {
+		return ""
+	}
+	local, domain := email.value[:at], strings.TrimSuffix(email.value[at+1:], ".")
+
+	if decoded, err := idna.ToUnicode(domain); err == nil {
+		domain = decoded
+	}
+	domain = skeleton(domain)
+	if alias, ok := c.DomainAliases[domain]; ok {
+		domain = alias
+	}
+
+	local = skeleton(local)
+	if c.FoldPlusAliases {
+		if plus := strings.IndexByte(local, '+'); plus > 0 {
+			local = local[:plus]
+		}
+	}
+	if c.FoldDots && slices.Contains(c.DotInsensitiveDomains, domain) {
+		local = strings.ReplaceAll(local, ".", "")
+	}
+	return local + "@" + domain
+}
+
+// skeleton reduces a string to the form people perceive: compatibility characters such as
+// full-width letters are decomposed, diacritics are removed and homoglyphs are replaced.
+func skeleton(s string) string {
+	decomposed := strings.ToLower(norm.NFKD.String(s))
+	return strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		if latin, ok := homoglyphs[r]; ok {
+			return latin
+		}
+		return r
+	}, decomposed)
+}
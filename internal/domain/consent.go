@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// consentVersionPattern allows version labels such as "2026-10" or "v3.1".
+var consentVersionPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,31}$`)
+
+// ConsentDocument names a legal document users have to accept.
+type ConsentDocument string
+
+// Consent documents.
+const (
+	ConsentTerms         ConsentDocument = "terms"
+	ConsentPrivacyPolicy ConsentDocument = "privacy_policy"
+)
+
+// Valid reports whether the document is one of the known consent documents.
+func (d ConsentDocument) Valid() bool {
+	return d == ConsentTerms || d == ConsentPrivacyPolicy
+}
+
+// DocumentVersion is a published version of a consent document. The most recently published
+// version of a document is the current one, which every user has to accept.
+type DocumentVersion struct {
+	Document    ConsentDocument
+	Version     string
+	PublishedAt time.Time
+}
+
+// NewDocumentVersion validates a version of a consent document to be published.
+//
+// Parameters:
+//   - document: The document
+//   - version: The version label, up to 32 letters, digits, dots, dashes and underscores
+//   - publishedAt: The time of the publication
+//
+// Returns:
+//   - DocumentVersion: The version
+//   - error: errorx.ErrInvalidConsent if the document is unknown or the version label is malformed
+func NewDocumentVersion(document ConsentDocument, version string, publishedAt time.Time) (DocumentVersion, error) {
+	if !document.Valid() {
+		return DocumentVersion{}, errorx.ErrInvalidConsent.Detailf("unknown document %q, expected terms or privacy_policy", document)
+	}
+	if !consentVersionPattern.MatchString(version) {
+		return DocumentVersion{}, errorx.ErrInvalidConsent.Detailf("versions consist of up to 32 letters, digits, dots, dashes and underscores")
+	}
+	return DocumentVersion{Document: document, Version: version, PublishedAt: publishedAt}, nil
+}
+
+// ConsentRef names a document version a user accepts.
+type ConsentRef struct {
+	Document ConsentDocument
+	Version  string
+}
+
+// Consent records that a user accepted a version of a consent document. Consents are never
+// changed or removed; accepting a new version adds another consent.
+type Consent struct {
+	UserID     string          `classification:"operational"`
+	Username   Username        `classification:"pii"`
+	Document   ConsentDocument `classification:"operational"`
+	Version    string          `classification:"operational"`
+	AcceptedAt time.Time       `classification:"operational"`
+	IPAddress  string          `classification:"pii"`
+}
+
+// ConsentStatus is the consent history of a user together with the current document versions
+// the user has not accepted yet.
+type ConsentStatus struct {
+	Consents    []Consent
+	Outstanding []DocumentVersion
+}
+
+// AcceptConsents records the acceptance of document versions by a user.
+//
+// Parameters:
+//   - user: The accepting user; the id may still be empty during registration
+//   - refs: The accepted document versions
+//   - current: The current document versions
+//   - device: The client the acceptance is made from
+//   - at: The time of the acceptance
+//
+// Returns:
+//   - []Consent: The consents to store
+//   - error: errorx.ErrInvalidConsent if a reference does not name the current version of a document
+func AcceptConsents(user User, refs []ConsentRef, current []DocumentVersion, device Device, at time.Time) ([]Consent, error) {
+	consents := make([]Consent, 0, len(refs))
+	for _, ref := range refs {
+		if !slices.ContainsFunc(current, func(v DocumentVersion) bool { return v.Document == ref.Document && v.Version == ref.Version }) {
+			return nil, errorx.ErrInvalidConsent.Detailf("%s version %q is not the current version", ref.Document, ref.Version)
+		}
+		consents = append(consents, Consent{
+			UserID:     user.ID,
+			Username:   user.Username,
+			Document:   ref.Document,
+			Version:    ref.Version,
+			AcceptedAt: at,
+			IPAddress:  device.IPAddress,
+		})
+	}
+	return consents, nil
+}
+
+// OutstandingDocuments returns the current document versions that have not been accepted.
+//
+// Parameters:
+//   - current: The current document versions
+//   - accepted: The consents of the user
+//
+// Returns:
+//   - []DocumentVersion: The versions the user still has to accept
+func OutstandingDocuments(current []DocumentVersion, accepted []Consent) []DocumentVersion {
+	var outstanding []DocumentVersion
+	for _, version := range current {
+		if !slices.ContainsFunc(accepted, func(c Consent) bool { return c.Document == version.Document && c.Version == version.Version }) {
+			outstanding = append(outstanding, version)
+		}
+	}
+	return outstanding
+}
+
+// ConsentRequired returns errorx.ErrConsentRequired naming the outstanding document versions.
+func ConsentRequired(outstanding []DocumentVersion) error {
+	names := make([]string, 0, len(outstanding))
+	for _, version := range outstanding {
+		names = append(names, fmt.Sprintf("%s version %s", version.Document, version.Version))
+	}
+	return errorx.ErrConsentRequired.Detailf("accept %s", strings.Join(names, " and "))
+}
@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// CredentialEventType identifies what happened to a user's credentials.
+type CredentialEventType string
+
+const (
+	CredentialCreated         CredentialEventType = "CREDENTIAL_CREATED"
+	CredentialHashUpgraded    CredentialEventType = "HASH_UPGRADED"
+	CredentialPasswordChanged CredentialEventType = "PASSWORD_CHANGED"
+	CredentialMfaEnrolled     CredentialEventType = "MFA_ENROLLED"
+	CredentialMfaRemoved      CredentialEventType = "MFA_REMOVED"
+	CredentialLockoutApplied  CredentialEventType = "LOCKOUT_APPLIED"
+	CredentialLockoutLifted   CredentialEventType = "LOCKOUT_LIFTED"
+	CredentialStatusChanged   CredentialEventType = "STATUS_CHANGED"
+	CredentialRolesChanged    CredentialEventType = "ROLES_CHANGED"
+	CredentialAccountMerged   CredentialEventType = "ACCOUNT_MERGED"
+)
+
+// Well-known keys of CredentialEvent.Details.
+const (
+	CredentialDetailAlgorithm = "algorithm"
+	CredentialDetailMethod    = "method"
+	CredentialDetailReason    = "reason"
+	CredentialDetailUntil     = "until"
+	CredentialDetailFrom      = "from"
+	CredentialDetailTo        = "to"
+	CredentialDetailAccountID = "accountId"
+)
+
+// CredentialEvent is an immutable fact about a change to a user's credentials.
+//
+// Credential events form an append-only stream per user. Sequence numbers start at 1
+// and increase by one with every event of the same user, so gaps reveal tampering.
+type CredentialEvent struct {
+	Username   string              `classification:"pii"`
+	Sequence   int64               `classification:"operational"`
+	Type       CredentialEventType `classification:"operational"`
+	OccurredAt time.Time           `classification:"operational"`
+	Details    map[string]string   `classification:"pii"`
+}
+
+// NewCredentialEvent creates a credential event that has not been assigned a sequence number yet.
+//
+// Parameters:
+//   - username: The user whose credentials changed
+//   - eventType: What happened
+//   - details: Optional event-specific details, see the CredentialDetail* keys
+//
+// Returns:
+//   - CredentialEvent: The new event
+func NewCredentialEvent(username string, eventType CredentialEventType, details map[string]string) CredentialEvent {
+	return CredentialEvent{
+		Username:   username,
+		Type:       eventType,
+		OccurredAt: time.Now(),
+		Details:    details,
+	}
+}
+
+// SecurityTimeline is the credential state of an account reconstructed from its event stream.
+type SecurityTimeline struct {
+	Username           string
+	Events             []CredentialEvent
+	CreatedAt          time.Time
+	PasswordChangedAt  time.Time
+	HashAlgorithm      string
+	MfaMethods         []string
+	Locked             bool
+	LockedUntil        time.Time
+	LockoutCount       int
+	Status             string
+	Roles              []string
+	ConsistentSequence bool
+}
+
+// ReplayCredentialEvents reconstructs the security timeline of an account.
+//
+// The events are applied in sequence order. The resulting timeline flags whether
+// the stream is free of gaps and duplicates.
+//
+// Parameters:
+//   - username: The user the events belong to
+//   - events: The user's credential events in any order
+//
+// Returns:
+//   - SecurityTimeline: The reconstructed timeline
+func ReplayCredentialEvents(username string, events []CredentialEvent) SecurityTimeline {
+	sorted := make([]CredentialEvent, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Sequence < sorted[j].Sequence })
+
+	timeline := SecurityTimeline{Username: username, Events: sorted, ConsistentSequence: true}
+	for i, event := range sorted {
+		if event.Sequence != int64(i+1) {
+			timeline.ConsistentSequence = false
+		}
+		timeline.apply(event)
+	}
+	return timeline
+}
+
+// apply folds a single event into the timeline.
+func (t *SecurityTimeline) apply(event CredentialEvent) {
+	switch event.Type {
+	case CredentialCreated:
+		t.CreatedAt = event.OccurredAt
+		t.PasswordChangedAt = event.OccurredAt
+		t.HashAlgorithm = event.Details[CredentialDetailAlgorithm]
+	case CredentialHashUpgraded:
+		t.HashAlgorithm = event.Details[CredentialDetailAlgorithm]
+	case CredentialPasswordChanged:
+		t.PasswordChangedAt = event.OccurredAt
+		if algorithm, ok := event.Details[CredentialDetailAlgorithm]; ok {
+			t.HashAlgorithm = algorithm
+		}
+	case CredentialMfaEnrolled:
+		t.MfaMethods = append(t.MfaMethods, event.Details[CredentialDetailMethod])
+	case CredentialMfaRemoved:
+		method := event.Details[CredentialDetailMethod]
+		for i, m := range t.MfaMethods {
+			if m == method {
+				t.MfaMethods = append(t.MfaMethods[:i], t.MfaMethods[i+1:]...)
+				break
+			}
+		}
+	case CredentialLockoutApplied:
+		t.Locked = true
+		t.LockoutCount++
+		t.LockedUntil = time.Time{}
+		if until, err := time.Parse(time.RFC3339, event.Details[CredentialDetailUntil]); err == nil {
+			t.LockedUntil = until
+		}
+	case CredentialLockoutLifted:
+		t.Locked = false
+		t.LockedUntil = time.Time{}
+	case CredentialStatusChanged:
+		t.Status = event.Details[CredentialDetailTo]
+	case CredentialRolesChanged:
+		t.Roles = strings.Split(event.Details[CredentialDetailTo], ",")
+	}
+}
@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"fmt"
+	"reflect"
+	"unicode"
+)
+
+// classificationTag is the struct tag declaring the DataClassification of a field of a record
+// holding data about users, e.g. `classification:"pii"`.
+const classificationTag = "classification"
+
+// DataClassification tells data protection tooling how a field of a user related record is treated.
+type DataClassification string
+
+// Data classifications.
+const (
+	// ClassificationPII marks personal data about the user, e.g. the username or the IP address of a session.
+	// It is part of data exports and removed when the user's data is erased.
+	ClassificationPII DataClassification = "pii"
+	// ClassificationCredential marks secrets, e.g. password hashes. They are never exported and
+	// removed when the user's data is erased.
+	ClassificationCredential DataClassification = "credential"
+	// ClassificationOperational marks data the service needs to operate, e.g. ids, statuses and timestamps.
+	// It is part of data exports and kept when the user's data is erased.
+	ClassificationOperational DataClassification = "operational"
+	// ClassificationUnclassified is reported for fields without classification, so gaps can be spotted.
+	// Tooling has to treat them like personal data.
+	ClassificationUnclassified DataClassification = "unclassified"
+)
+
+// Exportable reports whether fields of the classification are handed out in data exports.
+func (c DataClassification) Exportable() bool {
+	return c != ClassificationCredential
+}
+
+// Erasable reports whether fields of the classification are removed when the user's data is erased.
+func (c DataClassification) Erasable() bool {
+	return c != ClassificationOperational
+}
+
+// ClassifiedField is a field of a record together with its classification.
+type ClassifiedField struct {
+	// Name is the lower camel case name of the field, e.g. "lastLoginAt".
+	Name           string
+	Classification DataClassification
+	// Value is the value of the field; value objects such as Username are reported as their string form.
+	Value any
+}
+
+// RecordClassification lists the classified fields of a kind of record.
+type RecordClassification struct {
+	Record string
+	Fields []ClassifiedField
+}
+
+// PersonalDataInventory lists the records holding data about users together with the classification
+// of their fields, without values. Data protection tooling uses it to find personal data without
+// hardcoding field lists.
+//
+// Returns:
+//   - []RecordClassification: The records and their fields
+func PersonalDataInventory() []RecordClassification {
+	records := []struct {
+		name   string
+		record any
+	}{
+		{"user", User{}},
+		{"profile", Profile{}},
+		{"session", Session{}},
+		{"consent", Consent{}},
+		{"credential_event", CredentialEvent{}},
+		{"user_overview", UserOverview{}},
+		{"data_export", DataExport{}},
+		{"login_record", LoginRecord{}},
+		{"email_suppression", EmailSuppression{}},
+	}
+
+	inventory := make([]RecordClassification, 0, len(records))
+	for _, r := range records {
+		fields := ClassifyFields(r.record)
+		for i := range fields {
+			fields[i].Value = nil
+		}
+		inventory = append(inventory, RecordClassification{Record: r.name, Fields: fields})
+	}
+	return inventory
+}
+
+// ClassifyFields returns the exported fields of a record with their classification and value.
+//
+// Parameters:
+//   - record: A struct or a pointer to a struct whose fields carry classification tags
+//
+// Returns:
+//   - []ClassifiedField: The fields in declaration order; fields without tag are ClassificationUnclassified
+func ClassifyFields(record any) []ClassifiedField {
+	value := reflect.Indirect(reflect.ValueOf(record))
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []ClassifiedField
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		classification := DataClassification(field.Tag.Get(classificationTag))
+		if classification == "" {
+			classification = ClassificationUnclassified
+		}
+		fields = append(fields, ClassifiedField{Name: lowerCamel(field.Name), Classification: classification, Value: fieldValue(value.Field(i))})
+	}
+	return fields
+}
+
+// ExportableFields returns the fields of a record that are handed out in data exports, keyed by
+// their lower camel case name. Credentials are left out.
+//
+// Parameters:
+//   - record: A struct or a pointer to a struct whose fields carry classification tags
+//
+// Returns:
+//   - map[string]any: The values of the exportable fields
+func ExportableFields(record any) map[string]any {
+	fields := ClassifyFields(record)
+	exportable := make(map[string]any, len(fields))
+	for _, field := range fields {
+		if field.Classification.Exportable() {
+			exportable[field.Name] = field.Value
+		}
+	}
+	return exportable
+}
+
+// EraseFields resets the fields of a record whose classification is erasable to their zero value.
+//
+// Parameters:
+//   - record: A pointer to a struct whose fields carry classification tags
+//
+// Returns:
+//   - []string: The names of the erased fields
+//   - error: An error if record is not a pointer to a struct
+func EraseFields(record any) ([]string, error) {
+	pointer := reflect.ValueOf(record)
+	if pointer.Kind() != reflect.Pointer || pointer.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot erase fields of %T, expected a pointer to a struct", record)
+	}
+
+	value := pointer.Elem()
+	var erased []string
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		classification := DataClassification(field.Tag.Get(classificationTag))
+		if !field.IsExported() || (classification != "" && !classification.Erasable()) {
+			continue
+		}
+		value.Field(i).SetZero()
+		erased = append(erased, lowerCamel(field.Name))
+	}
+	return erased, nil
+}
+
+// fieldValue returns the value of a field, value objects as their string form.
+func fieldValue(value reflect.Value) any {
+	if stringer, ok := value.Interface().(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	return value.Interface()
+}
+
+// lowerCamel converts the name of a Go field to lower camel case, e.g. "IPAddress" to "ipAddress".
+func lowerCamel(name string) string {
+	runes := []rune(name)
+	for i := range runes {
+		if !unicode.IsUpper(runes[i]) || (i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"time"
+)
+
+// DataExportStatus is the state of the generation of a data export.
+type DataExportStatus string
+
+// Data export statuses.
+const (
+	// DataExportPending marks exports whose archive is still being generated.
+	DataExportPending DataExportStatus = "PENDING"
+	// DataExportReady marks exports whose archive can be downloaded.
+	DataExportReady DataExportStatus = "READY"
+	// DataExportFailed marks exports whose archive could not be generated; the user has to request a new one.
+	DataExportFailed DataExportStatus = "FAILED"
+)
+
+// DataExport is a machine-readable archive of everything stored about a user, requested by the
+// user under the right of access and data portability. The archive is generated in the background,
+// stored apart from the export and can be downloaded until the export expires.
+type DataExport struct {
+	ID          string           `classification:"operational"`
+	UserID      string           `classification:"operational"`
+	Username    Username         `classification:"pii"`
+	Status      DataExportStatus `classification:"operational"`
+	RequestedAt time.Time        `classification:"operational"`
+	CompletedAt time.Time        `classification:"operational"`
+	ExpiresAt   time.Time        `classification:"operational"`
+}
+
+// NewDataExport starts a data export of a user.
+//
+// Parameters:
+//   - id: The unique, unguessable id of the export
+//   - user: The user whose data is exported
+//   - requestedAt: The time of the request
+//   - expiresAt: The time the export and its archive are removed
+//
+// Returns:
+//   - DataExport: The pending export
+func NewDataExport(id string, user User, requestedAt time.Time, expiresAt time.Time) DataExport {
+	return DataExport{
+		ID:          id,
+		UserID:      user.ID,
+		Username:    user.Username,
+		Status:      DataExportPending,
+		RequestedAt: requestedAt,
+		ExpiresAt:   expiresAt,
+	}
+}
+
+// Complete makes the export downloadable once its archive has been stored.
+func (e *DataExport) Complete(now time.Time) {
+	e.Status = DataExportReady
+	e.CompletedAt = now
+}
+
+// Fail marks the export as failed.
+func (e *DataExport) Fail(now time.Time) {
+	e.Status = DataExportFailed
+	e.CompletedAt = now
+}
+
+// IsExpired reports whether the export can no longer be downloaded.
+func (e DataExport) IsExpired(now time.Time) bool {
+	return !now.Before(e.ExpiresAt)
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"golang.org/x/text/unicode/norm"
+	"net/mail"
+	"strings"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// MaxEmailLength is the longest email address permitted by RFC 5321.
+const MaxEmailLength = 254
+
+// Email is a normalized email address. The zero value is no address, which is allowed for users.
+//
+// Addresses are trimmed, converted to Unicode NFC and lowercased.
+type Email struct {
+	value string
+}
+
+// NewEmail normalizes an email address and checks that it is a single bare address.
+//
+// Parameters:
+//   - raw: The address as entered by the user
+//
+// Returns:
+//   - Email: The normalized address
+//   - error: errorx.ErrInvalidEmail wrapped with the violated rule
+func NewEmail(raw string) (Email, error) {
+	email := strings.ToLower(norm.NFC.String(strings.TrimSpace(raw)))
+	if len(email) > MaxEmailLength {
+		return Email{}, errorx.ErrInvalidEmail.Detailf("must not exceed %d characters", MaxEmailLength)
+	}
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email || address.Name != "" {
+		return Email{}, errorx.ErrInvalidEmail.Detailf("must be a bare address like name@example.com")
+	}
+	return Email{email}, nil
+}
+
+// RestoreEmail wraps an email address read from storage, which was normalized when it was stored.
+// It is meant for persistence adapters only.
+func RestoreEmail(stored string) Email {
+	return Email{stored}
+}
+
+// String returns the normalized address, or "" if there is none.
+func (e Email) String() string {
+	return e.value
+}
+
+// IsZero reports whether there is no address.
+func (e Email) IsZero() bool {
+	return e.value == ""
+}
+
+// Domain returns the part of the address after the @, or "" if there is no address.
+func (e Email) Domain() string {
+	_, domain, _ := strings.Cut(e.value, "@")
+	return domain
+}
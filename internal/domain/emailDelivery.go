@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"time"
+)
+
+// EmailMessage is a rendered email ready to be handed to an email provider.
+type EmailMessage struct {
+	// From is the sender, e.g. "Example <no-reply@example.com>".
+	From string
+	// To is the address of the recipient.
+	To string
+	// ToName is the display name of the recipient, none if empty.
+	ToName  string
+	Subject string
+	// Text is the plain text body.
+	Text string
+	// HTML is the HTML body.
+	HTML string
+}
+
+// EmailFeedbackType is the kind of delivery feedback an email provider reports about an address.
+type EmailFeedbackType string
+
+// Email feedback types.
+const (
+	// EmailFeedbackHardBounce reports an address that does not exist or permanently rejects email.
+	EmailFeedbackHardBounce EmailFeedbackType = "hard_bounce"
+	// EmailFeedbackSoftBounce reports a temporary failure, e.g. a full mailbox.
+	EmailFeedbackSoftBounce EmailFeedbackType = "soft_bounce"
+	// EmailFeedbackComplaint reports a recipient marking an email as spam.
+	EmailFeedbackComplaint EmailFeedbackType = "complaint"
+)
+
+// Suppresses reports whether feedback of the type stops further emails to the address.
+// Soft bounces do not, the next email may well be delivered.
+func (t EmailFeedbackType) Suppresses() bool {
+	return t == EmailFeedbackHardBounce || t == EmailFeedbackComplaint
+}
+
+// EmailFeedback is a bounce or complaint an email provider reported about an address.
+type EmailFeedback struct {
+	Address  string
+	Type     EmailFeedbackType
+	Provider string
+	// Detail is the explanation of the provider, e.g. the SMTP diagnostic of a bounce.
+	Detail string
+	At     time.Time
+}
+
+// EmailSuppression is an address no notification emails are sent to anymore, because it bounced
+// permanently or its owner complained. Suppressions are not scoped to a tenant, a mailbox that does
+// not exist does not exist for any tenant.
+type EmailSuppression struct {
+	// Address is the normalized address, see NewEmail.
+	Address  string            `classification:"pii"`
+	Reason   EmailFeedbackType `classification:"operational"`
+	Provider string            `classification:"operational"`
+	// Detail is the diagnostic of the provider, which may quote the address.
+	Detail string `classification:"pii"`
+	// CreatedAt is when the address was suppressed, UpdatedAt when feedback was last received for it.
+	CreatedAt time.Time `classification:"operational"`
+	UpdatedAt time.Time `classification:"operational"`
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// ErasureAction is what happened to the records of a kind when the data of a user was erased.
+type ErasureAction string
+
+// Erasure actions.
+const (
+	// ErasureDeleted marks records that were removed.
+	ErasureDeleted ErasureAction = "deleted"
+	// ErasurePseudonymized marks records that are kept, e.g. for the integrity of the audit trail,
+	// but no longer identify the user.
+	ErasurePseudonymized ErasureAction = "pseudonymized"
+)
+
+// ErasedRecords reports how many records of a kind were erased.
+type ErasedRecords struct {
+	Record string
+	Action ErasureAction
+	Count  int64
+}
+
+// ErasureCertificate documents that the personal data of a user was erased on request under the
+// right to erasure. It holds no personal data itself: the user is referred to by id and by the
+// pseudonym that replaced the username in the records that were kept.
+type ErasureCertificate struct {
+	ID                  string
+	UserID              string
+	TenantID            string
+	Pseudonym           string
+	RequestedBy         string
+	LegalHoldOverridden bool
+	Records             []ErasedRecords
+	ErasedAt            time.Time
+}
+
+// CheckErasable verifies that the data of the user may be erased.
+//
+// Parameters:
+//   - overrideLegalHold: Whether a legal hold is overridden
+//
+// Returns:
+//   - error: errorx.ErrLegalHold if the user is under legal hold and the hold is not overridden
+func (u User) CheckErasable(overrideLegalHold bool) error {
+	if u.LegalHold && !overrideLegalHold {
+		return errorx.ErrLegalHold
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+// Package errorx defines the typed errors of the domain.
+//
+// Every error carries a stable, machine-readable Code that adapters map onto their protocol,
+// e.g. an HTTP status or a gRPC status code, and a human-readable message. Structured errors
+// derived from a sentinel with Detailf add a detail explaining which rule was violated and
+// still match the sentinel with errors.Is.
+package errorx
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Code is a stable, machine-readable error code clients can branch on.
+type Code string
+
+const (
+	CodeUserNotFound               Code = "USER_NOT_FOUND"
+	CodeInvalidCredentials         Code = "INVALID_CREDENTIALS"
+	CodeAccountLocked              Code = "ACCOUNT_LOCKED"
+	CodeUsernameTaken              Code = "USERNAME_TAKEN"
+	CodeEmailTaken                 Code = "EMAIL_TAKEN"
+	CodeAccountDisabled            Code = "ACCOUNT_DISABLED"
+	CodeAccountPending             Code = "ACCOUNT_PENDING"
+	CodeInvalidStatusTransition    Code = "INVALID_STATUS_TRANSITION"
+	CodeWeakPassword               Code = "WEAK_PASSWORD"
+	CodeUnknownRole                Code = "UNKNOWN_ROLE"
+	CodeRoleNotFound               Code = "ROLE_NOT_FOUND"
+	CodeBuiltInRole                Code = "BUILT_IN_ROLE"
+	CodeGroupNotFound              Code = "GROUP_NOT_FOUND"
+	CodePolicyNotFound             Code = "POLICY_NOT_FOUND"
+	CodeInvalidPolicy              Code = "INVALID_POLICY"
+	CodeInvalidProfile             Code = "INVALID_PROFILE"
+	CodeInvalidUsername            Code = "INVALID_USERNAME"
+	CodeInvalidEmail               Code = "INVALID_EMAIL"
+	CodeInvalidPasswordHash        Code = "INVALID_PASSWORD_HASH"
+	CodeWebhookNotFound            Code = "WEBHOOK_NOT_FOUND"
+	CodeTenantNotFound             Code = "TENANT_NOT_FOUND"
+	CodeInvalidTenant              Code = "INVALID_TENANT"
+	CodeMfaRequired                Code = "MFA_REQUIRED"
+	CodeLoginMethodNotAllowed      Code = "LOGIN_METHOD_NOT_ALLOWED"
+	CodeSessionNotFound            Code = "SESSION_NOT_FOUND"
+	CodeSessionNotActive           Code = "SESSION_NOT_ACTIVE"
+	CodeSessionLimitReached        Code = "SESSION_LIMIT_REACHED"
+	CodeLoginBlocked               Code = "LOGIN_BLOCKED"
+	CodeConsentRequired            Code = "CONSENT_REQUIRED"
+	CodeInvalidConsent             Code = "INVALID_CONSENT"
+	CodeInvalidToken               Code = "INVALID_TOKEN"
+	CodeInvalidRefreshToken        Code = "INVALID_REFRESH_TOKEN"
+	CodeRefreshTokenReused         Code = "REFRESH_TOKEN_REUSED"
+	CodeLastAdmin                  Code = "LAST_ADMIN"
+	CodeRoleRequired               Code = "ROLE_REQUIRED"
+	CodeDataExportNotFound         Code = "DATA_EXPORT_NOT_FOUND"
+	CodeDataExportNotReady         Code = "DATA_EXPORT_NOT_READY"
+	CodeInvalidDownloadLink        Code = "INVALID_DOWNLOAD_LINK"
+	CodeLegalHold                  Code = "LEGAL_HOLD"
+	CodeErasureCertificateNotFound Code = "ERASURE_CERTIFICATE_NOT_FOUND"
+	CodeInvalidLock                Code = "INVALID_LOCK"
+	CodeInvalidUserSpec            Code = "INVALID_USER_SPEC"
+	CodeInvalidStatsPeriod         Code = "INVALID_STATS_PERIOD"
+	CodePasswordChangeRequired     Code = "PASSWORD_CHANGE_REQUIRED"
+	CodeInvalidPasswordChangeToken Code = "INVALID_PASSWORD_CHANGE_TOKEN"
+	CodeInvalidMerge               Code = "INVALID_MERGE"
+	CodeMfaAlreadyEnabled          Code = "MFA_ALREADY_ENABLED"
+	CodeMfaNotEnrolled             Code = "MFA_NOT_ENROLLED"
+	CodeInvalidMfaCode             Code = "INVALID_MFA_CODE"
+	CodeUnsupportedMfaMethod       Code = "UNSUPPORTED_MFA_METHOD"
+	CodeMfaCodeRequired            Code = "MFA_CODE_REQUIRED"
+	CodeRegistrationRejected       Code = "REGISTRATION_REJECTED"
+	CodeDependencyUnavailable      Code = "DEPENDENCY_UNAVAILABLE"
+	CodeOverloaded                 Code = "OVERLOADED"
+	CodeTooManyFailedLogins        Code = "TOO_MANY_FAILED_LOGINS"
+	CodeEmailSuppressionNotFound   Code = "EMAIL_SUPPRESSION_NOT_FOUND"
+	CodeInvalidEmailFeedback       Code = "INVALID_EMAIL_FEEDBACK"
+	CodeSMSLimitExceeded           Code = "SMS_LIMIT_EXCEEDED"
+)
+
+var (
+	// ErrUserNotFound is returned when no user matches the given identity.
+	ErrUserNotFound = New(CodeUserNotFound, "user not found")
+	// ErrInvalidCredentials is returned when authentication fails.
+	// It deliberately does not reveal whether the username or the password was wrong.
+	ErrInvalidCredentials = New(CodeInvalidCredentials, "invalid username or password")
+	// ErrAccountLocked is returned when a locked account tries to authenticate.
+	ErrAccountLocked = New(CodeAccountLocked, "account locked")
+	// ErrUsernameTaken is returned when registering a username that is already in use.
+	ErrUsernameTaken = New(CodeUsernameTaken, "username already taken")
+	// ErrEmailTaken is returned when registering an email address that reaches the mailbox of another account.
+	ErrEmailTaken = New(CodeEmailTaken, "email already registered")
+	// ErrAccountDisabled is returned when a disabled account tries to authenticate.
+	ErrAccountDisabled = New(CodeAccountDisabled, "account disabled")
+	// ErrAccountPending is returned when an account that has not been activated yet tries to authenticate.
+	ErrAccountPending = New(CodeAccountPending, "account not activated")
+	// ErrInvalidStatusTransition is returned when a status change is not allowed by the account lifecycle.
+	ErrInvalidStatusTransition = New(CodeInvalidStatusTransition, "invalid account status transition")
+	// ErrWeakPassword is returned when a new password does not satisfy the password rules.
+	ErrWeakPassword = New(CodeWeakPassword, "password too weak")
+	// ErrUnknownRole is returned when a role that does not exist is assigned.
+	ErrUnknownRole = New(CodeUnknownRole, "unknown role")
+	// ErrRoleNotFound is returned when no role definition matches the given name.
+	ErrRoleNotFound = New(CodeRoleNotFound, "role not found")
+	// ErrBuiltInRole is returned when a built-in role is deleted or the ADMIN role is changed.
+	ErrBuiltInRole = New(CodeBuiltInRole, "built-in role cannot be changed")
+	// ErrGroupNotFound is returned when no group matches the given name.
+	ErrGroupNotFound = New(CodeGroupNotFound, "group not found")
+	// ErrPolicyNotFound is returned when no access policy matches the given id.
+	ErrPolicyNotFound = New(CodePolicyNotFound, "policy not found")
+	// ErrInvalidPolicy is returned when an access policy is malformed.
+	ErrInvalidPolicy = New(CodeInvalidPolicy, "invalid policy")
+	// ErrInvalidProfile is returned when a profile update violates the profile rules.
+	ErrInvalidProfile = New(CodeInvalidProfile, "invalid profile")
+	// ErrInvalidUsername is returned when a username violates the username rules.
+	ErrInvalidUsername = New(CodeInvalidUsername, "invalid username")
+	// ErrInvalidEmail is returned when an email address is malformed.
+	ErrInvalidEmail = New(CodeInvalidEmail, "invalid email address")
+	// ErrInvalidPasswordHash is returned when a value that is not a supported password hash is used as password hash.
+	ErrInvalidPasswordHash = New(CodeInvalidPasswordHash, "invalid password hash")
+	// ErrWebhookNotFound is returned when no webhook subscription matches the given id.
+	ErrWebhookNotFound = New(CodeWebhookNotFound, "webhook not found")
+	// ErrTenantNotFound is returned when no tenant matches the given id.
+	ErrTenantNotFound = New(CodeTenantNotFound, "tenant not found")
+	// ErrInvalidTenant is returned when tenant settings are out of bounds.
+	ErrInvalidTenant = New(CodeInvalidTenant, "invalid tenant")
+	// ErrMfaRequired is returned when an account without multi-factor authentication logs in to a tenant requiring it.
+	ErrMfaRequired = New(CodeMfaRequired, "multi-factor authentication required")
+	// ErrLoginMethodNotAllowed is returned when a tenant does not allow the login method used.
+	ErrLoginMethodNotAllowed = New(CodeLoginMethodNotAllowed, "login method not allowed")
+	// ErrSessionNotFound is returned when no session matches the given id.
+	ErrSessionNotFound = New(CodeSessionNotFound, "session not found")
+	// ErrSessionNotActive is returned when a revoked or expired session is used or revoked again.
+	ErrSessionNotActive = New(CodeSessionNotActive, "session not active")
+	// ErrSessionLimitReached is returned when a login would exceed the number of concurrent sessions allowed.
+	ErrSessionLimitReached = New(CodeSessionLimitReached, "too many active sessions")
+	// ErrLoginBlocked is returned when the risk assessment of a login with correct credentials blocks it.
+	ErrLoginBlocked = New(CodeLoginBlocked, "login blocked due to unusual activity")
+	// ErrConsentRequired is returned when a registration or login does not accept the current terms or privacy policy.
+	ErrConsentRequired = New(CodeConsentRequired, "consent required")
+	// ErrInvalidConsent is returned when a consent or a document version to publish is malformed or outdated.
+	ErrInvalidConsent = New(CodeInvalidConsent, "invalid consent")
+	// ErrInvalidToken is returned when an access token is malformed, forged or expired.
+	ErrInvalidToken = New(CodeInvalidToken, "invalid token")
+	// ErrInvalidRefreshToken is returned when a refresh token is malformed, unknown or its session has ended.
+	ErrInvalidRefreshToken = New(CodeInvalidRefreshToken, "invalid refresh token")
+	// ErrRefreshTokenReused is returned when a refresh token that has already been rotated is presented again.
+	ErrRefreshTokenReused = New(CodeRefreshTokenReused, "refresh token reused")
+	// ErrLastAdmin is returned when a role change would leave no active user with the ADMIN role.
+	ErrLastAdmin = New(CodeLastAdmin, "cannot remove the last administrator")
+	// ErrRoleRequired is returned when revoking the only role of a user.
+	ErrRoleRequired = New(CodeRoleRequired, "a user needs at least one role")
+	// ErrDataExportNotFound is returned when no data export of the user matches the given id or the export has expired.
+	ErrDataExportNotFound = New(CodeDataExportNotFound, "data export not found")
+	// ErrDataExportNotReady is returned when a data export is downloaded before its archive has been generated.
+	ErrDataExportNotReady = New(CodeDataExportNotReady, "data export not ready")
+	// ErrInvalidDownloadLink is returned when a download link is malformed, forged or expired.
+	ErrInvalidDownloadLink = New(CodeInvalidDownloadLink, "invalid download link")
+	// ErrLegalHold is returned when erasing the data of a user under legal hold.
+	ErrLegalHold = New(CodeLegalHold, "account under legal hold")
+	// ErrErasureCertificateNotFound is returned when no erasure certificate matches the given id.
+	ErrErasureCertificateNotFound = New(CodeErasureCertificateNotFound, "erasure certificate not found")
+	// ErrInvalidLock is returned when an account is locked with an unknown reason or an expiry in the past.
+	ErrInvalidLock = New(CodeInvalidLock, "invalid lock")
+	// ErrInvalidUserSpec is returned when an account to provision has neither or both a temporary password and an invitation.
+	ErrInvalidUserSpec = New(CodeInvalidUserSpec, "invalid user spec")
+	// ErrInvalidStatsPeriod is returned when statistics are requested for an empty or too long period.
+	ErrInvalidStatsPeriod = New(CodeInvalidStatsPeriod, "invalid statistics period")
+	// ErrPasswordChangeRequired is returned when a user with a temporary password logs in where the password cannot be changed.
+	ErrPasswordChangeRequired = New(CodePasswordChangeRequired, "password change required")
+	// ErrInvalidPasswordChangeToken is returned when a password change token is malformed, expired or has been used.
+	ErrInvalidPasswordChangeToken = New(CodeInvalidPasswordChangeToken, "invalid password change token")
+	// ErrInvalidMerge is returned when an account is merged into itself or into an account of another tenant.
+	ErrInvalidMerge = New(CodeInvalidMerge, "invalid account merge")
+	// ErrMfaAlreadyEnabled is returned when enrolling a second factor of a method that is enabled already.
+	ErrMfaAlreadyEnabled = New(CodeMfaAlreadyEnabled, "multi-factor authentication already enabled")
+	// ErrMfaNotEnrolled is returned when confirming or removing a second factor the user has not enrolled.
+	ErrMfaNotEnrolled = New(CodeMfaNotEnrolled, "multi-factor authentication not enrolled")
+	// ErrInvalidMfaCode is returned when a one-time code does not match the second factor.
+	ErrInvalidMfaCode = New(CodeInvalidMfaCode, "invalid one-time code")
+	// ErrUnsupportedMfaMethod is returned when a second factor method is not supported.
+	ErrUnsupportedMfaMethod = New(CodeUnsupportedMfaMethod, "unsupported multi-factor authentication method")
+	// ErrMfaCodeRequired is returned when an account with multi-factor authentication logs in without a one-time code.
+	ErrMfaCodeRequired = New(CodeMfaCodeRequired, "one-time code required")
+	// ErrRegistrationRejected is returned when a registration interceptor rejects a registration.
+	ErrRegistrationRejected = New(CodeRegistrationRejected, "registration rejected")
+	// ErrDependencyUnavailable is returned without calling a dependency, such as the database or an email
+	// provider, while its circuit breaker is open after repeated failures.
+	ErrDependencyUnavailable = New(CodeDependencyUnavailable, "dependency unavailable")
+	// ErrOverloaded is returned when a CPU-bound operation such as password hashing is shed because
+	// too many of them are running or waiting.
+	ErrOverloaded = New(CodeOverloaded, "service overloaded")
+	// ErrTooManyFailedLogins is returned without checking the password while a username is locked
+	// out after repeated failed logins.
+	ErrTooManyFailedLogins = New(CodeTooManyFailedLogins, "too many failed logins")
+	// ErrEmailSuppressionNotFound is returned when an address to lift the suppression of is not suppressed.
+	ErrEmailSuppressionNotFound = New(CodeEmailSuppressionNotFound, "email suppression not found")
+	// ErrInvalidEmailFeedback is returned when a delivery feedback callback of an email provider is
+	// unsigned, forged, malformed or from a provider that is not configured.
+	ErrInvalidEmailFeedback = New(CodeInvalidEmailFeedback, "invalid email feedback")
+	// ErrSMSLimitExceeded is returned without sending when an SMS goes to a country that is not allowed,
+	// exceeds the rate limit of its country or the budget for SMS is spent.
+	ErrSMSLimitExceeded = New(CodeSMSLimitExceeded, "SMS limit exceeded")
+)
+
+// Error is a domain error with a machine-readable code.
+type Error struct {
+	Code    Code
+	Message string
+	// Detail explains the specific occurrence, e.g. which rule a value violated. It is empty for sentinels.
+	Detail string
+}
+
+// New creates a sentinel error.
+//
+// Parameters:
+//   - code: The machine-readable code
+//   - message: The human-readable message
+//
+// Returns:
+//   - *Error: The new error
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Error returns the message, followed by the detail if there is one.
+func (e *Error) Error() string {
+	if e.Detail == "" {
+		return e.Message
+	}
+	return e.Message + ": " + e.Detail
+}
+
+// Is reports whether target is an Error with the same code, so structured errors match their sentinel.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Detailf derives a structured error with the same code and message and a formatted detail.
+//
+// Parameters:
+//   - format: A fmt format string for the detail
+//   - args: The format arguments
+//
+// Returns:
+//   - *Error: The structured error, matching e with errors.Is
+func (e *Error) Detailf(format string, args ...any) *Error {
+	return &Error{Code: e.Code, Message: e.Message, Detail: fmt.Sprintf(format, args...)}
+}
+
+// As returns the first domain error in the chain of err.
+//
+// Parameters:
+//   - err: The error to inspect, possibly wrapped with fmt.Errorf and %w
+//
+// Returns:
+//   - *Error: The domain error
+//   - bool: false if err does not wrap a domain error
+func As(err error) (*Error, bool) {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr, true
+	}
+	return nil, false
+}
@@ -0,0 +1,74 @@
+package events
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Category groups events by subject, so consumers such as SIEM exporters can select the events they need.
+type Category string
+
+// Event categories.
+const (
+	// CategoryAuthentication covers logins, failed logins and sessions.
+	CategoryAuthentication Category = "authentication"
+	// CategoryCredential covers passwords, multi-factor authentication and account locks.
+	CategoryCredential Category = "credential"
+	// CategoryAuthorization covers role changes.
+	CategoryAuthorization Category = "authorization"
+	// CategoryAccount covers the lifecycle of accounts: registration, invitations, renames, status
+	// changes, merges, profile changes and deletion.
+	CategoryAccount Category = "account"
+	// CategoryPrivacy covers consents, data exports and erasures.
+	CategoryPrivacy Category = "privacy"
+	// CategoryNotification covers notifications requested for delivery by a subscribed backend.
+	CategoryNotification Category = "notification"
+)
+
+// categories lists the known categories.
+var categories = []Category{CategoryAuthentication, CategoryCredential, CategoryAuthorization, CategoryAccount, CategoryPrivacy, CategoryNotification}
+
+// CategoryOf returns the category of an event.
+func CategoryOf(event Event) Category {
+	switch event.(type) {
+	case UserLoggedIn, LoginFailed, SessionEvicted, SessionRevoked:
+		return CategoryAuthentication
+	case PasswordChanged, PasswordHashUpgraded, MfaEnabled, MfaDisabled, AccountLocked, AccountUnlocked:
+		return CategoryCredential
+	case UserRoleChanged:
+		return CategoryAuthorization
+	case ConsentAccepted, DataExportRequested, UserErased:
+		return CategoryPrivacy
+	case NotificationRequested:
+		return CategoryNotification
+	default:
+		return CategoryAccount
+	}
+}
+
+// ParseCategories converts a comma-separated list of category names.
+//
+// Parameters:
+//   - value: Names like "authentication,credential", or "*" for all categories
+//
+// Returns:
+//   - []Category: The categories, all of them for "*"
+//   - error: An error if a name is unknown
+func ParseCategories(value string) ([]Category, error) {
+	if strings.TrimSpace(value) == "*" {
+		return slices.Clone(categories), nil
+	}
+	var parsed []Category
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !slices.Contains(categories, Category(name)) {
+			return nil, fmt.Errorf("unknown event category %q", name)
+		}
+		parsed = append(parsed, Category(name))
+	}
+	return parsed, nil
+}
@@ -0,0 +1,370 @@
+// Package events defines the domain events emitted by the application's use cases.
+package events
+
+import (
+	"time"
+)
+
+// Event is a fact that happened in the domain.
+type Event interface {
+	// Name returns the stable, dot-separated event name, e.g. "user.registered".
+	Name() string
+	// OccurredAt returns the time the event happened.
+	OccurredAt() time.Time
+}
+
+// SchemaVersion is the version of the serialized form of the events, their fields encoded as JSON.
+// It is increased whenever a field is renamed, removed or changes its meaning, so consumers outside
+// of the service can tell the payloads apart. Adding a field does not change the version.
+const SchemaVersion = 1
+
+// UserRegistered is emitted after a new user has been persisted.
+type UserRegistered struct {
+	UserID   string
+	TenantID string
+	Username string
+	Roles    []string
+	At       time.Time
+}
+
+// Name returns "user.registered".
+func (e UserRegistered) Name() string { return "user.registered" }
+
+// OccurredAt returns the registration time.
+func (e UserRegistered) OccurredAt() time.Time { return e.At }
+
+// UserInvited is emitted after an administrator provisioned a pending account without password.
+// Subscribers are expected to deliver the invitation to the email address.
+type UserInvited struct {
+	UserID   string
+	TenantID string
+	Username string
+	Email    string
+	Roles    []string
+	At       time.Time
+}
+
+// Name returns "user.invited".
+func (e UserInvited) Name() string { return "user.invited" }
+
+// OccurredAt returns the time the account was provisioned.
+func (e UserInvited) OccurredAt() time.Time { return e.At }
+
+// UserLoggedIn is emitted after a user has successfully authenticated. NewDevice is set if the
+// risk assessment found the user has not logged in with this device before.
+type UserLoggedIn struct {
+	Username  string
+	NewDevice bool
+	At        time.Time
+}
+
+// Name returns "user.logged_in".
+func (e UserLoggedIn) Name() string { return "user.logged_in" }
+
+// OccurredAt returns the login time.
+func (e UserLoggedIn) OccurredAt() time.Time { return e.At }
+
+// UserRoleChanged is emitted after an administrator changed the roles of a user. Previous are
+// the roles before the change, Roles the new ones.
+type UserRoleChanged struct {
+	Username string
+	Previous []string
+	Roles    []string
+	At       time.Time
+}
+
+// Name returns "user.role_changed".
+func (e UserRoleChanged) Name() string { return "user.role_changed" }
+
+// OccurredAt returns the time of the change.
+func (e UserRoleChanged) OccurredAt() time.Time { return e.At }
+
+// UserStatusChanged is emitted after the account status of a user changed, e.g. because the
+// user was disabled or enabled. From is the previous status, Status the new one.
+type UserStatusChanged struct {
+	Username string
+	From     string
+	Status   string
+	At       time.Time
+}
+
+// Name returns "user.status_changed".
+func (e UserStatusChanged) Name() string { return "user.status_changed" }
+
+// OccurredAt returns the time of the change.
+func (e UserStatusChanged) OccurredAt() time.Time { return e.At }
+
+// UserRenamed is emitted after an administrator changed the username of a user.
+// Username is the previous username, NewUsername the new one.
+type UserRenamed struct {
+	UserID      string
+	Username    string
+	NewUsername string
+	At          time.Time
+}
+
+// Name returns "user.renamed".
+func (e UserRenamed) Name() string { return "user.renamed" }
+
+// OccurredAt returns the time of the change.
+func (e UserRenamed) OccurredAt() time.Time { return e.At }
+
+// UserDeleted is emitted after a user was soft-deleted.
+type UserDeleted struct {
+	Username string
+	At       time.Time
+}
+
+// Name returns "user.deleted".
+func (e UserDeleted) Name() string { return "user.deleted" }
+
+// OccurredAt returns the deletion time.
+func (e UserDeleted) OccurredAt() time.Time { return e.At }
+
+// AccountsMerged is emitted after an administrator merged a duplicate account into a primary
+// account. DuplicateUsername is the username the duplicate had, Disposition whether it was
+// released or kept as an alias of the primary account.
+type AccountsMerged struct {
+	PrimaryID         string
+	Username          string
+	DuplicateID       string
+	DuplicateUsername string
+	Disposition       string
+	MergedBy          string
+	At                time.Time
+}
+
+// Name returns "user.merged".
+func (e AccountsMerged) Name() string { return "user.merged" }
+
+// OccurredAt returns the time of the merge.
+func (e AccountsMerged) OccurredAt() time.Time { return e.At }
+
+// MfaEnabled is emitted after a user confirmed a second factor, enabling multi-factor authentication.
+type MfaEnabled struct {
+	Username string
+	Method   string
+	At       time.Time
+}
+
+// Name returns "user.mfa_enabled".
+func (e MfaEnabled) Name() string { return "user.mfa_enabled" }
+
+// OccurredAt returns the time of the confirmation.
+func (e MfaEnabled) OccurredAt() time.Time { return e.At }
+
+// MfaDisabled is emitted after a user removed a second factor. Remaining is the number of confirmed
+// factors the user still has; multi-factor authentication is disabled once it drops to zero.
+type MfaDisabled struct {
+	Username  string
+	Method    string
+	Remaining int
+	At        time.Time
+}
+
+// Name returns "user.mfa_disabled".
+func (e MfaDisabled) Name() string { return "user.mfa_disabled" }
+
+// OccurredAt returns the time of the removal.
+func (e MfaDisabled) OccurredAt() time.Time { return e.At }
+
+// Reasons of a LoginFailed event.
+const (
+	LoginFailedInvalidCredentials     = "invalid_credentials"
+	LoginFailedAccountDisabled        = "account_disabled"
+	LoginFailedAccountLocked          = "account_locked"
+	LoginFailedAccountPending         = "account_pending"
+	LoginFailedMfaRequired            = "mfa_required"
+	LoginFailedMfaCodeRequired        = "mfa_code_required"
+	LoginFailedInvalidMfaCode         = "invalid_mfa_code"
+	LoginFailedSessionLimit           = "session_limit"
+	LoginFailedRiskBlocked            = "risk_blocked"
+	LoginFailedConsentRequired        = "consent_required"
+	LoginFailedPasswordChangeRequired = "password_change_required"
+	LoginFailedLockedOut              = "locked_out"
+)
+
+// LoginFailed is emitted after an authentication attempt was rejected.
+// The username is the one supplied by the client and does not necessarily exist.
+type LoginFailed struct {
+	Username string
+	Reason   string
+	At       time.Time
+}
+
+// Name returns "user.login_failed".
+func (e LoginFailed) Name() string { return "user.login_failed" }
+
+// OccurredAt returns the time of the attempt.
+func (e LoginFailed) OccurredAt() time.Time { return e.At }
+
+// PasswordChanged is emitted after the password of a user was replaced.
+type PasswordChanged struct {
+	Username string
+	At       time.Time
+}
+
+// Name returns "user.password_changed".
+func (e PasswordChanged) Name() string { return "user.password_changed" }
+
+// OccurredAt returns the time of the change.
+func (e PasswordChanged) OccurredAt() time.Time { return e.At }
+
+// PasswordHashUpgraded is emitted after the password hash of a user was replaced at login with a
+// hash of the configured algorithm. The password itself did not change.
+type PasswordHashUpgraded struct {
+	Username  string
+	Algorithm string
+	At        time.Time
+}
+
+// Name returns "user.password_hash_upgraded".
+func (e PasswordHashUpgraded) Name() string { return "user.password_hash_upgraded" }
+
+// OccurredAt returns the time of the upgrade.
+func (e PasswordHashUpgraded) OccurredAt() time.Time { return e.At }
+
+// AccountLockedFailedLogins is the reason of an AccountLocked event for a lock applied automatically
+// after repeated failed logins. Locks applied by administrators carry a domain.LockReason.
+const AccountLockedFailedLogins = "FAILED_LOGINS"
+
+// AccountLocked is emitted after an account was locked, e.g. because of repeated failed logins.
+// A zero Until means the account stays locked until it is unlocked explicitly. LockedBy is the
+// administrator who locked the account, empty for locks applied automatically.
+type AccountLocked struct {
+	Username string
+	Reason   string
+	LockedBy string
+	Until    time.Time
+	At       time.Time
+}
+
+// Name returns "user.account_locked".
+func (e AccountLocked) Name() string { return "user.account_locked" }
+
+// OccurredAt returns the time the lock was applied.
+func (e AccountLocked) OccurredAt() time.Time { return e.At }
+
+// Reasons of an AccountUnlocked event.
+const (
+	AccountUnlockedByAdmin = "unlocked_by_admin"
+	AccountUnlockedExpired = "expired"
+)
+
+// AccountUnlocked is emitted after the lock of an account was lifted, by an administrator or
+// because it expired.
+type AccountUnlocked struct {
+	Username string
+	Reason   string
+	At       time.Time
+}
+
+// Name returns "user.account_unlocked".
+func (e AccountUnlocked) Name() string { return "user.account_unlocked" }
+
+// OccurredAt returns the time the lock was lifted.
+func (e AccountUnlocked) OccurredAt() time.Time { return e.At }
+
+// NotificationRequested is emitted for notifications a user wants to receive through the webhook
+// channel, so a subscribed backend can deliver them, e.g. as push messages. Topic is a
+// domain.NotificationTopic, Details the values for rendering the message.
+type NotificationRequested struct {
+	Username string
+	Topic    string
+	Details  map[string]string
+	At       time.Time
+}
+
+// Name returns "user.notification_requested".
+func (e NotificationRequested) Name() string { return "user.notification_requested" }
+
+// OccurredAt returns the time of the activity the user is notified about.
+func (e NotificationRequested) OccurredAt() time.Time { return e.At }
+
+// ProfileUpdated is emitted after a user changed their profile.
+type ProfileUpdated struct {
+	Username string
+	At       time.Time
+}
+
+// Name returns "user.profile_updated".
+func (e ProfileUpdated) Name() string { return "user.profile_updated" }
+
+// OccurredAt returns the time of the change.
+func (e ProfileUpdated) OccurredAt() time.Time { return e.At }
+
+// SessionEvicted is emitted after a session was revoked to make room for a new login, because the
+// user had reached the limit of concurrent sessions. It warns the user of a login on another device.
+type SessionEvicted struct {
+	Username  string
+	SessionID string
+	UserAgent string
+	IPAddress string
+	At        time.Time
+}
+
+// Name returns "user.session_evicted".
+func (e SessionEvicted) Name() string { return "user.session_evicted" }
+
+// OccurredAt returns the time of the eviction.
+func (e SessionEvicted) OccurredAt() time.Time { return e.At }
+
+// SessionRevoked is emitted after a user ended one of their sessions from another session, an
+// administrator ended a session of a user, or a session was ended because its refresh token was reused.
+type SessionRevoked struct {
+	Username  string
+	SessionID string
+	Reason    string
+	At        time.Time
+}
+
+// Name returns "user.session_revoked".
+func (e SessionRevoked) Name() string { return "user.session_revoked" }
+
+// OccurredAt returns the time of the revocation.
+func (e SessionRevoked) OccurredAt() time.Time { return e.At }
+
+// UserErased is emitted after the personal data of a user was erased under the right to erasure.
+// Subscribers holding copies of the user's data are expected to erase them as well. The username
+// is the one the user had before the erasure; the pseudonym replaces it in the kept audit records.
+type UserErased struct {
+	UserID        string
+	Username      string
+	Pseudonym     string
+	CertificateID string
+	At            time.Time
+}
+
+// Name returns "user.erased".
+func (e UserErased) Name() string { return "user.erased" }
+
+// OccurredAt returns the time of the erasure.
+func (e UserErased) OccurredAt() time.Time { return e.At }
+
+// DataExportRequested is emitted after a user requested an export of their data.
+type DataExportRequested struct {
+	Username string
+	ExportID string
+	At       time.Time
+}
+
+// Name returns "user.data_export_requested".
+func (e DataExportRequested) Name() string { return "user.data_export_requested" }
+
+// OccurredAt returns the time of the request.
+func (e DataExportRequested) OccurredAt() time.Time { return e.At }
+
+// ConsentAccepted is emitted after a user accepted a version of the terms or the privacy policy.
+type ConsentAccepted struct {
+	UserID   string
+	Username string
+	Document string
+	Version  string
+	At       time.Time
+}
+
+// Name returns "user.consent_accepted".
+func (e ConsentAccepted) Name() string { return "user.consent_accepted" }
+
+// OccurredAt returns the time of the acceptance.
+func (e ConsentAccepted) OccurredAt() time.Time { return e.At }
@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// FeatureFlag names a capability that can be switched on and off per deployment or per tenant while the service runs.
+type FeatureFlag string
+
+// Feature flags.
+const (
+	// FeatureOpenRegistration lets visitors create accounts themselves. Enabled by default.
+	FeatureOpenRegistration FeatureFlag = "open-registration"
+	// FeatureRequireMFA rejects logins of accounts without multi-factor authentication, in addition
+	// to tenants requiring it in their settings. Disabled by default.
+	FeatureRequireMFA FeatureFlag = "require-mfa"
+	// FeatureLegacyAPI serves the deprecated unversioned routes, if they are mounted. Enabled by default.
+	FeatureLegacyAPI FeatureFlag = "legacy-api"
+)
+
+// featureDefaults are the values of the flags that are not configured.
+var featureDefaults = map[FeatureFlag]bool{
+	FeatureOpenRegistration: true,
+	FeatureRequireMFA:       false,
+	FeatureLegacyAPI:        true,
+}
+
+// Default returns the value of the flag when it is not configured.
+func (f FeatureFlag) Default() bool {
+	return featureDefaults[f]
+}
+
+// KnownFeatureFlags returns every flag, sorted by name.
+func KnownFeatureFlags() []FeatureFlag {
+	return slices.Sorted(maps.Keys(featureDefaults))
+}
+
+// ParseFeatureFlag converts the name of a flag.
+//
+// Parameters:
+//   - name: The name of a known flag, e.g. "open-registration"
+//
+// Returns:
+//   - FeatureFlag: The flag
+//   - error: An error if the name is unknown
+func ParseFeatureFlag(name string) (FeatureFlag, error) {
+	if _, ok := featureDefaults[FeatureFlag(name)]; !ok {
+		return "", fmt.Errorf("unknown feature flag %q", name)
+	}
+	return FeatureFlag(name), nil
+}
+
+// FeatureFlags holds the configured values of feature flags, for the whole deployment and per tenant.
+type FeatureFlags struct {
+	Defaults map[FeatureFlag]bool            `json:"defaults"`
+	Tenants  map[string]map[FeatureFlag]bool `json:"tenants"`
+}
+
+// Lookup returns the configured value of a flag for a tenant: the value of the tenant, else the
+// value for the deployment.
+//
+// Parameters:
+//   - flag: The flag
+//   - tenantID: The id of the tenant, empty for the deployment value
+//
+// Returns:
+//   - bool: The value of the flag
+//   - bool: Whether the flag is configured at all
+func (f FeatureFlags) Lookup(flag FeatureFlag, tenantID string) (bool, bool) {
+	if enabled, ok := f.Tenants[tenantID][flag]; ok {
+		return enabled, true
+	}
+	enabled, ok := f.Defaults[flag]
+	return enabled, ok
+}
+
+// Validate checks that only known flags and well-formed tenant ids are configured.
+//
+// Returns:
+//   - error: An error naming the first unknown flag or malformed tenant id, nil if the flags are valid
+func (f FeatureFlags) Validate() error {
+	for flag := range f.Defaults {
+		if _, err := ParseFeatureFlag(string(flag)); err != nil {
+			return err
+		}
+	}
+	for tenantID, flags := range f.Tenants {
+		if !ValidTenantID(tenantID) {
+			return fmt.Errorf("malformed tenant id %q", tenantID)
+		}
+		for flag := range flags {
+			if _, err := ParseFeatureFlag(string(flag)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ParseFeatureFlags converts a comma-separated list of flag values.
+//
+// Parameters:
+//   - value: Entries like "open-registration=false" for the deployment or "acme:require-mfa=true" for a tenant
+//
+// Returns:
+//   - FeatureFlags: The configured values
+//   - error: An error if an entry is malformed or names an unknown flag
+func ParseFeatureFlags(value string) (FeatureFlags, error) {
+	flags := FeatureFlags{Defaults: map[FeatureFlag]bool{}, Tenants: map[string]map[FeatureFlag]bool{}}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, rawValue, ok := strings.Cut(entry, "=")
+		if !ok {
+			return FeatureFlags{}, fmt.Errorf("feature flag %q lacks a value, expected name=true or tenant:name=false", entry)
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(rawValue))
+		if err != nil {
+			return FeatureFlags{}, fmt.Errorf("feature flag %q has no boolean value", entry)
+		}
+
+		tenantID, name, scoped := strings.Cut(strings.TrimSpace(name), ":")
+		if !scoped {
+			tenantID, name = "", tenantID
+		}
+		flag, err := ParseFeatureFlag(name)
+		if err != nil {
+			return FeatureFlags{}, err
+		}
+		if !scoped {
+			flags.Defaults[flag] = enabled
+			continue
+		}
+		if flags.Tenants[tenantID] == nil {
+			flags.Tenants[tenantID] = map[FeatureFlag]bool{}
+		}
+		flags.Tenants[tenantID][flag] = enabled
+	}
+	return flags, flags.Validate()
+}
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"regexp"
+	"sort"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// groupNamePattern allows lower case letters, digits, dashes and underscores, starting with a letter or digit.
+var groupNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// Group is a named set of users that inherit the roles of the group.
+//
+// Assigning roles to a group instead of to every member keeps the roles of whole teams consistent:
+// the effective roles of a user are the roles assigned to the user plus the roles of all groups
+// the user is a member of.
+//
+// A group belongs to a tenant and only holds users of that tenant, so the administrators of one
+// tenant cannot grant roles to the users of another. Group names are unique within a tenant.
+type Group struct {
+	TenantID  string
+	Name      string
+	Roles     []Role
+	Members   []string
+	UpdatedAt time.Time
+}
+
+// ValidGroupName reports whether the group name is well-formed, e.g. "support-team".
+func ValidGroupName(name string) bool {
+	return groupNamePattern.MatchString(name)
+}
+
+// NewGroup creates a group without roles and members.
+//
+// Parameters:
+//   - tenantID: The id of the tenant the group belongs to
+//   - name: The name of the group
+//   - createdAt: The time of the creation
+//
+// Returns:
+//   - Group: The new group
+func NewGroup(tenantID string, name string, createdAt time.Time) Group {
+	return Group{TenantID: tenantID, Name: name, Roles: []Role{}, Members: []string{}, UpdatedAt: createdAt}
+}
+
+// AssignRoles replaces the roles the group grants to its members. Duplicates are dropped and
+// the order is normalized; an empty list leaves the group without roles.
+//
+// Parameters:
+//   - roles: The new roles, each known to the role registry
+//   - known: The roles known to the application
+//
+// Returns:
+//   - error: errorx.ErrUnknownRole if a role does not exist
+func (g *Group) AssignRoles(roles []Role, known RolePermissions) error {
+	for _, role := range roles {
+		if !known.Exists(role) {
+			return errorx.ErrUnknownRole
+		}
+	}
+	g.Roles = uniqueRoles(roles)
+	return nil
+}
+
+// Admits reports whether the user may become a member of the group, i.e. belongs to its tenant.
+func (g Group) Admits(user User) bool {
+	return user.TenantID == g.TenantID
+}
+
+// HasMember reports whether the user is a member of the group.
+func (g Group) HasMember(userID string) bool {
+	for _, member := range g.Members {
+		if member == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// EffectiveRoles returns the roles of the user together with the roles inherited from the groups,
+// sorted and without duplicates. Groups the user is not a member of are ignored.
+//
+// Parameters:
+//   - user: The user
+//   - groups: The groups to inherit roles from
+//
+// Returns:
+//   - []Role: The effective roles
+func EffectiveRoles(user User, groups []Group) []Role {
+	roles := append([]Role{}, user.Roles...)
+	for _, group := range groups {
+		if group.HasMember(user.ID) {
+			roles = append(roles, group.Roles...)
+		}
+	}
+	return uniqueRoles(roles)
+}
+
+// uniqueRoles returns the roles sorted and without duplicates.
+func uniqueRoles(roles []Role) []Role {
+	seen := map[Role]bool{}
+	unique := make([]Role, 0, len(roles))
+	for _, role := range roles {
+		if !seen[role] {
+			seen[role] = true
+			unique = append(unique, role)
+		}
+	}
+	sort.Slice(unique, func(i, j int) bool { return unique[i] < unique[j] })
+	return unique
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"strings"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// HashedPassword is a password hash in modular crypt format, i.e. "$<algorithm>$..." as produced by
+// bcrypt, argon2id or scrypt. The zero value is no password, which never verifies.
+//
+// The domain does not hash or verify passwords itself; that is the job of the PasswordHasherPort,
+// which recognises the algorithm from the format of the hash.
+type HashedPassword struct {
+	value string
+}
+
+// NewHashedPassword wraps a hash after checking that it is in modular crypt format.
+//
+// Parameters:
+//   - hash: The hash, e.g. the result of PasswordHasherPort.Hash
+//
+// Returns:
+//   - HashedPassword: The wrapped hash
+//   - error: errorx.ErrInvalidPasswordHash if the value is not in modular crypt format
+func NewHashedPassword(hash string) (HashedPassword, error) {
+	if hash == "" {
+		return HashedPassword{}, errorx.ErrInvalidPasswordHash.Detailf("the hash is empty")
+	}
+	if strings.Count(hash, "$") < 3 || !strings.HasPrefix(hash, "$") {
+		return HashedPassword{}, errorx.ErrInvalidPasswordHash.Detailf("the hash is not in modular crypt format")
+	}
+	return HashedPassword{hash}, nil
+}
+
+// RestoreHashedPassword wraps a hash read from storage. It is meant for persistence adapters only.
+func RestoreHashedPassword(stored string) HashedPassword {
+	return HashedPassword{stored}
+}
+
+// String returns the hash. It is never the plain text password.
+func (h HashedPassword) String() string {
+	return h.value
+}
+
+// IsZero reports whether there is no hash.
+func (h HashedPassword) IsZero() bool {
+	return h.value == ""
+}
+
+// Algorithm returns the identifier of the hash format, e.g. "2b" for bcrypt or "argon2id",
+// or an empty string if there is no hash.
+func (h HashedPassword) Algorithm() string {
+	id, _, _ := strings.Cut(strings.TrimPrefix(h.value, "$"), "$")
+	return id
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"time"
+)
+
+// LoginOutcome is the result of an authentication attempt.
+type LoginOutcome string
+
+// Login outcomes.
+const (
+	// LoginOutcomeSuccess marks attempts that started a session.
+	LoginOutcomeSuccess LoginOutcome = "SUCCESS"
+	// LoginOutcomeFailure marks attempts that were rejected, see LoginRecord.Reason.
+	LoginOutcomeFailure LoginOutcome = "FAILURE"
+)
+
+// LoginRecord is the audit record of an authentication attempt, successful or not, together with
+// the device it was made from. Records of failed attempts carry the username as supplied by the
+// client, which does not necessarily belong to an account. TenantID is the tenant the attempt was
+// made for.
+type LoginRecord struct {
+	TenantID  string       `classification:"operational"`
+	Username  Username     `classification:"pii"`
+	Outcome   LoginOutcome `classification:"operational"`
+	Reason    string       `classification:"operational"`
+	IPAddress string       `classification:"pii"`
+	UserAgent string       `classification:"pii"`
+	Country   string       `classification:"pii"`
+	At        time.Time    `classification:"operational"`
+}
+
+// LoginRecordFilter selects login records for a forensic lookup. Zero fields do not restrict the
+// result; at least a username or an IP address should be given to keep lookups selective.
+type LoginRecordFilter struct {
+	Username  Username
+	IPAddress string
+	Outcome   LoginOutcome
+	Since     time.Time
+	Until     time.Time
+	Limit     int64
+}
+
+// Valid reports whether the outcome is a known one.
+func (o LoginOutcome) Valid() bool {
+	return o == LoginOutcomeSuccess || o == LoginOutcomeFailure
+}
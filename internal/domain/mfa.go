@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"slices"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// MfaMethod identifies a second factor users can authenticate with.
+type MfaMethod string
+
+// MfaMethodTotp is a time-based one-time password (RFC 6238) generated by an authenticator app.
+const MfaMethodTotp MfaMethod = "totp"
+
+// mfaMethods lists every supported method.
+var mfaMethods = []MfaMethod{MfaMethodTotp}
+
+// MfaMethods returns every supported method.
+func MfaMethods() []MfaMethod {
+	return slices.Clone(mfaMethods)
+}
+
+// Valid reports whether the method is supported.
+func (m MfaMethod) Valid() bool {
+	return slices.Contains(mfaMethods, m)
+}
+
+// MfaFactor is a second factor of a user. A factor is pending from its enrollment until the user
+// proves with a first code that the authenticator has been set up, and only counts once confirmed.
+type MfaFactor struct {
+	Method      MfaMethod `classification:"operational"`
+	Secret      string    `classification:"credential"`
+	EnrolledAt  time.Time `classification:"operational"`
+	ConfirmedAt time.Time `classification:"operational"`
+}
+
+// MfaEnrollment is what a user needs to set up a pending factor in an authenticator app. It is
+// only handed out once, when the enrollment begins.
+type MfaEnrollment struct {
+	Method          MfaMethod `classification:"operational"`
+	Secret          string    `classification:"credential"`
+	ProvisioningURI string    `classification:"credential"`
+}
+
+// Confirmed reports whether the user has confirmed the factor.
+func (f MfaFactor) Confirmed() bool {
+	return !f.ConfirmedAt.IsZero()
+}
+
+// WithoutSecret returns a copy of the factor without its secret, for handing it out of the core.
+func (f MfaFactor) WithoutSecret() MfaFactor {
+	f.Secret = ""
+	return f
+}
+
+// MfaFactor returns the factor of the user for a method.
+func (u User) MfaFactor(method MfaMethod) (MfaFactor, bool) {
+	for _, factor := range u.MfaFactors {
+		if factor.Method == method {
+			return factor, true
+		}
+	}
+	return MfaFactor{}, false
+}
+
+// EnrollMfa starts the enrollment of a second factor, replacing a pending enrollment of the same method.
+//
+// Parameters:
+//   - method: The method to enroll
+//   - secret: The secret shared with the authenticator
+//   - at: The time of the enrollment
+//
+// Returns:
+//   - error: errorx.ErrMfaAlreadyEnabled if a confirmed factor of the method exists
+func (u *User) EnrollMfa(method MfaMethod, secret string, at time.Time) error {
+	if factor, ok := u.MfaFactor(method); ok && factor.Confirmed() {
+		return errorx.ErrMfaAlreadyEnabled.Detailf("%s is enabled already", method)
+	}
+	u.removeMfaFactor(method)
+	u.MfaFactors = append(u.MfaFactors, MfaFactor{Method: method, Secret: secret, EnrolledAt: at})
+	return nil
+}
+
+// ConfirmMfa confirms a pending factor, which enables multi-factor authentication for the user.
+//
+// Returns:
+//   - error: errorx.ErrMfaNotEnrolled if no factor of the method is pending
+func (u *User) ConfirmMfa(method MfaMethod, at time.Time) error {
+	for i, factor := range u.MfaFactors {
+		if factor.Method == method && !factor.Confirmed() {
+			u.MfaFactors[i].ConfirmedAt = at
+			u.MfaEnabled = true
+			return nil
+		}
+	}
+	return errorx.ErrMfaNotEnrolled.Detailf("no pending %s enrollment", method)
+}
+
+// RemoveMfa removes a confirmed factor. Multi-factor authentication stays enabled as long as
+// another confirmed factor remains.
+//
+// Returns:
+//   - error: errorx.ErrMfaNotEnrolled if the user has no confirmed factor of the method
+func (u *User) RemoveMfa(method MfaMethod) error {
+	if factor, ok := u.MfaFactor(method); !ok || !factor.Confirmed() {
+		return errorx.ErrMfaNotEnrolled.Detailf("%s is not enabled", method)
+	}
+	u.removeMfaFactor(method)
+	u.MfaEnabled = false
+	for _, factor := range u.MfaFactors {
+		if factor.Confirmed() {
+			u.MfaEnabled = true
+		}
+	}
+	return nil
+}
+
+// removeMfaFactor drops the factor of a method, if any.
+func (u *User) removeMfaFactor(method MfaMethod) {
+	factors := u.MfaFactors[:0]
+	for _, factor := range u.MfaFactors {
+		if factor.Method != method {
+			factors = append(factors, factor)
+		}
+	}
+	u.MfaFactors = factors
+}
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"regexp"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// NotificationChannel is a way of reaching a user with a notification.
+type NotificationChannel string
+
+// Notification channels.
+const (
+	// NotificationChannelEmail sends notifications to the email address of the account.
+	NotificationChannelEmail NotificationChannel = "email"
+	// NotificationChannelSMS sends notifications to the phone number of the profile.
+	NotificationChannelSMS NotificationChannel = "sms"
+	// NotificationChannelWebhook hands notifications to the webhook subscribers, e.g. a backend sending push messages.
+	NotificationChannelWebhook NotificationChannel = "webhook"
+)
+
+// Valid reports whether the channel is one of the known channels.
+func (c NotificationChannel) Valid() bool {
+	switch c {
+	case NotificationChannelEmail, NotificationChannelSMS, NotificationChannelWebhook:
+		return true
+	}
+	return false
+}
+
+// NotificationTopic is the kind of account activity a user is notified about.
+type NotificationTopic string
+
+// Notification topics.
+const (
+	NotificationTopicRegistration    NotificationTopic = "registration"
+	NotificationTopicPasswordChanged NotificationTopic = "password_changed"
+	NotificationTopicNewDeviceLogin  NotificationTopic = "new_device_login"
+	NotificationTopicAccountLocked   NotificationTopic = "account_locked"
+)
+
+// Valid reports whether the topic is one of the known topics.
+func (t NotificationTopic) Valid() bool {
+	switch t {
+	case NotificationTopicRegistration, NotificationTopicPasswordChanged, NotificationTopicNewDeviceLogin, NotificationTopicAccountLocked:
+		return true
+	}
+	return false
+}
+
+// DefaultNotificationChannels are the channels used for topics the user has not configured.
+var DefaultNotificationChannels = []NotificationChannel{NotificationChannelEmail}
+
+// phoneNumberPattern accepts phone numbers in E.164 format, e.g. "+41791234567".
+var phoneNumberPattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// NotificationPreferences maps notification topics onto the channels a user wants to be notified
+// on. Topics without entry are sent on DefaultNotificationChannels; an empty list opts out of a topic.
+type NotificationPreferences map[NotificationTopic][]NotificationChannel
+
+// Channels returns the channels notifications of a topic are sent on.
+//
+// Parameters:
+//   - topic: The topic of the notification
+//
+// Returns:
+//   - []NotificationChannel: The channels, empty if the user opted out of the topic
+func (p NotificationPreferences) Channels(topic NotificationTopic) []NotificationChannel {
+	channels, ok := p[topic]
+	if !ok {
+		return DefaultNotificationChannels
+	}
+	return channels
+}
+
+// validate checks that only known topics and channels are configured, each channel at most once per topic.
+func (p NotificationPreferences) validate() error {
+	for topic, channels := range p {
+		if !topic.Valid() {
+			return errorx.ErrInvalidProfile.Detailf("notifications: unknown topic %q", topic)
+		}
+		seen := make(map[NotificationChannel]bool, len(channels))
+		for _, channel := range channels {
+			if !channel.Valid() {
+				return errorx.ErrInvalidProfile.Detailf("notifications: unknown channel %q for topic %q", channel, topic)
+			}
+			if seen[channel] {
+				return errorx.ErrInvalidProfile.Detailf("notifications: channel %q is listed twice for topic %q", channel, topic)
+			}
+			seen[channel] = true
+		}
+	}
+	return nil
+}
+
+// validatePhoneNumber checks that a phone number is empty or in E.164 format.
+func validatePhoneNumber(phoneNumber string) error {
+	if phoneNumber != "" && !phoneNumberPattern.MatchString(phoneNumber) {
+		return errorx.ErrInvalidProfile.Detailf("phoneNumber must be in E.164 format such as \"+41791234567\"")
+	}
+	return nil
+}
+
+// Notification is a message about account activity to be sent to a user on one or more channels.
+// It carries the contact details of the user, so channels do not have to look them up.
+type Notification struct {
+	Topic       NotificationTopic
+	Username    string
+	Email       string
+	PhoneNumber string
+	DisplayName string
+	Locale      string
+	// Details are topic specific values for rendering the message, e.g. the "userAgent" of a new device.
+	Details map[string]string
+	At      time.Time
+}
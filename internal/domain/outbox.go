@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"time"
+)
+
+// OutboxMessage is a domain event recorded for publishing to a message broker. Recording the event
+// before publishing it decouples the use case from the broker: the event survives a broker outage
+// and a restart of the service and is published once the broker is back.
+//
+// Messages are published at least once. The ID stays the same across attempts, so consumers and
+// brokers supporting it can drop duplicates.
+type OutboxMessage struct {
+	ID string
+	// EventName is the name of the event, e.g. "user.registered".
+	EventName string
+	// Category is the category of the event, e.g. "account", which publishers map onto topics.
+	Category string
+	// Key identifies the user the event is about, the user id if it is known and the username
+	// otherwise. Publishers use it for partitioning, so the events of a user stay in order.
+	Key string
+	// SchemaVersion is the version of the serialized form of the event in Payload.
+	SchemaVersion int
+	// Payload is the JSON encoded event.
+	Payload    []byte
+	RequestID  string
+	OccurredAt time.Time
+	// Attempts counts the failed attempts to publish the message.
+	Attempts  int
+	LastError string
+}
+
+// OutboxPolicy configures the relay publishing recorded messages.
+type OutboxPolicy struct {
+	// BatchSize is the largest number of messages published at once.
+	BatchSize int
+	// MaxAttempts is the number of attempts before a message is given up and kept as failed.
+	MaxAttempts int
+	// Lease is how long a relay may take to publish a batch before another instance may claim it.
+	Lease time.Duration
+	// InitialBackoff is the delay after the first failed attempt. It doubles with every further failure.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts.
+	MaxBackoff time.Duration
+}
+
+// DefaultOutboxPolicy publishes batches of up to 100 messages and gives a message up after 10
+// attempts, retrying after 1 second at first and after at most 5 minutes.
+var DefaultOutboxPolicy = OutboxPolicy{BatchSize: 100, MaxAttempts: 10, Lease: time.Minute, InitialBackoff: time.Second, MaxBackoff: 5 * time.Minute}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// Password constraints applied to new passwords regardless of the tenant.
+const (
+	MinPasswordLength = 6
+	// MaxPasswordBytes is the longest password bcrypt can hash; longer input would be rejected by the hasher.
+	MaxPasswordBytes = 72
+)
+
+// PasswordPolicy holds the rules new passwords of a tenant have to satisfy.
+type PasswordPolicy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+}
+
+// DefaultPasswordPolicy only enforces the minimum length.
+var DefaultPasswordPolicy = PasswordPolicy{MinLength: MinPasswordLength}
+
+// Validate checks a new password against the policy. Existing passwords are never checked,
+// so tightening the rules does not lock anybody out.
+//
+// Parameters:
+//   - password: The new plain text password
+//   - username: The user the password is for; the password must not equal it
+//
+// Returns:
+//   - error: errorx.ErrWeakPassword with the first violated rule as detail, nil if the password is acceptable
+func (p PasswordPolicy) Validate(password string, username Username) error {
+	if violations := p.Violations(password, username); len(violations) > 0 {
+		return errorx.ErrWeakPassword.Detailf("%s", violations[0])
+	}
+	return nil
+}
+
+// Violations checks a new password against all rules of the policy, so clients can show every
+// rule the password breaks at once.
+//
+// Parameters:
+//   - password: The new plain text password
+//   - username: The user the password is for, empty if not known yet
+//
+// Returns:
+//   - []string: The violated rules in the order Validate checks them, e.g. "must contain a digit", none if the password is acceptable
+func (p PasswordPolicy) Violations(password string, username Username) []string {
+	minLength := max(p.MinLength, MinPasswordLength)
+	var violations []string
+	if utf8.RuneCountInString(password) < minLength {
+		violations = append(violations, fmt.Sprintf("must have at least %d characters", minLength))
+	}
+	if len(password) > MaxPasswordBytes {
+		violations = append(violations, fmt.Sprintf("must not exceed %d bytes", MaxPasswordBytes))
+	}
+	if username.String() != "" && strings.EqualFold(strings.TrimSpace(password), username.String()) {
+		violations = append(violations, "must not equal the username")
+	}
+	if p.RequireUpper && !strings.ContainsFunc(password, unicode.IsUpper) {
+		violations = append(violations, "must contain an upper case letter")
+	}
+	if p.RequireLower && !strings.ContainsFunc(password, unicode.IsLower) {
+		violations = append(violations, "must contain a lower case letter")
+	}
+	if p.RequireDigit && !strings.ContainsFunc(password, unicode.IsDigit) {
+		violations = append(violations, "must contain a digit")
+	}
+	if p.RequireSymbol && !strings.ContainsFunc(password, isSymbol) {
+		violations = append(violations, "must contain a character that is neither a letter nor a digit")
+	}
+	return violations
+}
+
+// isSymbol reports whether r is a printable character other than a letter, digit or space.
+func isSymbol(r rune) bool {
+	return unicode.IsPrint(r) && !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r)
+}
@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Strength scores of PasswordStrength, from trivially guessable to very strong.
+const (
+	PasswordScoreVeryWeak   = 0
+	PasswordScoreWeak       = 1
+	PasswordScoreFair       = 2
+	PasswordScoreStrong     = 3
+	PasswordScoreVeryStrong = 4
+)
+
+// passwordScoreBits are the estimated entropy bits a password needs for the scores above PasswordScoreVeryWeak.
+var passwordScoreBits = [...]float64{28, 36, 60, 80}
+
+// commonPasswords are frequently used passwords and fragments that attackers try first.
+var commonPasswords = []string{
+	"password", "passwort", "123456", "qwerty", "azerty", "letmein", "welcome", "admin", "login",
+	"iloveyou", "monkey", "dragon", "secret", "master", "abc123", "football", "baseball", "sunshine",
+}
+
+// PasswordStrength is the estimated strength of a password together with the feedback to improve it.
+// Acceptable reports whether the password satisfies the password policy, which is what the server
+// enforces; the score only guides users towards better passwords.
+type PasswordStrength struct {
+	Score       int      `classification:"operational"`
+	EntropyBits float64  `classification:"operational"`
+	Acceptable  bool     `classification:"operational"`
+	Violations  []string `classification:"operational"`
+	Suggestions []string `classification:"operational"`
+}
+
+// EstimatePasswordStrength estimates the entropy of a password from its length and character
+// classes, discounted for repeated characters, sequences, common passwords and the username, and
+// checks it against the password policy.
+//
+// Parameters:
+//   - password: The plain text password
+//   - username: The user the password is for, empty if not known yet
+//   - policy: The password policy of the tenant
+//
+// Returns:
+//   - PasswordStrength: The score from PasswordScoreVeryWeak to PasswordScoreVeryStrong, the policy violations and suggestions
+func EstimatePasswordStrength(password string, username Username, policy PasswordPolicy) PasswordStrength {
+	strength := PasswordStrength{Violations: policy.Violations(password, username)}
+	strength.Acceptable = len(strength.Violations) == 0
+
+	runes := []rune(password)
+	effective := float64(len(runes))
+	if repeated := repeatedRunes(runes); repeated > 0 {
+		effective -= float64(repeated) * 0.75
+		strength.Suggestions = append(strength.Suggestions, "avoid repeated characters")
+	}
+	if sequential := sequentialRunes(runes); sequential > 0 {
+		effective -= float64(sequential) * 0.75
+		strength.Suggestions = append(strength.Suggestions, "avoid sequences like abc or 123")
+	}
+	lower := strings.ToLower(password)
+	for _, common := range commonPasswords {
+		if strings.Contains(lower, common) {
+			effective -= float64(len(common)) * 0.9
+			strength.Suggestions = append(strength.Suggestions, "avoid common passwords and words")
+			break
+		}
+	}
+	if name := strings.ToLower(username.String()); len(name) >= 3 && strings.Contains(lower, name) {
+		effective -= float64(len([]rune(name))) * 0.9
+		strength.Suggestions = append(strength.Suggestions, "avoid including the username")
+	}
+
+	pool, classes := characterPool(runes)
+	if classes < 3 && len(runes) < 20 {
+		strength.Suggestions = append(strength.Suggestions, "mix upper and lower case letters, digits and symbols")
+	}
+	if len(runes) < 12 {
+		strength.Suggestions = append(strength.Suggestions, "use at least 12 characters, e.g. several unrelated words")
+	}
+
+	if effective > 0 && pool > 0 {
+		strength.EntropyBits = math.Round(effective*math.Log2(float64(pool))*10) / 10
+	}
+	for _, bits := range passwordScoreBits {
+		if strength.EntropyBits >= bits {
+			strength.Score++
+		}
+	}
+	if !strength.Acceptable {
+		strength.Score = min(strength.Score, PasswordScoreWeak)
+	}
+	return strength
+}
+
+// characterPool returns the size of the alphabet the password draws from and the number of character classes used.
+func characterPool(runes []rune) (int, int) {
+	var lower, upper, digit, symbol, other bool
+	for _, r := range runes {
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		case (r <= unicode.MaxASCII && isSymbol(r)) || r == ' ':
+			symbol = true
+		default:
+			other = true
+		}
+	}
+	pool, classes := 0, 0
+	for _, class := range []struct {
+		used bool
+		size int
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {symbol, 33}, {other, 100}} {
+		if class.used {
+			pool += class.size
+			classes++
+		}
+	}
+	return pool, classes
+}
+
+// repeatedRunes counts the characters repeating their predecessor in runs of at least three,
+// e.g. 2 in "aaa" and none in "coffee".
+func repeatedRunes(runes []rune) int {
+	count := 0
+	for i := 2; i < len(runes); i++ {
+		a, b, c := unicode.ToLower(runes[i-2]), unicode.ToLower(runes[i-1]), unicode.ToLower(runes[i])
+		if a == b && b == c {
+			if i == 2 || unicode.ToLower(runes[i-3]) != a {
+				count++
+			}
+			count++
+		}
+	}
+	return count
+}
+
+// sequentialRunes counts the characters continuing an ascending or descending sequence of at
+// least three, e.g. 2 in "abc" and 3 in "4321".
+func sequentialRunes(runes []rune) int {
+	count := 0
+	for i := 2; i < len(runes); i++ {
+		a, b, c := unicode.ToLower(runes[i-2]), unicode.ToLower(runes[i-1]), unicode.ToLower(runes[i])
+		if step := b - a; (step == 1 || step == -1) && c-b == step {
+			if i == 2 || unicode.ToLower(runes[i-3])+step != a {
+				count++
+			}
+			count++
+		}
+	}
+	return count
+}
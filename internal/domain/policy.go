@@ -0,0 +1,154 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// Attributes describe one party of an access request. Every attribute may carry several values,
+// e.g. the roles of a subject; single-valued attributes carry one.
+type Attributes map[string][]string
+
+// AccessRequest is the input of an attribute-based access decision.
+type AccessRequest struct {
+	Subject     Attributes
+	Resource    Attributes
+	Action      string
+	Environment Attributes
+}
+
+// PolicyEffect is the outcome a policy produces when it applies.
+type PolicyEffect string
+
+// Policy effects.
+const (
+	EffectAllow PolicyEffect = "allow"
+	EffectDeny  PolicyEffect = "deny"
+)
+
+// PolicyDecision is the combined outcome of all policies for an access request.
+type PolicyDecision int
+
+// Policy decisions.
+const (
+	// DecisionNotApplicable leaves the decision to the role-based access rules.
+	DecisionNotApplicable PolicyDecision = iota
+	// DecisionAllow admits the request, even if the role-based access rules would not.
+	DecisionAllow
+	// DecisionDeny rejects the request, even if the role-based access rules would admit it.
+	DecisionDeny
+)
+
+// policyIDPattern allows lower case letters, digits, dashes and underscores, starting with a letter or digit.
+var policyIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// Policy is an attribute-based access rule.
+//
+// A policy applies to a request if one of its actions matches the action of the request and its
+// condition holds. Actions are matched literally, "*" matches every action and a trailing "*"
+// matches every action with the given prefix, e.g. "GET /admin/*". The condition is written in
+// the policy language described at ParseCondition; an empty condition always holds.
+type Policy struct {
+	ID          string
+	Description string
+	Effect      PolicyEffect
+	Actions     []string
+	Condition   string
+	UpdatedAt   time.Time
+}
+
+// ValidPolicyID reports whether the policy id is well-formed, e.g. "deny-outside-office-hours".
+func ValidPolicyID(id string) bool {
+	return policyIDPattern.MatchString(id)
+}
+
+// Validate checks the id, the effect, the actions and the condition of the policy.
+//
+// Returns:
+//   - error: An error wrapping errorx.ErrInvalidPolicy that names the violated rule, nil if the policy is valid
+func (p Policy) Validate() error {
+	if !ValidPolicyID(p.ID) {
+		return errorx.ErrInvalidPolicy.Detailf("malformed id")
+	}
+	if p.Effect != EffectAllow && p.Effect != EffectDeny {
+		return errorx.ErrInvalidPolicy.Detailf("effect must be %q or %q", EffectAllow, EffectDeny)
+	}
+	if len(p.Actions) == 0 {
+		return errorx.ErrInvalidPolicy.Detailf("at least one action is required")
+	}
+	if _, err := ParseCondition(p.Condition); err != nil {
+		return errorx.ErrInvalidPolicy.Detailf("%v", err)
+	}
+	return nil
+}
+
+// AppliesTo reports whether one of the actions of the policy matches the action.
+func (p Policy) AppliesTo(action string) bool {
+	for _, pattern := range p.Actions {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(action, prefix) {
+				return true
+			}
+		} else if pattern == action {
+			return true
+		}
+	}
+	return false
+}
+
+// PolicySet is a set of validated policies ready to be evaluated.
+type PolicySet struct {
+	policies   []Policy
+	conditions []Condition
+}
+
+// NewPolicySet compiles the conditions of the policies.
+//
+// Parameters:
+//   - policies: The policies to evaluate
+//
+// Returns:
+//   - PolicySet: The compiled policies
+//   - error: An error wrapping errorx.ErrInvalidPolicy that names the first invalid policy
+func NewPolicySet(policies []Policy) (PolicySet, error) {
+	set := PolicySet{policies: policies, conditions: make([]Condition, 0, len(policies))}
+	for _, policy := range policies {
+		if err := policy.Validate(); err != nil {
+			return PolicySet{}, fmt.Errorf("policy %s: %w", policy.ID, err)
+		}
+		condition, _ := ParseCondition(policy.Condition)
+		set.conditions = append(set.conditions, condition)
+	}
+	return set, nil
+}
+
+// Policies returns the policies of the set. The result must not be modified.
+func (ps PolicySet) Policies() []Policy {
+	return ps.policies
+}
+
+// Decide evaluates all policies against the request. Deny overrides allow: a single applicable
+// deny policy rejects the request no matter how many allow policies apply.
+//
+// Parameters:
+//   - request: The access request
+//
+// Returns:
+//   - PolicyDecision: DecisionDeny if a deny policy applies, DecisionAllow if only allow policies
+//     apply, DecisionNotApplicable if no policy applies
+func (ps PolicySet) Decide(request AccessRequest) PolicyDecision {
+	decision := DecisionNotApplicable
+	for i, policy := range ps.policies {
+		if !policy.AppliesTo(request.Action) || !ps.conditions[i].Matches(request) {
+			continue
+		}
+		if policy.Effect == EffectDeny {
+			return DecisionDeny
+		}
+		decision = DecisionAllow
+	}
+	return decision
+}
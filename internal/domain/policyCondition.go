@@ -0,0 +1,416 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxConditionLength caps the source length of a condition, so evaluating policies stays cheap.
+const maxConditionLength = 2048
+
+// Condition is a compiled policy condition.
+type Condition struct {
+	root conditionNode
+}
+
+// conditionNode is a node of the syntax tree of a condition.
+type conditionNode interface {
+	matches(request AccessRequest) bool
+}
+
+// ParseCondition compiles a condition written in the policy language.
+//
+// A condition combines comparisons with "&&", "||", "!" and parentheses, e.g.
+//
+//	subject.roles contains "SUPPORT" && resource.id != subject.id
+//	env.hour >= 8 && env.hour < 18 && env.weekday in ["Mon", "Tue", "Wed", "Thu", "Fri"]
+//
+// Operands are attributes, quoted strings, integers and lists of strings and integers. Attributes
+// are named "subject.<name>", "resource.<name>", "env.<name>" or "action". The operators are
+// "==" and "!=" (equal values), "contains" (the left values include all right values), "in"
+// (all left values are among the right values) and "<", "<=", ">", ">=" (integers). Every
+// comparison involving a missing attribute is false, so a condition never holds by accident.
+// The literals true and false are conditions as well.
+//
+// Parameters:
+//   - source: The condition; empty conditions always hold
+//
+// Returns:
+//   - Condition: The compiled condition
+//   - error: An error describing the syntax error
+func ParseCondition(source string) (Condition, error) {
+	if strings.TrimSpace(source) == "" {
+		return Condition{constantNode(true)}, nil
+	}
+	if len(source) > maxConditionLength {
+		return Condition{}, fmt.Errorf("condition exceeds %d bytes", maxConditionLength)
+	}
+
+	tokens, err := tokenizeCondition(source)
+	if err != nil {
+		return Condition{}, err
+	}
+	p := &conditionParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return Condition{}, err
+	}
+	if !p.done() {
+		return Condition{}, fmt.Errorf("unexpected %q", p.peek().text)
+	}
+	return Condition{root}, nil
+}
+
+// Matches reports whether the condition holds for the request. The zero Condition never holds.
+func (c Condition) Matches(request AccessRequest) bool {
+	return c.root != nil && c.root.matches(request)
+}
+
+// tokenKind classifies the tokens of a condition.
+type tokenKind int
+
+const (
+	tokenIdent tokenKind = iota
+	tokenString
+	tokenNumber
+	tokenSymbol
+)
+
+// conditionToken is a lexical token of a condition.
+type conditionToken struct {
+	kind tokenKind
+	text string
+}
+
+// tokenizeCondition splits a condition into tokens.
+func tokenizeCondition(source string) ([]conditionToken, error) {
+	var tokens []conditionToken
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			text, err := strconv.Unquote(string(runes[i : end+1]))
+			if err != nil {
+				return nil, fmt.Errorf("malformed string %s", string(runes[i:end+1]))
+			}
+			tokens = append(tokens, conditionToken{tokenString, text})
+			i = end + 1
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			end := i + 1
+			for end < len(runes) && unicode.IsDigit(runes[end]) {
+				end++
+			}
+			tokens = append(tokens, conditionToken{tokenNumber, string(runes[i:end])})
+			i = end
+		case unicode.IsLetter(r) || r == '_':
+			end := i + 1
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_' || runes[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, conditionToken{tokenIdent, string(runes[i:end])})
+			i = end
+		default:
+			symbol := ""
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case "==", "!=", "<=", ">=", "&&", "||":
+					symbol = two
+				}
+			}
+			if symbol == "" {
+				switch r {
+				case '<', '>', '!', '(', ')', '[', ']', ',':
+					symbol = string(r)
+				default:
+					return nil, fmt.Errorf("unexpected character %q", r)
+				}
+			}
+			tokens = append(tokens, conditionToken{tokenSymbol, symbol})
+			i += len(symbol)
+		}
+	}
+	return tokens, nil
+}
+
+// conditionParser is a recursive descent parser for conditions.
+type conditionParser struct {
+	tokens []conditionToken
+	pos    int
+}
+
+func (p *conditionParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *conditionParser) peek() conditionToken {
+	if p.done() {
+		return conditionToken{tokenSymbol, "end of condition"}
+	}
+	return p.tokens[p.pos]
+}
+
+// accept consumes the next token if it is the given symbol or keyword.
+func (p *conditionParser) accept(text string) bool {
+	if !p.done() && (p.tokens[p.pos].kind == tokenSymbol || p.tokens[p.pos].kind == tokenIdent) && p.tokens[p.pos].text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *conditionParser) expect(text string) error {
+	if !p.accept(text) {
+		return fmt.Errorf("expected %q, found %q", text, p.peek().text)
+	}
+	return nil
+}
+
+// parseOr parses: and ( "||" and )*
+func (p *conditionParser) parseOr() (conditionNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+// parseAnd parses: unary ( "&&" unary )*
+func (p *conditionParser) parseAnd() (conditionNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+// parseUnary parses: "!" unary | "(" or ")" | "true" | "false" | comparison
+func (p *conditionParser) parseUnary() (conditionNode, error) {
+	switch {
+	case p.accept("!"):
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand}, nil
+	case p.accept("("):
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(")")
+	case p.accept("true"):
+		return constantNode(true), nil
+	case p.accept("false"):
+		return constantNode(false), nil
+	}
+	return p.parseComparison()
+}
+
+// parseComparison parses: operand operator operand
+func (p *conditionParser) parseComparison() (conditionNode, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	operator := p.peek()
+	switch operator.text {
+	case "==", "!=", "<", "<=", ">", ">=", "contains", "in":
+		if operator.kind == tokenString {
+			return nil, fmt.Errorf("expected comparison operator, found string %q", operator.text)
+		}
+		p.pos++
+	default:
+		return nil, fmt.Errorf("expected comparison operator, found %q", operator.text)
+	}
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return comparisonNode{operator.text, left, right}, nil
+}
+
+// parseOperand parses: attribute | string | number | "[" literal ( "," literal )* "]"
+func (p *conditionParser) parseOperand() (operand, error) {
+	if p.accept("[") {
+		var values []string
+		for {
+			token := p.peek()
+			if token.kind != tokenString && token.kind != tokenNumber {
+				return operand{}, fmt.Errorf("expected string or number in list, found %q", token.text)
+			}
+			p.pos++
+			values = append(values, token.text)
+			if !p.accept(",") {
+				break
+			}
+		}
+		return operand{literal: values}, p.expect("]")
+	}
+
+	token := p.peek()
+	switch token.kind {
+	case tokenString, tokenNumber:
+		p.pos++
+		return operand{literal: []string{token.text}}, nil
+	case tokenIdent:
+		scope, name, _ := strings.Cut(token.text, ".")
+		switch {
+		case token.text == "action":
+		case (scope == "subject" || scope == "resource" || scope == "env") && name != "":
+		default:
+			return operand{}, fmt.Errorf("unknown attribute %q", token.text)
+		}
+		p.pos++
+		return operand{attribute: token.text}, nil
+	}
+	return operand{}, fmt.Errorf("expected operand, found %q", token.text)
+}
+
+// operand is either a literal or a reference to an attribute of the request.
+type operand struct {
+	attribute string
+	literal   []string
+}
+
+// values resolves the operand against the request.
+func (o operand) values(request AccessRequest) []string {
+	if o.attribute == "" {
+		return o.literal
+	}
+	if o.attribute == "action" {
+		if request.Action == "" {
+			return nil
+		}
+		return []string{request.Action}
+	}
+	scope, name, _ := strings.Cut(o.attribute, ".")
+	switch scope {
+	case "subject":
+		return request.Subject[name]
+	case "resource":
+		return request.Resource[name]
+	default:
+		return request.Environment[name]
+	}
+}
+
+type constantNode bool
+
+func (n constantNode) matches(AccessRequest) bool { return bool(n) }
+
+type notNode struct{ operand conditionNode }
+
+func (n notNode) matches(request AccessRequest) bool { return !n.operand.matches(request) }
+
+type andNode struct{ left, right conditionNode }
+
+func (n andNode) matches(request AccessRequest) bool {
+	return n.left.matches(request) && n.right.matches(request)
+}
+
+type orNode struct{ left, right conditionNode }
+
+func (n orNode) matches(request AccessRequest) bool {
+	return n.left.matches(request) || n.right.matches(request)
+}
+
+type comparisonNode struct {
+	operator    string
+	left, right operand
+}
+
+func (n comparisonNode) matches(request AccessRequest) bool {
+	left, right := n.left.values(request), n.right.values(request)
+	if len(left) == 0 || len(right) == 0 {
+		return false
+	}
+
+	switch n.operator {
+	case "==":
+		return equalValues(left, right)
+	case "!=":
+		return !equalValues(left, right)
+	case "contains":
+		return includesAll(left, right)
+	case "in":
+		return includesAll(right, left)
+	}
+
+	if len(left) != 1 || len(right) != 1 {
+		return false
+	}
+	l, lerr := strconv.ParseInt(left[0], 10, 64)
+	r, rerr := strconv.ParseInt(right[0], 10, 64)
+	if lerr != nil || rerr != nil {
+		return false
+	}
+	switch n.operator {
+	case "<":
+		return l < r
+	case "<=":
+		return l <= r
+	case ">":
+		return l > r
+	default:
+		return l >= r
+	}
+}
+
+// equalValues reports whether both lists hold the same values in the same order.
+func equalValues(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// includesAll reports whether every value of sub is among the values of set.
+func includesAll(set []string, sub []string) bool {
+	for _, value := range sub {
+		found := false
+		for _, candidate := range set {
+			if candidate == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Principal is the authenticated subject of a verified access token.
+type Principal struct {
+	Subject string
+	Tenant  string
+	// SessionID is the session the token is bound to, empty for tokens issued before sessions were tracked.
+	SessionID string
+	// TokenVersion is the token version of the user when the token was issued; tokens carrying an
+	// older version than their session were issued before a role change and are rejected.
+	TokenVersion int
+	Roles        []Role
+	Permissions  []Permission
+	Groups       []string
+	ExpiresAt    time.Time
+}
+
+// HasRole reports whether the principal holds the given role.
+func (p Principal) HasRole(role Role) bool {
+	return slices.Contains(p.Roles, role)
+}
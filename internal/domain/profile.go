@@ -0,0 +1,194 @@
+package domain
+
+import (
+	"golang.org/x/text/language"
+	"net/url"
+	"regexp"
+	"time"
+	"unicode/utf8"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// Profile limits.
+const (
+	MaxDisplayNameLength      = 64
+	MaxAvatarURLLength        = 2048
+	MaxProfileAttributes      = 50
+	MaxProfileAttributeLength = 1024
+)
+
+// AttributeVisibility controls who may read a custom profile attribute.
+type AttributeVisibility string
+
+// Attribute visibilities.
+const (
+	// VisibilityPrivate attributes are visible to the user only.
+	VisibilityPrivate AttributeVisibility = "private"
+	// VisibilityPublic attributes are visible to every authenticated user.
+	VisibilityPublic AttributeVisibility = "public"
+)
+
+// attributeKeyPattern allows letters, digits, dashes and underscores, starting with a letter. Dots are
+// excluded, as the attributes are stored as fields of a document.
+var attributeKeyPattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]{0,63}$`)
+
+// ProfileAttribute is a custom profile attribute together with its visibility.
+type ProfileAttribute struct {
+	Value      string
+	Visibility AttributeVisibility
+}
+
+// Profile holds the self-managed, non-credential information about a user.
+//
+// The structured fields are shown to every authenticated user; the custom attributes are shown
+// according to their visibility. The phone number and the notification preferences are private.
+type Profile struct {
+	DisplayName   string                      `classification:"pii"`
+	Locale        string                      `classification:"pii"`
+	Timezone      string                      `classification:"pii"`
+	AvatarURL     string                      `classification:"pii"`
+	Attributes    map[string]ProfileAttribute `classification:"pii"`
+	PhoneNumber   string                      `classification:"pii"`
+	Notifications NotificationPreferences     `classification:"pii"`
+	UpdatedAt     time.Time                   `classification:"operational"`
+}
+
+// ProfileChanges describes a partial profile update. Nil fields are left unchanged, empty strings
+// clear a field. An attribute mapped to nil is removed. Notifications replace the preferences as a whole.
+type ProfileChanges struct {
+	DisplayName   *string
+	Locale        *string
+	Timezone      *string
+	AvatarURL     *string
+	Attributes    map[string]*ProfileAttribute
+	PhoneNumber   *string
+	Notifications NotificationPreferences
+}
+
+// Apply validates the changes and applies them to the profile. Locales are stored in their
+// canonical form, e.g. "de-CH" for "de-ch". On error the profile is left unchanged.
+//
+// Parameters:
+//   - changes: The fields to change
+//   - at: The time of the change
+//
+// Returns:
+//   - error: An error wrapping errorx.ErrInvalidProfile that names the rejected field
+func (p *Profile) Apply(changes ProfileChanges, at time.Time) error {
+	updated := *p
+	updated.Attributes = make(map[string]ProfileAttribute, len(p.Attributes))
+	for key, attribute := range p.Attributes {
+		updated.Attributes[key] = attribute
+	}
+
+	if changes.DisplayName != nil {
+		if utf8.RuneCountInString(*changes.DisplayName) > MaxDisplayNameLength {
+			return errorx.ErrInvalidProfile.Detailf("displayName must not exceed %d characters", MaxDisplayNameLength)
+		}
+		updated.DisplayName = *changes.DisplayName
+	}
+	if changes.Locale != nil {
+		locale, err := canonicalLocale(*changes.Locale)
+		if err != nil {
+			return err
+		}
+		updated.Locale = locale
+	}
+	if changes.Timezone != nil {
+		if *changes.Timezone != "" {
+			if _, err := time.LoadLocation(*changes.Timezone); err != nil {
+				return errorx.ErrInvalidProfile.Detailf("timezone must be an IANA time zone such as \"Europe/Berlin\"")
+			}
+		}
+		updated.Timezone = *changes.Timezone
+	}
+	if changes.AvatarURL != nil {
+		if err := validateAvatarURL(*changes.AvatarURL); err != nil {
+			return err
+		}
+		updated.AvatarURL = *changes.AvatarURL
+	}
+	if changes.PhoneNumber != nil {
+		if err := validatePhoneNumber(*changes.PhoneNumber); err != nil {
+			return err
+		}
+		updated.PhoneNumber = *changes.PhoneNumber
+	}
+	if changes.Notifications != nil {
+		if err := changes.Notifications.validate(); err != nil {
+			return err
+		}
+		updated.Notifications = changes.Notifications
+	}
+
+	for key, attribute := range changes.Attributes {
+		if attribute == nil {
+			delete(updated.Attributes, key)
+			continue
+		}
+		if !attributeKeyPattern.MatchString(key) {
+			return errorx.ErrInvalidProfile.Detailf("attribute %q has a malformed name", key)
+		}
+		if len(attribute.Value) > MaxProfileAttributeLength {
+			return errorx.ErrInvalidProfile.Detailf("attribute %q must not exceed %d bytes", key, MaxProfileAttributeLength)
+		}
+		visibility := attribute.Visibility
+		if visibility == "" {
+			visibility = VisibilityPrivate
+		}
+		if visibility != VisibilityPrivate && visibility != VisibilityPublic {
+			return errorx.ErrInvalidProfile.Detailf("attribute %q must be %q or %q", key, VisibilityPrivate, VisibilityPublic)
+		}
+		updated.Attributes[key] = ProfileAttribute{Value: attribute.Value, Visibility: visibility}
+	}
+	if len(updated.Attributes) > MaxProfileAttributes {
+		return errorx.ErrInvalidProfile.Detailf("at most %d attributes are allowed", MaxProfileAttributes)
+	}
+
+	updated.UpdatedAt = at
+	*p = updated
+	return nil
+}
+
+// PublicView returns a copy of the profile that contains the public attributes only and neither
+// the phone number nor the notification preferences, for showing it to users other than its owner.
+func (p Profile) PublicView() Profile {
+	public := make(map[string]ProfileAttribute)
+	for key, attribute := range p.Attributes {
+		if attribute.Visibility == VisibilityPublic {
+			public[key] = attribute
+		}
+	}
+	p.Attributes = public
+	p.PhoneNumber = ""
+	p.Notifications = nil
+	return p
+}
+
+// canonicalLocale validates a BCP 47 language tag and returns its canonical form.
+func canonicalLocale(locale string) (string, error) {
+	if locale == "" {
+		return "", nil
+	}
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return "", errorx.ErrInvalidProfile.Detailf("locale must be a BCP 47 language tag such as \"en-US\"")
+	}
+	return tag.String(), nil
+}
+
+// validateAvatarURL checks that the avatar is an absolute https URL, so profiles cannot embed
+// mixed content or script URLs into pages that render them.
+func validateAvatarURL(avatarURL string) error {
+	if avatarURL == "" {
+		return nil
+	}
+	if len(avatarURL) > MaxAvatarURLLength {
+		return errorx.ErrInvalidProfile.Detailf("avatarUrl must not exceed %d bytes", MaxAvatarURLLength)
+	}
+	parsed, err := url.Parse(avatarURL)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return errorx.ErrInvalidProfile.Detailf("avatarUrl must be an absolute https URL")
+	}
+	return nil
+}
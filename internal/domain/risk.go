@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"time"
+)
+
+// maxRiskScore caps the risk score of a login attempt.
+const maxRiskScore = 100
+
+// LoginAttempt is a login whose credentials have been verified and whose risk is assessed
+// before a session is started.
+type LoginAttempt struct {
+	User   User
+	Device Device
+	At     time.Time
+}
+
+// RiskSignal is an indication that a login attempt might not be made by the owner of the account,
+// e.g. a device or country the user has not logged in from before.
+type RiskSignal struct {
+	// Name identifies the kind of signal, e.g. "new_device".
+	Name string
+	// Score is the risk the signal adds, between 0 and 100.
+	Score int
+	// Detail explains the signal for the audit trail, e.g. "3 failed logins in the last 15m0s".
+	Detail string
+}
+
+// Risk signal names of the built-in signal providers.
+const (
+	RiskSignalNewDevice      = "new_device"
+	RiskSignalNewCountry     = "new_country"
+	RiskSignalRecentFailures = "recent_failures"
+	RiskSignalUnusualTime    = "unusual_time"
+)
+
+// RiskAction is what happens to a login attempt of a given risk.
+type RiskAction string
+
+// Risk actions, from the least to the most restrictive.
+const (
+	// RiskAllow lets the login proceed.
+	RiskAllow RiskAction = "allow"
+	// RiskRequireMfa only lets the login proceed if the account uses multi-factor authentication.
+	RiskRequireMfa RiskAction = "require_mfa"
+	// RiskBlock rejects the login.
+	RiskBlock RiskAction = "block"
+)
+
+// RiskPolicy maps risk scores onto actions. A threshold of zero or less is never reached.
+type RiskPolicy struct {
+	// RequireMfaAt is the score from which on multi-factor authentication is required.
+	RequireMfaAt int
+	// BlockAt is the score from which on logins are rejected.
+	BlockAt int
+}
+
+// DefaultRiskPolicy requires multi-factor authentication from a score of 60 and blocks from 90.
+var DefaultRiskPolicy = RiskPolicy{RequireMfaAt: 60, BlockAt: 90}
+
+// Decide returns the action for a risk score.
+func (p RiskPolicy) Decide(score int) RiskAction {
+	switch {
+	case p.BlockAt > 0 && score >= p.BlockAt:
+		return RiskBlock
+	case p.RequireMfaAt > 0 && score >= p.RequireMfaAt:
+		return RiskRequireMfa
+	default:
+		return RiskAllow
+	}
+}
+
+// RiskAssessment is the risk of a login attempt and the action taken on it.
+type RiskAssessment struct {
+	Score   int
+	Signals []RiskSignal
+	Action  RiskAction
+}
+
+// HasSignal reports whether a signal of the given name was raised.
+func (a RiskAssessment) HasSignal(name string) bool {
+	for _, signal := range a.Signals {
+		if signal.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// AssessRisk combines the signals of a login attempt into a risk score, the sum of the signal
+// scores capped at 100, and decides on the action.
+//
+// Parameters:
+//   - signals: The signals raised for the attempt
+//   - policy: The policy deciding on the action
+//
+// Returns:
+//   - RiskAssessment: The score, the signals and the action
+func AssessRisk(signals []RiskSignal, policy RiskPolicy) RiskAssessment {
+	score := 0
+	for _, signal := range signals {
+		score += min(max(signal.Score, 0), maxRiskScore)
+	}
+	score = min(score, maxRiskScore)
+	return RiskAssessment{Score: score, Signals: signals, Action: policy.Decide(score)}
+}
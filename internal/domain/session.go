@@ -0,0 +1,208 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// sessionTouchInterval is how much time has to pass before Touch records activity again,
+// which keeps the writes of busy sessions bounded.
+const sessionTouchInterval = time.Minute
+
+// SessionRevocationReason explains why a session was ended before it expired.
+type SessionRevocationReason string
+
+// Session revocation reasons.
+const (
+	// SessionRevokedLogout marks sessions the user logged out of.
+	SessionRevokedLogout SessionRevocationReason = "logout"
+	// SessionRevokedByUser marks sessions the user ended from another session.
+	SessionRevokedByUser SessionRevocationReason = "revoked_by_user"
+	// SessionRevokedByAdmin marks sessions an administrator ended.
+	SessionRevokedByAdmin SessionRevocationReason = "revoked_by_admin"
+	// SessionRevokedEvicted marks sessions ended to make room for a new login beyond the session limit.
+	SessionRevokedEvicted SessionRevocationReason = "evicted"
+	// SessionRevokedAccountDeleted marks sessions ended because their account was deleted.
+	SessionRevokedAccountDeleted SessionRevocationReason = "account_deleted"
+	// SessionRevokedRefreshTokenReused marks sessions ended because a rotated refresh token was presented again,
+	// which means the token was copied.
+	SessionRevokedRefreshTokenReused SessionRevocationReason = "refresh_token_reused"
+	// SessionRevokedAccountMerged marks sessions ended because their account was merged into another one.
+	SessionRevokedAccountMerged SessionRevocationReason = "account_merged"
+)
+
+// Device describes the client a session was started from, as reported by the client.
+// The country is the ISO 3166-1 alpha-2 code an edge proxy located the client in, if known.
+type Device struct {
+	UserAgent string
+	IPAddress string
+	Country   string
+}
+
+// Session is a login of a user on a device. Every access token issued at login belongs to a
+// session, and the token is only accepted while its session is active.
+//
+// If refresh tokens are enabled, the session also holds the hash of the refresh token issued last.
+// Every refresh rotates the token, and the hash of the replaced token is kept to recognize its reuse.
+//
+// TokenVersion is the lowest token version of the user the access tokens of the session have to
+// carry. It is raised when the roles of the user change, which outdates the tokens issued before.
+type Session struct {
+	ID                       string                  `classification:"operational"`
+	UserID                   string                  `classification:"operational"`
+	Username                 Username                `classification:"pii"`
+	TenantID                 string                  `classification:"operational"`
+	TokenVersion             int                     `classification:"operational"`
+	Device                   Device                  `classification:"pii"`
+	CreatedAt                time.Time               `classification:"operational"`
+	LastSeenAt               time.Time               `classification:"operational"`
+	ExpiresAt                time.Time               `classification:"operational"`
+	RevokedAt                time.Time               `classification:"operational"`
+	RevocationReason         SessionRevocationReason `classification:"operational"`
+	RefreshTokenHash         string                  `classification:"credential"`
+	PreviousRefreshTokenHash string                  `classification:"credential"`
+	RefreshedAt              time.Time               `classification:"operational"`
+}
+
+// NewSession starts a session for a user.
+//
+// Parameters:
+//   - id: The unique, unguessable id of the session
+//   - user: The user logging in
+//   - device: The client the login is made from
+//   - createdAt: The time of the login
+//   - expiresAt: The time the session ends on its own, i.e. the expiry of its access token or, if
+//     refresh tokens are enabled, of its refresh token
+//
+// Returns:
+//   - Session: The active session
+func NewSession(id string, user User, device Device, createdAt time.Time, expiresAt time.Time) Session {
+	return Session{
+		ID:           id,
+		UserID:       user.ID,
+		Username:     user.Username,
+		TenantID:     user.TenantID,
+		TokenVersion: user.TokenVersion,
+		Device:       device,
+		CreatedAt:    createdAt,
+		LastSeenAt:   createdAt,
+		ExpiresAt:    expiresAt,
+	}
+}
+
+// IsActive reports whether the session can still be used, i.e. it is neither revoked nor expired.
+func (s Session) IsActive(now time.Time) bool {
+	return s.RevokedAt.IsZero() && now.Before(s.ExpiresAt)
+}
+
+// AcceptsTokenVersion reports whether an access token carrying the given token version of the user
+// can be used with the session, i.e. it was not issued before the roles of the user changed.
+func (s Session) AcceptsTokenVersion(version int) bool {
+	return version >= s.TokenVersion
+}
+
+// RaiseTokenVersion outdates the access tokens of the session that carry an older token version
+// than the given one, e.g. after the roles of the user changed.
+//
+// Parameters:
+//   - version: The current token version of the user
+//
+// Returns:
+//   - bool: true if the version was raised and the session has to be stored
+func (s *Session) RaiseTokenVersion(version int) bool {
+	if version <= s.TokenVersion {
+		return false
+	}
+	s.TokenVersion = version
+	return true
+}
+
+// IsRevoked reports whether the session was ended before it expired.
+func (s Session) IsRevoked() bool {
+	return !s.RevokedAt.IsZero()
+}
+
+// Touch records activity of the session. Activity is recorded at most once per minute.
+//
+// Parameters:
+//   - now: The time of the activity
+//
+// Returns:
+//   - bool: true if LastSeenAt changed and the session has to be stored
+//   - error: errorx.ErrSessionNotActive if the session is revoked or expired
+func (s *Session) Touch(now time.Time) (bool, error) {
+	if !s.IsActive(now) {
+		return false, errorx.ErrSessionNotActive
+	}
+	if now.Sub(s.LastSeenAt) < sessionTouchInterval {
+		return false, nil
+	}
+	s.LastSeenAt = now
+	return true, nil
+}
+
+// Revoke ends the session before it expires.
+//
+// Parameters:
+//   - reason: Why the session is ended
+//   - now: The time of the revocation
+//
+// Returns:
+//   - error: errorx.ErrSessionNotActive if the session is already revoked or expired
+func (s *Session) Revoke(reason SessionRevocationReason, now time.Time) error {
+	if !s.IsActive(now) {
+		return errorx.ErrSessionNotActive
+	}
+	s.RevokedAt = now
+	s.RevocationReason = reason
+	return nil
+}
+
+// BindRefreshToken makes a refresh token the only one accepted for the session, e.g. at login or
+// when a refresh rotates the token. The token it replaces is remembered to recognize its reuse.
+//
+// Parameters:
+//   - secret: The secret part of the new refresh token, see NewRefreshToken
+//   - now: The time the token is issued
+//   - expiresAt: The time the refresh token and with it the session expire
+func (s *Session) BindRefreshToken(secret string, now time.Time, expiresAt time.Time) {
+	if s.RefreshTokenHash != "" {
+		s.PreviousRefreshTokenHash = s.RefreshTokenHash
+		s.RefreshedAt = now
+	}
+	s.RefreshTokenHash = HashRefreshTokenSecret(secret)
+	s.ExpiresAt = expiresAt
+}
+
+// CheckRefreshToken checks that a refresh token presented for the session is the one issued last.
+//
+// Parameters:
+//   - secret: The secret part of the presented refresh token
+//   - now: The time of the refresh
+//
+// Returns:
+//   - error: errorx.ErrRefreshTokenReused if the token has already been rotated, errorx.ErrInvalidRefreshToken
+//     if the session is not active or the token was never issued for it
+func (s Session) CheckRefreshToken(secret string, now time.Time) error {
+	hash := HashRefreshTokenSecret(secret)
+	if s.PreviousRefreshTokenHash != "" && subtle.ConstantTimeCompare([]byte(hash), []byte(s.PreviousRefreshTokenHash)) == 1 {
+		return errorx.ErrRefreshTokenReused
+	}
+	if s.RefreshTokenHash == "" || subtle.ConstantTimeCompare([]byte(hash), []byte(s.RefreshTokenHash)) != 1 {
+		return errorx.ErrInvalidRefreshToken
+	}
+	if !s.IsActive(now) {
+		return errorx.ErrInvalidRefreshToken.Detailf("session not active")
+	}
+	return nil
+}
+
+// HashRefreshTokenSecret returns the hex encoded SHA-256 hash of the secret of a refresh token,
+// which is stored instead of the secret. The secret is random, so a fast hash suffices.
+func HashRefreshTokenSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// SessionLimitStrategy decides what happens when a login would exceed the session limit.
+type SessionLimitStrategy string
+
+// Session limit strategies.
+const (
+	// SessionLimitReject rejects the new login, the existing sessions stay active.
+	SessionLimitReject SessionLimitStrategy = "reject"
+	// SessionLimitEvictOldest revokes the oldest sessions to make room for the new login.
+	SessionLimitEvictOldest SessionLimitStrategy = "evict_oldest"
+)
+
+// ParseSessionLimitStrategy converts the name of a strategy.
+//
+// Parameters:
+//   - name: "reject" or "evict_oldest"
+//
+// Returns:
+//   - SessionLimitStrategy: The strategy
+//   - error: An error if the name is unknown
+func ParseSessionLimitStrategy(name string) (SessionLimitStrategy, error) {
+	switch strategy := SessionLimitStrategy(name); strategy {
+	case SessionLimitReject, SessionLimitEvictOldest:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("unknown session limit strategy %q", name)
+}
+
+// SessionLimit bounds the number of sessions a user may have active at the same time.
+// A Max of zero or less disables the limit.
+type SessionLimit struct {
+	Max      int
+	Strategy SessionLimitStrategy
+}
+
+// Admit decides whether a user with the given sessions may start another one.
+//
+// Parameters:
+//   - sessions: The stored sessions of the user; ended sessions are ignored
+//   - now: The time of the login
+//
+// Returns:
+//   - []Session: The active sessions to evict, the oldest first, before the new session is started
+//   - error: errorx.ErrSessionLimitReached if the limit is reached and the strategy rejects new logins
+func (l SessionLimit) Admit(sessions []Session, now time.Time) ([]Session, error) {
+	if l.Max <= 0 {
+		return nil, nil
+	}
+
+	active := make([]Session, 0, len(sessions))
+	for _, session := range sessions {
+		if session.IsActive(now) {
+			active = append(active, session)
+		}
+	}
+	excess := len(active) - l.Max + 1
+	if excess <= 0 {
+		return nil, nil
+	}
+	if l.Strategy != SessionLimitEvictOldest {
+		return nil, errorx.ErrSessionLimitReached.Detailf("at most %d sessions may be active at the same time", l.Max)
+	}
+
+	sort.Slice(active, func(i, j int) bool { return active[i].CreatedAt.Before(active[j].CreatedAt) })
+	return active[:excess], nil
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+)
+
+// SMSMessage is a rendered SMS ready to be handed to an SMS provider.
+type SMSMessage struct {
+	// From is the sender, a phone number or an alphanumeric sender id; the default of the provider if empty.
+	From string
+	// To is the phone number of the recipient in E.164 format.
+	To   string
+	Body string
+}
+
+// twoDigitCallingCodes lists the country calling codes of two digits; "1" and "7" are the only ones
+// of a single digit and every other code has three.
+var twoDigitCallingCodes = []string{
+	"20", "27", "30", "31", "32", "33", "34", "36", "39", "40", "41", "43", "44", "45", "46", "47", "48", "49",
+	"51", "52", "53", "54", "55", "56", "57", "58", "60", "61", "62", "63", "64", "65", "66", "81", "82", "84",
+	"86", "90", "91", "92", "93", "94", "95", "98",
+}
+
+// CallingCode returns the country calling code of a phone number in E.164 format, e.g. "+41" for
+// "+41791234567". Countries sharing a code, e.g. the members of the North American Numbering Plan,
+// share the result.
+//
+// Parameters:
+//   - phoneNumber: The phone number in E.164 format
+//
+// Returns:
+//   - string: The calling code with the leading "+", empty if the number is not in E.164 format
+func CallingCode(phoneNumber string) string {
+	if !phoneNumberPattern.MatchString(phoneNumber) {
+		return ""
+	}
+	digits := strings.TrimPrefix(phoneNumber, "+")
+	switch {
+	case digits[0] == '1' || digits[0] == '7':
+		return "+" + digits[:1]
+	case slices.Contains(twoDigitCallingCodes, digits[:2]):
+		return "+" + digits[:2]
+	default:
+		return "+" + digits[:3]
+	}
+}
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"regexp"
+	"slices"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// DefaultTenantID is the tenant of requests that do not name one and of accounts created before tenants existed.
+const DefaultTenantID = "default"
+
+// Access token lifetimes a tenant may configure.
+const (
+	MinAccessTokenTTL     = 5 * time.Minute
+	MaxAccessTokenTTL     = 7 * 24 * time.Hour
+	DefaultAccessTokenTTL = 24 * time.Hour
+)
+
+// LoginMethod names a way of authenticating with username and password.
+type LoginMethod string
+
+// Login methods.
+const (
+	// LoginMethodPassword issues an access token to API clients, via HTTP or gRPC.
+	LoginMethodPassword LoginMethod = "password"
+	// LoginMethodSession keeps the access token of browsers in a session cookie.
+	LoginMethodSession LoginMethod = "session"
+)
+
+// loginMethods lists every known login method.
+var loginMethods = []LoginMethod{LoginMethodPassword, LoginMethodSession}
+
+// tenantIDPattern allows lower case letters, digits, dashes and underscores, starting with a letter or digit.
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// ValidTenantID reports whether a tenant id is well-formed, e.g. "acme-corp".
+func ValidTenantID(id string) bool {
+	return tenantIDPattern.MatchString(id)
+}
+
+// TenantSettings is the security posture of a tenant, applied to every request made for it.
+type TenantSettings struct {
+	PasswordPolicy PasswordPolicy
+	AccessTokenTTL time.Duration
+	// RequireMFA rejects logins of accounts without multi-factor authentication.
+	RequireMFA   bool
+	LoginMethods []LoginMethod
+}
+
+// DefaultTenantSettings returns the settings of tenants that have not been configured otherwise.
+//
+// Returns:
+//   - TenantSettings: The default password policy and token lifetime, no MFA requirement, all login methods
+func DefaultTenantSettings() TenantSettings {
+	return TenantSettings{
+		PasswordPolicy: DefaultPasswordPolicy,
+		AccessTokenTTL: DefaultAccessTokenTTL,
+		LoginMethods:   slices.Clone(loginMethods),
+	}
+}
+
+// Validate checks that the settings are within the bounds every tenant has to respect.
+//
+// Returns:
+//   - error: errorx.ErrInvalidTenant naming the rejected setting, nil if the settings are valid
+func (s TenantSettings) Validate() error {
+	if s.PasswordPolicy.MinLength < MinPasswordLength || s.PasswordPolicy.MinLength > MaxPasswordBytes {
+		return errorx.ErrInvalidTenant.Detailf("password minimum length must be between %d and %d", MinPasswordLength, MaxPasswordBytes)
+	}
+	if s.AccessTokenTTL < MinAccessTokenTTL || s.AccessTokenTTL > MaxAccessTokenTTL {
+		return errorx.ErrInvalidTenant.Detailf("access token lifetime must be between %s and %s", MinAccessTokenTTL, MaxAccessTokenTTL)
+	}
+	if len(s.LoginMethods) == 0 {
+		return errorx.ErrInvalidTenant.Detailf("at least one login method is required")
+	}
+	for _, method := range s.LoginMethods {
+		if !slices.Contains(loginMethods, method) {
+			return errorx.ErrInvalidTenant.Detailf("unknown login method %q", method)
+		}
+	}
+	return nil
+}
+
+// AllowsLoginMethod reports whether users of the tenant may log in with the method.
+func (s TenantSettings) AllowsLoginMethod(method LoginMethod) bool {
+	return slices.Contains(s.LoginMethods, method)
+}
+
+// Tenant is a customer organization sharing the deployment. Every account belongs to exactly one
+// tenant, and the settings of the tenant apply to its registrations and logins.
+type Tenant struct {
+	ID        string
+	Name      string
+	Settings  TenantSettings
+	UpdatedAt time.Time
+}
+
+// DefaultTenant returns the built-in tenant with the default settings.
+//
+// Returns:
+//   - Tenant: The default tenant
+func DefaultTenant() Tenant {
+	return Tenant{ID: DefaultTenantID, Name: "Default", Settings: DefaultTenantSettings()}
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"strings"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// AuthTokens are the credentials issued to a client after successful authentication.
+//
+// RefreshToken is empty unless refresh tokens are enabled. While the user has to replace a
+// temporary password, only a PasswordChangeToken expiring at ExpiresAt is issued instead of the
+// access and refresh tokens; it is accepted for nothing but the password change.
+type AuthTokens struct {
+	AccessToken         string
+	TokenType           string
+	ExpiresAt           time.Time
+	RefreshToken        string
+	PasswordChangeToken string
+}
+
+// NewRefreshToken composes a refresh token from the id of the session it belongs to and a random
+// secret, e.g. "9f86d081884c7d65.q0x3...". Only the hash of the secret is stored with the session.
+//
+// Parameters:
+//   - sessionID: The id of the session the token renews
+//   - secret: The unguessable secret of the token
+//
+// Returns:
+//   - string: The refresh token handed to the client
+func NewRefreshToken(sessionID string, secret string) string {
+	return sessionID + "." + secret
+}
+
+// ParseRefreshToken splits a refresh token created by NewRefreshToken.
+//
+// Parameters:
+//   - token: The refresh token presented by the client
+//
+// Returns:
+//   - string: The id of the session the token belongs to
+//   - string: The secret of the token
+//   - error: errorx.ErrInvalidRefreshToken if the token is malformed
+func ParseRefreshToken(token string) (string, string, error) {
+	sessionID, secret, ok := strings.Cut(token, ".")
+	if !ok || sessionID == "" || secret == "" {
+		return "", "", errorx.ErrInvalidRefreshToken.Detailf("malformed token")
+	}
+	return sessionID, secret, nil
+}
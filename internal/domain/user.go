@@ -1,12 +1,281 @@
 // Package domain defines core business logic and models for the application.
 package domain
 
+import (
+	"slices"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
 // User represents a user in the system.
 //
-// It encapsulates the core attributes of a user: username and password.
+// It encapsulates the core attributes of a user: identity, credentials, role and activity.
 // This struct is used to represent user data across different layers of the application.
+//
+// New users are created with NewUser from validated value objects. Adapters restore stored
+// users field by field; every later change goes through the methods of User, so the business
+// rules are applied no matter which use case changes the user.
+//
+// The canonical username and email are the keys under which both have to be unique, see Canonicalizer.
+// They are empty for users stored before canonicalization was introduced. The token version is
+// increased whenever the roles change and carried in access tokens, so tokens issued with the
+// previous roles can be told apart and are not refreshed. A legal hold keeps the data of the user
+// from being erased or purged, e.g. while it is evidence in a legal dispute. The lock reason and
+// expiry are only set while an administrator's lock is in place, see LockFor. Users whose password
+// was set by an administrator must change it before they can log in, see SetTemporaryPassword.
+// Aliases are the usernames of duplicate accounts merged into the user, which keep logging in to
+// it; MergedInto is set on the deleted duplicate, see MergeAccounts. MfaEnabled is set while the
+// user has at least one confirmed second factor, see EnrollMfa.
 type User struct {
-	Username string
-	Password string
-	Role     string
+	ID                 string         `classification:"operational"`
+	TenantID           string         `classification:"operational"`
+	Username           Username       `classification:"pii"`
+	Password           HashedPassword `classification:"credential"`
+	Email              Email          `classification:"pii"`
+	Roles              []Role         `classification:"operational"`
+	Status             AccountStatus  `classification:"operational"`
+	CreatedAt          time.Time      `classification:"operational"`
+	LastLoginAt        time.Time      `classification:"operational"`
+	MfaEnabled         bool           `classification:"operational"`
+	MfaFactors         []MfaFactor    `classification:"credential"`
+	CanonicalUsername  string         `classification:"pii"`
+	CanonicalEmail     string         `classification:"pii"`
+	TokenVersion       int            `classification:"operational"`
+	LegalHold          bool           `classification:"operational"`
+	LockReason         LockReason     `classification:"operational"`
+	LockedUntil        time.Time      `classification:"operational"`
+	MustChangePassword bool           `classification:"operational"`
+	Aliases            []Username     `classification:"pii"`
+	CanonicalAliases   []string       `classification:"pii"`
+	MergedInto         string         `classification:"operational"`
+}
+
+// NewUser creates an active user with the USER role.
+//
+// Parameters:
+//   - tenantID: The tenant the account belongs to
+//   - username: The username
+//   - email: The email address, may be the zero Email
+//   - password: The hashed password
+//   - createdAt: The time of the registration
+//
+// Returns:
+//   - User: The new user, without id until it is stored
+func NewUser(tenantID string, username Username, email Email, password HashedPassword, createdAt time.Time) User {
+	return User{
+		TenantID:  tenantID,
+		Username:  username,
+		Password:  password,
+		Email:     email,
+		Roles:     []Role{RoleUser},
+		Status:    StatusActive,
+		CreatedAt: createdAt,
+	}
+}
+
+// CanLogIn checks whether the account may authenticate, independent of its credentials.
+//
+// Returns:
+//   - error: errorx.ErrAccountDisabled if the account is disabled or deleted, errorx.ErrAccountLocked if it is
+//     locked, errorx.ErrAccountPending if it has not been activated yet, nil if it is active
+func (u User) CanLogIn() error {
+	switch u.Status {
+	case StatusActive:
+		return nil
+	case StatusLocked:
+		return errorx.ErrAccountLocked
+	case StatusPending:
+		return errorx.ErrAccountPending
+	default:
+		return errorx.ErrAccountDisabled
+	}
+}
+
+// Activate activates a pending account or allows a disabled or locked account to log in again.
+//
+// Returns:
+//   - StatusTransition: The transition; unchanged if the user was active already
+//   - error: An error wrapping errorx.ErrInvalidStatusTransition for deleted accounts
+func (u *User) Activate(at time.Time) (StatusTransition, error) {
+	return u.transitionStatus(StatusActive, at)
+}
+
+// Disable prevents the user from logging in until an administrator activates the account again.
+//
+// Returns:
+//   - StatusTransition: The transition; unchanged if the user was disabled already
+//   - error: An error wrapping errorx.ErrInvalidStatusTransition for pending and deleted accounts
+func (u *User) Disable(at time.Time) (StatusTransition, error) {
+	return u.transitionStatus(StatusDisabled, at)
+}
+
+// Lock prevents an active user from logging in, e.g. after repeated failed logins.
+//
+// Returns:
+//   - StatusTransition: The transition; unchanged if the user was locked already
+//   - error: An error wrapping errorx.ErrInvalidStatusTransition unless the account is active
+func (u *User) Lock(at time.Time) (StatusTransition, error) {
+	return u.transitionStatus(StatusLocked, at)
+}
+
+// Unlock allows a locked user to log in again.
+//
+// Returns:
+//   - StatusTransition: The transition; unchanged if the user was active already
+//   - error: An error wrapping errorx.ErrInvalidStatusTransition unless the account is locked or active
+func (u *User) Unlock(at time.Time) (StatusTransition, error) {
+	if u.Status != StatusLocked && u.Status != StatusActive {
+		return StatusTransition{}, errorx.ErrInvalidStatusTransition.Detailf("%s is not locked", u.Status)
+	}
+	return u.transitionStatus(StatusActive, at)
+}
+
+// Delete marks the account as deleted. Deleted accounts cannot change their status anymore.
+//
+// Returns:
+//   - StatusTransition: The transition; unchanged if the user was deleted already
+//   - error: nil, every status may change to DELETED
+func (u *User) Delete(at time.Time) (StatusTransition, error) {
+	return u.transitionStatus(StatusDeleted, at)
+}
+
+// HasRole reports whether the user holds the role.
+func (u User) HasRole(role Role) bool {
+	for _, r := range u.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// AssignRoles replaces the roles of the user. Duplicates are dropped and the order is normalized.
+//
+// Parameters:
+//   - roles: The new roles; at least one, each known to the role registry
+//   - known: The roles known to the application
+//
+// Returns:
+//   - bool: true if the roles changed, false if the user held exactly these roles already; a change increases the token version
+//   - error: errorx.ErrUnknownRole if a role does not exist or no role is given
+func (u *User) AssignRoles(roles []Role, known RolePermissions) (bool, error) {
+	for _, role := range roles {
+		if !known.Exists(role) {
+			return false, errorx.ErrUnknownRole
+		}
+	}
+	normalized := uniqueRoles(roles)
+	if len(normalized) == 0 {
+		return false, errorx.ErrUnknownRole
+	}
+
+	if len(normalized) == len(u.Roles) {
+		same := true
+		for i := range normalized {
+			if normalized[i] != u.Roles[i] {
+				same = false
+				break
+			}
+		}
+		if same {
+			return false, nil
+		}
+	}
+	u.Roles = normalized
+	u.TokenVersion++
+	return true, nil
+}
+
+// GrantRole adds a role to the roles of the user.
+//
+// Parameters:
+//   - role: The role to add
+//   - known: The roles known to the application
+//
+// Returns:
+//   - bool: true if the roles changed, false if the user held the role already
+//   - error: errorx.ErrUnknownRole if the role does not exist
+func (u *User) GrantRole(role Role, known RolePermissions) (bool, error) {
+	return u.AssignRoles(append(slices.Clone(u.Roles), role), known)
+}
+
+// RevokeRole removes a role from the roles of the user.
+//
+// Parameters:
+//   - role: The role to remove
+//   - known: The roles known to the application
+//
+// Returns:
+//   - bool: true if the roles changed, false if the user did not hold the role
+//   - error: errorx.ErrRoleRequired if the role is the only role of the user
+func (u *User) RevokeRole(role Role, known RolePermissions) (bool, error) {
+	if !u.HasRole(role) {
+		return false, nil
+	}
+	remaining := slices.DeleteFunc(slices.Clone(u.Roles), func(r Role) bool { return r == role })
+	if len(remaining) == 0 {
+		return false, errorx.ErrRoleRequired
+	}
+	return u.AssignRoles(remaining, known)
+}
+
+// Rename replaces the username, which has to satisfy the username policy already.
+//
+// Parameters:
+//   - username: The new username
+//
+// Returns:
+//   - Username: The previous username
+//   - bool: true if the username changed
+func (u *User) Rename(username Username) (Username, bool) {
+	previous := u.Username
+	u.Username = username
+	return previous, previous != username
+}
+
+// Canonicalize derives the canonical username, aliases and email of the user. It has to be called
+// after the username, the aliases or the email changed.
+func (u *User) Canonicalize(c Canonicalizer) {
+	u.CanonicalUsername = c.Username(u.Username)
+	u.CanonicalEmail = c.Email(u.Email)
+	u.CanonicalAliases = nil
+	for _, alias := range u.Aliases {
+		u.CanonicalAliases = append(u.CanonicalAliases, c.Username(alias))
+	}
+}
+
+// ChangeEmail replaces the email address of the user. The zero Email removes it.
+func (u *User) ChangeEmail(email Email) {
+	u.Email = email
+}
+
+// SetTemporaryPassword replaces the password with one chosen by an administrator. The user has to
+// replace it with ChangePassword before logging in.
+func (u *User) SetTemporaryPassword(password HashedPassword) {
+	u.Password = password
+	u.MustChangePassword = true
+}
+
+// ChangePassword replaces the password with one chosen by the user, which satisfies the password
+// policy already.
+func (u *User) ChangePassword(password HashedPassword) {
+	u.Password = password
+	u.MustChangePassword = false
+}
+
+// RecordLogin records the time of a successful login.
+func (u *User) RecordLogin(at time.Time) {
+	u.LastLoginAt = at
+}
+
+// WithoutPassword returns a copy of the user without the password hash and the secrets of its
+// second factors, for handing it out of the core.
+func (u User) WithoutPassword() User {
+	u.Password = HashedPassword{}
+	factors := make([]MfaFactor, 0, len(u.MfaFactors))
+	for _, factor := range u.MfaFactors {
+		factors = append(factors, factor.WithoutSecret())
+	}
+	u.MfaFactors = factors
+	return u
 }
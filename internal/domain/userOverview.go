@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"time"
+)
+
+// UserOverview is a denormalized, read-optimized view of a user for administrative queries.
+//
+// It is maintained by a projection from domain events and is never written by the use cases directly.
+type UserOverview struct {
+	ID          string    `classification:"operational"`
+	TenantID    string    `classification:"operational"`
+	Username    string    `classification:"pii"`
+	Status      string    `classification:"operational"`
+	Roles       []string  `classification:"operational"`
+	CreatedAt   time.Time `classification:"operational"`
+	LastLoginAt time.Time `classification:"operational"`
+	DeviceCount int       `classification:"operational"`
+	MfaEnabled  bool      `classification:"operational"`
+}
+
+// UserOverviewUpdate describes a partial change to a UserOverview.
+// Nil fields are left untouched.
+type UserOverviewUpdate struct {
+	Username         *string
+	Status           *string
+	Roles            []string
+	LastLoginAt      *time.Time
+	DeviceCountDelta int
+	MfaEnabled       *bool
+}
+
+// UserOverviewFilter selects, sorts and pages user overviews.
+type UserOverviewFilter struct {
+	// Spec selects the users; nil selects every user.
+	Spec   UserSpecification
+	SortBy string
+	Desc   bool
+	Offset int64
+	Limit  int64
+}
@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// UserSpecification selects users by their overview.
+//
+// Specifications are composed with And, Or and Not and evaluated in memory with IsSatisfiedBy.
+// Persistence adapters translate them into their native query language instead, so use cases can
+// express filters without depending on a database. A nil specification selects every user.
+type UserSpecification interface {
+	// IsSatisfiedBy reports whether the user is selected.
+	IsSatisfiedBy(user UserOverview) bool
+	// userSpecification seals the interface, so adapters can rely on knowing every specification.
+	userSpecification()
+}
+
+// ByStatus selects users with the given account status.
+type ByStatus struct {
+	Status AccountStatus
+}
+
+// IsSatisfiedBy reports whether the user has the status.
+func (s ByStatus) IsSatisfiedBy(user UserOverview) bool {
+	return user.Status == string(s.Status)
+}
+
+func (ByStatus) userSpecification() {}
+
+// ByRole selects users holding the given role.
+type ByRole struct {
+	Role Role
+}
+
+// IsSatisfiedBy reports whether the user holds the role.
+func (s ByRole) IsSatisfiedBy(user UserOverview) bool {
+	return slices.Contains(user.Roles, string(s.Role))
+}
+
+func (ByRole) userSpecification() {}
+
+// CreatedBetween selects users registered from From (inclusive) until To (exclusive).
+// A zero bound leaves that side open.
+type CreatedBetween struct {
+	From time.Time
+	To   time.Time
+}
+
+// IsSatisfiedBy reports whether the user was registered within the period.
+func (s CreatedBetween) IsSatisfiedBy(user UserOverview) bool {
+	return (s.From.IsZero() || !user.CreatedAt.Before(s.From)) && (s.To.IsZero() || user.CreatedAt.Before(s.To))
+}
+
+func (CreatedBetween) userSpecification() {}
+
+// SearchTerm selects users whose username starts with the term, ignoring case.
+type SearchTerm struct {
+	Term string
+}
+
+// IsSatisfiedBy reports whether the username starts with the term.
+func (s SearchTerm) IsSatisfiedBy(user UserOverview) bool {
+	return strings.HasPrefix(strings.ToLower(user.Username), strings.ToLower(s.Term))
+}
+
+func (SearchTerm) userSpecification() {}
+
+// AndSpecification selects users satisfying all of its specifications.
+type AndSpecification struct {
+	Specs []UserSpecification
+}
+
+// IsSatisfiedBy reports whether the user satisfies every specification.
+func (s AndSpecification) IsSatisfiedBy(user UserOverview) bool {
+	for _, spec := range s.Specs {
+		if !spec.IsSatisfiedBy(user) {
+			return false
+		}
+	}
+	return true
+}
+
+func (AndSpecification) userSpecification() {}
+
+// OrSpecification selects users satisfying at least one of its specifications.
+type OrSpecification struct {
+	Specs []UserSpecification
+}
+
+// IsSatisfiedBy reports whether the user satisfies any specification.
+func (s OrSpecification) IsSatisfiedBy(user UserOverview) bool {
+	for _, spec := range s.Specs {
+		if spec.IsSatisfiedBy(user) {
+			return true
+		}
+	}
+	return false
+}
+
+func (OrSpecification) userSpecification() {}
+
+// NotSpecification selects users not satisfying its specification.
+type NotSpecification struct {
+	Spec UserSpecification
+}
+
+// IsSatisfiedBy reports whether the user does not satisfy the specification.
+func (s NotSpecification) IsSatisfiedBy(user UserOverview) bool {
+	return !s.Spec.IsSatisfiedBy(user)
+}
+
+func (NotSpecification) userSpecification() {}
+
+// And combines specifications so that all of them have to be satisfied. Nil specifications are
+// skipped, so optional filters can be passed unconditionally.
+//
+// Parameters:
+//   - specs: The specifications to combine
+//
+// Returns:
+//   - UserSpecification: The combination, the only specification if there is just one, or nil if there is none
+func And(specs ...UserSpecification) UserSpecification {
+	specs = slices.DeleteFunc(slices.Clone(specs), func(spec UserSpecification) bool { return spec == nil })
+	switch len(specs) {
+	case 0:
+		return nil
+	case 1:
+		return specs[0]
+	}
+	return AndSpecification{specs}
+}
+
+// Or combines specifications so that one of them has to be satisfied. Nil specifications are skipped.
+//
+// Parameters:
+//   - specs: The specifications to combine
+//
+// Returns:
+//   - UserSpecification: The combination, the only specification if there is just one, or nil if there is none
+func Or(specs ...UserSpecification) UserSpecification {
+	specs = slices.DeleteFunc(slices.Clone(specs), func(spec UserSpecification) bool { return spec == nil })
+	switch len(specs) {
+	case 0:
+		return nil
+	case 1:
+		return specs[0]
+	}
+	return OrSpecification{specs}
+}
+
+// Not negates a specification.
+//
+// Parameters:
+//   - spec: The specification to negate
+//
+// Returns:
+//   - UserSpecification: The negation
+func Not(spec UserSpecification) UserSpecification {
+	return NotSpecification{spec}
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"golang.org/x/text/unicode/norm"
+	"strings"
+)
+
+// Username lengths of the DefaultUsernamePolicy.
+const (
+	MinUsernameLength = 3
+	MaxUsernameLength = 32
+)
+
+// Username is a normalized username. The zero value is no username.
+//
+// Usernames are trimmed, converted to Unicode NFC and lowercased, so "Alice " and "alice"
+// denote the same account.
+type Username struct {
+	value string
+}
+
+// NewUsername normalizes a new username and checks it against the DefaultUsernamePolicy.
+//
+// Parameters:
+//   - raw: The username as entered by the user
+//
+// Returns:
+//   - Username: The normalized username
+//   - error: errorx.ErrInvalidUsername wrapped with the violated rule
+func NewUsername(raw string) (Username, error) {
+	return DefaultUsernamePolicy.Validate(raw)
+}
+
+// NormalizeUsername normalizes a username without checking the username rules. It is used to
+// look up existing accounts, which may predate the current rules.
+//
+// Parameters:
+//   - raw: The username as entered by the user or taken from a token
+//
+// Returns:
+//   - Username: The normalized username
+func NormalizeUsername(raw string) Username {
+	return Username{strings.ToLower(norm.NFC.String(strings.TrimSpace(raw)))}
+}
+
+// RestoreUsername wraps a username read from storage, which was normalized when it was stored.
+// It is meant for persistence adapters only.
+func RestoreUsername(stored string) Username {
+	return Username{stored}
+}
+
+// String returns the normalized username.
+func (u Username) String() string {
+	return u.value
+}
+
+// IsZero reports whether the username is empty.
+func (u Username) IsZero() bool {
+	return u.value == ""
+}
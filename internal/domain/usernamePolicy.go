@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// DefaultReservedUsernames are names that could be mistaken for the operators of the service.
+var DefaultReservedUsernames = []string{
+	"abuse", "admin", "administrator", "api", "help", "hostmaster", "info", "mailer-daemon", "moderator",
+	"noreply", "no-reply", "null", "operator", "postmaster", "root", "security", "support", "system", "webmaster",
+}
+
+// UsernamePolicy holds the rules new usernames have to satisfy, at registration and on rename.
+// Existing usernames are never checked, so tightening the rules does not lock anybody out.
+type UsernamePolicy struct {
+	MinLength int
+	MaxLength int
+	// Punctuation lists the characters allowed besides the letters a-z and the digits. A username
+	// always starts with a letter or digit.
+	Punctuation string
+	// Reserved lists names nobody may register. They also match with punctuation, e.g. "ad.min".
+	Reserved []string
+	// Blocked lists words, e.g. profanity, no username may contain.
+	Blocked []string
+}
+
+// DefaultUsernamePolicy allows 3 to 32 letters, digits, dots, dashes and underscores and reserves the DefaultReservedUsernames.
+var DefaultUsernamePolicy = UsernamePolicy{
+	MinLength:   MinUsernameLength,
+	MaxLength:   MaxUsernameLength,
+	Punctuation: "._-",
+	Reserved:    DefaultReservedUsernames,
+}
+
+// Validate normalizes a new username and checks it against the policy.
+//
+// Parameters:
+//   - raw: The username as entered by the user
+//
+// Returns:
+//   - Username: The normalized username
+//   - error: errorx.ErrInvalidUsername with the violated rule as detail
+func (p UsernamePolicy) Validate(raw string) (Username, error) {
+	username := NormalizeUsername(raw)
+
+	length := utf8.RuneCountInString(username.value)
+	switch {
+	case length < p.MinLength:
+		return Username{}, errorx.ErrInvalidUsername.Detailf("must have at least %d characters", p.MinLength)
+	case length > p.MaxLength:
+		return Username{}, errorx.ErrInvalidUsername.Detailf("must not exceed %d characters", p.MaxLength)
+	case !p.allowedCharacters(username.value):
+		return Username{}, errorx.ErrInvalidUsername.Detailf("may only contain letters, digits%s and must start with a letter or digit", p.describePunctuation())
+	case p.reserved(username.value):
+		return Username{}, errorx.ErrInvalidUsername.Detailf("is reserved")
+	case p.blocked(username.value):
+		return Username{}, errorx.ErrInvalidUsername.Detailf("contains a word that is not allowed")
+	}
+	return username, nil
+}
+
+// allowedCharacters reports whether the normalized username only consists of the allowed characters
+// and starts with a letter or digit.
+func (p UsernamePolicy) allowedCharacters(username string) bool {
+	for i, c := range username {
+		if isUsernameAlphanumeric(c) {
+			continue
+		}
+		if i == 0 || !strings.ContainsRune(p.Punctuation, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// reserved reports whether the username equals a reserved name, ignoring punctuation.
+func (p UsernamePolicy) reserved(username string) bool {
+	stripped := stripUsernamePunctuation(username)
+	for _, name := range p.Reserved {
+		if stripped == stripUsernamePunctuation(NormalizeUsername(name).value) {
+			return true
+		}
+	}
+	return false
+}
+
+// blocked reports whether the username contains a blocked word, ignoring punctuation.
+func (p UsernamePolicy) blocked(username string) bool {
+	stripped := stripUsernamePunctuation(username)
+	for _, word := range p.Blocked {
+		word = stripUsernamePunctuation(NormalizeUsername(word).value)
+		if word != "" && strings.Contains(stripped, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// describePunctuation lists the allowed punctuation for error messages, e.g. ", '.', '-' and '_'".
+func (p UsernamePolicy) describePunctuation() string {
+	quoted := make([]string, 0, len(p.Punctuation))
+	for _, c := range p.Punctuation {
+		quoted = append(quoted, fmt.Sprintf("'%c'", c))
+	}
+	switch len(quoted) {
+	case 0:
+		return ""
+	case 1:
+		return " and " + quoted[0]
+	}
+	return ", " + strings.Join(quoted[:len(quoted)-1], ", ") + " and " + quoted[len(quoted)-1]
+}
+
+// isUsernameAlphanumeric reports whether c is a letter a-z or a digit.
+func isUsernameAlphanumeric(c rune) bool {
+	return c >= 'a' && c <= 'z' || c >= '0' && c <= '9'
+}
+
+// stripUsernamePunctuation removes everything but letters and digits.
+func stripUsernamePunctuation(username string) string {
+	return strings.Map(func(c rune) rune {
+		if isUsernameAlphanumeric(c) {
+			return c
+		}
+		return -1
+	}, username)
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"time"
+)
+
+// WebhookAllEvents subscribes a webhook to every event.
+const WebhookAllEvents = "*"
+
+// WebhookSubscription is an external endpoint that is notified about domain events.
+//
+// Deliveries are signed with the secret, so the receiver can verify that they originate
+// from this service. An empty event list is treated like WebhookAllEvents. A subscription
+// belongs to a tenant and only receives the events of that tenant.
+type WebhookSubscription struct {
+	ID        string
+	TenantID  string
+	URL       string
+	Secret    string
+	Events    []string
+	CreatedAt time.Time
+}
+
+// Subscribes reports whether the subscription wants to receive events of the given name.
+//
+// Parameters:
+//   - eventName: The name of the event, e.g. "user.registered"
+//
+// Returns:
+//   - bool: true if the event has to be delivered
+func (ws WebhookSubscription) Subscribes(eventName string) bool {
+	if len(ws.Events) == 0 {
+		return true
+	}
+	for _, e := range ws.Events {
+		if e == WebhookAllEvents || e == eventName {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeadLetter is an event delivery that was given up after its retries were exhausted.
+type WebhookDeadLetter struct {
+	ID             string
+	TenantID       string
+	SubscriptionID string
+	URL            string
+	EventName      string
+	Payload        []byte
+	Attempts       int
+	LastError      string
+	FailedAt       time.Time
+}
@@ -0,0 +1,49 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Component returns the logger of a part of the service, e.g. "scheduler". Its records carry a
+// "component" attribute and are written by the default logger at the time of writing, so loggers
+// declared in package variables use the logger installed at startup.
+func Component(name string) *slog.Logger {
+	return slog.New(&componentHandler{attrs: []slog.Attr{slog.String("component", name)}})
+}
+
+// componentHandler passes records on to the handler of the default logger.
+type componentHandler struct {
+	attrs []slog.Attr
+	group string
+	// parent is the handler the group was opened on, nil unless group is set.
+	parent *componentHandler
+}
+
+// resolve returns the default handler with the attributes and groups of the component applied.
+func (h *componentHandler) resolve() slog.Handler {
+	if h.parent == nil {
+		return slog.Default().Handler().WithAttrs(h.attrs)
+	}
+	return h.parent.resolve().WithGroup(h.group).WithAttrs(h.attrs)
+}
+
+// Enabled asks the default handler.
+func (h *componentHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return slog.Default().Handler().Enabled(ctx, level)
+}
+
+// Handle writes the record with the default handler.
+func (h *componentHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.resolve().Handle(ctx, record)
+}
+
+// WithAttrs adds attributes to the records of the component.
+func (h *componentHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &componentHandler{attrs: append(append([]slog.Attr{}, h.attrs...), attrs...), group: h.group, parent: h.parent}
+}
+
+// WithGroup nests the following attributes of the component in a group.
+func (h *componentHandler) WithGroup(name string) slog.Handler {
+	return &componentHandler{group: name, parent: h}
+}
@@ -0,0 +1,106 @@
+package logging
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// LevelState is the current level of a LevelSwitch.
+type LevelState struct {
+	Level slog.Level
+	// Base is the configured level the switch reverts to.
+	Base slog.Level
+	// RevertAt is the time a temporary level ends, zero if the configured level applies.
+	RevertAt time.Time
+}
+
+// LevelSwitch changes the level of a logger at runtime, e.g. to debug an incident without a
+// redeployment. A changed level is temporary and reverts to the configured level after a while,
+// so a forgotten debug level does not flood the logs.
+type LevelSwitch struct {
+	level *slog.LevelVar
+
+	mu       sync.Mutex
+	base     slog.Level
+	revertAt time.Time
+	timer    *time.Timer
+	// changes counts the changes, so a revert that fires after a newer change does nothing.
+	changes int
+}
+
+// NewLevelSwitch creates a new LevelSwitch controlling a level and starting at the configured level.
+//
+// Parameters:
+//   - level: The level of the logger, e.g. the one passed to New
+//   - base: The configured level
+//
+// Returns:
+//   - *LevelSwitch: A pointer to the newly created LevelSwitch
+func NewLevelSwitch(level *slog.LevelVar, base slog.Level) *LevelSwitch {
+	level.Set(base)
+	return &LevelSwitch{level: level, base: base}
+}
+
+// Get returns the current state.
+func (ls *LevelSwitch) Get() LevelState {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	return LevelState{Level: ls.level.Level(), Base: ls.base, RevertAt: ls.revertAt}
+}
+
+// Set changes the level until the duration has passed, replacing an earlier temporary level.
+//
+// Parameters:
+//   - level: The new level
+//   - duration: How long the level applies before reverting to the configured level, zero keeps it
+//     until the next change
+func (ls *LevelSwitch) Set(level slog.Level, duration time.Duration) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	ls.stopTimer()
+	ls.changes++
+	ls.level.Set(level)
+	if duration <= 0 {
+		return
+	}
+	change := ls.changes
+	ls.revertAt = time.Now().Add(duration)
+	ls.timer = time.AfterFunc(duration, func() { ls.revert(change) })
+}
+
+// SetBase changes the configured level, e.g. on a configuration reload. It applies at once unless a
+// temporary level is active, which then reverts to the new configured level.
+func (ls *LevelSwitch) SetBase(level slog.Level) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	ls.base = level
+	if ls.revertAt.IsZero() {
+		ls.level.Set(level)
+	}
+}
+
+// revert restores the configured level once a temporary level ends, unless the level changed since.
+func (ls *LevelSwitch) revert(change int) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	if change != ls.changes {
+		return
+	}
+	ls.timer = nil
+	ls.revertAt = time.Time{}
+	ls.level.Set(ls.base)
+	Component("logging").Warn("Reverted the log level", "level", ls.base)
+}
+
+// stopTimer cancels the revert of a temporary level.
+func (ls *LevelSwitch) stopTimer() {
+	if ls.timer != nil {
+		ls.timer.Stop()
+		ls.timer = nil
+	}
+	ls.revertAt = time.Time{}
+}
@@ -0,0 +1,187 @@
+// Package logging sets up the structured logger of the service.
+//
+// Every record passes a handler that adds the request id and the user of the request from the
+// context and redacts credentials: attributes whose names suggest a password, token, secret or
+// hash are replaced, and values that look like tokens, password hashes or URLs with passwords are
+// masked wherever they appear, including the message. The redaction cannot be turned off.
+//
+// Records at error level that carry an "error" attribute mark unexpected errors; they are also
+// sent to the error reporter of the options, redacted in the same way.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"user-auth-hexagonal-architecture/internal/ports/telemetry"
+	"user-auth-hexagonal-architecture/internal/requestid"
+)
+
+// Format names the encoding of log records.
+type Format string
+
+const (
+	// FormatText writes key=value pairs, readable in a terminal.
+	FormatText Format = "text"
+	// FormatJSON writes one JSON object per record, for log shippers.
+	FormatJSON Format = "json"
+)
+
+// ParseFormat validates the name of a format.
+//
+// Parameters:
+//   - value: "text" or "json"
+//
+// Returns:
+//   - Format: The format
+//   - error: An error if the format is unknown
+func ParseFormat(value string) (Format, error) {
+	switch Format(value) {
+	case FormatText, FormatJSON:
+		return Format(value), nil
+	}
+	return "", fmt.Errorf("unknown log format %q", value)
+}
+
+// Options configures the logger.
+type Options struct {
+	Format Format
+	// Level is the minimum level of the records written, e.g. a *slog.LevelVar to change it at runtime.
+	Level slog.Leveler
+	// Reporter receives the unexpected errors logged, nil to only log them.
+	Reporter telemetry.ErrorReporterPort
+}
+
+// New creates a logger writing redacted records with the request context to w.
+//
+// Parameters:
+//   - w: The writer receiving the records, usually os.Stderr
+//   - options: The format and level of the records and the error reporter
+//
+// Returns:
+//   - *slog.Logger: The logger, to be installed with slog.SetDefault
+//   - error: An error if the format is unknown
+func New(w io.Writer, options Options) (*slog.Logger, error) {
+	format, err := ParseFormat(string(options.Format))
+	if err != nil {
+		return nil, err
+	}
+	handlerOptions := &slog.HandlerOptions{Level: options.Level}
+	var handler slog.Handler = slog.NewTextHandler(w, handlerOptions)
+	if format == FormatJSON {
+		handler = slog.NewJSONHandler(w, handlerOptions)
+	}
+	return slog.New(&contextHandler{next: handler, reporter: options.Reporter}), nil
+}
+
+// userKey is the context key under which the user of a request is stored.
+type userKey struct{}
+
+// WithUser returns a copy of ctx whose log records carry the given user, once the request is authenticated.
+func WithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// reportTags are the attributes of an unexpected error that become searchable tags of its report.
+var reportTags = []string{"component", "method", "route", "use_case"}
+
+// contextHandler adds the request id and user from the context to records and redacts them before
+// passing them to the wrapped handler.
+type contextHandler struct {
+	next     slog.Handler
+	reporter telemetry.ErrorReporterPort
+	// attrs are the redacted attributes added to the handler, with the keys prefixed by their groups.
+	attrs []slog.Attr
+	// group is the prefix of the keys of following attributes, e.g. "request." inside group "request".
+	group string
+}
+
+// Enabled reports whether the wrapped handler writes records of the level.
+func (h *contextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle redacts the message and attributes of a record and adds the request id and user, unless
+// the record carries them already.
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	redactedRecord := slog.NewRecord(record.Time, record.Level, Scrub(record.Message), record.PC)
+	var keys []string
+	record.Attrs(func(attr slog.Attr) bool {
+		keys = append(keys, attr.Key)
+		redactedRecord.AddAttrs(redact(attr))
+		return true
+	})
+	if id := requestid.FromContext(ctx); id != "" && !slices.Contains(keys, "request_id") {
+		redactedRecord.AddAttrs(slog.String("request_id", id))
+	}
+	if user, _ := ctx.Value(userKey{}).(string); user != "" && !slices.Contains(keys, "user_id") {
+		redactedRecord.AddAttrs(slog.String("user_id", user))
+	}
+	if h.reporter != nil && record.Level >= slog.LevelError {
+		h.report(ctx, redactedRecord)
+	}
+	return h.next.Handle(ctx, redactedRecord)
+}
+
+// report sends a redacted record to the error reporter if it carries an "error" attribute.
+func (h *contextHandler) report(ctx context.Context, record slog.Record) {
+	attrs := slices.Clone(h.attrs)
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = flatten(attrs, h.group, attr)
+		return true
+	})
+
+	report := telemetry.ErrorReport{Message: record.Message, Tags: map[string]string{}, Context: map[string]string{}}
+	found := false
+	for _, attr := range attrs {
+		// the attributes of the record and those added by the handler are nested in the open groups
+		key, value := strings.TrimPrefix(attr.Key, h.group), attr.Value.String()
+		switch {
+		case key == "error":
+			report.Error, found = value, true
+		case key == "request_id":
+			report.RequestID = value
+		case key == "user_id":
+			report.UserID = value
+		case slices.Contains(reportTags, key):
+			report.Tags[key] = value
+		default:
+			report.Context[attr.Key] = value
+		}
+	}
+	if found {
+		h.reporter.ReportError(ctx, report)
+	}
+}
+
+// flatten appends an attribute to attrs, the members of groups with the keys prefixed by the group names.
+func flatten(attrs []slog.Attr, prefix string, attr slog.Attr) []slog.Attr {
+	if attr.Value.Kind() != slog.KindGroup {
+		return append(attrs, slog.Attr{Key: prefix + attr.Key, Value: attr.Value})
+	}
+	for _, member := range attr.Value.Group() {
+		attrs = flatten(attrs, prefix+attr.Key+".", member)
+	}
+	return attrs
+}
+
+// WithAttrs redacts the attributes before adding them to the wrapped handler.
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redactedAttrs := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redactedAttrs[i] = redact(attr)
+	}
+	flattened := slices.Clone(h.attrs)
+	for _, attr := range redactedAttrs {
+		flattened = flatten(flattened, h.group, attr)
+	}
+	return &contextHandler{h.next.WithAttrs(redactedAttrs), h.reporter, flattened, h.group}
+}
+
+// WithGroup opens a group in the wrapped handler.
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{h.next.WithGroup(name), h.reporter, h.attrs, h.group + name + "."}
+}
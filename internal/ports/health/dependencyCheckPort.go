@@ -0,0 +1,15 @@
+package health
+
+import (
+	"context"
+)
+
+// DependencyCheckPort is a secondary (driven) port reporting whether a dependency the application needs to serve requests is usable
+type DependencyCheckPort interface {
+	// Name identifies the dependency in readiness reports.
+	Name() string
+	// Optional reports whether the application can still serve requests, possibly degraded, without the dependency.
+	Optional() bool
+	// Check returns an error if the dependency is not usable.
+	Check(ctx context.Context) error
+}
@@ -0,0 +1,23 @@
+// Package hooks contains the ports through which integrators extend the use cases of the core
+// layer without changing them, e.g. to check or enrich registrations.
+package hooks
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// RegistrationInterceptorPort is a secondary (driven) port through which the core layer runs
+// integrator code around the registration of a user, such as email domain allowlists, the
+// enrollment in a CRM or a welcome email. Interceptors run in the order they are configured.
+type RegistrationInterceptorPort interface {
+	// Name identifies the interceptor in logs, e.g. "email-domain-allowlist".
+	Name() string
+	// BeforeRegistration runs before the user is saved. The user has no id and no password hash yet.
+	// An error aborts the registration; errorx.ErrRegistrationRejected with a detail tells the
+	// client why, other errors are reported as internal errors.
+	BeforeRegistration(ctx context.Context, user domain.User) error
+	// AfterRegistration runs after the user was saved. Errors are logged and do not undo the
+	// registration, so interceptors have to tolerate being skipped.
+	AfterRegistration(ctx context.Context, user domain.User) error
+}
@@ -0,0 +1,25 @@
+package messaging
+
+import (
+	"context"
+	"net/http"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// EmailSenderPort is a secondary (driven) port that hands rendered emails to an email provider,
+// e.g. an SMTP server or the API of SendGrid
+type EmailSenderPort interface {
+	// Provider identifies the provider in logs and circuit breakers, e.g. "sendgrid".
+	Provider() string
+	SendEmail(ctx context.Context, message domain.EmailMessage) error
+}
+
+// EmailFeedbackSourcePort is a secondary (driven) port that authenticates and decodes the bounce and
+// complaint callbacks of an email provider
+type EmailFeedbackSourcePort interface {
+	// Provider identifies the provider the callbacks are from, e.g. "ses".
+	Provider() string
+	// ParseFeedback returns the feedback of a callback, errorx.ErrInvalidEmailFeedback if it is not
+	// authentic or malformed. Callbacks without bounces or complaints yield no feedback.
+	ParseFeedback(ctx context.Context, header http.Header, body []byte) ([]domain.EmailFeedback, error)
+}
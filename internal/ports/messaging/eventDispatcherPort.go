@@ -0,0 +1,18 @@
+// Package messaging contains the ports for distributing domain events.
+package messaging
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+)
+
+// EventDispatcherPort is a secondary (driven) port through which services emit domain events
+// without knowing who consumes them
+type EventDispatcherPort interface {
+	Dispatch(ctx context.Context, event events.Event)
+}
+
+// EventHandler consumes domain events delivered by an EventDispatcherPort implementation
+type EventHandler interface {
+	Handle(ctx context.Context, event events.Event) error
+}
@@ -0,0 +1,15 @@
+package messaging
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// EventPublisherPort is a secondary (driven) port that publishes recorded domain events to a message broker
+type EventPublisherPort interface {
+	// Name identifies the broker in logs and metrics, e.g. "kafka".
+	Name() string
+	// Publish delivers a batch of messages. It returns once the broker has acknowledged all of them,
+	// or an error if any of them may not have been stored. The batch is published again then.
+	Publish(ctx context.Context, messages []domain.OutboxMessage) error
+}
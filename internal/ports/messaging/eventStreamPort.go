@@ -0,0 +1,13 @@
+package messaging
+
+import (
+	"user-auth-hexagonal-architecture/internal/domain/events"
+)
+
+// EventStreamPort is a primary (driving) port through which adapters follow domain events as they happen
+type EventStreamPort interface {
+	// SubscribeEvents returns a channel receiving every event of the tenant dispatched from now on and
+	// a function ending the subscription. Events are dropped for subscribers that fall more than buffer
+	// events behind.
+	SubscribeEvents(tenantID string, buffer int) (<-chan events.Event, func())
+}
@@ -0,0 +1,13 @@
+package messaging
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// NotificationChannelPort is a secondary (driven) port that delivers notifications to users on one
+// channel, e.g. email or SMS. Implementations render the message from the topic and the details.
+type NotificationChannelPort interface {
+	Channel() domain.NotificationChannel
+	Send(ctx context.Context, notification domain.Notification) error
+}
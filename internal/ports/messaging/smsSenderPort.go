@@ -0,0 +1,14 @@
+package messaging
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// SMSSenderPort is a secondary (driven) port that hands rendered SMS to an SMS provider,
+// e.g. the API of Twilio or an HTTP gateway
+type SMSSenderPort interface {
+	// Provider identifies the provider in logs and circuit breakers, e.g. "twilio".
+	Provider() string
+	SendSMS(ctx context.Context, message domain.SMSMessage) error
+}
@@ -0,0 +1,13 @@
+package messaging
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+)
+
+// WebhookDeliveryPort is a secondary (driven) port that delivers domain events to webhook subscriptions.
+// Implementations deliver asynchronously and take care of retries, so Deliver does not block the use case.
+type WebhookDeliveryPort interface {
+	Deliver(ctx context.Context, subscription domain.WebhookSubscription, event events.Event)
+}
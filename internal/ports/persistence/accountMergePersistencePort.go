@@ -0,0 +1,13 @@
+package persistence
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// AccountMergePersistencePort is a secondary (driven) port for storing the outcome of merging two accounts
+type AccountMergePersistencePort interface {
+	// SaveMergedAccounts stores the deleted duplicate before the primary account, so the email
+	// address and username the duplicate gives up are free when the primary account takes them.
+	SaveMergedAccounts(ctx context.Context, merge domain.AccountMerge) error
+}
@@ -0,0 +1,15 @@
+package persistence
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// ConsentPersistencePort is a secondary (driven) port to decouple the core layer from the persistence layer
+type ConsentPersistencePort interface {
+	SaveDocumentVersion(ctx context.Context, version domain.DocumentVersion) error
+	FindCurrentDocumentVersions(ctx context.Context) ([]domain.DocumentVersion, error)
+	AppendConsents(ctx context.Context, consents []domain.Consent) error
+	FindConsents(ctx context.Context, userID string) ([]domain.Consent, error)
+	DeleteConsents(ctx context.Context, userID string) (int64, error)
+}
@@ -0,0 +1,13 @@
+package persistence
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// CredentialEventStorePort is a secondary (driven) port for the append-only credential event stream
+type CredentialEventStorePort interface {
+	AppendCredentialEvent(ctx context.Context, event domain.CredentialEvent) error
+	LoadCredentialEvents(ctx context.Context, username string) ([]domain.CredentialEvent, error)
+	PseudonymizeCredentialEvents(ctx context.Context, username string, pseudonym string) (int64, error)
+}
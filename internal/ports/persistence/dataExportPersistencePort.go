@@ -0,0 +1,26 @@
+package persistence
+
+import (
+	"context"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// DataExportPersistencePort is a secondary (driven) port to decouple the core layer from the persistence layer
+type DataExportPersistencePort interface {
+	SaveDataExport(ctx context.Context, export domain.DataExport) error
+	FindDataExport(ctx context.Context, id string) (domain.DataExport, error)
+	// ClaimPendingDataExport leases the oldest pending export, so no other worker generates it until
+	// the lease expires. Exports whose worker stopped, e.g. in a restart, are claimed again once their
+	// lease expired. It returns errorx.ErrDataExportNotFound if no export is waiting.
+	ClaimPendingDataExport(ctx context.Context, lease time.Duration) (domain.DataExport, error)
+	// SaveDataExportArchive stores the archive of an export apart from the export, as archives can
+	// outgrow the size limit of a document.
+	SaveDataExportArchive(ctx context.Context, export domain.DataExport, archive []byte) error
+	// LoadDataExportArchive returns the archive of an export, errorx.ErrDataExportNotFound if it has none.
+	LoadDataExportArchive(ctx context.Context, id string) ([]byte, error)
+	// DeleteExpiredDataExportArchives removes the archives of the exports that expired before now.
+	DeleteExpiredDataExportArchives(ctx context.Context, now time.Time) (int64, error)
+	// DeleteDataExportsByUser removes all exports of a user together with their archives.
+	DeleteDataExportsByUser(ctx context.Context, userID string) (int64, error)
+}
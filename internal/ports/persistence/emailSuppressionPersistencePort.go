@@ -0,0 +1,14 @@
+package persistence
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// EmailSuppressionPersistencePort is a secondary (driven) port for storing the addresses no emails are sent to
+type EmailSuppressionPersistencePort interface {
+	SaveEmailSuppression(ctx context.Context, suppression domain.EmailSuppression) error
+	IsEmailSuppressed(ctx context.Context, address string) (bool, error)
+	FindEmailSuppressions(ctx context.Context, limit int64) ([]domain.EmailSuppression, error)
+	DeleteEmailSuppression(ctx context.Context, address string) error
+}
@@ -0,0 +1,17 @@
+package persistence
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// GroupPersistencePort is a secondary (driven) port to decouple the core layer from the persistence layer
+type GroupPersistencePort interface {
+	FindGroups(ctx context.Context) ([]domain.Group, error)
+	FindGroup(ctx context.Context, name string) (domain.Group, error)
+	FindGroupsByMember(ctx context.Context, userID string) ([]domain.Group, error)
+	SaveGroup(ctx context.Context, group domain.Group) error
+	DeleteGroup(ctx context.Context, name string) error
+	AddGroupMember(ctx context.Context, name string, userID string) error
+	RemoveGroupMember(ctx context.Context, name string, userID string) error
+}
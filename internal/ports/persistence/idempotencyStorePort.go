@@ -0,0 +1,34 @@
+package persistence
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// IdempotencyStorePort is a secondary (driven) port remembering the outcome of requests sent with an idempotency key
+type IdempotencyStorePort interface {
+	// Reserve claims a key for a request. If the key is new it is stored as in progress and
+	// reserved is true; otherwise the existing record is returned.
+	Reserve(ctx context.Context, key string, fingerprint string, expiresAt time.Time) (record IdempotencyRecord, reserved bool, err error)
+	// Complete stores the response of the request that reserved the key.
+	Complete(ctx context.Context, key string, response StoredResponse) error
+	// Release removes a reservation, so the request can be retried with the same key.
+	Release(ctx context.Context, key string) error
+}
+
+// IdempotencyRecord is the state of an idempotency key.
+type IdempotencyRecord struct {
+	Key         string
+	Fingerprint string
+	Completed   bool
+	Response    StoredResponse
+	ExpiresAt   time.Time
+}
+
+// StoredResponse is a response kept for replay.
+type StoredResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
@@ -0,0 +1,13 @@
+package persistence
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// LoginAuditStorePort is a secondary (driven) port for the audit records of authentication attempts
+type LoginAuditStorePort interface {
+	AppendLoginRecord(ctx context.Context, record domain.LoginRecord) error
+	FindLoginRecords(ctx context.Context, filter domain.LoginRecordFilter) ([]domain.LoginRecord, error)
+	DeleteLoginRecords(ctx context.Context, username string) (int64, error)
+}
@@ -0,0 +1,24 @@
+package persistence
+
+import (
+	"context"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// OutboxPersistencePort is a secondary (driven) port for storing domain events until they are published
+type OutboxPersistencePort interface {
+	// AppendOutboxMessage records a message for publishing.
+	AppendOutboxMessage(ctx context.Context, message domain.OutboxMessage) error
+	// ClaimOutboxMessages leases up to limit pending messages, the oldest first, so no other relay
+	// publishes them until the lease expires.
+	ClaimOutboxMessages(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxMessage, error)
+	// MarkOutboxMessagesPublished completes messages.
+	MarkOutboxMessagesPublished(ctx context.Context, ids []string, at time.Time) error
+	// ReleaseOutboxMessages returns messages after a failed attempt, counting the attempt.
+	ReleaseOutboxMessages(ctx context.Context, ids []string, lastError string) error
+	// FailOutboxMessages gives messages up after their last attempt. They are kept for inspection.
+	FailOutboxMessages(ctx context.Context, ids []string, at time.Time, lastError string) error
+	// CountPendingOutboxMessages returns the number of messages waiting to be published.
+	CountPendingOutboxMessages(ctx context.Context) (int64, error)
+}
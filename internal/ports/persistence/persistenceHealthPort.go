@@ -0,0 +1,35 @@
+package persistence
+
+import (
+	"context"
+)
+
+// PersistenceHealthPort is a secondary (driven) port exposing the health and basic statistics of a persistence backend
+type PersistenceHealthPort interface {
+	Ping(ctx context.Context) error
+	Stats(ctx context.Context) (PersistenceStats, error)
+}
+
+// PersistenceStats summarizes the state of a persistence backend.
+type PersistenceStats struct {
+	Backend     string            `json:"backend"`
+	UserCount   int64             `json:"userCount"`
+	Collections []CollectionStats `json:"collections"`
+	Indexes     []IndexHealth     `json:"indexes"`
+}
+
+// CollectionStats holds size information about a single collection or table.
+type CollectionStats struct {
+	Name           string `json:"name"`
+	DocumentCount  int64  `json:"documentCount"`
+	SizeBytes      int64  `json:"sizeBytes"`
+	StorageBytes   int64  `json:"storageBytes"`
+	IndexSizeBytes int64  `json:"indexSizeBytes"`
+}
+
+// IndexHealth reports whether an index the backend relies on is present.
+type IndexHealth struct {
+	Collection string `json:"collection"`
+	Name       string `json:"name"`
+	Present    bool   `json:"present"`
+}
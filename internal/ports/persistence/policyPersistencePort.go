@@ -0,0 +1,13 @@
+package persistence
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// PolicyPersistencePort is a secondary (driven) port to decouple the core layer from the persistence layer
+type PolicyPersistencePort interface {
+	FindPolicies(ctx context.Context) ([]domain.Policy, error)
+	SavePolicy(ctx context.Context, policy domain.Policy) error
+	DeletePolicy(ctx context.Context, id string) error
+}
@@ -0,0 +1,12 @@
+package persistence
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// ProfilePersistencePort is a secondary (driven) port to decouple the core layer from the persistence layer
+type ProfilePersistencePort interface {
+	FindProfile(ctx context.Context, username domain.Username) (domain.Profile, error)
+	UpdateProfile(ctx context.Context, username domain.Username, profile domain.Profile) error
+}
@@ -0,0 +1,13 @@
+package persistence
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// RolePersistencePort is a secondary (driven) port to decouple the core layer from the persistence layer
+type RolePersistencePort interface {
+	FindRoles(ctx context.Context) ([]domain.RoleDefinition, error)
+	SaveRole(ctx context.Context, role domain.RoleDefinition) error
+	DeleteRole(ctx context.Context, name domain.Role) error
+}
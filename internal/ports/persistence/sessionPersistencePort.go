@@ -0,0 +1,17 @@
+package persistence
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// SessionPersistencePort is a secondary (driven) port to decouple the core layer from the persistence layer
+type SessionPersistencePort interface {
+	SaveSession(ctx context.Context, session domain.Session) error
+	// SaveRefreshedSession stores a session whose refresh token was rotated, unless the stored
+	// session no longer holds the refresh token with the given hash, i.e. a concurrent refresh won.
+	SaveRefreshedSession(ctx context.Context, session domain.Session, previousRefreshTokenHash string) error
+	FindSession(ctx context.Context, id string) (domain.Session, error)
+	FindSessionsByUser(ctx context.Context, userID string) ([]domain.Session, error)
+	DeleteSessionsByUser(ctx context.Context, userID string) (int64, error)
+}
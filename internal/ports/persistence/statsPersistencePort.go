@@ -0,0 +1,17 @@
+package persistence
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// UserStatsPersistencePort is a secondary (driven) port for aggregating the stored users
+type UserStatsPersistencePort interface {
+	CountRegistrationsPerDay(ctx context.Context, period domain.StatsPeriod) ([]domain.DailyCount, error)
+	CountMfaAdoption(ctx context.Context) (domain.MfaAdoption, error)
+}
+
+// LoginStatsPersistencePort is a secondary (driven) port for aggregating the audit records of authentication attempts
+type LoginStatsPersistencePort interface {
+	AggregateLogins(ctx context.Context, period domain.StatsPeriod) (domain.LoginStats, error)
+}
@@ -0,0 +1,13 @@
+package persistence
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// TenantPersistencePort is a secondary (driven) port to decouple the core layer from the persistence layer
+type TenantPersistencePort interface {
+	FindTenants(ctx context.Context) ([]domain.Tenant, error)
+	SaveTenant(ctx context.Context, tenant domain.Tenant) error
+	DeleteTenant(ctx context.Context, id string) error
+}
@@ -0,0 +1,23 @@
+package persistence
+
+import (
+	"context"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// UserAdminPersistencePort is a secondary (driven) port for administrative changes to users identified by id
+type UserAdminPersistencePort interface {
+	FindUserByID(ctx context.Context, id string) (domain.User, error)
+	SearchUsers(ctx context.Context, term string, offset int64, limit int64) ([]domain.User, int64, error)
+	UpdateUsername(ctx context.Context, id string, username domain.Username, canonicalUsername string) error
+	UpdateUserRoles(ctx context.Context, id string, roles []domain.Role, tokenVersion int) error
+	CountActiveUsersWithRole(ctx context.Context, role domain.Role) (int64, error)
+	UpdateUserStatus(ctx context.Context, id string, status domain.AccountStatus) error
+	UpdateUserLock(ctx context.Context, id string, reason domain.LockReason, until time.Time) error
+	FindUsersWithExpiredLocks(ctx context.Context, now time.Time) ([]domain.User, error)
+	// SoftDeleteUser marks a user as deleted and removes its MFA factors in the same update.
+	SoftDeleteUser(ctx context.Context, id string, deletedAt time.Time) error
+	UpdatePassword(ctx context.Context, id string, password domain.HashedPassword, mustChangePassword bool) error
+	UpdateMfa(ctx context.Context, id string, factors []domain.MfaFactor, mfaEnabled bool) error
+}
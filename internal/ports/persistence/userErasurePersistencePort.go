@@ -0,0 +1,20 @@
+package persistence
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// UserErasurePersistencePort is a secondary (driven) port for erasing users identified by id,
+// including soft-deleted and archived users
+type UserErasurePersistencePort interface {
+	FindUserForErasure(ctx context.Context, id string) (domain.User, error)
+	UpdateLegalHold(ctx context.Context, id string, legalHold bool) error
+	EraseUser(ctx context.Context, id string) (int64, error)
+}
+
+// ErasureCertificatePersistencePort is a secondary (driven) port for storing erasure certificates
+type ErasureCertificatePersistencePort interface {
+	SaveErasureCertificate(ctx context.Context, certificate domain.ErasureCertificate) error
+	FindErasureCertificate(ctx context.Context, id string) (domain.ErasureCertificate, error)
+}
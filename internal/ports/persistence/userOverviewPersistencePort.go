@@ -0,0 +1,14 @@
+package persistence
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// UserOverviewPersistencePort is a secondary (driven) port for the denormalized user overview read model
+type UserOverviewPersistencePort interface {
+	InsertUserOverview(ctx context.Context, overview domain.UserOverview) error
+	UpdateUserOverview(ctx context.Context, username string, update domain.UserOverviewUpdate) error
+	DeleteUserOverview(ctx context.Context, username string) error
+	FindUserOverviews(ctx context.Context, filter domain.UserOverviewFilter) ([]domain.UserOverview, int64, error)
+}
@@ -1,12 +1,16 @@
 package persistence
 
 import (
+	"context"
+	"time"
 	"user-auth-hexagonal-architecture/internal/domain"
 )
 
 // UserPersistencePort is a secondary (driven) port to decouple the core layer from the persistence layer
 type UserPersistencePort interface {
-	SaveUser(username string, hashedPassword string) error
-	FindUser(username string) (domain.User, error)
-	IsUsernameAvailable(username string) (bool, error)
+	SaveUser(ctx context.Context, user domain.User) (string, error)
+	FindUser(ctx context.Context, username domain.Username) (domain.User, error)
+	IsUsernameAvailable(ctx context.Context, username domain.Username, canonicalUsername string) (bool, error)
+	UpdateLastLogin(ctx context.Context, username domain.Username, loginAt time.Time) error
+	UpdatePasswordHash(ctx context.Context, username domain.Username, previous domain.HashedPassword, password domain.HashedPassword) error
 }
@@ -0,0 +1,19 @@
+package persistence
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// UserProvisioningPersistencePort is a secondary (driven) port for storing many new users at once
+type UserProvisioningPersistencePort interface {
+	// SaveUsers stores the users in one bulk operation. The results are in the order of the users;
+	// the error is only set if the operation failed as a whole.
+	SaveUsers(ctx context.Context, users []domain.User) ([]UserSaveResult, error)
+}
+
+// UserSaveResult is the outcome of storing one user of a bulk save. Err is nil if the user was stored.
+type UserSaveResult struct {
+	ID  string
+	Err error
+}
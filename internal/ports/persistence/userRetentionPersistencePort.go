@@ -0,0 +1,12 @@
+package persistence
+
+import (
+	"context"
+	"time"
+)
+
+// UserRetentionPersistencePort is a secondary (driven) port for moving stale accounts out of the active user store
+type UserRetentionPersistencePort interface {
+	ArchiveUsersInactiveSince(ctx context.Context, cutoff time.Time) (int64, error)
+	PurgeUsersDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
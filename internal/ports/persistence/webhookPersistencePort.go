@@ -0,0 +1,19 @@
+package persistence
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// WebhookPersistencePort is a secondary (driven) port for storing webhook subscriptions
+type WebhookPersistencePort interface {
+	SaveWebhook(ctx context.Context, subscription domain.WebhookSubscription) (string, error)
+	FindWebhooks(ctx context.Context) ([]domain.WebhookSubscription, error)
+	DeleteWebhook(ctx context.Context, id string) error
+}
+
+// WebhookDeadLetterPersistencePort is a secondary (driven) port for storing failed webhook deliveries
+type WebhookDeadLetterPersistencePort interface {
+	SaveDeadLetter(ctx context.Context, deadLetter domain.WebhookDeadLetter) error
+	FindDeadLetters(ctx context.Context, limit int64) ([]domain.WebhookDeadLetter, error)
+}
@@ -0,0 +1,39 @@
+package security
+
+import (
+	"context"
+	"time"
+)
+
+// LockoutStorePort is a secondary (driven) port counting failed logins and locking out the keys that fail too often
+type LockoutStorePort interface {
+	// LockedFor returns how long the key stays locked out, zero if it is not.
+	LockedFor(ctx context.Context, key string) (time.Duration, error)
+	// RecordFailure counts a failed login of the key and locks it out once the policy is exceeded.
+	RecordFailure(ctx context.Context, key string, policy LockoutPolicy) (LockoutState, error)
+	// Reset forgets the failed logins of the key, e.g. after a successful login.
+	Reset(ctx context.Context, key string) error
+}
+
+// LockoutPolicy locks a key out for Duration once it failed MaxFailures times within Window.
+// Durations are relative, so stores measure them with a single clock and never compare the
+// clocks of different instances.
+type LockoutPolicy struct {
+	MaxFailures int
+	Window      time.Duration
+	Duration    time.Duration
+}
+
+// Enabled reports whether the policy locks keys out at all.
+func (p LockoutPolicy) Enabled() bool {
+	return p.MaxFailures > 0 && p.Window > 0 && p.Duration > 0
+}
+
+// LockoutState is the state of a key after a failed login.
+type LockoutState struct {
+	// Failures is the number of failed logins within the window, including the one that locked the
+	// key out; zero if the key was locked out already.
+	Failures int
+	// LockedFor is how long the key is locked out, zero if it is not.
+	LockedFor time.Duration
+}
@@ -0,0 +1,16 @@
+package security
+
+import (
+	"time"
+)
+
+// OneTimePasswordPort is a secondary (driven) port to decouple the core layer from the one-time password
+// algorithm of authenticator apps
+type OneTimePasswordPort interface {
+	// GenerateSecret creates a random secret to share with an authenticator.
+	GenerateSecret() (string, error)
+	// ProvisioningURI returns the otpauth:// URI authenticator apps scan to set up the secret for an account.
+	ProvisioningURI(secret string, account string) string
+	// Verify reports whether a code is valid for the secret at the given time.
+	Verify(secret string, code string, at time.Time) bool
+}
@@ -0,0 +1,21 @@
+package security
+
+import (
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// PasswordHasherPort is a secondary (driven) port to decouple the core layer from the password hashing algorithms
+type PasswordHasherPort interface {
+	// Hash hashes a plain text password with the configured algorithm and a random salt.
+	Hash(password string) (domain.HashedPassword, error)
+	// Verify compares a plain text password with a hash. The algorithm is recognised from the format of
+	// the hash, so hashes created with a previously configured algorithm keep verifying.
+	// It returns errorx.ErrInvalidCredentials if the password does not match and
+	// errorx.ErrInvalidPasswordHash if the algorithm of the hash is not supported.
+	Verify(hash domain.HashedPassword, password string) error
+	// Algorithm returns the name of the algorithm new hashes are created with, e.g. "bcrypt".
+	Algorithm() string
+	// NeedsRehash reports whether a hash was created with another algorithm than the one new hashes
+	// are created with, so it should be replaced once the password is known.
+	NeedsRehash(hash domain.HashedPassword) bool
+}
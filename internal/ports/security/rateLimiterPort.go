@@ -0,0 +1,40 @@
+// Package security contains the ports for protective infrastructure such as rate limiting, password hashing
+// and risk signals.
+package security
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimiterPort is a secondary (driven) port for token bucket rate limiting shared by all adapters
+type RateLimiterPort interface {
+	Allow(ctx context.Context, key string, limit RateLimit) (RateLimitDecision, error)
+}
+
+// RateLimit configures a token bucket: it holds at most Burst tokens and refills Requests tokens every Per.
+type RateLimit struct {
+	Requests int
+	Per      time.Duration
+	Burst    int
+}
+
+// RefillPerSecond returns the number of tokens added to the bucket per second.
+func (l RateLimit) RefillPerSecond() float64 {
+	return float64(l.Requests) / l.Per.Seconds()
+}
+
+// Capacity returns the bucket size, defaulting to Requests if no burst is configured.
+func (l RateLimit) Capacity() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Requests
+}
+
+// RateLimitDecision is the outcome of taking a token from a bucket.
+type RateLimitDecision struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
@@ -0,0 +1,13 @@
+package security
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// RiskSignalProviderPort is a secondary (driven) port through which the core layer collects the risk signals of
+// a login attempt, so further sources such as threat intelligence feeds can be plugged in
+type RiskSignalProviderPort interface {
+	// Signals returns the signals the provider raises for the attempt, none if it considers the attempt safe.
+	Signals(ctx context.Context, attempt domain.LoginAttempt) ([]domain.RiskSignal, error)
+}
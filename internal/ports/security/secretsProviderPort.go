@@ -0,0 +1,13 @@
+package security
+
+import (
+	"context"
+)
+
+// SecretsProviderPort is a secondary (driven) port to decouple the service from the secret store holding
+// credentials such as the token signing key and database passwords
+type SecretsProviderPort interface {
+	// Secret returns the current value of a secret. The format of the reference depends on the store,
+	// e.g. "secret/data/auth#jwt-key" for a field of a Vault secret.
+	Secret(ctx context.Context, ref string) (string, error)
+}
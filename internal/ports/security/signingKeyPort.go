@@ -0,0 +1,11 @@
+package security
+
+// SigningKeyPort is a secondary (driven) port to decouple the core layer from the keys tokens are signed with,
+// so the keys can be rotated while the service runs
+type SigningKeyPort interface {
+	// SigningKey returns the key new tokens are signed with.
+	SigningKey() []byte
+	// VerificationKeys returns the keys tokens are accepted with: the signing key first, followed by
+	// previous keys that are still within their grace period.
+	VerificationKeys() [][]byte
+}
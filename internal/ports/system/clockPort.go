@@ -0,0 +1,13 @@
+// Package system contains the ports for the environment the core layer runs in, such as the
+// current time and randomness, so time-dependent and random business rules stay deterministic under test.
+package system
+
+import (
+	"time"
+)
+
+// ClockPort is a secondary (driven) port through which the core layer reads the current time
+type ClockPort interface {
+	// Now returns the current time.
+	Now() time.Time
+}
@@ -0,0 +1,13 @@
+package system
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// FeatureFlagPort is a secondary (driven) port through which the core layer asks whether an optional capability is switched on
+type FeatureFlagPort interface {
+	// Enabled reports whether a flag is on for the tenant of the request in ctx, falling back to the
+	// value for the whole deployment and to the default of the flag.
+	Enabled(ctx context.Context, flag domain.FeatureFlag) bool
+}
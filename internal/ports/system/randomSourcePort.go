@@ -0,0 +1,8 @@
+package system
+
+// RandomSourcePort is a secondary (driven) port through which the core layer obtains random bytes for secrets
+// such as signing keys, one-time passwords and reset tokens
+type RandomSourcePort interface {
+	// Read fills b with random bytes. It returns len(b) or an error, never a partial read without an error.
+	Read(b []byte) (n int, err error)
+}
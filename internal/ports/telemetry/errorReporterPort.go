@@ -0,0 +1,31 @@
+package telemetry
+
+import (
+	"context"
+)
+
+// ErrorReporterPort is a secondary (driven) port through which unexpected errors and panics are sent to an
+// error tracking service
+type ErrorReporterPort interface {
+	// ReportError reports an unexpected error without blocking the caller.
+	ReportError(ctx context.Context, report ErrorReport)
+}
+
+// ErrorReport describes an unexpected error. All values are sanitized by the reporting side and
+// must not contain credentials.
+type ErrorReport struct {
+	// Message says what failed, e.g. "Error recording login". Reports with the same message are grouped.
+	Message string
+	// Error is the text of the error or of the panic value.
+	Error string
+	// Stack is the stack trace of a panic, empty for errors.
+	Stack string
+	// RequestID is the id of the request the error occurred in, empty outside requests.
+	RequestID string
+	// UserID is the id of the authenticated user of the request, if any.
+	UserID string
+	// Tags are searchable properties such as the component, the HTTP method and the route.
+	Tags map[string]string
+	// Context holds the further details logged with the error.
+	Context map[string]string
+}
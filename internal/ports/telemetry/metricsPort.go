@@ -0,0 +1,19 @@
+package telemetry
+
+import (
+	"time"
+)
+
+// MetricsPort is a secondary (driven) port through which the core layer reports measurements only it can take
+type MetricsPort interface {
+	// ObservePasswordHashing records how long hashing ("hash") or verifying ("verify") a password took.
+	ObservePasswordHashing(operation string, duration time.Duration)
+	// CountLockout records that a username was locked out after too many failed logins.
+	CountLockout()
+}
+
+// Password hashing operations reported to ObservePasswordHashing.
+const (
+	PasswordHash   = "hash"
+	PasswordVerify = "verify"
+)
@@ -0,0 +1,18 @@
+package usecases
+
+import (
+	"context"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// LockUserPort is a primary (driving) port to decouple the core layer from the adapter layer
+type LockUserPort interface {
+	LockUser(ctx context.Context, id string, reason domain.LockReason, until time.Time, lockedBy string) error
+	UnlockUser(ctx context.Context, id string) error
+}
+
+// LockExpiryPort is a primary (driving) port for the periodic lifting of expired account locks
+type LockExpiryPort interface {
+	LiftExpiredLocks(ctx context.Context) (int64, error)
+}
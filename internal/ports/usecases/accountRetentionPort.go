@@ -0,0 +1,11 @@
+package usecases
+
+import (
+	"context"
+)
+
+// AccountRetentionPort is a primary (driving) port for the periodic account housekeeping use cases
+type AccountRetentionPort interface {
+	ArchiveInactiveUsers(ctx context.Context) (int64, error)
+	PurgeDeletedUsers(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,72 @@
+package usecases
+
+import (
+	"context"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// ListUsersPort is a primary (driving) port to decouple the core layer from the adapter layer
+type ListUsersPort interface {
+	ListUsers(ctx context.Context, filter domain.UserOverviewFilter) (UserPage, error)
+}
+
+// GetUserPort is a primary (driving) port to decouple the core layer from the adapter layer
+type GetUserPort interface {
+	GetUser(ctx context.Context, id string) (UserView, error)
+}
+
+// SearchUsersPort is a primary (driving) port to decouple the core layer from the adapter layer
+type SearchUsersPort interface {
+	SearchUsers(ctx context.Context, term string, offset int64, limit int64) (UserViewPage, error)
+}
+
+// AssignRolePort is a primary (driving) port to decouple the core layer from the adapter layer
+type AssignRolePort interface {
+	AssignRoles(ctx context.Context, id string, roles []domain.Role) error
+	GrantRole(ctx context.Context, id string, role domain.Role) error
+	RevokeRole(ctx context.Context, id string, role domain.Role) error
+}
+
+// RenameUserPort is a primary (driving) port to decouple the core layer from the adapter layer
+type RenameUserPort interface {
+	RenameUser(ctx context.Context, id string, username string) error
+}
+
+// ChangeUserStatusPort is a primary (driving) port to decouple the core layer from the adapter layer
+type ChangeUserStatusPort interface {
+	DisableUser(ctx context.Context, id string) error
+	EnableUser(ctx context.Context, id string) error
+}
+
+// DeleteUserPort is a primary (driving) port to decouple the core layer from the adapter layer
+type DeleteUserPort interface {
+	DeleteUser(ctx context.Context, id string) error
+}
+
+// UserPage is one page of a user listing.
+type UserPage struct {
+	Users []domain.UserOverview
+	Total int64
+}
+
+// UserView is a user as shown to administrators, without credentials.
+type UserView struct {
+	ID          string
+	TenantID    string
+	Username    string
+	Email       string
+	Status      domain.AccountStatus
+	Roles       []domain.Role
+	MfaEnabled  bool
+	CreatedAt   time.Time
+	LastLoginAt time.Time
+	LockReason  domain.LockReason
+	LockedUntil time.Time
+}
+
+// UserViewPage is one page of a user search.
+type UserViewPage struct {
+	Users []UserView
+	Total int64
+}
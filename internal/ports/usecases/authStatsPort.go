@@ -0,0 +1,13 @@
+package usecases
+
+import (
+	"context"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// GetAuthStatsPort is a primary (driving) port to decouple the core layer from the adapter layer
+type GetAuthStatsPort interface {
+	// GetAuthStats aggregates the days from up to but excluding until; zero times select the last 30 days.
+	GetAuthStats(ctx context.Context, from time.Time, until time.Time) (domain.AuthStats, error)
+}
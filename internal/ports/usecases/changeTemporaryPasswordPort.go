@@ -0,0 +1,10 @@
+package usecases
+
+import (
+	"context"
+)
+
+// ChangeTemporaryPasswordPort is a primary (driving) port to decouple the core layer from the adapter layer
+type ChangeTemporaryPasswordPort interface {
+	ChangeTemporaryPassword(ctx context.Context, passwordChangeToken string, newPassword string) error
+}
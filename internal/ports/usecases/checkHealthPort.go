@@ -0,0 +1,25 @@
+package usecases
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+)
+
+// CheckHealthPort is a primary (driving) port to decouple the core layer from the adapter layer
+type CheckHealthPort interface {
+	CheckHealth(ctx context.Context) HealthReport
+}
+
+// HealthReport is the aggregated health of all persistence backends and dependencies.
+type HealthReport struct {
+	Healthy      bool                `json:"healthy"`
+	Persistence  []PersistenceHealth `json:"persistence"`
+	Dependencies []DependencyStatus  `json:"dependencies,omitempty"`
+}
+
+// PersistenceHealth is the health of a single persistence backend.
+type PersistenceHealth struct {
+	Healthy bool                          `json:"healthy"`
+	Error   string                        `json:"error,omitempty"`
+	Stats   *persistence.PersistenceStats `json:"stats,omitempty"`
+}
@@ -0,0 +1,24 @@
+package usecases
+
+import (
+	"context"
+)
+
+// CheckReadinessPort is a primary (driving) port to decouple the core layer from the adapter layer
+type CheckReadinessPort interface {
+	CheckReadiness(ctx context.Context) ReadinessReport
+}
+
+// ReadinessReport tells whether the application can serve requests.
+type ReadinessReport struct {
+	Ready        bool               `json:"ready"`
+	Dependencies []DependencyStatus `json:"dependencies"`
+}
+
+// DependencyStatus is the state of a single dependency.
+type DependencyStatus struct {
+	Name     string `json:"name"`
+	Ready    bool   `json:"ready"`
+	Optional bool   `json:"optional,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
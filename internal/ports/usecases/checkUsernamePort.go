@@ -0,0 +1,10 @@
+package usecases
+
+import (
+	"context"
+)
+
+// CheckUsernamePort is a primary (driving) port to decouple the core layer from the adapter layer
+type CheckUsernamePort interface {
+	CheckUsername(ctx context.Context, username string) (bool, error)
+}
@@ -0,0 +1,29 @@
+package usecases
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// ConsentDocumentsPort is a primary (driving) port to decouple the core layer from the adapter layer
+type ConsentDocumentsPort interface {
+	CurrentDocumentVersions(ctx context.Context) ([]domain.DocumentVersion, error)
+	PublishDocumentVersion(ctx context.Context, document domain.ConsentDocument, version string) (domain.DocumentVersion, error)
+}
+
+// UserConsentPort is a primary (driving) port to decouple the core layer from the adapter layer
+type UserConsentPort interface {
+	GetConsents(ctx context.Context, username string) (domain.ConsentStatus, error)
+	AcceptConsents(ctx context.Context, username string, refs []domain.ConsentRef) error
+}
+
+// ConsentGatePort is a primary (driving) port to decouple the core layer from the adapter layer.
+// The registration and login use cases consult it, so accounts cannot be used without accepting
+// the current terms and privacy policy.
+type ConsentGatePort interface {
+	// CheckConsents returns the consents a user gives with refs, or errorx.ErrConsentRequired if a current
+	// document version would remain outstanding.
+	CheckConsents(ctx context.Context, user domain.User, refs []domain.ConsentRef) ([]domain.Consent, error)
+	// RecordConsents stores the consents returned by CheckConsents and emits ConsentAccepted events.
+	RecordConsents(ctx context.Context, consents []domain.Consent) error
+}
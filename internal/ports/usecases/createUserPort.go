@@ -0,0 +1,11 @@
+package usecases
+
+import (
+	"context"
+)
+
+// CreateUserPort is a primary (driving) port to decouple the core layer from the adapter layer.
+// It lets administrators create a single account with a temporary password.
+type CreateUserPort interface {
+	CreateUser(ctx context.Context, spec UserSpec) (string, error)
+}
@@ -0,0 +1,37 @@
+package usecases
+
+import (
+	"context"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// ExportUserDataPort is a primary (driving) port to decouple the core layer from the adapter layer
+type ExportUserDataPort interface {
+	ExportUserData(ctx context.Context, username string) (DataExportView, error)
+	GetDataExport(ctx context.Context, username string, id string) (DataExportView, error)
+}
+
+// DownloadDataExportPort is a primary (driving) port to decouple the core layer from the adapter layer.
+// Downloads are authorized by a signed download token instead of an access token, so the link can be
+// opened in a browser.
+type DownloadDataExportPort interface {
+	DownloadDataExport(ctx context.Context, token string) ([]byte, error)
+}
+
+// DataExportView is a data export without its archive. DownloadToken authorizes the download of a
+// ready export until it expires and is empty otherwise.
+type DataExportView struct {
+	ID            string
+	Status        domain.DataExportStatus
+	RequestedAt   time.Time
+	CompletedAt   time.Time
+	ExpiresAt     time.Time
+	DownloadToken string
+}
+
+// GenerateDataExportsPort is a primary (driving) port for the background generation of the
+// archives of requested data exports
+type GenerateDataExportsPort interface {
+	GenerateDataExports(ctx context.Context) (int, error)
+}
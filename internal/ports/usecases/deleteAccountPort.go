@@ -0,0 +1,10 @@
+package usecases
+
+import (
+	"context"
+)
+
+// DeleteOwnAccountPort is a primary (driving) port to decouple the core layer from the adapter layer
+type DeleteOwnAccountPort interface {
+	DeleteOwnAccount(ctx context.Context, username string, password string) error
+}
@@ -0,0 +1,27 @@
+package usecases
+
+import (
+	"context"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// DescribeCapabilitiesPort is a primary (driving) port to decouple the core layer from the adapter layer
+type DescribeCapabilitiesPort interface {
+	DescribeCapabilities(ctx context.Context) (Capabilities, error)
+}
+
+// Capabilities describes what the deployment offers to the tenant of a request, so clients can
+// adapt to its configuration instead of hard-coding it.
+type Capabilities struct {
+	TenantID               string
+	Registration           bool
+	LoginMethods           []domain.LoginMethod
+	MfaMethods             []domain.MfaMethod
+	MfaRequired            bool
+	SocialProviders        []string
+	TokenSigningAlgorithms []string
+	AccessTokenTTL         time.Duration
+	PasswordPolicy         domain.PasswordPolicy
+	Features               map[domain.FeatureFlag]bool
+}
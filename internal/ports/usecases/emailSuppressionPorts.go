@@ -0,0 +1,18 @@
+package usecases
+
+import (
+	"context"
+	"net/http"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// RecordEmailFeedbackPort is a primary (driving) port to decouple the core layer from the adapter layer
+type RecordEmailFeedbackPort interface {
+	RecordEmailFeedback(ctx context.Context, provider string, header http.Header, body []byte) error
+}
+
+// ManageEmailSuppressionsPort is a primary (driving) port to decouple the core layer from the adapter layer
+type ManageEmailSuppressionsPort interface {
+	ListEmailSuppressions(ctx context.Context, limit int64) ([]domain.EmailSuppression, error)
+	DeleteEmailSuppression(ctx context.Context, address string) error
+}
@@ -0,0 +1,18 @@
+package usecases
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// EraseUserPort is a primary (driving) port to decouple the core layer from the adapter layer
+type EraseUserPort interface {
+	EraseUser(ctx context.Context, id string, requestedBy string, overrideLegalHold bool) (domain.ErasureCertificate, error)
+	GetErasureCertificate(ctx context.Context, id string) (domain.ErasureCertificate, error)
+}
+
+// LegalHoldPort is a primary (driving) port to decouple the core layer from the adapter layer
+type LegalHoldPort interface {
+	PlaceLegalHold(ctx context.Context, id string) error
+	LiftLegalHold(ctx context.Context, id string) error
+}
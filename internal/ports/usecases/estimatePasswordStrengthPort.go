@@ -0,0 +1,11 @@
+package usecases
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// EstimatePasswordStrengthPort is a primary (driving) port to decouple the core layer from the adapter layer
+type EstimatePasswordStrengthPort interface {
+	EstimatePasswordStrength(ctx context.Context, password string, username string) (domain.PasswordStrength, error)
+}
@@ -0,0 +1,11 @@
+package usecases
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// GetCurrentUserPort is a primary (driving) port to decouple the core layer from the adapter layer
+type GetCurrentUserPort interface {
+	GetCurrentUser(ctx context.Context, username string) (domain.User, error)
+}
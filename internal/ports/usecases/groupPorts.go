@@ -0,0 +1,16 @@
+package usecases
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// ManageGroupsPort is a primary (driving) port to decouple the core layer from the adapter layer
+type ManageGroupsPort interface {
+	ListGroups(ctx context.Context) ([]domain.Group, error)
+	GetGroup(ctx context.Context, name string) (domain.Group, error)
+	SaveGroup(ctx context.Context, name string, roles []domain.Role) (domain.Group, error)
+	DeleteGroup(ctx context.Context, name string) error
+	AddGroupMember(ctx context.Context, name string, userID string) error
+	RemoveGroupMember(ctx context.Context, name string, userID string) error
+}
@@ -1,6 +1,11 @@
 package usecases
 
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
 // LoadUserPort is a primary (driving) port to decouple the core layer from the adapter layer
 type LoadUserPort interface {
-	LoadUser(username string, password string) (string, error)
+	LoadUser(ctx context.Context, username string, password string, mfaCode string, method domain.LoginMethod, consents []domain.ConsentRef) (domain.AuthTokens, error)
 }
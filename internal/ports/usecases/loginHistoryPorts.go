@@ -0,0 +1,17 @@
+package usecases
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// LoginHistoryPort is a primary (driving) port to decouple the core layer from the adapter layer.
+// It lets signed-in users review the recent logins to their account.
+type LoginHistoryPort interface {
+	GetLoginHistory(ctx context.Context, username string, limit int64) ([]domain.LoginRecord, error)
+}
+
+// SearchLoginAttemptsPort is a primary (driving) port to decouple the core layer from the adapter layer
+type SearchLoginAttemptsPort interface {
+	SearchLoginAttempts(ctx context.Context, filter domain.LoginRecordFilter) ([]domain.LoginRecord, error)
+}
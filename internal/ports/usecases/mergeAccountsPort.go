@@ -0,0 +1,11 @@
+package usecases
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// MergeAccountsPort is a primary (driving) port to decouple the core layer from the adapter layer
+type MergeAccountsPort interface {
+	MergeAccounts(ctx context.Context, primaryID string, duplicateID string, disposition domain.MergeUsernameDisposition, mergedBy string) (domain.AccountMerge, error)
+}
@@ -0,0 +1,14 @@
+package usecases
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// ManageMfaPort is a primary (driving) port to decouple the core layer from the adapter layer
+type ManageMfaPort interface {
+	ListMfaMethods(ctx context.Context, username string) ([]domain.MfaFactor, error)
+	BeginMfaEnrollment(ctx context.Context, username string, method domain.MfaMethod) (domain.MfaEnrollment, error)
+	EnableMfa(ctx context.Context, username string, method domain.MfaMethod, code string) error
+	DisableMfa(ctx context.Context, username string, method domain.MfaMethod, password string, code string) error
+}
@@ -0,0 +1,19 @@
+package usecases
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// ManagePoliciesPort is a primary (driving) port to decouple the core layer from the adapter layer
+type ManagePoliciesPort interface {
+	ListPolicies(ctx context.Context) ([]domain.Policy, error)
+	SavePolicy(ctx context.Context, policy domain.Policy) (domain.Policy, error)
+	DeletePolicy(ctx context.Context, id string) error
+}
+
+// AuthorizePort is a primary (driving) port to decouple the core layer from the adapter layer.
+// It is answered from memory, so it can be consulted on every request.
+type AuthorizePort interface {
+	Authorize(ctx context.Context, request domain.AccessRequest) domain.PolicyDecision
+}
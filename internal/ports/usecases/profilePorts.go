@@ -0,0 +1,17 @@
+package usecases
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// GetProfilePort is a primary (driving) port to decouple the core layer from the adapter layer
+type GetProfilePort interface {
+	GetProfile(ctx context.Context, username string) (domain.Profile, error)
+	GetPublicProfile(ctx context.Context, username string) (domain.Profile, error)
+}
+
+// UpdateProfilePort is a primary (driving) port to decouple the core layer from the adapter layer
+type UpdateProfilePort interface {
+	UpdateProfile(ctx context.Context, username string, changes domain.ProfileChanges) (domain.Profile, error)
+}
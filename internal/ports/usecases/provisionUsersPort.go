@@ -0,0 +1,41 @@
+package usecases
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// ProvisionUsersPort is a primary (driving) port to decouple the core layer from the adapter layer.
+// It creates many accounts at once, e.g. for the user import API or the CLI.
+type ProvisionUsersPort interface {
+	ProvisionUsers(ctx context.Context, specs []UserSpec) (ProvisioningReport, error)
+}
+
+// UserSpec describes an account to provision. Exactly one of TemporaryPassword and Invite is set:
+// accounts with a temporary password are active right away but have to change it at the first
+// login, invited accounts stay pending without a password until the invitation is accepted.
+type UserSpec struct {
+	Username          string
+	Email             string
+	Role              domain.Role
+	TemporaryPassword string
+	Invite            bool
+}
+
+// ProvisioningResult is the outcome of provisioning a single account. Err is nil if the account
+// was created, otherwise it explains why the spec was rejected.
+type ProvisioningResult struct {
+	// Index is the position of the spec in the request.
+	Index    int
+	Username string
+	UserID   string
+	Invited  bool
+	Err      error
+}
+
+// ProvisioningReport lists the results of a provisioning request in the order of the specs.
+type ProvisioningReport struct {
+	Results []ProvisioningResult
+	Created int
+	Failed  int
+}
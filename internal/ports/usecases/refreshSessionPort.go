@@ -0,0 +1,11 @@
+package usecases
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// RefreshSessionPort is a primary (driving) port to decouple the core layer from the adapter layer
+type RefreshSessionPort interface {
+	RefreshSession(ctx context.Context, refreshToken string) (domain.AuthTokens, error)
+}
@@ -1,6 +1,12 @@
 package usecases
 
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
 // RegisterUserPort is a primary (driving) port to decouple the core layer from the adapter layer
 type RegisterUserPort interface {
-	RegisterUser(username string, password string) error
+	// RegisterUser creates a new account and returns the id of the created user.
+	RegisterUser(ctx context.Context, username string, password string, email string, consents []domain.ConsentRef) (string, error)
 }
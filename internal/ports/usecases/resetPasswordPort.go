@@ -0,0 +1,10 @@
+package usecases
+
+import (
+	"context"
+)
+
+// ResetPasswordPort is a primary (driving) port to decouple the core layer from the adapter layer
+type ResetPasswordPort interface {
+	ResetPassword(ctx context.Context, id string, temporaryPassword string) error
+}
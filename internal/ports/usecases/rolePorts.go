@@ -0,0 +1,19 @@
+package usecases
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// ManageRolesPort is a primary (driving) port to decouple the core layer from the adapter layer
+type ManageRolesPort interface {
+	ListRoles(ctx context.Context) ([]domain.RoleDefinition, error)
+	SaveRole(ctx context.Context, name domain.Role, permissions []domain.Permission) (domain.RoleDefinition, error)
+	DeleteRole(ctx context.Context, name domain.Role) error
+}
+
+// RoleRegistryPort is a primary (driving) port to decouple the core layer from the adapter layer.
+// It is answered from memory, so it can be consulted on every request.
+type RoleRegistryPort interface {
+	RolePermissions() domain.RolePermissions
+}
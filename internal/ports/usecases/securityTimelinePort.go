@@ -0,0 +1,11 @@
+package usecases
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// SecurityTimelinePort is a primary (driving) port to decouple the core layer from the adapter layer
+type SecurityTimelinePort interface {
+	GetSecurityTimeline(ctx context.Context, username string) (domain.SecurityTimeline, error)
+}
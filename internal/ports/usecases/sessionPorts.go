@@ -0,0 +1,30 @@
+package usecases
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// TrackSessionPort is a primary (driving) port to decouple the core layer from the adapter layer.
+// It is consulted on every request carrying a session bound access token.
+type TrackSessionPort interface {
+	TouchSession(ctx context.Context, id string) error
+}
+
+// EndSessionPort is a primary (driving) port to decouple the core layer from the adapter layer
+type EndSessionPort interface {
+	EndSession(ctx context.Context, id string) error
+}
+
+// UserSessionsPort is a primary (driving) port to decouple the core layer from the adapter layer.
+// It lets signed-in users review and end their own sessions.
+type UserSessionsPort interface {
+	ListMySessions(ctx context.Context, username string) ([]domain.Session, error)
+	RevokeSession(ctx context.Context, username string, id string) error
+}
+
+// AdminSessionsPort is a primary (driving) port to decouple the core layer from the adapter layer
+type AdminSessionsPort interface {
+	ListUserSessions(ctx context.Context, userID string) ([]domain.Session, error)
+	RevokeUserSession(ctx context.Context, userID string, id string) error
+}
@@ -0,0 +1,21 @@
+package usecases
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// ManageTenantsPort is a primary (driving) port to decouple the core layer from the adapter layer
+type ManageTenantsPort interface {
+	ListTenants(ctx context.Context) ([]domain.Tenant, error)
+	GetTenant(ctx context.Context, id string) (domain.Tenant, error)
+	SaveTenant(ctx context.Context, tenant domain.Tenant) (domain.Tenant, error)
+	DeleteTenant(ctx context.Context, id string) error
+}
+
+// TenantRegistryPort is a primary (driving) port to decouple the core layer from the adapter layer.
+// It is answered from memory, so it can be consulted on every request.
+type TenantRegistryPort interface {
+	// ResolveTenant returns the tenant named by the context, see the tenant package, or the default tenant.
+	ResolveTenant(ctx context.Context) (domain.Tenant, error)
+}
@@ -0,0 +1,11 @@
+package usecases
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// VerifyTokenPort is a primary (driving) port to decouple the core layer from the adapter layer
+type VerifyTokenPort interface {
+	VerifyToken(ctx context.Context, token string) (domain.Principal, error)
+}
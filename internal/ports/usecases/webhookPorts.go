@@ -0,0 +1,18 @@
+package usecases
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+)
+
+// ManageWebhooksPort is a primary (driving) port to decouple the core layer from the adapter layer
+type ManageWebhooksPort interface {
+	CreateWebhook(ctx context.Context, url string, events []string) (domain.WebhookSubscription, error)
+	ListWebhooks(ctx context.Context) ([]domain.WebhookSubscription, error)
+	DeleteWebhook(ctx context.Context, id string) error
+}
+
+// ListWebhookDeadLettersPort is a primary (driving) port to decouple the core layer from the adapter layer
+type ListWebhookDeadLettersPort interface {
+	ListWebhookDeadLetters(ctx context.Context, limit int64) ([]domain.WebhookDeadLetter, error)
+}
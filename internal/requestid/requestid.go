@@ -0,0 +1,33 @@
+// Package requestid carries the correlation id of a request through contexts, so that log lines,
+// error responses and outbound calls of all layers can be traced back to the originating request.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Header is the HTTP header carrying the request id.
+const Header = "X-Request-ID"
+
+// contextKey is the context key under which the request id is stored.
+type contextKey struct{}
+
+// New generates a random 128 bit request id.
+func New() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// WithID returns a copy of ctx carrying the request id.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request id stored in ctx, or an empty string.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
@@ -0,0 +1,129 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"fmt"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/ports/messaging"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/security"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+)
+
+// AccountDeletionService handles the business logic for deleting accounts, by administrators and
+// by the users themselves. It implements the DeleteUserPort and DeleteOwnAccountPort interfaces
+// from the usecases package.
+//
+// Deleted accounts are soft-deleted and all their sessions are revoked at once, so no access token
+// of the account is accepted anymore. Their MFA factors are removed together with the soft-delete.
+// The account itself is purged by the retention job once the deletion grace period has passed.
+type AccountDeletionService struct {
+	userPersistence      persistence.UserPersistencePort
+	userAdminPersistence persistence.UserAdminPersistencePort
+	sessionPersistence   persistence.SessionPersistencePort
+	eventDispatcher      messaging.EventDispatcherPort
+	passwordHasher       security.PasswordHasherPort
+	clock                system.ClockPort
+}
+
+// NewAccountDeletionService creates a new instance of AccountDeletionService.
+//
+// Parameters:
+//   - userPersistence: An implementation of UserPersistencePort for finding users by username
+//   - userAdminPersistence: An implementation of UserAdminPersistencePort for soft-deleting users
+//   - sessionPersistence: An implementation of SessionPersistencePort for revoking the sessions of deleted users
+//   - eventDispatcher: An implementation of EventDispatcherPort for emitting domain events
+//   - passwordHasher: An implementation of PasswordHasherPort for confirming self-service deletions
+//   - clock: An implementation of ClockPort for reading the current time
+//
+// Returns:
+//   - *AccountDeletionService: A pointer to the newly created AccountDeletionService
+func NewAccountDeletionService(userPersistence persistence.UserPersistencePort, userAdminPersistence persistence.UserAdminPersistencePort, sessionPersistence persistence.SessionPersistencePort, eventDispatcher messaging.EventDispatcherPort, passwordHasher security.PasswordHasherPort, clock system.ClockPort) *AccountDeletionService {
+	return &AccountDeletionService{userPersistence, userAdminPersistence, sessionPersistence, eventDispatcher, passwordHasher, clock}
+}
+
+// DeleteUser soft-deletes a user on behalf of an administrator and moves the account to the DELETED status.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The id of the user
+//
+// Returns:
+//   - error: errorx.ErrUserNotFound, or a wrapped persistence error
+func (ds *AccountDeletionService) DeleteUser(ctx context.Context, id string) (err error) {
+	ctx, span := tracer.Start(ctx, "AccountDeletionService.DeleteUser")
+	defer func() { endSpan(span, err) }()
+
+	user, err := ds.userAdminPersistence.FindUserByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error finding user: %w", err)
+	}
+	return ds.delete(ctx, user)
+}
+
+// DeleteOwnAccount soft-deletes the account of the authenticated user after confirming the
+// deletion with the user's password.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - username: The username of the authenticated user, i.e. the subject of the access token
+//   - password: The current password of the user, confirming the deletion
+//
+// Returns:
+//   - error: errorx.ErrInvalidCredentials if the password is wrong, errorx.ErrUserNotFound if the
+//     account no longer exists, or a wrapped persistence error
+func (ds *AccountDeletionService) DeleteOwnAccount(ctx context.Context, username string, password string) (err error) {
+	ctx, span := tracer.Start(ctx, "AccountDeletionService.DeleteOwnAccount")
+	defer func() { endSpan(span, err) }()
+
+	user, err := ds.userPersistence.FindUser(ctx, domain.NormalizeUsername(username))
+	if err != nil {
+		return fmt.Errorf("error finding user: %w", err)
+	}
+	if err := ds.passwordHasher.Verify(user.Password, password); err != nil {
+		return err
+	}
+	return ds.delete(ctx, user)
+}
+
+// delete soft-deletes the user, removing its MFA factors, revokes its sessions and emits the UserStatusChanged and UserDeleted events.
+func (ds *AccountDeletionService) delete(ctx context.Context, user domain.User) error {
+	transition, err := user.Delete(ds.clock.Now())
+	if err != nil {
+		return err
+	}
+	if err := ds.userAdminPersistence.SoftDeleteUser(ctx, user.ID, transition.At); err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	if err := ds.revokeSessions(ctx, user); err != nil {
+		// the account is deleted at this point and its sessions fail on the next request
+		logger.ErrorContext(ctx, "Error revoking sessions of deleted user", "username", user.Username.String(), "error", err)
+	}
+
+	if transition.Changed() {
+		ds.eventDispatcher.Dispatch(ctx, statusChanged(user, transition))
+	}
+	ds.eventDispatcher.Dispatch(ctx, events.UserDeleted{Username: user.Username.String(), At: transition.At})
+	return nil
+}
+
+// revokeSessions revokes all active sessions of a deleted user.
+func (ds *AccountDeletionService) revokeSessions(ctx context.Context, user domain.User) error {
+	sessions, err := ds.sessionPersistence.FindSessionsByUser(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find sessions: %w", err)
+	}
+	now := ds.clock.Now()
+	for _, session := range sessions {
+		if err := session.Revoke(domain.SessionRevokedAccountDeleted, now); err != nil {
+			continue
+		}
+		if err := ds.sessionPersistence.SaveSession(ctx, session); err != nil {
+			return fmt.Errorf("failed to revoke session: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/ports/messaging"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+)
+
+// AccountLockService handles the business logic for accounts locked by administrators, e.g.
+// while a suspected compromise is investigated. Unlike disabling, a lock carries a reason and
+// may expire on its own.
+// It implements the LockUserPort and LockExpiryPort interfaces from the usecases package.
+//
+// Locks and unlocks emit AccountLocked and AccountUnlocked events, on which the affected user is
+// notified, next to the UserStatusChanged event of the status transition.
+type AccountLockService struct {
+	userAdminPersistence persistence.UserAdminPersistencePort
+	eventDispatcher      messaging.EventDispatcherPort
+	clock                system.ClockPort
+}
+
+// NewAccountLockService creates a new instance of AccountLockService.
+//
+// Parameters:
+//   - userAdminPersistence: An implementation of UserAdminPersistencePort for finding and locking users
+//   - eventDispatcher: An implementation of EventDispatcherPort for emitting domain events
+//   - clock: An implementation of ClockPort for reading the current time
+//
+// Returns:
+//   - *AccountLockService: A pointer to the newly created AccountLockService
+func NewAccountLockService(userAdminPersistence persistence.UserAdminPersistencePort, eventDispatcher messaging.EventDispatcherPort, clock system.ClockPort) *AccountLockService {
+	return &AccountLockService{userAdminPersistence, eventDispatcher, clock}
+}
+
+// LockUser locks a user, or replaces the reason and expiry of the lock of a locked user.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The id of the user
+//   - reason: Why the user is locked
+//   - until: When the lock expires, the zero time for a lock without expiry
+//   - lockedBy: The administrator locking the user
+//
+// Returns:
+//   - error: errorx.ErrInvalidLock, errorx.ErrInvalidStatusTransition unless the account is active
+//     or locked, errorx.ErrUserNotFound, or a wrapped persistence error
+func (ls *AccountLockService) LockUser(ctx context.Context, id string, reason domain.LockReason, until time.Time, lockedBy string) (err error) {
+	ctx, span := tracer.Start(ctx, "AccountLockService.LockUser")
+	defer func() { endSpan(span, err) }()
+
+	user, err := ls.userAdminPersistence.FindUserByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error finding user: %w", err)
+	}
+	now := ls.clock.Now()
+	transition, err := user.LockFor(reason, until, now)
+	if err != nil {
+		return err
+	}
+	if err := ls.userAdminPersistence.UpdateUserLock(ctx, id, user.LockReason, user.LockedUntil); err != nil {
+		return fmt.Errorf("failed to lock user: %w", err)
+	}
+
+	if transition.Changed() {
+		ls.eventDispatcher.Dispatch(ctx, statusChanged(user, transition))
+	}
+	ls.eventDispatcher.Dispatch(ctx, events.AccountLocked{Username: user.Username.String(), Reason: string(user.LockReason), LockedBy: lockedBy, Until: user.LockedUntil, At: now})
+	return nil
+}
+
+// UnlockUser lifts the lock of a user. Unlocking an active user changes nothing.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The id of the user
+//
+// Returns:
+//   - error: errorx.ErrInvalidStatusTransition unless the account is locked or active,
+//     errorx.ErrUserNotFound, or a wrapped persistence error
+func (ls *AccountLockService) UnlockUser(ctx context.Context, id string) (err error) {
+	ctx, span := tracer.Start(ctx, "AccountLockService.UnlockUser")
+	defer func() { endSpan(span, err) }()
+
+	user, err := ls.userAdminPersistence.FindUserByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error finding user: %w", err)
+	}
+	return ls.unlock(ctx, user, events.AccountUnlockedByAdmin)
+}
+
+// LiftExpiredLocks unlocks the users whose lock has expired. Failures to unlock single users do
+// not stop the others from being unlocked.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//
+// Returns:
+//   - int64: The number of unlocked users
+//   - error: The joined errors of the users that could not be unlocked, or a wrapped persistence error
+func (ls *AccountLockService) LiftExpiredLocks(ctx context.Context) (int64, error) {
+	users, err := ls.userAdminPersistence.FindUsersWithExpiredLocks(ctx, ls.clock.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to find expired locks: %w", err)
+	}
+
+	var unlocked int64
+	var errs []error
+	for _, user := range users {
+		if err := ls.unlock(ctx, user, events.AccountUnlockedExpired); err != nil {
+			errs = append(errs, fmt.Errorf("failed to unlock user %s: %w", user.ID, err))
+			continue
+		}
+		unlocked++
+	}
+	return unlocked, errors.Join(errs...)
+}
+
+// unlock moves a locked user back to ACTIVE, stores the status and emits the UserStatusChanged
+// and AccountUnlocked events.
+func (ls *AccountLockService) unlock(ctx context.Context, user domain.User, reason string) error {
+	transition, err := user.Unlock(ls.clock.Now())
+	if err != nil || !transition.Changed() {
+		return err
+	}
+	if err := ls.userAdminPersistence.UpdateUserStatus(ctx, user.ID, user.Status); err != nil {
+		return fmt.Errorf("failed to unlock user: %w", err)
+	}
+
+	ls.eventDispatcher.Dispatch(ctx, statusChanged(user, transition))
+	ls.eventDispatcher.Dispatch(ctx, events.AccountUnlocked{Username: user.Username.String(), Reason: reason, At: transition.At})
+	return nil
+}
@@ -0,0 +1,164 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/ports/messaging"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// AccountMergeService handles the business logic for merging duplicate accounts of the same person,
+// e.g. after an import created an account for someone who had registered already.
+// It implements the MergeAccountsPort interface from the usecases package.
+type AccountMergeService struct {
+	userAdminPersistence persistence.UserAdminPersistencePort
+	mergePersistence     persistence.AccountMergePersistencePort
+	groupPersistence     persistence.GroupPersistencePort
+	sessionPersistence   persistence.SessionPersistencePort
+	roleRegistry         usecases.RoleRegistryPort
+	eventDispatcher      messaging.EventDispatcherPort
+	clock                system.ClockPort
+	canonicalizer        domain.Canonicalizer
+}
+
+// NewAccountMergeService creates a new instance of AccountMergeService.
+//
+// Parameters:
+//   - userAdminPersistence: An implementation of UserAdminPersistencePort for loading both accounts
+//   - mergePersistence: An implementation of AccountMergePersistencePort for storing the merged accounts
+//   - groupPersistence: An implementation of GroupPersistencePort for moving the group memberships
+//   - sessionPersistence: An implementation of SessionPersistencePort for revoking the sessions of the duplicate
+//     and outdating the access tokens of the primary account
+//   - roleRegistry: An implementation of RoleRegistryPort for the roles known to the application
+//   - eventDispatcher: An implementation of EventDispatcherPort for emitting domain events
+//   - clock: An implementation of ClockPort for reading the current time
+//   - canonicalizer: The rules under which usernames and email addresses count as duplicates
+//
+// Returns:
+//   - *AccountMergeService: A pointer to the newly created AccountMergeService
+func NewAccountMergeService(userAdminPersistence persistence.UserAdminPersistencePort, mergePersistence persistence.AccountMergePersistencePort, groupPersistence persistence.GroupPersistencePort, sessionPersistence persistence.SessionPersistencePort, roleRegistry usecases.RoleRegistryPort, eventDispatcher messaging.EventDispatcherPort, clock system.ClockPort, canonicalizer domain.Canonicalizer) *AccountMergeService {
+	return &AccountMergeService{userAdminPersistence, mergePersistence, groupPersistence, sessionPersistence, roleRegistry, eventDispatcher, clock, canonicalizer}
+}
+
+// MergeAccounts merges a duplicate account into a primary account.
+//
+// This method performs the following steps:
+// 1. Loads both accounts and merges them by the rules of domain.MergeAccounts.
+// 2. Revokes the sessions of the duplicate, emitting a SessionRevoked event for each.
+// 3. Stores the deleted duplicate and the primary account with the roles, email address and aliases it gained.
+// 4. Moves the group memberships of the duplicate to the primary account.
+// 5. Emits an AccountsMerged event, which links the audit trails of both accounts, and a UserRoleChanged event if the roles changed.
+//
+// Consents, profile, data exports and login records of the duplicate are not moved; they stay
+// with the deleted duplicate until the retention job purges it.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - primaryID: The id of the account that remains
+//   - duplicateID: The id of the account that is merged into it
+//   - disposition: Whether the username of the duplicate is released or kept as alias
+//   - mergedBy: The administrator merging the accounts
+//
+// Returns:
+//   - domain.AccountMerge: The merged accounts, without password hashes
+//   - error: errorx.ErrUserNotFound if an account does not exist, errorx.ErrInvalidMerge, errorx.ErrEmailTaken,
+//     or a wrapped persistence error
+func (ms *AccountMergeService) MergeAccounts(ctx context.Context, primaryID string, duplicateID string, disposition domain.MergeUsernameDisposition, mergedBy string) (merge domain.AccountMerge, err error) {
+	ctx, span := tracer.Start(ctx, "AccountMergeService.MergeAccounts")
+	defer func() { endSpan(span, err) }()
+
+	primary, err := ms.userAdminPersistence.FindUserByID(ctx, primaryID)
+	if err != nil {
+		return domain.AccountMerge{}, fmt.Errorf("error finding primary account: %w", err)
+	}
+	duplicate, err := ms.userAdminPersistence.FindUserByID(ctx, duplicateID)
+	if err != nil {
+		return domain.AccountMerge{}, fmt.Errorf("error finding duplicate account: %w", err)
+	}
+	now := ms.clock.Now()
+	merge, err = domain.MergeAccounts(primary, duplicate, disposition, ms.roleRegistry.RolePermissions(), ms.canonicalizer, now)
+	if err != nil {
+		return domain.AccountMerge{}, err
+	}
+
+	if err := ms.revokeSessions(ctx, duplicate, now); err != nil {
+		return domain.AccountMerge{}, err
+	}
+	if err := ms.mergePersistence.SaveMergedAccounts(ctx, merge); err != nil {
+		return domain.AccountMerge{}, err
+	}
+	if err := ms.moveGroupMemberships(ctx, primary.ID, duplicate.ID); err != nil {
+		return domain.AccountMerge{}, err
+	}
+
+	ms.eventDispatcher.Dispatch(ctx, events.AccountsMerged{
+		PrimaryID:         primary.ID,
+		Username:          merge.Primary.Username.String(),
+		DuplicateID:       duplicate.ID,
+		DuplicateUsername: merge.DuplicateUsername.String(),
+		Disposition:       string(disposition),
+		MergedBy:          mergedBy,
+		At:                now,
+	})
+	if merge.RolesChanged {
+		if err := outdateAccessTokens(ctx, ms.sessionPersistence, merge.Primary); err != nil {
+			logger.ErrorContext(ctx, "Error outdating access tokens after merge", "username", merge.Primary.Username.String(), "error", err)
+		}
+		ms.eventDispatcher.Dispatch(ctx, events.UserRoleChanged{Username: merge.Primary.Username.String(), Previous: roleNames(merge.PreviousRoles), Roles: roleNames(merge.Primary.Roles), At: now})
+	}
+
+	merge.Primary = merge.Primary.WithoutPassword()
+	merge.Duplicate = merge.Duplicate.WithoutPassword()
+	return merge, nil
+}
+
+// revokeSessions ends the active sessions of the duplicate account.
+func (ms *AccountMergeService) revokeSessions(ctx context.Context, duplicate domain.User, now time.Time) error {
+	sessions, err := ms.sessionPersistence.FindSessionsByUser(ctx, duplicate.ID)
+	if err != nil {
+		return fmt.Errorf("error finding sessions: %w", err)
+	}
+	for _, session := range sessions {
+		if err := session.Revoke(domain.SessionRevokedAccountMerged, now); err != nil {
+			continue
+		}
+		if err := ms.sessionPersistence.SaveSession(ctx, session); err != nil {
+			return fmt.Errorf("failed to revoke session: %w", err)
+		}
+		ms.eventDispatcher.Dispatch(ctx, events.SessionRevoked{
+			Username:  duplicate.Username.String(),
+			SessionID: session.ID,
+			Reason:    string(domain.SessionRevokedAccountMerged),
+			At:        now,
+		})
+	}
+	return nil
+}
+
+// moveGroupMemberships makes the primary account a member of the groups of the duplicate and
+// removes the duplicate from them.
+func (ms *AccountMergeService) moveGroupMemberships(ctx context.Context, primaryID string, duplicateID string) error {
+	groups, err := ms.groupPersistence.FindGroupsByMember(ctx, duplicateID)
+	if err != nil {
+		return fmt.Errorf("error finding groups: %w", err)
+	}
+	for _, group := range groups {
+		if !slices.Contains(group.Members, primaryID) {
+			if err := ms.groupPersistence.AddGroupMember(ctx, group.Name, primaryID); err != nil {
+				return fmt.Errorf("failed to add member to group %s: %w", group.Name, err)
+			}
+		}
+		if err := ms.groupPersistence.RemoveGroupMember(ctx, group.Name, duplicateID); err != nil {
+			return fmt.Errorf("failed to remove member from group %s: %w", group.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+)
+
+// AccountRetentionService handles the business logic for archiving stale accounts
+// and purging soft-deleted ones.
+// It implements the AccountRetentionPort interface from the usecases package.
+type AccountRetentionService struct {
+	retentionPersistence persistence.UserRetentionPersistencePort
+	clock                system.ClockPort
+	inactivityPeriod     time.Duration
+	deletionRetention    time.Duration
+}
+
+// NewAccountRetentionService creates a new instance of AccountRetentionService.
+//
+// Parameters:
+//   - retentionPersistence: An implementation of UserRetentionPersistencePort for archiving and purging user data
+//   - clock: An implementation of ClockPort for reading the current time
+//   - inactivityPeriod: How long a user may go without logging in before being archived
+//   - deletionRetention: How long a soft-deleted user is kept before being purged
+//
+// Returns:
+//   - *AccountRetentionService: A pointer to the newly created AccountRetentionService
+func NewAccountRetentionService(retentionPersistence persistence.UserRetentionPersistencePort, clock system.ClockPort, inactivityPeriod time.Duration, deletionRetention time.Duration) *AccountRetentionService {
+	return &AccountRetentionService{retentionPersistence, clock, inactivityPeriod, deletionRetention}
+}
+
+// ArchiveInactiveUsers moves every user whose last activity is older than the
+// configured inactivity period into the archive.
+//
+// A user that never logged in is judged by its registration date.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//
+// Returns:
+//   - int64: The number of archived users
+//   - error: An error if archiving fails
+func (rs *AccountRetentionService) ArchiveInactiveUsers(ctx context.Context) (int64, error) {
+	if rs.inactivityPeriod <= 0 {
+		return 0, nil
+	}
+
+	cutoff := rs.clock.Now().Add(-rs.inactivityPeriod)
+	archived, err := rs.retentionPersistence.ArchiveUsersInactiveSince(ctx, cutoff)
+	if err != nil {
+		return archived, fmt.Errorf("failed to archive inactive users: %w", err)
+	}
+	return archived, nil
+}
+
+// PurgeDeletedUsers permanently removes soft-deleted users whose retention window has elapsed.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//
+// Returns:
+//   - int64: The number of purged users
+//   - error: An error if purging fails
+func (rs *AccountRetentionService) PurgeDeletedUsers(ctx context.Context) (int64, error) {
+	cutoff := rs.clock.Now().Add(-rs.deletionRetention)
+	purged, err := rs.retentionPersistence.PurgeUsersDeletedBefore(ctx, cutoff)
+	if err != nil {
+		return purged, fmt.Errorf("failed to purge deleted users: %w", err)
+	}
+	return purged, nil
+}
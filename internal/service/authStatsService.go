@@ -0,0 +1,84 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+)
+
+// AuthStatsService handles the business logic for the statistics administrators report on:
+// registrations per day, active users, login success and failure ratios and MFA adoption.
+// The counting is left to aggregations of the stores, so no records are loaded into the service.
+// It implements the GetAuthStatsPort interface from the usecases package.
+type AuthStatsService struct {
+	userStats  persistence.UserStatsPersistencePort
+	loginStats persistence.LoginStatsPersistencePort
+	clock      system.ClockPort
+}
+
+// NewAuthStatsService creates a new instance of AuthStatsService.
+//
+// Parameters:
+//   - userStats: An implementation of UserStatsPersistencePort for counting registrations and MFA adoption
+//   - loginStats: An implementation of LoginStatsPersistencePort for aggregating the login records
+//   - clock: An implementation of ClockPort for the default period
+//
+// Returns:
+//   - *AuthStatsService: A pointer to the newly created AuthStatsService
+func NewAuthStatsService(userStats persistence.UserStatsPersistencePort, loginStats persistence.LoginStatsPersistencePort, clock system.ClockPort) *AuthStatsService {
+	return &AuthStatsService{userStats, loginStats, clock}
+}
+
+// GetAuthStats aggregates the statistics of the days from up to but excluding until.
+//
+// A zero until selects the end of today, a zero from the domain.DefaultStatsPeriod before until.
+// Every day of the period is reported, days without registrations with a count of zero.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - from: A time on the first day of the period
+//   - until: A time on the day after the last day of the period
+//
+// Returns:
+//   - domain.AuthStats: The statistics of the period
+//   - error: errorx.ErrInvalidStatsPeriod if the period is empty or too long, or a wrapped persistence error
+func (ss *AuthStatsService) GetAuthStats(ctx context.Context, from time.Time, until time.Time) (stats domain.AuthStats, err error) {
+	ctx, span := tracer.Start(ctx, "AuthStatsService.GetAuthStats")
+	defer func() { endSpan(span, err) }()
+
+	if until.IsZero() {
+		until = ss.clock.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	}
+	if from.IsZero() {
+		from = until.Add(-domain.DefaultStatsPeriod)
+	}
+	period, err := domain.NewStatsPeriod(from, until)
+	if err != nil {
+		return domain.AuthStats{}, err
+	}
+
+	registrations, err := ss.userStats.CountRegistrationsPerDay(ctx, period)
+	if err != nil {
+		return domain.AuthStats{}, fmt.Errorf("error counting registrations: %w", err)
+	}
+	logins, err := ss.loginStats.AggregateLogins(ctx, period)
+	if err != nil {
+		return domain.AuthStats{}, fmt.Errorf("error aggregating logins: %w", err)
+	}
+	mfa, err := ss.userStats.CountMfaAdoption(ctx)
+	if err != nil {
+		return domain.AuthStats{}, fmt.Errorf("error counting mfa adoption: %w", err)
+	}
+
+	return domain.AuthStats{
+		Period:        period,
+		Registrations: period.FillDays(registrations),
+		Logins:        logins,
+		Mfa:           mfa,
+	}, nil
+}
@@ -0,0 +1,67 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"github.com/golang-jwt/jwt/v5"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// CapabilityService describes the capabilities of the deployment as they apply to the tenant of a request.
+// It implements the DescribeCapabilitiesPort interface from the usecases package.
+type CapabilityService struct {
+	tenantRegistry usecases.TenantRegistryPort
+	features       system.FeatureFlagPort
+}
+
+// NewCapabilityService creates a new instance of CapabilityService.
+//
+// Parameters:
+//   - tenantRegistry: An implementation of TenantRegistryPort for the settings of the tenant
+//   - features: An implementation of FeatureFlagPort for the flags switched on for the tenant
+//
+// Returns:
+//   - *CapabilityService: A pointer to the newly created CapabilityService
+func NewCapabilityService(tenantRegistry usecases.TenantRegistryPort, features system.FeatureFlagPort) *CapabilityService {
+	return &CapabilityService{tenantRegistry, features}
+}
+
+// DescribeCapabilities collects the login and MFA methods, the token and password settings of the
+// tenant of the request and the feature flags in effect for it.
+//
+// MFA counts as required if the tenant requires it or the require-mfa flag is on. No social login
+// providers are built in, so their list is empty.
+//
+// Parameters:
+//   - ctx: A context.Context naming the tenant of the request
+//
+// Returns:
+//   - usecases.Capabilities: The capabilities
+//   - error: errorx.ErrTenantNotFound if the tenant of the request does not exist
+func (cs *CapabilityService) DescribeCapabilities(ctx context.Context) (usecases.Capabilities, error) {
+	tenant, err := cs.tenantRegistry.ResolveTenant(ctx)
+	if err != nil {
+		return usecases.Capabilities{}, err
+	}
+
+	features := make(map[domain.FeatureFlag]bool)
+	for _, flag := range domain.KnownFeatureFlags() {
+		features[flag] = cs.features.Enabled(ctx, flag)
+	}
+
+	return usecases.Capabilities{
+		TenantID:               tenant.ID,
+		Registration:           features[domain.FeatureOpenRegistration],
+		LoginMethods:           tenant.Settings.LoginMethods,
+		MfaMethods:             domain.MfaMethods(),
+		MfaRequired:            tenant.Settings.RequireMFA || features[domain.FeatureRequireMFA],
+		SocialProviders:        []string{},
+		TokenSigningAlgorithms: []string{jwt.SigningMethodHS256.Alg()},
+		AccessTokenTTL:         tenant.Settings.AccessTokenTTL,
+		PasswordPolicy:         tenant.Settings.PasswordPolicy,
+		Features:               features,
+	}, nil
+}
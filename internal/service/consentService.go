@@ -0,0 +1,193 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"fmt"
+	"user-auth-hexagonal-architecture/internal/device"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/ports/messaging"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+)
+
+// ConsentService handles the business logic for the acceptance of the terms and the privacy policy.
+// It implements the ConsentDocumentsPort, UserConsentPort and ConsentGatePort interfaces from the usecases package.
+//
+// As long as no version of a document has been published, nobody has to accept it.
+type ConsentService struct {
+	consentPersistence persistence.ConsentPersistencePort
+	userPersistence    persistence.UserPersistencePort
+	eventDispatcher    messaging.EventDispatcherPort
+	clock              system.ClockPort
+}
+
+// NewConsentService creates a new instance of ConsentService.
+//
+// Parameters:
+//   - consentPersistence: An implementation of ConsentPersistencePort for storing document versions and consents
+//   - userPersistence: An implementation of UserPersistencePort for retrieving the consenting user
+//   - eventDispatcher: An implementation of EventDispatcherPort for emitting domain events
+//   - clock: An implementation of ClockPort for reading the current time
+//
+// Returns:
+//   - *ConsentService: A pointer to the newly created ConsentService
+func NewConsentService(consentPersistence persistence.ConsentPersistencePort, userPersistence persistence.UserPersistencePort, eventDispatcher messaging.EventDispatcherPort, clock system.ClockPort) *ConsentService {
+	return &ConsentService{consentPersistence, userPersistence, eventDispatcher, clock}
+}
+
+// CurrentDocumentVersions returns the current version of every published document.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//
+// Returns:
+//   - []domain.DocumentVersion: The current versions
+//   - error: A wrapped persistence error
+func (cs *ConsentService) CurrentDocumentVersions(ctx context.Context) ([]domain.DocumentVersion, error) {
+	current, err := cs.consentPersistence.FindCurrentDocumentVersions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find document versions: %w", err)
+	}
+	return current, nil
+}
+
+// PublishDocumentVersion publishes a new version of a document. From then on, registrations and
+// logins have to accept it, so existing users are asked for their consent again at their next login.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - document: The document
+//   - version: The label of the new version
+//
+// Returns:
+//   - domain.DocumentVersion: The published version
+//   - error: errorx.ErrInvalidConsent if the document or version is malformed or the version has been
+//     published before, or a wrapped persistence error
+func (cs *ConsentService) PublishDocumentVersion(ctx context.Context, document domain.ConsentDocument, version string) (domain.DocumentVersion, error) {
+	published, err := domain.NewDocumentVersion(document, version, cs.clock.Now())
+	if err != nil {
+		return domain.DocumentVersion{}, err
+	}
+	if err := cs.consentPersistence.SaveDocumentVersion(ctx, published); err != nil {
+		return domain.DocumentVersion{}, fmt.Errorf("failed to publish document version: %w", err)
+	}
+	return published, nil
+}
+
+// GetConsents returns the consent history of a user and the document versions the user has not accepted yet.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - username: The username of the user
+//
+// Returns:
+//   - domain.ConsentStatus: The consents, the most recent first, and the outstanding versions
+//   - error: errorx.ErrUserNotFound, or a wrapped persistence error
+func (cs *ConsentService) GetConsents(ctx context.Context, username string) (domain.ConsentStatus, error) {
+	user, err := cs.userPersistence.FindUser(ctx, domain.NormalizeUsername(username))
+	if err != nil {
+		return domain.ConsentStatus{}, fmt.Errorf("error finding user: %w", err)
+	}
+	current, err := cs.CurrentDocumentVersions(ctx)
+	if err != nil {
+		return domain.ConsentStatus{}, err
+	}
+	consents, err := cs.consentPersistence.FindConsents(ctx, user.ID)
+	if err != nil {
+		return domain.ConsentStatus{}, fmt.Errorf("failed to find consents: %w", err)
+	}
+	return domain.ConsentStatus{Consents: consents, Outstanding: domain.OutstandingDocuments(current, consents)}, nil
+}
+
+// AcceptConsents records that a signed-in user accepts document versions, recording the IP address
+// the request is made from.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation, carrying the device of the request
+//   - username: The username of the user
+//   - refs: The accepted document versions
+//
+// Returns:
+//   - error: errorx.ErrUserNotFound, errorx.ErrInvalidConsent if a reference does not name a current
+//     version, or a wrapped persistence error
+func (cs *ConsentService) AcceptConsents(ctx context.Context, username string, refs []domain.ConsentRef) error {
+	user, err := cs.userPersistence.FindUser(ctx, domain.NormalizeUsername(username))
+	if err != nil {
+		return fmt.Errorf("error finding user: %w", err)
+	}
+	current, err := cs.CurrentDocumentVersions(ctx)
+	if err != nil {
+		return err
+	}
+	consents, err := domain.AcceptConsents(user, refs, current, device.FromContext(ctx), cs.clock.Now())
+	if err != nil {
+		return err
+	}
+	return cs.RecordConsents(ctx, consents)
+}
+
+// CheckConsents checks that a user accepts every current document version, either now with refs or
+// with an earlier consent. Users without id, i.e. registering users, have no earlier consents.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation, carrying the device of the request
+//   - user: The registering or logging in user
+//   - refs: The document versions the user accepts with the request
+//
+// Returns:
+//   - []domain.Consent: The consents to record once the registration or login succeeded
+//   - error: errorx.ErrConsentRequired naming the outstanding versions, errorx.ErrInvalidConsent if a
+//     reference does not name a current version, or a wrapped persistence error
+func (cs *ConsentService) CheckConsents(ctx context.Context, user domain.User, refs []domain.ConsentRef) ([]domain.Consent, error) {
+	current, err := cs.CurrentDocumentVersions(ctx)
+	if err != nil || len(current) == 0 {
+		return nil, err
+	}
+	consents, err := domain.AcceptConsents(user, refs, current, device.FromContext(ctx), cs.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	accepted := consents
+	if user.ID != "" {
+		earlier, err := cs.consentPersistence.FindConsents(ctx, user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find consents: %w", err)
+		}
+		accepted = append(earlier, consents...)
+	}
+	if outstanding := domain.OutstandingDocuments(current, accepted); len(outstanding) > 0 {
+		return nil, domain.ConsentRequired(outstanding)
+	}
+	return consents, nil
+}
+
+// RecordConsents stores consents and emits a ConsentAccepted event for each.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - consents: The consents, with the id of the user set
+//
+// Returns:
+//   - error: A wrapped persistence error
+func (cs *ConsentService) RecordConsents(ctx context.Context, consents []domain.Consent) error {
+	if len(consents) == 0 {
+		return nil
+	}
+	if err := cs.consentPersistence.AppendConsents(ctx, consents); err != nil {
+		return fmt.Errorf("failed to record consents: %w", err)
+	}
+	for _, consent := range consents {
+		cs.eventDispatcher.Dispatch(ctx, events.ConsentAccepted{
+			UserID:   consent.UserID,
+			Username: consent.Username.String(),
+			Document: string(consent.Document),
+			Version:  consent.Version,
+			At:       consent.AcceptedAt,
+		})
+	}
+	return nil
+}
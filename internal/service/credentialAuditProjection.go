@@ -0,0 +1,97 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+)
+
+// CredentialAuditProjection records credential-related domain events in the credential audit trail,
+// so the use cases changing passwords, locking accounts or changing their status do not have to
+// write the trail themselves.
+// It implements the EventHandler interface from the messaging ports package.
+type CredentialAuditProjection struct {
+	credentialEventStore persistence.CredentialEventStorePort
+}
+
+// NewCredentialAuditProjection creates a new instance of CredentialAuditProjection.
+//
+// Parameters:
+//   - credentialEventStore: An implementation of CredentialEventStorePort for appending to the audit trail
+//
+// Returns:
+//   - *CredentialAuditProjection: A pointer to the newly created CredentialAuditProjection
+func NewCredentialAuditProjection(credentialEventStore persistence.CredentialEventStorePort) *CredentialAuditProjection {
+	return &CredentialAuditProjection{credentialEventStore}
+}
+
+// Handle appends a credential event for password changes, password hash upgrades, second factor
+// changes, account locks and unlocks, account status transitions, role changes and account merges.
+// Other events are ignored.
+//
+// A merge is recorded in the trails of both accounts, each referring to the other account, so
+// the history of the duplicate can be found from the primary account and vice versa.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - event: The domain event to record
+//
+// Returns:
+//   - error: An error if the credential event cannot be appended
+func (p *CredentialAuditProjection) Handle(ctx context.Context, event events.Event) error {
+	var credentialEvent domain.CredentialEvent
+	switch e := event.(type) {
+	case events.PasswordChanged:
+		credentialEvent = domain.NewCredentialEvent(e.Username, domain.CredentialPasswordChanged, nil)
+	case events.PasswordHashUpgraded:
+		credentialEvent = domain.NewCredentialEvent(e.Username, domain.CredentialHashUpgraded, map[string]string{domain.CredentialDetailAlgorithm: e.Algorithm})
+	case events.MfaEnabled:
+		credentialEvent = domain.NewCredentialEvent(e.Username, domain.CredentialMfaEnrolled, map[string]string{domain.CredentialDetailMethod: e.Method})
+	case events.MfaDisabled:
+		credentialEvent = domain.NewCredentialEvent(e.Username, domain.CredentialMfaRemoved, map[string]string{domain.CredentialDetailMethod: e.Method})
+	case events.AccountLocked:
+		details := map[string]string{domain.CredentialDetailReason: e.Reason}
+		if !e.Until.IsZero() {
+			details[domain.CredentialDetailUntil] = e.Until.UTC().Format(time.RFC3339)
+		}
+		credentialEvent = domain.NewCredentialEvent(e.Username, domain.CredentialLockoutApplied, details)
+	case events.AccountUnlocked:
+		credentialEvent = domain.NewCredentialEvent(e.Username, domain.CredentialLockoutLifted, map[string]string{domain.CredentialDetailReason: e.Reason})
+	case events.UserStatusChanged:
+		details := map[string]string{domain.CredentialDetailFrom: e.From, domain.CredentialDetailTo: e.Status}
+		credentialEvent = domain.NewCredentialEvent(e.Username, domain.CredentialStatusChanged, details)
+	case events.UserRoleChanged:
+		details := map[string]string{domain.CredentialDetailFrom: strings.Join(e.Previous, ","), domain.CredentialDetailTo: strings.Join(e.Roles, ",")}
+		credentialEvent = domain.NewCredentialEvent(e.Username, domain.CredentialRolesChanged, details)
+	case events.AccountsMerged:
+		details := map[string]string{domain.CredentialDetailFrom: e.DuplicateUsername, domain.CredentialDetailTo: e.Username}
+		duplicateEvent := domain.NewCredentialEvent(e.DuplicateUsername, domain.CredentialAccountMerged, withDetail(details, domain.CredentialDetailAccountID, e.PrimaryID))
+		duplicateEvent.OccurredAt = e.At
+		if err := p.credentialEventStore.AppendCredentialEvent(ctx, duplicateEvent); err != nil {
+			return fmt.Errorf("failed to record %s: %w", event.Name(), err)
+		}
+		credentialEvent = domain.NewCredentialEvent(e.Username, domain.CredentialAccountMerged, withDetail(details, domain.CredentialDetailAccountID, e.DuplicateID))
+	default:
+		return nil
+	}
+
+	credentialEvent.OccurredAt = event.OccurredAt()
+	if err := p.credentialEventStore.AppendCredentialEvent(ctx, credentialEvent); err != nil {
+		return fmt.Errorf("failed to record %s: %w", event.Name(), err)
+	}
+	return nil
+}
+
+// withDetail returns a copy of the details with an additional entry.
+func withDetail(details map[string]string, key string, value string) map[string]string {
+	extended := maps.Clone(details)
+	extended[key] = value
+	return extended
+}
@@ -0,0 +1,44 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"fmt"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+)
+
+// CredentialAuditService reconstructs the security timeline of accounts from their credential events.
+// It implements the SecurityTimelinePort interface from the usecases package.
+type CredentialAuditService struct {
+	credentialEventStore persistence.CredentialEventStorePort
+}
+
+// NewCredentialAuditService creates a new instance of CredentialAuditService.
+//
+// Parameters:
+//   - credentialEventStore: An implementation of CredentialEventStorePort for reading credential events
+//
+// Returns:
+//   - *CredentialAuditService: A pointer to the newly created CredentialAuditService
+func NewCredentialAuditService(credentialEventStore persistence.CredentialEventStorePort) *CredentialAuditService {
+	return &CredentialAuditService{credentialEventStore}
+}
+
+// GetSecurityTimeline loads the credential event stream of a user and replays it.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - username: The user whose timeline should be reconstructed
+//
+// Returns:
+//   - domain.SecurityTimeline: The reconstructed timeline
+//   - error: An error if the events cannot be loaded
+func (cs *CredentialAuditService) GetSecurityTimeline(ctx context.Context, username string) (domain.SecurityTimeline, error) {
+	events, err := cs.credentialEventStore.LoadCredentialEvents(ctx, username)
+	if err != nil {
+		return domain.SecurityTimeline{}, fmt.Errorf("failed to load credential events: %w", err)
+	}
+	return domain.ReplayCredentialEvents(username, events), nil
+}
@@ -0,0 +1,324 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/ports/messaging"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/security"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// dataExportLease is how long a data export is reserved for the worker generating it. Exports of
+// a worker that stopped are generated again once the lease expired.
+const dataExportLease = 10 * time.Minute
+
+// downloadTokenPurpose separates the signatures of download tokens from other signatures made with the same key.
+const downloadTokenPurpose = "data-export:"
+
+// DataExportService handles the business logic for exporting everything stored about a user.
+// It implements the ExportUserDataPort, DownloadDataExportPort and GenerateDataExportsPort interfaces
+// from the usecases package.
+//
+// Requested exports are generated by GenerateDataExports, which runs as a scheduled job, so the
+// generation stops with the server and exports interrupted by a restart are picked up again.
+// The archive is a ZIP file with one JSON file per kind of record. Fields classified as credentials
+// are left out, see domain.ExportableFields.
+type DataExportService struct {
+	userPersistence       persistence.UserPersistencePort
+	profilePersistence    persistence.ProfilePersistencePort
+	sessionPersistence    persistence.SessionPersistencePort
+	consentPersistence    persistence.ConsentPersistencePort
+	credentialEventStore  persistence.CredentialEventStorePort
+	loginAuditStore       persistence.LoginAuditStorePort
+	dataExportPersistence persistence.DataExportPersistencePort
+	eventDispatcher       messaging.EventDispatcherPort
+	clock                 system.ClockPort
+	random                system.RandomSourcePort
+	signingKeys           security.SigningKeyPort
+	ttl                   time.Duration
+}
+
+// NewDataExportService creates a new instance of DataExportService.
+//
+// Parameters:
+//   - userPersistence: An implementation of UserPersistencePort for retrieving the user
+//   - profilePersistence: An implementation of ProfilePersistencePort for retrieving the profile
+//   - sessionPersistence: An implementation of SessionPersistencePort for retrieving the sessions
+//   - consentPersistence: An implementation of ConsentPersistencePort for retrieving the consents
+//   - credentialEventStore: An implementation of CredentialEventStorePort for retrieving the audit trail
+//   - loginAuditStore: An implementation of LoginAuditStorePort for retrieving the login history
+//   - dataExportPersistence: An implementation of DataExportPersistencePort for storing exports and their archives
+//   - eventDispatcher: An implementation of EventDispatcherPort for emitting domain events
+//   - clock: An implementation of ClockPort for reading the current time
+//   - random: An implementation of RandomSourcePort for generating export ids
+//   - signingKeys: An implementation of SigningKeyPort providing the keys download tokens are signed with
+//   - ttl: How long an export can be downloaded after it was requested
+//
+// Returns:
+//   - *DataExportService: A pointer to the newly created DataExportService
+func NewDataExportService(userPersistence persistence.UserPersistencePort, profilePersistence persistence.ProfilePersistencePort, sessionPersistence persistence.SessionPersistencePort, consentPersistence persistence.ConsentPersistencePort, credentialEventStore persistence.CredentialEventStorePort, loginAuditStore persistence.LoginAuditStorePort, dataExportPersistence persistence.DataExportPersistencePort, eventDispatcher messaging.EventDispatcherPort, clock system.ClockPort, random system.RandomSourcePort, signingKeys security.SigningKeyPort, ttl time.Duration) *DataExportService {
+	return &DataExportService{userPersistence, profilePersistence, sessionPersistence, consentPersistence, credentialEventStore, loginAuditStore, dataExportPersistence, eventDispatcher, clock, random, signingKeys, ttl}
+}
+
+// ExportUserData starts the export of everything stored about a signed-in user. The archive is
+// generated by GenerateDataExports; its progress is polled with GetDataExport.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - username: The username of the user
+//
+// Returns:
+//   - usecases.DataExportView: The pending export
+//   - error: errorx.ErrUserNotFound, or a wrapped persistence error
+func (ds *DataExportService) ExportUserData(ctx context.Context, username string) (view usecases.DataExportView, err error) {
+	ctx, span := tracer.Start(ctx, "DataExportService.ExportUserData")
+	defer func() { endSpan(span, err) }()
+
+	user, err := ds.userPersistence.FindUser(ctx, domain.NormalizeUsername(username))
+	if err != nil {
+		return usecases.DataExportView{}, fmt.Errorf("error finding user: %w", err)
+	}
+	id := make([]byte, 16)
+	if _, err := ds.random.Read(id); err != nil {
+		return usecases.DataExportView{}, fmt.Errorf("error generating export id: %w", err)
+	}
+	now := ds.clock.Now()
+	export := domain.NewDataExport(hex.EncodeToString(id), user, now, now.Add(ds.ttl))
+	if err := ds.dataExportPersistence.SaveDataExport(ctx, export); err != nil {
+		return usecases.DataExportView{}, fmt.Errorf("failed to save data export: %w", err)
+	}
+
+	ds.eventDispatcher.Dispatch(ctx, events.DataExportRequested{Username: user.Username.String(), ExportID: export.ID, At: now})
+	return ds.toView(export), nil
+}
+
+// GetDataExport returns the state of a data export of a signed-in user, including the download
+// token once the archive is ready. Exports of other users are reported as not found.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - username: The username of the user
+//   - id: The id of the export
+//
+// Returns:
+//   - usecases.DataExportView: The export
+//   - error: errorx.ErrDataExportNotFound, or a wrapped persistence error
+func (ds *DataExportService) GetDataExport(ctx context.Context, username string, id string) (usecases.DataExportView, error) {
+	export, err := ds.dataExportPersistence.FindDataExport(ctx, id)
+	if err != nil {
+		return usecases.DataExportView{}, err
+	}
+	if export.Username != domain.NormalizeUsername(username) || export.IsExpired(ds.clock.Now()) {
+		return usecases.DataExportView{}, errorx.ErrDataExportNotFound
+	}
+	return ds.toView(export), nil
+}
+
+// DownloadDataExport returns the archive of a data export authorized by a download token.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - token: The download token handed out by GetDataExport
+//
+// Returns:
+//   - []byte: The ZIP archive
+//   - error: errorx.ErrInvalidDownloadLink if the token is malformed, forged or expired,
+//     errorx.ErrDataExportNotFound, errorx.ErrDataExportNotReady, or a wrapped persistence error
+func (ds *DataExportService) DownloadDataExport(ctx context.Context, token string) ([]byte, error) {
+	id, expiresAt, ok := ds.verifyDownloadToken(token)
+	if !ok || !ds.clock.Now().Before(expiresAt) {
+		return nil, errorx.ErrInvalidDownloadLink
+	}
+	export, err := ds.dataExportPersistence.FindDataExport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if export.Status != domain.DataExportReady {
+		return nil, errorx.ErrDataExportNotReady
+	}
+	return ds.dataExportPersistence.LoadDataExportArchive(ctx, id)
+}
+
+// GenerateDataExports generates the archives of the pending data exports one after another until
+// none is left or ctx is cancelled, and removes the archives of expired exports.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//
+// Returns:
+//   - int: The number of generated exports, including the failed ones
+//   - error: A wrapped persistence error
+func (ds *DataExportService) GenerateDataExports(ctx context.Context) (int, error) {
+	generated := 0
+	for ctx.Err() == nil {
+		export, err := ds.dataExportPersistence.ClaimPendingDataExport(ctx, dataExportLease)
+		if errors.Is(err, errorx.ErrDataExportNotFound) {
+			break
+		}
+		if err != nil {
+			return generated, fmt.Errorf("failed to claim data export: %w", err)
+		}
+		if err := ds.generate(ctx, export); err != nil {
+			return generated, err
+		}
+		generated++
+	}
+
+	if _, err := ds.dataExportPersistence.DeleteExpiredDataExportArchives(ctx, ds.clock.Now()); err != nil {
+		return generated, fmt.Errorf("failed to delete expired data export archives: %w", err)
+	}
+	return generated, nil
+}
+
+// generate collects the data of the user into the archive of the export and stores it.
+// Failures to collect the data are logged and mark the export as failed; failures to store the
+// export are returned, leaving it to be generated again once its lease expired.
+func (ds *DataExportService) generate(ctx context.Context, export domain.DataExport) error {
+	user, err := ds.userPersistence.FindUser(ctx, export.Username)
+	var archive []byte
+	if err == nil {
+		archive, err = ds.buildArchive(ctx, user)
+	}
+	if err == nil {
+		err = ds.dataExportPersistence.SaveDataExportArchive(ctx, export, archive)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		logger.ErrorContext(ctx, "Error generating data export", "export_id", export.ID, "error", err)
+		export.Fail(ds.clock.Now())
+	} else {
+		export.Complete(ds.clock.Now())
+	}
+	if err := ds.dataExportPersistence.SaveDataExport(ctx, export); err != nil {
+		return fmt.Errorf("failed to save data export: %w", err)
+	}
+	return nil
+}
+
+// buildArchive writes the records stored about a user as JSON files into a ZIP archive.
+func (ds *DataExportService) buildArchive(ctx context.Context, user domain.User) ([]byte, error) {
+	profile, err := ds.profilePersistence.FindProfile(ctx, user.Username)
+	if err != nil && !errors.Is(err, errorx.ErrUserNotFound) {
+		return nil, fmt.Errorf("failed to find profile: %w", err)
+	}
+	sessions, err := ds.sessionPersistence.FindSessionsByUser(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sessions: %w", err)
+	}
+	consents, err := ds.consentPersistence.FindConsents(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find consents: %w", err)
+	}
+	credentialEvents, err := ds.credentialEventStore.LoadCredentialEvents(ctx, user.Username.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load audit trail: %w", err)
+	}
+	loginRecords, err := ds.loginAuditStore.FindLoginRecords(ctx, domain.LoginRecordFilter{Username: user.Username})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find login history: %w", err)
+	}
+
+	files := []struct {
+		name    string
+		content any
+	}{
+		{"user.json", domain.ExportableFields(user)},
+		{"profile.json", domain.ExportableFields(profile)},
+		{"sessions.json", exportableRecords(sessions)},
+		{"consents.json", exportableRecords(consents)},
+		{"audit_trail.json", exportableRecords(credentialEvents)},
+		{"login_history.json", exportableRecords(loginRecords)},
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range files {
+		w, err := archive.Create(file.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add %s: %w", file.name, err)
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.content); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close archive: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// toView converts an export into its view, signing a download token for ready exports.
+func (ds *DataExportService) toView(export domain.DataExport) usecases.DataExportView {
+	view := usecases.DataExportView{
+		ID:          export.ID,
+		Status:      export.Status,
+		RequestedAt: export.RequestedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+	if export.Status == domain.DataExportReady {
+		payload := export.ID + "." + strconv.FormatInt(export.ExpiresAt.Unix(), 10)
+		view.DownloadToken = payload + "." + signDownloadToken(ds.signingKeys.SigningKey(), payload)
+	}
+	return view
+}
+
+// verifyDownloadToken checks the signature of a download token of the form "<id>.<expiry>.<signature>"
+// and returns the export id and the expiry it carries.
+func (ds *DataExportService) verifyDownloadToken(token string) (string, time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", time.Time{}, false
+	}
+	payload := parts[0] + "." + parts[1]
+	valid := false
+	for _, key := range ds.signingKeys.VerificationKeys() {
+		valid = valid || hmac.Equal([]byte(parts[2]), []byte(signDownloadToken(key, payload)))
+	}
+	if !valid {
+		return "", time.Time{}, false
+	}
+	expiresAt, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return parts[0], time.Unix(expiresAt, 0), true
+}
+
+// signDownloadToken returns the base64url encoded HMAC-SHA256 of a download token payload.
+func signDownloadToken(key []byte, payload string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(downloadTokenPurpose + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// exportableRecords returns the exportable fields of each record.
+func exportableRecords[T any](records []T) []map[string]any {
+	exported := make([]map[string]any, 0, len(records))
+	for _, record := range records {
+		exported = append(exported, domain.ExportableFields(record))
+	}
+	return exported
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/ports/messaging"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+)
+
+// maxEmailSuppressions caps the number of suppressions returned by a single listing.
+const maxEmailSuppressions = 1000
+
+// EmailSuppressionService maintains the list of addresses no notification emails are sent to. Email
+// providers report hard bounces and spam complaints through their callbacks; continuing to email
+// such addresses harms the reputation of the sender domain, so they are suppressed until an admin
+// lifts the suppression, e.g. after the user fixed the address.
+// It implements the RecordEmailFeedbackPort and ManageEmailSuppressionsPort interfaces from the usecases package.
+type EmailSuppressionService struct {
+	suppressionPersistence persistence.EmailSuppressionPersistencePort
+	clock                  system.ClockPort
+	sources                map[string]messaging.EmailFeedbackSourcePort
+}
+
+// NewEmailSuppressionService creates a new instance of EmailSuppressionService.
+//
+// Parameters:
+//   - suppressionPersistence: An implementation of EmailSuppressionPersistencePort for storing the suppressions
+//   - clock: An implementation of ClockPort for reading the current time
+//   - sources: The providers whose callbacks are accepted; callbacks of others are rejected
+//
+// Returns:
+//   - *EmailSuppressionService: A pointer to the newly created EmailSuppressionService
+func NewEmailSuppressionService(suppressionPersistence persistence.EmailSuppressionPersistencePort, clock system.ClockPort, sources ...messaging.EmailFeedbackSourcePort) *EmailSuppressionService {
+	byProvider := make(map[string]messaging.EmailFeedbackSourcePort, len(sources))
+	for _, source := range sources {
+		byProvider[source.Provider()] = source
+	}
+	return &EmailSuppressionService{suppressionPersistence, clock, byProvider}
+}
+
+// RecordEmailFeedback suppresses the addresses a provider callback reports as hard bounced or
+// complained about. Soft bounces are only logged.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - provider: The provider the callback is from, e.g. "sendgrid"
+//   - header: The headers of the callback, carrying its signature
+//   - body: The body of the callback
+//
+// Returns:
+//   - error: errorx.ErrInvalidEmailFeedback if the callback is not authentic or from an unknown
+//     provider, or a wrapped persistence error
+func (ss *EmailSuppressionService) RecordEmailFeedback(ctx context.Context, provider string, header http.Header, body []byte) error {
+	source, ok := ss.sources[provider]
+	if !ok {
+		return errorx.ErrInvalidEmailFeedback.Detailf("feedback of %q is not accepted", provider)
+	}
+	feedback, err := source.ParseFeedback(ctx, header, body)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range feedback {
+		if !item.Type.Suppresses() {
+			logger.InfoContext(ctx, "Email soft bounced", "provider", provider, "detail", item.Detail)
+			continue
+		}
+		address, err := domain.NewEmail(item.Address)
+		if err != nil {
+			logger.WarnContext(ctx, "Ignoring email feedback for a malformed address", "provider", provider, "type", item.Type)
+			continue
+		}
+		at := item.At
+		if at.IsZero() {
+			at = ss.clock.Now()
+		}
+		err = ss.suppressionPersistence.SaveEmailSuppression(ctx, domain.EmailSuppression{
+			Address:   address.String(),
+			Reason:    item.Type,
+			Provider:  provider,
+			Detail:    item.Detail,
+			CreatedAt: at,
+			UpdatedAt: at,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to save email suppression: %w", err)
+		}
+		logger.InfoContext(ctx, "Suppressed email address", "provider", provider, "reason", item.Type)
+	}
+	return nil
+}
+
+// ListEmailSuppressions returns the most recently suppressed addresses.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - limit: The maximum number of suppressions to return, capped at 1000
+//
+// Returns:
+//   - []domain.EmailSuppression: The suppressions, newest first
+//   - error: A wrapped persistence error
+func (ss *EmailSuppressionService) ListEmailSuppressions(ctx context.Context, limit int64) ([]domain.EmailSuppression, error) {
+	if limit <= 0 || limit > maxEmailSuppressions {
+		limit = maxEmailSuppressions
+	}
+	suppressions, err := ss.suppressionPersistence.FindEmailSuppressions(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list email suppressions: %w", err)
+	}
+	return suppressions, nil
+}
+
+// DeleteEmailSuppression lifts the suppression of an address, so it receives emails again.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - address: The suppressed address
+//
+// Returns:
+//   - error: errorx.ErrInvalidEmail for malformed addresses, errorx.ErrEmailSuppressionNotFound if
+//     the address is not suppressed, or a wrapped persistence error
+func (ss *EmailSuppressionService) DeleteEmailSuppression(ctx context.Context, address string) error {
+	email, err := domain.NewEmail(address)
+	if err != nil {
+		return err
+	}
+	if err := ss.suppressionPersistence.DeleteEmailSuppression(ctx, email.String()); err != nil {
+		return fmt.Errorf("failed to delete email suppression: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/ports/messaging"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+)
+
+// ErasureService handles the business logic for erasing the personal data of a user under the
+// right to erasure. It implements the EraseUserPort and LegalHoldPort interfaces from the usecases package.
+//
+// Unlike a deletion, which only deactivates the account until the retention job purges it, an
+// erasure takes effect immediately: the account, its profile, sessions, consents, login history and
+// data exports are removed, group memberships are ended and the credential audit trail is pseudonymized. An
+// erasure certificate documents what was erased, and a UserErased event tells webhook subscribers
+// to erase their copies.
+type ErasureService struct {
+	userErasurePersistence  persistence.UserErasurePersistencePort
+	sessionPersistence      persistence.SessionPersistencePort
+	consentPersistence      persistence.ConsentPersistencePort
+	credentialEventStore    persistence.CredentialEventStorePort
+	loginAuditStore         persistence.LoginAuditStorePort
+	dataExportPersistence   persistence.DataExportPersistencePort
+	groupPersistence        persistence.GroupPersistencePort
+	certificatePersistence  persistence.ErasureCertificatePersistencePort
+	eventDispatcher         messaging.EventDispatcherPort
+	clock                   system.ClockPort
+	random                  system.RandomSourcePort
+	allowLegalHoldOverrides bool
+}
+
+// NewErasureService creates a new instance of ErasureService.
+//
+// Parameters:
+//   - userErasurePersistence: An implementation of UserErasurePersistencePort for finding and erasing users
+//   - sessionPersistence: An implementation of SessionPersistencePort for removing the sessions
+//   - consentPersistence: An implementation of ConsentPersistencePort for removing the consents
+//   - credentialEventStore: An implementation of CredentialEventStorePort for pseudonymizing the audit trail
+//   - loginAuditStore: An implementation of LoginAuditStorePort for removing the login history
+//   - dataExportPersistence: An implementation of DataExportPersistencePort for removing data exports
+//   - groupPersistence: An implementation of GroupPersistencePort for ending group memberships
+//   - certificatePersistence: An implementation of ErasureCertificatePersistencePort for storing certificates
+//   - eventDispatcher: An implementation of EventDispatcherPort for emitting domain events
+//   - clock: An implementation of ClockPort for reading the current time
+//   - random: An implementation of RandomSourcePort for generating pseudonyms and certificate ids
+//   - allowLegalHoldOverrides: Whether an erasure may override a legal hold
+//
+// Returns:
+//   - *ErasureService: A pointer to the newly created ErasureService
+func NewErasureService(userErasurePersistence persistence.UserErasurePersistencePort, sessionPersistence persistence.SessionPersistencePort, consentPersistence persistence.ConsentPersistencePort, credentialEventStore persistence.CredentialEventStorePort, loginAuditStore persistence.LoginAuditStorePort, dataExportPersistence persistence.DataExportPersistencePort, groupPersistence persistence.GroupPersistencePort, certificatePersistence persistence.ErasureCertificatePersistencePort, eventDispatcher messaging.EventDispatcherPort, clock system.ClockPort, random system.RandomSourcePort, allowLegalHoldOverrides bool) *ErasureService {
+	return &ErasureService{userErasurePersistence, sessionPersistence, consentPersistence, credentialEventStore, loginAuditStore, dataExportPersistence, groupPersistence, certificatePersistence, eventDispatcher, clock, random, allowLegalHoldOverrides}
+}
+
+// EraseUser erases the personal data of a user, active, deleted or archived, and certifies the erasure.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The id of the user
+//   - requestedBy: The administrator the erasure is made on behalf of, recorded in the certificate
+//   - overrideLegalHold: Whether a legal hold on the user is overridden; only honored if overrides are allowed
+//
+// Returns:
+//   - domain.ErasureCertificate: The certificate of the erasure
+//   - error: errorx.ErrLegalHold, errorx.ErrUserNotFound, or a wrapped persistence error. An error
+//     after the first records were erased leaves the erasure incomplete; it can be repeated until the
+//     account itself is removed.
+func (es *ErasureService) EraseUser(ctx context.Context, id string, requestedBy string, overrideLegalHold bool) (certificate domain.ErasureCertificate, err error) {
+	ctx, span := tracer.Start(ctx, "ErasureService.EraseUser")
+	defer func() { endSpan(span, err) }()
+
+	user, err := es.userErasurePersistence.FindUserForErasure(ctx, id)
+	if err != nil {
+		return domain.ErasureCertificate{}, fmt.Errorf("error finding user: %w", err)
+	}
+	if user.LegalHold && overrideLegalHold && !es.allowLegalHoldOverrides {
+		return domain.ErasureCertificate{}, errorx.ErrLegalHold.Detailf("legal hold overrides are disabled")
+	}
+	if err := user.CheckErasable(overrideLegalHold); err != nil {
+		return domain.ErasureCertificate{}, err
+	}
+
+	certificateID, err := es.randomHex(16)
+	if err != nil {
+		return domain.ErasureCertificate{}, err
+	}
+	pseudonym, err := es.randomHex(8)
+	if err != nil {
+		return domain.ErasureCertificate{}, err
+	}
+	certificate = domain.ErasureCertificate{
+		ID:                  certificateID,
+		UserID:              user.ID,
+		TenantID:            user.TenantID,
+		Pseudonym:           "erased-" + pseudonym,
+		RequestedBy:         requestedBy,
+		LegalHoldOverridden: user.LegalHold,
+	}
+
+	steps := []struct {
+		record string
+		action domain.ErasureAction
+		erase  func() (int64, error)
+	}{
+		{"session", domain.ErasureDeleted, func() (int64, error) { return es.sessionPersistence.DeleteSessionsByUser(ctx, user.ID) }},
+		{"consent", domain.ErasureDeleted, func() (int64, error) { return es.consentPersistence.DeleteConsents(ctx, user.ID) }},
+		{"login_record", domain.ErasureDeleted, func() (int64, error) { return es.loginAuditStore.DeleteLoginRecords(ctx, user.Username.String()) }},
+		{"data_export", domain.ErasureDeleted, func() (int64, error) { return es.dataExportPersistence.DeleteDataExportsByUser(ctx, user.ID) }},
+		{"group_membership", domain.ErasureDeleted, func() (int64, error) { return es.endGroupMemberships(ctx, user.ID) }},
+		{"credential_event", domain.ErasurePseudonymized, func() (int64, error) {
+			return es.credentialEventStore.PseudonymizeCredentialEvents(ctx, user.Username.String(), certificate.Pseudonym)
+		}},
+		// the account goes last, so a failed erasure can be repeated
+		{"user", domain.ErasureDeleted, func() (int64, error) { return es.userErasurePersistence.EraseUser(ctx, user.ID) }},
+	}
+	for _, step := range steps {
+		count, err := step.erase()
+		if err != nil {
+			return domain.ErasureCertificate{}, fmt.Errorf("failed to erase %s records: %w", step.record, err)
+		}
+		certificate.Records = append(certificate.Records, domain.ErasedRecords{Record: step.record, Action: step.action, Count: count})
+	}
+
+	certificate.ErasedAt = es.clock.Now()
+	if err := es.certificatePersistence.SaveErasureCertificate(ctx, certificate); err != nil {
+		return domain.ErasureCertificate{}, fmt.Errorf("failed to save erasure certificate: %w", err)
+	}
+
+	es.eventDispatcher.Dispatch(ctx, events.UserErased{UserID: user.ID, Username: user.Username.String(), Pseudonym: certificate.Pseudonym, CertificateID: certificate.ID, At: certificate.ErasedAt})
+	return certificate, nil
+}
+
+// GetErasureCertificate returns a stored erasure certificate.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The id of the certificate
+//
+// Returns:
+//   - domain.ErasureCertificate: The certificate
+//   - error: errorx.ErrErasureCertificateNotFound, or a wrapped persistence error
+func (es *ErasureService) GetErasureCertificate(ctx context.Context, id string) (domain.ErasureCertificate, error) {
+	return es.certificatePersistence.FindErasureCertificate(ctx, id)
+}
+
+// PlaceLegalHold keeps the data of a user from being erased or purged by the retention job.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The id of the user, active, deleted or archived
+//
+// Returns:
+//   - error: errorx.ErrUserNotFound, or a wrapped persistence error
+func (es *ErasureService) PlaceLegalHold(ctx context.Context, id string) error {
+	return es.userErasurePersistence.UpdateLegalHold(ctx, id, true)
+}
+
+// LiftLegalHold lifts the legal hold of a user.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The id of the user, active, deleted or archived
+//
+// Returns:
+//   - error: errorx.ErrUserNotFound, or a wrapped persistence error
+func (es *ErasureService) LiftLegalHold(ctx context.Context, id string) error {
+	return es.userErasurePersistence.UpdateLegalHold(ctx, id, false)
+}
+
+// endGroupMemberships removes a user from all groups and returns the number of memberships ended.
+func (es *ErasureService) endGroupMemberships(ctx context.Context, userID string) (int64, error) {
+	groups, err := es.groupPersistence.FindGroupsByMember(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	var ended int64
+	for _, group := range groups {
+		if err := es.groupPersistence.RemoveGroupMember(ctx, group.Name, userID); err != nil {
+			return ended, err
+		}
+		ended++
+	}
+	return ended, nil
+}
+
+// randomHex returns n random bytes in hex encoding.
+func (es *ErasureService) randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := es.random.Read(b); err != nil {
+		return "", fmt.Errorf("error generating random id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,231 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/ports/messaging"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+	"user-auth-hexagonal-architecture/internal/requestid"
+)
+
+// EventOutboxService records domain events in an outbox and relays them to a message broker, so
+// downstream systems can react to registrations and security events.
+// It implements the EventHandler interface from the messaging ports package.
+//
+// Events are recorded while the use case emitting them runs and published by a background relay,
+// so an unavailable broker neither fails nor delays a use case. Every instance of the service runs a
+// relay; claimed batches are leased, so instances do not publish the same messages concurrently.
+type EventOutboxService struct {
+	outboxPersistence persistence.OutboxPersistencePort
+	userPersistence   persistence.UserPersistencePort
+	publisher         messaging.EventPublisherPort
+	clock             system.ClockPort
+	random            system.RandomSourcePort
+	categories        []events.Category
+	policy            domain.OutboxPolicy
+}
+
+// subjectFields are the fields identifying the user an event is about.
+type subjectFields struct {
+	UserID   string
+	Username string
+}
+
+// NewEventOutboxService creates a new instance of EventOutboxService.
+//
+// Parameters:
+//   - outboxPersistence: An implementation of OutboxPersistencePort for storing the events until they are published
+//   - userPersistence: An implementation of UserPersistencePort for looking up the id of users events name by username only
+//   - publisher: An implementation of EventPublisherPort for publishing the events
+//   - clock: An implementation of ClockPort for reading the current time
+//   - random: An implementation of RandomSourcePort for generating message ids
+//   - categories: The event categories recorded, all if empty
+//   - policy: The batches and retries of the relay
+//
+// Returns:
+//   - *EventOutboxService: A pointer to the newly created EventOutboxService
+func NewEventOutboxService(outboxPersistence persistence.OutboxPersistencePort, userPersistence persistence.UserPersistencePort, publisher messaging.EventPublisherPort, clock system.ClockPort, random system.RandomSourcePort, categories []events.Category, policy domain.OutboxPolicy) *EventOutboxService {
+	return &EventOutboxService{outboxPersistence, userPersistence, publisher, clock, random, categories, policy}
+}
+
+// Handle records an event of a selected category in the outbox.
+//
+// The message is keyed by the id of the user the event is about. Events naming the user by
+// username only are keyed by the id of the user stored under the username, or by the username
+// if there is none, e.g. for failed logins with unknown usernames.
+//
+// Parameters:
+//   - ctx: The context of the emitting use case, providing the request id and the tenant
+//   - event: The event to record
+//
+// Returns:
+//   - error: An error if the event cannot be encoded or stored
+func (eo *EventOutboxService) Handle(ctx context.Context, event events.Event) error {
+	category := events.CategoryOf(event)
+	if len(eo.categories) > 0 && !slices.Contains(eo.categories, category) {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", event.Name(), err)
+	}
+	id := make([]byte, 16)
+	if _, err := eo.random.Read(id); err != nil {
+		return fmt.Errorf("failed to generate message id: %w", err)
+	}
+
+	message := domain.OutboxMessage{
+		ID:            hex.EncodeToString(id),
+		EventName:     event.Name(),
+		Category:      string(category),
+		Key:           eo.key(ctx, payload),
+		SchemaVersion: events.SchemaVersion,
+		Payload:       payload,
+		RequestID:     requestid.FromContext(ctx),
+		OccurredAt:    event.OccurredAt(),
+	}
+	if err := eo.outboxPersistence.AppendOutboxMessage(ctx, message); err != nil {
+		return fmt.Errorf("failed to record %s: %w", event.Name(), err)
+	}
+	return nil
+}
+
+// key returns the partitioning key of an encoded event.
+func (eo *EventOutboxService) key(ctx context.Context, payload []byte) string {
+	var subject subjectFields
+	_ = json.Unmarshal(payload, &subject)
+	if subject.UserID != "" || subject.Username == "" {
+		return subject.UserID
+	}
+
+	username, err := domain.NewUsername(subject.Username)
+	if err != nil {
+		return subject.Username
+	}
+	user, err := eo.userPersistence.FindUser(ctx, username)
+	if err != nil {
+		if !errors.Is(err, errorx.ErrUserNotFound) {
+			logger.WarnContext(ctx, "Error looking up the key of an outbox message, using the username", "error", err)
+		}
+		return subject.Username
+	}
+	return user.ID
+}
+
+// Relay publishes a batch of recorded messages.
+//
+// This method performs the following steps:
+// 1. Claims up to a batch of pending messages, the oldest first.
+// 2. Publishes them.
+// 3. Marks them as published, or returns them for another attempt if publishing failed. Messages
+// that failed their last attempt are kept as failed and no longer published.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//
+// Returns:
+//   - int: The number of messages published
+//   - error: An error if the messages cannot be claimed or the publisher failed
+func (eo *EventOutboxService) Relay(ctx context.Context) (int, error) {
+	messages, err := eo.outboxPersistence.ClaimOutboxMessages(ctx, eo.policy.BatchSize, eo.policy.Lease)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim outbox messages: %w", err)
+	}
+	if len(messages) == 0 {
+		return 0, nil
+	}
+	if err := eo.publish(ctx, messages); err != nil {
+		return 0, err
+	}
+	return len(messages), nil
+}
+
+// publish publishes claimed messages and records the outcome. Polling an empty outbox is not
+// traced, only the batches are.
+func (eo *EventOutboxService) publish(ctx context.Context, messages []domain.OutboxMessage) (err error) {
+	ctx, span := tracer.Start(ctx, "EventOutboxService.Relay")
+	defer func() { endSpan(span, err) }()
+
+	ids := make([]string, len(messages))
+	for i, message := range messages {
+		ids[i] = message.ID
+	}
+	if publishErr := eo.publisher.Publish(ctx, messages); publishErr != nil {
+		eo.release(ctx, messages, publishErr)
+		return fmt.Errorf("failed to publish %d messages to %s: %w", len(messages), eo.publisher.Name(), publishErr)
+	}
+	if err := eo.outboxPersistence.MarkOutboxMessagesPublished(ctx, ids, eo.clock.Now()); err != nil {
+		// the lease expires and the messages are published again, consumers drop the duplicates
+		return fmt.Errorf("failed to mark outbox messages as published: %w", err)
+	}
+	return nil
+}
+
+// release returns the messages of a failed batch, giving up those that failed their last attempt.
+func (eo *EventOutboxService) release(ctx context.Context, messages []domain.OutboxMessage, cause error) {
+	var retry, failed []string
+	for _, message := range messages {
+		if message.Attempts+1 >= eo.policy.MaxAttempts {
+			failed = append(failed, message.ID)
+		} else {
+			retry = append(retry, message.ID)
+		}
+	}
+	if len(failed) > 0 {
+		logger.ErrorContext(ctx, "Giving up outbox messages", "publisher", eo.publisher.Name(), "count", len(failed), "error", cause)
+		if err := eo.outboxPersistence.FailOutboxMessages(ctx, failed, eo.clock.Now(), cause.Error()); err != nil {
+			logger.ErrorContext(ctx, "Error marking outbox messages as failed", "error", err)
+		}
+	}
+	if len(retry) > 0 {
+		if err := eo.outboxPersistence.ReleaseOutboxMessages(ctx, retry, cause.Error()); err != nil {
+			logger.ErrorContext(ctx, "Error releasing outbox messages", "error", err)
+		}
+	}
+}
+
+// RelayEvery publishes the recorded messages until the context is cancelled.
+//
+// Full batches are followed by the next batch right away. Once the outbox is drained, the relay
+// polls it every interval. After a failure it waits with exponential backoff, so a broker outage
+// is not hammered with attempts; the failed batch is the oldest and published first afterwards.
+//
+// Parameters:
+//   - ctx: A context.Context controlling the lifetime of the relay
+//   - interval: How long to wait before polling a drained outbox again
+func (eo *EventOutboxService) RelayEvery(ctx context.Context, interval time.Duration) {
+	backoff := eo.policy.InitialBackoff
+	for {
+		wait := interval
+		published, err := eo.Relay(ctx)
+		switch {
+		case err != nil:
+			logger.WarnContext(ctx, "Error relaying outbox messages, retrying", "retry_in", backoff, "error", err)
+			wait = backoff
+			backoff = min(backoff*2, eo.policy.MaxBackoff)
+		case published >= eo.policy.BatchSize:
+			backoff = eo.policy.InitialBackoff
+			wait = 0
+		default:
+			backoff = eo.policy.InitialBackoff
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
@@ -0,0 +1,51 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"fmt"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+)
+
+// GetCurrentUserService handles the business logic for reading the profile of the authenticated user.
+// It implements the GetCurrentUserPort interface from the usecases package.
+type GetCurrentUserService struct {
+	userPersistence persistence.UserPersistencePort
+}
+
+// NewGetCurrentUserService creates a new instance of GetCurrentUserService.
+//
+// Parameters:
+//   - userPersistence: An implementation of UserPersistencePort for retrieving user data
+//
+// Returns:
+//   - *GetCurrentUserService: A pointer to the newly created GetCurrentUserService
+func NewGetCurrentUserService(userPersistence persistence.UserPersistencePort) *GetCurrentUserService {
+	return &GetCurrentUserService{userPersistence}
+}
+
+// GetCurrentUser loads the user identified by the subject of the caller's token.
+//
+// The password hash is stripped from the returned user, so it can never leak through
+// a profile response.
+//
+// Parameters:
+//   - ctx: The context of the request
+//   - username: The token subject
+//
+// Returns:
+//   - domain.User: The user without credentials
+//   - error: errorx.ErrUserNotFound if the account no longer exists, or a wrapped persistence error
+func (gs *GetCurrentUserService) GetCurrentUser(ctx context.Context, username string) (user domain.User, err error) {
+	ctx, span := tracer.Start(ctx, "GetCurrentUserService.GetCurrentUser")
+	defer func() { endSpan(span, err) }()
+
+	user, err = gs.userPersistence.FindUser(ctx, domain.NormalizeUsername(username))
+	if err != nil {
+		return domain.User{}, fmt.Errorf("error finding user: %w", err)
+	}
+
+	return user.WithoutPassword(), nil
+}
@@ -0,0 +1,180 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// GroupService handles the business logic for user groups.
+// It implements the ManageGroupsPort interface from the usecases package.
+//
+// Members inherit the roles of their groups when they log in, so changes to a group apply to
+// its members with their next access token. Groups are managed within the tenant of the request
+// and only take users of that tenant as members.
+type GroupService struct {
+	groupPersistence     persistence.GroupPersistencePort
+	userAdminPersistence persistence.UserAdminPersistencePort
+	roleRegistry         usecases.RoleRegistryPort
+	tenantRegistry       usecases.TenantRegistryPort
+	clock                system.ClockPort
+}
+
+// NewGroupService creates a new instance of GroupService.
+//
+// Parameters:
+//   - groupPersistence: An implementation of GroupPersistencePort for storing groups
+//   - userAdminPersistence: An implementation of UserAdminPersistencePort for checking that members exist
+//   - roleRegistry: An implementation of RoleRegistryPort for checking that assigned roles exist
+//   - tenantRegistry: An implementation of TenantRegistryPort for resolving the tenant new groups belong to
+//   - clock: An implementation of ClockPort for reading the current time
+//
+// Returns:
+//   - *GroupService: A pointer to the newly created GroupService
+func NewGroupService(groupPersistence persistence.GroupPersistencePort, userAdminPersistence persistence.UserAdminPersistencePort, roleRegistry usecases.RoleRegistryPort, tenantRegistry usecases.TenantRegistryPort, clock system.ClockPort) *GroupService {
+	return &GroupService{groupPersistence, userAdminPersistence, roleRegistry, tenantRegistry, clock}
+}
+
+// ListGroups returns the groups of the tenant of the request sorted by name.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//
+// Returns:
+//   - []domain.Group: The groups
+//   - error: A wrapped persistence error
+func (gs *GroupService) ListGroups(ctx context.Context) ([]domain.Group, error) {
+	groups, err := gs.groupPersistence.FindGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list groups: %w", err)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups, nil
+}
+
+// GetGroup returns a single group.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - name: The name of the group
+//
+// Returns:
+//   - domain.Group: The group
+//   - error: errorx.ErrGroupNotFound if the group does not exist, or a wrapped persistence error
+func (gs *GroupService) GetGroup(ctx context.Context, name string) (domain.Group, error) {
+	group, err := gs.groupPersistence.FindGroup(ctx, name)
+	if err != nil {
+		return domain.Group{}, fmt.Errorf("error finding group: %w", err)
+	}
+	return group, nil
+}
+
+// SaveGroup creates a group in the tenant of the request or replaces the roles of an existing one.
+// The members are left untouched.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - name: The name of the group
+//   - roles: The roles the group grants to its members, each known to the role registry
+//
+// Returns:
+//   - domain.Group: The stored group
+//   - error: errorx.ErrUnknownRole, errorx.ErrTenantNotFound, or a wrapped persistence error
+func (gs *GroupService) SaveGroup(ctx context.Context, name string, roles []domain.Role) (domain.Group, error) {
+	group, err := gs.groupPersistence.FindGroup(ctx, name)
+	if errors.Is(err, errorx.ErrGroupNotFound) {
+		groupTenant, err := gs.tenantRegistry.ResolveTenant(ctx)
+		if err != nil {
+			return domain.Group{}, err
+		}
+		group = domain.NewGroup(groupTenant.ID, name, gs.clock.Now())
+	} else if err != nil {
+		return domain.Group{}, fmt.Errorf("error finding group: %w", err)
+	}
+
+	if err := group.AssignRoles(roles, gs.roleRegistry.RolePermissions()); err != nil {
+		return domain.Group{}, err
+	}
+	group.UpdatedAt = gs.clock.Now()
+	if err := gs.groupPersistence.SaveGroup(ctx, group); err != nil {
+		return domain.Group{}, fmt.Errorf("failed to save group: %w", err)
+	}
+	return group, nil
+}
+
+// DeleteGroup deletes a group. Its members lose the inherited roles with their next access token.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - name: The name of the group
+//
+// Returns:
+//   - error: errorx.ErrGroupNotFound, or a wrapped persistence error
+func (gs *GroupService) DeleteGroup(ctx context.Context, name string) error {
+	if err := gs.groupPersistence.DeleteGroup(ctx, name); err != nil {
+		return fmt.Errorf("failed to delete group: %w", err)
+	}
+	return nil
+}
+
+// AddGroupMember adds a user to a group. Adding a member twice changes nothing. Users of other
+// tenants are reported as not found.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - name: The name of the group
+//   - userID: The id of the user
+//
+// Returns:
+//   - error: errorx.ErrUserNotFound, errorx.ErrGroupNotFound, or a wrapped persistence error
+func (gs *GroupService) AddGroupMember(ctx context.Context, name string, userID string) error {
+	group, err := gs.groupPersistence.FindGroup(ctx, name)
+	if err != nil {
+		return fmt.Errorf("error finding group: %w", err)
+	}
+	user, err := gs.userAdminPersistence.FindUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("error finding user: %w", err)
+	}
+	if !group.Admits(user) {
+		return errorx.ErrUserNotFound
+	}
+	if err := gs.groupPersistence.AddGroupMember(ctx, name, userID); err != nil {
+		return fmt.Errorf("failed to add group member: %w", err)
+	}
+	return nil
+}
+
+// RemoveGroupMember removes a user from a group. Removing a user that is not a member changes nothing.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - name: The name of the group
+//   - userID: The id of the user
+//
+// Returns:
+//   - error: errorx.ErrGroupNotFound, or a wrapped persistence error
+func (gs *GroupService) RemoveGroupMember(ctx context.Context, name string, userID string) error {
+	if err := gs.groupPersistence.RemoveGroupMember(ctx, name, userID); err != nil {
+		return fmt.Errorf("failed to remove group member: %w", err)
+	}
+	return nil
+}
+
+// groupNames returns the names of the groups for the access token claims.
+func groupNames(groups []domain.Group) []string {
+	names := make([]string, 0, len(groups))
+	for _, group := range groups {
+		names = append(names, group.Name)
+	}
+	sort.Strings(names)
+	return names
+}
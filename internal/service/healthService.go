@@ -0,0 +1,112 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"sync"
+	"user-auth-hexagonal-architecture/internal/ports/health"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// HealthService checks the health of every persistence backend the application depends on
+// and whether the application is ready to serve requests.
+// It implements the CheckHealthPort and CheckReadinessPort interfaces from the usecases package.
+type HealthService struct {
+	dependencies []health.DependencyCheckPort
+	backends     []persistence.PersistenceHealthPort
+}
+
+// NewHealthService creates a new instance of HealthService.
+//
+// Parameters:
+//   - dependencies: The DependencyCheckPort implementations deciding readiness
+//   - backends: The PersistenceHealthPort implementations to check
+//
+// Returns:
+//   - *HealthService: A pointer to the newly created HealthService
+func NewHealthService(dependencies []health.DependencyCheckPort, backends ...persistence.PersistenceHealthPort) *HealthService {
+	return &HealthService{dependencies, backends}
+}
+
+// CheckHealth pings every backend, collects its statistics and checks every dependency.
+//
+// The report is healthy only if every backend answers the ping, all of its expected
+// indexes are present and every non-optional dependency passes its check. Failing to
+// gather statistics for a reachable backend or a failing optional dependency is
+// reported but does not mark it unhealthy.
+//
+// Parameters:
+//   - ctx: A context.Context bounding the duration of the checks
+//
+// Returns:
+//   - usecases.HealthReport: The aggregated health report
+func (hs *HealthService) CheckHealth(ctx context.Context) usecases.HealthReport {
+	report := usecases.HealthReport{Healthy: true}
+
+	for _, backend := range hs.backends {
+		health := usecases.PersistenceHealth{Healthy: true}
+
+		if err := backend.Ping(ctx); err != nil {
+			health.Healthy = false
+			health.Error = err.Error()
+		} else if stats, err := backend.Stats(ctx); err != nil {
+			health.Error = err.Error()
+		} else {
+			health.Stats = &stats
+			for _, index := range stats.Indexes {
+				if !index.Present {
+					health.Healthy = false
+				}
+			}
+		}
+
+		report.Healthy = report.Healthy && health.Healthy
+		report.Persistence = append(report.Persistence, health)
+	}
+
+	readiness := hs.CheckReadiness(ctx)
+	report.Healthy = report.Healthy && readiness.Ready
+	report.Dependencies = readiness.Dependencies
+
+	return report
+}
+
+// CheckReadiness checks every dependency the application needs to serve requests.
+//
+// The application is ready if all non-optional dependencies pass their check. Failing
+// optional dependencies are reported but leave the application ready in a degraded mode.
+// The checks run concurrently, so one slow dependency does not delay the others.
+//
+// Parameters:
+//   - ctx: A context.Context bounding the duration of the checks
+//
+// Returns:
+//   - usecases.ReadinessReport: The readiness report
+func (hs *HealthService) CheckReadiness(ctx context.Context) usecases.ReadinessReport {
+	statuses := make([]usecases.DependencyStatus, len(hs.dependencies))
+
+	var wg sync.WaitGroup
+	for i, dependency := range hs.dependencies {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status := usecases.DependencyStatus{Name: dependency.Name(), Ready: true, Optional: dependency.Optional()}
+			if err := dependency.Check(ctx); err != nil {
+				status.Ready = false
+				status.Error = err.Error()
+			}
+			statuses[i] = status
+		}()
+	}
+	wg.Wait()
+
+	report := usecases.ReadinessReport{Ready: true, Dependencies: statuses}
+	for _, status := range statuses {
+		if !status.Ready && !status.Optional {
+			report.Ready = false
+		}
+	}
+	return report
+}
@@ -3,86 +3,435 @@
 package service
 
 import (
+	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
 	"time"
+	"user-auth-hexagonal-architecture/internal/device"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/ports/messaging"
 	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/security"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+	"user-auth-hexagonal-architecture/internal/ports/telemetry"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
 )
 
 // LoadUserService handles the business logic for user authentication.
 // It implements the LoadUserPort interface from the usecases package.
 type LoadUserService struct {
-	userPersistence persistence.UserPersistencePort
+	userPersistence    persistence.UserPersistencePort
+	eventDispatcher    messaging.EventDispatcherPort
+	metrics            telemetry.MetricsPort
+	groupPersistence   persistence.GroupPersistencePort
+	sessionPersistence persistence.SessionPersistencePort
+	tenantRegistry     usecases.TenantRegistryPort
+	consentGate        usecases.ConsentGatePort
+	passwordHasher     security.PasswordHasherPort
+	oneTimePassword    security.OneTimePasswordPort
+	lockouts           security.LockoutStorePort
+	lockoutPolicy      security.LockoutPolicy
+	riskEvaluator      *RiskEvaluator
+	clock              system.ClockPort
+	features           system.FeatureFlagPort
+	random             system.RandomSourcePort
+	tokens             tokenIssuer
+	sessionLimit       domain.SessionLimit
+	// dummyPasswordHash is compared against when the user does not exist, so that unknown
+	// usernames take as long to reject as wrong passwords and cannot be enumerated by timing.
+	dummyPasswordHash domain.HashedPassword
 }
 
 // NewLoadUserService creates a new instance of LoadUserService.
 //
 // Parameters:
 //   - userPersistence: An implementation of UserPersistencePort for retrieving user data
+//   - eventDispatcher: An implementation of EventDispatcherPort for emitting domain events
+//   - metrics: An implementation of MetricsPort for reporting the password verification duration
+//   - roleRegistry: An implementation of RoleRegistryPort for resolving the permissions of the user's roles
+//   - groupPersistence: An implementation of GroupPersistencePort for resolving the roles inherited from groups
+//   - sessionPersistence: An implementation of SessionPersistencePort for storing the session started at login
+//   - tenantRegistry: An implementation of TenantRegistryPort for the settings of the tenant the login is made for
+//   - consentGate: An implementation of ConsentGatePort for the acceptance of the terms and the privacy policy
+//   - passwordHasher: An implementation of PasswordHasherPort for verifying passwords
+//   - oneTimePassword: An implementation of OneTimePasswordPort for verifying the codes of second factors
+//   - lockouts: An implementation of LockoutStorePort counting failed logins per username
+//   - lockoutPolicy: The failed logins after which a username is locked out and for how long, disabled if zero
+//   - riskEvaluator: The RiskEvaluator assessing the risk of logins with correct credentials
+//   - clock: An implementation of ClockPort for reading the current time
+//   - features: An implementation of FeatureFlagPort deciding whether multi-factor authentication is required
+//   - random: An implementation of RandomSourcePort for generating session ids
+//   - signingKeys: An implementation of SigningKeyPort providing the key used to sign access tokens
+//   - sessionLimit: The number of sessions a user may have active at the same time and what happens beyond it
+//   - tokenSettings: The lifetime of the refresh tokens issued at login, zero disables refresh tokens
 //
 // Returns:
 //   - *LoadUserService: A pointer to the newly created LoadUserService
-func NewLoadUserService(userPersistence persistence.UserPersistencePort) *LoadUserService {
-	return &LoadUserService{userPersistence}
+func NewLoadUserService(userPersistence persistence.UserPersistencePort, eventDispatcher messaging.EventDispatcherPort, metrics telemetry.MetricsPort, roleRegistry usecases.RoleRegistryPort, groupPersistence persistence.GroupPersistencePort, sessionPersistence persistence.SessionPersistencePort, tenantRegistry usecases.TenantRegistryPort, consentGate usecases.ConsentGatePort, passwordHasher security.PasswordHasherPort, oneTimePassword security.OneTimePasswordPort, lockouts security.LockoutStorePort, lockoutPolicy security.LockoutPolicy, riskEvaluator *RiskEvaluator, clock system.ClockPort, features system.FeatureFlagPort, random system.RandomSourcePort, signingKeys security.SigningKeyPort, sessionLimit domain.SessionLimit, tokenSettings *TokenSettings) *LoadUserService {
+	dummyPasswordHash, err := passwordHasher.Hash("dummy-password")
+	if err != nil {
+		logger.Error("Error hashing the dummy password, unknown usernames are rejected faster", "error", err)
+	}
+	tokens := tokenIssuer{roleRegistry: roleRegistry, random: random, keys: signingKeys, settings: tokenSettings}
+	return &LoadUserService{userPersistence, eventDispatcher, metrics, groupPersistence, sessionPersistence, tenantRegistry, consentGate, passwordHasher, oneTimePassword, lockouts, lockoutPolicy, riskEvaluator, clock, features, random, tokens, sessionLimit, dummyPasswordHash}
 }
 
 // LoadUser authenticates a user and generates a JWT token upon successful authentication.
 //
 // This method performs the following steps:
-// 1. Retrieves the user from the persistence layer using the provided username.
-// 2. Compares the provided password with the stored (hashed) password.
-// 3. If authentication is successful, generates a JWT token with user claims.
+// 1. Resolves the tenant of the request and checks that it allows the login method.
+// 2. Rejects usernames locked out after too many failed logins, whether or not the account exists.
+// 3. Retrieves the user of the tenant from the persistence layer using the provided username.
+// 4. Compares the provided password with the stored (hashed) password, counting failures towards the lockout.
+// 5. Checks that the user has accepted the current terms and privacy policy, now or earlier, and records new consents.
+// 6. Assesses the risk of the login, which may block it or require MFA, and checks the MFA requirement of the tenant.
+// 7. Verifies the one-time code if the account has MFA enabled, counting wrong codes towards the lockout.
+// 8. Rehashes a password hash of another algorithm than the configured one and emits a PasswordHashUpgraded event.
+// 9. Issues only a password change token if the user still has a temporary password.
+// 10. Resolves the effective roles of the user, including the roles inherited from groups.
+// 11. Applies the session limit, rejecting the login or evicting the oldest sessions with a SessionEvicted event.
+// 12. Records the login time, which drives the archival of inactive accounts, and emits a UserLoggedIn event.
+// 13. Starts a session on the device the request was made from.
+// 14. If authentication is successful, generates a JWT token with user claims bound to the session.
+//
+// If refresh tokens are enabled, the session lasts as long as its refresh token, which is returned
+// along with the access token and renews both with the RefreshSessionPort.
+//
+// A user whose password was set by an administrator gets neither; the returned tokens only hold a
+// PasswordChangeToken for the ChangeTemporaryPasswordPort, and the user has to log in again with
+// the new password.
+//
+// Rejected attempts emit a LoginFailed event.
 //
 // Parameters:
+//   - ctx: The context of the request, cancelling it aborts the authentication.
 //   - username: A string representing the username of the user to authenticate.
 //   - password: A string representing the password to verify.
+//   - mfaCode: A current one-time code of a second factor, required if the account has MFA enabled.
+//   - method: The login method the adapter offers, which the tenant has to allow.
+//   - consents: The versions of the terms and privacy policy the user accepts with this login.
 //
 // Returns:
-//   - string: A signed JWT token string if authentication is successful.
+//   - domain.AuthTokens: The signed JWT access token and its expiry, and the refresh token if enabled,
+//     if authentication is successful, or only a password change token for a temporary password.
 //   - error: An error in the following cases:
-//   - If the user is not found in the persistence layer.
-//   - If the provided password doesn't match the stored password.
-//   - If there's an error during password comparison.
-//   - If there's an error while creating or signing the JWT token.
+//   - errorx.ErrTenantNotFound or errorx.ErrLoginMethodNotAllowed if the tenant is unknown or does not allow the method.
+//   - errorx.ErrTooManyFailedLogins if the username is locked out after too many failed logins.
+//   - errorx.ErrInvalidCredentials if the user is not found in the tenant or the password doesn't match.
+//   - errorx.ErrConsentRequired naming the outstanding document versions if the user has not accepted them,
+//     or errorx.ErrInvalidConsent if the accepted versions are not the current ones.
+//   - errorx.ErrLoginBlocked if the risk of the login is too high.
+//   - errorx.ErrMfaRequired if the tenant, the require-mfa feature flag or the risk of the login requires multi-factor
+//     authentication the account has not enabled.
+//   - errorx.ErrMfaCodeRequired if the account has MFA enabled and no code is given, or errorx.ErrInvalidMfaCode
+//     if the code does not match any of its second factors.
+//   - errorx.ErrSessionLimitReached if the user has too many active sessions and the limit rejects new logins.
+//   - errorx.ErrOverloaded if too many password verifications are queued.
+//   - errorx.ErrAccountDisabled, errorx.ErrAccountLocked or errorx.ErrAccountPending if the credentials
+//     are correct but the account is not ACTIVE.
+//   - If there's an error while loading the user or during password comparison.
+//   - If there's an error while resolving the user's groups.
+//   - If there's an error while loading, evicting or storing sessions.
+//   - If there's an error while creating or signing the JWT token or generating the refresh token.
 //
 // The JWT token includes the following claims:
 //   - username: The authenticated user's username.
-//   - role: The user's role.
-//   - exp: The expiration time of the token (set to 24 hours from creation).
+//   - roles: The user's effective roles, i.e. the assigned roles plus the roles of the user's groups.
+//   - groups: The names of the user's groups.
+//   - permissions: The permissions granted by the roles, flattened for resource servers.
+//   - tenant: The id of the tenant the account belongs to.
+//   - sid: The id of the session, the token is rejected once the session is revoked.
+//   - exp: The expiration time of the token (set to the access token lifetime of the tenant from creation).
 //
 // Note:
-//   - This method uses bcrypt for password comparison.
-//   - The JWT signing key is hardcoded for demonstration purposes.
-//     In a production environment, this should be securely managed.
+//   - The password is verified with the algorithm the stored hash was created with; a failed
+//     rehash is logged and does not fail the login, the next login tries again.
+//   - The JWT signing key is injected by the caller and must be kept secret.
 //   - Error messages for authentication failures are intentionally vague
 //     to prevent information leakage.
-func (lu *LoadUserService) LoadUser(username string, password string) (string, error) {
-	user, err := lu.userPersistence.FindUser(username)
+func (lu *LoadUserService) LoadUser(ctx context.Context, username string, password string, mfaCode string, method domain.LoginMethod, consents []domain.ConsentRef) (tokens domain.AuthTokens, err error) {
+	ctx, span := tracer.Start(ctx, "LoadUserService.LoadUser")
+	defer func() { endSpan(span, err) }()
+
+	userTenant, err := lu.tenantRegistry.ResolveTenant(ctx)
+	if err != nil {
+		return domain.AuthTokens{}, err
+	}
+	if !userTenant.Settings.AllowsLoginMethod(method) {
+		return domain.AuthTokens{}, errorx.ErrLoginMethodNotAllowed
+	}
+
+	lockoutKey := userTenant.ID + ":" + domain.NormalizeUsername(username).String()
+	if lockedFor := lu.lockedFor(ctx, lockoutKey); lockedFor > 0 {
+		lu.loginFailed(ctx, username, events.LoginFailedLockedOut)
+		return domain.AuthTokens{}, errorx.ErrTooManyFailedLogins.Detailf("try again in %s", lockedFor.Round(time.Second))
+	}
+
+	user, err := lu.userPersistence.FindUser(ctx, domain.NormalizeUsername(username))
+	if err == nil && user.TenantID != userTenant.ID {
+		// accounts of other tenants are treated like unknown usernames
+		err = errorx.ErrUserNotFound
+	}
+	if err != nil {
+		if errors.Is(err, errorx.ErrUserNotFound) {
+			// a shed verification is reported like for existing users, so overload does not reveal unknown usernames
+			if err := lu.passwordHasher.Verify(lu.dummyPasswordHash, password); errors.Is(err, errorx.ErrOverloaded) {
+				return domain.AuthTokens{}, err
+			}
+			lu.loginFailed(ctx, username, events.LoginFailedInvalidCredentials)
+			lu.recordFailure(ctx, lockoutKey, "")
+			return domain.AuthTokens{}, errorx.ErrInvalidCredentials
+		}
+		return domain.AuthTokens{}, fmt.Errorf("error finding user: %w", err)
+	}
+
+	verifyStart := time.Now()
+	_, verifySpan := tracer.Start(ctx, "PasswordHasher.Verify")
+	err = lu.passwordHasher.Verify(user.Password, password)
+	verifySpan.End()
+	lu.metrics.ObservePasswordHashing(telemetry.PasswordVerify, time.Since(verifyStart))
+	if err != nil {
+		if errors.Is(err, errorx.ErrInvalidCredentials) {
+			lu.loginFailed(ctx, user.Username.String(), events.LoginFailedInvalidCredentials)
+			lu.recordFailure(ctx, lockoutKey, user.Username.String())
+		}
+		return domain.AuthTokens{}, err
+	}
+
+	if err := user.CanLogIn(); err != nil {
+		lu.loginFailed(ctx, user.Username.String(), loginFailedReason(err))
+		return domain.AuthTokens{}, err
+	}
+	accepted, err := lu.consentGate.CheckConsents(ctx, user, consents)
+	if err != nil {
+		if errors.Is(err, errorx.ErrConsentRequired) || errors.Is(err, errorx.ErrInvalidConsent) {
+			lu.loginFailed(ctx, user.Username.String(), events.LoginFailedConsentRequired)
+		}
+		return domain.AuthTokens{}, err
+	}
+	if err := lu.consentGate.RecordConsents(ctx, accepted); err != nil {
+		return domain.AuthTokens{}, err
+	}
+	risk := lu.riskEvaluator.Evaluate(ctx, domain.LoginAttempt{User: user, Device: device.FromContext(ctx), At: lu.clock.Now()})
+	if risk.Action == domain.RiskBlock {
+		lu.loginFailed(ctx, user.Username.String(), events.LoginFailedRiskBlocked)
+		return domain.AuthTokens{}, errorx.ErrLoginBlocked.Detailf("risk score %d", risk.Score)
+	}
+	mfaRequired := userTenant.Settings.RequireMFA || lu.features.Enabled(ctx, domain.FeatureRequireMFA)
+	if (mfaRequired || risk.Action == domain.RiskRequireMfa) && !user.MfaEnabled {
+		lu.loginFailed(ctx, user.Username.String(), events.LoginFailedMfaRequired)
+		return domain.AuthTokens{}, errorx.ErrMfaRequired
+	}
+	if user.MfaEnabled {
+		if mfaCode == "" {
+			lu.loginFailed(ctx, user.Username.String(), events.LoginFailedMfaCodeRequired)
+			return domain.AuthTokens{}, errorx.ErrMfaCodeRequired
+		}
+		if !lu.verifyMfaCode(user, mfaCode) {
+			lu.loginFailed(ctx, user.Username.String(), events.LoginFailedInvalidMfaCode)
+			lu.recordFailure(ctx, lockoutKey, user.Username.String())
+			return domain.AuthTokens{}, errorx.ErrInvalidMfaCode
+		}
+	}
+	// the failures are only forgotten once every factor was verified, so guessing codes for a known
+	// password still runs into the lockout
+	lu.resetFailures(ctx, lockoutKey)
+	lu.upgradePasswordHash(ctx, user, password)
+
+	if user.MustChangePassword {
+		return lu.passwordChangeRequired(ctx, user)
+	}
+
+	groups, err := lu.groupPersistence.FindGroupsByMember(ctx, user.ID)
+	if err != nil {
+		return domain.AuthTokens{}, fmt.Errorf("error finding groups: %w", err)
+	}
+	roles := domain.EffectiveRoles(user, groups)
+
+	loginAt := lu.clock.Now()
+	if err := lu.applySessionLimit(ctx, user, loginAt); err != nil {
+		if errors.Is(err, errorx.ErrSessionLimitReached) {
+			lu.loginFailed(ctx, user.Username.String(), events.LoginFailedSessionLimit)
+		}
+		return domain.AuthTokens{}, err
+	}
+
+	user.RecordLogin(loginAt)
+	if err := lu.userPersistence.UpdateLastLogin(ctx, user.Username, user.LastLoginAt); err != nil {
+		// not being able to track activity must not lock the user out
+		logger.ErrorContext(ctx, "Error recording login", "username", user.Username.String(), "error", err)
+	}
+	lu.eventDispatcher.Dispatch(ctx, events.UserLoggedIn{Username: user.Username.String(), NewDevice: risk.HasSignal(domain.RiskSignalNewDevice), At: loginAt})
+
+	expiresAt := loginAt.Add(userTenant.Settings.AccessTokenTTL)
+	sessionID, err := lu.newSessionID()
+	if err != nil {
+		return domain.AuthTokens{}, err
+	}
+	session := domain.NewSession(sessionID, user, device.FromContext(ctx), loginAt, expiresAt)
+	refreshToken, err := lu.tokens.bindRefreshToken(&session, loginAt)
+	if err != nil {
+		return domain.AuthTokens{}, err
+	}
+	if err := lu.sessionPersistence.SaveSession(ctx, session); err != nil {
+		return domain.AuthTokens{}, fmt.Errorf("error saving session: %w", err)
+	}
+
+	signedString, err := lu.tokens.signAccessToken(user, roles, groups, session, expiresAt)
 	if err != nil {
-		return "", fmt.Errorf("error finding user: %w", err)
+		return domain.AuthTokens{}, err
+	}
+
+	return domain.AuthTokens{AccessToken: signedString, TokenType: "Bearer", ExpiresAt: expiresAt, RefreshToken: refreshToken}, nil
+}
+
+// verifyMfaCode reports whether a code is valid for one of the confirmed second factors of the user.
+func (lu *LoadUserService) verifyMfaCode(user domain.User, code string) bool {
+	now := lu.clock.Now()
+	for _, factor := range user.MfaFactors {
+		if factor.Confirmed() && lu.oneTimePassword.Verify(factor.Secret, code, now) {
+			return true
+		}
 	}
+	return false
+}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+// upgradePasswordHash replaces a verified password hash that was created with another algorithm
+// than the configured one, so switching the algorithm eventually covers every active account.
+func (lu *LoadUserService) upgradePasswordHash(ctx context.Context, user domain.User, password string) {
+	if !lu.passwordHasher.NeedsRehash(user.Password) {
+		return
+	}
+	hashStart := time.Now()
+	upgraded, err := lu.passwordHasher.Hash(password)
+	lu.metrics.ObservePasswordHashing(telemetry.PasswordHash, time.Since(hashStart))
+	if err != nil {
+		logger.ErrorContext(ctx, "Error rehashing password", "username", user.Username.String(), "error", err)
+		return
+	}
+	if err := lu.userPersistence.UpdatePasswordHash(ctx, user.Username, user.Password, upgraded); err != nil {
+		logger.ErrorContext(ctx, "Error storing rehashed password", "username", user.Username.String(), "error", err)
+		return
+	}
+	lu.eventDispatcher.Dispatch(ctx, events.PasswordHashUpgraded{Username: user.Username.String(), Algorithm: lu.passwordHasher.Algorithm(), At: lu.clock.Now()})
+}
+
+// passwordChangeRequired issues the password change token for a user who logged in with a
+// temporary password and emits a LoginFailed event, as no session is started.
+func (lu *LoadUserService) passwordChangeRequired(ctx context.Context, user domain.User) (domain.AuthTokens, error) {
+	lu.loginFailed(ctx, user.Username.String(), events.LoginFailedPasswordChangeRequired)
+	expiresAt := lu.clock.Now().Add(passwordChangeTokenTTL)
+	changeToken, err := lu.tokens.signPasswordChangeToken(user, expiresAt)
+	if err != nil {
+		return domain.AuthTokens{}, err
+	}
+	return domain.AuthTokens{ExpiresAt: expiresAt, PasswordChangeToken: changeToken}, nil
+}
+
+// applySessionLimit checks the active sessions of the user against the session limit and revokes
+// the sessions the limit evicts.
+func (lu *LoadUserService) applySessionLimit(ctx context.Context, user domain.User, now time.Time) error {
+	if lu.sessionLimit.Max <= 0 {
+		return nil
+	}
+
+	sessions, err := lu.sessionPersistence.FindSessionsByUser(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("error finding sessions: %w", err)
+	}
+	evicted, err := lu.sessionLimit.Admit(sessions, now)
 	if err != nil {
-		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
-			return "", fmt.Errorf("invalid username or password")
+		return err
+	}
+
+	for _, session := range evicted {
+		if err := session.Revoke(domain.SessionRevokedEvicted, now); err != nil {
+			return err
+		}
+		if err := lu.sessionPersistence.SaveSession(ctx, session); err != nil {
+			return fmt.Errorf("error evicting session: %w", err)
 		}
-		return "", fmt.Errorf("error comparing passwords: %w", err)
+		lu.eventDispatcher.Dispatch(ctx, events.SessionEvicted{
+			Username:  user.Username.String(),
+			SessionID: session.ID,
+			UserAgent: session.Device.UserAgent,
+			IPAddress: session.Device.IPAddress,
+			At:        now,
+		})
 	}
+	return nil
+}
 
-	var jwtKey = []byte("my_secret_key") // This is only for demo purposes
-	token := jwt.New(jwt.SigningMethodHS256)
-	claims := token.Claims.(jwt.MapClaims)
-	claims["username"] = user.Username
-	claims["role"] = user.Role
-	claims["exp"] = time.Now().Add(time.Hour * 24).Unix() // Token expires in 24 hours
+// lockedFor returns how long a username is locked out after failed logins. A lockout store that
+// cannot be read lets the login proceed, so an unavailable Redis does not lock everybody out.
+func (lu *LoadUserService) lockedFor(ctx context.Context, key string) time.Duration {
+	if !lu.lockoutPolicy.Enabled() {
+		return 0
+	}
+	lockedFor, err := lu.lockouts.LockedFor(ctx, key)
+	if err != nil {
+		logger.ErrorContext(ctx, "Error reading lockout", "error", err)
+		return 0
+	}
+	return lockedFor
+}
 
-	signedString, err := token.SignedString(jwtKey)
+// recordFailure counts a failed login towards the lockout of a username. A lockout of an existing
+// account, named by username, emits an AccountLocked event; unknown usernames are locked out silently.
+func (lu *LoadUserService) recordFailure(ctx context.Context, key string, username string) {
+	if !lu.lockoutPolicy.Enabled() {
+		return
+	}
+	state, err := lu.lockouts.RecordFailure(ctx, key, lu.lockoutPolicy)
 	if err != nil {
-		return "", fmt.Errorf("error while creating jwt: %w", err)
+		logger.ErrorContext(ctx, "Error recording failed login", "error", err)
+		return
+	}
+	if state.LockedFor > 0 && state.Failures > 0 {
+		lu.metrics.CountLockout()
+		logger.WarnContext(ctx, "Username locked out after failed logins", "lockout_key", key, "locked_for", state.LockedFor)
+		if username != "" {
+			now := lu.clock.Now()
+			lu.eventDispatcher.Dispatch(ctx, events.AccountLocked{Username: username, Reason: events.AccountLockedFailedLogins, Until: now.Add(state.LockedFor), At: now})
+		}
 	}
+}
+
+// resetFailures forgets the failed logins of a username after its password was verified.
+func (lu *LoadUserService) resetFailures(ctx context.Context, key string) {
+	if !lu.lockoutPolicy.Enabled() {
+		return
+	}
+	if err := lu.lockouts.Reset(ctx, key); err != nil {
+		logger.ErrorContext(ctx, "Error resetting failed logins", "error", err)
+	}
+}
 
-	return signedString, nil
+// newSessionID generates an unguessable session id.
+func (lu *LoadUserService) newSessionID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := lu.random.Read(id); err != nil {
+		return "", fmt.Errorf("error generating session id: %w", err)
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// loginFailed emits a LoginFailed event.
+func (lu *LoadUserService) loginFailed(ctx context.Context, username string, reason string) {
+	lu.eventDispatcher.Dispatch(ctx, events.LoginFailed{Username: username, Reason: reason, At: lu.clock.Now()})
+}
+
+// loginFailedReason maps the error of a rejected login of an existing account onto the LoginFailed reason.
+func loginFailedReason(err error) string {
+	switch {
+	case errors.Is(err, errorx.ErrAccountLocked):
+		return events.LoginFailedAccountLocked
+	case errors.Is(err, errorx.ErrAccountPending):
+		return events.LoginFailedAccountPending
+	default:
+		return events.LoginFailedAccountDisabled
+	}
 }
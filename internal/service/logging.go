@@ -0,0 +1,8 @@
+package service
+
+import (
+	"user-auth-hexagonal-architecture/internal/logging"
+)
+
+// logger writes the log records of the use cases.
+var logger = logging.Component("service")
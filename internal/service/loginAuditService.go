@@ -0,0 +1,122 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"fmt"
+	"user-auth-hexagonal-architecture/internal/device"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/tenant"
+)
+
+// maxLoginRecords caps the number of login records returned by a single query.
+const maxLoginRecords = 100
+
+// LoginAuditService records every authentication attempt together with the device it was made
+// from, and answers queries on these records: the login history of a signed-in user and forensic
+// lookups of administrators.
+// It implements the LoginHistoryPort and SearchLoginAttemptsPort interfaces from the usecases
+// package and the EventHandler interface from the messaging ports package.
+type LoginAuditService struct {
+	loginAuditStore persistence.LoginAuditStorePort
+}
+
+// NewLoginAuditService creates a new instance of LoginAuditService.
+//
+// Parameters:
+//   - loginAuditStore: An implementation of LoginAuditStorePort for storing and querying login records
+//
+// Returns:
+//   - *LoginAuditService: A pointer to the newly created LoginAuditService
+func NewLoginAuditService(loginAuditStore persistence.LoginAuditStorePort) *LoginAuditService {
+	return &LoginAuditService{loginAuditStore}
+}
+
+// Handle records UserLoggedIn and LoginFailed events as login records. The tenant and the device
+// are taken from the context of the login request. Other events are ignored.
+//
+// Parameters:
+//   - ctx: The context the event was dispatched with
+//   - event: The domain event to record
+//
+// Returns:
+//   - error: An error if the login record cannot be stored
+func (as *LoginAuditService) Handle(ctx context.Context, event events.Event) error {
+	var record domain.LoginRecord
+	switch e := event.(type) {
+	case events.UserLoggedIn:
+		record = domain.LoginRecord{Username: domain.NormalizeUsername(e.Username), Outcome: domain.LoginOutcomeSuccess}
+	case events.LoginFailed:
+		record = domain.LoginRecord{Username: domain.NormalizeUsername(e.Username), Outcome: domain.LoginOutcomeFailure, Reason: e.Reason}
+	default:
+		return nil
+	}
+
+	record.TenantID = tenant.FromContext(ctx)
+	if record.TenantID == "" {
+		record.TenantID = domain.DefaultTenantID
+	}
+	loginDevice := device.FromContext(ctx)
+	record.IPAddress = loginDevice.IPAddress
+	record.UserAgent = loginDevice.UserAgent
+	record.Country = loginDevice.Country
+	record.At = event.OccurredAt()
+	if err := as.loginAuditStore.AppendLoginRecord(ctx, record); err != nil {
+		return fmt.Errorf("failed to record %s: %w", event.Name(), err)
+	}
+	return nil
+}
+
+// GetLoginHistory returns the most recent authentication attempts on the account of a signed-in
+// user, so the user can spot logins that were not their own.
+//
+// A missing or too large limit is replaced by maxLoginRecords.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - username: The username of the user
+//   - limit: The maximum number of records to return
+//
+// Returns:
+//   - []domain.LoginRecord: The attempts, newest first
+//   - error: A wrapped persistence error
+func (as *LoginAuditService) GetLoginHistory(ctx context.Context, username string, limit int64) (records []domain.LoginRecord, err error) {
+	ctx, span := tracer.Start(ctx, "LoginAuditService.GetLoginHistory")
+	defer func() { endSpan(span, err) }()
+
+	return as.find(ctx, domain.LoginRecordFilter{Username: domain.NormalizeUsername(username), Limit: limit})
+}
+
+// SearchLoginAttempts returns the authentication attempts matching a filter, e.g. all attempts
+// from an IP address across accounts while investigating an attack.
+//
+// A missing or too large limit is replaced by maxLoginRecords.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - filter: The criteria the attempts have to match
+//
+// Returns:
+//   - []domain.LoginRecord: The attempts, newest first
+//   - error: A wrapped persistence error
+func (as *LoginAuditService) SearchLoginAttempts(ctx context.Context, filter domain.LoginRecordFilter) (records []domain.LoginRecord, err error) {
+	ctx, span := tracer.Start(ctx, "LoginAuditService.SearchLoginAttempts")
+	defer func() { endSpan(span, err) }()
+
+	return as.find(ctx, filter)
+}
+
+// find applies the limit cap and queries the login records.
+func (as *LoginAuditService) find(ctx context.Context, filter domain.LoginRecordFilter) ([]domain.LoginRecord, error) {
+	if filter.Limit <= 0 || filter.Limit > maxLoginRecords {
+		filter.Limit = maxLoginRecords
+	}
+	records, err := as.loginAuditStore.FindLoginRecords(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find login records: %w", err)
+	}
+	return records, nil
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/ports/messaging"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/security"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// MfaService handles the business logic for users managing their own second factors.
+// It implements the ManageMfaPort interface from the usecases package.
+//
+// Enabling a factor takes two steps: the enrollment hands out the secret for the authenticator app,
+// and the factor only counts once the user confirmed it with a first code. Disabling a factor needs
+// the password and a current code, so neither a stolen session nor a stolen password alone can
+// remove the second factor.
+type MfaService struct {
+	userPersistence      persistence.UserPersistencePort
+	userAdminPersistence persistence.UserAdminPersistencePort
+	eventDispatcher      messaging.EventDispatcherPort
+	tenantRegistry       usecases.TenantRegistryPort
+	passwordHasher       security.PasswordHasherPort
+	oneTimePassword      security.OneTimePasswordPort
+	clock                system.ClockPort
+	features             system.FeatureFlagPort
+}
+
+// NewMfaService creates a new instance of MfaService.
+//
+// Parameters:
+//   - userPersistence: An implementation of UserPersistencePort for finding users by username
+//   - userAdminPersistence: An implementation of UserAdminPersistencePort for storing second factors
+//   - eventDispatcher: An implementation of EventDispatcherPort for emitting domain events
+//   - tenantRegistry: An implementation of TenantRegistryPort for the tenant settings of the request
+//   - passwordHasher: An implementation of PasswordHasherPort for confirming the removal of a factor
+//   - oneTimePassword: An implementation of OneTimePasswordPort for generating secrets and verifying codes
+//   - clock: An implementation of ClockPort for reading the current time
+//   - features: An implementation of FeatureFlagPort deciding whether multi-factor authentication is required
+//
+// Returns:
+//   - *MfaService: A pointer to the newly created MfaService
+func NewMfaService(userPersistence persistence.UserPersistencePort, userAdminPersistence persistence.UserAdminPersistencePort, eventDispatcher messaging.EventDispatcherPort, tenantRegistry usecases.TenantRegistryPort, passwordHasher security.PasswordHasherPort, oneTimePassword security.OneTimePasswordPort, clock system.ClockPort, features system.FeatureFlagPort) *MfaService {
+	return &MfaService{userPersistence, userAdminPersistence, eventDispatcher, tenantRegistry, passwordHasher, oneTimePassword, clock, features}
+}
+
+// ListMfaMethods returns the second factors of a user, pending and confirmed, without their secrets.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - username: The username of the authenticated user
+//
+// Returns:
+//   - []domain.MfaFactor: The factors of the user
+//   - error: errorx.ErrUserNotFound, or a wrapped persistence error
+func (ms *MfaService) ListMfaMethods(ctx context.Context, username string) (factors []domain.MfaFactor, err error) {
+	ctx, span := tracer.Start(ctx, "MfaService.ListMfaMethods")
+	defer func() { endSpan(span, err) }()
+
+	user, err := ms.findUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return user.WithoutPassword().MfaFactors, nil
+}
+
+// BeginMfaEnrollment generates a new secret for a second factor and stores it as pending factor,
+// replacing a previous pending enrollment of the method.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - username: The username of the authenticated user
+//   - method: The method to enroll
+//
+// Returns:
+//   - domain.MfaEnrollment: The secret and the provisioning URI for the authenticator app
+//   - error: errorx.ErrUnsupportedMfaMethod, errorx.ErrMfaAlreadyEnabled if the method is enabled
+//     already, errorx.ErrUserNotFound, or a wrapped persistence error
+func (ms *MfaService) BeginMfaEnrollment(ctx context.Context, username string, method domain.MfaMethod) (enrollment domain.MfaEnrollment, err error) {
+	ctx, span := tracer.Start(ctx, "MfaService.BeginMfaEnrollment")
+	defer func() { endSpan(span, err) }()
+
+	if !method.Valid() {
+		return domain.MfaEnrollment{}, errorx.ErrUnsupportedMfaMethod.Detailf("%q is not supported", method)
+	}
+	user, err := ms.findUser(ctx, username)
+	if err != nil {
+		return domain.MfaEnrollment{}, err
+	}
+	secret, err := ms.oneTimePassword.GenerateSecret()
+	if err != nil {
+		return domain.MfaEnrollment{}, fmt.Errorf("failed to generate secret: %w", err)
+	}
+	if err := user.EnrollMfa(method, secret, ms.clock.Now()); err != nil {
+		return domain.MfaEnrollment{}, err
+	}
+	if err := ms.userAdminPersistence.UpdateMfa(ctx, user.ID, user.MfaFactors, user.MfaEnabled); err != nil {
+		return domain.MfaEnrollment{}, fmt.Errorf("failed to store enrollment: %w", err)
+	}
+
+	return domain.MfaEnrollment{
+		Method:          method,
+		Secret:          secret,
+		ProvisioningURI: ms.oneTimePassword.ProvisioningURI(secret, user.Username.String()),
+	}, nil
+}
+
+// EnableMfa confirms a pending factor with a code from the authenticator app and emits the MfaEnabled event.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - username: The username of the authenticated user
+//   - method: The method of the pending factor
+//   - code: A current code of the factor
+//
+// Returns:
+//   - error: errorx.ErrUnsupportedMfaMethod, errorx.ErrMfaNotEnrolled if no factor of the method is
+//     pending, errorx.ErrInvalidMfaCode, errorx.ErrUserNotFound, or a wrapped persistence error
+func (ms *MfaService) EnableMfa(ctx context.Context, username string, method domain.MfaMethod, code string) (err error) {
+	ctx, span := tracer.Start(ctx, "MfaService.EnableMfa")
+	defer func() { endSpan(span, err) }()
+
+	if !method.Valid() {
+		return errorx.ErrUnsupportedMfaMethod.Detailf("%q is not supported", method)
+	}
+	user, err := ms.findUser(ctx, username)
+	if err != nil {
+		return err
+	}
+	factor, ok := user.MfaFactor(method)
+	if !ok || factor.Confirmed() {
+		return errorx.ErrMfaNotEnrolled.Detailf("no pending %s enrollment", method)
+	}
+	now := ms.clock.Now()
+	if !ms.oneTimePassword.Verify(factor.Secret, code, now) {
+		return errorx.ErrInvalidMfaCode
+	}
+	if err := user.ConfirmMfa(method, now); err != nil {
+		return err
+	}
+	if err := ms.userAdminPersistence.UpdateMfa(ctx, user.ID, user.MfaFactors, user.MfaEnabled); err != nil {
+		return fmt.Errorf("failed to enable factor: %w", err)
+	}
+
+	ms.eventDispatcher.Dispatch(ctx, events.MfaEnabled{Username: user.Username.String(), Method: string(method), At: now})
+	return nil
+}
+
+// DisableMfa removes a confirmed factor after the user confirmed the removal with the password and a
+// current code of the factor, and emits the MfaDisabled event. The last factor cannot be removed
+// while the tenant or the require-mfa feature flag requires multi-factor authentication.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - username: The username of the authenticated user
+//   - method: The method of the factor
+//   - password: The current password of the user
+//   - code: A current code of the factor
+//
+// Returns:
+//   - error: errorx.ErrUnsupportedMfaMethod, errorx.ErrMfaNotEnrolled if the method is not enabled,
+//     errorx.ErrInvalidCredentials if the password is wrong, errorx.ErrInvalidMfaCode,
+//     errorx.ErrMfaRequired if multi-factor authentication is required, errorx.ErrUserNotFound,
+//     or a wrapped persistence error
+func (ms *MfaService) DisableMfa(ctx context.Context, username string, method domain.MfaMethod, password string, code string) (err error) {
+	ctx, span := tracer.Start(ctx, "MfaService.DisableMfa")
+	defer func() { endSpan(span, err) }()
+
+	if !method.Valid() {
+		return errorx.ErrUnsupportedMfaMethod.Detailf("%q is not supported", method)
+	}
+	user, err := ms.findUser(ctx, username)
+	if err != nil {
+		return err
+	}
+	factor, ok := user.MfaFactor(method)
+	if !ok || !factor.Confirmed() {
+		return errorx.ErrMfaNotEnrolled.Detailf("%s is not enabled", method)
+	}
+	if err := ms.passwordHasher.Verify(user.Password, password); err != nil {
+		return err
+	}
+	now := ms.clock.Now()
+	if !ms.oneTimePassword.Verify(factor.Secret, code, now) {
+		return errorx.ErrInvalidMfaCode
+	}
+	if err := user.RemoveMfa(method); err != nil {
+		return err
+	}
+	if !user.MfaEnabled {
+		userTenant, err := ms.tenantRegistry.ResolveTenant(ctx)
+		if err != nil {
+			return err
+		}
+		if userTenant.Settings.RequireMFA {
+			return errorx.ErrMfaRequired.Detailf("the tenant requires multi-factor authentication")
+		}
+		if ms.features.Enabled(ctx, domain.FeatureRequireMFA) {
+			return errorx.ErrMfaRequired.Detailf("multi-factor authentication is required")
+		}
+	}
+	if err := ms.userAdminPersistence.UpdateMfa(ctx, user.ID, user.MfaFactors, user.MfaEnabled); err != nil {
+		return fmt.Errorf("failed to disable factor: %w", err)
+	}
+
+	remaining := 0
+	for _, factor := range user.MfaFactors {
+		if factor.Confirmed() {
+			remaining++
+		}
+	}
+	ms.eventDispatcher.Dispatch(ctx, events.MfaDisabled{Username: user.Username.String(), Method: string(method), Remaining: remaining, At: now})
+	return nil
+}
+
+// findUser loads the authenticated user.
+func (ms *MfaService) findUser(ctx context.Context, username string) (domain.User, error) {
+	user, err := ms.userPersistence.FindUser(ctx, domain.NormalizeUsername(username))
+	if err != nil {
+		return domain.User{}, fmt.Errorf("error finding user: %w", err)
+	}
+	return user, nil
+}
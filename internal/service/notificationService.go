@@ -0,0 +1,147 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"user-auth-hexagonal-architecture/internal/device"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/ports/messaging"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+)
+
+// NotificationService notifies users about activity on their account: the registration, password
+// changes, logins from new devices and account locks. It decides on the channels according to the
+// notification preferences of the user's profile and sends through the configured channel ports.
+// It implements the EventHandler interface from the messaging ports package.
+type NotificationService struct {
+	userPersistence    persistence.UserPersistencePort
+	profilePersistence persistence.ProfilePersistencePort
+	suppressions       persistence.EmailSuppressionPersistencePort
+	channels           map[domain.NotificationChannel]messaging.NotificationChannelPort
+}
+
+// NewNotificationService creates a new instance of NotificationService.
+//
+// Parameters:
+//   - userPersistence: An implementation of UserPersistencePort for looking up the email address of users
+//   - profilePersistence: An implementation of ProfilePersistencePort for reading the notification preferences
+//   - suppressions: An implementation of EmailSuppressionPersistencePort for skipping addresses that bounced
+//   - channels: The channels notifications can be sent on; notifications for other channels are skipped
+//
+// Returns:
+//   - *NotificationService: A pointer to the newly created NotificationService
+func NewNotificationService(userPersistence persistence.UserPersistencePort, profilePersistence persistence.ProfilePersistencePort, suppressions persistence.EmailSuppressionPersistencePort, channels ...messaging.NotificationChannelPort) *NotificationService {
+	byChannel := make(map[domain.NotificationChannel]messaging.NotificationChannelPort, len(channels))
+	for _, channel := range channels {
+		byChannel[channel.Channel()] = channel
+	}
+	return &NotificationService{userPersistence, profilePersistence, suppressions, byChannel}
+}
+
+// Handle sends a notification for UserRegistered, PasswordChanged, AccountLocked and UserLoggedIn
+// events of logins from new devices. Other events are ignored.
+//
+// The notification is sent on every channel the user chose for its topic. A channel the user has
+// no address for, e.g. SMS without a phone number, is skipped, as is email to a suppressed address
+// that bounced or complained. A failing channel does not prevent sending on the remaining ones.
+//
+// Parameters:
+//   - ctx: The context the event was dispatched with
+//   - event: The domain event to notify about
+//
+// Returns:
+//   - error: An error if the user cannot be loaded or a channel fails
+func (ns *NotificationService) Handle(ctx context.Context, event events.Event) error {
+	notification, ok := notificationFor(ctx, event)
+	if !ok {
+		return nil
+	}
+	username := domain.NormalizeUsername(notification.Username)
+
+	profile, err := ns.profilePersistence.FindProfile(ctx, username)
+	if err != nil {
+		return fmt.Errorf("failed to load notification preferences: %w", err)
+	}
+	channels := profile.Notifications.Channels(notification.Topic)
+	if len(channels) == 0 {
+		return nil
+	}
+	user, err := ns.userPersistence.FindUser(ctx, username)
+	if err != nil {
+		return fmt.Errorf("failed to load user: %w", err)
+	}
+
+	notification.Email = user.Email.String()
+	notification.PhoneNumber = profile.PhoneNumber
+	notification.DisplayName = profile.DisplayName
+	notification.Locale = profile.Locale
+
+	var errs []error
+	for _, channel := range channels {
+		port, ok := ns.channels[channel]
+		if !ok {
+			logger.WarnContext(ctx, "Skipping notification, channel is not configured", "topic", notification.Topic, "username", notification.Username, "channel", channel)
+			continue
+		}
+		if (channel == domain.NotificationChannelEmail && notification.Email == "") ||
+			(channel == domain.NotificationChannelSMS && notification.PhoneNumber == "") {
+			continue
+		}
+		if channel == domain.NotificationChannelEmail {
+			suppressed, err := ns.suppressions.IsEmailSuppressed(ctx, notification.Email)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to check the email suppressions: %w", err))
+				continue
+			}
+			if suppressed {
+				logger.InfoContext(ctx, "Skipping notification, the email address is suppressed", "topic", notification.Topic, "username", notification.Username)
+				continue
+			}
+		}
+		if err := port.Send(ctx, notification); err != nil {
+			errs = append(errs, fmt.Errorf("failed to send %s notification on channel %s: %w", notification.Topic, channel, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// notificationFor maps an event onto the notification the user receives for it, without the
+// contact details. It reports false for events users are not notified about.
+func notificationFor(ctx context.Context, event events.Event) (domain.Notification, bool) {
+	notification := domain.Notification{At: event.OccurredAt()}
+	switch e := event.(type) {
+	case events.UserRegistered:
+		notification.Topic = domain.NotificationTopicRegistration
+		notification.Username = e.Username
+	case events.PasswordChanged:
+		notification.Topic = domain.NotificationTopicPasswordChanged
+		notification.Username = e.Username
+	case events.UserLoggedIn:
+		if !e.NewDevice {
+			return domain.Notification{}, false
+		}
+		loginDevice := device.FromContext(ctx)
+		notification.Topic = domain.NotificationTopicNewDeviceLogin
+		notification.Username = e.Username
+		notification.Details = map[string]string{
+			"userAgent": loginDevice.UserAgent,
+			"ipAddress": loginDevice.IPAddress,
+			"country":   loginDevice.Country,
+		}
+	case events.AccountLocked:
+		notification.Topic = domain.NotificationTopicAccountLocked
+		notification.Username = e.Username
+		notification.Details = map[string]string{"reason": e.Reason}
+		if !e.Until.IsZero() {
+			notification.Details["until"] = e.Until.Format(time.RFC3339)
+		}
+	default:
+		return domain.Notification{}, false
+	}
+	return notification, true
+}
@@ -0,0 +1,205 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/ports/messaging"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/security"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+	"user-auth-hexagonal-architecture/internal/ports/telemetry"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+	"user-auth-hexagonal-architecture/internal/tenant"
+)
+
+// PasswordChangeService handles the business logic for users replacing the temporary password an
+// administrator has set, with the password change token issued by the login, and for administrators
+// resetting the password of a user to a temporary one.
+// It implements the ChangeTemporaryPasswordPort and ResetPasswordPort interfaces from the usecases package.
+type PasswordChangeService struct {
+	userAdminPersistence persistence.UserAdminPersistencePort
+	sessionPersistence   persistence.SessionPersistencePort
+	eventDispatcher      messaging.EventDispatcherPort
+	metrics              telemetry.MetricsPort
+	tenantRegistry       usecases.TenantRegistryPort
+	passwordHasher       security.PasswordHasherPort
+	clock                system.ClockPort
+	tokens               tokenIssuer
+}
+
+// NewPasswordChangeService creates a new instance of PasswordChangeService.
+//
+// Parameters:
+//   - userAdminPersistence: An implementation of UserAdminPersistencePort for loading the user and storing the password
+//   - sessionPersistence: An implementation of SessionPersistencePort for revoking the sessions of users whose password was reset
+//   - eventDispatcher: An implementation of EventDispatcherPort for emitting domain events
+//   - metrics: An implementation of MetricsPort for reporting the password hashing duration
+//   - tenantRegistry: An implementation of TenantRegistryPort for the tenant and password policy of the request
+//   - passwordHasher: An implementation of PasswordHasherPort for hashing the new password
+//   - clock: An implementation of ClockPort for reading the current time
+//   - signingKeys: An implementation of SigningKeyPort providing the keys the password change tokens are signed with
+//
+// Returns:
+//   - *PasswordChangeService: A pointer to the newly created PasswordChangeService
+func NewPasswordChangeService(userAdminPersistence persistence.UserAdminPersistencePort, sessionPersistence persistence.SessionPersistencePort, eventDispatcher messaging.EventDispatcherPort, metrics telemetry.MetricsPort, tenantRegistry usecases.TenantRegistryPort, passwordHasher security.PasswordHasherPort, clock system.ClockPort, signingKeys security.SigningKeyPort) *PasswordChangeService {
+	return &PasswordChangeService{userAdminPersistence, sessionPersistence, eventDispatcher, metrics, tenantRegistry, passwordHasher, clock, tokenIssuer{keys: signingKeys}}
+}
+
+// ChangeTemporaryPassword replaces the temporary password of the user a password change token was
+// issued for.
+//
+// This method performs the following steps:
+// 1. Verifies the password change token and checks that it was issued in the tenant of the request.
+// 2. Loads the user and checks that the account may log in and still has a temporary password.
+// 3. Validates the new password against the password policy of the tenant; it must differ from the temporary one.
+// 4. Stores the hash of the new password, which clears the temporary flag, and emits a PasswordChanged event.
+//
+// A token can only be used once, as the user has no temporary password anymore afterwards.
+// Logging in with the new password issues the access token.
+//
+// Parameters:
+//   - ctx: The context of the request
+//   - passwordChangeToken: The token issued by the login with the temporary password
+//   - newPassword: The password chosen by the user
+//
+// Returns:
+//   - error: errorx.ErrInvalidPasswordChangeToken if the token is malformed, expired, issued in another
+//     tenant or has been used, errorx.ErrAccountDisabled, errorx.ErrAccountLocked or errorx.ErrAccountPending
+//     if the account may not log in, errorx.ErrWeakPassword if the new password is rejected, or a wrapped
+//     persistence or hashing error
+func (pc *PasswordChangeService) ChangeTemporaryPassword(ctx context.Context, passwordChangeToken string, newPassword string) (err error) {
+	ctx, span := tracer.Start(ctx, "PasswordChangeService.ChangeTemporaryPassword")
+	defer func() { endSpan(span, err) }()
+
+	userID, tenantID, err := pc.tokens.parsePasswordChangeToken(passwordChangeToken)
+	if err != nil {
+		return err
+	}
+	userTenant, err := pc.tenantRegistry.ResolveTenant(ctx)
+	if err != nil {
+		return err
+	}
+	if tenantID != userTenant.ID {
+		return errorx.ErrInvalidPasswordChangeToken.Detailf("issued in another tenant")
+	}
+
+	user, err := pc.userAdminPersistence.FindUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, errorx.ErrUserNotFound) {
+			return errorx.ErrInvalidPasswordChangeToken
+		}
+		return fmt.Errorf("error finding user: %w", err)
+	}
+	if err := user.CanLogIn(); err != nil {
+		return err
+	}
+	if !user.MustChangePassword {
+		return errorx.ErrInvalidPasswordChangeToken.Detailf("the password has been changed already")
+	}
+
+	if err := userTenant.Settings.PasswordPolicy.Validate(newPassword, user.Username); err != nil {
+		return err
+	}
+	if pc.passwordHasher.Verify(user.Password, newPassword) == nil {
+		return errorx.ErrWeakPassword.Detailf("the new password must differ from the temporary password")
+	}
+	hashStart := time.Now()
+	password, err := pc.passwordHasher.Hash(newPassword)
+	pc.metrics.ObservePasswordHashing(telemetry.PasswordHash, time.Since(hashStart))
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user.ChangePassword(password)
+	if err := pc.userAdminPersistence.UpdatePassword(ctx, user.ID, user.Password, user.MustChangePassword); err != nil {
+		return fmt.Errorf("error saving password: %w", err)
+	}
+	pc.eventDispatcher.Dispatch(ctx, events.PasswordChanged{Username: user.Username.String(), At: pc.clock.Now()})
+	return nil
+}
+
+// ResetPassword replaces the password of a user with a temporary one set by an administrator, e.g.
+// for a user who forgot the password.
+//
+// This method performs the following steps:
+// 1. Loads the user; deleted and erased accounts cannot be reset.
+// 2. Validates the temporary password against the password policy of the tenant of the user.
+// 3. Stores its hash and flags it as temporary, so the user has to replace it at the next login.
+// 4. Revokes the sessions of the user, as whoever knew the old password may hold one, and emits a
+// PasswordChanged event.
+//
+// Parameters:
+//   - ctx: The context of the request
+//   - id: The id of the user
+//   - temporaryPassword: The password the user logs in with once
+//
+// Returns:
+//   - error: errorx.ErrUserNotFound if the user does not exist, errorx.ErrInvalidStatusTransition if
+//     the account was deleted, errorx.ErrWeakPassword if the password is rejected, or a wrapped
+//     persistence or hashing error
+func (pc *PasswordChangeService) ResetPassword(ctx context.Context, id string, temporaryPassword string) (err error) {
+	ctx, span := tracer.Start(ctx, "PasswordChangeService.ResetPassword")
+	defer func() { endSpan(span, err) }()
+
+	user, err := pc.userAdminPersistence.FindUserByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, errorx.ErrUserNotFound) {
+			return err
+		}
+		return fmt.Errorf("error finding user: %w", err)
+	}
+	if user.Status == domain.StatusDeleted {
+		return errorx.ErrInvalidStatusTransition.Detailf("the account has been deleted")
+	}
+	// the policy of the account applies, not the one of the administrator's request
+	userTenant, err := pc.tenantRegistry.ResolveTenant(tenant.WithID(ctx, user.TenantID))
+	if err != nil {
+		return err
+	}
+	if err := userTenant.Settings.PasswordPolicy.Validate(temporaryPassword, user.Username); err != nil {
+		return err
+	}
+
+	hashStart := time.Now()
+	password, err := pc.passwordHasher.Hash(temporaryPassword)
+	pc.metrics.ObservePasswordHashing(telemetry.PasswordHash, time.Since(hashStart))
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user.SetTemporaryPassword(password)
+	if err := pc.userAdminPersistence.UpdatePassword(ctx, user.ID, user.Password, user.MustChangePassword); err != nil {
+		return fmt.Errorf("error saving password: %w", err)
+	}
+	if err := pc.revokeSessions(ctx, user); err != nil {
+		// the password is reset at this point, the sessions keep working until they expire
+		logger.ErrorContext(ctx, "Error revoking sessions after password reset", "username", user.Username.String(), "error", err)
+	}
+	pc.eventDispatcher.Dispatch(ctx, events.PasswordChanged{Username: user.Username.String(), At: pc.clock.Now()})
+	return nil
+}
+
+// revokeSessions revokes all active sessions of a user whose password was reset.
+func (pc *PasswordChangeService) revokeSessions(ctx context.Context, user domain.User) error {
+	sessions, err := pc.sessionPersistence.FindSessionsByUser(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find sessions: %w", err)
+	}
+	now := pc.clock.Now()
+	for _, session := range sessions {
+		if err := session.Revoke(domain.SessionRevokedByAdmin, now); err != nil {
+			continue
+		}
+		if err := pc.sessionPersistence.SaveSession(ctx, session); err != nil {
+			return fmt.Errorf("failed to revoke session: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// PasswordStrengthService handles the business logic for rating passwords before they are chosen.
+// It implements the EstimatePasswordStrengthPort interface from the usecases package.
+//
+// The estimate applies the password policy of the tenant of the request, the same rules registration
+// and password changes enforce, so clients do not have to reimplement them.
+type PasswordStrengthService struct {
+	tenantRegistry usecases.TenantRegistryPort
+}
+
+// NewPasswordStrengthService creates a new instance of PasswordStrengthService.
+//
+// Parameters:
+//   - tenantRegistry: An implementation of TenantRegistryPort for the password policy of the request
+//
+// Returns:
+//   - *PasswordStrengthService: A pointer to the newly created PasswordStrengthService
+func NewPasswordStrengthService(tenantRegistry usecases.TenantRegistryPort) *PasswordStrengthService {
+	return &PasswordStrengthService{tenantRegistry}
+}
+
+// EstimatePasswordStrength rates a password and checks it against the password policy of the tenant.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - password: The plain text password
+//   - username: The username the password is for, empty if not known yet
+//
+// Returns:
+//   - domain.PasswordStrength: The score, the policy violations and suggestions to improve the password
+//   - error: An error if the tenant cannot be resolved
+func (ps *PasswordStrengthService) EstimatePasswordStrength(ctx context.Context, password string, username string) (strength domain.PasswordStrength, err error) {
+	ctx, span := tracer.Start(ctx, "PasswordStrengthService.EstimatePasswordStrength")
+	defer func() { endSpan(span, err) }()
+
+	userTenant, err := ps.tenantRegistry.ResolveTenant(ctx)
+	if err != nil {
+		return domain.PasswordStrength{}, err
+	}
+	return domain.EstimatePasswordStrength(password, domain.NormalizeUsername(username), userTenant.Settings.PasswordPolicy), nil
+}
@@ -0,0 +1,167 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+)
+
+// PolicyService handles the business logic for attribute-based access policies.
+// It implements the ManagePoliciesPort and AuthorizePort interfaces from the usecases package.
+//
+// The policies are compiled and kept in memory, so access decisions do not hit the database.
+// Changes made through this instance apply immediately; changes made by other instances apply
+// once RefreshEvery has picked them up.
+type PolicyService struct {
+	policyPersistence persistence.PolicyPersistencePort
+	clock             system.ClockPort
+	mu                sync.Mutex
+	policies          atomic.Pointer[domain.PolicySet]
+}
+
+// NewPolicyService creates a new instance of PolicyService without policies, until Refresh
+// loads the stored ones.
+//
+// Parameters:
+//   - policyPersistence: An implementation of PolicyPersistencePort for storing policies
+//   - clock: An implementation of ClockPort for reading the current time
+//
+// Returns:
+//   - *PolicyService: A pointer to the newly created PolicyService
+func NewPolicyService(policyPersistence persistence.PolicyPersistencePort, clock system.ClockPort) *PolicyService {
+	ps := &PolicyService{policyPersistence: policyPersistence, clock: clock}
+	ps.policies.Store(&domain.PolicySet{})
+	return ps
+}
+
+// Refresh reloads the policies from the persistence layer.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//
+// Returns:
+//   - error: A wrapped persistence or validation error; the previous policies stay in effect
+func (ps *PolicyService) Refresh(ctx context.Context) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	return ps.refresh(ctx)
+}
+
+// RefreshEvery reloads the policies periodically until the context is cancelled.
+// Failed reloads are logged and keep the previous policies in effect.
+//
+// Parameters:
+//   - ctx: A context.Context that stops the refreshing
+//   - interval: The time between two reloads
+func (ps *PolicyService) RefreshEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := ps.Refresh(ctx); err != nil {
+				logger.ErrorContext(ctx, "Error refreshing access policies", "error", err)
+			}
+		}
+	}
+}
+
+// Authorize evaluates the policies against an access request.
+//
+// Parameters:
+//   - ctx: A context.Context of the request
+//   - request: The subject, resource, action and environment attributes of the request
+//
+// Returns:
+//   - domain.PolicyDecision: The combined decision of all applicable policies
+func (ps *PolicyService) Authorize(ctx context.Context, request domain.AccessRequest) domain.PolicyDecision {
+	return ps.policies.Load().Decide(request)
+}
+
+// ListPolicies returns all policies sorted by id.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//
+// Returns:
+//   - []domain.Policy: The policies
+//   - error: A wrapped persistence error
+func (ps *PolicyService) ListPolicies(ctx context.Context) ([]domain.Policy, error) {
+	if err := ps.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return ps.policies.Load().Policies(), nil
+}
+
+// SavePolicy creates a policy or replaces an existing one with the same id.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - policy: The policy to store
+//
+// Returns:
+//   - domain.Policy: The stored policy
+//   - error: An error wrapping errorx.ErrInvalidPolicy, or a wrapped persistence error
+func (ps *PolicyService) SavePolicy(ctx context.Context, policy domain.Policy) (domain.Policy, error) {
+	if err := policy.Validate(); err != nil {
+		return domain.Policy{}, err
+	}
+	policy.UpdatedAt = ps.clock.Now()
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if err := ps.policyPersistence.SavePolicy(ctx, policy); err != nil {
+		return domain.Policy{}, fmt.Errorf("failed to save policy: %w", err)
+	}
+	if err := ps.refresh(ctx); err != nil {
+		logger.ErrorContext(ctx, "Error refreshing access policies after saving a policy", "policy_id", policy.ID, "error", err)
+	}
+	return policy, nil
+}
+
+// DeletePolicy deletes a policy.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The id of the policy
+//
+// Returns:
+//   - error: errorx.ErrPolicyNotFound, or a wrapped persistence error
+func (ps *PolicyService) DeletePolicy(ctx context.Context, id string) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if err := ps.policyPersistence.DeletePolicy(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete policy: %w", err)
+	}
+	if err := ps.refresh(ctx); err != nil {
+		logger.ErrorContext(ctx, "Error refreshing access policies after deleting a policy", "policy_id", id, "error", err)
+	}
+	return nil
+}
+
+// refresh loads, compiles and publishes the stored policies. The caller must hold mu.
+func (ps *PolicyService) refresh(ctx context.Context) error {
+	policies, err := ps.policyPersistence.FindPolicies(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load policies: %w", err)
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].ID < policies[j].ID })
+
+	set, err := domain.NewPolicySet(policies)
+	if err != nil {
+		return fmt.Errorf("failed to compile policies: %w", err)
+	}
+	ps.policies.Store(&set)
+	return nil
+}
@@ -0,0 +1,96 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"fmt"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/ports/messaging"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+)
+
+// ProfileService handles the business logic for user profiles.
+// It implements the GetProfilePort and UpdateProfilePort interfaces from the usecases package.
+type ProfileService struct {
+	profilePersistence persistence.ProfilePersistencePort
+	eventDispatcher    messaging.EventDispatcherPort
+	clock              system.ClockPort
+}
+
+// NewProfileService creates a new instance of ProfileService.
+//
+// Parameters:
+//   - profilePersistence: An implementation of ProfilePersistencePort for reading and storing profiles
+//   - eventDispatcher: An implementation of EventDispatcherPort for emitting domain events
+//   - clock: An implementation of ClockPort for reading the current time
+//
+// Returns:
+//   - *ProfileService: A pointer to the newly created ProfileService
+func NewProfileService(profilePersistence persistence.ProfilePersistencePort, eventDispatcher messaging.EventDispatcherPort, clock system.ClockPort) *ProfileService {
+	return &ProfileService{profilePersistence, eventDispatcher, clock}
+}
+
+// GetProfile returns the complete profile of a user, for showing it to the user.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - username: The username of the user
+//
+// Returns:
+//   - domain.Profile: The profile including private attributes
+//   - error: errorx.ErrUserNotFound, or a wrapped persistence error
+func (ps *ProfileService) GetProfile(ctx context.Context, username string) (domain.Profile, error) {
+	profile, err := ps.profilePersistence.FindProfile(ctx, domain.NormalizeUsername(username))
+	if err != nil {
+		return domain.Profile{}, fmt.Errorf("error finding profile: %w", err)
+	}
+	return profile, nil
+}
+
+// GetPublicProfile returns the profile of a user as other users may see it.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - username: The username of the user
+//
+// Returns:
+//   - domain.Profile: The profile without private attributes
+//   - error: errorx.ErrUserNotFound, or a wrapped persistence error
+func (ps *ProfileService) GetPublicProfile(ctx context.Context, username string) (domain.Profile, error) {
+	profile, err := ps.GetProfile(ctx, username)
+	if err != nil {
+		return domain.Profile{}, err
+	}
+	return profile.PublicView(), nil
+}
+
+// UpdateProfile applies a partial update to the profile of a user and emits a ProfileUpdated event.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - username: The username of the user
+//   - changes: The fields to change
+//
+// Returns:
+//   - domain.Profile: The updated profile
+//   - error: An error wrapping errorx.ErrInvalidProfile, errorx.ErrUserNotFound, or a wrapped persistence error
+func (ps *ProfileService) UpdateProfile(ctx context.Context, username string, changes domain.ProfileChanges) (domain.Profile, error) {
+	normalized := domain.NormalizeUsername(username)
+	profile, err := ps.profilePersistence.FindProfile(ctx, normalized)
+	if err != nil {
+		return domain.Profile{}, fmt.Errorf("error finding profile: %w", err)
+	}
+
+	if err := profile.Apply(changes, ps.clock.Now()); err != nil {
+		return domain.Profile{}, err
+	}
+	if err := ps.profilePersistence.UpdateProfile(ctx, normalized, profile); err != nil {
+		return domain.Profile{}, fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	ps.eventDispatcher.Dispatch(ctx, events.ProfileUpdated{Username: normalized.String(), At: profile.UpdatedAt})
+	return profile, nil
+}
@@ -0,0 +1,164 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/ports/messaging"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/security"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// RefreshSessionService handles the business logic for renewing the tokens of a session with its
+// refresh token, without asking the user for the password again.
+// It implements the RefreshSessionPort interface from the usecases package.
+type RefreshSessionService struct {
+	sessionPersistence   persistence.SessionPersistencePort
+	userAdminPersistence persistence.UserAdminPersistencePort
+	groupPersistence     persistence.GroupPersistencePort
+	tenantRegistry       usecases.TenantRegistryPort
+	eventDispatcher      messaging.EventDispatcherPort
+	clock                system.ClockPort
+	tokens               tokenIssuer
+}
+
+// NewRefreshSessionService creates a new instance of RefreshSessionService.
+//
+// Parameters:
+//   - sessionPersistence: An implementation of SessionPersistencePort for the sessions the refresh tokens belong to
+//   - userAdminPersistence: An implementation of UserAdminPersistencePort for loading the current state of the user
+//   - groupPersistence: An implementation of GroupPersistencePort for resolving the roles inherited from groups
+//   - roleRegistry: An implementation of RoleRegistryPort for resolving the permissions of the user's roles
+//   - tenantRegistry: An implementation of TenantRegistryPort for the settings of the tenant the refresh is made for
+//   - eventDispatcher: An implementation of EventDispatcherPort for publishing revocations after a token reuse
+//   - clock: An implementation of ClockPort for reading the current time
+//   - random: An implementation of RandomSourcePort for generating refresh tokens
+//   - signingKeys: An implementation of SigningKeyPort providing the key used to sign access tokens
+//   - tokenSettings: The lifetime of the rotated refresh tokens
+//
+// Returns:
+//   - *RefreshSessionService: A pointer to the newly created RefreshSessionService
+func NewRefreshSessionService(sessionPersistence persistence.SessionPersistencePort, userAdminPersistence persistence.UserAdminPersistencePort, groupPersistence persistence.GroupPersistencePort, roleRegistry usecases.RoleRegistryPort, tenantRegistry usecases.TenantRegistryPort, eventDispatcher messaging.EventDispatcherPort, clock system.ClockPort, random system.RandomSourcePort, signingKeys security.SigningKeyPort, tokenSettings *TokenSettings) *RefreshSessionService {
+	tokens := tokenIssuer{roleRegistry: roleRegistry, random: random, keys: signingKeys, settings: tokenSettings}
+	return &RefreshSessionService{sessionPersistence, userAdminPersistence, groupPersistence, tenantRegistry, eventDispatcher, clock, tokens}
+}
+
+// RefreshSession issues a new access token and a new refresh token for the session of a refresh token.
+//
+// This method performs the following steps:
+// 1. Finds the session the refresh token belongs to in the tenant of the request.
+// 2. Checks that the token is the one issued last for the session, revoking the session if it was rotated before.
+// 3. Loads the current state of the user, so role and group changes take effect in the new access token.
+// 4. Raises the token version of the session to the one of the user, so access tokens issued before a
+// role change stop working, rotates the refresh token, which extends the session, and signs the new access token.
+//
+// A refresh token that has already been rotated was copied, so its session is revoked with a
+// SessionRevoked event and neither the legitimate client nor the one holding the copy can continue.
+//
+// Parameters:
+//   - ctx: The context of the request, cancelling it aborts the refresh.
+//   - refreshToken: The refresh token issued at login or by the previous refresh.
+//
+// Returns:
+//   - domain.AuthTokens: The new access token, its expiry and the new refresh token.
+//   - error: An error in the following cases:
+//   - errorx.ErrInvalidRefreshToken if the token is malformed, unknown, belongs to another tenant or its session has ended.
+//   - errorx.ErrRefreshTokenReused if the token has already been rotated, including by a concurrent refresh.
+//   - errorx.ErrAccountDisabled, errorx.ErrAccountLocked or errorx.ErrAccountPending if the account is not ACTIVE anymore.
+//   - If there's an error while loading the tenant, the session, the user or the groups, or while storing the session.
+//   - If there's an error while generating the refresh token or signing the access token.
+func (rs *RefreshSessionService) RefreshSession(ctx context.Context, refreshToken string) (tokens domain.AuthTokens, err error) {
+	ctx, span := tracer.Start(ctx, "RefreshSessionService.RefreshSession")
+	defer func() { endSpan(span, err) }()
+
+	sessionID, secret, err := domain.ParseRefreshToken(refreshToken)
+	if err != nil {
+		return domain.AuthTokens{}, err
+	}
+	userTenant, err := rs.tenantRegistry.ResolveTenant(ctx)
+	if err != nil {
+		return domain.AuthTokens{}, err
+	}
+	session, err := rs.sessionPersistence.FindSession(ctx, sessionID)
+	if err == nil && session.TenantID != userTenant.ID {
+		// sessions of other tenants are treated like unknown sessions
+		err = errorx.ErrSessionNotFound
+	}
+	if err != nil {
+		if errors.Is(err, errorx.ErrSessionNotFound) {
+			return domain.AuthTokens{}, errorx.ErrInvalidRefreshToken
+		}
+		return domain.AuthTokens{}, fmt.Errorf("error finding session: %w", err)
+	}
+
+	now := rs.clock.Now()
+	if err := session.CheckRefreshToken(secret, now); err != nil {
+		if errors.Is(err, errorx.ErrRefreshTokenReused) {
+			rs.revokeReused(ctx, session, now)
+		}
+		return domain.AuthTokens{}, err
+	}
+
+	user, err := rs.userAdminPersistence.FindUserByID(ctx, session.UserID)
+	if err != nil {
+		if errors.Is(err, errorx.ErrUserNotFound) {
+			return domain.AuthTokens{}, errorx.ErrInvalidRefreshToken
+		}
+		return domain.AuthTokens{}, fmt.Errorf("error finding user: %w", err)
+	}
+	if err := user.CanLogIn(); err != nil {
+		return domain.AuthTokens{}, err
+	}
+	groups, err := rs.groupPersistence.FindGroupsByMember(ctx, user.ID)
+	if err != nil {
+		return domain.AuthTokens{}, fmt.Errorf("error finding groups: %w", err)
+	}
+	roles := domain.EffectiveRoles(user, groups)
+
+	// the new access token carries the current roles, the ones issued before a role change are outdated
+	session.RaiseTokenVersion(user.TokenVersion)
+	previousHash := session.RefreshTokenHash
+	newRefreshToken, err := rs.tokens.bindRefreshToken(&session, now)
+	if err != nil {
+		return domain.AuthTokens{}, err
+	}
+	session.LastSeenAt = now
+	if err := rs.sessionPersistence.SaveRefreshedSession(ctx, session, previousHash); err != nil {
+		if errors.Is(err, errorx.ErrRefreshTokenReused) {
+			return domain.AuthTokens{}, err
+		}
+		return domain.AuthTokens{}, fmt.Errorf("error saving session: %w", err)
+	}
+
+	expiresAt := now.Add(userTenant.Settings.AccessTokenTTL)
+	signedString, err := rs.tokens.signAccessToken(user, roles, groups, session, expiresAt)
+	if err != nil {
+		return domain.AuthTokens{}, err
+	}
+	return domain.AuthTokens{AccessToken: signedString, TokenType: "Bearer", ExpiresAt: expiresAt, RefreshToken: newRefreshToken}, nil
+}
+
+// revokeReused ends a session whose rotated refresh token was presented again and emits events.SessionRevoked.
+func (rs *RefreshSessionService) revokeReused(ctx context.Context, session domain.Session, now time.Time) {
+	if err := session.Revoke(domain.SessionRevokedRefreshTokenReused, now); err != nil {
+		return
+	}
+	if err := rs.sessionPersistence.SaveSession(ctx, session); err != nil {
+		logger.ErrorContext(ctx, "Error revoking session after refresh token reuse", "session_id", session.ID, "error", err)
+		return
+	}
+	rs.eventDispatcher.Dispatch(ctx, events.SessionRevoked{
+		Username:  session.Username.String(),
+		SessionID: session.ID,
+		Reason:    string(domain.SessionRevokedRefreshTokenReused),
+		At:        now,
+	})
+}
@@ -3,51 +3,183 @@
 package service
 
 import (
+	"context"
 	"fmt"
-	"golang.org/x/crypto/bcrypt"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/ports/hooks"
+	"user-auth-hexagonal-architecture/internal/ports/messaging"
 	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/security"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+	"user-auth-hexagonal-architecture/internal/ports/telemetry"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
 )
 
 // RegisterUserService handles the business logic for user registration.
 // It implements the RegisterUserPort interface from the usecases package.
+//
+// Integrators extend the registration with implementations of RegistrationInterceptorPort, which
+// run in order before and after the user is saved.
 type RegisterUserService struct {
-	userPersistence persistence.UserPersistencePort
+	userPersistence      persistence.UserPersistencePort
+	credentialEventStore persistence.CredentialEventStorePort
+	eventDispatcher      messaging.EventDispatcherPort
+	metrics              telemetry.MetricsPort
+	tenantRegistry       usecases.TenantRegistryPort
+	consentGate          usecases.ConsentGatePort
+	passwordHasher       security.PasswordHasherPort
+	clock                system.ClockPort
+	features             system.FeatureFlagPort
+	usernamePolicy       domain.UsernamePolicy
+	canonicalizer        domain.Canonicalizer
+	interceptors         []hooks.RegistrationInterceptorPort
 }
 
 // NewRegisterUserService creates a new instance of RegisterUserService.
 //
 // Parameters:
 //   - userPersistence: An implementation of UserPersistencePort for storing user data
+//   - credentialEventStore: An implementation of CredentialEventStorePort for the credential audit trail
+//   - eventDispatcher: An implementation of EventDispatcherPort for emitting domain events
+//   - metrics: An implementation of MetricsPort for reporting the password hashing duration
+//   - tenantRegistry: An implementation of TenantRegistryPort for the password policy of the tenant
+//   - consentGate: An implementation of ConsentGatePort for the acceptance of the terms and the privacy policy
+//   - passwordHasher: An implementation of PasswordHasherPort for hashing the password
+//   - clock: An implementation of ClockPort for reading the current time
+//   - features: An implementation of FeatureFlagPort deciding whether self-registration is open
+//   - usernamePolicy: The rules new usernames have to satisfy
+//   - canonicalizer: The rules under which usernames and email addresses count as duplicates
+//   - interceptors: Implementations of RegistrationInterceptorPort, run in the given order
 //
 // Returns:
 //   - *RegisterUserService: A pointer to the newly created RegisterUserService
-func NewRegisterUserService(userPersistence persistence.UserPersistencePort) *RegisterUserService {
-	return &RegisterUserService{userPersistence}
+func NewRegisterUserService(userPersistence persistence.UserPersistencePort, credentialEventStore persistence.CredentialEventStorePort, eventDispatcher messaging.EventDispatcherPort, metrics telemetry.MetricsPort, tenantRegistry usecases.TenantRegistryPort, consentGate usecases.ConsentGatePort, passwordHasher security.PasswordHasherPort, clock system.ClockPort, features system.FeatureFlagPort, usernamePolicy domain.UsernamePolicy, canonicalizer domain.Canonicalizer, interceptors ...hooks.RegistrationInterceptorPort) *RegisterUserService {
+	return &RegisterUserService{userPersistence, credentialEventStore, eventDispatcher, metrics, tenantRegistry, consentGate, passwordHasher, clock, features, usernamePolicy, canonicalizer, interceptors}
 }
 
 // RegisterUser handles the registration of a new user.
 //
-// This method performs the following steps:
-// 1. Hashes the provided password using bcrypt
-// 2. Saves the user's username and hashed password using the persistence layer
+// This method performs the following steps, unless the open-registration feature flag is off for the tenant:
+// 1. Normalizes the username and checks it against the username policy, validates the email and checks the password policy of the tenant
+// 2. Checks that the username is available, so taken usernames are rejected before the costly hashing
+// 3. Hashes the provided password with the configured algorithm
+// 4. Checks that the current terms and privacy policy are accepted
+// 5. Runs the BeforeRegistration interceptors, any of which can reject the registration
+// 6. Saves the new user using the persistence layer, unless its canonical username or email is taken
+// 7. Records the consents and the creation of the credentials in the credential audit trail
+// 8. Emits a UserRegistered event and runs the AfterRegistration interceptors
+//
+// The availability check of step 2 is only a shortcut: two registrations of the same username can
+// both pass it, so the unique indexes of the persistence layer decide atomically in step 6.
 //
 // Parameters:
+//   - ctx: The context of the request, cancelling it aborts the registration
 //   - username: The username for the new user
 //   - password: The plain text password for the new user
+//   - email: The email address of the new user, may be empty
+//   - consents: The versions of the terms and privacy policy the user accepts
 //
 // Returns:
+//   - string: The id of the created user
 //   - error: An error if registration fails, nil otherwise
 //
 // Possible errors:
+//   - errorx.ErrRegistrationRejected if self-registration is closed
+//   - errorx.ErrInvalidUsername or errorx.ErrInvalidEmail if the username violates the username policy or the email is malformed
+//   - errorx.ErrTenantNotFound if the tenant of the request does not exist
+//   - errorx.ErrWeakPassword if the password violates the password policy of the tenant
+//   - errorx.ErrConsentRequired or errorx.ErrInvalidConsent if a current document version is not accepted
+//   - errorx.ErrRegistrationRejected if an interceptor rejects the registration
+//   - errorx.ErrUsernameTaken or errorx.ErrEmailTaken if another account has the same canonical username or email
+//   - If the availability check fails
 //   - If password hashing fails
 //   - If saving the user to the persistence layer fails
 //
-// Note: This method uses bcrypt's DefaultCost for password hashing.
-func (lu *RegisterUserService) RegisterUser(username string, password string) error {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+// Note: The algorithm and its cost parameters are configured in the PasswordHasherPort.
+func (lu *RegisterUserService) RegisterUser(ctx context.Context, username string, password string, email string, consents []domain.ConsentRef) (userID string, err error) {
+	ctx, span := tracer.Start(ctx, "RegisterUserService.RegisterUser")
+	defer func() { endSpan(span, err) }()
+
+	if !lu.features.Enabled(ctx, domain.FeatureOpenRegistration) {
+		return "", errorx.ErrRegistrationRejected.Detailf("self-registration is closed")
+	}
+	validUsername, err := lu.usernamePolicy.Validate(username)
+	if err != nil {
+		return "", err
+	}
+	var validEmail domain.Email
+	if email != "" {
+		if validEmail, err = domain.NewEmail(email); err != nil {
+			return "", err
+		}
+	}
+
+	userTenant, err := lu.tenantRegistry.ResolveTenant(ctx)
+	if err != nil {
+		return "", err
+	}
+	if err := userTenant.Settings.PasswordPolicy.Validate(password, validUsername); err != nil {
+		return "", err
+	}
+	available, err := lu.userPersistence.IsUsernameAvailable(ctx, validUsername, lu.canonicalizer.Username(validUsername))
 	if err != nil {
-		return fmt.Errorf("failed to hash password: %w", err)
+		return "", fmt.Errorf("failed to check username: %w", err)
+	}
+	if !available {
+		return "", errorx.ErrUsernameTaken
 	}
 
-	return lu.userPersistence.SaveUser(username, string(hashedPassword))
+	hashStart := time.Now()
+	hashedPassword, err := lu.passwordHasher.Hash(password)
+	lu.metrics.ObservePasswordHashing(telemetry.PasswordHash, time.Since(hashStart))
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user := domain.NewUser(userTenant.ID, validUsername, validEmail, hashedPassword, lu.clock.Now())
+	user.Canonicalize(lu.canonicalizer)
+	accepted, err := lu.consentGate.CheckConsents(ctx, user, consents)
+	if err != nil {
+		return "", err
+	}
+	for _, interceptor := range lu.interceptors {
+		if err := interceptor.BeforeRegistration(ctx, user.WithoutPassword()); err != nil {
+			if _, ok := errorx.As(err); ok {
+				return "", err
+			}
+			return "", fmt.Errorf("registration interceptor %s failed: %w", interceptor.Name(), err)
+		}
+	}
+	userID, err = lu.userPersistence.SaveUser(ctx, user)
+	if err != nil {
+		return "", err
+	}
+	for i := range accepted {
+		accepted[i].UserID = userID
+	}
+	if err := lu.consentGate.RecordConsents(ctx, accepted); err != nil {
+		// the user exists at this point and is asked for the consents again at login
+		logger.ErrorContext(ctx, "Error recording consents", "username", user.Username.String(), "error", err)
+	}
+
+	event := domain.NewCredentialEvent(user.Username.String(), domain.CredentialCreated, map[string]string{domain.CredentialDetailAlgorithm: lu.passwordHasher.Algorithm()})
+	event.OccurredAt = user.CreatedAt
+	if err := lu.credentialEventStore.AppendCredentialEvent(ctx, event); err != nil {
+		// the user exists at this point, so registration itself has succeeded
+		logger.ErrorContext(ctx, "Error recording credential event", "username", user.Username.String(), "error", err)
+	}
+
+	lu.eventDispatcher.Dispatch(ctx, events.UserRegistered{UserID: userID, TenantID: user.TenantID, Username: user.Username.String(), Roles: roleNames(user.Roles), At: event.OccurredAt})
+
+	user.ID = userID
+	for _, interceptor := range lu.interceptors {
+		if err := interceptor.AfterRegistration(ctx, user.WithoutPassword()); err != nil {
+			// the user exists at this point, so registration itself has succeeded
+			logger.ErrorContext(ctx, "Error running registration interceptor", "interceptor", interceptor.Name(), "username", user.Username.String(), "error", err)
+		}
+	}
+	return userID, nil
 }
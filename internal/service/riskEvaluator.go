@@ -0,0 +1,53 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/security"
+)
+
+// RiskEvaluator assesses the risk of login attempts by combining the signals of its providers
+// into a risk score and deciding with the risk policy whether the login is allowed, requires
+// multi-factor authentication or is blocked.
+type RiskEvaluator struct {
+	policy    domain.RiskPolicy
+	providers []security.RiskSignalProviderPort
+}
+
+// NewRiskEvaluator creates a new instance of RiskEvaluator.
+//
+// Parameters:
+//   - policy: The policy mapping risk scores onto actions
+//   - providers: Implementations of RiskSignalProviderPort raising the signals
+//
+// Returns:
+//   - *RiskEvaluator: A pointer to the newly created RiskEvaluator
+func NewRiskEvaluator(policy domain.RiskPolicy, providers ...security.RiskSignalProviderPort) *RiskEvaluator {
+	return &RiskEvaluator{policy, providers}
+}
+
+// Evaluate assesses the risk of a login attempt.
+//
+// A provider that fails is logged and skipped, so an unavailable signal source does not lock
+// users out.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - attempt: The login attempt with verified credentials
+//
+// Returns:
+//   - domain.RiskAssessment: The score, the raised signals and the action to take
+func (re *RiskEvaluator) Evaluate(ctx context.Context, attempt domain.LoginAttempt) domain.RiskAssessment {
+	var signals []domain.RiskSignal
+	for _, provider := range re.providers {
+		raised, err := provider.Signals(ctx, attempt)
+		if err != nil {
+			logger.WarnContext(ctx, "Error collecting risk signals", "username", attempt.User.Username.String(), "error", err)
+			continue
+		}
+		signals = append(signals, raised...)
+	}
+	return domain.AssessRisk(signals, re.policy)
+}
@@ -0,0 +1,188 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+)
+
+// maxTrackedFailureUsers bounds the number of usernames LoginFailureSignals remembers before it
+// forgets those without failures inside the window.
+const maxTrackedFailureUsers = 10000
+
+// SessionHistorySignals raises the new_device and new_country signals by comparing the device of a
+// login attempt with the devices of the user's previous sessions. The first login of a user raises
+// no signal, as there is nothing to compare with.
+// It implements the RiskSignalProviderPort interface from the security ports package.
+type SessionHistorySignals struct {
+	sessionPersistence persistence.SessionPersistencePort
+	newDeviceScore     int
+	newCountryScore    int
+}
+
+// NewSessionHistorySignals creates a new instance of SessionHistorySignals.
+//
+// Parameters:
+//   - sessionPersistence: An implementation of SessionPersistencePort for loading the previous sessions
+//   - newDeviceScore: The score of a user agent the user has not logged in with before
+//   - newCountryScore: The score of a country the user has not logged in from before
+//
+// Returns:
+//   - *SessionHistorySignals: A pointer to the newly created SessionHistorySignals
+func NewSessionHistorySignals(sessionPersistence persistence.SessionPersistencePort, newDeviceScore int, newCountryScore int) *SessionHistorySignals {
+	return &SessionHistorySignals{sessionPersistence, newDeviceScore, newCountryScore}
+}
+
+// Signals compares the device of the attempt with the devices of the previous sessions.
+// Attempts without a known country never raise new_country.
+func (sh *SessionHistorySignals) Signals(ctx context.Context, attempt domain.LoginAttempt) ([]domain.RiskSignal, error) {
+	sessions, err := sh.sessionPersistence.FindSessionsByUser(ctx, attempt.User.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sessions: %w", err)
+	}
+	if len(sessions) == 0 {
+		return nil, nil
+	}
+
+	knownDevice, knownCountry, anyCountry := false, false, false
+	for _, session := range sessions {
+		knownDevice = knownDevice || session.Device.UserAgent == attempt.Device.UserAgent
+		knownCountry = knownCountry || session.Device.Country == attempt.Device.Country
+		anyCountry = anyCountry || session.Device.Country != ""
+	}
+
+	var signals []domain.RiskSignal
+	if !knownDevice {
+		signals = append(signals, domain.RiskSignal{Name: domain.RiskSignalNewDevice, Score: sh.newDeviceScore, Detail: "first login with this user agent"})
+	}
+	if attempt.Device.Country != "" && anyCountry && !knownCountry {
+		signals = append(signals, domain.RiskSignal{Name: domain.RiskSignalNewCountry, Score: sh.newCountryScore, Detail: "first login from " + attempt.Device.Country})
+	}
+	return signals, nil
+}
+
+// LoginFailureSignals raises the recent_failures signal for users whose logins failed recently.
+// It counts the LoginFailed events in memory, so every instance only knows the failures it handled.
+// It implements the RiskSignalProviderPort interface from the security ports package and the
+// EventHandler interface from the messaging ports package.
+type LoginFailureSignals struct {
+	clock           system.ClockPort
+	window          time.Duration
+	scorePerFailure int
+	mu              sync.Mutex
+	failures        map[string][]time.Time
+}
+
+// NewLoginFailureSignals creates a new instance of LoginFailureSignals.
+//
+// Parameters:
+//   - clock: An implementation of ClockPort for reading the current time
+//   - window: How long a failed login counts
+//   - scorePerFailure: The score every failed login inside the window adds
+//
+// Returns:
+//   - *LoginFailureSignals: A pointer to the newly created LoginFailureSignals
+func NewLoginFailureSignals(clock system.ClockPort, window time.Duration, scorePerFailure int) *LoginFailureSignals {
+	return &LoginFailureSignals{clock: clock, window: window, scorePerFailure: scorePerFailure, failures: map[string][]time.Time{}}
+}
+
+// Handle records LoginFailed events. Other events are ignored.
+func (lf *LoginFailureSignals) Handle(_ context.Context, event events.Event) error {
+	failed, ok := event.(events.LoginFailed)
+	if !ok {
+		return nil
+	}
+	username := domain.NormalizeUsername(failed.Username).String()
+
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+	if len(lf.failures) >= maxTrackedFailureUsers {
+		lf.forgetExpired()
+	}
+	lf.failures[username] = append(lf.recent(username), failed.At)
+	return nil
+}
+
+// Signals raises recent_failures if logins of the user failed inside the window.
+func (lf *LoginFailureSignals) Signals(_ context.Context, attempt domain.LoginAttempt) ([]domain.RiskSignal, error) {
+	lf.mu.Lock()
+	count := len(lf.recent(attempt.User.Username.String()))
+	lf.mu.Unlock()
+	if count == 0 {
+		return nil, nil
+	}
+	return []domain.RiskSignal{{
+		Name:   domain.RiskSignalRecentFailures,
+		Score:  count * lf.scorePerFailure,
+		Detail: fmt.Sprintf("%d failed logins in the last %s", count, lf.window),
+	}}, nil
+}
+
+// recent returns the failures of the user inside the window. The caller must hold mu.
+func (lf *LoginFailureSignals) recent(username string) []time.Time {
+	since := lf.clock.Now().Add(-lf.window)
+	failures := lf.failures[username]
+	for len(failures) > 0 && failures[0].Before(since) {
+		failures = failures[1:]
+	}
+	return failures
+}
+
+// forgetExpired drops the users without failures inside the window. The caller must hold mu.
+func (lf *LoginFailureSignals) forgetExpired() {
+	for username := range lf.failures {
+		if recent := lf.recent(username); len(recent) > 0 {
+			lf.failures[username] = recent
+		} else {
+			delete(lf.failures, username)
+		}
+	}
+}
+
+// TimeOfDaySignals raises the unusual_time signal for logins during the night or another period in
+// which the users are not expected to work.
+// It implements the RiskSignalProviderPort interface from the security ports package.
+type TimeOfDaySignals struct {
+	fromHour int
+	toHour   int
+	location *time.Location
+	score    int
+}
+
+// NewTimeOfDaySignals creates a new instance of TimeOfDaySignals.
+//
+// Parameters:
+//   - fromHour: The hour the unusual period starts, between 0 and 23
+//   - toHour: The hour the unusual period ends (exclusive); it may be before fromHour to span midnight
+//   - location: The time zone the hours are read in
+//   - score: The score of a login in the unusual period
+//
+// Returns:
+//   - *TimeOfDaySignals: A pointer to the newly created TimeOfDaySignals
+func NewTimeOfDaySignals(fromHour int, toHour int, location *time.Location, score int) *TimeOfDaySignals {
+	return &TimeOfDaySignals{fromHour, toHour, location, score}
+}
+
+// Signals raises unusual_time if the attempt is made in the unusual period.
+func (td *TimeOfDaySignals) Signals(_ context.Context, attempt domain.LoginAttempt) ([]domain.RiskSignal, error) {
+	hour := attempt.At.In(td.location).Hour()
+	unusual := hour >= td.fromHour && hour < td.toHour
+	if td.toHour < td.fromHour {
+		unusual = hour >= td.fromHour || hour < td.toHour
+	}
+	if !unusual {
+		return nil, nil
+	}
+	return []domain.RiskSignal{{
+		Name:   domain.RiskSignalUnusualTime,
+		Score:  td.score,
+		Detail: "login at " + attempt.At.In(td.location).Format("15:04 MST"),
+	}}, nil
+}
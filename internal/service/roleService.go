@@ -0,0 +1,228 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+)
+
+// RoleService handles the business logic for the role registry.
+// It implements the ManageRolesPort and RoleRegistryPort interfaces from the usecases package.
+//
+// The role definitions are kept in memory, so authorization decisions do not hit the database.
+// The built-in roles always exist with their default permissions unless a stored definition
+// overrides them. Changes made through this instance apply immediately; changes made by other
+// instances apply once RefreshEvery has picked them up.
+type RoleService struct {
+	rolePersistence persistence.RolePersistencePort
+	clock           system.ClockPort
+	mu              sync.Mutex
+	definitions     atomic.Pointer[[]domain.RoleDefinition]
+	permissions     atomic.Pointer[domain.RolePermissions]
+}
+
+// NewRoleService creates a new instance of RoleService that knows the built-in roles only,
+// until Refresh loads the stored definitions.
+//
+// Parameters:
+//   - rolePersistence: An implementation of RolePersistencePort for storing role definitions
+//   - clock: An implementation of ClockPort for reading the current time
+//
+// Returns:
+//   - *RoleService: A pointer to the newly created RoleService
+func NewRoleService(rolePersistence persistence.RolePersistencePort, clock system.ClockPort) *RoleService {
+	rs := &RoleService{rolePersistence: rolePersistence, clock: clock}
+	rs.publish(nil)
+	return rs
+}
+
+// Refresh reloads the role definitions from the persistence layer.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//
+// Returns:
+//   - error: A wrapped persistence error; the previous definitions stay in effect
+func (rs *RoleService) Refresh(ctx context.Context) error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return rs.refresh(ctx)
+}
+
+// RefreshEvery reloads the role definitions periodically until the context is cancelled.
+// Failed reloads are logged and keep the previous definitions in effect.
+//
+// Parameters:
+//   - ctx: A context.Context that stops the refreshing
+//   - interval: The time between two reloads
+func (rs *RoleService) RefreshEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := rs.Refresh(ctx); err != nil {
+				logger.ErrorContext(ctx, "Error refreshing role definitions", "error", err)
+			}
+		}
+	}
+}
+
+// RolePermissions returns the permissions granted by every known role. The result must not be modified.
+//
+// Returns:
+//   - domain.RolePermissions: The current role registry
+func (rs *RoleService) RolePermissions() domain.RolePermissions {
+	return *rs.permissions.Load()
+}
+
+// ListRoles returns all role definitions sorted by name.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//
+// Returns:
+//   - []domain.RoleDefinition: The role definitions
+//   - error: A wrapped persistence error
+func (rs *RoleService) ListRoles(ctx context.Context) ([]domain.RoleDefinition, error) {
+	if err := rs.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return *rs.definitions.Load(), nil
+}
+
+// SaveRole creates a role or replaces the permissions of an existing one.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - name: The name of the role
+//   - permissions: The permissions the role grants; duplicates are dropped
+//
+// Returns:
+//   - domain.RoleDefinition: The stored definition
+//   - error: errorx.ErrBuiltInRole for the ADMIN role, or a wrapped persistence error
+func (rs *RoleService) SaveRole(ctx context.Context, name domain.Role, permissions []domain.Permission) (domain.RoleDefinition, error) {
+	definition := domain.RoleDefinition{
+		Name:        name,
+		Permissions: uniquePermissions(permissions),
+		BuiltIn:     domain.DefaultRolePermissions.Exists(name),
+		UpdatedAt:   rs.clock.Now(),
+	}
+	if !definition.Editable() {
+		return domain.RoleDefinition{}, errorx.ErrBuiltInRole
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	if err := rs.rolePersistence.SaveRole(ctx, definition); err != nil {
+		return domain.RoleDefinition{}, fmt.Errorf("failed to save role: %w", err)
+	}
+	if err := rs.refresh(ctx); err != nil {
+		logger.ErrorContext(ctx, "Error refreshing role definitions after saving a role", "role", name, "error", err)
+	}
+	return definition, nil
+}
+
+// DeleteRole deletes a role. Users holding it keep the role name but are no longer granted anything by it.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - name: The name of the role
+//
+// Returns:
+//   - error: errorx.ErrBuiltInRole for built-in roles, errorx.ErrRoleNotFound, or a wrapped persistence error
+func (rs *RoleService) DeleteRole(ctx context.Context, name domain.Role) error {
+	if domain.DefaultRolePermissions.Exists(name) {
+		return errorx.ErrBuiltInRole
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	if err := rs.rolePersistence.DeleteRole(ctx, name); err != nil {
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+	if err := rs.refresh(ctx); err != nil {
+		logger.ErrorContext(ctx, "Error refreshing role definitions after deleting a role", "role", name, "error", err)
+	}
+	return nil
+}
+
+// refresh loads the stored definitions and publishes them. The caller must hold mu.
+func (rs *RoleService) refresh(ctx context.Context) error {
+	stored, err := rs.rolePersistence.FindRoles(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load roles: %w", err)
+	}
+	rs.publish(stored)
+	return nil
+}
+
+// publish merges the stored definitions into the built-in ones and makes the result visible.
+func (rs *RoleService) publish(stored []domain.RoleDefinition) {
+	byName := map[domain.Role]domain.RoleDefinition{}
+	for name, permissions := range domain.DefaultRolePermissions {
+		byName[name] = domain.RoleDefinition{Name: name, Permissions: permissions, BuiltIn: true}
+	}
+	for _, definition := range stored {
+		if existing, ok := byName[definition.Name]; ok && !existing.Editable() {
+			continue
+		}
+		definition.BuiltIn = domain.DefaultRolePermissions.Exists(definition.Name)
+		byName[definition.Name] = definition
+	}
+
+	definitions := make([]domain.RoleDefinition, 0, len(byName))
+	permissions := domain.RolePermissions{}
+	for name, definition := range byName {
+		definitions = append(definitions, definition)
+		permissions[name] = definition.Permissions
+	}
+	sort.Slice(definitions, func(i, j int) bool { return definitions[i].Name < definitions[j].Name })
+
+	rs.definitions.Store(&definitions)
+	rs.permissions.Store(&permissions)
+}
+
+// uniquePermissions returns the permissions sorted and without duplicates.
+func uniquePermissions(permissions []domain.Permission) []domain.Permission {
+	seen := map[domain.Permission]bool{}
+	unique := []domain.Permission{}
+	for _, permission := range permissions {
+		if !seen[permission] {
+			seen[permission] = true
+			unique = append(unique, permission)
+		}
+	}
+	sort.Slice(unique, func(i, j int) bool { return unique[i] < unique[j] })
+	return unique
+}
+
+// roleNames converts roles into the plain names carried by domain events.
+func roleNames(roles []domain.Role) []string {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, string(role))
+	}
+	return names
+}
+
+// permissionNames converts permissions into the plain names carried by access tokens.
+func permissionNames(permissions []domain.Permission) []string {
+	names := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		names = append(names, string(permission))
+	}
+	return names
+}
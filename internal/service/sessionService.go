@@ -0,0 +1,198 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"fmt"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/ports/messaging"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+)
+
+// SessionService handles the business logic for the sessions started at login.
+// It implements the TrackSessionPort, EndSessionPort, UserSessionsPort and AdminSessionsPort
+// interfaces from the usecases package.
+type SessionService struct {
+	sessionPersistence   persistence.SessionPersistencePort
+	userPersistence      persistence.UserPersistencePort
+	userAdminPersistence persistence.UserAdminPersistencePort
+	eventDispatcher      messaging.EventDispatcherPort
+	clock                system.ClockPort
+}
+
+// NewSessionService creates a new instance of SessionService.
+//
+// Parameters:
+//   - sessionPersistence: An implementation of SessionPersistencePort for storing sessions
+//   - userPersistence: An implementation of UserPersistencePort for resolving signed-in users
+//   - userAdminPersistence: An implementation of UserAdminPersistencePort for resolving users by id
+//   - eventDispatcher: An implementation of EventDispatcherPort for publishing revocations
+//   - clock: An implementation of ClockPort for reading the current time
+//
+// Returns:
+//   - *SessionService: A pointer to the newly created SessionService
+func NewSessionService(sessionPersistence persistence.SessionPersistencePort, userPersistence persistence.UserPersistencePort, userAdminPersistence persistence.UserAdminPersistencePort, eventDispatcher messaging.EventDispatcherPort, clock system.ClockPort) *SessionService {
+	return &SessionService{sessionPersistence, userPersistence, userAdminPersistence, eventDispatcher, clock}
+}
+
+// TouchSession checks that a session is still active and records its activity.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The id of the session
+//
+// Returns:
+//   - error: errorx.ErrSessionNotFound or errorx.ErrSessionNotActive if the session cannot be used anymore,
+//     or a wrapped persistence error
+func (ss *SessionService) TouchSession(ctx context.Context, id string) error {
+	session, err := ss.sessionPersistence.FindSession(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	changed, err := session.Touch(ss.clock.Now())
+	if err != nil || !changed {
+		return err
+	}
+	if err := ss.sessionPersistence.SaveSession(ctx, session); err != nil {
+		// not being able to track activity must not end the session
+		logger.WarnContext(ctx, "Error recording session activity", "session_id", id, "error", err)
+	}
+	return nil
+}
+
+// EndSession revokes a session the user logs out of.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The id of the session
+//
+// Returns:
+//   - error: errorx.ErrSessionNotFound or errorx.ErrSessionNotActive if there is no active session to end,
+//     or a wrapped persistence error
+func (ss *SessionService) EndSession(ctx context.Context, id string) error {
+	session, err := ss.sessionPersistence.FindSession(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := session.Revoke(domain.SessionRevokedLogout, ss.clock.Now()); err != nil {
+		return err
+	}
+	if err := ss.sessionPersistence.SaveSession(ctx, session); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
+
+// ListMySessions returns the active sessions of a signed-in user, the most recent first, with the
+// device, location and last activity of each.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - username: The username of the user
+//
+// Returns:
+//   - []domain.Session: The active sessions
+//   - error: errorx.ErrUserNotFound, or a wrapped persistence error
+func (ss *SessionService) ListMySessions(ctx context.Context, username string) ([]domain.Session, error) {
+	user, err := ss.userPersistence.FindUser(ctx, domain.NormalizeUsername(username))
+	if err != nil {
+		return nil, fmt.Errorf("error finding user: %w", err)
+	}
+	return ss.activeSessions(ctx, user)
+}
+
+// RevokeSession ends a session of a signed-in user, e.g. one left open on a lost device. Sessions
+// of other users are reported as not found.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - username: The username of the user
+//   - id: The id of the session
+//
+// Returns:
+//   - error: errorx.ErrUserNotFound, errorx.ErrSessionNotFound, errorx.ErrSessionNotActive if the
+//     session has already ended, or a wrapped persistence error
+func (ss *SessionService) RevokeSession(ctx context.Context, username string, id string) error {
+	user, err := ss.userPersistence.FindUser(ctx, domain.NormalizeUsername(username))
+	if err != nil {
+		return fmt.Errorf("error finding user: %w", err)
+	}
+	return ss.revoke(ctx, user, id, domain.SessionRevokedByUser)
+}
+
+// ListUserSessions returns the active sessions of any user for support staff, the most recent first.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - userID: The id of the user
+//
+// Returns:
+//   - []domain.Session: The active sessions
+//   - error: errorx.ErrUserNotFound, or a wrapped persistence error
+func (ss *SessionService) ListUserSessions(ctx context.Context, userID string) ([]domain.Session, error) {
+	user, err := ss.userAdminPersistence.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error finding user: %w", err)
+	}
+	return ss.activeSessions(ctx, user)
+}
+
+// RevokeUserSession ends a session of any user on behalf of support staff.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - userID: The id of the user
+//   - id: The id of the session
+//
+// Returns:
+//   - error: errorx.ErrUserNotFound, errorx.ErrSessionNotFound, errorx.ErrSessionNotActive if the
+//     session has already ended, or a wrapped persistence error
+func (ss *SessionService) RevokeUserSession(ctx context.Context, userID string, id string) error {
+	user, err := ss.userAdminPersistence.FindUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("error finding user: %w", err)
+	}
+	return ss.revoke(ctx, user, id, domain.SessionRevokedByAdmin)
+}
+
+// activeSessions returns the sessions of a user that are neither revoked nor expired.
+func (ss *SessionService) activeSessions(ctx context.Context, user domain.User) ([]domain.Session, error) {
+	sessions, err := ss.sessionPersistence.FindSessionsByUser(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find sessions: %w", err)
+	}
+	now := ss.clock.Now()
+	active := make([]domain.Session, 0, len(sessions))
+	for _, session := range sessions {
+		if session.IsActive(now) {
+			active = append(active, session)
+		}
+	}
+	return active, nil
+}
+
+// revoke ends a session of a user and emits events.SessionRevoked.
+func (ss *SessionService) revoke(ctx context.Context, user domain.User, id string, reason domain.SessionRevocationReason) error {
+	session, err := ss.sessionPersistence.FindSession(ctx, id)
+	if err != nil {
+		return err
+	}
+	if session.UserID != user.ID {
+		return errorx.ErrSessionNotFound
+	}
+	now := ss.clock.Now()
+	if err := session.Revoke(reason, now); err != nil {
+		return err
+	}
+	if err := ss.sessionPersistence.SaveSession(ctx, session); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+
+	ss.eventDispatcher.Dispatch(ctx, events.SessionRevoked{Username: user.Username.String(), SessionID: session.ID, Reason: string(reason), At: now})
+	return nil
+}
@@ -0,0 +1,208 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+	"user-auth-hexagonal-architecture/internal/tenant"
+)
+
+// TenantService handles the business logic for tenants.
+// It implements the ManageTenantsPort and TenantRegistryPort interfaces from the usecases package.
+//
+// The tenants are kept in memory, so resolving the tenant of a request does not hit the database.
+// The default tenant always exists; a stored definition may change its settings. Changes made
+// through this instance apply immediately; changes made by other instances apply once
+// RefreshEvery has picked them up.
+type TenantService struct {
+	tenantPersistence persistence.TenantPersistencePort
+	clock             system.ClockPort
+	mu                sync.Mutex
+	tenants           atomic.Pointer[map[string]domain.Tenant]
+}
+
+// NewTenantService creates a new instance of TenantService that knows the default tenant only,
+// until Refresh loads the stored tenants.
+//
+// Parameters:
+//   - tenantPersistence: An implementation of TenantPersistencePort for storing tenants
+//   - clock: An implementation of ClockPort for reading the current time
+//
+// Returns:
+//   - *TenantService: A pointer to the newly created TenantService
+func NewTenantService(tenantPersistence persistence.TenantPersistencePort, clock system.ClockPort) *TenantService {
+	ts := &TenantService{tenantPersistence: tenantPersistence, clock: clock}
+	ts.publish(nil)
+	return ts
+}
+
+// Refresh reloads the tenants from the persistence layer.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//
+// Returns:
+//   - error: A wrapped persistence error; the previous tenants stay in effect
+func (ts *TenantService) Refresh(ctx context.Context) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return ts.refresh(ctx)
+}
+
+// RefreshEvery reloads the tenants periodically until the context is cancelled.
+// Failed reloads are logged and keep the previous tenants in effect.
+//
+// Parameters:
+//   - ctx: A context.Context that stops the refreshing
+//   - interval: The time between two reloads
+func (ts *TenantService) RefreshEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := ts.Refresh(ctx); err != nil {
+				logger.ErrorContext(ctx, "Error refreshing tenants", "error", err)
+			}
+		}
+	}
+}
+
+// ResolveTenant returns the tenant the request carried by ctx is made for.
+//
+// Parameters:
+//   - ctx: The context of the request; requests without a tenant id belong to the default tenant
+//
+// Returns:
+//   - domain.Tenant: The tenant
+//   - error: errorx.ErrTenantNotFound if the named tenant does not exist
+func (ts *TenantService) ResolveTenant(ctx context.Context) (domain.Tenant, error) {
+	id := tenant.FromContext(ctx)
+	if id == "" {
+		id = domain.DefaultTenantID
+	}
+	resolved, ok := (*ts.tenants.Load())[id]
+	if !ok {
+		return domain.Tenant{}, errorx.ErrTenantNotFound
+	}
+	return resolved, nil
+}
+
+// ListTenants returns all tenants sorted by id.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//
+// Returns:
+//   - []domain.Tenant: The tenants, including the default tenant
+//   - error: A wrapped persistence error
+func (ts *TenantService) ListTenants(ctx context.Context) ([]domain.Tenant, error) {
+	if err := ts.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	tenants := make([]domain.Tenant, 0, len(*ts.tenants.Load()))
+	for _, t := range *ts.tenants.Load() {
+		tenants = append(tenants, t)
+	}
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].ID < tenants[j].ID })
+	return tenants, nil
+}
+
+// GetTenant returns a single tenant.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The id of the tenant
+//
+// Returns:
+//   - domain.Tenant: The tenant
+//   - error: errorx.ErrTenantNotFound, or a wrapped persistence error
+func (ts *TenantService) GetTenant(ctx context.Context, id string) (domain.Tenant, error) {
+	if err := ts.Refresh(ctx); err != nil {
+		return domain.Tenant{}, err
+	}
+	t, ok := (*ts.tenants.Load())[id]
+	if !ok {
+		return domain.Tenant{}, errorx.ErrTenantNotFound
+	}
+	return t, nil
+}
+
+// SaveTenant creates a tenant or replaces the name and settings of an existing one.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - t: The tenant to store
+//
+// Returns:
+//   - domain.Tenant: The stored tenant
+//   - error: errorx.ErrInvalidTenant for settings out of bounds, or a wrapped persistence error
+func (ts *TenantService) SaveTenant(ctx context.Context, t domain.Tenant) (domain.Tenant, error) {
+	if err := t.Settings.Validate(); err != nil {
+		return domain.Tenant{}, err
+	}
+	t.UpdatedAt = ts.clock.Now()
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if err := ts.tenantPersistence.SaveTenant(ctx, t); err != nil {
+		return domain.Tenant{}, fmt.Errorf("failed to save tenant: %w", err)
+	}
+	if err := ts.refresh(ctx); err != nil {
+		logger.ErrorContext(ctx, "Error refreshing tenants after saving a tenant", "tenant_id", t.ID, "error", err)
+	}
+	return t, nil
+}
+
+// DeleteTenant deletes a tenant. Its accounts are kept but can no longer log in, as their tenant
+// cannot be resolved anymore. Deleting the default tenant only resets its settings.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The id of the tenant
+//
+// Returns:
+//   - error: errorx.ErrTenantNotFound, or a wrapped persistence error
+func (ts *TenantService) DeleteTenant(ctx context.Context, id string) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if err := ts.tenantPersistence.DeleteTenant(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete tenant: %w", err)
+	}
+	if err := ts.refresh(ctx); err != nil {
+		logger.ErrorContext(ctx, "Error refreshing tenants after deleting a tenant", "tenant_id", id, "error", err)
+	}
+	return nil
+}
+
+// refresh loads the stored tenants and publishes them. The caller must hold mu.
+func (ts *TenantService) refresh(ctx context.Context) error {
+	stored, err := ts.tenantPersistence.FindTenants(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load tenants: %w", err)
+	}
+	ts.publish(stored)
+	return nil
+}
+
+// publish adds the stored tenants to the default tenant and makes the result visible.
+func (ts *TenantService) publish(stored []domain.Tenant) {
+	byID := map[string]domain.Tenant{domain.DefaultTenantID: domain.DefaultTenant()}
+	for _, t := range stored {
+		byID[t.ID] = t
+	}
+	ts.tenants.Store(&byID)
+}
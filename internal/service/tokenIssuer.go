@@ -0,0 +1,148 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"sync/atomic"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/ports/security"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// passwordChangePurpose is the "purpose" claim of password change tokens. Access tokens have no
+// purpose claim, and tokens with one are rejected by the TokenVerificationService.
+const passwordChangePurpose = "password_change"
+
+// passwordChangeTokenTTL is the lifetime of password change tokens, long enough to choose a new password.
+const passwordChangeTokenTTL = 10 * time.Minute
+
+// TokenSettings holds the settings of issued tokens that can be changed while the service runs.
+// Access token lifetimes are configured per tenant instead.
+type TokenSettings struct {
+	refreshTokenTTL atomic.Int64
+}
+
+// NewTokenSettings creates a new instance of TokenSettings.
+//
+// Parameters:
+//   - refreshTokenTTL: The lifetime of refresh tokens, zero disables refresh tokens
+//
+// Returns:
+//   - *TokenSettings: A pointer to the newly created TokenSettings
+func NewTokenSettings(refreshTokenTTL time.Duration) *TokenSettings {
+	ts := &TokenSettings{}
+	ts.SetRefreshTokenTTL(refreshTokenTTL)
+	return ts
+}
+
+// RefreshTokenTTL returns the lifetime of refresh tokens, zero if refresh tokens are disabled.
+func (ts *TokenSettings) RefreshTokenTTL() time.Duration {
+	return time.Duration(ts.refreshTokenTTL.Load())
+}
+
+// SetRefreshTokenTTL changes the lifetime of the refresh tokens issued from now on. Refresh tokens
+// already issued keep their expiry.
+func (ts *TokenSettings) SetRefreshTokenTTL(ttl time.Duration) {
+	ts.refreshTokenTTL.Store(int64(ttl))
+}
+
+// tokenIssuer issues the access and refresh tokens of a session. It is shared by the login and the
+// refresh use cases, so both put the same claims into the tokens they issue.
+type tokenIssuer struct {
+	roleRegistry usecases.RoleRegistryPort
+	random       system.RandomSourcePort
+	keys         security.SigningKeyPort
+	settings     *TokenSettings
+}
+
+// signAccessToken creates the signed JWT access token of a session.
+//
+// The token carries the username, the effective roles, the group names, the permissions of the roles,
+// the tenant, the session id, the token version of the user and the expiry.
+func (ti tokenIssuer) signAccessToken(user domain.User, roles []domain.Role, groups []domain.Group, session domain.Session, expiresAt time.Time) (string, error) {
+	token := jwt.New(jwt.SigningMethodHS256)
+	claims := token.Claims.(jwt.MapClaims)
+	claims["username"] = user.Username.String()
+	claims["roles"] = roleNames(roles)
+	claims["groups"] = groupNames(groups)
+	claims["permissions"] = permissionNames(ti.roleRegistry.RolePermissions().Flatten(session.TenantID, roles))
+	claims["tenant"] = session.TenantID
+	claims["sid"] = session.ID
+	claims["tv"] = user.TokenVersion
+	claims["exp"] = expiresAt.Unix()
+
+	signedString, err := token.SignedString(ti.keys.SigningKey())
+	if err != nil {
+		return "", fmt.Errorf("error while creating jwt: %w", err)
+	}
+	return signedString, nil
+}
+
+// bindRefreshToken issues a new refresh token for a session and extends the session to its expiry.
+// The session has to be stored afterwards. It returns an empty token if refresh tokens are disabled.
+func (ti tokenIssuer) bindRefreshToken(session *domain.Session, now time.Time) (string, error) {
+	ttl := ti.settings.RefreshTokenTTL()
+	if ttl <= 0 {
+		return "", nil
+	}
+
+	secret := make([]byte, 32)
+	if _, err := ti.random.Read(secret); err != nil {
+		return "", fmt.Errorf("error generating refresh token: %w", err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(secret)
+	session.BindRefreshToken(encoded, now, now.Add(ttl))
+	return domain.NewRefreshToken(session.ID, encoded), nil
+}
+
+// signPasswordChangeToken creates the signed JWT that entitles a user with a temporary password to
+// change it. It carries the user id, the tenant, the purpose and the expiry, but no roles.
+func (ti tokenIssuer) signPasswordChangeToken(user domain.User, expiresAt time.Time) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":     user.ID,
+		"tenant":  user.TenantID,
+		"purpose": passwordChangePurpose,
+		"exp":     expiresAt.Unix(),
+	})
+	signedString, err := token.SignedString(ti.keys.SigningKey())
+	if err != nil {
+		return "", fmt.Errorf("error while creating jwt: %w", err)
+	}
+	return signedString, nil
+}
+
+// parsePasswordChangeToken verifies a token created by signPasswordChangeToken and returns the id
+// and tenant of its user, or errorx.ErrInvalidPasswordChangeToken.
+func (ti tokenIssuer) parsePasswordChangeToken(token string) (string, string, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, verificationKeys(ti.keys), jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return "", "", errorx.ErrInvalidPasswordChangeToken.Detailf("%v", err)
+	}
+	if purpose, _ := claims["purpose"].(string); purpose != passwordChangePurpose {
+		return "", "", errorx.ErrInvalidPasswordChangeToken.Detailf("not a password change token")
+	}
+	userID, _ := claims["sub"].(string)
+	if userID == "" {
+		return "", "", errorx.ErrInvalidPasswordChangeToken.Detailf("missing subject")
+	}
+	return userID, tenantClaim(claims), nil
+}
+
+// verificationKeys returns a jwt.Keyfunc accepting tokens signed with any of the current verification keys,
+// so tokens signed before a key rotation stay valid for the grace period.
+func verificationKeys(keys security.SigningKeyPort) jwt.Keyfunc {
+	return func(*jwt.Token) (interface{}, error) {
+		set := jwt.VerificationKeySet{}
+		for _, key := range keys.VerificationKeys() {
+			set.Keys = append(set.Keys, key)
+		}
+		return set, nil
+	}
+}
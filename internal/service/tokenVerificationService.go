@@ -0,0 +1,136 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/security"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+)
+
+// TokenVerificationService handles the business logic for verifying the access tokens issued at login.
+// It implements the VerifyTokenPort interface from the usecases package and is shared by the HTTP
+// and gRPC authentication and the token introspection endpoints, so none of them accepts the token
+// of a session that was logged out of or revoked.
+type TokenVerificationService struct {
+	signingKeys        security.SigningKeyPort
+	sessionPersistence persistence.SessionPersistencePort
+	clock              system.ClockPort
+}
+
+// NewTokenVerificationService creates a new instance of TokenVerificationService.
+//
+// Parameters:
+//   - signingKeys: An implementation of SigningKeyPort providing the keys the access tokens are verified with
+//   - sessionPersistence: An implementation of SessionPersistencePort for checking the session of a token
+//   - clock: An implementation of ClockPort for reading the current time
+//
+// Returns:
+//   - *TokenVerificationService: A pointer to the newly created TokenVerificationService
+func NewTokenVerificationService(signingKeys security.SigningKeyPort, sessionPersistence persistence.SessionPersistencePort, clock system.ClockPort) *TokenVerificationService {
+	return &TokenVerificationService{signingKeys, sessionPersistence, clock}
+}
+
+// VerifyToken verifies a signed access token and converts its claims into a Principal.
+//
+// Tokens issued before users could hold several roles carry a single role, and tokens issued
+// before tenants existed belong to the default tenant. Tokens bound to a session are only valid
+// while the session is active and as long as the roles of their user have not changed since they
+// were issued, see domain.Session.TokenVersion; if the sessions cannot be read, the token is
+// accepted, so an outage of the session store does not log everybody out.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - token: The JWT
+//
+// Returns:
+//   - domain.Principal: The subject of the token
+//   - error: errorx.ErrInvalidToken if the token is malformed, not signed with a current key, expired, has no subject,
+//     is limited to a purpose such as a password change, its session was revoked or has expired, or
+//     it was issued before a role change
+func (ts *TokenVerificationService) VerifyToken(ctx context.Context, token string) (domain.Principal, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, verificationKeys(ts.signingKeys), jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return domain.Principal{}, errorx.ErrInvalidToken.Detailf("%v", err)
+	}
+
+	if purpose, _ := claims["purpose"].(string); purpose != "" {
+		// tokens issued for a single purpose, e.g. changing a temporary password, are no access tokens
+		return domain.Principal{}, errorx.ErrInvalidToken.Detailf("token is limited to %s", purpose)
+	}
+	subject, _ := claims["username"].(string)
+	if subject == "" {
+		return domain.Principal{}, errorx.ErrInvalidToken.Detailf("missing subject")
+	}
+
+	principal := domain.Principal{Subject: subject, Tenant: tenantClaim(claims)}
+	principal.SessionID, _ = claims["sid"].(string)
+	if version, ok := claims["tv"].(float64); ok {
+		principal.TokenVersion = int(version)
+	}
+	for _, role := range stringClaims(claims, "roles") {
+		principal.Roles = append(principal.Roles, domain.Role(role))
+	}
+	if role, ok := claims["role"].(string); ok && role != "" && len(principal.Roles) == 0 {
+		principal.Roles = []domain.Role{domain.Role(role)}
+	}
+	for _, permission := range stringClaims(claims, "permissions") {
+		principal.Permissions = append(principal.Permissions, domain.Permission(permission))
+	}
+	principal.Groups = stringClaims(claims, "groups")
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		principal.ExpiresAt = exp.Time
+	}
+	if err := ts.checkSession(ctx, principal); err != nil {
+		return domain.Principal{}, err
+	}
+	return principal, nil
+}
+
+// checkSession rejects tokens whose session is unknown, revoked or expired, and tokens issued
+// before the roles of their user changed.
+func (ts *TokenVerificationService) checkSession(ctx context.Context, principal domain.Principal) error {
+	id := principal.SessionID
+	if id == "" {
+		return nil
+	}
+	session, err := ts.sessionPersistence.FindSession(ctx, id)
+	switch {
+	case errors.Is(err, errorx.ErrSessionNotFound):
+		return errorx.ErrInvalidToken.Detailf("session not found")
+	case err != nil:
+		logger.ErrorContext(ctx, "Error checking session of token", "session_id", id, "error", err)
+		return nil
+	case !session.IsActive(ts.clock.Now()):
+		return errorx.ErrInvalidToken.Detailf("session is no longer active")
+	case !session.AcceptsTokenVersion(principal.TokenVersion):
+		return errorx.ErrInvalidToken.Detailf("token was issued before a role change")
+	}
+	return nil
+}
+
+// tenantClaim returns the tenant the token was issued for, the default tenant for tokens without one.
+func tenantClaim(claims jwt.MapClaims) string {
+	if id, ok := claims["tenant"].(string); ok && id != "" {
+		return id
+	}
+	return domain.DefaultTenantID
+}
+
+// stringClaims returns the string entries of an array claim.
+func stringClaims(claims jwt.MapClaims, name string) []string {
+	values, _ := claims[name].([]interface{})
+	strs := make([]string, 0, len(values))
+	for _, value := range values {
+		if str, ok := value.(string); ok && str != "" {
+			strs = append(strs, str)
+		}
+	}
+	return strs
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+)
+
+// fixedClock is a ClockPort returning a fixed time.
+type fixedClock struct{ now time.Time }
+
+func (c fixedClock) Now() time.Time { return c.now }
+
+// staticKeys is a SigningKeyPort with a single key.
+type staticKeys []byte
+
+func (k staticKeys) SigningKey() []byte         { return k }
+func (k staticKeys) VerificationKeys() [][]byte { return [][]byte{k} }
+
+// staticRoles is a RoleRegistryPort with fixed role permissions.
+type staticRoles domain.RolePermissions
+
+func (r staticRoles) RolePermissions() domain.RolePermissions { return domain.RolePermissions(r) }
+
+// memorySessions is a SessionPersistencePort keeping the sessions in a map.
+type memorySessions map[string]domain.Session
+
+func (m memorySessions) SaveSession(_ context.Context, session domain.Session) error {
+	m[session.ID] = session
+	return nil
+}
+
+func (m memorySessions) SaveRefreshedSession(_ context.Context, session domain.Session, _ string) error {
+	m[session.ID] = session
+	return nil
+}
+
+func (m memorySessions) FindSession(_ context.Context, id string) (domain.Session, error) {
+	session, ok := m[id]
+	if !ok {
+		return domain.Session{}, errorx.ErrSessionNotFound
+	}
+	return session, nil
+}
+
+func (m memorySessions) FindSessionsByUser(_ context.Context, userID string) ([]domain.Session, error) {
+	var sessions []domain.Session
+	for _, session := range m {
+		if session.UserID == userID {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (m memorySessions) DeleteSessionsByUser(_ context.Context, userID string) (int64, error) {
+	var deleted int64
+	for id, session := range m {
+		if session.UserID == userID {
+			delete(m, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func TestVerifyTokenRejectsTokenOfRevokedSession(t *testing.T) {
+	ctx := context.Background()
+	// the JWT library checks the expiry against the current time
+	clock := fixedClock{time.Now()}
+	keys := staticKeys("0123456789abcdef0123456789abcdef")
+	sessions := memorySessions{}
+	issuer := tokenIssuer{roleRegistry: staticRoles{domain.RoleUser: nil}, keys: keys}
+	verifier := NewTokenVerificationService(keys, sessions, clock)
+
+	user := domain.User{ID: "user-1", TenantID: domain.DefaultTenantID, Username: domain.NormalizeUsername("alice"), Roles: []domain.Role{domain.RoleUser}}
+	expiresAt := clock.now.Add(time.Hour)
+	session := domain.NewSession("session-1", user, domain.Device{}, clock.now, expiresAt)
+	if err := sessions.SaveSession(ctx, session); err != nil {
+		t.Fatal(err)
+	}
+	token, err := issuer.signAccessToken(user, user.Roles, nil, session, expiresAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	principal, err := verifier.VerifyToken(ctx, token)
+	if err != nil {
+		t.Fatalf("token of an active session rejected: %v", err)
+	}
+	if principal.Subject != "alice" || principal.SessionID != "session-1" {
+		t.Fatalf("unexpected principal %+v", principal)
+	}
+
+	if err := NewSessionService(sessions, nil, nil, nil, clock).EndSession(ctx, session.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifier.VerifyToken(ctx, token); !errors.Is(err, errorx.ErrInvalidToken) {
+		t.Fatalf("token of a revoked session: got %v, want %v", err, errorx.ErrInvalidToken)
+	}
+
+	delete(sessions, session.ID)
+	if _, err := verifier.VerifyToken(ctx, token); !errors.Is(err, errorx.ErrInvalidToken) {
+		t.Fatalf("token of a deleted session: got %v, want %v", err, errorx.ErrInvalidToken)
+	}
+}
+
+func TestVerifyTokenRejectsTokenIssuedBeforeRoleChange(t *testing.T) {
+	ctx := context.Background()
+	clock := fixedClock{time.Now()}
+	keys := staticKeys("0123456789abcdef0123456789abcdef")
+	sessions := memorySessions{}
+	roles := staticRoles{domain.RoleUser: nil, domain.RoleAdmin: nil}
+	issuer := tokenIssuer{roleRegistry: roles, keys: keys}
+	verifier := NewTokenVerificationService(keys, sessions, clock)
+
+	user := domain.User{ID: "user-1", TenantID: domain.DefaultTenantID, Username: domain.NormalizeUsername("alice"), Roles: []domain.Role{domain.RoleUser}}
+	expiresAt := clock.now.Add(time.Hour)
+	session := domain.NewSession("session-1", user, domain.Device{}, clock.now, expiresAt)
+	if err := sessions.SaveSession(ctx, session); err != nil {
+		t.Fatal(err)
+	}
+	token, err := issuer.signAccessToken(user, user.Roles, nil, session, expiresAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := user.GrantRole(domain.RoleAdmin, domain.RolePermissions(roles)); err != nil {
+		t.Fatal(err)
+	}
+	if err := outdateAccessTokens(ctx, sessions, user); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifier.VerifyToken(ctx, token); !errors.Is(err, errorx.ErrInvalidToken) {
+		t.Fatalf("token issued before a role change: got %v, want %v", err, errorx.ErrInvalidToken)
+	}
+
+	refreshed, err := issuer.signAccessToken(user, user.Roles, nil, sessions[session.ID], expiresAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifier.VerifyToken(ctx, refreshed); err != nil {
+		t.Fatalf("token issued after a role change rejected: %v", err)
+	}
+}
@@ -0,0 +1,339 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/ports/messaging"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// maxUserPageSize caps the number of users returned by a single listing.
+const maxUserPageSize = 100
+
+// UserAdministrationService handles the business logic for administrative user management.
+// It implements the ListUsersPort, GetUserPort, SearchUsersPort, AssignRolePort, RenameUserPort and
+// ChangeUserStatusPort interfaces from the usecases package. Deletions are handled by the AccountDeletionService.
+//
+// Listings are served from the user overview read model, while changes go to the user store
+// and are propagated to the read model through domain events. Both stores only return the users
+// of the tenant of the request, so administrators cannot see or change the users of other tenants.
+type UserAdministrationService struct {
+	userAdminPersistence persistence.UserAdminPersistencePort
+	overviewPersistence  persistence.UserOverviewPersistencePort
+	sessionPersistence   persistence.SessionPersistencePort
+	eventDispatcher      messaging.EventDispatcherPort
+	roleRegistry         usecases.RoleRegistryPort
+	clock                system.ClockPort
+	usernamePolicy       domain.UsernamePolicy
+	canonicalizer        domain.Canonicalizer
+}
+
+// NewUserAdministrationService creates a new instance of UserAdministrationService.
+//
+// Parameters:
+//   - userAdminPersistence: An implementation of UserAdminPersistencePort for changing user data
+//   - overviewPersistence: An implementation of UserOverviewPersistencePort for listing users
+//   - sessionPersistence: An implementation of SessionPersistencePort for outdating access tokens after role changes
+//   - eventDispatcher: An implementation of EventDispatcherPort for emitting domain events
+//   - roleRegistry: An implementation of RoleRegistryPort for checking that assigned roles exist
+//   - clock: An implementation of ClockPort for reading the current time
+//   - usernamePolicy: The rules new usernames have to satisfy on rename
+//   - canonicalizer: The rules under which usernames count as duplicates
+//
+// Returns:
+//   - *UserAdministrationService: A pointer to the newly created UserAdministrationService
+func NewUserAdministrationService(userAdminPersistence persistence.UserAdminPersistencePort, overviewPersistence persistence.UserOverviewPersistencePort, sessionPersistence persistence.SessionPersistencePort, eventDispatcher messaging.EventDispatcherPort, roleRegistry usecases.RoleRegistryPort, clock system.ClockPort, usernamePolicy domain.UsernamePolicy, canonicalizer domain.Canonicalizer) *UserAdministrationService {
+	return &UserAdministrationService{userAdminPersistence, overviewPersistence, sessionPersistence, eventDispatcher, roleRegistry, clock, usernamePolicy, canonicalizer}
+}
+
+// ListUsers returns one page of users matching the filter.
+//
+// A missing or too large limit is replaced by maxUserPageSize.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - filter: The selection, sorting and paging to apply
+//
+// Returns:
+//   - usecases.UserPage: The requested page and the total number of matches
+//   - error: An error if the read model cannot be queried
+func (as *UserAdministrationService) ListUsers(ctx context.Context, filter domain.UserOverviewFilter) (usecases.UserPage, error) {
+	if filter.Limit <= 0 || filter.Limit > maxUserPageSize {
+		filter.Limit = maxUserPageSize
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	users, total, err := as.overviewPersistence.FindUserOverviews(ctx, filter)
+	if err != nil {
+		return usecases.UserPage{}, fmt.Errorf("failed to list users: %w", err)
+	}
+	return usecases.UserPage{Users: users, Total: total}, nil
+}
+
+// GetUser returns a single user without its credentials.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The id of the user
+//
+// Returns:
+//   - usecases.UserView: The user
+//   - error: errorx.ErrUserNotFound if the user does not exist, or a wrapped persistence error
+func (as *UserAdministrationService) GetUser(ctx context.Context, id string) (usecases.UserView, error) {
+	user, err := as.userAdminPersistence.FindUserByID(ctx, id)
+	if err != nil {
+		return usecases.UserView{}, fmt.Errorf("error finding user: %w", err)
+	}
+	return toUserView(user), nil
+}
+
+// SearchUsers returns one page of the users whose username or email contains the search term,
+// ignoring case, sorted by username.
+//
+// Unlike ListUsers, the search reads the user store, as the read model holds no email addresses.
+// A missing or too large limit is replaced by maxUserPageSize.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - term: The text to search for, the empty term matches every user
+//   - offset: The number of matches to skip
+//   - limit: The maximum number of users to return
+//
+// Returns:
+//   - usecases.UserViewPage: The requested page and the total number of matches
+//   - error: A wrapped persistence error
+func (as *UserAdministrationService) SearchUsers(ctx context.Context, term string, offset int64, limit int64) (usecases.UserViewPage, error) {
+	if limit <= 0 || limit > maxUserPageSize {
+		limit = maxUserPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	users, total, err := as.userAdminPersistence.SearchUsers(ctx, strings.TrimSpace(term), offset, limit)
+	if err != nil {
+		return usecases.UserViewPage{}, fmt.Errorf("failed to search users: %w", err)
+	}
+	page := usecases.UserViewPage{Users: make([]usecases.UserView, 0, len(users)), Total: total}
+	for _, user := range users {
+		page.Users = append(page.Users, toUserView(user))
+	}
+	return page, nil
+}
+
+// AssignRoles replaces the roles of a user. Assigning the roles the user already holds changes nothing.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The id of the user
+//   - roles: The roles to assign; at least one, each known to the role registry
+//
+// Returns:
+//   - error: errorx.ErrUnknownRole, errorx.ErrLastAdmin, errorx.ErrUserNotFound, or a wrapped persistence error
+func (as *UserAdministrationService) AssignRoles(ctx context.Context, id string, roles []domain.Role) error {
+	return as.changeRoles(ctx, id, func(user *domain.User, known domain.RolePermissions) (bool, error) {
+		return user.AssignRoles(roles, known)
+	})
+}
+
+// GrantRole adds a role to the roles of a user. Granting a role the user already holds changes nothing.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The id of the user
+//   - role: The role to grant, known to the role registry
+//
+// Returns:
+//   - error: errorx.ErrUnknownRole, errorx.ErrUserNotFound, or a wrapped persistence error
+func (as *UserAdministrationService) GrantRole(ctx context.Context, id string, role domain.Role) error {
+	return as.changeRoles(ctx, id, func(user *domain.User, known domain.RolePermissions) (bool, error) {
+		return user.GrantRole(role, known)
+	})
+}
+
+// RevokeRole removes a role from the roles of a user. Revoking a role the user does not hold changes nothing.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The id of the user
+//   - role: The role to revoke
+//
+// Returns:
+//   - error: errorx.ErrRoleRequired for the only role of the user, errorx.ErrLastAdmin, errorx.ErrUserNotFound,
+//     or a wrapped persistence error
+func (as *UserAdministrationService) RevokeRole(ctx context.Context, id string, role domain.Role) error {
+	return as.changeRoles(ctx, id, func(user *domain.User, known domain.RolePermissions) (bool, error) {
+		return user.RevokeRole(role, known)
+	})
+}
+
+// changeRoles applies a role change to a user, stores it together with the increased token version,
+// outdates the access tokens issued with the previous roles and emits events.UserRoleChanged. A change
+// that takes the ADMIN role from the last active administrator is rejected, so the system cannot lock
+// itself out of administration.
+func (as *UserAdministrationService) changeRoles(ctx context.Context, id string, change func(*domain.User, domain.RolePermissions) (bool, error)) error {
+	user, err := as.userAdminPersistence.FindUserByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error finding user: %w", err)
+	}
+	previous := slices.Clone(user.Roles)
+	changed, err := change(&user, as.roleRegistry.RolePermissions())
+	if err != nil || !changed {
+		return err
+	}
+	if slices.Contains(previous, domain.RoleAdmin) && !user.HasRole(domain.RoleAdmin) && user.Status == domain.StatusActive {
+		admins, err := as.userAdminPersistence.CountActiveUsersWithRole(ctx, domain.RoleAdmin)
+		if err != nil {
+			return fmt.Errorf("failed to count administrators: %w", err)
+		}
+		if admins <= 1 {
+			return errorx.ErrLastAdmin
+		}
+	}
+	if err := as.userAdminPersistence.UpdateUserRoles(ctx, id, user.Roles, user.TokenVersion); err != nil {
+		return fmt.Errorf("failed to assign roles: %w", err)
+	}
+	if err := outdateAccessTokens(ctx, as.sessionPersistence, user); err != nil {
+		// the roles are changed at this point, the tokens carrying the previous roles work until they expire
+		logger.ErrorContext(ctx, "Error outdating access tokens after role change", "username", user.Username.String(), "error", err)
+	}
+
+	as.eventDispatcher.Dispatch(ctx, events.UserRoleChanged{Username: user.Username.String(), Previous: roleNames(previous), Roles: roleNames(user.Roles), At: as.clock.Now()})
+	return nil
+}
+
+// RenameUser changes the username of a user. Access tokens issued under the previous username
+// stay valid until they expire, but no longer match the account. A username that only differs
+// from the one of another user in punctuation or look-alike characters counts as taken.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The id of the user
+//   - username: The new username, checked against the username policy
+//
+// Returns:
+//   - error: errorx.ErrInvalidUsername, errorx.ErrUsernameTaken, errorx.ErrUserNotFound, or a wrapped persistence error
+func (as *UserAdministrationService) RenameUser(ctx context.Context, id string, username string) error {
+	validUsername, err := as.usernamePolicy.Validate(username)
+	if err != nil {
+		return err
+	}
+	user, err := as.userAdminPersistence.FindUserByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error finding user: %w", err)
+	}
+	previous, changed := user.Rename(validUsername)
+	if !changed {
+		return nil
+	}
+	user.Canonicalize(as.canonicalizer)
+	if err := as.userAdminPersistence.UpdateUsername(ctx, id, user.Username, user.CanonicalUsername); err != nil {
+		return fmt.Errorf("failed to rename user: %w", err)
+	}
+
+	as.eventDispatcher.Dispatch(ctx, events.UserRenamed{UserID: user.ID, Username: previous.String(), NewUsername: user.Username.String(), At: as.clock.Now()})
+	return nil
+}
+
+// DisableUser prevents a user from logging in.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The id of the user
+//
+// Returns:
+//   - error: errorx.ErrInvalidStatusTransition for pending accounts, errorx.ErrUserNotFound, or a wrapped persistence error
+func (as *UserAdministrationService) DisableUser(ctx context.Context, id string) error {
+	return as.changeStatus(ctx, id, (*domain.User).Disable)
+}
+
+// EnableUser allows a previously disabled or locked user to log in again.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The id of the user
+//
+// Returns:
+//   - error: errorx.ErrUserNotFound, or a wrapped persistence error
+func (as *UserAdministrationService) EnableUser(ctx context.Context, id string) error {
+	return as.changeStatus(ctx, id, (*domain.User).Activate)
+}
+
+// changeStatus applies a status transition of the user aggregate, stores the new status
+// and emits a UserStatusChanged event. Transitions that change nothing are not stored,
+// transitions the account lifecycle does not allow are rejected.
+func (as *UserAdministrationService) changeStatus(ctx context.Context, id string, transitionTo func(*domain.User, time.Time) (domain.StatusTransition, error)) error {
+	user, err := as.userAdminPersistence.FindUserByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error finding user: %w", err)
+	}
+	transition, err := transitionTo(&user, as.clock.Now())
+	if err != nil || !transition.Changed() {
+		return err
+	}
+
+	if err := as.userAdminPersistence.UpdateUserStatus(ctx, id, user.Status); err != nil {
+		return fmt.Errorf("failed to change user status: %w", err)
+	}
+
+	as.eventDispatcher.Dispatch(ctx, statusChanged(user, transition))
+	return nil
+}
+
+// outdateAccessTokens raises the token version of the active sessions of a user to the one of the
+// user, so the access tokens issued before its roles changed are rejected. The sessions continue
+// with the tokens of their next refresh, which carry the new roles.
+func outdateAccessTokens(ctx context.Context, sessionPersistence persistence.SessionPersistencePort, user domain.User) error {
+	sessions, err := sessionPersistence.FindSessionsByUser(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("error finding sessions: %w", err)
+	}
+	for _, session := range sessions {
+		if session.IsRevoked() || !session.RaiseTokenVersion(user.TokenVersion) {
+			continue
+		}
+		if err := sessionPersistence.SaveSession(ctx, session); err != nil {
+			return fmt.Errorf("failed to save session: %w", err)
+		}
+	}
+	return nil
+}
+
+// toUserView converts a user into the view handed out to administrators.
+func toUserView(user domain.User) usecases.UserView {
+	return usecases.UserView{
+		ID:          user.ID,
+		TenantID:    user.TenantID,
+		Username:    user.Username.String(),
+		Email:       user.Email.String(),
+		Status:      user.Status,
+		Roles:       user.Roles,
+		MfaEnabled:  user.MfaEnabled,
+		CreatedAt:   user.CreatedAt,
+		LastLoginAt: user.LastLoginAt,
+		LockReason:  user.LockReason,
+		LockedUntil: user.LockedUntil,
+	}
+}
+
+// statusChanged creates the event announcing a status transition of the user.
+func statusChanged(user domain.User, transition domain.StatusTransition) events.UserStatusChanged {
+	return events.UserStatusChanged{
+		Username: user.Username.String(),
+		From:     string(transition.From),
+		Status:   string(transition.To),
+		At:       transition.At,
+	}
+}
@@ -0,0 +1,88 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"fmt"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+)
+
+// UserOverviewProjection keeps the user overview read model in sync with the domain events.
+// It implements the EventHandler interface from the messaging ports package.
+type UserOverviewProjection struct {
+	overviewPersistence persistence.UserOverviewPersistencePort
+}
+
+// NewUserOverviewProjection creates a new instance of UserOverviewProjection.
+//
+// Parameters:
+//   - overviewPersistence: An implementation of UserOverviewPersistencePort for writing the read model
+//
+// Returns:
+//   - *UserOverviewProjection: A pointer to the newly created UserOverviewProjection
+func NewUserOverviewProjection(overviewPersistence persistence.UserOverviewPersistencePort) *UserOverviewProjection {
+	return &UserOverviewProjection{overviewPersistence}
+}
+
+// Handle applies a domain event to the read model. Events that do not affect
+// the overview are ignored.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - event: The domain event to project
+//
+// Returns:
+//   - error: An error if the read model cannot be updated
+func (p *UserOverviewProjection) Handle(ctx context.Context, event events.Event) error {
+	var err error
+	switch e := event.(type) {
+	case events.UserRegistered:
+		err = p.overviewPersistence.InsertUserOverview(ctx, domain.UserOverview{
+			ID:        e.UserID,
+			TenantID:  e.TenantID,
+			Username:  e.Username,
+			Status:    string(domain.StatusActive),
+			Roles:     e.Roles,
+			CreatedAt: e.At,
+		})
+	case events.UserInvited:
+		err = p.overviewPersistence.InsertUserOverview(ctx, domain.UserOverview{
+			ID:        e.UserID,
+			TenantID:  e.TenantID,
+			Username:  e.Username,
+			Status:    string(domain.StatusPending),
+			Roles:     e.Roles,
+			CreatedAt: e.At,
+		})
+	case events.UserLoggedIn:
+		err = p.overviewPersistence.UpdateUserOverview(ctx, e.Username, domain.UserOverviewUpdate{LastLoginAt: &e.At})
+	case events.UserRoleChanged:
+		err = p.overviewPersistence.UpdateUserOverview(ctx, e.Username, domain.UserOverviewUpdate{Roles: e.Roles})
+	case events.UserStatusChanged:
+		err = p.overviewPersistence.UpdateUserOverview(ctx, e.Username, domain.UserOverviewUpdate{Status: &e.Status})
+	case events.UserRenamed:
+		err = p.overviewPersistence.UpdateUserOverview(ctx, e.Username, domain.UserOverviewUpdate{Username: &e.NewUsername})
+	case events.UserDeleted:
+		err = p.overviewPersistence.DeleteUserOverview(ctx, e.Username)
+	case events.UserErased:
+		err = p.overviewPersistence.DeleteUserOverview(ctx, e.Username)
+	case events.AccountsMerged:
+		err = p.overviewPersistence.DeleteUserOverview(ctx, e.DuplicateUsername)
+	case events.MfaEnabled:
+		enabled := true
+		err = p.overviewPersistence.UpdateUserOverview(ctx, e.Username, domain.UserOverviewUpdate{MfaEnabled: &enabled})
+	case events.MfaDisabled:
+		enabled := e.Remaining > 0
+		err = p.overviewPersistence.UpdateUserOverview(ctx, e.Username, domain.UserOverviewUpdate{MfaEnabled: &enabled})
+	default:
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to project %s: %w", event.Name(), err)
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/errorx"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/ports/messaging"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/security"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+	"user-auth-hexagonal-architecture/internal/ports/telemetry"
+	"user-auth-hexagonal-architecture/internal/ports/usecases"
+)
+
+// UserProvisioningService handles the business logic for creating many accounts at once on behalf
+// of administrators, e.g. when migrating the users of another system.
+// Accounts created with a temporary password have to change it at the first login.
+// It implements the ProvisionUsersPort and CreateUserPort interfaces from the usecases package.
+type UserProvisioningService struct {
+	provisioningPersistence persistence.UserProvisioningPersistencePort
+	userPersistence         persistence.UserPersistencePort
+	credentialEventStore    persistence.CredentialEventStorePort
+	eventDispatcher         messaging.EventDispatcherPort
+	metrics                 telemetry.MetricsPort
+	roleRegistry            usecases.RoleRegistryPort
+	tenantRegistry          usecases.TenantRegistryPort
+	passwordHasher          security.PasswordHasherPort
+	clock                   system.ClockPort
+	usernamePolicy          domain.UsernamePolicy
+	canonicalizer           domain.Canonicalizer
+}
+
+// NewUserProvisioningService creates a new instance of UserProvisioningService.
+//
+// Parameters:
+//   - provisioningPersistence: An implementation of UserProvisioningPersistencePort for storing the users in bulk
+//   - userPersistence: An implementation of UserPersistencePort for checking the availability of usernames
+//   - credentialEventStore: An implementation of CredentialEventStorePort for the credential audit trail
+//   - eventDispatcher: An implementation of EventDispatcherPort for emitting domain events
+//   - metrics: An implementation of MetricsPort for reporting the password hashing duration
+//   - roleRegistry: An implementation of RoleRegistryPort for validating the roles of the specs
+//   - tenantRegistry: An implementation of TenantRegistryPort for the tenant and password policy of the request
+//   - passwordHasher: An implementation of PasswordHasherPort for hashing the temporary passwords
+//   - clock: An implementation of ClockPort for reading the current time
+//   - usernamePolicy: The rules new usernames have to satisfy
+//   - canonicalizer: The rules under which usernames and email addresses count as duplicates
+//
+// Returns:
+//   - *UserProvisioningService: A pointer to the newly created UserProvisioningService
+func NewUserProvisioningService(provisioningPersistence persistence.UserProvisioningPersistencePort, userPersistence persistence.UserPersistencePort, credentialEventStore persistence.CredentialEventStorePort, eventDispatcher messaging.EventDispatcherPort, metrics telemetry.MetricsPort, roleRegistry usecases.RoleRegistryPort, tenantRegistry usecases.TenantRegistryPort, passwordHasher security.PasswordHasherPort, clock system.ClockPort, usernamePolicy domain.UsernamePolicy, canonicalizer domain.Canonicalizer) *UserProvisioningService {
+	return &UserProvisioningService{provisioningPersistence, userPersistence, credentialEventStore, eventDispatcher, metrics, roleRegistry, tenantRegistry, passwordHasher, clock, usernamePolicy, canonicalizer}
+}
+
+// ProvisionUsers creates the accounts described by the specs in the tenant of the request.
+//
+// This method performs the following steps:
+// 1. Validates every spec on its own: username policy, email, role and either the password policy of the tenant or an email to invite.
+// 2. Rejects specs whose canonical username or email repeats an earlier spec or whose username is taken.
+// 3. Hashes the temporary passwords and stores all valid accounts in one bulk operation.
+// 4. Records the creation of the credentials and emits a UserRegistered or, for invitations, a UserInvited event.
+//
+// A rejected spec does not affect the others; the report tells which accounts were created and why
+// the others were not.
+//
+// Parameters:
+//   - ctx: The context of the request, cancelling it aborts the provisioning
+//   - specs: The accounts to create
+//
+// Returns:
+//   - usecases.ProvisioningReport: One result per spec, in the order of the specs
+//   - error: errorx.ErrTenantNotFound if the tenant of the request does not exist, or a wrapped persistence error
+//     if the accounts cannot be stored at all
+func (ps *UserProvisioningService) ProvisionUsers(ctx context.Context, specs []usecases.UserSpec) (report usecases.ProvisioningReport, err error) {
+	ctx, span := tracer.Start(ctx, "UserProvisioningService.ProvisionUsers")
+	defer func() { endSpan(span, err) }()
+
+	userTenant, err := ps.tenantRegistry.ResolveTenant(ctx)
+	if err != nil {
+		return usecases.ProvisioningReport{}, err
+	}
+
+	now := ps.clock.Now()
+	report.Results = make([]usecases.ProvisioningResult, len(specs))
+	usernames := make(map[string]bool, len(specs))
+	emails := make(map[string]bool, len(specs))
+	var users []domain.User
+	var indexes []int
+	for i, spec := range specs {
+		report.Results[i] = usecases.ProvisioningResult{Index: i, Username: spec.Username, Invited: spec.Invite}
+		user, err := ps.newUser(ctx, userTenant, spec, now)
+		if err == nil && usernames[user.CanonicalUsername] {
+			err = errorx.ErrUsernameTaken.Detailf("username repeats an earlier entry")
+		}
+		if err == nil && user.CanonicalEmail != "" && emails[user.CanonicalEmail] {
+			err = errorx.ErrEmailTaken.Detailf("email repeats an earlier entry")
+		}
+		if err != nil {
+			report.Results[i].Err = err
+			continue
+		}
+		usernames[user.CanonicalUsername] = true
+		if user.CanonicalEmail != "" {
+			emails[user.CanonicalEmail] = true
+		}
+		report.Results[i].Username = user.Username.String()
+		users = append(users, user)
+		indexes = append(indexes, i)
+	}
+
+	saved, err := ps.provisioningPersistence.SaveUsers(ctx, users)
+	if err != nil {
+		return usecases.ProvisioningReport{}, err
+	}
+	for j, result := range saved {
+		i := indexes[j]
+		if result.Err != nil {
+			report.Results[i].Err = result.Err
+			continue
+		}
+		report.Results[i].UserID = result.ID
+		ps.provisioned(ctx, result.ID, users[j])
+	}
+
+	for _, result := range report.Results {
+		if result.Err != nil {
+			report.Failed++
+		} else {
+			report.Created++
+		}
+	}
+	return report, nil
+}
+
+// CreateUser creates a single account with a temporary password in the tenant of the request. The
+// account is active, but logging in only yields a password change token until the user has
+// replaced the temporary password.
+//
+// Parameters:
+//   - ctx: The context of the request
+//   - spec: The account to create; Invite must not be set
+//
+// Returns:
+//   - string: The id of the created user
+//   - error: errorx.ErrInvalidUserSpec for invitations, the validation errors of ProvisionUsers,
+//     errorx.ErrUsernameTaken or errorx.ErrEmailTaken, or a wrapped persistence error
+func (ps *UserProvisioningService) CreateUser(ctx context.Context, spec usecases.UserSpec) (userID string, err error) {
+	ctx, span := tracer.Start(ctx, "UserProvisioningService.CreateUser")
+	defer func() { endSpan(span, err) }()
+
+	if spec.Invite {
+		return "", errorx.ErrInvalidUserSpec.Detailf("use the import to invite users")
+	}
+	userTenant, err := ps.tenantRegistry.ResolveTenant(ctx)
+	if err != nil {
+		return "", err
+	}
+	user, err := ps.newUser(ctx, userTenant, spec, ps.clock.Now())
+	if err != nil {
+		return "", err
+	}
+	userID, err = ps.userPersistence.SaveUser(ctx, user)
+	if err != nil {
+		return "", err
+	}
+	ps.provisioned(ctx, userID, user)
+	return userID, nil
+}
+
+// newUser validates a spec and creates the account it describes, without storing it.
+func (ps *UserProvisioningService) newUser(ctx context.Context, userTenant domain.Tenant, spec usecases.UserSpec, now time.Time) (domain.User, error) {
+	username, err := ps.usernamePolicy.Validate(spec.Username)
+	if err != nil {
+		return domain.User{}, err
+	}
+	var email domain.Email
+	if spec.Email != "" {
+		if email, err = domain.NewEmail(spec.Email); err != nil {
+			return domain.User{}, err
+		}
+	}
+	role := spec.Role
+	if role == "" {
+		role = domain.RoleUser
+	}
+	if !ps.roleRegistry.RolePermissions().Exists(role) {
+		return domain.User{}, errorx.ErrUnknownRole.Detailf("role %s does not exist", role)
+	}
+
+	switch {
+	case spec.Invite && spec.TemporaryPassword != "":
+		return domain.User{}, errorx.ErrInvalidUserSpec.Detailf("invited users choose their own password")
+	case spec.Invite:
+		if email.IsZero() {
+			return domain.User{}, errorx.ErrInvalidUserSpec.Detailf("an email address is required to invite the user")
+		}
+	default:
+		if err := userTenant.Settings.PasswordPolicy.Validate(spec.TemporaryPassword, username); err != nil {
+			return domain.User{}, err
+		}
+	}
+
+	user := domain.NewUser(userTenant.ID, username, email, domain.HashedPassword{}, now)
+	user.Canonicalize(ps.canonicalizer)
+	available, err := ps.userPersistence.IsUsernameAvailable(ctx, username, user.CanonicalUsername)
+	if err != nil {
+		return domain.User{}, fmt.Errorf("failed to check username: %w", err)
+	}
+	if !available {
+		return domain.User{}, errorx.ErrUsernameTaken
+	}
+
+	if role != domain.RoleUser {
+		user.Roles = []domain.Role{role}
+	}
+	if spec.Invite {
+		user.Status = domain.StatusPending
+		return user, nil
+	}
+
+	hashStart := time.Now()
+	password, err := ps.passwordHasher.Hash(spec.TemporaryPassword)
+	ps.metrics.ObservePasswordHashing(telemetry.PasswordHash, time.Since(hashStart))
+	if err != nil {
+		return domain.User{}, fmt.Errorf("failed to hash password: %w", err)
+	}
+	user.SetTemporaryPassword(password)
+	return user, nil
+}
+
+// provisioned records the credentials of a stored account and emits its UserRegistered or UserInvited event.
+func (ps *UserProvisioningService) provisioned(ctx context.Context, userID string, user domain.User) {
+	if user.Status == domain.StatusPending {
+		ps.eventDispatcher.Dispatch(ctx, events.UserInvited{UserID: userID, TenantID: user.TenantID, Username: user.Username.String(), Email: user.Email.String(), Roles: roleNames(user.Roles), At: user.CreatedAt})
+		return
+	}
+
+	event := domain.NewCredentialEvent(user.Username.String(), domain.CredentialCreated, map[string]string{domain.CredentialDetailAlgorithm: ps.passwordHasher.Algorithm()})
+	event.OccurredAt = user.CreatedAt
+	if err := ps.credentialEventStore.AppendCredentialEvent(ctx, event); err != nil {
+		// the user exists at this point, so provisioning itself has succeeded
+		logger.ErrorContext(ctx, "Error recording credential event", "username", user.Username.String(), "error", err)
+	}
+	ps.eventDispatcher.Dispatch(ctx, events.UserRegistered{UserID: userID, TenantID: user.TenantID, Username: user.Username.String(), Roles: roleNames(user.Roles), At: user.CreatedAt})
+}
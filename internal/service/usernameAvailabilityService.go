@@ -0,0 +1,87 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+)
+
+// UsernameAvailabilityService handles the business logic for checking usernames before registration.
+// It implements the CheckUsernamePort interface from the usecases package.
+type UsernameAvailabilityService struct {
+	userPersistence persistence.UserPersistencePort
+	random          system.RandomSourcePort
+	usernamePolicy  domain.UsernamePolicy
+	canonicalizer   domain.Canonicalizer
+	maxJitter       time.Duration
+}
+
+// NewUsernameAvailabilityService creates a new instance of UsernameAvailabilityService.
+//
+// Parameters:
+//   - userPersistence: An implementation of UserPersistencePort for looking up existing usernames
+//   - random: An implementation of RandomSourcePort for the response delay
+//   - usernamePolicy: The rules new usernames have to satisfy
+//   - canonicalizer: The rules under which usernames count as duplicates
+//   - maxJitter: The upper bound of the random delay added to every lookup, 0 disables it
+//
+// Returns:
+//   - *UsernameAvailabilityService: A pointer to the newly created UsernameAvailabilityService
+func NewUsernameAvailabilityService(userPersistence persistence.UserPersistencePort, random system.RandomSourcePort, usernamePolicy domain.UsernamePolicy, canonicalizer domain.Canonicalizer, maxJitter time.Duration) *UsernameAvailabilityService {
+	return &UsernameAvailabilityService{userPersistence, random, usernamePolicy, canonicalizer, maxJitter}
+}
+
+// CheckUsername reports whether a username could be registered right now.
+//
+// A username is available if it satisfies the username policy and neither it nor its canonical
+// form is in use. Every lookup is delayed by a random amount of time, so the response time does
+// not tell apart usernames found in the cache of the database from unknown ones.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - username: The username as entered by the user
+//
+// Returns:
+//   - bool: true if the username is available
+//   - error: errorx.ErrInvalidUsername if the username violates the username policy, or a wrapped persistence error
+func (us *UsernameAvailabilityService) CheckUsername(ctx context.Context, username string) (available bool, err error) {
+	ctx, span := tracer.Start(ctx, "UsernameAvailabilityService.CheckUsername")
+	defer func() { endSpan(span, err) }()
+
+	validUsername, err := us.usernamePolicy.Validate(username)
+	if err != nil {
+		return false, err
+	}
+	defer us.jitter(ctx)
+
+	available, err = us.userPersistence.IsUsernameAvailable(ctx, validUsername, us.canonicalizer.Username(validUsername))
+	if err != nil {
+		return false, fmt.Errorf("failed to check username: %w", err)
+	}
+	return available, nil
+}
+
+// jitter waits for a random duration below maxJitter, or until the context is cancelled.
+func (us *UsernameAvailabilityService) jitter(ctx context.Context) {
+	if us.maxJitter <= 0 {
+		return
+	}
+	var b [8]byte
+	if _, err := us.random.Read(b[:]); err != nil {
+		return
+	}
+	delay := time.Duration(binary.BigEndian.Uint64(b[:]) % uint64(us.maxJitter))
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
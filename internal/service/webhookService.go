@@ -0,0 +1,173 @@
+// Package service implements the application's business logic and use cases.
+// It acts as an intermediary between the adapter layer and the domain layer,
+package service
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"user-auth-hexagonal-architecture/internal/domain"
+	"user-auth-hexagonal-architecture/internal/domain/events"
+	"user-auth-hexagonal-architecture/internal/ports/messaging"
+	"user-auth-hexagonal-architecture/internal/ports/persistence"
+	"user-auth-hexagonal-architecture/internal/ports/system"
+	"user-auth-hexagonal-architecture/internal/tenant"
+)
+
+// maxDeadLetters caps the number of dead letters returned by a single listing.
+const maxDeadLetters = 100
+
+// WebhookService handles the business logic for outbound webhooks.
+// It implements the ManageWebhooksPort and ListWebhookDeadLettersPort interfaces from the usecases
+// package and the EventHandler interface from the messaging ports package.
+//
+// Every domain event is handed to the delivery port once per subscription of its tenant that
+// subscribes to it.
+type WebhookService struct {
+	webhookPersistence    persistence.WebhookPersistencePort
+	deadLetterPersistence persistence.WebhookDeadLetterPersistencePort
+	webhookDelivery       messaging.WebhookDeliveryPort
+	clock                 system.ClockPort
+	random                system.RandomSourcePort
+}
+
+// NewWebhookService creates a new instance of WebhookService.
+//
+// Parameters:
+//   - webhookPersistence: An implementation of WebhookPersistencePort for storing subscriptions
+//   - deadLetterPersistence: An implementation of WebhookDeadLetterPersistencePort for reading failed deliveries
+//   - webhookDelivery: An implementation of WebhookDeliveryPort for sending the events
+//   - clock: An implementation of ClockPort for reading the current time
+//   - random: An implementation of RandomSourcePort for generating secrets
+//
+// Returns:
+//   - *WebhookService: A pointer to the newly created WebhookService
+func NewWebhookService(webhookPersistence persistence.WebhookPersistencePort, deadLetterPersistence persistence.WebhookDeadLetterPersistencePort, webhookDelivery messaging.WebhookDeliveryPort, clock system.ClockPort, random system.RandomSourcePort) *WebhookService {
+	return &WebhookService{webhookPersistence, deadLetterPersistence, webhookDelivery, clock, random}
+}
+
+// CreateWebhook subscribes an endpoint to the domain events of the tenant of the request.
+//
+// A random signing secret is generated for the subscription. It is only returned here,
+// so the caller has to hand it to the receiver right away.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - url: The endpoint the events are posted to
+//   - eventNames: The names of the events to deliver; empty or "*" for all events
+//
+// Returns:
+//   - domain.WebhookSubscription: The new subscription including its secret
+//   - error: A wrapped persistence error
+func (ws *WebhookService) CreateWebhook(ctx context.Context, url string, eventNames []string) (domain.WebhookSubscription, error) {
+	secret := make([]byte, 32)
+	if _, err := ws.random.Read(secret); err != nil {
+		return domain.WebhookSubscription{}, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	subscription := domain.WebhookSubscription{
+		TenantID:  requestTenantID(ctx),
+		URL:       url,
+		Secret:    "whsec_" + hex.EncodeToString(secret),
+		Events:    eventNames,
+		CreatedAt: ws.clock.Now(),
+	}
+	id, err := ws.webhookPersistence.SaveWebhook(ctx, subscription)
+	if err != nil {
+		return domain.WebhookSubscription{}, fmt.Errorf("failed to save webhook: %w", err)
+	}
+	subscription.ID = id
+	return subscription, nil
+}
+
+// ListWebhooks returns the subscriptions of the tenant of the request without their secrets.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//
+// Returns:
+//   - []domain.WebhookSubscription: The subscriptions
+//   - error: A wrapped persistence error
+func (ws *WebhookService) ListWebhooks(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	subscriptions, err := ws.webhookPersistence.FindWebhooks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+	return subscriptions, nil
+}
+
+// DeleteWebhook removes a subscription of the tenant of the request. Deliveries already queued are
+// still attempted.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - id: The id of the subscription
+//
+// Returns:
+//   - error: errorx.ErrWebhookNotFound, or a wrapped persistence error
+func (ws *WebhookService) DeleteWebhook(ctx context.Context, id string) error {
+	if err := ws.webhookPersistence.DeleteWebhook(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return nil
+}
+
+// ListWebhookDeadLetters returns the most recent deliveries to the subscriptions of the tenant of
+// the request that were given up.
+//
+// A missing or too large limit is replaced by maxDeadLetters.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - limit: The maximum number of dead letters to return
+//
+// Returns:
+//   - []domain.WebhookDeadLetter: The dead letters, newest first
+//   - error: A wrapped persistence error
+func (ws *WebhookService) ListWebhookDeadLetters(ctx context.Context, limit int64) ([]domain.WebhookDeadLetter, error) {
+	if limit <= 0 || limit > maxDeadLetters {
+		limit = maxDeadLetters
+	}
+
+	deadLetters, err := ws.deadLetterPersistence.FindDeadLetters(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook dead letters: %w", err)
+	}
+	return deadLetters, nil
+}
+
+// Handle hands a domain event to the delivery port for every subscription that subscribes to it
+// and belongs to the tenant the event was emitted in. Events of background jobs, which run without
+// a tenant, belong to the default tenant.
+//
+// Parameters:
+//   - ctx: A context.Context for cancelling the operation
+//   - event: The domain event to deliver
+//
+// Returns:
+//   - error: An error if the subscriptions cannot be loaded
+func (ws *WebhookService) Handle(ctx context.Context, event events.Event) error {
+	eventTenant := requestTenantID(ctx)
+	subscriptions, err := ws.webhookPersistence.FindWebhooks(tenant.WithID(ctx, eventTenant))
+	if err != nil {
+		return fmt.Errorf("failed to load webhooks: %w", err)
+	}
+
+	for _, subscription := range subscriptions {
+		if subscription.TenantID == eventTenant && subscription.Subscribes(event.Name()) {
+			ws.webhookDelivery.Deliver(ctx, subscription, event)
+		}
+	}
+	return nil
+}
+
+// requestTenantID returns the tenant of the request, the default tenant if it has none.
+func requestTenantID(ctx context.Context) string {
+	if id := tenant.FromContext(ctx); id != "" {
+		return id
+	}
+	return domain.DefaultTenantID
+}
@@ -0,0 +1,24 @@
+// Package tenant carries the id of the tenant a request is made for through contexts, so the core
+// layer can apply the settings of the customer organization the request belongs to.
+package tenant
+
+import (
+	"context"
+)
+
+// Header is the HTTP header naming the tenant of unauthenticated requests such as logins and registrations.
+const Header = "X-Tenant-ID"
+
+// contextKey is the context key under which the tenant id is stored.
+type contextKey struct{}
+
+// WithID returns a copy of ctx carrying the tenant id.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the tenant id stored in ctx, or an empty string if the request does not name a tenant.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}